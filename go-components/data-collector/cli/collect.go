@@ -0,0 +1,397 @@
+// Package cli implements the collector commands of the weather CLI (go-components/weather):
+// collect, which also runs the collection daemon and pipe mode, and serve.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/integration"
+	"weather-collector/logging"
+	"weather-collector/notify"
+	"weather-collector/server"
+	"weather-collector/storage"
+	"weathermodels/fileio"
+)
+
+// Collect runs the "collect" command: a one-shot collection from the input file, a collection
+// daemon with -daemon, or a stdin/stdout filter with -pipe. It returns the process exit code.
+func Collect(args []string) int {
+	flags := flag.NewFlagSet("collect", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a JSON configuration file (defaults are used if empty)")
+	daemon := flags.Bool("daemon", false, "run continuously, collecting on schedule.cron or every schedule.interval")
+	pipe := flags.Bool("pipe", false, "read locations as JSON from stdin and write results to stdout")
+	format := flags.String("format", "", "output format for -pipe and the output file: json (one array), jsonl (one result per line as it completes) or csv; overrides integration.output_format")
+	csvForecast := flags.Bool("csv-forecast", false, "with -format csv, add a row per forecast point (overrides integration.csv_forecast)")
+	record := flags.Bool("record", false, "save live API responses into api.fixtures_dir for replay with the mock provider")
+	timeseriesDir := flags.String("timeseries-dir", "", "merge each run into the per-location time-series files in this directory (enables storage.timeseries)")
+	if err := flags.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	cfg, logCloser, code := setup(*configPath)
+	if code != exitOK {
+		return code
+	}
+	defer logCloser.Close()
+
+	if *format != "" {
+		if !validFormat(*format) {
+			return fail(exitConfigError, "Invalid -format", fmt.Errorf("unknown output format %q (expected %q, %q or %q)", *format, formatJSON, formatJSONL, formatCSV))
+		}
+		cfg.Integration.OutputFormat = *format
+	}
+	if *csvForecast {
+		cfg.Integration.CSVForecast = true
+	}
+	if *record {
+		if cfg.API.Provider == collector.ProviderMock {
+			return fail(exitConfigError, "Invalid -record", fmt.Errorf("cannot record fixtures while the %q provider is replaying them", collector.ProviderMock))
+		}
+		cfg.API.Record = true
+	}
+	if *timeseriesDir != "" {
+		cfg.Storage.Timeseries.Enabled = true
+		cfg.Storage.Timeseries.Directory = *timeseriesDir
+	}
+
+	// Cancel on SIGINT/SIGTERM; whatever was collected before that point is still written out
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *pipe {
+		results, err := runPipe(ctx, cfg, os.Stdin, os.Stdout, cfg.Integration.OutputFormat)
+		if err != nil {
+			return fail(exitError, "Pipe mode failed", err)
+		}
+		return resultsExitCode(results)
+	}
+
+	// Open storage once; daemon cycles share the database connection pool
+	store, err := storage.New(ctx, cfg)
+	if err != nil {
+		return fail(exitError, "Failed to open storage", err)
+	}
+	if store != nil {
+		defer store.Close()
+	}
+
+	if *daemon {
+		if err := runDaemon(ctx, cfg, store); err != nil {
+			return fail(exitError, "Daemon mode failed", err)
+		}
+		return exitOK
+	}
+
+	results, err := collectOnce(ctx, cfg, store, true)
+	notify.NewAlerter(cfg).Check(context.WithoutCancel(ctx), results, time.Now())
+	if err != nil {
+		return fail(exitError, "Collection failed", err)
+	}
+	return resultsExitCode(results)
+}
+
+// CollectResults runs a one-shot collection like Collect but returns the results instead of
+// writing the output file, for callers that analyze them in the same process. Storage, alerts
+// and the run summary work as in Collect. Results are returned whenever the exit code is exitOK
+// or exitPartialFailure.
+func CollectResults(configPath string) ([]collector.WeatherResult, int) {
+	cfg, logCloser, code := setup(configPath)
+	if code != exitOK {
+		return nil, code
+	}
+	defer logCloser.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.New(ctx, cfg)
+	if err != nil {
+		return nil, fail(exitError, "Failed to open storage", err)
+	}
+	if store != nil {
+		defer store.Close()
+	}
+
+	results, err := collectOnce(ctx, cfg, store, false)
+	notify.NewAlerter(cfg).Check(context.WithoutCancel(ctx), results, time.Now())
+	if err != nil {
+		return nil, fail(exitError, "Collection failed", err)
+	}
+	return results, resultsExitCode(results)
+}
+
+// Serve runs the "serve" command: the collection REST API, or the gRPC API with -grpc,
+// until SIGINT/SIGTERM. It returns the process exit code.
+func Serve(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a JSON configuration file (defaults are used if empty)")
+	grpcMode := flags.Bool("grpc", false, "serve the gRPC collection API (see weatherpb/weather.proto) instead of REST")
+	addr := flags.String("addr", "", "listen address (overrides server.address, or server.grpc_address with -grpc)")
+	if err := flags.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	cfg, logCloser, code := setup(*configPath)
+	if code != exitOK {
+		return code
+	}
+	defer logCloser.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *grpcMode {
+		if *addr != "" {
+			cfg.Server.GRPCAddress = *addr
+		}
+		if err := server.ServeGRPC(ctx, cfg); err != nil {
+			return fail(exitError, "gRPC server failed", err)
+		}
+		return exitOK
+	}
+
+	if *addr != "" {
+		cfg.Server.Address = *addr
+	}
+	if err := server.New(cfg).ListenAndServe(ctx); err != nil {
+		return fail(exitError, "Server failed", err)
+	}
+	return exitOK
+}
+
+// setup loads the configuration and configures structured logging before anything else is logged.
+// On failure it returns exitConfigError and no closer.
+func setup(configPath string) (*config.Config, io.Closer, int) {
+	cfg, metadata, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, fail(exitConfigError, "Failed to load config", err)
+	}
+	logCloser, err := logging.Setup(cfg.Logging)
+	if err != nil {
+		return nil, nil, fail(exitConfigError, "Failed to set up logging", err)
+	}
+
+	// Log configuration info
+	slog.Info("Weather Data Collector v1.0 starting", "config_source", metadata.Source)
+	slog.Debug("Configuration",
+		"api_url", cfg.API.BaseURL,
+		"max_workers", cfg.Performance.MaxWorkers,
+		"input_file", cfg.GetInputFilePath(),
+		"output_file", cfg.GetOutputFilePath())
+	return cfg, logCloser, exitOK
+}
+
+// fail logs err and returns code, for commands to return as their exit code
+func fail(code int, msg string, err error) int {
+	slog.Error(msg, "error", err)
+	return code
+}
+
+// flagExitCode maps a flag parsing error to an exit code; -h and -help are not failures
+func flagExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitConfigError
+}
+
+// resultsExitCode returns exitPartialFailure or exitTotalFailure when locations failed
+func resultsExitCode(results []collector.WeatherResult) int {
+	code := exitCodeFor(results)
+	if code != exitOK {
+		slog.Warn("Some locations failed", "exit_code", code)
+	}
+	return code
+}
+
+// collectOnce reads the input locations, collects weather for them, and writes the output file
+// unless writeOutput is false.
+// A run summary is written to integration.summary_file and sent to the notification webhook
+// once results are collected, or as soon as the run fails. With a store, results are also saved to the database.
+func collectOnce(ctx context.Context, cfg *config.Config, store storage.Store, writeOutput bool) ([]collector.WeatherResult, error) {
+	startedAt := time.Now()
+
+	// Read locations from Python input file using config
+	locations, err := readLocationsFromFile(ctx, cfg)
+	if err != nil {
+		err = fmt.Errorf("Failed to read locations from %s: %w", cfg.GetInputFilePath(), err)
+		reportRun(ctx, cfg, summarize(nil, startedAt, "").withError(err))
+		return nil, err
+	}
+
+	slog.Info("Collecting weather", "locations", len(locations))
+
+	// Enforce the overall deadline for this run
+	if cfg.Performance.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Performance.RunTimeout)
+		defer cancel()
+	}
+
+	// Use collector package for actual work, then write results for Python to read.
+	// JSON Lines output is appended as each result completes instead of at the end.
+	var results []collector.WeatherResult
+	outputPath := cfg.GetOutputFilePath()
+	switch {
+	case !writeOutput:
+		results = collector.CollectWeatherData(ctx, locations)
+		outputPath = ""
+	case cfg.Integration.OutputFormat == formatJSONL:
+		results, err = collectToJSONL(ctx, cfg, locations)
+	default:
+		results = collector.CollectWeatherData(ctx, locations)
+		err = writeResultsToFile(results, cfg)
+	}
+	if ctx.Err() != nil {
+		slog.Warn("Collection interrupted, partial results kept", "error", ctx.Err())
+	}
+	summary := summarize(results, startedAt, outputPath)
+	if err != nil {
+		err = fmt.Errorf("Failed to write results to %s: %w", cfg.GetOutputFilePath(), err)
+		reportRun(ctx, cfg, summary.withError(err))
+		return results, err
+	}
+	reportRun(ctx, cfg, summary)
+
+	// The output file is the source of truth for Python, so a storage outage only logs an error
+	if store != nil {
+		if err := store.Save(context.WithoutCancel(ctx), results); err != nil {
+			slog.Error("Could not store results", "error", err)
+		}
+	}
+
+	slog.Info("Successfully completed collection", "locations", len(results))
+
+	// Show metrics if enabled
+	if cfg.Logging.EnableMetrics {
+		logMetrics(results)
+	}
+
+	return results, nil
+}
+
+// logMetrics logs success rate, cache usage, retries, latency and any tripped circuit breakers for a collection run
+func logMetrics(results []collector.WeatherResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	successful := 0
+	cacheHits := 0
+	retries := 0
+	var totalMs float64
+	slowest := results[0]
+	for _, result := range results {
+		if result.Success {
+			successful++
+		}
+		if result.Meta.CacheHit {
+			cacheHits++
+		}
+		retries += result.Meta.Retries
+		totalMs += result.Meta.DurationMs
+		if result.Meta.DurationMs > slowest.Meta.DurationMs {
+			slowest = result
+		}
+	}
+	slog.Info("Metrics",
+		"successful", successful,
+		"locations", len(results),
+		"success_rate", float64(successful)/float64(len(results))*100,
+		"cache_hits", cacheHits,
+		"retries", retries,
+		"avg_duration_ms", totalMs/float64(len(results)),
+		"slowest_location", slowest.Location.Name,
+		"slowest_duration_ms", slowest.Meta.DurationMs)
+
+	for endpoint, state := range collector.BreakerStates() {
+		if state != collector.BreakerClosed.String() {
+			slog.Warn("Circuit breaker not closed", "endpoint", endpoint, "state", state)
+		}
+	}
+}
+
+// ReadLocationsFromFile reads location data from JSON file - TODO integration function
+func ReadLocationsFromFile() ([]collector.Location, error) {
+	cfg, _, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return readLocationsFromFile(context.Background(), cfg)
+}
+
+// SaveWeatherToFile writes weather data to JSON file - TODO integration function
+func SaveWeatherToFile(data []collector.WeatherResult) error {
+	cfg, _, err := config.Load()
+	if err != nil {
+		return err
+	}
+	return writeResultsToFile(data, cfg)
+}
+
+// readLocationsFromFile reads location data from the input file. With the handshake enabled
+// it waits for the writer's marker so a half-written file is never parsed.
+func readLocationsFromFile(ctx context.Context, cfg *config.Config) ([]collector.Location, error) {
+	filename := cfg.GetInputFilePath()
+
+	var data []byte
+	var err error
+	if cfg.Integration.Handshake {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Integration.HandshakeTimeout)
+		defer cancel()
+		data, err = integration.ReadFile(ctx, filename)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []collector.Location
+	err = json.Unmarshal(data, &locations)
+	return locations, err
+}
+
+// writeResultsToFile writes results to the output file atomically so the Python reader never sees
+// a partial file, followed by the handshake marker when enabled
+func writeResultsToFile(results []collector.WeatherResult, cfg *config.Config) error {
+	data, err := encodeResults(results, cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.Integration.Compress {
+		if data, err = fileio.Compress(data); err != nil {
+			return err
+		}
+	}
+	if cfg.Integration.Handshake {
+		return integration.WriteFile(cfg.GetOutputFilePath(), data, 0644)
+	}
+	return fileio.WriteFileAtomic(cfg.GetOutputFilePath(), data, 0644)
+}
+
+// encodeResults renders results in the configured output file format (JSON unless CSV is selected).
+// The JSON output file is read by the Python side and the pattern engine, which expect metric
+// units, so it stays metric whatever the units section says. CSV is written for people and
+// spreadsheets, so it is converted, with the units in its header.
+func encodeResults(results []collector.WeatherResult, cfg *config.Config) ([]byte, error) {
+	if cfg.Integration.OutputFormat == formatCSV {
+		var buf bytes.Buffer
+		err := writeCSV(&buf, results, cfg.OutputUnits(), cfg.Integration.CSVForecast)
+		return buf.Bytes(), err
+	}
+	return json.MarshalIndent(results, "", "  ")
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weathermodels/units"
+)
+
+// TestEncodeResultsUnits tests that the JSON output file stays metric when other units are
+// configured, as the Python side and the pattern engine read it, while CSV is converted
+func TestEncodeResultsUnits(t *testing.T) {
+	cfg := &config.Config{Units: units.Config{System: units.ImperialSystem}}
+	results := []collector.WeatherResult{{
+		Location:       collector.Location{Name: "Oslo"},
+		CurrentWeather: collector.WeatherPoint{Temperature: 10, Pressure: 1013},
+		Success:        true,
+	}}
+
+	data, err := encodeResults(results, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []collector.WeatherResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].Units != nil || decoded[0].CurrentWeather.Temperature != 10 {
+		t.Errorf("Expected the metric readings, got %s", data)
+	}
+
+	cfg.Integration.OutputFormat = formatCSV
+	if data, err = encodeResults(results, cfg); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.Index(rows[0], "temperature (°F)")
+	if len(rows) != 2 || i < 0 || rows[1][i] != "50" {
+		t.Errorf("Expected the temperature in °F, got %v", rows)
+	}
+}
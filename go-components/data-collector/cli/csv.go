@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"weather-collector/collector"
+	"weathermodels"
+	"weathermodels/units"
+)
+
+// CSV row kinds for the "kind" column
+const (
+	csvKindCurrent  = "current"
+	csvKindForecast = "forecast"
+)
+
+// csvHeader is the stable column order for CSV output; new columns are only ever appended
+var csvHeader = []string{
+	"location", "lat", "lon", "alt", "success", "error", "source", "attempts",
+	"kind", "timestamp", "temperature", "pressure", "humidity", "wind_speed", "wind_direction",
+	"cloud_cover", "precipitation_mm", "precipitation_probability", "symbol_code",
+	"dew_point", "uv_index", "wind_gust", "fog_area_fraction",
+	"error_code", "duration_ms", "retries", "provider", "cache_hit", "http_status",
+}
+
+// csvUnitColumns are the columns of readings converted to the configured units
+var csvUnitColumns = []string{"temperature", "pressure", "wind_speed", "precipitation_mm", "dew_point", "wind_gust"}
+
+// csvHeaderFor returns csvHeader with the unit of system after each column in csvUnitColumns,
+// such as "temperature (°F)". The metric header has no units, as before units were configurable.
+func csvHeaderFor(system units.System) []string {
+	if system.IsMetric() {
+		return csvHeader
+	}
+	header := slices.Clone(csvHeader)
+	for i, name := range header {
+		if slices.Contains(csvUnitColumns, name) {
+			header[i] = name + " (" + strings.TrimSpace(system.Symbol(name)) + ")"
+		}
+	}
+	return header
+}
+
+// writeCSV flattens results into CSV, with the readings in the units of system: one "current" row
+// per location and, when includeForecast is set, one "forecast" row per forecast point. Failed
+// locations get a single row with the error.
+func writeCSV(w io.Writer, results []collector.WeatherResult, system units.System, includeForecast bool) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeaderFor(system)); err != nil {
+		return err
+	}
+
+	for _, result := range collector.ConvertUnits(results, system) {
+		if err := writer.Write(csvRow(result, csvKindCurrent, result.CurrentWeather)); err != nil {
+			return err
+		}
+		if !includeForecast {
+			continue
+		}
+		for _, point := range result.Forecast {
+			if err := writer.Write(csvRow(result, csvKindForecast, point)); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRow builds one row in csvHeader order
+func csvRow(result collector.WeatherResult, kind string, point collector.WeatherPoint) []string {
+	return []string{
+		result.Location.Name,
+		formatFloat(result.Location.Lat),
+		formatFloat(result.Location.Lon),
+		strconv.Itoa(result.Location.Alt),
+		strconv.FormatBool(result.Success),
+		result.Error,
+		result.Source,
+		strconv.Itoa(result.Attempts),
+		kind,
+		weathermodels.FormatTimestamp(point.Timestamp),
+		formatFloat(point.Temperature),
+		formatFloat(point.Pressure),
+		formatFloat(point.Humidity),
+		formatFloat(point.WindSpeed),
+		formatFloat(point.WindDirection),
+		formatFloat(point.CloudCover),
+		formatFloat(point.PrecipitationMm),
+		formatFloat(point.PrecipitationProbability),
+		point.SymbolCode,
+		formatFloat(point.DewPoint),
+		formatFloat(point.UVIndex),
+		formatFloat(point.WindGust),
+		formatFloat(point.FogAreaFraction),
+		result.ErrorCode,
+		formatFloat(result.Meta.DurationMs),
+		strconv.Itoa(result.Meta.Retries),
+		result.Meta.Provider,
+		strconv.FormatBool(result.Meta.CacheHit),
+		strconv.Itoa(result.Meta.HTTPStatus),
+	}
+}
+
+// formatFloat formats a number with the shortest exact representation
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
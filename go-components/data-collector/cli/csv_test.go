@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+	"weathermodels/units"
+)
+
+// TestWriteCSV tests the column order, which consumers rely on, and the rows of each result
+func TestWriteCSV(t *testing.T) {
+	start := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	results := []collector.WeatherResult{
+		{
+			Location:       collector.Location{Name: "Oslo", Lat: 59.91, Lon: 10.75, Alt: 23},
+			CurrentWeather: collector.WeatherPoint{Timestamp: start, Temperature: 9.5, Pressure: 1013.2},
+			Forecast:       []collector.WeatherPoint{{Timestamp: start.Add(time.Hour), Temperature: 10}},
+			Success:        true,
+			Source:         collector.SourceAPI,
+			Attempts:       1,
+		},
+		{Location: collector.Location{Name: "Atlantis"}, Error: "no data", ErrorCode: collector.ErrorCodeNoData},
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, results, units.Metric(), true); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// New columns are only ever appended, so this prefix must never change
+	wantHeader := []string{
+		"location", "lat", "lon", "alt", "success", "error", "source", "attempts",
+		"kind", "timestamp", "temperature", "pressure", "humidity", "wind_speed", "wind_direction",
+		"cloud_cover", "precipitation_mm", "precipitation_probability", "symbol_code",
+		"dew_point", "uv_index", "wind_gust", "fog_area_fraction",
+		"error_code", "duration_ms", "retries", "provider", "cache_hit", "http_status",
+	}
+	if len(rows) != 4 || !slices.Equal(rows[0][:len(wantHeader)], wantHeader) {
+		t.Fatalf("Expected the header and 3 rows, got %v", rows)
+	}
+
+	column := func(row []string, name string) string {
+		return row[slices.Index(rows[0], name)]
+	}
+	current, forecast, failed := rows[1], rows[2], rows[3]
+	if column(current, "kind") != csvKindCurrent || column(current, "temperature") != "9.5" || column(current, "timestamp") != "2025-10-03T12:00:00Z" {
+		t.Errorf("Unexpected current row: %v", current)
+	}
+	if column(forecast, "kind") != csvKindForecast || column(forecast, "location") != "Oslo" || column(forecast, "temperature") != "10" {
+		t.Errorf("Unexpected forecast row: %v", forecast)
+	}
+	if column(failed, "success") != "false" || column(failed, "error_code") != collector.ErrorCodeNoData {
+		t.Errorf("Unexpected failed row: %v", failed)
+	}
+
+	buf.Reset()
+	if err := writeCSV(&buf, results, units.Metric(), false); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := csv.NewReader(&buf).ReadAll(); len(rows) != 3 {
+		t.Errorf("Expected no forecast rows, got %v", rows)
+	}
+}
+
+// TestWriteCSVUnits tests that the readings are converted to the configured units, which the
+// header gives after the name of their columns
+func TestWriteCSVUnits(t *testing.T) {
+	results := []collector.WeatherResult{{
+		Location:       collector.Location{Name: "Oslo"},
+		CurrentWeather: collector.WeatherPoint{Temperature: 10, Pressure: 1013, WindSpeed: 10, Humidity: 80, PrecipitationMm: 25.4},
+		Success:        true,
+	}}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, results, units.Imperial(), false); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[0]) != len(csvHeader) {
+		t.Fatalf("Expected the header and 1 row, got %v", rows)
+	}
+
+	column := func(name string) float64 {
+		i := slices.Index(rows[0], name)
+		if i < 0 {
+			t.Fatalf("Expected a %q column, got %v", name, rows[0])
+		}
+		value, err := strconv.ParseFloat(rows[1][i], 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	if got := column("temperature (°F)"); got != 50 {
+		t.Errorf("Expected 50°F, got %v", got)
+	}
+	if got := column("pressure (inHg)"); math.Abs(got-29.91) > 0.01 {
+		t.Errorf("Expected 29.91 inHg, got %v", got)
+	}
+	if got := column("wind_speed (mph)"); math.Abs(got-22.37) > 0.01 {
+		t.Errorf("Expected 22.37 mph, got %v", got)
+	}
+	if got := column("precipitation_mm (in)"); got != 1 {
+		t.Errorf("Expected 1 in, got %v", got)
+	}
+	if got := column("humidity"); got != 80 {
+		t.Errorf("Expected the humidity unchanged, got %v", got)
+	}
+}
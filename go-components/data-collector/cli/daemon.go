@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"weather-collector/config"
+	"weather-collector/notify"
+	"weather-collector/scheduler"
+	"weather-collector/storage"
+	"weathermodels/fileio"
+)
+
+// rotatedTimeFormat is the timestamp suffix used for rotated output files
+const rotatedTimeFormat = "20060102_150405"
+
+// runDaemon collects on the configured schedule until ctx is cancelled.
+// Interval schedules collect immediately on startup; cron schedules wait for the
+// first matching time. Each cycle refreshes the main output file and keeps a
+// timestamped copy for history. Operators are alerted when a cycle's failure rate is too
+// high or a location stays without a successful collection (see notify.Alerter).
+//
+// Shutdown is graceful: the first SIGINT/SIGTERM (which cancels ctx) stops
+// scheduling but lets an in-flight cycle finish writing its results; a second
+// signal aborts that cycle immediately.
+func runDaemon(ctx context.Context, cfg *config.Config, store storage.Store) error {
+	schedule, runNow, err := daemonSchedule(cfg.Schedule)
+	if err != nil {
+		return err
+	}
+
+	cycleCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+	go abortOnSecondSignal(ctx, cycleCtx, abort)
+
+	alerter := notify.NewAlerter(cfg)
+	cycle := func(context.Context) {
+		results, err := collectOnce(cycleCtx, cfg, store, true)
+		alerter.Check(cycleCtx, results, time.Now())
+		if err != nil {
+			slog.Error("Collection cycle failed", "error", err)
+			return
+		}
+		if err := rotateOutput(cfg.GetOutputFilePath(), time.Now(), cfg.Schedule.MaxOutputFiles); err != nil {
+			slog.Warn("Could not rotate output file", "error", err)
+		}
+	}
+
+	if runNow {
+		cycle(ctx)
+	}
+	scheduler.Run(ctx, schedule, cycle)
+	slog.Info("Daemon stopped")
+	return nil
+}
+
+// daemonSchedule builds the schedule from config and reports whether to collect immediately
+func daemonSchedule(cfg config.SchedulerConfig) (scheduler.Schedule, bool, error) {
+	if cfg.Cron != "" {
+		cron, err := scheduler.ParseCron(cfg.Cron)
+		if err != nil {
+			return nil, false, err
+		}
+		slog.Info("Daemon mode: collecting on cron schedule", "cron", cron)
+		return cron, false, nil
+	}
+
+	if cfg.Interval <= 0 {
+		return nil, false, fmt.Errorf("schedule.cron or schedule.interval must be set for daemon mode")
+	}
+	slog.Info("Daemon mode: collecting on interval", "interval", cfg.Interval)
+	return scheduler.Every(cfg.Interval), true, nil
+}
+
+// abortOnSecondSignal cancels the in-flight cycle if another shutdown signal arrives after ctx is done
+func abortOnSecondSignal(ctx, cycleCtx context.Context, abort context.CancelFunc) {
+	select {
+	case <-ctx.Done():
+	case <-cycleCtx.Done():
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	slog.Warn("Shutdown requested, finishing current cycle (signal again to abort)")
+	select {
+	case <-signals:
+		slog.Warn("Aborting current cycle")
+		abort()
+	case <-cycleCtx.Done():
+	}
+}
+
+// rotateOutput copies the output file to a timestamped sibling and prunes old copies beyond keep
+func rotateOutput(outputPath string, now time.Time, keep int) error {
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return err
+	}
+
+	base, ext := splitExt(outputPath)
+	rotated := fmt.Sprintf("%s_%s%s", base, now.Format(rotatedTimeFormat), ext)
+	if err := fileio.WriteFileAtomic(rotated, data, 0644); err != nil {
+		return err
+	}
+
+	if keep <= 0 {
+		return nil
+	}
+
+	// Timestamps sort lexically, so the oldest copies come first
+	matches, err := filepath.Glob(base + "_" + strings.Repeat("[0-9]", 8) + "_" + strings.Repeat("[0-9]", 6) + ext)
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+	for len(matches) > keep {
+		if err := os.Remove(matches[0]); err != nil {
+			return err
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
+
+// splitExt splits a path into everything before the extension and the extension itself.
+// A compressed file keeps its inner extension, so "out.json.gz" splits into "out" and ".json.gz".
+func splitExt(path string) (string, string) {
+	trimmed := strings.TrimSuffix(path, fileio.GzipExt)
+	ext := filepath.Ext(trimmed) + path[len(trimmed):]
+	return strings.TrimSuffix(path, ext), ext
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/integration"
+)
+
+// jsonlOutput appends each result to the output file as a JSON line as soon as it completes,
+// so a run that dies part-way still leaves every result collected up to that point.
+// Compressed output is flushed after each line so the completed lines stay decodable. Like the
+// JSON output file it stays metric (see encodeResults).
+type jsonlOutput struct {
+	cfg     *config.Config
+	file    *os.File
+	gz      *gzip.Writer // nil unless integration.compress is set
+	encoder *json.Encoder
+	err     error
+}
+
+// createJSONLOutput truncates the output file for a new run. With the handshake enabled the
+// old marker is removed first, so readers wait until the run is complete.
+func createJSONLOutput(cfg *config.Config) (*jsonlOutput, error) {
+	path := cfg.GetOutputFilePath()
+	if cfg.Integration.Handshake {
+		if err := integration.ClearMarker(path); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	out := &jsonlOutput{cfg: cfg, file: file, encoder: json.NewEncoder(file)}
+	if cfg.Integration.Compress {
+		out.gz = gzip.NewWriter(file)
+		out.encoder = json.NewEncoder(out.gz)
+	}
+	return out, nil
+}
+
+// write appends one result and syncs it to disk; after the first error further results are dropped
+func (o *jsonlOutput) write(result collector.WeatherResult) {
+	if o.err != nil {
+		return
+	}
+	if o.err = o.encoder.Encode(result); o.err != nil {
+		return
+	}
+	if o.gz != nil {
+		if o.err = o.gz.Flush(); o.err != nil {
+			return
+		}
+	}
+	o.err = o.file.Sync()
+}
+
+// close closes the output file and, with the handshake enabled, marks it complete
+func (o *jsonlOutput) close() error {
+	if o.gz != nil {
+		if err := o.gz.Close(); o.err == nil {
+			o.err = err
+		}
+	}
+	if err := o.file.Close(); o.err == nil {
+		o.err = err
+	}
+	if o.err != nil {
+		return o.err
+	}
+	if o.cfg.Integration.Handshake {
+		return integration.MarkComplete(o.cfg.GetOutputFilePath(), 0644)
+	}
+	return nil
+}
+
+// collectToJSONL collects weather for locations, appending each result to the output file as it completes
+func collectToJSONL(ctx context.Context, cfg *config.Config, locations []collector.Location) ([]collector.WeatherResult, error) {
+	provider, err := collector.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := createJSONLOutput(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create output file: %w", err)
+	}
+
+	results := collector.CollectEach(ctx, provider, locations, out.write)
+	return results, out.close()
+}
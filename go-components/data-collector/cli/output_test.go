@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/integration"
+)
+
+// readJSONL decodes the results of a JSON Lines file, stopping at the first incomplete line
+func readJSONL(t *testing.T, r io.Reader) []collector.WeatherResult {
+	t.Helper()
+	var results []collector.WeatherResult
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var result collector.WeatherResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("Invalid line %q: %v", scanner.Text(), err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// TestJSONLOutput tests that each result is on disk as soon as it is written, and that the
+// handshake marker is only written once the output is closed
+func TestJSONLOutput(t *testing.T) {
+	cfg := &config.Config{Integration: config.IntegrationConfig{OutputFile: filepath.Join(t.TempDir(), "output_weather.jsonl"), Handshake: true}}
+	path := cfg.GetOutputFilePath()
+	if err := integration.WriteFile(path, []byte("[]"), 0644); err != nil { // Left by the previous run
+		t.Fatal(err)
+	}
+
+	out, err := createJSONLOutput(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(integration.MarkerPath(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected the previous run's marker to be removed, got %v", err)
+	}
+
+	for i, name := range []string{"Oslo", "Bergen"} {
+		out.write(collector.WeatherResult{Location: collector.Location{Name: name}, Success: true})
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if results := readJSONL(t, bytes.NewReader(data)); len(results) != i+1 || results[i].Location.Name != name {
+			t.Fatalf("Expected %d results after writing %s, got %s", i+1, name, data)
+		}
+	}
+
+	if err := out.close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(integration.MarkerPath(path)); err != nil {
+		t.Errorf("Expected the marker once closed, got %v", err)
+	}
+}
+
+// TestJSONLOutputCompressed tests that compressed lines can be decoded before the output is closed
+func TestJSONLOutputCompressed(t *testing.T) {
+	cfg := &config.Config{Integration: config.IntegrationConfig{OutputFile: filepath.Join(t.TempDir(), "output_weather.jsonl"), Compress: true}}
+	out, err := createJSONLOutput(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.close()
+	out.write(collector.WeatherResult{Location: collector.Location{Name: "Oslo"}, Success: true})
+
+	file, err := os.Open(cfg.GetOutputFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The stream has no trailer yet, so reading ends in io.ErrUnexpectedEOF after the flushed line
+	data, _ := io.ReadAll(zr)
+	if results := readJSONL(t, bytes.NewReader(data)); len(results) != 1 || results[0].Location.Name != "Oslo" {
+		t.Errorf("Expected the flushed result, got %q", data)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// Output formats for pipe mode and the output file
+const (
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// validFormat reports whether format is a supported output format
+func validFormat(format string) bool {
+	return format == formatJSON || format == formatJSONL || format == formatCSV
+}
+
+// runPipe reads a JSON array of locations from in and writes results to out.
+// With formatJSON the whole result array is written once collection finishes; with
+// formatJSONL each result is written on its own line as soon as it completes; with
+// formatCSV the results are flattened into CSV rows once collection finishes.
+// Results are written in the configured units, unlike the output file. Logs go to stderr, so out
+// only ever carries result data. A run summary is written to integration.summary_file and sent to
+// the notification webhook once the results are out.
+func runPipe(ctx context.Context, cfg *config.Config, in io.Reader, out io.Writer, format string) ([]collector.WeatherResult, error) {
+	startedAt := time.Now()
+	if format == "" {
+		format = formatJSON
+	}
+	if !validFormat(format) {
+		return nil, fmt.Errorf("Unknown output format %q (expected %q, %q or %q)", format, formatJSON, formatJSONL, formatCSV)
+	}
+
+	var locations []collector.Location
+	if err := json.NewDecoder(in).Decode(&locations); err != nil {
+		return nil, fmt.Errorf("Failed to read locations from stdin: %w", err)
+	}
+
+	provider, err := collector.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Performance.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Performance.RunTimeout)
+		defer cancel()
+	}
+
+	system := cfg.OutputUnits()
+	encoder := json.NewEncoder(out)
+	var writeErr error
+	var onResult func(collector.WeatherResult)
+	if format == formatJSONL {
+		onResult = func(result collector.WeatherResult) {
+			if writeErr == nil {
+				writeErr = encoder.Encode(result.ConvertUnits(system))
+			}
+		}
+	}
+
+	results := collector.CollectEach(ctx, provider, locations, onResult)
+	switch format {
+	case formatJSON:
+		encoder.SetIndent("", "  ")
+		writeErr = encoder.Encode(collector.ConvertUnits(results, system))
+	case formatCSV:
+		writeErr = writeCSV(out, results, system, cfg.Integration.CSVForecast)
+	}
+	summary := summarize(results, startedAt, "")
+	if writeErr != nil {
+		err := fmt.Errorf("Failed to write results to stdout: %w", writeErr)
+		reportRun(ctx, cfg, summary.withError(err))
+		return results, err
+	}
+	reportRun(ctx, cfg, summary)
+
+	if cfg.Logging.EnableMetrics {
+		logMetrics(results)
+	}
+	return results, nil
+}
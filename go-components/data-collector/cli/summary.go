@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/notify"
+	"weathermodels/fileio"
+)
+
+// Process exit codes, so orchestration scripts can tell a partial failure from a total one
+const (
+	exitOK             = 0 // Every location was collected
+	exitError          = 1 // Unexpected failure (I/O, server errors)
+	exitPartialFailure = 2 // Some locations failed
+	exitTotalFailure   = 3 // Every location failed
+	exitConfigError    = 4 // Invalid configuration or flags
+)
+
+// Run statuses reported in runSummary.Status
+const (
+	statusOK      = "ok"
+	statusPartial = "partial"
+	statusFailed  = "failed"
+)
+
+// runSummary is the machine-readable outcome of a collection run, written to integration.summary_file
+// and sent to notifications.webhook_url
+type runSummary struct {
+	Status          string         `json:"status"`    // "ok", "partial" or "failed"
+	ExitCode        int            `json:"exit_code"` // Exit code of a one-shot run with these results
+	StartedAt       time.Time      `json:"started_at"`
+	FinishedAt      time.Time      `json:"finished_at"`
+	DurationMs      float64        `json:"duration_ms"`
+	Locations       int            `json:"locations"`
+	Successful      int            `json:"successful"`
+	Failed          int            `json:"failed"`
+	ErrorCodes      map[string]int `json:"error_codes,omitempty"` // Failed locations per collector error code
+	FailedLocations []string       `json:"failed_locations,omitempty"`
+	OutputFile      string         `json:"output_file,omitempty"` // Where the results were written ("" for pipe mode)
+	Error           string         `json:"error,omitempty"`       // Why the run failed before or after collecting (input or output errors)
+}
+
+// exitCodeFor returns the process exit code for a set of collection results
+func exitCodeFor(results []collector.WeatherResult) int {
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	switch {
+	case failed == 0:
+		return exitOK
+	case failed == len(results):
+		return exitTotalFailure
+	}
+	return exitPartialFailure
+}
+
+// summarize builds the run summary for results collected since startedAt
+func summarize(results []collector.WeatherResult, startedAt time.Time, outputFile string) runSummary {
+	finishedAt := time.Now()
+	summary := runSummary{
+		ExitCode:   exitCodeFor(results),
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		DurationMs: float64(finishedAt.Sub(startedAt).Microseconds()) / 1000,
+		Locations:  len(results),
+		OutputFile: outputFile,
+	}
+
+	for _, result := range results {
+		if result.Success {
+			summary.Successful++
+			continue
+		}
+		summary.Failed++
+		summary.FailedLocations = append(summary.FailedLocations, result.Location.Name)
+		if summary.ErrorCodes == nil {
+			summary.ErrorCodes = make(map[string]int)
+		}
+		summary.ErrorCodes[result.ErrorCode]++
+	}
+
+	switch summary.ExitCode {
+	case exitOK:
+		summary.Status = statusOK
+	case exitPartialFailure:
+		summary.Status = statusPartial
+	default:
+		summary.Status = statusFailed
+	}
+	return summary
+}
+
+// withError marks a run that failed outside of collection (unreadable input, unwritable output)
+func (s runSummary) withError(err error) runSummary {
+	s.Status, s.ExitCode, s.Error = statusFailed, exitError, err.Error()
+	return s
+}
+
+// reportRun writes the summary to integration.summary_file and POSTs it to notifications.webhook_url,
+// whichever are configured. Failures are logged rather than failing a run whose results were saved.
+func reportRun(ctx context.Context, cfg *config.Config, summary runSummary) {
+	if path := cfg.Integration.SummaryFile; path != "" {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0755)
+		}
+		if err == nil {
+			err = fileio.WriteFileAtomic(path, data, 0644)
+		}
+		if err != nil {
+			slog.Warn("Could not write run summary", "path", path, "error", err)
+		}
+	}
+
+	// Still notify when the run itself was interrupted by a shutdown signal
+	if err := notify.NewWebhook(cfg).Send(context.WithoutCancel(ctx), summary); err != nil {
+		slog.Warn("Could not send run notification", "url", cfg.Notifications.WebhookURL, "error", err)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+)
+
+// succeeded and failedResult are results of one location each
+var (
+	succeeded    = collector.WeatherResult{Location: collector.Location{Name: "Oslo"}, Success: true}
+	failedResult = collector.WeatherResult{Location: collector.Location{Name: "Atlantis"}, Error: "no data", ErrorCode: collector.ErrorCodeNoData}
+)
+
+// TestExitCodes tests the exit code and status of a run with each mix of results, and of runs
+// stopped by bad flags
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []collector.WeatherResult
+		code    int
+		status  string
+	}{
+		{name: "Every location collected", results: []collector.WeatherResult{succeeded, succeeded}, code: exitOK, status: statusOK},
+		{name: "No locations", results: nil, code: exitOK, status: statusOK},
+		{name: "Some locations failed", results: []collector.WeatherResult{succeeded, failedResult}, code: exitPartialFailure, status: statusPartial},
+		{name: "Every location failed", results: []collector.WeatherResult{failedResult, failedResult}, code: exitTotalFailure, status: statusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := exitCodeFor(tt.results); code != tt.code {
+				t.Errorf("Expected exit code %d, got %d", tt.code, code)
+			}
+			summary := summarize(tt.results, time.Now(), "")
+			if summary.ExitCode != tt.code || summary.Status != tt.status {
+				t.Errorf("Expected %s (%d), got %s (%d)", tt.status, tt.code, summary.Status, summary.ExitCode)
+			}
+		})
+	}
+
+	if code := Collect([]string{"-bogus"}); code != exitConfigError {
+		t.Errorf("Expected exit code %d for an unknown flag, got %d", exitConfigError, code)
+	}
+	if code := Collect([]string{"-h"}); code != exitOK {
+		t.Errorf("Expected exit code %d for -h, got %d", exitOK, code)
+	}
+}
+
+// TestSummarize tests the counts and the JSON of a run summary
+func TestSummarize(t *testing.T) {
+	startedAt := time.Now().Add(-1500 * time.Millisecond)
+	results := []collector.WeatherResult{succeeded, failedResult, failedResult}
+	summary := summarize(results, startedAt, "data/integration/output_weather.json")
+
+	if summary.Locations != 3 || summary.Successful != 1 || summary.Failed != 2 {
+		t.Errorf("Unexpected counts: %+v", summary)
+	}
+	if summary.ErrorCodes[collector.ErrorCodeNoData] != 2 || !slices.Equal(summary.FailedLocations, []string{"Atlantis", "Atlantis"}) {
+		t.Errorf("Unexpected failures: %v %v", summary.ErrorCodes, summary.FailedLocations)
+	}
+	if summary.DurationMs < 1500 {
+		t.Errorf("Expected a duration of at least 1500ms, got %.0fms", summary.DurationMs)
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"status", "exit_code", "started_at", "finished_at", "duration_ms", "locations", "successful", "failed", "error_codes", "failed_locations", "output_file"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Expected %q in the summary JSON, got %s", key, data)
+		}
+	}
+	if _, ok := fields["error"]; ok {
+		t.Errorf("Expected no error in the summary of a completed run, got %s", data)
+	}
+
+	failed := summarize(nil, startedAt, "").withError(errors.New("unreadable input"))
+	if failed.Status != statusFailed || failed.ExitCode != exitError || failed.Error != "unreadable input" {
+		t.Errorf("Unexpected summary of a failed run: %+v", failed)
+	}
+}
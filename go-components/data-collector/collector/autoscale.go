@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"weather-collector/config"
+)
+
+// Autoscaler adapts how many workers may fetch at once. Concurrency starts at the minimum,
+// grows by one worker after each window of fast, healthy results and halves when latency
+// exceeds the target or too many requests fail with load-related errors (timeouts, throttling,
+// server errors). A nil Autoscaler places no limit on the worker pool.
+type Autoscaler struct {
+	min, max      int
+	targetLatency time.Duration
+	maxErrorRate  float64
+
+	mu       sync.Mutex
+	limit    int           // Workers currently allowed to fetch
+	active   int           // Workers currently fetching
+	wake     chan struct{} // Closed when a slot frees up or the limit grows
+	observed int           // Results in the current window
+	errors   int           // Load-related failures in the current window
+	latency  time.Duration // Total latency in the current window
+}
+
+// NewAutoscaler creates an autoscaler from the performance configuration, or nil when autoscaling is off
+func NewAutoscaler(cfg config.PerformanceConfig) *Autoscaler {
+	if !cfg.Autoscale {
+		return nil
+	}
+	return &Autoscaler{
+		min:           cfg.MinWorkers,
+		max:           cfg.MaxWorkers,
+		targetLatency: cfg.TargetLatency,
+		maxErrorRate:  cfg.MaxErrorRate,
+		limit:         cfg.MinWorkers,
+		wake:          make(chan struct{}),
+	}
+}
+
+// Limit returns the number of workers currently allowed to fetch
+func (a *Autoscaler) Limit() int {
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.limit
+}
+
+// Acquire blocks until the worker may fetch or ctx is done
+func (a *Autoscaler) Acquire(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	for {
+		a.mu.Lock()
+		if a.active < a.limit {
+			a.active++
+			a.mu.Unlock()
+			return nil
+		}
+		wake := a.wake
+		a.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees the worker's slot and feeds its result into the current window,
+// adjusting the limit once the window (one result per allowed worker) is complete
+func (a *Autoscaler) Release(result WeatherResult) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.active--
+	a.observed++
+	a.latency += time.Duration(result.Meta.DurationMs * float64(time.Millisecond))
+	if !result.Success && isLoadError(result.ErrorCode) {
+		a.errors++
+	}
+
+	if a.observed >= a.limit {
+		a.adjust()
+	}
+	a.broadcast()
+}
+
+// adjust applies additive increase / multiplicative decrease to the limit and starts a new window
+func (a *Autoscaler) adjust() {
+	avgLatency := a.latency / time.Duration(a.observed)
+	errorRate := float64(a.errors) / float64(a.observed)
+
+	previous := a.limit
+	if errorRate > a.maxErrorRate || avgLatency > a.targetLatency {
+		a.limit = max(a.min, a.limit/2)
+	} else {
+		a.limit = min(a.max, a.limit+1)
+	}
+	if a.limit != previous {
+		slog.Debug("Autoscaler adjusted workers", "from", previous, "to", a.limit,
+			"avg_latency", avgLatency.Round(time.Millisecond), "error_rate", errorRate)
+	}
+
+	a.observed, a.errors, a.latency = 0, 0, 0
+}
+
+// broadcast wakes every worker waiting in Acquire
+func (a *Autoscaler) broadcast() {
+	close(a.wake)
+	a.wake = make(chan struct{})
+}
+
+// isLoadError reports whether a failure suggests we are pushing the API too hard,
+// as opposed to a problem with the location itself (bad coordinates, no data)
+func isLoadError(code string) bool {
+	switch code {
+	case ErrorCodeTimeout, ErrorCodeRateLimited, ErrorCodeCircuitOpen, ErrorCodeUnknown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// newTestAutoscaler creates an autoscaler between 1 and 4 workers with a 100ms latency target
+func newTestAutoscaler() *Autoscaler {
+	return NewAutoscaler(config.PerformanceConfig{
+		Autoscale:     true,
+		MinWorkers:    1,
+		MaxWorkers:    4,
+		TargetLatency: 100 * time.Millisecond,
+		MaxErrorRate:  0.2,
+	})
+}
+
+// runWindow feeds one full window of results into the autoscaler
+func runWindow(t *testing.T, a *Autoscaler, result WeatherResult) {
+	t.Helper()
+	for range a.Limit() {
+		if err := a.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range a.Limit() {
+		a.Release(result)
+	}
+}
+
+// TestAutoscalerRampsUpAndBacksOff tests additive increase on healthy windows and
+// multiplicative decrease on slow or failing ones, within the configured bounds
+func TestAutoscalerRampsUpAndBacksOff(t *testing.T) {
+	a := newTestAutoscaler()
+	fast := WeatherResult{Success: true, Meta: CollectionMeta{DurationMs: 20}}
+	slow := WeatherResult{Success: true, Meta: CollectionMeta{DurationMs: 500}}
+	throttled := WeatherResult{ErrorCode: ErrorCodeRateLimited, Meta: CollectionMeta{DurationMs: 20}}
+	badInput := WeatherResult{ErrorCode: ErrorCodeBadCoordinates, Meta: CollectionMeta{DurationMs: 20}}
+
+	if a.Limit() != 1 {
+		t.Fatalf("Expected to start at min workers, got %d", a.Limit())
+	}
+	for _, want := range []int{2, 3, 4, 4} {
+		runWindow(t, a, fast)
+		if a.Limit() != want {
+			t.Errorf("Expected %d workers after a fast window, got %d", want, a.Limit())
+		}
+	}
+
+	runWindow(t, a, slow)
+	if a.Limit() != 2 {
+		t.Errorf("Expected slow responses to halve workers to 2, got %d", a.Limit())
+	}
+	runWindow(t, a, throttled)
+	if a.Limit() != 1 {
+		t.Errorf("Expected throttling to halve workers to 1, got %d", a.Limit())
+	}
+	runWindow(t, a, throttled)
+	if a.Limit() != 1 {
+		t.Errorf("Expected workers to stay at the minimum, got %d", a.Limit())
+	}
+
+	runWindow(t, a, badInput)
+	if a.Limit() != 2 {
+		t.Errorf("Expected location errors not to count as load, got %d workers", a.Limit())
+	}
+}
+
+// TestAutoscalerLimitsConcurrency tests that Acquire blocks beyond the limit until a slot is released
+func TestAutoscalerLimitsConcurrency(t *testing.T) {
+	a := newTestAutoscaler()
+	if err := a.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := a.Acquire(ctx); err == nil {
+		t.Fatal("Expected a second worker to wait while the limit is 1")
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- a.Acquire(context.Background()) }()
+	a.Release(WeatherResult{Success: true})
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the waiting worker to acquire the released slot")
+	}
+}
+
+// TestCollectWithAutoscale tests that an autoscaled collection still returns every location in order
+func TestCollectWithAutoscale(t *testing.T) {
+	cfg := config.Get()
+	original := cfg.Performance
+	cfg.Performance.Autoscale = true
+	cfg.Performance.MinWorkers = 1
+	cfg.Performance.TargetLatency = time.Second
+	cfg.Performance.MaxErrorRate = 0.5
+	t.Cleanup(func() { cfg.Performance = original })
+
+	locations := []Location{{Name: "Oslo", Lat: 59.91}, {Name: "Bergen", Lat: 60.39}, {Name: "Tromsø", Lat: 69.65}}
+	results := CollectWithProvider(context.Background(), &stubProvider{}, locations)
+
+	for i, result := range results {
+		if !result.Success || result.Location.Name != locations[i].Name {
+			t.Errorf("Result %d: expected success for %s, got %+v", i, locations[i].Name, result)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"weather-collector/config"
+)
+
+// ErrCircuitOpen is reported for requests short-circuited by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker open: endpoint is failing, request skipped")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Requests flow normally
+	BreakerOpen                         // Requests are rejected until the cooldown elapses
+	BreakerHalfOpen                     // A single probe request is allowed through
+)
+
+// String returns the state name used in logs and metrics
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops hammering an endpoint that keeps failing. It opens after Threshold
+// consecutive transient failures, rejects requests for Cooldown, then lets one probe through:
+// a successful probe closes it again, a failed one re-opens it.
+// A nil breaker allows everything.
+type CircuitBreaker struct {
+	Endpoint  string        // Endpoint URL, used in logs
+	Threshold int           // Consecutive failures before opening
+	Cooldown  time.Duration // Time spent open before a probe is allowed
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed breaker for an endpoint
+func NewCircuitBreaker(endpoint string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Endpoint:  endpoint,
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a request may be made, returning ErrCircuitOpen when it may not
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record reports the outcome of an allowed request. Only transient failures (5xx, timeouts)
+// should be recorded as failures; any response that proves the endpoint is up counts as success.
+func (b *CircuitBreaker) Record(success bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.Threshold {
+		b.openedAt = b.now()
+		if b.state != BreakerOpen {
+			b.setState(BreakerOpen)
+		}
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// setState changes state and logs the transition; callers must hold b.mu
+func (b *CircuitBreaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+	if state == BreakerOpen {
+		slog.Warn("Circuit breaker opened", "endpoint", b.Endpoint, "from", from.String(),
+			"failures", b.failures, "cooldown", b.Cooldown)
+		return
+	}
+	slog.Info("Circuit breaker state changed", "endpoint", b.Endpoint, "from", from.String(), "to", state.String())
+}
+
+// sharedBreakers holds one breaker per endpoint so every worker and provider in the process
+// sees the same failure history
+var (
+	sharedBreakersMu sync.Mutex
+	sharedBreakers   = map[string]*CircuitBreaker{}
+)
+
+// sharedBreaker returns the process-wide breaker for an endpoint, or nil when breakers are disabled
+func sharedBreaker(cfg *config.Config, endpoint string) *CircuitBreaker {
+	if cfg.API.BreakerThreshold <= 0 {
+		return nil
+	}
+
+	sharedBreakersMu.Lock()
+	defer sharedBreakersMu.Unlock()
+
+	breaker, ok := sharedBreakers[endpoint]
+	if !ok {
+		breaker = NewCircuitBreaker(endpoint, cfg.API.BreakerThreshold, cfg.API.BreakerCooldown)
+		sharedBreakers[endpoint] = breaker
+	}
+	breaker.mu.Lock()
+	breaker.Threshold, breaker.Cooldown = cfg.API.BreakerThreshold, cfg.API.BreakerCooldown
+	breaker.mu.Unlock()
+	return breaker
+}
+
+// BreakerStates returns the current state of every endpoint breaker, keyed by endpoint URL
+func BreakerStates() map[string]string {
+	sharedBreakersMu.Lock()
+	defer sharedBreakersMu.Unlock()
+
+	states := make(map[string]string, len(sharedBreakers))
+	for endpoint, breaker := range sharedBreakers {
+		states[endpoint] = breaker.State().String()
+	}
+	return states
+}
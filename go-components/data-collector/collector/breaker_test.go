@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// TestCircuitBreakerTransitions tests opening, cooldown, half-open probing and closing
+func TestCircuitBreakerTransitions(t *testing.T) {
+	now := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker("test", 3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Attempt %d should be allowed while closed, got %v", i+1, err)
+		}
+		breaker.Record(false)
+	}
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("Expected breaker to open after 3 failures, got %s", breaker.State())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen while open, got %v", err)
+	}
+
+	// After the cooldown a single probe is allowed; a failed probe re-opens the breaker
+	now = now.Add(time.Minute)
+	if err := breaker.Allow(); err != nil || breaker.State() != BreakerHalfOpen {
+		t.Fatalf("Expected a half-open probe after cooldown, got %v (%s)", err, breaker.State())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected only one probe while half-open, got %v", err)
+	}
+	breaker.Record(false)
+	if breaker.State() != BreakerOpen {
+		t.Errorf("Expected failed probe to re-open the breaker, got %s", breaker.State())
+	}
+
+	// A successful probe closes it again
+	now = now.Add(time.Minute)
+	breaker.Allow()
+	breaker.Record(true)
+	if breaker.State() != BreakerClosed {
+		t.Errorf("Expected successful probe to close the breaker, got %s", breaker.State())
+	}
+}
+
+// TestRetryPolicyShortCircuits tests that an open breaker stops requests to a failing endpoint
+func TestRetryPolicyShortCircuits(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 2)
+
+	cfg := config.Get()
+	cfg.API.BreakerThreshold = 3 // restored by useTestAPI
+	provider := NewMetNoProvider(cfg)
+	first := FetchWithProvider(context.Background(), provider, Location{Name: "Down", Lat: 60.0, Lon: 10.0})
+	if first.Success || calls.Load() != 3 {
+		t.Fatalf("Expected 3 failed attempts before the breaker opens, got %d calls", calls.Load())
+	}
+
+	second := FetchWithProvider(context.Background(), provider, Location{Name: "Skipped", Lat: 61.0, Lon: 10.0})
+	if calls.Load() != 3 {
+		t.Errorf("Expected no requests while the breaker is open, got %d calls", calls.Load())
+	}
+	if second.Error != ErrCircuitOpen.Error() || second.Attempts != 0 {
+		t.Errorf("Expected a short-circuited result, got error %q after %d attempts", second.Error, second.Attempts)
+	}
+	if BreakerStates()[server.URL] != "open" {
+		t.Errorf("Expected breaker state to be reported as open, got %v", BreakerStates())
+	}
+}
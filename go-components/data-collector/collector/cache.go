@@ -0,0 +1,233 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"weather-collector/config"
+	"weathermodels/fileio"
+)
+
+// CacheEntry holds the last payload returned by the API for a location along with its caching headers
+type CacheEntry struct {
+	Body         json.RawMessage `json:"body"`          // Raw JSON payload
+	Expires      time.Time       `json:"expires"`       // When the payload stops being fresh (from the Expires header)
+	LastModified string          `json:"last_modified"` // Last-Modified header, sent back as If-Modified-Since
+	FetchedAt    time.Time       `json:"fetched_at"`    // When the payload was last received from the API
+}
+
+// Fresh reports whether the entry can be served without contacting the API.
+// An entry is fresh until its Expires time, or for ttl after it was fetched when ttl is positive.
+func (e CacheEntry) Fresh(now time.Time, ttl time.Duration) bool {
+	if !e.Expires.IsZero() && now.Before(e.Expires) {
+		return true
+	}
+	return ttl > 0 && !e.FetchedAt.IsZero() && now.Before(e.FetchedAt.Add(ttl))
+}
+
+// ResponseCache stores API responses keyed by rounded coordinates so repeated
+// requests honor met.no's Expires/If-Modified-Since caching requirements.
+// When Dir is set, entries are also persisted to disk so they survive between runs.
+type ResponseCache struct {
+	Dir string        // Directory for persisted entries ("" keeps the cache in memory only)
+	TTL time.Duration // Minimum freshness window regardless of Expires (0 = honor Expires only)
+
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+	now     func() time.Time
+}
+
+// NewResponseCache creates an empty in-memory response cache
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{
+		entries: make(map[string]CacheEntry),
+		now:     time.Now,
+	}
+}
+
+// NewDiskResponseCache creates a response cache persisted under dir
+func NewDiskResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	cache := NewResponseCache()
+	cache.Dir = dir
+	cache.TTL = ttl
+	return cache
+}
+
+// sharedCaches holds one cache per directory ("" is the in-memory cache) reused by every
+// met.no provider in the process
+var (
+	sharedCachesMu sync.Mutex
+	sharedCaches   = map[string]*ResponseCache{}
+)
+
+// sharedResponseCache returns the process-wide cache matching the cache configuration
+func sharedResponseCache(cfg *config.Config) *ResponseCache {
+	dir, ttl := "", time.Duration(0)
+	if cfg.Cache.Enabled {
+		dir, ttl = cfg.Cache.Directory, cfg.Cache.TTL
+	}
+
+	sharedCachesMu.Lock()
+	defer sharedCachesMu.Unlock()
+
+	cache, ok := sharedCaches[dir]
+	if !ok {
+		cache = NewDiskResponseCache(dir, ttl)
+		sharedCaches[dir] = cache
+	}
+	cache.TTL = ttl
+	return cache
+}
+
+// cacheKey rounds coordinates to the 4 decimals met.no accepts so nearby requests share an entry.
+// The altitude is part of the key when set since it changes the forecast.
+func cacheKey(loc Location) string {
+	if loc.Alt != 0 {
+		return fmt.Sprintf("%.4f,%.4f,%d", loc.Lat, loc.Lon, loc.Alt)
+	}
+	return fmt.Sprintf("%.4f,%.4f", loc.Lat, loc.Lon)
+}
+
+// Get returns the cached entry for a key, falling back to disk when the cache is persistent
+func (c *ResponseCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		return entry, true
+	}
+	if c.Dir == "" {
+		return CacheEntry{}, false
+	}
+
+	data, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		slog.Warn("Ignoring corrupt cache entry", "path", c.entryPath(key), "error", err)
+		return CacheEntry{}, false
+	}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// Put stores an entry for a key, persisting it to disk when the cache is persistent
+func (c *ResponseCache) Put(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+
+	if c.Dir == "" {
+		return
+	}
+	if err := c.persist(key, entry); err != nil {
+		slog.Warn("Could not persist cache entry", "key", key, "error", err)
+	}
+}
+
+// Fresh reports whether an entry can be served without contacting the API
+func (c *ResponseCache) Fresh(entry CacheEntry) bool {
+	return entry.Fresh(c.now(), c.TTL)
+}
+
+// persist writes an entry to the cache directory, atomically so a concurrent run or a crash
+// never leaves a truncated entry
+func (c *ResponseCache) persist(key string, entry CacheEntry) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return fileio.WriteFileAtomic(c.entryPath(key), data, 0644)
+}
+
+// entryPath returns the file used to persist a key
+func (c *ResponseCache) entryPath(key string) string {
+	return filepath.Join(c.Dir, strings.ReplaceAll(key, ",", "_")+".json")
+}
+
+// getCachedJSON behaves like getJSON but serves fresh entries from the cache, sends
+// If-Modified-Since for stale ones, and reuses the cached payload on 304 Not Modified.
+// It reports whether the payload came from the cache and whether a failure is worth retrying.
+func getCachedJSON(ctx context.Context, client *http.Client, url, userAgent string, cache *ResponseCache, key string, v any) (bool, bool, error) {
+	entry, cached := cache.Get(key)
+	if cached && cache.Fresh(entry) {
+		return true, false, decodeCachedBody(entry.Body, v)
+	}
+
+	req, err := newGetRequest(ctx, url, userAgent)
+	if err != nil {
+		return false, false, err
+	}
+	if cached && entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, isRetryableError(err), requestError(err)
+	}
+	defer resp.Body.Close()
+	recordStatus(ctx, resp.StatusCode)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		// Payload unchanged: refresh the expiry and reuse what we have
+		entry.Expires = parseHTTPTime(resp.Header.Get("Expires"))
+		entry.FetchedAt = cache.now()
+		cache.Put(key, entry)
+		return true, false, decodeCachedBody(entry.Body, v)
+	case resp.StatusCode != http.StatusOK:
+		return false, isRetryableResponse(resp), statusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, isRetryableError(err), fmt.Errorf("Failed to read response: %v", err)
+	}
+	if err := decodeJSON(bytes.NewReader(body), v); err != nil {
+		return false, false, classify(ErrDecode, fmt.Errorf("Failed to parse JSON: %v", err))
+	}
+
+	cache.Put(key, CacheEntry{
+		Body:         body,
+		Expires:      parseHTTPTime(resp.Header.Get("Expires")),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    cache.now(),
+	})
+	return false, false, nil
+}
+
+// decodeCachedBody decodes a cached payload
+func decodeCachedBody(body []byte, v any) error {
+	if err := decodeJSON(bytes.NewReader(body), v); err != nil {
+		return classify(ErrDecode, fmt.Errorf("Failed to parse cached JSON: %v", err))
+	}
+	return nil
+}
+
+// parseHTTPTime parses an HTTP date header, returning the zero time if absent or malformed
+func parseHTTPTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	parsed, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// newCachingTestProvider creates a met.no provider with a private cache pointed at a test server
+func newCachingTestProvider(serverURL string) *MetNoProvider {
+	cfg := *config.Get()
+	cfg.API.BaseURL = serverURL
+	cfg.API.MaxRetries = 0
+	provider := NewMetNoProvider(&cfg)
+	provider.Cache = NewResponseCache()
+	return provider
+}
+
+// TestCacheServesFreshEntries tests that a payload is reused until its Expires time
+func TestCacheServesFreshEntries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Expires", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+
+	provider := newCachingTestProvider(server.URL)
+	loc := Location{Name: "Oslo", Lat: 59.91, Lon: 10.75}
+
+	for i := 0; i < 3; i++ {
+		if _, err := provider.Fetch(context.Background(), loc); err != nil {
+			t.Fatalf("Fetch %d failed: %v", i, err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 API request while the cache is fresh, got %d", requests)
+	}
+}
+
+// TestCacheRevalidatesWithIfModifiedSince tests 304 handling for expired entries
+func TestCacheRevalidatesWithIfModifiedSince(t *testing.T) {
+	const lastModified = "Fri, 03 Oct 2025 01:00:00 GMT"
+	requests := 0
+	notModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Expires", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+
+	provider := newCachingTestProvider(server.URL)
+	loc := Location{Name: "Oslo", Lat: 59.91, Lon: 10.75}
+
+	first, err := provider.Fetch(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("First fetch failed: %v", err)
+	}
+	second, err := provider.Fetch(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("Revalidated fetch failed: %v", err)
+	}
+
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected 2 requests with 1 conditional 304, got %d requests / %d 304s", requests, notModified)
+	}
+	if second.CurrentWeather.Temperature != first.CurrentWeather.Temperature {
+		t.Errorf("Expected cached payload on 304, got %.1f vs %.1f",
+			second.CurrentWeather.Temperature, first.CurrentWeather.Temperature)
+	}
+}
+
+// TestCacheKeyRounding tests that coordinates are rounded to 4 decimals
+func TestCacheKeyRounding(t *testing.T) {
+	a := cacheKey(Location{Lat: 59.913868, Lon: 10.752245})
+	b := cacheKey(Location{Lat: 59.91391, Lon: 10.75219})
+	if a != b {
+		t.Errorf("Expected nearby coordinates to share a cache key, got %s and %s", a, b)
+	}
+}
+
+// TestDiskCacheSurvivesBetweenRuns tests that a persisted entry is served by a new cache instance
+func TestDiskCacheSurvivesBetweenRuns(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	loc := Location{Name: "Oslo", Lat: 59.91, Lon: 10.75}
+
+	firstRun := newCachingTestProvider(server.URL)
+	firstRun.Cache = NewDiskResponseCache(dir, time.Hour)
+	first, err := firstRun.Fetch(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+	if first.Source != SourceAPI {
+		t.Errorf("Expected first result from the API, got source '%s'", first.Source)
+	}
+
+	// A fresh cache instance simulates the next process start
+	secondRun := newCachingTestProvider(server.URL)
+	secondRun.Cache = NewDiskResponseCache(dir, time.Hour)
+	second, err := secondRun.Fetch(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the second run to be served from disk, got %d API requests", requests)
+	}
+	if second.Source != SourceCache {
+		t.Errorf("Expected cached result to be marked '%s', got '%s'", SourceCache, second.Source)
+	}
+	if second.CurrentWeather.Temperature != first.CurrentWeather.Temperature {
+		t.Errorf("Expected identical data from cache, got %.1f vs %.1f",
+			second.CurrentWeather.Temperature, first.CurrentWeather.Temperature)
+	}
+}
+
+// TestCacheEntryTTL tests freshness by TTL when no Expires header was sent
+func TestCacheEntryTTL(t *testing.T) {
+	now := time.Now()
+	entry := CacheEntry{FetchedAt: now.Add(-10 * time.Minute)}
+
+	if !entry.Fresh(now, 30*time.Minute) {
+		t.Error("Entry fetched 10 minutes ago should be fresh with a 30 minute TTL")
+	}
+	if entry.Fresh(now, 5*time.Minute) {
+		t.Error("Entry fetched 10 minutes ago should be stale with a 5 minute TTL")
+	}
+	if entry.Fresh(now, 0) {
+		t.Error("Entry without Expires should be stale when TTL is disabled")
+	}
+}
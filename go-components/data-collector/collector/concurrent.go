@@ -1,38 +1,74 @@
 package collector
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"sync"
+	"time"
 
 	"weather-collector/config"
 )
 
 // CollectWeatherData orchestrates weather collection for multiple locations
-// Uses config for performance settings and rate limiting
-func CollectWeatherData(locations []Location) []WeatherResult {
+// Uses config for performance settings and rate limiting. Cancelling ctx stops
+// outstanding requests; locations that were not collected are reported as failed.
+func CollectWeatherData(ctx context.Context, locations []Location) []WeatherResult {
+	provider, err := NewProvider(config.Get())
+	if err != nil {
+		slog.Error("Cannot create weather provider", "error", err)
+		results := make([]WeatherResult, len(locations))
+		for i, location := range locations {
+			results[i] = failedResult(location, err)
+		}
+		return results
+	}
+	return CollectWithProvider(ctx, provider, locations)
+}
+
+// CollectWithProvider orchestrates weather collection for multiple locations using a specific provider
+func CollectWithProvider(ctx context.Context, provider Provider, locations []Location) []WeatherResult {
+	return CollectEach(ctx, provider, locations, nil)
+}
+
+// CollectEach is CollectWithProvider with a callback invoked for each result as soon as it
+// completes (in completion order, from the calling goroutine); the returned slice is in input order.
+// With performance.autoscale set, MaxWorkers workers are started but only as many as the
+// Autoscaler allows fetch at once. Locations with the same rounded coordinates share one request,
+// higher-priority locations are dispatched first and a location's own provider and timeout
+// override the run's.
+func CollectEach(ctx context.Context, provider Provider, locations []Location, onResult func(WeatherResult)) []WeatherResult {
 	cfg := config.Get()
+	scaler := NewAutoscaler(cfg.Performance)
+	providers := NewProviderSet(cfg, provider)
+
+	slog.Info("Starting weather collection", "locations", len(locations))
+	slog.Debug("Collection settings", "provider", provider.Name(), "max_workers", cfg.Performance.MaxWorkers,
+		"autoscale", cfg.Performance.Autoscale)
 
-	log.Printf("Starting weather collection for %d locations...", len(locations))
-	if cfg.Logging.EnableDebug {
-		log.Printf("Using max workers: %d", cfg.Performance.MaxWorkers)
+	// Locations sharing coordinates are fetched once and the result fanned out to each of them
+	groups := groupLocations(locations, cfg.Performance.WorkerTimeout)
+	if duplicates := len(locations) - len(groups); duplicates > 0 {
+		slog.Info("Merged duplicate locations", "duplicates", duplicates, "requests", len(groups))
 	}
 
 	// Create job and result channels
-	jobs := make(chan job, len(locations))
-	results := make(chan workerResult, len(locations))
+	jobs := make(chan job, len(groups))
+	results := make(chan workerResult, len(groups))
 
 	// Start worker pool
 	var wg sync.WaitGroup
 	for w := 0; w < cfg.Performance.MaxWorkers; w++ {
 		wg.Add(1)
-		go worker(jobs, results, &wg)
+		go worker(ctx, scaler, jobs, results, &wg)
 	}
 
 	// Send jobs to workers
 	go func() {
 		defer close(jobs)
-		for i, location := range locations {
-			jobs <- job{index: i, location: location}
+		for _, i := range dispatchOrder(groups) {
+			group := groups[i]
+			jobs <- job{index: i, location: group.location, provider: providers.For(group.location), timeout: group.timeout}
 		}
 	}()
 
@@ -46,27 +82,71 @@ func CollectWeatherData(locations []Location) []WeatherResult {
 	jobResults := make([]WeatherResult, len(locations))
 	completed := 0
 	for res := range results {
-		jobResults[res.index] = res.result
-		completed++
-
-		// Log the result
-		if res.result.Success {
-			log.Printf("✅ Success: %s - %.1f°C", res.result.Location.Name, res.result.CurrentWeather.Temperature)
-		} else {
-			log.Printf("❌ Failed: %s - %s", res.result.Location.Name, res.result.Error)
+		for n, i := range groups[res.index].indexes {
+			result := res.result
+			if n > 0 {
+				result.Location = locations[i] // Duplicates keep their own label and coordinates
+			}
+			jobResults[i] = result
+			completed++
+
+			// Log the result
+			if result.Success {
+				slog.Info("Collected weather", "location", result.Location.Name,
+					"temperature", result.CurrentWeather.Temperature, "attempts", result.Attempts, "source", result.Source)
+			} else {
+				slog.Error("Collection failed", "location", result.Location.Name, "error", result.Error)
+			}
+
+			if onResult != nil {
+				onResult(result)
+			}
 		}
 	}
 
-	log.Printf("Completed collection for %d/%d locations", completed, len(locations))
+	if ctx.Err() != nil {
+		slog.Warn("Collection stopped early", "cause", context.Cause(ctx))
+	}
+	if scaler != nil {
+		slog.Debug("Autoscaler final workers", "workers", scaler.Limit())
+	}
+	slog.Info("Completed collection", "completed", completed, "locations", len(locations))
 	return jobResults
 }
 
-// worker processes jobs from the jobs channel and sends results to the results channel
-func worker(jobs <-chan job, results chan<- workerResult, wg *sync.WaitGroup) {
+// worker processes jobs from the jobs channel and sends results to the results channel.
+// Each fetch waits for a slot from the autoscaler (if any) and is bounded by the job's
+// timeout; once ctx is done remaining jobs are drained as cancelled so partial results can still be written.
+func worker(ctx context.Context, scaler *Autoscaler, jobs <-chan job, results chan<- workerResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for job := range jobs {
-		result := FetchWeatherForLocation(job.location)
+		if err := ctx.Err(); err != nil {
+			results <- workerResult{index: job.index, result: cancelledResult(ctx, job.location)}
+			continue
+		}
+		if err := scaler.Acquire(ctx); err != nil {
+			results <- workerResult{index: job.index, result: cancelledResult(ctx, job.location)}
+			continue
+		}
+
+		result := fetchWithTimeout(ctx, job.provider, job.location, job.timeout)
+		scaler.Release(result)
 		results <- workerResult{index: job.index, result: result}
 	}
 }
+
+// fetchWithTimeout fetches a single location, bounding the call by timeout when it is positive
+func fetchWithTimeout(ctx context.Context, provider Provider, loc Location, timeout time.Duration) WeatherResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return FetchWithProvider(ctx, provider, loc)
+}
+
+// cancelledResult builds the failure reported for a location skipped because the run was cancelled
+func cancelledResult(ctx context.Context, loc Location) WeatherResult {
+	return failedResult(loc, fmt.Errorf("Collection cancelled: %w", context.Cause(ctx)))
+}
@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// errStopDecoding ends a stream decode early once the remaining data is not needed
+var errStopDecoding = errors.New("stop decoding")
+
+// streamDecoder is implemented by responses that decode themselves token by token
+// instead of being unmarshaled in one go
+type streamDecoder interface {
+	DecodeStream(dec *json.Decoder) error
+}
+
+// decodeJSON decodes a JSON document from r into v, streaming it when v supports that
+func decodeJSON(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	if stream, ok := v.(streamDecoder); ok {
+		return stream.DecodeStream(dec)
+	}
+	return dec.Decode(v)
+}
+
+// DecodeStream walks the GeoJSON document down to properties.timeseries and decodes one entry
+// at a time, skipping everything else and stopping once an entry is more than MaxHours past the first
+func (r *APIResponse) DecodeStream(dec *json.Decoder) error {
+	err := decodeObject(dec, func(key string) error {
+		if key != "properties" {
+			return skipValue(dec)
+		}
+		return decodeObject(dec, func(key string) error {
+			if key != "timeseries" {
+				return skipValue(dec)
+			}
+			return r.decodeTimeseries(dec)
+		})
+	})
+	if errors.Is(err, errStopDecoding) {
+		return nil
+	}
+	return err
+}
+
+// decodeTimeseries decodes the timeseries array entry by entry
+func (r *APIResponse) decodeTimeseries(dec *json.Decoder) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+
+	var cutoff time.Time
+	for dec.More() {
+		var entry TimeseriesEntry
+		if err := dec.Decode(&entry); err != nil {
+			return err
+		}
+
+		if r.MaxHours > 0 {
+			if cutoff.IsZero() {
+				cutoff = entry.Time.Add(time.Duration(r.MaxHours) * time.Hour)
+			} else if entry.Time.After(cutoff) {
+				return errStopDecoding
+			}
+		}
+		r.Timeseries = append(r.Timeseries, entry)
+	}
+
+	_, err := dec.Token() // closing ]
+	return err
+}
+
+// decodeObject reads a JSON object, calling field for each key with the decoder positioned at its value
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", token)
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token() // closing }
+	return err
+}
+
+// expectDelim reads the next token and checks it is the given delimiter
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q, got %v", want, token)
+	}
+	return nil
+}
+
+// skipValue consumes the next JSON value without decoding it into Go values
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const streamTestDocument = `{
+	"type": "Feature",
+	"geometry": {"type": "Point", "coordinates": [10.0, 60.0, 100]},
+	"properties": {
+		"meta": {"units": {"air_temperature": "celsius"}, "updated_at": "2024-01-01T00:00:00Z"},
+		"timeseries": [
+			{"time": "2024-01-01T00:00:00Z", "data": {"instant": {"details": {"air_temperature": 1.5}}}},
+			{"time": "2024-01-01T01:00:00Z", "data": {"instant": {"details": {"air_temperature": 2.5}}}},
+			{"time": "2024-01-01T02:00:00Z", "data": {"instant": {"details": {"air_temperature": 3.5}}}},
+			{"time": "2024-01-01T06:00:00Z", "data": {"instant": {"details": {"air_temperature": 4.5}}}}
+		]
+	}
+}`
+
+// TestDecodeStreamSkipsUnusedFields tests that the stream decoder finds the timeseries past other keys
+func TestDecodeStreamSkipsUnusedFields(t *testing.T) {
+	var resp APIResponse
+	if err := decodeJSON(strings.NewReader(streamTestDocument), &resp); err != nil {
+		t.Fatalf("decodeJSON failed: %v", err)
+	}
+	if len(resp.Timeseries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(resp.Timeseries))
+	}
+	if got := resp.Timeseries[3].Data.Instant.Details.AirTemperature; got != 4.5 {
+		t.Errorf("Expected last temperature 4.5, got %v", got)
+	}
+}
+
+// TestDecodeStreamMaxHours tests that decoding stops at entries beyond MaxHours
+func TestDecodeStreamMaxHours(t *testing.T) {
+	resp := APIResponse{MaxHours: 2}
+	if err := decodeJSON(strings.NewReader(streamTestDocument), &resp); err != nil {
+		t.Fatalf("decodeJSON failed: %v", err)
+	}
+	if len(resp.Timeseries) != 3 {
+		t.Fatalf("Expected 3 entries within 2 hours, got %d", len(resp.Timeseries))
+	}
+	if last := resp.Timeseries[2].Time; !last.Equal(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected last entry at 02:00, got %s", last)
+	}
+}
+
+// TestDecodeStreamMatchesFullDecode tests that streaming a recorded response gives the same
+// entries as unmarshaling the whole document
+func TestDecodeStreamMatchesFullDecode(t *testing.T) {
+	data, err := os.ReadFile("testdata/fixtures/api.met.no/weatherapi/locationforecast/2.0/complete/lat=51.5074_lon=-0.1278.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var full struct {
+		Properties struct {
+			Timeseries []TimeseriesEntry `json:"timeseries"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &full); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	var streamed APIResponse
+	if err := decodeJSON(bytes.NewReader(data), &streamed); err != nil {
+		t.Fatalf("decodeJSON failed: %v", err)
+	}
+	if len(streamed.Timeseries) != len(full.Properties.Timeseries) {
+		t.Fatalf("Expected %d entries, got %d", len(full.Properties.Timeseries), len(streamed.Timeseries))
+	}
+	for i := range full.Properties.Timeseries {
+		if streamed.Timeseries[i] != full.Properties.Timeseries[i] {
+			t.Errorf("Entry %d differs: %+v vs %+v", i, streamed.Timeseries[i], full.Properties.Timeseries[i])
+		}
+	}
+}
+
+// TestDecodeStreamRejectsMalformed tests that a document that is not an object is a decode error
+func TestDecodeStreamRejectsMalformed(t *testing.T) {
+	var resp APIResponse
+	if err := decodeJSON(strings.NewReader(`[1, 2, 3]`), &resp); err == nil {
+		t.Error("Expected an error for a non-object document")
+	}
+}
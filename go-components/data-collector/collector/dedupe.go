@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"sort"
+	"time"
+)
+
+// locationGroup is a set of input locations that share coordinates and are fetched with one request
+type locationGroup struct {
+	location Location      // Representative location sent to the provider (the first entry in the group)
+	indexes  []int         // Positions of every matching entry in the input list
+	priority int           // Highest priority among the entries
+	timeout  time.Duration // Longest timeout among the entries (0 = no limit)
+}
+
+// groupLocations collapses locations with the same rounded coordinates, altitude, coastal flag and
+// provider into one group each, in order of first appearance. Input lists from the Python side often repeat
+// a city under different labels. Name-only locations are never merged since their coordinates are
+// not known until they are geocoded. Each entry's timeout is its own or else fallback, the run's,
+// so that a short override on one entry does not cut off another that relied on the run's timeout.
+func groupLocations(locations []Location, fallback time.Duration) []locationGroup {
+	groups := make([]locationGroup, 0, len(locations))
+	byKey := make(map[string]int, len(locations))
+	for i, loc := range locations {
+		key := dedupeKey(loc)
+		if g, ok := byKey[key]; ok {
+			group := &groups[g]
+			group.indexes = append(group.indexes, i)
+			group.priority = max(group.priority, loc.Priority)
+			group.timeout = longerTimeout(group.timeout, loc.FetchTimeout(fallback))
+			continue
+		}
+		if key != "" {
+			byKey[key] = len(groups)
+		}
+		groups = append(groups, locationGroup{location: loc, indexes: []int{i}, priority: loc.Priority, timeout: loc.FetchTimeout(fallback)})
+	}
+	return groups
+}
+
+// longerTimeout returns the longer of two timeouts, where 0 means no limit
+func longerTimeout(a, b time.Duration) time.Duration {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return max(a, b)
+}
+
+// dedupeKey returns the key identical requests share, or "" for locations that must not be merged
+func dedupeKey(loc Location) string {
+	if NeedsGeocoding(loc) {
+		return ""
+	}
+	key := cacheKey(loc)
+	if loc.Coastal {
+		key += ",coastal"
+	}
+	if loc.Provider != "" {
+		key += "," + loc.Provider
+	}
+	return key
+}
+
+// dispatchOrder returns group indexes with higher priorities first, keeping input order within a priority
+func dispatchOrder(groups []locationGroup) []int {
+	order := make([]int, len(groups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return groups[order[a]].priority > groups[order[b]].priority
+	})
+	return order
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingProvider wraps stubProvider and counts fetches
+type countingProvider struct {
+	stubProvider
+	calls atomic.Int32
+}
+
+func (c *countingProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	c.calls.Add(1)
+	return c.stubProvider.Fetch(ctx, loc)
+}
+
+// TestGroupLocations tests which locations are merged into one request
+func TestGroupLocations(t *testing.T) {
+	locations := []Location{
+		{Name: "Oslo", Lat: 59.91, Lon: 10.75},
+		{Name: "Kristiania", Lat: 59.910001, Lon: 10.750002}, // Same after rounding to 4 decimals
+		{Name: "Oslo harbour", Lat: 59.91, Lon: 10.75, Coastal: true},
+		{Name: "Holmenkollen", Lat: 59.91, Lon: 10.75, Alt: 300},
+		{Name: "Bergen"}, // Name-only, resolved later
+		{Name: "Bergen"},
+		{Name: "Capital", Lat: 59.91, Lon: 10.75},
+	}
+
+	groups := groupLocations(locations, 0)
+	if len(groups) != 5 {
+		t.Fatalf("Expected 5 groups, got %d: %+v", len(groups), groups)
+	}
+	if got := groups[0].indexes; len(got) != 3 || got[0] != 0 || got[1] != 1 || got[2] != 6 {
+		t.Errorf("Expected Oslo group to hold entries 0, 1 and 6, got %v", got)
+	}
+	if groups[0].location.Name != "Oslo" {
+		t.Errorf("Expected the first entry to represent the group, got %q", groups[0].location.Name)
+	}
+}
+
+// TestGroupTimeout tests that a group gets the longest timeout of its entries, counting the run's
+// timeout for entries without their own
+func TestGroupTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts []float64 // Timeout of each entry in seconds (0 = the run's)
+		fallback time.Duration
+		want     time.Duration
+	}{
+		{name: "Run timeout", timeouts: []float64{0, 0}, fallback: time.Minute, want: time.Minute},
+		{name: "Short override and run timeout", timeouts: []float64{5, 0}, fallback: time.Minute, want: time.Minute},
+		{name: "Run timeout and short override", timeouts: []float64{0, 5}, fallback: time.Minute, want: time.Minute},
+		{name: "Long override", timeouts: []float64{120, 0}, fallback: time.Minute, want: 2 * time.Minute},
+		{name: "Overrides only", timeouts: []float64{5, 10}, fallback: time.Minute, want: 10 * time.Second},
+		{name: "No run timeout", timeouts: []float64{5, 0}, fallback: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var locations []Location
+			for _, timeout := range tt.timeouts {
+				locations = append(locations, Location{Name: "Oslo", Lat: 59.91, Lon: 10.75, Timeout: timeout})
+			}
+			groups := groupLocations(locations, tt.fallback)
+			if len(groups) != 1 || groups[0].timeout != tt.want {
+				t.Errorf("Expected one group with a timeout of %v, got %+v", tt.want, groups)
+			}
+		})
+	}
+}
+
+// TestCollectDeduplicatesLocations tests that duplicates share one fetch and keep their own labels
+func TestCollectDeduplicatesLocations(t *testing.T) {
+	provider := &countingProvider{}
+	locations := []Location{
+		{Name: "Oslo", Lat: 59.91, Lon: 10.75},
+		{Name: "Bergen", Lat: 60.39, Lon: 5.32},
+		{Name: "Kristiania", Lat: 59.91, Lon: 10.75},
+	}
+
+	var callbacks int
+	results := CollectEach(context.Background(), provider, locations, func(WeatherResult) { callbacks++ })
+
+	if calls := provider.calls.Load(); calls != 2 {
+		t.Errorf("Expected 2 fetches for 3 locations, got %d", calls)
+	}
+	if callbacks != len(locations) {
+		t.Errorf("Expected a callback per location, got %d", callbacks)
+	}
+	for i, result := range results {
+		if result.Location.Name != locations[i].Name || !result.Success {
+			t.Errorf("Result %d: expected successful %q, got %+v", i, locations[i].Name, result)
+		}
+	}
+	if results[2].CurrentWeather != results[0].CurrentWeather {
+		t.Errorf("Expected the duplicate to share Oslo's weather, got %+v", results[2].CurrentWeather)
+	}
+}
@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for the classes of collection failure. Failures returned by providers
+// match one of these with errors.Is, and WeatherResult.ErrorCode carries the same class
+// for consumers that only see the JSON output.
+var (
+	ErrTimeout        = errors.New("request timed out")
+	ErrRateLimited    = errors.New("rate limited by the API")
+	ErrBadCoordinates = errors.New("invalid coordinates")
+	ErrDecode         = errors.New("could not decode API response")
+	ErrNoData         = errors.New("no weather data in API response")
+)
+
+// Error codes reported in WeatherResult.ErrorCode
+const (
+	ErrorCodeTimeout        = "timeout"
+	ErrorCodeRateLimited    = "rate_limited"
+	ErrorCodeBadCoordinates = "bad_coordinates"
+	ErrorCodeDecode         = "decode"
+	ErrorCodeNoData         = "no_data"
+	ErrorCodeCircuitOpen    = "circuit_open"
+	ErrorCodeCancelled      = "cancelled"
+	ErrorCodeUnknown        = "unknown" // Any other failure (connection errors, unexpected statuses, ...)
+)
+
+// errorCodes maps each sentinel to its error code
+var errorCodes = map[error]string{
+	ErrTimeout:        ErrorCodeTimeout,
+	ErrRateLimited:    ErrorCodeRateLimited,
+	ErrBadCoordinates: ErrorCodeBadCoordinates,
+	ErrDecode:         ErrorCodeDecode,
+	ErrNoData:         ErrorCodeNoData,
+	ErrCircuitOpen:    ErrorCodeCircuitOpen,
+}
+
+// CollectionError is a collection failure tagged with its class. Its message is the
+// underlying error's, so classifying an error does not change what users see.
+type CollectionError struct {
+	Code string // One of the ErrorCode constants
+	Err  error
+}
+
+// Error returns the underlying error message
+func (e *CollectionError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *CollectionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel for this error's class
+func (e *CollectionError) Is(target error) bool {
+	code, ok := errorCodes[target]
+	return ok && code == e.Code
+}
+
+// classify tags err with the class of sentinel
+func classify(sentinel error, err error) error {
+	return &CollectionError{Code: errorCodes[sentinel], Err: err}
+}
+
+// ErrorCode returns the class of a collection error as one of the ErrorCode constants
+func ErrorCode(err error) string {
+	var collErr *CollectionError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &collErr):
+		return collErr.Code
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorCodeCircuitOpen
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCancelled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCodeTimeout
+	}
+	return ErrorCodeUnknown
+}
+
+// requestError classifies a failed HTTP round trip (timeouts, cancellation, connection errors)
+func requestError(err error) error {
+	wrapped := fmt.Errorf("HTTP request failed: %w", err)
+	if code := ErrorCode(err); code == ErrorCodeTimeout || code == ErrorCodeCancelled {
+		return &CollectionError{Code: code, Err: wrapped}
+	}
+	return wrapped
+}
+
+// statusError classifies a non-200 API response. Throttling statuses mean we are rate limited,
+// and 400 is what met.no and Open-Meteo answer for coordinates they cannot serve.
+func statusError(resp *http.Response) error {
+	err := fmt.Errorf("API returned status %d", resp.StatusCode)
+	if _, throttled := throttleDelay(resp, time.Now()); throttled {
+		return classify(ErrRateLimited, err)
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		return classify(ErrBadCoordinates, err)
+	}
+	return err
+}
+
+// checkCoordinates rejects coordinates outside the valid latitude/longitude range before any request is made
+func checkCoordinates(loc Location) error {
+	if loc.Lat < -90 || loc.Lat > 90 || loc.Lon < -180 || loc.Lon > 180 {
+		return classify(ErrBadCoordinates, fmt.Errorf("invalid coordinates %.4f, %.4f for %q", loc.Lat, loc.Lon, loc.Name))
+	}
+	return nil
+}
+
+// failedResult builds the result reported when collecting a location fails with err
+func failedResult(loc Location, err error) WeatherResult {
+	return WeatherResult{
+		SchemaVersion: SchemaVersion,
+		Location:      loc,
+		Success:       false,
+		Error:         err.Error(),
+		ErrorCode:     ErrorCode(err),
+	}
+}
+
+// Err returns the failure of an unsuccessful result as a CollectionError (nil on success),
+// so callers holding only the result can still use errors.Is with the sentinel errors
+func (r WeatherResult) Err() error {
+	if r.Success {
+		return nil
+	}
+	code := r.ErrorCode
+	if code == "" {
+		code = ErrorCodeUnknown
+	}
+	return &CollectionError{Code: code, Err: errors.New(r.Error)}
+}
@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestErrorCode tests classification of sentinel, wrapped and context errors
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{classify(ErrDecode, errors.New("bad json")), ErrorCodeDecode},
+		{fmt.Errorf("fetch: %w", classify(ErrNoData, errors.New("empty"))), ErrorCodeNoData},
+		{ErrCircuitOpen, ErrorCodeCircuitOpen},
+		{context.Canceled, ErrorCodeCancelled},
+		{fmt.Errorf("Collection cancelled: %w", context.DeadlineExceeded), ErrorCodeTimeout},
+		{errors.New("connection refused"), ErrorCodeUnknown},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.want {
+			t.Errorf("ErrorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestCollectionErrorIs tests that classified errors match their sentinel and keep their message
+func TestCollectionErrorIs(t *testing.T) {
+	err := classify(ErrRateLimited, errors.New("API returned status 429"))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("Expected errors.Is to match ErrRateLimited")
+	}
+	if errors.Is(err, ErrTimeout) {
+		t.Error("Expected errors.Is not to match another sentinel")
+	}
+	if err.Error() != "API returned status 429" {
+		t.Errorf("Expected the underlying message, got %q", err.Error())
+	}
+
+	result := failedResult(Location{Name: "Oslo"}, err)
+	if result.ErrorCode != ErrorCodeRateLimited || !errors.Is(result.Err(), ErrRateLimited) {
+		t.Errorf("Expected the result to keep the error class, got %q / %v", result.ErrorCode, result.Err())
+	}
+	if (WeatherResult{Success: true}).Err() != nil {
+		t.Error("Expected no error for a successful result")
+	}
+}
+
+// TestStatusErrorClasses tests that throttling and bad-request statuses are classified
+func TestStatusErrorClasses(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, ErrBadCoordinates},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		if err := statusError(resp); !errors.Is(err, tt.want) {
+			t.Errorf("Status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+	}
+	if code := ErrorCode(statusError(&http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}})); code != ErrorCodeUnknown {
+		t.Errorf("Expected 502 to be unclassified, got %q", code)
+	}
+}
+
+// TestFetchRejectsBadCoordinates tests that out-of-range coordinates fail without a request
+func TestFetchRejectsBadCoordinates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for invalid coordinates")
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 0)
+
+	result := FetchWeatherForLocation(context.Background(), Location{Name: "Nowhere", Lat: 999, Lon: 999})
+
+	if result.Success || result.ErrorCode != ErrorCodeBadCoordinates {
+		t.Errorf("Expected a bad_coordinates failure, got %+v", result)
+	}
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"weather-collector/config"
+	"weathermodels/fileio"
+)
+
+// ProviderMock is the registered name of the offline provider that replays recorded met.no responses
+const ProviderMock = "mock"
+
+// defaultFixture answers any query for an endpoint that has no fixture recorded for that exact query
+const defaultFixture = "default.json"
+
+// unsafeFixtureChars matches query characters that are awkward in file names
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9._=-]`)
+
+// MockProvider is the met.no provider served from a fixtures directory instead of the network,
+// for tests and offline development. Fixtures are raw response bodies laid out as
+// <dir>/<host>/<path>/<query>.json, which is what the collector's -record flag writes.
+type MockProvider struct {
+	*MetNoProvider
+}
+
+// NewMockProvider creates a provider that replays fixtures from cfg.API.FixturesDir
+func NewMockProvider(cfg *config.Config) *MockProvider {
+	provider := NewMetNoProvider(cfg)
+	provider.Cache = nil // Fixtures never change, so conditional requests are pointless
+	return &MockProvider{MetNoProvider: provider}
+}
+
+// Name returns the provider name
+func (p *MockProvider) Name() string {
+	return ProviderMock
+}
+
+// fixturePath returns where the response for u is stored under dir
+func fixturePath(dir string, u *url.URL) string {
+	name := strings.TrimSuffix(defaultFixture, ".json")
+	if query := u.Query(); len(query) > 0 {
+		name = unsafeFixtureChars.ReplaceAllString(strings.ReplaceAll(query.Encode(), "&", "_"), "_")
+	}
+	return filepath.Join(dir, u.Host, filepath.FromSlash(strings.Trim(u.Path, "/")), name+".json")
+}
+
+// fixtureTransport answers requests from recorded fixtures without touching the network
+type fixtureTransport struct {
+	dir string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := fixturePath(t.dir, req.URL)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = os.ReadFile(filepath.Join(filepath.Dir(path), defaultFixture))
+	}
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no fixture %s (record one with -record)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// recordingTransport saves every successful response body as a fixture for the mock provider
+type recordingTransport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	// A failed recording must not fail the collection itself
+	path := fixturePath(t.dir, req.URL)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		slog.Warn("Failed to record fixture", "path", path, "error", err)
+	} else if err := fileio.WriteFileAtomic(path, data, 0644); err != nil {
+		slog.Warn("Failed to record fixture", "path", path, "error", err)
+	} else {
+		slog.Debug("Recorded fixture", "url", req.URL.String(), "path", path)
+	}
+	return resp, nil
+}
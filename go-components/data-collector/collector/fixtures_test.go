@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFixturePath tests that fixtures are laid out by host, path and sorted query
+func TestFixturePath(t *testing.T) {
+	u, _ := url.Parse("https://api.met.no/weatherapi/locationforecast/2.0/complete?lon=10.7500&lat=59.9100&altitude=23")
+	want := filepath.Join("fixtures", "api.met.no", "weatherapi", "locationforecast", "2.0", "complete",
+		"altitude=23_lat=59.9100_lon=10.7500.json")
+	if got := fixturePath("fixtures", u); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	u, _ = url.Parse("https://api.met.no/weatherapi/metalerts/2.0/current.json")
+	want = filepath.Join("fixtures", "api.met.no", "weatherapi", "metalerts", "2.0", "current.json", defaultFixture)
+	if got := fixturePath("fixtures", u); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+// TestRecordAndReplay tests that a recorded response is replayed by the mock provider without the network
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	dir := t.TempDir()
+	loc := Location{Name: "Oslo", Lat: 59.91, Lon: 10.75}
+
+	recorder := &MetNoProvider{
+		BaseURL: server.URL + "/complete",
+		client:  &http.Client{Transport: &recordingTransport{base: http.DefaultTransport, dir: dir}},
+	}
+	recorded, err := recorder.Fetch(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("Recording fetch failed: %v", err)
+	}
+	server.Close()
+
+	replayer := &MockProvider{MetNoProvider: &MetNoProvider{
+		BaseURL: recorder.BaseURL,
+		client:  &http.Client{Transport: &fixtureTransport{dir: dir}},
+	}}
+	replayed, err := replayer.Fetch(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if replayed.CurrentWeather != recorded.CurrentWeather {
+		t.Errorf("Expected replayed weather %+v, got %+v", recorded.CurrentWeather, replayed.CurrentWeather)
+	}
+
+	// Other coordinates have no fixture until a default is recorded for the endpoint
+	other := Location{Name: "Bergen", Lat: 60.39, Lon: 5.32}
+	if result, err := replayer.Fetch(context.Background(), other); err == nil || !strings.Contains(result.Error, "no fixture") {
+		t.Errorf("Expected a missing fixture error, got %v", err)
+	}
+
+	fixture := fixturePath(dir, mustParseURL(t, recorder.BaseURL))
+	if err := os.WriteFile(fixture, []byte(sampleAPIResponse), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := replayer.Fetch(context.Background(), other); err != nil {
+		t.Errorf("Expected the default fixture to answer any query, got %v", err)
+	}
+}
+
+// mustParseURL parses a URL or fails the test
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
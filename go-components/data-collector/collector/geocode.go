@@ -0,0 +1,250 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"weather-collector/config"
+	"weathermodels/fileio"
+)
+
+// Geocoder resolves a place name to coordinates
+type Geocoder interface {
+	Geocode(ctx context.Context, name string) (Location, error)
+}
+
+// NeedsGeocoding reports whether a location was given by name only
+func NeedsGeocoding(loc Location) bool {
+	return loc.Name != "" && loc.Lat == 0 && loc.Lon == 0
+}
+
+// GeocodeEntry is a cached name resolution
+type GeocodeEntry struct {
+	Lat         float64   `json:"lat"`
+	Lon         float64   `json:"lon"`
+	DisplayName string    `json:"display_name,omitempty"` // Full name reported by the geocoder
+	ResolvedAt  time.Time `json:"resolved_at"`
+}
+
+// GeocodeCache remembers resolved names so each place is only looked up once.
+// When Path is set, the cache is persisted to that JSON file between runs.
+type GeocodeCache struct {
+	Path string // JSON file holding all entries ("" keeps the cache in memory only)
+
+	mu      sync.Mutex
+	entries map[string]GeocodeEntry
+	loaded  bool
+}
+
+// NewGeocodeCache creates a geocode cache persisted at path ("" for in-memory only)
+func NewGeocodeCache(path string) *GeocodeCache {
+	return &GeocodeCache{Path: path, entries: make(map[string]GeocodeEntry)}
+}
+
+// sharedGeocodeCaches holds one cache per file reused by every geocoder in the process
+var (
+	sharedGeocodeCachesMu sync.Mutex
+	sharedGeocodeCaches   = map[string]*GeocodeCache{}
+)
+
+// sharedGeocodeCache returns the process-wide geocode cache for a cache file
+func sharedGeocodeCache(path string) *GeocodeCache {
+	sharedGeocodeCachesMu.Lock()
+	defer sharedGeocodeCachesMu.Unlock()
+
+	cache, ok := sharedGeocodeCaches[path]
+	if !ok {
+		cache = NewGeocodeCache(path)
+		sharedGeocodeCaches[path] = cache
+	}
+	return cache
+}
+
+// geocodeKey normalizes a place name so lookups ignore case and surrounding whitespace
+func geocodeKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Get returns the cached resolution for a place name
+func (c *GeocodeCache) Get(name string) (GeocodeEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.entries[geocodeKey(name)]
+	return entry, ok
+}
+
+// Put stores a resolution, persisting the cache file when one is configured
+func (c *GeocodeCache) Put(name string, entry GeocodeEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	c.entries[geocodeKey(name)] = entry
+	if c.Path == "" {
+		return
+	}
+	if err := c.persist(); err != nil {
+		slog.Warn("Could not persist geocode cache", "path", c.Path, "error", err)
+	}
+}
+
+// load reads the cache file once; a missing file simply starts an empty cache
+func (c *GeocodeCache) load() {
+	if c.loaded || c.Path == "" {
+		return
+	}
+	c.loaded = true
+
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		slog.Warn("Ignoring corrupt geocode cache", "path", c.Path, "error", err)
+		c.entries = make(map[string]GeocodeEntry)
+	}
+}
+
+// persist atomically rewrites the cache file with all entries
+func (c *GeocodeCache) persist() error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileio.WriteFileAtomic(c.Path, data, 0644)
+}
+
+// NominatimGeocoder resolves place names with the OpenStreetMap Nominatim search API.
+// Requests are throttled to MinInterval apart as required by Nominatim's usage policy.
+type NominatimGeocoder struct {
+	BaseURL     string
+	UserAgent   string
+	MinInterval time.Duration
+	Cache       *GeocodeCache
+	client      *http.Client
+
+	mu          sync.Mutex // Serializes lookups so the throttle holds across workers
+	lastRequest time.Time
+}
+
+// NewNominatimGeocoder creates a Nominatim geocoder from the geocoding configuration
+func NewNominatimGeocoder(cfg *config.Config) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		BaseURL:     cfg.Geocoding.BaseURL,
+		UserAgent:   cfg.API.UserAgent,
+		MinInterval: cfg.Geocoding.MinInterval,
+		Cache:       sharedGeocodeCache(cfg.Geocoding.CacheFile),
+		client: &http.Client{
+			Timeout: cfg.API.Timeout,
+		},
+	}
+}
+
+// nominatimResult is a single match from the Nominatim search API (coordinates are strings)
+type nominatimResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+}
+
+// Geocode returns the location for a place name, using the cache when possible
+func (g *NominatimGeocoder) Geocode(ctx context.Context, name string) (Location, error) {
+	if entry, ok := g.Cache.Get(name); ok {
+		return Location{Name: name, Lat: entry.Lat, Lon: entry.Lon}, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Another worker may have resolved the same name while we waited
+	if entry, ok := g.Cache.Get(name); ok {
+		return Location{Name: name, Lat: entry.Lat, Lon: entry.Lon}, nil
+	}
+
+	if err := g.throttle(ctx); err != nil {
+		return Location{Name: name}, err
+	}
+
+	query := url.Values{}
+	query.Set("q", name)
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+
+	var matches []nominatimResult
+	_, err := getJSON(ctx, g.client, g.BaseURL+"?"+query.Encode(), g.UserAgent, &matches)
+	g.lastRequest = time.Now()
+	if err != nil {
+		return Location{Name: name}, err
+	}
+	if len(matches) == 0 {
+		return Location{Name: name}, errors.New("no matching place found")
+	}
+
+	lat, latErr := strconv.ParseFloat(matches[0].Lat, 64)
+	lon, lonErr := strconv.ParseFloat(matches[0].Lon, 64)
+	if latErr != nil || lonErr != nil {
+		return Location{Name: name}, fmt.Errorf("invalid coordinates %q, %q", matches[0].Lat, matches[0].Lon)
+	}
+
+	g.Cache.Put(name, GeocodeEntry{
+		Lat:         lat,
+		Lon:         lon,
+		DisplayName: matches[0].DisplayName,
+		ResolvedAt:  time.Now(),
+	})
+	slog.Info("Geocoded location", "location", name, "lat", lat, "lon", lon, "match", matches[0].DisplayName)
+	return Location{Name: name, Lat: lat, Lon: lon}, nil
+}
+
+// throttle waits until MinInterval has passed since the previous request
+func (g *NominatimGeocoder) throttle(ctx context.Context) error {
+	if g.MinInterval <= 0 || g.lastRequest.IsZero() {
+		return nil
+	}
+	wait := time.Until(g.lastRequest.Add(g.MinInterval))
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// geocodingProvider resolves name-only locations before delegating to the wrapped provider
+type geocodingProvider struct {
+	Provider
+	geocoder Geocoder
+}
+
+// Fetch geocodes the location if it has no coordinates, then fetches weather for it. Only the
+// coordinates are taken from the geocoder; the name, altitude, coastal flag and overrides are the
+// location's own.
+func (p *geocodingProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	if NeedsGeocoding(loc) {
+		resolved, err := p.geocoder.Geocode(ctx, loc.Name)
+		if err != nil {
+			return WeatherResult{Location: loc}, fmt.Errorf("Geocoding failed for %q: %v", loc.Name, err)
+		}
+		loc.Lat, loc.Lon = resolved.Lat, resolved.Lon
+	}
+	return p.Provider.Fetch(ctx, loc)
+}
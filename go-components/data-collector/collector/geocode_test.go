@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"weather-collector/config"
+)
+
+// newTestGeocoder creates a Nominatim geocoder pointed at a test server with the given cache file
+func newTestGeocoder(serverURL, cacheFile string) *NominatimGeocoder {
+	cfg := *config.Get()
+	cfg.Geocoding.BaseURL = serverURL
+	cfg.Geocoding.MinInterval = 0
+	geocoder := NewNominatimGeocoder(&cfg)
+	geocoder.Cache = NewGeocodeCache(cacheFile)
+	return geocoder
+}
+
+// TestNominatimGeocode tests name resolution and that results are cached on disk between runs
+func TestNominatimGeocode(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("q") != "Oslo, Norway" || r.URL.Query().Get("format") != "jsonv2" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"lat": "59.9133", "lon": "10.7389", "display_name": "Oslo, Norge"}]`))
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "geocode.json")
+	geocoder := newTestGeocoder(server.URL, cacheFile)
+
+	loc, err := geocoder.Geocode(context.Background(), "Oslo, Norway")
+	if err != nil {
+		t.Fatalf("Geocode failed: %v", err)
+	}
+	if loc.Name != "Oslo, Norway" || loc.Lat != 59.9133 || loc.Lon != 10.7389 {
+		t.Errorf("Unexpected location: %+v", loc)
+	}
+
+	// A fresh geocoder sharing the cache file should not hit the API again
+	again := newTestGeocoder(server.URL, cacheFile)
+	if loc, err := again.Geocode(context.Background(), "  oslo, norway "); err != nil || loc.Lat != 59.9133 {
+		t.Errorf("Expected cached resolution, got %+v (%v)", loc, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 geocoding request, got %d", requests)
+	}
+}
+
+// TestNominatimGeocodeNoMatch tests that an empty result is reported as an error
+func TestNominatimGeocodeNoMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	geocoder := newTestGeocoder(server.URL, "")
+	if _, err := geocoder.Geocode(context.Background(), "Atlantis"); err == nil {
+		t.Error("Expected an error for an unknown place")
+	}
+}
+
+// stubGeocoder resolves every name to fixed coordinates, failing for "Nowhere"
+type stubGeocoder struct {
+	calls int
+}
+
+func (g *stubGeocoder) Geocode(ctx context.Context, name string) (Location, error) {
+	g.calls++
+	if name == "Nowhere" {
+		return Location{Name: name}, context.DeadlineExceeded
+	}
+	return Location{Name: name, Lat: 48.85, Lon: 2.35}, nil
+}
+
+// TestGeocodingProvider tests that only name-only locations are resolved before fetching
+func TestGeocodingProvider(t *testing.T) {
+	geocoder := &stubGeocoder{}
+	provider := &geocodingProvider{Provider: &stubProvider{}, geocoder: geocoder}
+
+	result := FetchWithProvider(context.Background(), provider, Location{Name: "Paris"})
+	if !result.Success || result.Location.Lat != 48.85 || result.Location.Lon != 2.35 {
+		t.Errorf("Expected Paris to be geocoded, got %+v", result)
+	}
+
+	result = FetchWithProvider(context.Background(), provider, Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if !result.Success || result.Location.Lat != 59.91 {
+		t.Errorf("Expected explicit coordinates to be kept, got %+v", result)
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("Expected 1 geocoding call, got %d", geocoder.calls)
+	}
+
+	tagged := Location{Name: "Brest", Alt: 100, Coastal: true, Provider: "stub", Timeout: 5, Priority: 2}
+	result = FetchWithProvider(context.Background(), provider, tagged)
+	want := tagged
+	want.Lat, want.Lon = 48.85, 2.35
+	if !result.Success || result.Location != want {
+		t.Errorf("Expected only the coordinates to be filled in, got %+v", result.Location)
+	}
+
+	result = FetchWithProvider(context.Background(), provider, Location{Name: "Nowhere"})
+	if result.Success || !strings.Contains(result.Error, "Geocoding failed") {
+		t.Errorf("Expected geocoding failure to be reported, got %+v", result)
+	}
+}
@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"weather-collector/config"
+)
+
+// horizonProvider trims the forecast of the wrapped provider to the configured horizon and resolution,
+// so outputs only carry the hours users asked for instead of met.no's full multi-day timeseries
+type horizonProvider struct {
+	Provider
+	hours      int           // Keep forecast points at most this many hours after the current reading (0 = all)
+	resolution time.Duration // Keep only forecast points on this UTC step, e.g. 00/06/12/18 for 6h (0 = native)
+}
+
+// newHorizonProvider wraps provider when the collection settings limit the forecast, and returns it unchanged otherwise
+func newHorizonProvider(provider Provider, cfg config.CollectionConfig) Provider {
+	if cfg.ForecastHours == 0 && cfg.Resolution == 0 {
+		return provider
+	}
+	return &horizonProvider{Provider: provider, hours: cfg.ForecastHours, resolution: cfg.Resolution}
+}
+
+// Fetch fetches weather for a location, then drops forecast points outside the horizon or off the resolution step
+func (p *horizonProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	result, err := p.Provider.Fetch(ctx, loc)
+	if err != nil {
+		return result, err
+	}
+	result.Forecast = p.trim(result.CurrentWeather.Timestamp, result.Forecast)
+	return result, nil
+}
+
+// trim filters forecast points that follow the current reading. Points before it (Open-Meteo history)
+// and points without a timestamp are kept as they are.
+func (p *horizonProvider) trim(now time.Time, forecast []WeatherPoint) []WeatherPoint {
+	if now.IsZero() {
+		return forecast
+	}
+	cutoff := now.Add(time.Duration(p.hours) * time.Hour)
+
+	trimmed := forecast[:0]
+	for _, point := range forecast {
+		t := point.Timestamp
+		if t.After(now) {
+			if p.hours > 0 && t.After(cutoff) {
+				continue
+			}
+			if p.resolution > 0 && !t.Truncate(p.resolution).Equal(t) {
+				continue
+			}
+		}
+		trimmed = append(trimmed, point)
+	}
+	return trimmed
+}
+
+// forecastHoursLimit combines a provider's own forecast limit with collection.forecast_hours,
+// returning the tighter of the two (0 means no limit)
+func forecastHoursLimit(limit int, cfg config.CollectionConfig) int {
+	if cfg.ForecastHours > 0 && (limit == 0 || cfg.ForecastHours < limit) {
+		return cfg.ForecastHours
+	}
+	return limit
+}
@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// hourlyProvider returns a result with a current reading at 10:00 UTC and hourly forecast points after it
+type hourlyProvider struct {
+	hours int
+}
+
+func (h *hourlyProvider) Name() string { return "hourly" }
+
+func (h *hourlyProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	start := time.Date(2025, 10, 3, 10, 0, 0, 0, time.UTC)
+	result := WeatherResult{
+		Location:       loc,
+		CurrentWeather: WeatherPoint{Timestamp: start},
+		Success:        true,
+	}
+	for i := 1; i <= h.hours; i++ {
+		result.Forecast = append(result.Forecast, WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour)})
+	}
+	return result, nil
+}
+
+// TestHorizonProvider tests trimming the forecast to a horizon and resolution
+func TestHorizonProvider(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.CollectionConfig
+		wantPoints int
+		wantLast   string
+	}{
+		{"full forecast", config.CollectionConfig{}, 72, "2025-10-06T10:00:00Z"},
+		{"24 hours", config.CollectionConfig{ForecastHours: 24}, 24, "2025-10-04T10:00:00Z"},
+		{"48 hours at 6h", config.CollectionConfig{ForecastHours: 48, Resolution: 6 * time.Hour}, 8, "2025-10-05T06:00:00Z"},
+		{"1h resolution only", config.CollectionConfig{Resolution: time.Hour}, 72, "2025-10-06T10:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newHorizonProvider(&hourlyProvider{hours: 72}, tt.cfg)
+			result, err := provider.Fetch(context.Background(), Location{Name: "Oslo"})
+			if err != nil {
+				t.Fatalf("Fetch failed: %v", err)
+			}
+			if len(result.Forecast) != tt.wantPoints {
+				t.Fatalf("Expected %d forecast points, got %d", tt.wantPoints, len(result.Forecast))
+			}
+			if last := result.Forecast[len(result.Forecast)-1].Timestamp; last.Format(time.RFC3339) != tt.wantLast {
+				t.Errorf("Expected last point at %s, got %s", tt.wantLast, last)
+			}
+		})
+	}
+}
+
+// TestHorizonProviderKeepsHistory tests that points before the current reading are not trimmed
+func TestHorizonProviderKeepsHistory(t *testing.T) {
+	provider := &horizonProvider{hours: 1, resolution: 6 * time.Hour}
+	forecast := []WeatherPoint{
+		{Timestamp: time.Date(2025, 10, 3, 7, 0, 0, 0, time.UTC)},  // History, off the 6h step
+		{Timestamp: time.Date(2025, 10, 3, 11, 0, 0, 0, time.UTC)}, // Within the horizon, off the 6h step
+		{Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)}, // Beyond the horizon
+	}
+	trimmed := provider.trim(time.Date(2025, 10, 3, 10, 0, 0, 0, time.UTC), forecast)
+	if len(trimmed) != 1 || !trimmed[0].Timestamp.Equal(time.Date(2025, 10, 3, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected only the history point, got %+v", trimmed)
+	}
+}
+
+// TestForecastHoursLimit tests combining the API decode limit with the collection horizon
+func TestForecastHoursLimit(t *testing.T) {
+	tests := []struct {
+		limit, hours, want int
+	}{
+		{0, 0, 0},
+		{0, 24, 24},
+		{48, 0, 48},
+		{48, 24, 24},
+		{12, 24, 12},
+	}
+	for _, tt := range tests {
+		if got := forecastHoursLimit(tt.limit, config.CollectionConfig{ForecastHours: tt.hours}); got != tt.want {
+			t.Errorf("forecastHoursLimit(%d, %d) = %d, want %d", tt.limit, tt.hours, got, tt.want)
+		}
+	}
+}
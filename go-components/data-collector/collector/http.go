@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"weather-collector/config"
+)
+
+// newAPIClient creates an HTTP client for weather APIs that honors the configured per-host
+// rate limit and backs off when a host throttles us. The mock provider's client reads fixtures
+// instead, and with api.record set live responses are saved as fixtures.
+func newAPIClient(cfg *config.Config) *http.Client {
+	var transport http.RoundTripper = &rateLimitedTransport{base: http.DefaultTransport, perSecond: cfg.API.RateLimit}
+	switch {
+	case cfg.API.Provider == ProviderMock:
+		transport = &fixtureTransport{dir: cfg.API.FixturesDir}
+	case cfg.API.Record:
+		transport = &recordingTransport{base: transport, dir: cfg.API.FixturesDir}
+	}
+	return &http.Client{
+		Timeout:   cfg.API.Timeout,
+		Transport: transport,
+	}
+}
+
+// getJSON performs a GET request and decodes a JSON response body into v.
+// It reports whether a failure is transient and worth retrying.
+func getJSON(ctx context.Context, client *http.Client, url, userAgent string, v any) (bool, error) {
+	req, err := newGetRequest(ctx, url, userAgent)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return isRetryableError(err), requestError(err)
+	}
+	defer resp.Body.Close()
+	recordStatus(ctx, resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		return isRetryableResponse(resp), statusError(resp)
+	}
+
+	if err := decodeJSON(resp.Body, v); err != nil {
+		return isRetryableError(err), classify(ErrDecode, fmt.Errorf("Failed to parse JSON: %v", err))
+	}
+
+	return false, nil
+}
+
+// statusRecorder captures the HTTP status of the last API response made with its context
+type statusRecorder struct {
+	code int
+}
+
+// statusRecorderKey is the context key for a statusRecorder
+type statusRecorderKey struct{}
+
+// withStatusRecorder returns a context whose API responses are recorded in the returned recorder
+func withStatusRecorder(ctx context.Context) (context.Context, *statusRecorder) {
+	recorder := &statusRecorder{}
+	return context.WithValue(ctx, statusRecorderKey{}, recorder), recorder
+}
+
+// recordStatus stores an API response status in the context's recorder, if it has one
+func recordStatus(ctx context.Context, code int) {
+	if recorder, ok := ctx.Value(statusRecorderKey{}).(*statusRecorder); ok {
+		recorder.code = code
+	}
+}
+
+// newGetRequest creates a GET request with the User-Agent header set
+// (required by met.no, polite for everyone else)
+func newGetRequest(ctx context.Context, url, userAgent string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	return req, nil
+}
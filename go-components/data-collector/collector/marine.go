@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"weather-collector/config"
+)
+
+// OceanForecastClient fetches wave, sea temperature and current forecasts from the met.no oceanforecast API.
+// Oceanforecast only covers Norwegian and nearby waters and requires a point at sea.
+type OceanForecastClient struct {
+	URL       string
+	UserAgent string
+	client    *http.Client
+}
+
+// NewOceanForecastClient creates an oceanforecast client from the API configuration
+func NewOceanForecastClient(cfg *config.Config) *OceanForecastClient {
+	return &OceanForecastClient{
+		URL:       cfg.API.Ocean.URL,
+		UserAgent: cfg.API.UserAgent,
+		client:    newAPIClient(cfg),
+	}
+}
+
+// oceanForecastResponse represents the met.no oceanforecast/2.0/complete response structure
+type oceanForecastResponse struct {
+	Properties struct {
+		Timeseries []struct {
+			Time time.Time `json:"time"`
+			Data struct {
+				Instant struct {
+					Details struct {
+						WaveHeight          float64 `json:"sea_surface_wave_height"`
+						WaveFromDirection   float64 `json:"sea_surface_wave_from_direction"`
+						SeaWaterTemperature float64 `json:"sea_water_temperature"`
+						SeaWaterSpeed       float64 `json:"sea_water_speed"`
+						SeaWaterToDirection float64 `json:"sea_water_to_direction"`
+					} `json:"details"`
+				} `json:"instant"`
+			} `json:"data"`
+		} `json:"timeseries"`
+	} `json:"properties"`
+}
+
+// Marine returns the ocean forecast for a coastal location
+func (c *OceanForecastClient) Marine(ctx context.Context, loc Location) ([]MarinePoint, error) {
+	query := url.Values{}
+	query.Set("lat", fmt.Sprintf("%.4f", loc.Lat))
+	query.Set("lon", fmt.Sprintf("%.4f", loc.Lon))
+
+	var resp oceanForecastResponse
+	if _, err := getJSON(ctx, c.client, c.URL+"?"+query.Encode(), c.UserAgent, &resp); err != nil {
+		return nil, err
+	}
+
+	points := make([]MarinePoint, 0, len(resp.Properties.Timeseries))
+	for _, entry := range resp.Properties.Timeseries {
+		details := entry.Data.Instant.Details
+		points = append(points, MarinePoint{
+			Timestamp:        entry.Time,
+			WaveHeight:       details.WaveHeight,
+			WaveDirection:    details.WaveFromDirection,
+			SeaTemperature:   details.SeaWaterTemperature,
+			CurrentSpeed:     details.SeaWaterSpeed,
+			CurrentDirection: details.SeaWaterToDirection,
+		})
+	}
+	return points, nil
+}
+
+// marineProvider attaches an ocean forecast to successful results for locations flagged as coastal.
+// Like alerts, the marine forecast is best-effort and never fails the weather result.
+type marineProvider struct {
+	Provider
+	ocean *OceanForecastClient
+}
+
+// Fetch fetches weather for a location, then adds the marine forecast when the location is coastal
+func (p *marineProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	result, err := p.Provider.Fetch(ctx, loc)
+	if err != nil || !loc.Coastal {
+		return result, err
+	}
+
+	marine, marineErr := p.ocean.Marine(ctx, result.Location)
+	if marineErr != nil {
+		slog.Warn("Ocean forecast unavailable", "location", loc.Name, "error", marineErr)
+		return result, nil
+	}
+	result.Marine = marine
+	return result, nil
+}
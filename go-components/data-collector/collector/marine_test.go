@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"weather-collector/config"
+)
+
+// sampleOceanForecastResponse is a trimmed oceanforecast/2.0/complete payload with one reading
+const sampleOceanForecastResponse = `{
+  "type": "Feature",
+  "properties": {
+    "timeseries": [{
+      "time": "2025-10-03T12:00:00Z",
+      "data": {"instant": {"details": {
+        "sea_surface_wave_height": 1.6, "sea_surface_wave_from_direction": 240,
+        "sea_water_temperature": 12.3, "sea_water_speed": 0.2, "sea_water_to_direction": 15
+      }}}
+    }]
+  }
+}`
+
+// TestMarineProvider tests that ocean forecasts are only fetched for coastal locations
+func TestMarineProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(sampleOceanForecastResponse))
+	}))
+	defer server.Close()
+
+	cfg := *config.Get()
+	cfg.API.Ocean.URL = server.URL
+	provider := &marineProvider{Provider: &stubProvider{}, ocean: NewOceanForecastClient(&cfg)}
+
+	result := FetchWithProvider(context.Background(), provider, Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if requests != 0 || len(result.Marine) != 0 {
+		t.Errorf("Expected no ocean forecast for an inland location, got %d requests", requests)
+	}
+
+	result = FetchWithProvider(context.Background(), provider, Location{Name: "Bergen", Lat: 60.39, Lon: 5.32, Coastal: true})
+	if !result.Success || len(result.Marine) != 1 {
+		t.Fatalf("Expected a successful result with 1 marine point, got %+v", result)
+	}
+
+	point := result.Marine[0]
+	if point.WaveHeight != 1.6 || point.SeaTemperature != 12.3 || point.CurrentDirection != 15 {
+		t.Errorf("Unexpected marine point: %+v", point)
+	}
+}
@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"weather-collector/config"
+)
+
+// MetAlertsClient fetches active severe-weather warnings from the met.no MetAlerts API.
+// MetAlerts only covers Norway; elsewhere it simply returns no warnings.
+type MetAlertsClient struct {
+	URL       string
+	UserAgent string
+	Language  string
+	client    *http.Client
+}
+
+// NewMetAlertsClient creates a MetAlerts client from the API configuration
+func NewMetAlertsClient(cfg *config.Config) *MetAlertsClient {
+	return &MetAlertsClient{
+		URL:       cfg.API.MetAlerts.URL,
+		UserAgent: cfg.API.UserAgent,
+		Language:  cfg.API.MetAlerts.Language,
+		client:    newAPIClient(cfg),
+	}
+}
+
+// metAlertsResponse represents the MetAlerts GeoJSON feature collection
+type metAlertsResponse struct {
+	Features []struct {
+		Properties struct {
+			ID             string `json:"id"`
+			Event          string `json:"event"`
+			Title          string `json:"title"`
+			Area           string `json:"area"`
+			AwarenessLevel string `json:"awareness_level"` // e.g. "2; yellow; Moderate"
+			Severity       string `json:"severity"`
+			Certainty      string `json:"certainty"`
+			Description    string `json:"description"`
+			Instruction    string `json:"instruction"`
+		} `json:"properties"`
+		When struct {
+			Interval []string `json:"interval"` // [onset, ends]
+		} `json:"when"`
+	} `json:"features"`
+}
+
+// Alerts returns the warnings currently active at a location
+func (c *MetAlertsClient) Alerts(ctx context.Context, loc Location) ([]Alert, error) {
+	query := url.Values{}
+	query.Set("lat", fmt.Sprintf("%.4f", loc.Lat))
+	query.Set("lon", fmt.Sprintf("%.4f", loc.Lon))
+	if c.Language != "" {
+		query.Set("lang", c.Language)
+	}
+
+	var resp metAlertsResponse
+	if _, err := getJSON(ctx, c.client, c.URL+"?"+query.Encode(), c.UserAgent, &resp); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]Alert, 0, len(resp.Features))
+	for _, feature := range resp.Features {
+		props := feature.Properties
+		alert := Alert{
+			ID:             props.ID,
+			Event:          props.Event,
+			Title:          props.Title,
+			Area:           props.Area,
+			AwarenessLevel: awarenessColor(props.AwarenessLevel),
+			Severity:       props.Severity,
+			Certainty:      props.Certainty,
+			Description:    props.Description,
+			Instruction:    props.Instruction,
+		}
+		if len(feature.When.Interval) == 2 {
+			alert.Onset = feature.When.Interval[0]
+			alert.Ends = feature.When.Interval[1]
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// awarenessColor extracts the color from a MetAlerts awareness level such as "2; yellow; Moderate"
+func awarenessColor(level string) string {
+	parts := strings.Split(level, ";")
+	if len(parts) >= 2 {
+		return strings.TrimSpace(parts[1])
+	}
+	return strings.TrimSpace(level)
+}
+
+// alertsProvider attaches active MetAlerts warnings to successful results from the wrapped provider.
+// Alerts are best-effort: a failed lookup is logged and never fails the weather result.
+type alertsProvider struct {
+	Provider
+	alerts *MetAlertsClient
+}
+
+// Fetch fetches weather for a location, then adds any active warnings
+func (p *alertsProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	result, err := p.Provider.Fetch(ctx, loc)
+	if err != nil {
+		return result, err
+	}
+
+	alerts, alertErr := p.alerts.Alerts(ctx, result.Location)
+	if alertErr != nil {
+		slog.Warn("MetAlerts unavailable", "location", loc.Name, "error", alertErr)
+		return result, nil
+	}
+	result.Alerts = alerts
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"weather-collector/config"
+)
+
+// sampleMetAlertsResponse is a trimmed MetAlerts current.json payload with one wind warning
+const sampleMetAlertsResponse = `{
+  "type": "FeatureCollection",
+  "features": [{
+    "type": "Feature",
+    "properties": {
+      "id": "2.49.0.1.578.0.20251003.1",
+      "event": "wind",
+      "title": "Wind, yellow level, Vestland",
+      "area": "Vestland",
+      "awareness_level": "2; yellow; Moderate",
+      "severity": "Moderate",
+      "certainty": "Likely",
+      "description": "Gusts up to 25 m/s.",
+      "instruction": "Secure loose objects."
+    },
+    "when": {"interval": ["2025-10-03T06:00:00+00:00", "2025-10-03T18:00:00+00:00"]}
+  }]
+}`
+
+// newTestMetAlertsClient creates a MetAlerts client pointed at a test server
+func newTestMetAlertsClient(serverURL string) *MetAlertsClient {
+	cfg := *config.Get()
+	cfg.API.MetAlerts.URL = serverURL
+	return NewMetAlertsClient(&cfg)
+}
+
+// TestMetAlertsClient tests parsing of MetAlerts warnings
+func TestMetAlertsClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("lat") != "60.3900" || r.URL.Query().Get("lang") != "en" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(sampleMetAlertsResponse))
+	}))
+	defer server.Close()
+
+	alerts, err := newTestMetAlertsClient(server.URL).Alerts(context.Background(), Location{Name: "Bergen", Lat: 60.39, Lon: 5.32})
+	if err != nil {
+		t.Fatalf("Alerts failed: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+
+	alert := alerts[0]
+	if alert.Event != "wind" || alert.AwarenessLevel != "yellow" || alert.Severity != "Moderate" {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+	if alert.Onset != "2025-10-03T06:00:00+00:00" || alert.Ends != "2025-10-03T18:00:00+00:00" {
+		t.Errorf("Expected warning interval to be parsed, got %s - %s", alert.Onset, alert.Ends)
+	}
+}
+
+// TestAlertsProviderBestEffort tests that alerts are attached and that a failing lookup keeps the result
+func TestAlertsProviderBestEffort(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleMetAlertsResponse))
+	}))
+	defer healthy.Close()
+
+	provider := &alertsProvider{Provider: &stubProvider{}, alerts: newTestMetAlertsClient(healthy.URL)}
+	result := FetchWithProvider(context.Background(), provider, Location{Name: "Bergen", Lat: 60.39, Lon: 5.32})
+	if !result.Success || len(result.Alerts) != 1 {
+		t.Errorf("Expected a successful result with 1 alert, got %+v", result)
+	}
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	provider = &alertsProvider{Provider: &stubProvider{}, alerts: newTestMetAlertsClient(broken.URL)}
+	result = FetchWithProvider(context.Background(), provider, Location{Name: "Bergen", Lat: 60.39, Lon: 5.32})
+	if !result.Success || len(result.Alerts) != 0 {
+		t.Errorf("Expected alerts failure to be ignored, got %+v", result)
+	}
+}
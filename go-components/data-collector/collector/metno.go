@@ -0,0 +1,160 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"weather-collector/config"
+	"weathermodels"
+)
+
+// ProviderMetNo is the registered name of the met.no locationforecast provider
+const ProviderMetNo = "metno"
+
+// MetNoProvider fetches forecasts from the met.no locationforecast API.
+// The "complete" variant is used so dew point, UV index, gusts and fog are included.
+type MetNoProvider struct {
+	BaseURL          string
+	UserAgent        string
+	MaxForecastHours int // Stop decoding entries this many hours past the first (0 keeps the full timeseries)
+	Retry            RetryPolicy
+	Cache            *ResponseCache // Honors Expires / If-Modified-Since (nil disables caching)
+	client           *http.Client
+}
+
+// NewMetNoProvider creates a met.no provider from the API configuration
+func NewMetNoProvider(cfg *config.Config) *MetNoProvider {
+	return &MetNoProvider{
+		BaseURL:          cfg.API.BaseURL,
+		UserAgent:        cfg.API.UserAgent,
+		MaxForecastHours: forecastHoursLimit(cfg.API.MaxForecastHours, cfg.Collection),
+		Retry:            newRetryPolicy(cfg, cfg.API.BaseURL),
+		Cache:            sharedResponseCache(cfg),
+		client:           newAPIClient(cfg),
+	}
+}
+
+// Name returns the provider name
+func (p *MetNoProvider) Name() string {
+	return ProviderMetNo
+}
+
+// Fetch makes an HTTP request to met.no API for a single location
+// Transient failures (5xx, timeouts, connection resets) are retried with exponential backoff
+func (p *MetNoProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	if err := checkCoordinates(loc); err != nil {
+		return failedResult(loc, err), err
+	}
+
+	// Build the API URL using config
+	url := fmt.Sprintf("%s?lat=%.4f&lon=%.4f", p.BaseURL, loc.Lat, loc.Lon)
+	if loc.Alt != 0 {
+		// Altitude corrects the forecast temperature for the real terrain height
+		url += fmt.Sprintf("&altitude=%d", loc.Alt)
+	}
+
+	result := p.Retry.Do(ctx, loc, func(ctx context.Context) (WeatherResult, bool) {
+		return p.fetchOnce(ctx, url, loc)
+	})
+	if !result.Success {
+		return result, result.Err()
+	}
+	return result, nil
+}
+
+// fetchOnce performs a single API request and reports whether a failure is worth retrying
+func (p *MetNoProvider) fetchOnce(ctx context.Context, url string, loc Location) (WeatherResult, bool) {
+	// Request and parse the JSON response (User-Agent is a met.no requirement)
+	apiResp := APIResponse{MaxHours: p.MaxForecastHours}
+	fromCache, retryable, err := p.getJSON(ctx, url, loc, &apiResp)
+	if err != nil {
+		return failedResult(loc, err), retryable
+	}
+
+	// Extract weather data from timeseries entries
+	if len(apiResp.Timeseries) == 0 {
+		return failedResult(loc, classify(ErrNoData, errors.New("No weather data in API response"))), false
+	}
+
+	// Process all timeseries entries to extract current weather and forecasts
+	var currentWeather *WeatherPoint
+	var forecast []WeatherPoint
+
+	for i, entry := range apiResp.Timeseries {
+		details := entry.Data.Instant.Details
+
+		// Extract precipitation data from next_1_hours forecast if available
+		precipitationMm := 0.0
+		precipitationProb := 0.0
+		symbolCode := ""
+
+		if entry.Data.Next1Hours.Details.PrecipitationAmount > 0 {
+			precipitationMm = entry.Data.Next1Hours.Details.PrecipitationAmount
+		}
+		if entry.Data.Next1Hours.Details.ProbabilityOfPrecipitation > 0 {
+			precipitationProb = entry.Data.Next1Hours.Details.ProbabilityOfPrecipitation
+		}
+		if entry.Data.Next1Hours.Summary.SymbolCode != "" {
+			symbolCode = entry.Data.Next1Hours.Summary.SymbolCode
+		}
+
+		// Create weather point
+		weatherPoint := WeatherPoint{
+			Timestamp:                entry.Time,
+			Temperature:              details.AirTemperature,
+			Pressure:                 details.AirPressureAtSeaLevel,
+			Humidity:                 details.RelativeHumidity,
+			WindSpeed:                details.WindSpeed,
+			WindDirection:            details.WindFromDirection,
+			CloudCover:               details.CloudAreaFraction,
+			PrecipitationMm:          precipitationMm,
+			PrecipitationProbability: precipitationProb,
+			SymbolCode:               symbolCode,
+			DewPoint:                 details.DewPointTemperature,
+			UVIndex:                  details.UltravioletIndex,
+			WindGust:                 details.WindSpeedOfGust,
+			FogAreaFraction:          details.FogAreaFraction,
+		}
+
+		// First entry is current weather, rest are forecasts. The first is model output for the
+		// current hour rather than a measurement, but locationforecast has nothing closer to one:
+		// it is tagged as the observation so that the histories collected from this provider have
+		// readings for the historical analyzers, at the cost of model error passing for measured.
+		if i == 0 {
+			weatherPoint.Source = weathermodels.SourceObservation
+			currentWeather = &weatherPoint
+		} else {
+			weatherPoint.Source = weathermodels.SourceForecast
+			forecast = append(forecast, weatherPoint)
+		}
+	}
+
+	if currentWeather == nil {
+		return failedResult(loc, classify(ErrNoData, errors.New("No current weather data extracted"))), false
+	}
+
+	source := SourceAPI
+	if fromCache {
+		source = SourceCache
+	}
+
+	return WeatherResult{
+		Location:       loc,
+		CurrentWeather: *currentWeather,
+		Forecast:       forecast,
+		Success:        true,
+		Source:         source,
+	}, false
+}
+
+// getJSON requests a met.no payload, going through the response cache when one is configured.
+// It reports whether the payload came from the cache and whether a failure is worth retrying.
+func (p *MetNoProvider) getJSON(ctx context.Context, url string, loc Location, v any) (bool, bool, error) {
+	if p.Cache == nil {
+		retryable, err := getJSON(ctx, p.client, url, p.UserAgent, v)
+		return false, retryable, err
+	}
+	return getCachedJSON(ctx, p.client, url, p.UserAgent, p.Cache, cacheKey(loc), v)
+}
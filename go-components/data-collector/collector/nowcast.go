@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"weather-collector/config"
+	"weathermodels"
+)
+
+// Nowcast radar coverage states reported by met.no
+const nowcastCoverageOK = "ok"
+
+// NowcastClient fetches short-term precipitation nowcasts from the met.no nowcast API.
+// Nowcast only covers the Nordic area; elsewhere it returns no data points.
+type NowcastClient struct {
+	URL       string
+	UserAgent string
+	client    *http.Client
+}
+
+// NewNowcastClient creates a nowcast client from the API configuration
+func NewNowcastClient(cfg *config.Config) *NowcastClient {
+	return &NowcastClient{
+		URL:       cfg.API.Nowcast.URL,
+		UserAgent: cfg.API.UserAgent,
+		client:    newAPIClient(cfg),
+	}
+}
+
+// nowcastResponse represents the met.no nowcast/2.0/complete response structure
+type nowcastResponse struct {
+	Properties struct {
+		Meta struct {
+			RadarCoverage string `json:"radar_coverage"` // "ok", "temporarily unavailable" or "no coverage"
+		} `json:"meta"`
+		Timeseries []struct {
+			Time time.Time `json:"time"`
+			Data struct {
+				Instant struct {
+					Details struct {
+						AirTemperature    float64 `json:"air_temperature"`
+						PrecipitationRate float64 `json:"precipitation_rate"` // mm/h
+						RelativeHumidity  float64 `json:"relative_humidity"`
+						WindSpeed         float64 `json:"wind_speed"`
+						WindFromDirection float64 `json:"wind_from_direction"`
+					} `json:"details"`
+				} `json:"instant"`
+				Next1Hours struct {
+					Summary struct {
+						SymbolCode string `json:"symbol_code"`
+					} `json:"summary"`
+				} `json:"next_1_hours"`
+			} `json:"data"`
+		} `json:"timeseries"`
+	} `json:"properties"`
+}
+
+// Nowcast returns the 5-minute precipitation nowcast for a location.
+// PrecipitationMm holds the precipitation rate in mm/h at each step.
+func (c *NowcastClient) Nowcast(ctx context.Context, loc Location) ([]WeatherPoint, error) {
+	query := url.Values{}
+	query.Set("lat", fmt.Sprintf("%.4f", loc.Lat))
+	query.Set("lon", fmt.Sprintf("%.4f", loc.Lon))
+
+	var resp nowcastResponse
+	if _, err := getJSON(ctx, c.client, c.URL+"?"+query.Encode(), c.UserAgent, &resp); err != nil {
+		return nil, err
+	}
+
+	// Without radar coverage the precipitation values are not meaningful
+	if coverage := resp.Properties.Meta.RadarCoverage; coverage != "" && coverage != nowcastCoverageOK {
+		return nil, nil
+	}
+
+	points := make([]WeatherPoint, 0, len(resp.Properties.Timeseries))
+	for _, entry := range resp.Properties.Timeseries {
+		details := entry.Data.Instant.Details
+		points = append(points, WeatherPoint{
+			Timestamp:       entry.Time,
+			Temperature:     details.AirTemperature,
+			Humidity:        details.RelativeHumidity,
+			WindSpeed:       details.WindSpeed,
+			WindDirection:   details.WindFromDirection,
+			PrecipitationMm: details.PrecipitationRate,
+			SymbolCode:      entry.Data.Next1Hours.Summary.SymbolCode,
+			Source:          weathermodels.SourceNowcast,
+		})
+	}
+	return points, nil
+}
+
+// nowcastProvider attaches a precipitation nowcast to successful results from the wrapped provider.
+// Like alerts, the nowcast is best-effort and never fails the weather result.
+type nowcastProvider struct {
+	Provider
+	nowcast *NowcastClient
+}
+
+// Fetch fetches weather for a location, then adds the short-term nowcast
+func (p *nowcastProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	result, err := p.Provider.Fetch(ctx, loc)
+	if err != nil {
+		return result, err
+	}
+
+	nowcast, nowcastErr := p.nowcast.Nowcast(ctx, result.Location)
+	if nowcastErr != nil {
+		slog.Warn("Nowcast unavailable", "location", loc.Name, "error", nowcastErr)
+		return result, nil
+	}
+	result.Nowcast = nowcast
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+	"weathermodels"
+)
+
+// sampleNowcastResponse is a trimmed nowcast/2.0/complete payload with two 5-minute steps
+const sampleNowcastResponse = `{
+  "type": "Feature",
+  "properties": {
+    "meta": {"radar_coverage": "ok"},
+    "timeseries": [
+      {"time": "2025-10-03T12:00:00Z", "data": {"instant": {"details": {"air_temperature": 9.1, "precipitation_rate": 0.0, "relative_humidity": 80, "wind_speed": 4.2, "wind_from_direction": 200}}, "next_1_hours": {"summary": {"symbol_code": "rain"}}}},
+      {"time": "2025-10-03T12:05:00Z", "data": {"instant": {"details": {"air_temperature": 9.0, "precipitation_rate": 1.8, "relative_humidity": 85, "wind_speed": 4.5, "wind_from_direction": 205}}}}
+    ]
+  }
+}`
+
+// newTestNowcastClient creates a nowcast client pointed at a test server
+func newTestNowcastClient(serverURL string) *NowcastClient {
+	cfg := *config.Get()
+	cfg.API.Nowcast.URL = serverURL
+	return NewNowcastClient(&cfg)
+}
+
+// TestNowcastClient tests parsing of nowcast steps and radar coverage handling
+func TestNowcastClient(t *testing.T) {
+	payload := sampleNowcastResponse
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("lat") != "59.9100" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	client := newTestNowcastClient(server.URL)
+	points, err := client.Nowcast(context.Background(), Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if err != nil {
+		t.Fatalf("Nowcast failed: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 nowcast points, got %d", len(points))
+	}
+	if !points[1].Timestamp.Equal(time.Date(2025, 10, 3, 12, 5, 0, 0, time.UTC)) || points[1].PrecipitationMm != 1.8 {
+		t.Errorf("Unexpected nowcast point: %+v", points[1])
+	}
+	if points[0].SymbolCode != "rain" || points[0].Source != weathermodels.SourceNowcast {
+		t.Errorf("Expected a nowcast with the symbol code from next_1_hours, got %+v", points[0])
+	}
+
+	payload = `{"properties": {"meta": {"radar_coverage": "no coverage"}, "timeseries": [{"time": "2025-10-03T12:00:00Z"}]}}`
+	points, err = client.Nowcast(context.Background(), Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if err != nil || len(points) != 0 {
+		t.Errorf("Expected no points without radar coverage, got %d (err: %v)", len(points), err)
+	}
+}
+
+// TestNowcastProviderBestEffort tests that the nowcast is attached and that a failing lookup keeps the result
+func TestNowcastProviderBestEffort(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleNowcastResponse))
+	}))
+	defer healthy.Close()
+
+	provider := &nowcastProvider{Provider: &stubProvider{}, nowcast: newTestNowcastClient(healthy.URL)}
+	result := FetchWithProvider(context.Background(), provider, Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if !result.Success || len(result.Nowcast) != 2 {
+		t.Errorf("Expected a successful result with 2 nowcast points, got %+v", result)
+	}
+
+	outside := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer outside.Close()
+
+	provider = &nowcastProvider{Provider: &stubProvider{}, nowcast: newTestNowcastClient(outside.URL)}
+	result = FetchWithProvider(context.Background(), provider, Location{Name: "Rome", Lat: 41.9, Lon: 12.5})
+	if !result.Success || len(result.Nowcast) != 0 {
+		t.Errorf("Expected nowcast failure to be ignored, got %+v", result)
+	}
+}
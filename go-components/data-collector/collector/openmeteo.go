@@ -0,0 +1,331 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"weather-collector/config"
+	"weathermodels"
+)
+
+// ProviderOpenMeteo is the registered name of the Open-Meteo provider
+const ProviderOpenMeteo = "openmeteo"
+
+// openMeteoHourlyVars lists the hourly variables requested from both the forecast and archive APIs
+const openMeteoHourlyVars = "temperature_2m,relative_humidity_2m,pressure_msl,wind_speed_10m," +
+	"wind_direction_10m,cloud_cover,precipitation,weather_code"
+
+// openMeteoTimeLayout is the timestamp format returned by Open-Meteo with timezone=UTC
+const openMeteoTimeLayout = "2006-01-02T15:04"
+
+// OpenMeteoProvider fetches hourly forecasts and archived history from Open-Meteo (no API key required)
+type OpenMeteoProvider struct {
+	ForecastURL  string
+	ArchiveURL   string
+	ForecastDays int
+	HistoryDays  int
+	UserAgent    string
+	Retry        RetryPolicy // Retry policy for the forecast endpoint
+	HistoryRetry RetryPolicy // Retry policy for the archive endpoint (separate circuit breaker)
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewOpenMeteoProvider creates an Open-Meteo provider from the API configuration
+func NewOpenMeteoProvider(cfg *config.Config) *OpenMeteoProvider {
+	return &OpenMeteoProvider{
+		ForecastURL:  cfg.API.OpenMeteo.ForecastURL,
+		ArchiveURL:   cfg.API.OpenMeteo.ArchiveURL,
+		ForecastDays: openMeteoForecastDays(cfg),
+		HistoryDays:  cfg.API.OpenMeteo.HistoryDays,
+		UserAgent:    cfg.API.UserAgent,
+		Retry:        newRetryPolicy(cfg, cfg.API.OpenMeteo.ForecastURL),
+		HistoryRetry: newRetryPolicy(cfg, cfg.API.OpenMeteo.ArchiveURL),
+		client:       newAPIClient(cfg),
+		now:          time.Now,
+	}
+}
+
+// openMeteoForecastDays returns the days of forecast to request, covering no more than collection.forecast_hours
+func openMeteoForecastDays(cfg *config.Config) int {
+	days := cfg.API.OpenMeteo.ForecastDays
+	if hours := cfg.Collection.ForecastHours; hours > 0 {
+		days = min(days, (hours+23)/24+1) // +1 because the hourly data starts at midnight of the current day
+	}
+	return days
+}
+
+// Name returns the provider name
+func (p *OpenMeteoProvider) Name() string {
+	return ProviderOpenMeteo
+}
+
+// Fetch collects current conditions and the hourly forecast for a location.
+// When HistoryDays is set, archived hourly readings are prepended to the forecast
+// so the pattern engine can be seeded with history.
+func (p *OpenMeteoProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	if err := checkCoordinates(loc); err != nil {
+		return failedResult(loc, err), err
+	}
+
+	result := p.Retry.Do(ctx, loc, func(ctx context.Context) (WeatherResult, bool) {
+		return p.fetchForecast(ctx, loc)
+	})
+	if !result.Success {
+		return result, result.Err()
+	}
+
+	if p.HistoryDays > 0 {
+		history := p.HistoryRetry.Do(ctx, loc, func(ctx context.Context) (WeatherResult, bool) {
+			return p.fetchHistory(ctx, loc)
+		})
+		if history.Success {
+			result.Forecast = append(history.Forecast, result.Forecast...)
+		} else {
+			// History is best-effort: the forecast is still useful on its own
+			slog.Warn("Open-Meteo history unavailable", "location", loc.Name, "error", history.Error)
+		}
+	}
+
+	return result, nil
+}
+
+// fetchForecast requests current conditions and the hourly forecast
+func (p *OpenMeteoProvider) fetchForecast(ctx context.Context, loc Location) (WeatherResult, bool) {
+	query := p.baseQuery(loc)
+	query.Set("current", openMeteoHourlyVars)
+	query.Set("hourly", openMeteoHourlyVars+",precipitation_probability") // forecast-only variable
+	query.Set("forecast_days", fmt.Sprintf("%d", p.ForecastDays))
+
+	var resp openMeteoResponse
+	if retryable, err := getJSON(ctx, p.client, p.ForecastURL+"?"+query.Encode(), p.UserAgent, &resp); err != nil {
+		return failedResult(loc, err), retryable
+	}
+
+	if resp.Current == nil {
+		return failedResult(loc, classify(ErrNoData, errors.New("No current weather data in API response"))), false
+	}
+
+	current, ok := resp.Current.toWeatherPoint()
+	if !ok {
+		return failedResult(loc, classify(ErrNoData, errors.New("No current weather data extracted"))), false
+	}
+
+	// Only keep hourly entries after the current observation
+	var forecast []WeatherPoint
+	for _, point := range resp.Hourly.toWeatherPoints(weathermodels.SourceForecast) {
+		if point.Timestamp.After(current.Timestamp) {
+			forecast = append(forecast, point)
+		}
+	}
+
+	return WeatherResult{
+		Location:       loc,
+		CurrentWeather: current,
+		Forecast:       forecast,
+		Success:        true,
+		Source:         SourceAPI,
+	}, false
+}
+
+// fetchHistory requests archived hourly readings for the configured number of past days
+func (p *OpenMeteoProvider) fetchHistory(ctx context.Context, loc Location) (WeatherResult, bool) {
+	today := p.now().UTC().Truncate(24 * time.Hour)
+	query := p.baseQuery(loc)
+	query.Set("start_date", today.AddDate(0, 0, -p.HistoryDays).Format(time.DateOnly))
+	query.Set("end_date", today.AddDate(0, 0, -1).Format(time.DateOnly))
+
+	var resp openMeteoResponse
+	if retryable, err := getJSON(ctx, p.client, p.ArchiveURL+"?"+query.Encode(), p.UserAgent, &resp); err != nil {
+		return failedResult(loc, err), retryable
+	}
+
+	return WeatherResult{
+		Location: loc,
+		Forecast: resp.Hourly.toWeatherPoints(weathermodels.SourceObservation),
+		Success:  true,
+	}, false
+}
+
+// baseQuery builds the query parameters shared by the forecast and archive APIs
+func (p *OpenMeteoProvider) baseQuery(loc Location) url.Values {
+	query := url.Values{}
+	query.Set("latitude", fmt.Sprintf("%.4f", loc.Lat))
+	query.Set("longitude", fmt.Sprintf("%.4f", loc.Lon))
+	if loc.Alt != 0 {
+		query.Set("elevation", fmt.Sprintf("%d", loc.Alt))
+	}
+	query.Set("hourly", openMeteoHourlyVars)
+	query.Set("wind_speed_unit", "ms") // match met.no units
+	query.Set("timezone", "UTC")
+	return query
+}
+
+// openMeteoResponse represents the Open-Meteo forecast/archive response structure
+type openMeteoResponse struct {
+	Current *openMeteoCurrent `json:"current"`
+	Hourly  openMeteoHourly   `json:"hourly"`
+}
+
+// openMeteoCurrent holds the "current" block; values are pointers because Open-Meteo returns null for gaps
+type openMeteoCurrent struct {
+	Time               string   `json:"time"`
+	Temperature2m      *float64 `json:"temperature_2m"`
+	RelativeHumidity2m *float64 `json:"relative_humidity_2m"`
+	PressureMSL        *float64 `json:"pressure_msl"`
+	WindSpeed10m       *float64 `json:"wind_speed_10m"`
+	WindDirection10m   *float64 `json:"wind_direction_10m"`
+	CloudCover         *float64 `json:"cloud_cover"`
+	Precipitation      *float64 `json:"precipitation"`
+	WeatherCode        *int     `json:"weather_code"`
+}
+
+// openMeteoHourly holds the column-oriented hourly block
+type openMeteoHourly struct {
+	Time               []string   `json:"time"`
+	Temperature2m      []*float64 `json:"temperature_2m"`
+	RelativeHumidity2m []*float64 `json:"relative_humidity_2m"`
+	PressureMSL        []*float64 `json:"pressure_msl"`
+	WindSpeed10m       []*float64 `json:"wind_speed_10m"`
+	WindDirection10m   []*float64 `json:"wind_direction_10m"`
+	CloudCover         []*float64 `json:"cloud_cover"`
+	Precipitation      []*float64 `json:"precipitation"`
+	WeatherCode        []*int     `json:"weather_code"`
+
+	PrecipitationProbability []*float64 `json:"precipitation_probability"`
+}
+
+// toWeatherPoint converts the current block, reporting false if it has no usable timestamp or temperature
+func (c openMeteoCurrent) toWeatherPoint() (WeatherPoint, bool) {
+	timestamp, ok := openMeteoTimestamp(c.Time)
+	if !ok || c.Temperature2m == nil {
+		return WeatherPoint{}, false
+	}
+
+	return WeatherPoint{
+		Timestamp:       timestamp,
+		Temperature:     *c.Temperature2m,
+		Pressure:        valueOrZero(c.PressureMSL),
+		Humidity:        valueOrZero(c.RelativeHumidity2m),
+		WindSpeed:       valueOrZero(c.WindSpeed10m),
+		WindDirection:   valueOrZero(c.WindDirection10m),
+		CloudCover:      valueOrZero(c.CloudCover),
+		PrecipitationMm: valueOrZero(c.Precipitation),
+		SymbolCode:      wmoSymbolCode(c.WeatherCode),
+		Source:          weathermodels.SourceObservation,
+	}, true
+}
+
+// toWeatherPoints converts the hourly columns into rows of the given source, skipping hours
+// without a temperature (the archive reports null for the most recent days until reanalysis
+// catches up)
+func (h openMeteoHourly) toWeatherPoints(source string) []WeatherPoint {
+	var points []WeatherPoint
+	for i, rawTime := range h.Time {
+		timestamp, ok := openMeteoTimestamp(rawTime)
+		temperature := columnValue(h.Temperature2m, i)
+		if !ok || temperature == nil {
+			continue
+		}
+
+		var weatherCode *int
+		if i < len(h.WeatherCode) {
+			weatherCode = h.WeatherCode[i]
+		}
+
+		points = append(points, WeatherPoint{
+			Timestamp:                timestamp,
+			Temperature:              *temperature,
+			Pressure:                 valueOrZero(columnValue(h.PressureMSL, i)),
+			Humidity:                 valueOrZero(columnValue(h.RelativeHumidity2m, i)),
+			WindSpeed:                valueOrZero(columnValue(h.WindSpeed10m, i)),
+			WindDirection:            valueOrZero(columnValue(h.WindDirection10m, i)),
+			CloudCover:               valueOrZero(columnValue(h.CloudCover, i)),
+			PrecipitationMm:          valueOrZero(columnValue(h.Precipitation, i)),
+			PrecipitationProbability: valueOrZero(columnValue(h.PrecipitationProbability, i)),
+			SymbolCode:               wmoSymbolCode(weatherCode),
+			Source:                   source,
+		})
+	}
+	return points
+}
+
+// openMeteoTimestamp converts an Open-Meteo UTC timestamp into the RFC3339 format used by met.no
+func openMeteoTimestamp(raw string) (time.Time, bool) {
+	parsed, err := time.Parse(openMeteoTimeLayout, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.UTC(), true
+}
+
+// columnValue safely reads a nullable value from an hourly column
+func columnValue(column []*float64, i int) *float64 {
+	if i >= len(column) {
+		return nil
+	}
+	return column[i]
+}
+
+// valueOrZero dereferences a nullable value
+func valueOrZero(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// wmoSymbolCode maps a WMO weather interpretation code onto the closest met.no symbol code
+func wmoSymbolCode(code *int) string {
+	if code == nil {
+		return ""
+	}
+
+	switch *code {
+	case 0:
+		return "clearsky"
+	case 1:
+		return "fair"
+	case 2:
+		return "partlycloudy"
+	case 3:
+		return "cloudy"
+	case 45, 48:
+		return "fog"
+	case 51, 53, 55, 56, 57, 61:
+		return "lightrain"
+	case 63:
+		return "rain"
+	case 65:
+		return "heavyrain"
+	case 66, 67:
+		return "sleet"
+	case 71:
+		return "lightsnow"
+	case 73, 77:
+		return "snow"
+	case 75:
+		return "heavysnow"
+	case 80:
+		return "lightrainshowers"
+	case 81:
+		return "rainshowers"
+	case 82:
+		return "heavyrainshowers"
+	case 85:
+		return "lightsnowshowers"
+	case 86:
+		return "heavysnowshowers"
+	case 95:
+		return "rainandthunder"
+	case 96, 99:
+		return "heavyrainandthunder"
+	default:
+		return ""
+	}
+}
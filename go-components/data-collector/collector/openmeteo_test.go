@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+	"weathermodels"
+)
+
+const sampleOpenMeteoForecast = `{
+  "current": {"time": "2025-10-03T01:00", "temperature_2m": 9.5, "pressure_msl": 1011.0, "weather_code": 3},
+  "hourly": {
+    "time": ["2025-10-03T00:00", "2025-10-03T01:00", "2025-10-03T02:00", "2025-10-03T03:00"],
+    "temperature_2m": [9.0, 9.5, 10.1, 10.4],
+    "pressure_msl": [1011.2, 1011.0, 1010.7, 1010.1],
+    "precipitation": [0, 0, 0.4, 1.2],
+    "precipitation_probability": [0, 5, 40, 80],
+    "weather_code": [3, 3, 61, 63]
+  }
+}`
+
+const sampleOpenMeteoArchive = `{
+  "hourly": {
+    "time": ["2025-10-01T00:00", "2025-10-01T01:00", "2025-10-02T00:00"],
+    "temperature_2m": [7.0, 6.8, null],
+    "pressure_msl": [1015.0, 1014.8, null]
+  }
+}`
+
+// newTestOpenMeteoServer serves canned forecast and archive responses
+func newTestOpenMeteoServer(t *testing.T, archiveCalls *int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/forecast", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("wind_speed_unit") != "ms" {
+			t.Errorf("Expected wind speed in m/s, got query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(sampleOpenMeteoForecast))
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) {
+		*archiveCalls++
+		if r.URL.Query().Get("start_date") != "2025-10-01" || r.URL.Query().Get("end_date") != "2025-10-02" {
+			t.Errorf("Unexpected archive range: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(sampleOpenMeteoArchive))
+	})
+	return httptest.NewServer(mux)
+}
+
+// newTestOpenMeteoProvider creates a provider pointed at the test server
+func newTestOpenMeteoProvider(serverURL string, historyDays int) *OpenMeteoProvider {
+	cfg := *config.Get()
+	cfg.API.OpenMeteo = config.OpenMeteoConfig{
+		ForecastURL:  serverURL + "/forecast",
+		ArchiveURL:   serverURL + "/archive",
+		ForecastDays: 2,
+		HistoryDays:  historyDays,
+	}
+	provider := NewOpenMeteoProvider(&cfg)
+	provider.now = func() time.Time { return time.Date(2025, 10, 3, 1, 30, 0, 0, time.UTC) }
+	return provider
+}
+
+// TestOpenMeteoForecast tests mapping of the current block and hourly forecast
+func TestOpenMeteoForecast(t *testing.T) {
+	archiveCalls := 0
+	server := newTestOpenMeteoServer(t, &archiveCalls)
+	defer server.Close()
+
+	result, err := newTestOpenMeteoProvider(server.URL, 0).Fetch(context.Background(), Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.CurrentWeather.Timestamp.Equal(time.Date(2025, 10, 3, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected RFC3339 timestamp, got '%s'", result.CurrentWeather.Timestamp)
+	}
+	if result.CurrentWeather.SymbolCode != "cloudy" {
+		t.Errorf("Expected WMO code 3 to map to 'cloudy', got '%s'", result.CurrentWeather.SymbolCode)
+	}
+	if len(result.Forecast) != 2 {
+		t.Fatalf("Expected 2 forecast hours after the current observation, got %d", len(result.Forecast))
+	}
+	if result.Forecast[1].PrecipitationProbability != 80 || result.Forecast[1].SymbolCode != "rain" {
+		t.Errorf("Unexpected forecast mapping: %+v", result.Forecast[1])
+	}
+	if archiveCalls != 0 {
+		t.Errorf("Archive should not be called when history is disabled")
+	}
+}
+
+// TestOpenMeteoHistory tests that archived readings are prepended and null hours skipped
+func TestOpenMeteoHistory(t *testing.T) {
+	archiveCalls := 0
+	server := newTestOpenMeteoServer(t, &archiveCalls)
+	defer server.Close()
+
+	result, err := newTestOpenMeteoProvider(server.URL, 2).Fetch(context.Background(), Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if archiveCalls != 1 {
+		t.Errorf("Expected one archive request, got %d", archiveCalls)
+	}
+	if len(result.Forecast) != 4 {
+		t.Fatalf("Expected 2 history + 2 forecast points, got %d", len(result.Forecast))
+	}
+	if !result.Forecast[0].Timestamp.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) || result.Forecast[0].Temperature != 7.0 {
+		t.Errorf("Expected history first, got %+v", result.Forecast[0])
+	}
+	if result.Forecast[0].Source != weathermodels.SourceObservation || result.Forecast[3].Source != weathermodels.SourceForecast {
+		t.Errorf("Expected archived observations then forecasts, got %+v", result.Forecast)
+	}
+}
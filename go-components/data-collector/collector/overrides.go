@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"weather-collector/config"
+)
+
+// ProviderSet hands out the provider for each location: the base provider unless the location
+// names another one, in which case that provider is built from the same configuration on first use
+type ProviderSet struct {
+	cfg  *config.Config
+	base Provider
+
+	mu        sync.Mutex
+	overrides map[string]Provider
+}
+
+// NewProviderSet creates a provider set whose default is base
+func NewProviderSet(cfg *config.Config, base Provider) *ProviderSet {
+	return &ProviderSet{cfg: cfg, base: base, overrides: make(map[string]Provider)}
+}
+
+// For returns the provider to fetch loc with. An override that cannot be created (e.g. an unknown
+// name) yields a provider that fails every fetch with the creation error, so only that location fails.
+func (s *ProviderSet) For(loc Location) Provider {
+	if loc.Provider == "" || loc.Provider == s.base.Name() {
+		return s.base
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if provider, ok := s.overrides[loc.Provider]; ok {
+		return provider
+	}
+
+	cfg := *s.cfg
+	cfg.API.Provider = loc.Provider
+	provider, err := NewProvider(&cfg)
+	if err != nil {
+		provider = &unavailableProvider{name: loc.Provider, err: err}
+	}
+	s.overrides[loc.Provider] = provider
+	return provider
+}
+
+// unavailableProvider stands in for a per-location provider that could not be created
+type unavailableProvider struct {
+	name string
+	err  error
+}
+
+// Name returns the requested provider name
+func (p *unavailableProvider) Name() string {
+	return p.name
+}
+
+// Fetch fails with the error that prevented the provider from being created
+func (p *unavailableProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	return WeatherResult{Location: loc}, p.err
+}
+
+// FetchTimeout returns the location's own timeout, or fallback when it does not set one
+func (l Location) FetchTimeout(fallback time.Duration) time.Duration {
+	if l.Timeout > 0 {
+		return time.Duration(l.Timeout * float64(time.Second))
+	}
+	return fallback
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// TestProviderSet tests choosing the base provider, a named override and an unknown one
+func TestProviderSet(t *testing.T) {
+	base := &stubProvider{}
+	providers := NewProviderSet(config.Get(), base)
+
+	if got := providers.For(Location{Name: "Oslo"}); got != base {
+		t.Errorf("Expected the base provider without an override, got %q", got.Name())
+	}
+	if got := providers.For(Location{Name: "Oslo", Provider: "stub"}); got != base {
+		t.Errorf("Expected the base provider when the override names it, got %q", got.Name())
+	}
+
+	override := providers.For(Location{Name: "Oslo", Provider: ProviderOpenMeteo})
+	if override.Name() != ProviderOpenMeteo {
+		t.Errorf("Expected an Open-Meteo provider, got %q", override.Name())
+	}
+	if again := providers.For(Location{Name: "Bergen", Provider: ProviderOpenMeteo}); again != override {
+		t.Error("Expected the override provider to be reused")
+	}
+
+	result := FetchWithProvider(context.Background(), providers.For(Location{Provider: "nope"}), Location{Name: "Oslo", Provider: "nope"})
+	if result.Success || !strings.Contains(result.Error, "unknown weather provider") {
+		t.Errorf("Expected an unknown provider failure, got %+v", result)
+	}
+}
+
+// TestCollectPerLocationTimeout tests that a location's own timeout replaces the worker timeout
+func TestCollectPerLocationTimeout(t *testing.T) {
+	locations := []Location{{Name: "Slow", Lat: 60, Lon: 10, Timeout: 0.05}}
+
+	start := time.Now()
+	results := CollectWithProvider(context.Background(), blockingProvider{}, locations)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the location timeout to stop the fetch, took %v", elapsed)
+	}
+	if results[0].Success || results[0].ErrorCode != ErrorCodeTimeout {
+		t.Errorf("Expected a timeout failure, got %+v", results[0])
+	}
+}
+
+// TestDispatchOrder tests that higher priorities go first and ties keep input order
+func TestDispatchOrder(t *testing.T) {
+	groups := groupLocations([]Location{
+		{Name: "A", Lat: 1, Lon: 1},
+		{Name: "B", Lat: 2, Lon: 2, Priority: 5},
+		{Name: "C", Lat: 3, Lon: 3},
+		{Name: "D", Lat: 4, Lon: 4, Priority: 1},
+		{Name: "A again", Lat: 1, Lon: 1, Priority: 9}, // Raises the priority of A's group
+	}, 0)
+
+	order := dispatchOrder(groups)
+	var names []string
+	for _, i := range order {
+		names = append(names, groups[i].location.Name)
+	}
+	if got := strings.Join(names, ","); got != "A,B,D,C" {
+		t.Errorf("Expected dispatch order A,B,D,C, got %s", got)
+	}
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"weather-collector/config"
+)
+
+// Provider is a source of weather data for a single location
+type Provider interface {
+	// Name returns the short identifier used in config (e.g. "metno")
+	Name() string
+	// Fetch collects current weather and forecast for a location.
+	// On failure the returned WeatherResult still carries the location and error details.
+	Fetch(ctx context.Context, loc Location) (WeatherResult, error)
+}
+
+// NewProvider creates the provider selected by the API configuration
+// The forecast is trimmed to the collection horizon and resolution before any extras are attached.
+// When geocoding is enabled, locations given only by name are resolved before fetching;
+// when MetAlerts or nowcast is enabled, warnings or a short-term nowcast are attached to each successful result,
+// and coastal locations get an ocean forecast when marine collection is enabled.
+func NewProvider(cfg *config.Config) (Provider, error) {
+	var provider Provider
+	switch cfg.API.Provider {
+	case "", ProviderMetNo:
+		provider = NewMetNoProvider(cfg)
+	case ProviderOpenMeteo:
+		provider = NewOpenMeteoProvider(cfg)
+	case ProviderMock:
+		provider = NewMockProvider(cfg)
+	default:
+		return nil, fmt.Errorf("unknown weather provider %q", cfg.API.Provider)
+	}
+	provider = newHorizonProvider(provider, cfg.Collection)
+
+	if cfg.API.MetAlerts.Enabled {
+		provider = &alertsProvider{Provider: provider, alerts: NewMetAlertsClient(cfg)}
+	}
+	if cfg.API.Nowcast.Enabled {
+		provider = &nowcastProvider{Provider: provider, nowcast: NewNowcastClient(cfg)}
+	}
+	if cfg.API.Ocean.Enabled {
+		provider = &marineProvider{Provider: provider, ocean: NewOceanForecastClient(cfg)}
+	}
+	if cfg.Geocoding.Enabled {
+		provider = &geocodingProvider{Provider: provider, geocoder: NewNominatimGeocoder(cfg)}
+	}
+	return provider, nil
+}
+
+// FetchWeatherForLocation fetches weather for a single location using the configured provider
+func FetchWeatherForLocation(ctx context.Context, loc Location) WeatherResult {
+	provider, err := NewProvider(config.Get())
+	if err != nil {
+		return failedResult(loc, err)
+	}
+	return FetchWithProvider(ctx, provider, loc)
+}
+
+// FetchWithProvider calls a provider and normalizes its result so failures always carry an error message and code,
+// and every result is stamped with the output SchemaVersion and its collection metadata
+func FetchWithProvider(ctx context.Context, provider Provider, loc Location) WeatherResult {
+	start := time.Now()
+	result, err := provider.Fetch(ctx, loc)
+	result.SchemaVersion = SchemaVersion
+	result.Meta.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	result.Meta.Retries = max(result.Attempts-1, 0)
+	result.Meta.Provider = provider.Name()
+	result.Meta.CacheHit = result.Source == SourceCache
+	if err != nil {
+		result.Location = loc
+		result.Success = false
+		if result.Error == "" {
+			result.Error = err.Error()
+		}
+		if result.ErrorCode == "" {
+			result.ErrorCode = ErrorCode(err)
+		}
+	}
+	return result
+}
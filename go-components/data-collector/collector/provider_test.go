@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// stubProvider returns canned results without touching the network
+type stubProvider struct {
+	failFor map[string]bool
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+
+func (s *stubProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	if s.failFor[loc.Name] {
+		return WeatherResult{Location: loc}, errors.New("stub failure")
+	}
+	return WeatherResult{
+		Location:       loc,
+		CurrentWeather: WeatherPoint{Temperature: loc.Lat},
+		Success:        true,
+	}, nil
+}
+
+// TestNewProvider tests provider selection from config
+func TestNewProvider(t *testing.T) {
+	cfg := *config.Get()
+
+	cfg.API.Provider = ""
+	provider, err := NewProvider(&cfg)
+	if err != nil {
+		t.Fatalf("Empty provider should default to met.no, got error: %v", err)
+	}
+	if provider.Name() != ProviderMetNo {
+		t.Errorf("Expected provider %q, got %q", ProviderMetNo, provider.Name())
+	}
+
+	cfg.API.Provider = "does-not-exist"
+	if _, err := NewProvider(&cfg); err == nil {
+		t.Error("Unknown provider should return an error")
+	}
+}
+
+// TestCollectWithProvider tests that collection runs through the given provider
+func TestCollectWithProvider(t *testing.T) {
+	provider := &stubProvider{failFor: map[string]bool{"Broken": true}}
+	locations := []Location{
+		{Name: "Oslo", Lat: 59.91, Lon: 10.75},
+		{Name: "Broken", Lat: 1, Lon: 1},
+		{Name: "Bergen", Lat: 60.39, Lon: 5.32},
+	}
+
+	results := CollectWithProvider(context.Background(), provider, locations)
+
+	if len(results) != len(locations) {
+		t.Fatalf("Expected %d results, got %d", len(locations), len(results))
+	}
+	for i, result := range results {
+		if result.Location.Name != locations[i].Name {
+			t.Errorf("Result %d: expected '%s', got '%s'", i, locations[i].Name, result.Location.Name)
+		}
+		if result.SchemaVersion != SchemaVersion {
+			t.Errorf("Result %d: expected schema_version %d, got %d", i, SchemaVersion, result.SchemaVersion)
+		}
+		if result.Meta.Provider != "stub" {
+			t.Errorf("Result %d: expected provider 'stub' in meta, got %q", i, result.Meta.Provider)
+		}
+	}
+	if !results[0].Success || results[0].CurrentWeather.Temperature != 59.91 {
+		t.Errorf("Expected stub data for Oslo, got %+v", results[0])
+	}
+	if results[1].Success || results[1].Error != "stub failure" {
+		t.Errorf("Expected provider error to be surfaced, got %+v", results[1])
+	}
+}
+
+// TestCollectEach tests that every result is passed to the callback as it completes
+func TestCollectEach(t *testing.T) {
+	provider := &stubProvider{}
+	locations := []Location{
+		{Name: "Oslo", Lat: 59.91, Lon: 10.75},
+		{Name: "Bergen", Lat: 60.39, Lon: 5.32},
+		{Name: "Tromsø", Lat: 69.65, Lon: 18.96},
+	}
+
+	seen := make(map[string]int)
+	results := CollectEach(context.Background(), provider, locations, func(result WeatherResult) {
+		seen[result.Location.Name]++
+	})
+
+	if len(results) != len(locations) {
+		t.Fatalf("Expected %d results, got %d", len(locations), len(results))
+	}
+	for _, loc := range locations {
+		if seen[loc.Name] != 1 {
+			t.Errorf("Expected one callback for %s, got %d", loc.Name, seen[loc.Name])
+		}
+	}
+}
+
+// TestMetNoProviderFetch tests the met.no provider against a local server
+func TestMetNoProviderFetch(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+
+	cfg := *config.Get()
+	cfg.API.BaseURL = server.URL
+	provider := NewMetNoProvider(&cfg)
+
+	result, err := provider.Fetch(context.Background(), Location{Name: "Oslo", Lat: 59.91, Lon: 10.75})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if userAgent != cfg.API.UserAgent {
+		t.Errorf("Expected User-Agent %q, got %q", cfg.API.UserAgent, userAgent)
+	}
+	if result.CurrentWeather.SymbolCode != "cloudy" {
+		t.Errorf("Expected symbol code 'cloudy', got '%s'", result.CurrentWeather.SymbolCode)
+	}
+	if result.CurrentWeather.DewPoint != 8.1 || result.CurrentWeather.UVIndex != 0.4 || result.CurrentWeather.WindGust != 9.7 {
+		t.Errorf("Expected complete-variant details, got %+v", result.CurrentWeather)
+	}
+}
+
+// TestMetNoProviderAltitude tests that a location altitude is passed to met.no
+func TestMetNoProviderAltitude(t *testing.T) {
+	var altitude string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		altitude = r.URL.Query().Get("altitude")
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+
+	cfg := *config.Get()
+	cfg.API.BaseURL = server.URL
+	provider := NewMetNoProvider(&cfg)
+	provider.Cache = NewResponseCache()
+
+	result, err := provider.Fetch(context.Background(), Location{Name: "Finse", Lat: 60.60, Lon: 7.50, Alt: 1222})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if altitude != "1222" {
+		t.Errorf("Expected altitude=1222, got %q", altitude)
+	}
+	if result.Location.Alt != 1222 {
+		t.Errorf("Expected altitude to be carried into the result, got %d", result.Location.Alt)
+	}
+}
+
+// blockingProvider never answers until its context is cancelled
+type blockingProvider struct{}
+
+func (blockingProvider) Name() string { return "blocking" }
+
+func (blockingProvider) Fetch(ctx context.Context, loc Location) (WeatherResult, error) {
+	<-ctx.Done()
+	return WeatherResult{Location: loc}, ctx.Err()
+}
+
+// TestCollectWithProviderDeadline tests that a run deadline aborts stuck workers and keeps every location
+func TestCollectWithProviderDeadline(t *testing.T) {
+	locations := make([]Location, 12)
+	for i := range locations {
+		locations[i] = Location{Name: fmt.Sprintf("Stuck %d", i)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := CollectWithProvider(ctx, blockingProvider{}, locations)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected collection to stop at the deadline, took %v", elapsed)
+	}
+	if len(results) != len(locations) {
+		t.Fatalf("Expected %d results, got %d", len(locations), len(results))
+	}
+	for i, result := range results {
+		if result.Success || result.Error == "" {
+			t.Errorf("Result %d: expected a cancellation failure, got %+v", i, result)
+		}
+		if result.SchemaVersion != SchemaVersion {
+			t.Errorf("Result %d: expected schema_version %d, got %d", i, SchemaVersion, result.SchemaVersion)
+		}
+		if result.Location.Name != locations[i].Name {
+			t.Errorf("Result %d: expected '%s', got '%s'", i, locations[i].Name, result.Location.Name)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultThrottlePause is how long requests to a host are paused after a 429 without Retry-After
+const defaultThrottlePause = 10 * time.Second
+
+// RateLimiter spaces requests to one API host and can be paused when the host throttles us.
+// It is shared by every worker so a Retry-After from one request holds back all of them.
+// A nil limiter never waits.
+type RateLimiter struct {
+	Host     string        // API host, used in logs
+	Interval time.Duration // Minimum time between requests (0 = unlimited)
+
+	mu          sync.Mutex
+	next        time.Time // Earliest time the next request may start
+	pausedUntil time.Time // No requests before this time (set by throttle responses)
+	now         func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perSecond requests per second (0 = unlimited)
+func NewRateLimiter(host string, perSecond int) *RateLimiter {
+	limiter := &RateLimiter{Host: host, now: time.Now}
+	if perSecond > 0 {
+		limiter.Interval = time.Second / time.Duration(perSecond)
+	}
+	return limiter
+}
+
+// Wait blocks until a request may be made or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+	if l.pausedUntil.After(at) {
+		at = l.pausedUntil
+	}
+	l.next = at.Add(l.Interval)
+	l.mu.Unlock()
+
+	for delay := at.Sub(now); delay > 0; delay = l.pausedFor() {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// pausedFor returns how much longer the limiter is paused, picking up pauses that
+// started while a caller was already waiting for its slot
+func (l *RateLimiter) pausedFor() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pausedUntil.Sub(l.now())
+}
+
+// Pause holds back every request to the host for d
+func (l *RateLimiter) Pause(d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := l.now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+		slog.Warn("API throttled requests, pausing", "host", l.Host, "pause", d)
+	}
+}
+
+// sharedLimiters holds one rate limiter per API host for the whole process
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[string]*RateLimiter{}
+)
+
+// sharedRateLimiter returns the process-wide limiter for a host
+func sharedRateLimiter(host string, perSecond int) *RateLimiter {
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+
+	limiter, ok := sharedLimiters[host]
+	if !ok {
+		limiter = NewRateLimiter(host, perSecond)
+		sharedLimiters[host] = limiter
+	}
+	return limiter
+}
+
+// rateLimitedTransport waits on the shared limiter for the request's host before each request
+// and pauses that limiter when the response is a throttle (429, or 403 with Retry-After)
+type rateLimitedTransport struct {
+	base      http.RoundTripper
+	perSecond int
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter := sharedRateLimiter(req.URL.Host, t.perSecond)
+	if err := limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if pause, throttled := throttleDelay(resp, limiter.now()); throttled {
+		limiter.Pause(pause)
+	}
+	return resp, nil
+}
+
+// throttleDelay reports whether a response asks the client to slow down, and for how long.
+// met.no answers abusive clients with 429 (or 403 once blocked), usually with a Retry-After header.
+func throttleDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests && hasRetryAfter:
+		return retryAfter, true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return defaultThrottlePause, true
+	case resp.StatusCode == http.StatusForbidden && hasRetryAfter:
+		return retryAfter, true
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay-seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
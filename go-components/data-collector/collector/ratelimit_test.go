@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseRetryAfter tests both Retry-After formats
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	if d, ok := parseRetryAfter("120", now); !ok || d != 2*time.Minute {
+		t.Errorf("Expected 2m from delay-seconds, got %v (ok=%v)", d, ok)
+	}
+	if d, ok := parseRetryAfter("Fri, 03 Oct 2025 12:00:30 GMT", now); !ok || d != 30*time.Second {
+		t.Errorf("Expected 30s from HTTP date, got %v (ok=%v)", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected invalid Retry-After to be rejected")
+	}
+}
+
+// TestThrottledRequestIsRetried tests that a 429 pauses the shared limiter and the request is retried
+func TestThrottledRequestIsRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 2)
+
+	start := time.Now()
+	result := FetchWeatherForLocation(context.Background(), Location{Name: "Throttled", Lat: 60.0, Lon: 10.0})
+
+	if !result.Success || result.Attempts != 2 {
+		t.Fatalf("Expected success on the second attempt, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("Expected the retry to wait out Retry-After, only waited %v", elapsed)
+	}
+}
+
+// TestForbiddenWithoutRetryAfterFails tests that a plain 403 is not treated as a throttle
+func TestForbiddenWithoutRetryAfterFails(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 2)
+
+	result := FetchWeatherForLocation(context.Background(), Location{Name: "Forbidden", Lat: 60.0, Lon: 10.0})
+
+	if result.Success || calls.Load() != 1 {
+		t.Errorf("Expected a single failed attempt, got %d calls (success=%v)", calls.Load(), result.Success)
+	}
+}
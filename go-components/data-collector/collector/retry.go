@@ -0,0 +1,122 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"weather-collector/config"
+)
+
+// maxBackoff caps the delay between retry attempts
+const maxBackoff = 30 * time.Second
+
+// isRetryableStatus reports whether an HTTP status code indicates a transient server-side failure
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError
+}
+
+// isRetryableResponse reports whether a failed response is worth retrying: a transient
+// server-side failure, or a throttle that the shared rate limiter will wait out
+func isRetryableResponse(resp *http.Response) bool {
+	_, throttled := throttleDelay(resp, time.Now())
+	return isRetryableStatus(resp.StatusCode) || throttled
+}
+
+// isRetryableError reports whether a transport error is transient (timeouts, connection resets)
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoffDelay returns the wait before the given retry (1-based) using exponential backoff with jitter.
+// Half of the delay is fixed and the other half is randomized so concurrent workers don't retry in lockstep.
+func backoffDelay(base time.Duration, retry int) time.Duration {
+	if base <= 0 || retry <= 0 {
+		return 0
+	}
+
+	delay := base
+	for i := 1; i < retry && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// RetryPolicy controls how providers retry transient failures
+type RetryPolicy struct {
+	MaxRetries int             // Retries after the first attempt
+	BaseDelay  time.Duration   // Delay before the first retry, doubled for each subsequent one
+	Breaker    *CircuitBreaker // Short-circuits attempts while the endpoint is failing (nil disables)
+}
+
+// newRetryPolicy builds a retry policy for an endpoint from the API configuration
+func newRetryPolicy(cfg *config.Config, endpoint string) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: cfg.API.MaxRetries,
+		BaseDelay:  cfg.API.RetryDelay,
+		Breaker:    sharedBreaker(cfg, endpoint),
+	}
+}
+
+// Do runs attempt until it succeeds, fails permanently, or retries are exhausted.
+// The attempt function returns its result and whether a failure is worth retrying.
+// While the circuit breaker is open, remaining attempts are skipped with ErrCircuitOpen.
+// The last attempt's API response status is reported in the result's Meta.HTTPStatus.
+func (rp RetryPolicy) Do(ctx context.Context, loc Location, attempt func(context.Context) (WeatherResult, bool)) WeatherResult {
+	var result WeatherResult
+	maxAttempts := rp.MaxRetries + 1
+	for n := 1; n <= maxAttempts; n++ {
+		if n > 1 {
+			delay := backoffDelay(rp.BaseDelay, n-1)
+			slog.Debug("Retrying request", "location", loc.Name, "delay", delay.Round(time.Millisecond),
+				"attempt", n, "max_attempts", maxAttempts, "error", result.Error)
+			select {
+			case <-ctx.Done():
+				return result
+			case <-time.After(delay):
+			}
+		}
+
+		if err := rp.Breaker.Allow(); err != nil {
+			result = failedResult(loc, err)
+			result.Attempts = n - 1
+			break
+		}
+
+		attemptCtx, status := withStatusRecorder(ctx)
+		var retryable bool
+		result, retryable = attempt(attemptCtx)
+		result.Attempts = n
+		result.Meta.HTTPStatus = status.code
+		rp.Breaker.Record(result.Success || !retryable)
+
+		if result.Success || !retryable {
+			break
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// sampleAPIResponse is a minimal met.no payload with a single timeseries entry
+const sampleAPIResponse = `{
+  "type": "Feature",
+  "properties": {
+    "timeseries": [
+      {
+        "time": "2025-10-03T01:00:00Z",
+        "data": {
+          "instant": {"details": {"air_temperature": 12.5, "air_pressure_at_sea_level": 1012.3, "dew_point_temperature": 8.1,
+            "ultraviolet_index_clear_sky": 0.4, "wind_speed_of_gust": 9.7, "fog_area_fraction": 0.0}},
+          "next_1_hours": {"summary": {"symbol_code": "cloudy"}, "details": {"precipitation_amount": 0.2}}
+        }
+      }
+    ]
+  }
+}`
+
+// useTestAPI points the global config at a test server and restores it afterwards
+func useTestAPI(t *testing.T, url string, maxRetries int) {
+	t.Helper()
+	cfg := config.Get()
+	original := cfg.API
+	cfg.API.BaseURL = url
+	cfg.API.MaxRetries = maxRetries
+	cfg.API.RetryDelay = time.Millisecond
+	t.Cleanup(func() { cfg.API = original })
+}
+
+// TestFetchRetriesTransientFailures tests that 5xx responses are retried until success
+func TestFetchRetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(sampleAPIResponse))
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 3)
+
+	result := FetchWeatherForLocation(context.Background(), Location{Name: "Retry Town", Lat: 60.0, Lon: 10.0})
+
+	if !result.Success {
+		t.Fatalf("Expected success after retries, got error: %s", result.Error)
+	}
+	if result.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", result.Attempts)
+	}
+	if result.Meta.Retries != 2 || result.Meta.HTTPStatus != http.StatusOK || result.Meta.Provider != ProviderMetNo {
+		t.Errorf("Expected 2 retries ending in a 200 from metno, got %+v", result.Meta)
+	}
+	if result.CurrentWeather.Temperature != 12.5 {
+		t.Errorf("Expected temperature 12.5, got %f", result.CurrentWeather.Temperature)
+	}
+}
+
+// TestFetchDoesNotRetryClientErrors tests that 4xx responses fail immediately
+func TestFetchDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 3)
+
+	result := FetchWeatherForLocation(context.Background(), Location{Name: "Bad Request", Lat: 60.0, Lon: 10.0})
+
+	if result.Success {
+		t.Error("Expected failure for 400 response")
+	}
+	if calls.Load() != 1 || result.Attempts != 1 {
+		t.Errorf("Expected a single attempt, got %d calls / %d attempts", calls.Load(), result.Attempts)
+	}
+	if result.ErrorCode != ErrorCodeBadCoordinates {
+		t.Errorf("Expected error code %q, got %q", ErrorCodeBadCoordinates, result.ErrorCode)
+	}
+	if result.Meta.HTTPStatus != http.StatusBadRequest {
+		t.Errorf("Expected HTTP status 400 in meta, got %d", result.Meta.HTTPStatus)
+	}
+}
+
+// TestFetchGivesUpAfterMaxRetries tests that retries stop at the configured limit
+func TestFetchGivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	useTestAPI(t, server.URL, 2)
+
+	result := FetchWeatherForLocation(context.Background(), Location{Name: "Always Down", Lat: 60.0, Lon: 10.0})
+
+	if result.Success {
+		t.Error("Expected failure when every attempt returns 502")
+	}
+	if calls.Load() != 3 || result.Attempts != 3 {
+		t.Errorf("Expected 3 attempts (1 + 2 retries), got %d calls / %d attempts", calls.Load(), result.Attempts)
+	}
+}
+
+// TestBackoffDelay tests exponential growth, jitter bounds, and the maximum cap
+func TestBackoffDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	for retry := 1; retry <= 4; retry++ {
+		full := base << (retry - 1)
+		delay := backoffDelay(base, retry)
+		if delay < full/2 || delay > full {
+			t.Errorf("Retry %d: expected delay in [%v, %v], got %v", retry, full/2, full, delay)
+		}
+	}
+
+	if delay := backoffDelay(base, 50); delay > maxBackoff {
+		t.Errorf("Expected delay capped at %v, got %v", maxBackoff, delay)
+	}
+
+	if delay := backoffDelay(0, 3); delay != 0 {
+		t.Errorf("Expected zero delay for zero base, got %v", delay)
+	}
+}
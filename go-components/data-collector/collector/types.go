@@ -1,73 +1,135 @@
 package collector
 
-// Location represents a geographic location for weather data collection
+import (
+	"time"
+
+	"weathermodels"
+	"weathermodels/units"
+)
+
+// Location represents a geographic location for weather data collection.
+// With geocoding enabled, lat/lon may be omitted and are resolved from the name.
+// Provider, timeout and priority override the global settings for mixed fleets.
 type Location struct {
-	Name string  `json:"name"` // Human-readable name
-	Lat  float64 `json:"lat"`  // Latitude (-90 to 90)
-	Lon  float64 `json:"lon"`  // Longitude (-180 to 180)
+	Name    string  `json:"name"`              // Human-readable name
+	Lat     float64 `json:"lat"`               // Latitude (-90 to 90)
+	Lon     float64 `json:"lon"`               // Longitude (-180 to 180)
+	Alt     int     `json:"alt,omitempty"`     // Altitude in meters above sea level (0 = let the provider use its terrain model)
+	Coastal bool    `json:"coastal,omitempty"` // Also collect an ocean forecast (waves, sea temperature, currents)
+
+	// Optional per-location overrides of the global configuration
+	Provider string  `json:"provider,omitempty"` // Weather provider for this location (see api.provider)
+	Timeout  float64 `json:"timeout,omitempty"`  // Seconds allowed for this location (replaces performance.worker_timeout)
+	Priority int     `json:"priority,omitempty"` // Higher priorities are fetched first (default 0)
 }
 
+// SchemaVersion is the version of the WeatherResult JSON layout written by the collector.
+// Bump it whenever a field is renamed, removed or changes meaning so consumers can detect
+// output they do not understand; adding optional fields does not require a bump.
+const SchemaVersion = 1
+
 // WeatherResult represents the collected weather data for a location
 type WeatherResult struct {
+	SchemaVersion  int            `json:"schema_version"` // Layout version (see SchemaVersion)
 	Location       Location       `json:"location"`
 	CurrentWeather WeatherPoint   `json:"current_weather"`
 	Forecast       []WeatherPoint `json:"forecast,omitempty"`
 	Success        bool           `json:"success"`
 	Error          string         `json:"error,omitempty"`
+	ErrorCode      string         `json:"error_code,omitempty"` // Failure class, e.g. "timeout" or "bad_coordinates" (see ErrorCode)
+	Attempts       int            `json:"attempts,omitempty"`   // Number of API attempts made
+	Source         string         `json:"source,omitempty"`     // Where the data came from ("api" or "cache")
+	Alerts         []Alert        `json:"alerts,omitempty"`     // Active official weather warnings
+	Nowcast        []WeatherPoint `json:"nowcast,omitempty"`    // 5-minute precipitation nowcast (Nordic locations only)
+	Marine         []MarinePoint  `json:"marine,omitempty"`     // Ocean forecast for coastal locations
+	Meta           CollectionMeta `json:"meta,omitzero"`        // How the result was collected
+	Units          *units.System  `json:"units,omitempty"`      // Units of the readings when not metric (see ConvertUnits)
 }
 
-// WeatherPoint represents a single weather reading with timestamp
-type WeatherPoint struct {
-	Timestamp                string  `json:"timestamp"`
-	Temperature              float64 `json:"temperature"`
-	Pressure                 float64 `json:"pressure"`
-	Humidity                 float64 `json:"humidity"`
-	WindSpeed                float64 `json:"wind_speed"`
-	WindDirection            float64 `json:"wind_direction"`
-	CloudCover               float64 `json:"cloud_cover"`
-	PrecipitationMm          float64 `json:"precipitation_mm"`
-	PrecipitationProbability float64 `json:"precipitation_probability"`
-	SymbolCode               string  `json:"symbol_code"`
+// CollectionMeta describes how a result was collected, for diagnosing slow or flaky locations
+type CollectionMeta struct {
+	DurationMs float64 `json:"duration_ms"`           // Wall time spent on the location, including retries and extras
+	Retries    int     `json:"retries"`               // Attempts beyond the first
+	Provider   string  `json:"provider"`              // Provider that served the result (e.g. "metno")
+	CacheHit   bool    `json:"cache_hit"`             // Served from the response cache
+	HTTPStatus int     `json:"http_status,omitempty"` // Status of the last forecast API response (0 if no request was made)
+}
+
+// MarinePoint is a single ocean forecast reading (from met.no oceanforecast)
+type MarinePoint = weathermodels.MarinePoint
+
+// Alert is an official severe-weather warning covering a location (from met.no MetAlerts)
+type Alert struct {
+	ID             string `json:"id"`
+	Event          string `json:"event"`           // e.g. "wind", "flood", "icing"
+	Title          string `json:"title"`           // Short human-readable summary
+	Area           string `json:"area,omitempty"`  // Affected area description
+	AwarenessLevel string `json:"awareness_level"` // "yellow", "orange" or "red"
+	Severity       string `json:"severity"`        // CAP severity, e.g. "Moderate", "Severe"
+	Certainty      string `json:"certainty,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Instruction    string `json:"instruction,omitempty"`
+	Onset          string `json:"onset,omitempty"` // RFC3339 start of the warning period
+	Ends           string `json:"ends,omitempty"`  // RFC3339 end of the warning period
 }
 
-// APIResponse represents the met.no API response structure
+// Result sources reported in WeatherResult.Source
+const (
+	SourceAPI   = "api"   // Fetched from the provider
+	SourceCache = "cache" // Served from the response cache without a new payload
+)
+
+// WeatherPoint represents a single weather reading with timestamp. It is shared with the
+// pattern engine; timestamps are written to JSON as RFC 3339 strings.
+type WeatherPoint = weathermodels.WeatherPoint
+
+// APIResponse is the part of a met.no locationforecast/complete response the collector uses.
+// It is decoded as a stream (see DecodeStream), so the rest of the GeoJSON document is skipped
+// and entries beyond MaxHours are never decoded.
 type APIResponse struct {
-	Type     string `json:"type"`
-	Geometry struct {
-		Coordinates []float64 `json:"coordinates"`
-	} `json:"geometry"`
-	Properties struct {
-		Timeseries []struct {
-			Time string `json:"time"`
-			Data struct {
-				Instant struct {
-					Details struct {
-						AirTemperature        float64 `json:"air_temperature"`
-						AirPressureAtSeaLevel float64 `json:"air_pressure_at_sea_level"`
-						RelativeHumidity      float64 `json:"relative_humidity"`
-						WindSpeed             float64 `json:"wind_speed"`
-						WindFromDirection     float64 `json:"wind_from_direction"`
-						CloudAreaFraction     float64 `json:"cloud_area_fraction"`
-					} `json:"details"`
-				} `json:"instant"`
-				Next1Hours struct {
-					Summary struct {
-						SymbolCode string `json:"symbol_code"`
-					} `json:"summary"`
-					Details struct {
-						PrecipitationAmount        float64 `json:"precipitation_amount"`
-						ProbabilityOfPrecipitation float64 `json:"probability_of_precipitation"`
-					} `json:"details"`
-				} `json:"next_1_hours"`
-			} `json:"data"`
-		} `json:"timeseries"`
-	} `json:"properties"`
+	MaxHours   int               // Stop after entries more than this many hours past the first (0 decodes all)
+	Timeseries []TimeseriesEntry // Forecast timesteps in time order
+}
+
+// TimeseriesEntry is a single timestep of a met.no locationforecast response
+type TimeseriesEntry struct {
+	Time time.Time `json:"time"`
+	Data struct {
+		Instant struct {
+			Details InstantDetails `json:"details"`
+		} `json:"instant"`
+		Next1Hours struct {
+			Summary struct {
+				SymbolCode string `json:"symbol_code"`
+			} `json:"summary"`
+			Details struct {
+				PrecipitationAmount        float64 `json:"precipitation_amount"`
+				ProbabilityOfPrecipitation float64 `json:"probability_of_precipitation"`
+			} `json:"details"`
+		} `json:"next_1_hours"`
+	} `json:"data"`
+}
+
+// InstantDetails are the instantaneous values of a met.no timestep
+type InstantDetails struct {
+	AirTemperature        float64 `json:"air_temperature"`
+	AirPressureAtSeaLevel float64 `json:"air_pressure_at_sea_level"`
+	RelativeHumidity      float64 `json:"relative_humidity"`
+	WindSpeed             float64 `json:"wind_speed"`
+	WindFromDirection     float64 `json:"wind_from_direction"`
+	CloudAreaFraction     float64 `json:"cloud_area_fraction"`
+	DewPointTemperature   float64 `json:"dew_point_temperature"`
+	UltravioletIndex      float64 `json:"ultraviolet_index_clear_sky"`
+	WindSpeedOfGust       float64 `json:"wind_speed_of_gust"`
+	FogAreaFraction       float64 `json:"fog_area_fraction"`
 }
 
 // job represents a single location to process
 type job struct {
 	index    int
 	location Location
+	provider Provider      // Provider for this location (the run's provider unless overridden)
+	timeout  time.Duration // Time allowed for the fetch (0 = no limit)
 }
 
 // workerResult represents the outcome of processing a location
@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"slices"
+
+	"weathermodels/units"
+)
+
+// ConvertUnits returns the result with its readings in the units of system, which it records in
+// Units. The collector works in metric units and converts only what it writes out, so in the
+// metric system the result is returned as it is. Of the marine points only the sea temperature
+// has a unit of the system; wave heights and currents stay metric. Failed results have no
+// readings to convert.
+func (r WeatherResult) ConvertUnits(system units.System) WeatherResult {
+	if system.IsMetric() || !r.Success {
+		return r
+	}
+	r.CurrentWeather = system.ConvertPoint(r.CurrentWeather)
+	r.Forecast = system.ConvertPoints(r.Forecast)
+	r.Nowcast = system.ConvertPoints(r.Nowcast)
+	if r.Marine != nil {
+		r.Marine = slices.Clone(r.Marine)
+		for i := range r.Marine {
+			r.Marine[i].SeaTemperature = system.ConvertTemperature(r.Marine[i].SeaTemperature)
+		}
+	}
+	r.Units = &system
+	return r
+}
+
+// ConvertUnits returns the results in the units of system (see WeatherResult.ConvertUnits)
+func ConvertUnits(results []WeatherResult, system units.System) []WeatherResult {
+	if system.IsMetric() {
+		return results
+	}
+	converted := make([]WeatherResult, len(results))
+	for i, result := range results {
+		converted[i] = result.ConvertUnits(system)
+	}
+	return converted
+}
@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"weathermodels/units"
+)
+
+// TestConvertUnits tests that the readings of a result are converted without changing the
+// original, and that metric results and failed ones are left as they are
+func TestConvertUnits(t *testing.T) {
+	now := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	result := WeatherResult{
+		Success:        true,
+		CurrentWeather: WeatherPoint{Timestamp: now, Temperature: 20, WindSpeed: 10, Pressure: 1013.25},
+		Forecast:       []WeatherPoint{{Timestamp: now.Add(time.Hour), Temperature: 25, PrecipitationMm: 25.4}},
+		Marine:         []MarinePoint{{Timestamp: now, SeaTemperature: 10, WaveHeight: 2}},
+	}
+	system := units.System{Temperature: units.Fahrenheit, WindSpeed: units.Knots, Pressure: units.Hectopascals, Precipitation: units.Inches}
+
+	converted := result.ConvertUnits(system)
+	if converted.CurrentWeather.Temperature != 68 || converted.CurrentWeather.Pressure != 1013.25 {
+		t.Errorf("Unexpected current weather: %+v", converted.CurrentWeather)
+	}
+	if got := converted.CurrentWeather.WindSpeed; got < 19.43 || got > 19.44 {
+		t.Errorf("Expected 10 m/s to be 19.44 kn, got %v", got)
+	}
+	if converted.Forecast[0].Temperature != 77 || converted.Forecast[0].PrecipitationMm != 1 {
+		t.Errorf("Unexpected forecast: %+v", converted.Forecast[0])
+	}
+	if converted.Marine[0].SeaTemperature != 50 || converted.Marine[0].WaveHeight != 2 {
+		t.Errorf("Unexpected marine point: %+v", converted.Marine[0])
+	}
+	if converted.Units == nil || *converted.Units != system {
+		t.Errorf("Expected the units to be recorded, got %v", converted.Units)
+	}
+	if result.Forecast[0].Temperature != 25 || result.Marine[0].SeaTemperature != 10 {
+		t.Error("Expected the original result to be unchanged")
+	}
+
+	if got := result.ConvertUnits(units.Metric()); got.Units != nil || got.CurrentWeather != result.CurrentWeather {
+		t.Errorf("Expected a metric result to be unchanged, got %+v", got)
+	}
+	failed := WeatherResult{Error: "timeout"}
+	if got := failed.ConvertUnits(system); got.Units != nil || got.CurrentWeather.Temperature != 0 {
+		t.Errorf("Expected a failed result to be unchanged, got %+v", got)
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"weather-collector/scheduler"
+	"weathermodels/units"
 )
 
 // Global configuration instance
@@ -113,30 +117,138 @@ func loadFromFile(path string) (*Config, error) {
 func getDefaultConfig() *Config {
 	return &Config{
 		API: APIConfig{
-			BaseURL:    "https://api.met.no/weatherapi/locationforecast/2.0/compact",
+			Provider:   "metno",
+			BaseURL:    "https://api.met.no/weatherapi/locationforecast/2.0/complete",
 			UserAgent:  "WeatherIntelligenceSystem/1.0 (CS50 Final Project)",
 			Timeout:    30 * time.Second,
 			MaxRetries: 3,
 			RateLimit:  8, // Conservative rate limit (met.no allows ~20/sec)
 			RetryDelay: 2 * time.Second,
+
+			FixturesDir: "data/fixtures",
+
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+			OpenMeteo: OpenMeteoConfig{
+				ForecastURL:  "https://api.open-meteo.com/v1/forecast",
+				ArchiveURL:   "https://archive-api.open-meteo.com/v1/archive",
+				ForecastDays: 7,
+				HistoryDays:  14,
+			},
+			MetAlerts: MetAlertsConfig{
+				Enabled:  true,
+				URL:      "https://api.met.no/weatherapi/metalerts/2.0/current.json",
+				Language: "en",
+			},
+			Nowcast: NowcastConfig{
+				Enabled: false, // Extra request per location, only useful in the Nordics
+				URL:     "https://api.met.no/weatherapi/nowcast/2.0/complete",
+			},
+			Ocean: OceanConfig{
+				Enabled: true, // Only used for locations flagged as coastal
+				URL:     "https://api.met.no/weatherapi/oceanforecast/2.0/complete",
+			},
 		},
 		Integration: IntegrationConfig{
 			InputFile:     "data/integration/input_locations.json",
 			OutputFile:    "data/integration/output_weather.json",
 			DataDirectory: "data/integration",
 			CreateDirs:    true,
+			OutputFormat:  "json",
+			SummaryFile:   "data/integration/run_summary.json",
+
+			Handshake:        true,
+			HandshakeTimeout: 10 * time.Second,
+		},
+		Collection: CollectionConfig{
+			ForecastHours: 0, // Keep the full multi-day forecast
+			Resolution:    0, // Keep the provider's native resolution
 		},
 		Performance: PerformanceConfig{
 			MaxWorkers:      5, // Conservative for API rate limits
 			WorkerTimeout:   60 * time.Second,
 			CollectionDelay: 125 * time.Millisecond, // ~8 requests/second
 			BufferSize:      100,
+			RunTimeout:      5 * time.Minute,
+
+			Autoscale:     false,
+			MinWorkers:    1,
+			TargetLatency: 2 * time.Second,
+			MaxErrorRate:  0.2,
 		},
 		Logging: LoggingConfig{
 			EnableDebug:   false,
 			EnableMetrics: true,
 			LogToFile:     false,
+			LogFile:       "data/logs/collector.log",
 			LogLevel:      2, // Info level
+			LogFormat:     "text",
+
+			LogMaxSizeMB:      10,
+			LogRotateInterval: 24 * time.Hour,
+			LogMaxBackups:     7,
+			LogMaxAge:         30 * 24 * time.Hour,
+		},
+		Cache: CacheConfig{
+			Enabled:   false,
+			Directory: "data/cache",
+			TTL:       30 * time.Minute,
+		},
+		Geocoding: GeocodingConfig{
+			Enabled:     true,
+			BaseURL:     "https://nominatim.openstreetmap.org/search",
+			CacheFile:   "data/cache/geocode.json",
+			MinInterval: time.Second,
+		},
+		Schedule: SchedulerConfig{
+			Interval:       30 * time.Minute,
+			MaxOutputFiles: 48, // One day of half-hourly runs
+		},
+		Server: ServerConfig{
+			Address:         "127.0.0.1:8080",
+			GRPCAddress:     "127.0.0.1:50051",
+			ShutdownTimeout: 10 * time.Second,
+		},
+		MQTT: MQTTConfig{
+			Enabled:     false,
+			BrokerURL:   "tcp://localhost:1883",
+			ClientID:    "weather-collector",
+			TopicPrefix: "weather",
+			QoS:         1,
+			Retain:      true, // Home-automation dashboards show the last reading right after subscribing
+			Timeout:     30 * time.Second,
+		},
+		Events: EventsConfig{
+			Driver:          "", // No event bus until one is configured
+			WeatherSubject:  "weather.results",
+			AnalysisSubject: "weather.analysis",
+			Timeout:         30 * time.Second,
+		},
+		Notifications: NotificationsConfig{
+			WebhookURL: "", // Disabled until a URL is configured
+			Timeout:    10 * time.Second,
+
+			FailureThreshold: 0.5,
+			StaleAfter:       6 * time.Hour,
+		},
+		Storage: StorageConfig{
+			Driver: "", // Results are only written to the integration files
+			Postgres: PostgresConfig{
+				SQLDriver: "pgx",
+				MaxConns:  4,
+				BatchSize: 500,
+			},
+			Influx: InfluxConfig{
+				Enabled:     false,
+				Measurement: "weather",
+				Forecast:    true,
+			},
+			Timeseries: TimeseriesConfig{
+				Enabled:      false, // The Python app maintains the history files unless the Go pipeline is used
+				Directory:    "data/intelligence/timeseries",
+				MaxReadings:  1000,
+				MaxForecasts: 72,
+			},
 		},
 	}
 }
@@ -168,6 +280,96 @@ func validateConfig(cfg *Config) error {
 		}
 	}
 
+	if cfg.API.MaxForecastHours < 0 {
+		return ValidationError{
+			Field:   "api.max_forecast_hours",
+			Value:   cfg.API.MaxForecastHours,
+			Message: "max forecast hours cannot be negative",
+		}
+	}
+
+	if cfg.API.BreakerThreshold > 0 && cfg.API.BreakerCooldown <= 0 {
+		return ValidationError{
+			Field:   "api.breaker_cooldown",
+			Value:   cfg.API.BreakerCooldown,
+			Message: "circuit breaker cooldown must be positive when the breaker is enabled",
+		}
+	}
+
+	if (cfg.API.Provider == "mock" || cfg.API.Record) && cfg.API.FixturesDir == "" {
+		return ValidationError{
+			Field:   "api.fixtures_dir",
+			Value:   cfg.API.FixturesDir,
+			Message: "fixtures directory cannot be empty when replaying or recording fixtures",
+		}
+	}
+
+	if cfg.API.Provider == "mock" && cfg.API.Record {
+		return ValidationError{
+			Field:   "api.record",
+			Value:   cfg.API.Record,
+			Message: "cannot record fixtures while the mock provider is replaying them",
+		}
+	}
+
+	if cfg.API.Provider == "openmeteo" {
+		if cfg.API.OpenMeteo.ForecastURL == "" {
+			return ValidationError{
+				Field:   "api.open_meteo.forecast_url",
+				Value:   cfg.API.OpenMeteo.ForecastURL,
+				Message: "Open-Meteo forecast URL cannot be empty",
+			}
+		}
+
+		if cfg.API.OpenMeteo.ForecastDays < 1 || cfg.API.OpenMeteo.ForecastDays > 16 {
+			return ValidationError{
+				Field:   "api.open_meteo.forecast_days",
+				Value:   cfg.API.OpenMeteo.ForecastDays,
+				Message: "forecast days must be 1-16",
+			}
+		}
+
+		if cfg.API.OpenMeteo.HistoryDays < 0 {
+			return ValidationError{
+				Field:   "api.open_meteo.history_days",
+				Value:   cfg.API.OpenMeteo.HistoryDays,
+				Message: "history days cannot be negative",
+			}
+		}
+
+		if cfg.API.OpenMeteo.HistoryDays > 0 && cfg.API.OpenMeteo.ArchiveURL == "" {
+			return ValidationError{
+				Field:   "api.open_meteo.archive_url",
+				Value:   cfg.API.OpenMeteo.ArchiveURL,
+				Message: "Open-Meteo archive URL is required when history days are requested",
+			}
+		}
+	}
+
+	if cfg.API.MetAlerts.Enabled && cfg.API.MetAlerts.URL == "" {
+		return ValidationError{
+			Field:   "api.metalerts.url",
+			Value:   cfg.API.MetAlerts.URL,
+			Message: "MetAlerts URL cannot be empty when alerts are enabled",
+		}
+	}
+
+	if cfg.API.Nowcast.Enabled && cfg.API.Nowcast.URL == "" {
+		return ValidationError{
+			Field:   "api.nowcast.url",
+			Value:   cfg.API.Nowcast.URL,
+			Message: "Nowcast URL cannot be empty when nowcast is enabled",
+		}
+	}
+
+	if cfg.API.Ocean.Enabled && cfg.API.Ocean.URL == "" {
+		return ValidationError{
+			Field:   "api.ocean.url",
+			Value:   cfg.API.Ocean.URL,
+			Message: "Ocean forecast URL cannot be empty when marine collection is enabled",
+		}
+	}
+
 	// Validate Performance configuration
 	if cfg.Performance.MaxWorkers <= 0 {
 		return ValidationError{
@@ -185,6 +387,40 @@ func validateConfig(cfg *Config) error {
 		}
 	}
 
+	if cfg.Performance.Autoscale {
+		if cfg.Performance.MinWorkers <= 0 || cfg.Performance.MinWorkers > cfg.Performance.MaxWorkers {
+			return ValidationError{
+				Field:   "performance.min_workers",
+				Value:   cfg.Performance.MinWorkers,
+				Message: "min workers must be between 1 and max workers",
+			}
+		}
+
+		if cfg.Performance.TargetLatency <= 0 {
+			return ValidationError{
+				Field:   "performance.target_latency",
+				Value:   cfg.Performance.TargetLatency,
+				Message: "target latency must be positive when autoscaling",
+			}
+		}
+
+		if cfg.Performance.MaxErrorRate <= 0 || cfg.Performance.MaxErrorRate > 1 {
+			return ValidationError{
+				Field:   "performance.max_error_rate",
+				Value:   cfg.Performance.MaxErrorRate,
+				Message: "max error rate must be between 0 and 1",
+			}
+		}
+	}
+
+	if cfg.Performance.RunTimeout < 0 {
+		return ValidationError{
+			Field:   "performance.run_timeout",
+			Value:   cfg.Performance.RunTimeout,
+			Message: "run timeout cannot be negative (use 0 for no deadline)",
+		}
+	}
+
 	// Validate Integration configuration
 	if cfg.Integration.InputFile == "" || cfg.Integration.OutputFile == "" {
 		return ValidationError{
@@ -194,6 +430,291 @@ func validateConfig(cfg *Config) error {
 		}
 	}
 
+	switch cfg.Integration.OutputFormat {
+	case "", "json", "jsonl", "csv":
+	default:
+		return ValidationError{
+			Field:   "integration.output_format",
+			Value:   cfg.Integration.OutputFormat,
+			Message: "output format must be \"json\", \"jsonl\" or \"csv\"",
+		}
+	}
+
+	if cfg.Integration.Handshake && cfg.Integration.HandshakeTimeout <= 0 {
+		return ValidationError{
+			Field:   "integration.handshake_timeout",
+			Value:   cfg.Integration.HandshakeTimeout,
+			Message: "handshake timeout must be positive when the handshake is enabled",
+		}
+	}
+
+	// Validate Collection configuration
+	if cfg.Collection.ForecastHours < 0 {
+		return ValidationError{
+			Field:   "collection.forecast_hours",
+			Value:   cfg.Collection.ForecastHours,
+			Message: "forecast hours cannot be negative",
+		}
+	}
+
+	if cfg.Collection.Resolution < 0 || cfg.Collection.Resolution%time.Hour != 0 {
+		return ValidationError{
+			Field:   "collection.resolution",
+			Value:   cfg.Collection.Resolution,
+			Message: "resolution must be a whole number of hours",
+		}
+	}
+
+	// Validate Cache configuration
+	if cfg.Cache.Enabled && cfg.Cache.Directory == "" {
+		return ValidationError{
+			Field:   "cache.directory",
+			Value:   cfg.Cache.Directory,
+			Message: "cache directory cannot be empty when the cache is enabled",
+		}
+	}
+
+	if cfg.Cache.TTL < 0 {
+		return ValidationError{
+			Field:   "cache.ttl",
+			Value:   cfg.Cache.TTL,
+			Message: "cache TTL cannot be negative",
+		}
+	}
+
+	// Validate Geocoding configuration
+	if cfg.Geocoding.Enabled && cfg.Geocoding.BaseURL == "" {
+		return ValidationError{
+			Field:   "geocoding.base_url",
+			Value:   cfg.Geocoding.BaseURL,
+			Message: "geocoding base URL cannot be empty when geocoding is enabled",
+		}
+	}
+
+	if cfg.Geocoding.MinInterval < 0 {
+		return ValidationError{
+			Field:   "geocoding.min_interval",
+			Value:   cfg.Geocoding.MinInterval,
+			Message: "geocoding min interval cannot be negative",
+		}
+	}
+
+	// Validate Schedule configuration
+	if cfg.Schedule.Cron != "" {
+		if _, err := scheduler.ParseCron(cfg.Schedule.Cron); err != nil {
+			return ValidationError{
+				Field:   "schedule.cron",
+				Value:   cfg.Schedule.Cron,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	if cfg.Schedule.Interval != 0 && cfg.Schedule.Interval < time.Minute {
+		return ValidationError{
+			Field:   "schedule.interval",
+			Value:   cfg.Schedule.Interval,
+			Message: "schedule interval must be at least 1 minute (API terms of service)",
+		}
+	}
+
+	if cfg.Schedule.MaxOutputFiles < 0 {
+		return ValidationError{
+			Field:   "schedule.max_output_files",
+			Value:   cfg.Schedule.MaxOutputFiles,
+			Message: "max output files cannot be negative (use 0 to keep all)",
+		}
+	}
+
+	// Validate Notifications configuration
+	if url := cfg.Notifications.WebhookURL; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return ValidationError{
+			Field:   "notifications.webhook_url",
+			Value:   url,
+			Message: "webhook URL must start with http:// or https://",
+		}
+	}
+
+	if url := cfg.Notifications.SlackWebhookURL; url != "" && !strings.HasPrefix(url, "https://") {
+		return ValidationError{
+			Field:   "notifications.slack_webhook_url",
+			Value:   url,
+			Message: "Slack webhook URL must start with https://",
+		}
+	}
+
+	if url := cfg.Notifications.DiscordWebhookURL; url != "" && !strings.HasPrefix(url, "https://") {
+		return ValidationError{
+			Field:   "notifications.discord_webhook_url",
+			Value:   url,
+			Message: "Discord webhook URL must start with https://",
+		}
+	}
+
+	if cfg.Notifications.FailureThreshold < 0 || cfg.Notifications.FailureThreshold > 1 {
+		return ValidationError{
+			Field:   "notifications.failure_threshold",
+			Value:   cfg.Notifications.FailureThreshold,
+			Message: "failure threshold must be between 0 and 1",
+		}
+	}
+
+	if cfg.Notifications.StaleAfter < 0 {
+		return ValidationError{
+			Field:   "notifications.stale_after",
+			Value:   cfg.Notifications.StaleAfter,
+			Message: "stale-after duration cannot be negative",
+		}
+	}
+
+	if cfg.Notifications.Timeout < 0 {
+		return ValidationError{
+			Field:   "notifications.timeout",
+			Value:   cfg.Notifications.Timeout,
+			Message: "notification timeout cannot be negative",
+		}
+	}
+
+	// Validate Storage configuration
+	switch cfg.Storage.Driver {
+	case "":
+	case "postgres":
+		if cfg.Storage.Postgres.DSN == "" {
+			return ValidationError{
+				Field:   "storage.postgres.dsn",
+				Value:   cfg.Storage.Postgres.DSN,
+				Message: "Postgres connection string cannot be empty when the postgres driver is selected",
+			}
+		}
+		if cfg.Storage.Postgres.SQLDriver == "" {
+			return ValidationError{
+				Field:   "storage.postgres.sql_driver",
+				Value:   cfg.Storage.Postgres.SQLDriver,
+				Message: "SQL driver name cannot be empty when the postgres driver is selected",
+			}
+		}
+		// 14 parameters per reading must stay below Postgres' limit of 65535 per statement
+		if cfg.Storage.Postgres.BatchSize < 1 || cfg.Storage.Postgres.BatchSize > 4000 {
+			return ValidationError{
+				Field:   "storage.postgres.batch_size",
+				Value:   cfg.Storage.Postgres.BatchSize,
+				Message: "batch size must be between 1 and 4000",
+			}
+		}
+	default:
+		return ValidationError{
+			Field:   "storage.driver",
+			Value:   cfg.Storage.Driver,
+			Message: "storage driver must be \"\" or \"postgres\"",
+		}
+	}
+
+	if influx := cfg.Storage.Influx; influx.Enabled {
+		if influx.URL == "" && influx.File == "" {
+			return ValidationError{
+				Field:   "storage.influx",
+				Value:   "url and file empty",
+				Message: "InfluxDB export needs a url, a file or both",
+			}
+		}
+		if influx.URL != "" && influx.Bucket == "" {
+			return ValidationError{
+				Field:   "storage.influx.bucket",
+				Value:   influx.Bucket,
+				Message: "bucket cannot be empty when writing to the InfluxDB API",
+			}
+		}
+		if influx.Measurement == "" {
+			return ValidationError{
+				Field:   "storage.influx.measurement",
+				Value:   influx.Measurement,
+				Message: "measurement cannot be empty",
+			}
+		}
+	}
+
+	if ts := cfg.Storage.Timeseries; ts.Enabled {
+		if ts.Directory == "" {
+			return ValidationError{
+				Field:   "storage.timeseries.directory",
+				Value:   ts.Directory,
+				Message: "directory cannot be empty when history files are enabled",
+			}
+		}
+		if ts.MaxReadings < 1 {
+			return ValidationError{
+				Field:   "storage.timeseries.max_readings",
+				Value:   ts.MaxReadings,
+				Message: "must keep at least 1 reading per location",
+			}
+		}
+		if ts.MaxForecasts < 0 {
+			return ValidationError{
+				Field:   "storage.timeseries.max_forecasts",
+				Value:   ts.MaxForecasts,
+				Message: "cannot be negative",
+			}
+		}
+	}
+
+	// Validate MQTT configuration
+	if cfg.MQTT.Enabled {
+		if cfg.MQTT.BrokerURL == "" {
+			return ValidationError{
+				Field:   "mqtt.broker_url",
+				Value:   cfg.MQTT.BrokerURL,
+				Message: "broker URL cannot be empty when MQTT publishing is enabled",
+			}
+		}
+		if cfg.MQTT.QoS < 0 || cfg.MQTT.QoS > 2 {
+			return ValidationError{
+				Field:   "mqtt.qos",
+				Value:   cfg.MQTT.QoS,
+				Message: "QoS must be 0, 1 or 2",
+			}
+		}
+		if cfg.MQTT.TopicPrefix == "" || strings.ContainsAny(cfg.MQTT.TopicPrefix, "+#") {
+			return ValidationError{
+				Field:   "mqtt.topic_prefix",
+				Value:   cfg.MQTT.TopicPrefix,
+				Message: "topic prefix must be set and cannot contain wildcards",
+			}
+		}
+	}
+
+	// Validate event bus configuration
+	switch cfg.Events.Driver {
+	case "":
+	case "nats", "kafka":
+		if cfg.Events.URL == "" {
+			return ValidationError{
+				Field:   "events.url",
+				Value:   cfg.Events.URL,
+				Message: "URL cannot be empty when an event bus is configured",
+			}
+		}
+		if !validSubject(cfg.Events.WeatherSubject) {
+			return ValidationError{
+				Field:   "events.weather_subject",
+				Value:   cfg.Events.WeatherSubject,
+				Message: "subject must be set and cannot contain whitespace or wildcards",
+			}
+		}
+		if !validSubject(cfg.Events.AnalysisSubject) {
+			return ValidationError{
+				Field:   "events.analysis_subject",
+				Value:   cfg.Events.AnalysisSubject,
+				Message: "subject must be set and cannot contain whitespace or wildcards",
+			}
+		}
+	default:
+		return ValidationError{
+			Field:   "events.driver",
+			Value:   cfg.Events.Driver,
+			Message: "event bus driver must be \"\", \"nats\" or \"kafka\"",
+		}
+	}
+
 	// Validate Logging configuration
 	if cfg.Logging.LogLevel < 0 || cfg.Logging.LogLevel > 3 {
 		return ValidationError{
@@ -203,9 +724,60 @@ func validateConfig(cfg *Config) error {
 		}
 	}
 
+	if cfg.Logging.LogFormat != "" && cfg.Logging.LogFormat != "text" && cfg.Logging.LogFormat != "json" {
+		return ValidationError{
+			Field:   "logging.log_format",
+			Value:   cfg.Logging.LogFormat,
+			Message: "log format must be \"text\" or \"json\"",
+		}
+	}
+
+	rotationLimits := []struct {
+		field    string
+		value    any
+		negative bool
+	}{
+		{"logging.log_max_size_mb", cfg.Logging.LogMaxSizeMB, cfg.Logging.LogMaxSizeMB < 0},
+		{"logging.log_rotate_interval", cfg.Logging.LogRotateInterval, cfg.Logging.LogRotateInterval < 0},
+		{"logging.log_max_backups", cfg.Logging.LogMaxBackups, cfg.Logging.LogMaxBackups < 0},
+		{"logging.log_max_age", cfg.Logging.LogMaxAge, cfg.Logging.LogMaxAge < 0},
+	}
+	for _, limit := range rotationLimits {
+		if limit.negative {
+			return ValidationError{
+				Field:   limit.field,
+				Value:   limit.value,
+				Message: "log rotation settings cannot be negative (use 0 to disable)",
+			}
+		}
+	}
+
+	if cfg.Logging.LogRotateInterval > 0 && cfg.Logging.LogRotateInterval < time.Minute {
+		return ValidationError{
+			Field:   "logging.log_rotate_interval",
+			Value:   cfg.Logging.LogRotateInterval,
+			Message: "log rotate interval must be at least 1 minute",
+		}
+	}
+
+	// Validate Units configuration
+	if _, err := cfg.Units.Resolve(); err != nil {
+		return ValidationError{
+			Field:   "units",
+			Value:   cfg.Units,
+			Message: err.Error(),
+		}
+	}
+
 	return nil
 }
 
+// validSubject reports whether s can be published to as a NATS subject or Kafka topic:
+// non-empty, without whitespace and without the NATS wildcards "*" and ">"
+func validSubject(s string) bool {
+	return s != "" && !strings.ContainsAny(s, " \t\r\n*>")
+}
+
 // EnsureDirectories creates necessary directories if they don't exist
 func (c *Config) EnsureDirectories() error {
 	if c.Integration.CreateDirs {
@@ -225,12 +797,23 @@ func (c *Config) GetInputFilePath() string {
 	return c.Integration.InputFile
 }
 
-// GetOutputFilePath returns the full path to the output file
+// OutputUnits returns the units results are written out in (metric for a units section that
+// fails validation)
+func (c *Config) OutputUnits() units.System {
+	system, err := c.Units.Resolve()
+	if err != nil {
+		return units.Metric()
+	}
+	return system
+}
+
+// GetOutputFilePath returns the full path to the output file, with ".gz" appended when compressed
 func (c *Config) GetOutputFilePath() string {
-	if filepath.IsAbs(c.Integration.OutputFile) {
-		return c.Integration.OutputFile
+	path := c.Integration.OutputFile
+	if c.Integration.Compress && !strings.HasSuffix(path, ".gz") {
+		path += ".gz"
 	}
-	return c.Integration.OutputFile
+	return path
 }
 
 // SaveToFile saves the current configuration to a JSON file
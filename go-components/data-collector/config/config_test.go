@@ -4,6 +4,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"weathermodels/units"
 )
 
 // TestGetDefaultConfig tests that default configuration is valid
@@ -186,6 +188,114 @@ func TestConfigValidation(t *testing.T) {
 			},
 			shouldError: true,
 		},
+		{
+			name: "Invalid cron expression",
+			modifyFunc: func(c *Config) {
+				c.Schedule.Cron = "*/30 * * *"
+			},
+			shouldError: true,
+		},
+		{
+			name: "Valid cron expression",
+			modifyFunc: func(c *Config) {
+				c.Schedule.Cron = "*/30 * * * *"
+			},
+			shouldError: false,
+		},
+		{
+			name: "Recording with the mock provider",
+			modifyFunc: func(c *Config) {
+				c.API.Provider = "mock"
+				c.API.Record = true
+			},
+			shouldError: true,
+		},
+		{
+			name: "Mock provider without fixtures directory",
+			modifyFunc: func(c *Config) {
+				c.API.Provider = "mock"
+				c.API.FixturesDir = ""
+			},
+			shouldError: true,
+		},
+		{
+			name: "Autoscale min workers above max",
+			modifyFunc: func(c *Config) {
+				c.Performance.Autoscale = true
+				c.Performance.MinWorkers = c.Performance.MaxWorkers + 1
+			},
+			shouldError: true,
+		},
+		{
+			name: "Fractional-hour resolution",
+			modifyFunc: func(c *Config) {
+				c.Collection.Resolution = 90 * time.Minute
+			},
+			shouldError: true,
+		},
+		{
+			name: "24h horizon at 6h resolution",
+			modifyFunc: func(c *Config) {
+				c.Collection.ForecastHours = 24
+				c.Collection.Resolution = 6 * time.Hour
+			},
+			shouldError: false,
+		},
+		{
+			name: "Webhook URL without scheme",
+			modifyFunc: func(c *Config) {
+				c.Notifications.WebhookURL = "hooks.example.com/weather"
+			},
+			shouldError: true,
+		},
+		{
+			name: "Postgres storage without DSN",
+			modifyFunc: func(c *Config) {
+				c.Storage.Driver = "postgres"
+			},
+			shouldError: true,
+		},
+		{
+			name: "MQTT QoS out of range",
+			modifyFunc: func(c *Config) {
+				c.MQTT.Enabled = true
+				c.MQTT.QoS = 3
+			},
+			shouldError: true,
+		},
+		{
+			name: "Unknown event bus driver",
+			modifyFunc: func(c *Config) {
+				c.Events.Driver = "rabbitmq"
+				c.Events.URL = "amqp://localhost"
+			},
+			shouldError: true,
+		},
+		{
+			name: "Event subject with wildcard",
+			modifyFunc: func(c *Config) {
+				c.Events.Driver = "nats"
+				c.Events.URL = "nats://localhost:4222"
+				c.Events.WeatherSubject = "weather.>"
+			},
+			shouldError: true,
+		},
+		{
+			name: "Timeseries without readings",
+			modifyFunc: func(c *Config) {
+				c.Storage.Timeseries.Enabled = true
+				c.Storage.Timeseries.MaxReadings = 0
+			},
+			shouldError: true,
+		},
+		{
+			name: "Negative forecasts kept",
+			modifyFunc: func(c *Config) {
+				c.Storage.Timeseries.Enabled = true
+				c.Storage.Timeseries.MaxForecasts = -1
+			},
+			shouldError: true,
+		},
 		{
 			name: "Invalid log level",
 			modifyFunc: func(c *Config) {
@@ -193,6 +303,20 @@ func TestConfigValidation(t *testing.T) {
 			},
 			shouldError: true,
 		},
+		{
+			name: "Imperial units with knots",
+			modifyFunc: func(c *Config) {
+				c.Units = units.Config{System: "imperial", WindSpeed: "kn"}
+			},
+			shouldError: false,
+		},
+		{
+			name: "Unknown wind speed unit",
+			modifyFunc: func(c *Config) {
+				c.Units.WindSpeed = "beaufort"
+			},
+			shouldError: true,
+		},
 	}
 
 	for _, tt := range tests {
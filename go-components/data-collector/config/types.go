@@ -3,24 +3,88 @@ package config
 import (
 	"fmt"
 	"time"
+
+	"weathermodels/units"
 )
 
 // Config represents the complete configuration for the data collector service
 type Config struct {
 	API         APIConfig         `json:"api"`
 	Integration IntegrationConfig `json:"integration"`
+	Collection  CollectionConfig  `json:"collection"`
 	Performance PerformanceConfig `json:"performance"`
 	Logging     LoggingConfig     `json:"logging"`
+	Cache       CacheConfig       `json:"cache"`
+	Geocoding   GeocodingConfig   `json:"geocoding"`
+	Schedule    SchedulerConfig   `json:"schedule"`
+	Server      ServerConfig      `json:"server"`
+
+	Notifications NotificationsConfig `json:"notifications"`
+	Storage       StorageConfig       `json:"storage"`
+	MQTT          MQTTConfig          `json:"mqtt"`
+	Events        EventsConfig        `json:"events"`
+
+	// Units of the pipe and REST results and of CSV output. The JSON output file, storage, history
+	// files and the MQTT and event bus messages stay metric, as the Python side and the pattern
+	// engine read them as such.
+	Units units.Config `json:"units"`
 }
 
 // APIConfig contains all settings for external API calls (met.no, etc.)
 type APIConfig struct {
+	Provider   string        `json:"provider"`    // Weather provider name ("metno", "openmeteo" or "mock")
 	BaseURL    string        `json:"base_url"`    // API endpoint URL
 	UserAgent  string        `json:"user_agent"`  // HTTP User-Agent header
 	Timeout    time.Duration `json:"timeout"`     // Request timeout
 	MaxRetries int           `json:"max_retries"` // Number of retry attempts
-	RateLimit  int           `json:"rate_limit"`  // Max requests per second
+	RateLimit  int           `json:"rate_limit"`  // Max requests per second per API host (0 = unlimited)
 	RetryDelay time.Duration `json:"retry_delay"` // Delay between retries
+
+	MaxForecastHours int `json:"max_forecast_hours"` // Stop decoding met.no timeseries this many hours past the first entry (0 = all)
+
+	FixturesDir string `json:"fixtures_dir"` // Recorded API responses replayed by the "mock" provider
+	Record      bool   `json:"record"`       // Save live API responses into fixtures_dir
+
+	BreakerThreshold int           `json:"breaker_threshold"` // Consecutive endpoint failures before the circuit opens (0 disables)
+	BreakerCooldown  time.Duration `json:"breaker_cooldown"`  // Time the circuit stays open before a probe request
+
+	OpenMeteo OpenMeteoConfig `json:"open_meteo"` // Settings for the Open-Meteo provider
+	MetAlerts MetAlertsConfig `json:"metalerts"`  // Settings for official weather warnings
+	Nowcast   NowcastConfig   `json:"nowcast"`    // Settings for short-term precipitation nowcasts
+	Ocean     OceanConfig     `json:"ocean"`      // Settings for marine forecasts at coastal locations
+}
+
+// MetAlertsConfig contains settings for the met.no MetAlerts (severe weather warnings) API
+type MetAlertsConfig struct {
+	Enabled  bool   `json:"enabled"`  // Attach active warnings to each successful result
+	URL      string `json:"url"`      // MetAlerts current.json endpoint URL
+	Language string `json:"language"` // Warning text language ("en" or "no")
+}
+
+// NowcastConfig contains settings for the met.no nowcast (5-minute precipitation) API
+type NowcastConfig struct {
+	Enabled bool   `json:"enabled"` // Attach a nowcast to each successful result (Nordic locations only)
+	URL     string `json:"url"`     // Nowcast endpoint URL
+}
+
+// OceanConfig contains settings for the met.no oceanforecast (waves, sea temperature, currents) API
+type OceanConfig struct {
+	Enabled bool   `json:"enabled"` // Attach an ocean forecast to results for locations flagged as coastal
+	URL     string `json:"url"`     // Oceanforecast endpoint URL
+}
+
+// OpenMeteoConfig contains settings for the keyless Open-Meteo forecast and archive APIs
+type OpenMeteoConfig struct {
+	ForecastURL  string `json:"forecast_url"`  // Forecast API endpoint URL
+	ArchiveURL   string `json:"archive_url"`   // Historical archive API endpoint URL
+	ForecastDays int    `json:"forecast_days"` // Days of hourly forecast to request (1-16)
+	HistoryDays  int    `json:"history_days"`  // Days of hourly history to request (0 disables the archive)
+}
+
+// CollectionConfig contains settings for how much of each forecast is kept
+type CollectionConfig struct {
+	ForecastHours int           `json:"forecast_hours"` // Keep forecast points up to this many hours ahead, e.g. 24 or 48 (0 = everything the provider returns)
+	Resolution    time.Duration `json:"resolution"`     // Keep one forecast point per step on the UTC clock, e.g. 1h or 6h (0 = provider resolution)
 }
 
 // IntegrationConfig contains settings for Python ↔ Go communication
@@ -29,6 +93,14 @@ type IntegrationConfig struct {
 	OutputFile    string `json:"output_file"`    // Where Go writes weather results
 	DataDirectory string `json:"data_directory"` // Base directory for integration files
 	CreateDirs    bool   `json:"create_dirs"`    // Auto-create directories if missing
+	OutputFormat  string `json:"output_format"`  // "json" (one array at the end), "jsonl" (one line per result as it completes) or "csv"
+	CSVForecast   bool   `json:"csv_forecast"`   // With CSV output, add a row per forecast point
+	Compress      bool   `json:"compress"`       // Gzip the output file (".gz" is appended to output_file)
+	SummaryFile   string `json:"summary_file"`   // Machine-readable run summary written after each collection ("" disables)
+
+	// File handshake: writers add a "<file>.done" marker once a file is complete (see package integration)
+	Handshake        bool          `json:"handshake"`         // Wait for the input marker and write an output marker
+	HandshakeTimeout time.Duration `json:"handshake_timeout"` // How long to wait for the input marker
 }
 
 // PerformanceConfig contains settings for concurrent operations and optimization
@@ -37,14 +109,141 @@ type PerformanceConfig struct {
 	WorkerTimeout   time.Duration `json:"worker_timeout"`   // Timeout per worker operation
 	CollectionDelay time.Duration `json:"collection_delay"` // Delay between API calls (rate limiting)
 	BufferSize      int           `json:"buffer_size"`      // Channel buffer size for worker communication
+	RunTimeout      time.Duration `json:"run_timeout"`      // Overall deadline for a collection run (0 = no deadline)
+
+	// Adaptive concurrency: start at min_workers, add a worker while latency and errors stay low,
+	// halve when either spikes (never above max_workers)
+	Autoscale     bool          `json:"autoscale"`      // Adapt concurrency instead of always running max_workers
+	MinWorkers    int           `json:"min_workers"`    // Starting and lowest concurrency in autoscale mode
+	TargetLatency time.Duration `json:"target_latency"` // Average per-location latency above which concurrency is halved
+	MaxErrorRate  float64       `json:"max_error_rate"` // Fraction of load-related failures (0-1) above which concurrency is halved
+}
+
+// CacheConfig contains settings for the persistent on-disk API response cache
+type CacheConfig struct {
+	Enabled   bool          `json:"enabled"`   // Persist API responses between runs
+	Directory string        `json:"directory"` // Where cached responses are stored
+	TTL       time.Duration `json:"ttl"`       // How long a response is reused without re-hitting the API
+}
+
+// GeocodingConfig contains settings for resolving name-only locations to coordinates
+type GeocodingConfig struct {
+	Enabled     bool          `json:"enabled"`      // Resolve locations that have a name but no lat/lon
+	BaseURL     string        `json:"base_url"`     // Nominatim-compatible search endpoint
+	CacheFile   string        `json:"cache_file"`   // On-disk cache of resolved names ("" = in-memory only)
+	MinInterval time.Duration `json:"min_interval"` // Minimum time between geocoding requests (Nominatim allows 1/s)
+}
+
+// SchedulerConfig contains settings for daemon mode (continuous collection)
+type SchedulerConfig struct {
+	Cron           string        `json:"cron"`             // Cron expression, e.g. "*/30 * * * *" (takes precedence over interval)
+	Interval       time.Duration `json:"interval"`         // Time between collection runs
+	MaxOutputFiles int           `json:"max_output_files"` // Timestamped output files to keep (0 = keep all)
+}
+
+// ServerConfig contains settings for serve mode (REST collection API) and gRPC mode
+type ServerConfig struct {
+	Address         string        `json:"address"`          // Listen address, e.g. ":8080"
+	GRPCAddress     string        `json:"grpc_address"`     // Listen address for gRPC mode, e.g. ":50051"
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"` // Time allowed for in-flight requests on shutdown
+}
+
+// StorageConfig contains settings for persisting collected readings in a database
+type StorageConfig struct {
+	Driver   string         `json:"driver"`   // Storage backend: "" (files only) or "postgres"
+	Postgres PostgresConfig `json:"postgres"` // Settings for the "postgres" driver
+	Influx   InfluxConfig   `json:"influx"`   // InfluxDB line-protocol export (independent of driver)
+
+	Timeseries TimeseriesConfig `json:"timeseries"` // Per-location history files read by the pattern engine
+}
+
+// TimeseriesConfig contains settings for merging each run's current readings into the per-location
+// history files the pattern engine analyzes (the layout written by utils/intelligence_persistence.py)
+type TimeseriesConfig struct {
+	Enabled     bool   `json:"enabled"`      // Merge results into the history files after each collection
+	Directory   string `json:"directory"`    // Directory of the "<location>.json" history files
+	MaxReadings int    `json:"max_readings"` // Newest readings kept per location
+
+	// Newest forecasts kept per location in "<directory>/forecasts/<location>.json", which the
+	// pattern engine verifies against the readings observed later (0 = forecasts are not kept)
+	MaxForecasts int `json:"max_forecasts"`
+}
+
+// InfluxConfig contains settings for exporting readings as InfluxDB line protocol,
+// either through the InfluxDB v2 HTTP write API or to a file
+type InfluxConfig struct {
+	Enabled     bool   `json:"enabled"`     // Export readings after each collection
+	URL         string `json:"url"`         // InfluxDB base URL, e.g. "http://localhost:8086" ("" writes to file only)
+	Org         string `json:"org"`         // Organization for the write API
+	Bucket      string `json:"bucket"`      // Bucket for the write API
+	Token       string `json:"token"`       // API token sent as "Authorization: Token ..."
+	File        string `json:"file"`        // Append line protocol to this file ("" = HTTP only)
+	Measurement string `json:"measurement"` // Measurement name for every line
+	Forecast    bool   `json:"forecast"`    // Also export forecast points (tagged kind=forecast)
+}
+
+// PostgresConfig contains connection and write settings for PostgreSQL / TimescaleDB storage
+type PostgresConfig struct {
+	DSN       string `json:"dsn"`        // Connection string, e.g. "postgres://weather:secret@db:5432/weather?sslmode=disable"
+	SQLDriver string `json:"sql_driver"` // database/sql driver: "pgx", linked in, or one registered by the binary
+	MaxConns  int    `json:"max_conns"`  // Maximum open connections
+	BatchSize int    `json:"batch_size"` // Readings per INSERT statement
+	Timescale bool   `json:"timescale"`  // Convert the readings table into a TimescaleDB hypertable
+}
+
+// MQTTConfig contains settings for publishing current conditions to an MQTT broker after each collection
+type MQTTConfig struct {
+	Enabled     bool          `json:"enabled"`      // Publish after each collection
+	BrokerURL   string        `json:"broker_url"`   // e.g. "tcp://localhost:1883" or "mqtts://broker:8883"
+	ClientID    string        `json:"client_id"`    // MQTT client identifier
+	Username    string        `json:"username"`     // Broker user name ("" = anonymous)
+	Password    string        `json:"password"`     // Broker password
+	TopicPrefix string        `json:"topic_prefix"` // Messages go to "<topic_prefix>/<location>/current"
+	QoS         int           `json:"qos"`          // Delivery guarantee: 0, 1 or 2
+	Retain      bool          `json:"retain"`       // Ask the broker to keep the last message per topic
+	Timeout     time.Duration `json:"timeout"`      // Time allowed to connect and publish a run
+}
+
+// EventsConfig contains settings for publishing every result to an event bus after each collection.
+// The pattern engine reads the same section to publish its analyses.
+type EventsConfig struct {
+	Driver          string        `json:"driver"`           // Event bus: "" (disabled), "nats" or "kafka"
+	URL             string        `json:"url"`              // NATS server ("nats://host:4222", "tls://host:4222") or Kafka REST Proxy base URL
+	WeatherSubject  string        `json:"weather_subject"`  // NATS subject or Kafka topic for weather results
+	AnalysisSubject string        `json:"analysis_subject"` // NATS subject or Kafka topic for analysis results (pattern engine)
+	Username        string        `json:"username"`         // User name ("" = anonymous)
+	Password        string        `json:"password"`         // Password
+	Token           string        `json:"token"`            // NATS authentication token
+	Timeout         time.Duration `json:"timeout"`          // Time allowed to connect and publish a run
+}
+
+// NotificationsConfig contains settings for run notifications sent when a collection or daemon cycle ends
+// and for operator alerts on failing or stale locations
+type NotificationsConfig struct {
+	WebhookURL string        `json:"webhook_url"` // URL the run summary is POSTed to as JSON ("" disables)
+	Timeout    time.Duration `json:"timeout"`     // Time allowed for each delivery
+
+	// Operator alerts, posted to every configured chat webhook
+	SlackWebhookURL   string        `json:"slack_webhook_url"`   // Slack incoming webhook URL ("" disables)
+	DiscordWebhookURL string        `json:"discord_webhook_url"` // Discord webhook URL ("" disables)
+	FailureThreshold  float64       `json:"failure_threshold"`   // Alert when more than this fraction (0-1) of a run's locations fail (0 disables)
+	StaleAfter        time.Duration `json:"stale_after"`         // Alert when a location has had no successful collection for this long in daemon mode (0 disables)
 }
 
 // LoggingConfig contains logging and debugging preferences
 type LoggingConfig struct {
-	EnableDebug   bool `json:"enable_debug"`   // Show detailed debug logs
-	EnableMetrics bool `json:"enable_metrics"` // Show performance metrics
-	LogToFile     bool `json:"log_to_file"`    // Write logs to file (vs stdout only)
-	LogLevel      int  `json:"log_level"`      // Log level (0=Error, 1=Warn, 2=Info, 3=Debug)
+	EnableDebug   bool   `json:"enable_debug"`   // Show detailed debug logs
+	EnableMetrics bool   `json:"enable_metrics"` // Show performance metrics
+	LogToFile     bool   `json:"log_to_file"`    // Write logs to file (vs stdout only)
+	LogFile       string `json:"log_file"`       // Log file path when log_to_file is set
+	LogLevel      int    `json:"log_level"`      // Log level (0=Error, 1=Warn, 2=Info, 3=Debug)
+	LogFormat     string `json:"log_format"`     // Log output format: "text" or "json"
+
+	// Log file rotation; zero disables the corresponding limit
+	LogMaxSizeMB      int           `json:"log_max_size_mb"`     // Rotate once the file reaches this size
+	LogRotateInterval time.Duration `json:"log_rotate_interval"` // Rotate at each interval boundary (e.g. daily)
+	LogMaxBackups     int           `json:"log_max_backups"`     // Rotated files to keep
+	LogMaxAge         time.Duration `json:"log_max_age"`         // Delete rotated files older than this
 }
 
 // ValidationError represents configuration validation errors
@@ -0,0 +1,76 @@
+// Package events publishes every weather result to an event bus, a NATS subject or a Kafka topic,
+// so downstream consumers can react as soon as a collection finishes instead of scanning the
+// output directories. The bus clients are those of weathermodels/eventbus.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weathermodels/eventbus"
+)
+
+// Publisher publishes each WeatherResult of a run, successful or not, as one JSON message
+type Publisher struct {
+	Bus     eventbus.Bus
+	Subject string        // NATS subject or Kafka topic
+	Timeout time.Duration // Time allowed for connecting and publishing a whole run (0 = no limit)
+}
+
+// NewPublisher creates a publisher from the events configuration
+func NewPublisher(cfg *config.Config) *Publisher {
+	events := cfg.Events
+	return &Publisher{
+		Bus: eventbus.Bus{
+			Driver: events.Driver,
+			NATS: eventbus.NATSOptions{
+				URL:      events.URL,
+				Name:     "weather-collector",
+				Username: events.Username,
+				Password: events.Password,
+				Token:    events.Token,
+			},
+			Kafka: &eventbus.KafkaProducer{
+				URL:       events.URL,
+				Username:  events.Username,
+				Password:  events.Password,
+				UserAgent: cfg.API.UserAgent,
+				Client:    &http.Client{},
+			},
+		},
+		Subject: events.WeatherSubject,
+		Timeout: events.Timeout,
+	}
+}
+
+// Save publishes every result of a run, keyed by location name
+func (p *Publisher) Save(ctx context.Context, results []collector.WeatherResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	messages := make([]eventbus.Message, len(results))
+	for i, result := range results {
+		value, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode event for %s: %w", result.Location.Name, err)
+		}
+		messages[i] = eventbus.Message{Key: result.Location.Name, Value: value}
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	return p.Bus.Publish(ctx, p.Subject, messages)
+}
+
+// Close implements storage.Store; connections only last for one Save
+func (p *Publisher) Close() error {
+	return nil
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+	"weathermodels/eventbus"
+)
+
+// TestPublisherSave tests that every result, including failures, is produced to the weather
+// topic keyed by location
+func TestPublisherSave(t *testing.T) {
+	var body struct {
+		Records []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/weather.results" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":10},{"partition":1,"offset":4}]}`))
+	}))
+	defer server.Close()
+
+	publisher := &Publisher{
+		Bus:     eventbus.Bus{Driver: eventbus.DriverKafka, Kafka: &eventbus.KafkaProducer{URL: server.URL}},
+		Subject: "weather.results",
+		Timeout: 5 * time.Second,
+	}
+	results := []collector.WeatherResult{
+		{Location: collector.Location{Name: "Oslo"}, CurrentWeather: collector.WeatherPoint{Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), Temperature: 9.5}, Success: true},
+		{Location: collector.Location{Name: "Broken"}, Success: false, Error: "timeout"},
+	}
+	if err := publisher.Save(context.Background(), results); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if len(body.Records) != 2 || body.Records[0].Key != "Oslo" || body.Records[1].Key != "Broken" {
+		t.Fatalf("Expected records keyed by location, got %+v", body.Records)
+	}
+	var failed collector.WeatherResult
+	if err := json.Unmarshal(body.Records[1].Value, &failed); err != nil || failed.Success || failed.Error != "timeout" {
+		t.Errorf("Expected the failed result as record value, got %s (err: %v)", body.Records[1].Value, err)
+	}
+}
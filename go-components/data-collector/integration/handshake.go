@@ -0,0 +1,155 @@
+// Package integration implements the file handshake used between the Python orchestrator
+// and the Go collector.
+//
+// Each exchange file (input_locations.json, output_weather.json) has a companion marker,
+// "<file>.done", that the writer creates only after the data file is complete:
+//
+//  1. The writer removes any existing marker.
+//  2. The writer writes the data file (atomically, via temp file + rename).
+//  3. The writer writes the marker: {"size": ..., "sha256": ..., "written_at": ...}.
+//
+// A reader waits for the marker, reads the data file, and only accepts it when its size and
+// SHA-256 match the marker. A mismatch means the writer started a new cycle in between, so
+// the reader waits and tries again. Readers never delete markers, so a file that does not
+// change can be read any number of times (e.g. by the daemon on every cycle).
+package integration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"weathermodels/fileio"
+)
+
+// DoneSuffix is appended to a data file path to form its marker path
+const DoneSuffix = ".done"
+
+// pollInterval is how often a reader checks for the marker
+const pollInterval = 100 * time.Millisecond
+
+// Marker describes a completely written data file
+type Marker struct {
+	Size      int64     `json:"size"`       // Data file size in bytes
+	SHA256    string    `json:"sha256"`     // Hex-encoded SHA-256 of the data file
+	WrittenAt time.Time `json:"written_at"` // When the writer finished
+}
+
+// MarkerPath returns the marker path for a data file
+func MarkerPath(path string) string {
+	return path + DoneSuffix
+}
+
+// NewMarker describes data as it will be written to disk
+func NewMarker(data []byte) Marker {
+	sum := sha256.Sum256(data)
+	return Marker{
+		Size:      int64(len(data)),
+		SHA256:    hex.EncodeToString(sum[:]),
+		WrittenAt: time.Now().UTC(),
+	}
+}
+
+// Matches reports whether data is the file the marker describes
+func (m Marker) Matches(data []byte) bool {
+	sum := sha256.Sum256(data)
+	return int64(len(data)) == m.Size && hex.EncodeToString(sum[:]) == m.SHA256
+}
+
+// WriteFile writes data to path following the handshake protocol
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := ClearMarker(path); err != nil {
+		return err
+	}
+	if err := fileio.WriteFileAtomic(path, data, perm); err != nil {
+		return err
+	}
+	return writeMarker(path, data, perm)
+}
+
+// ClearMarker removes path's marker, signalling readers that a new version is being written.
+// Writers that build a file incrementally call this before they start.
+func ClearMarker(path string) error {
+	if err := os.Remove(MarkerPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale marker: %w", err)
+	}
+	return nil
+}
+
+// MarkComplete writes the marker for a file that was built incrementally and is now complete
+func MarkComplete(path string, perm os.FileMode) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return writeMarker(path, data, perm)
+}
+
+// writeMarker atomically writes the marker describing data
+func writeMarker(path string, data []byte, perm os.FileMode) error {
+	markerData, err := json.Marshal(NewMarker(data))
+	if err != nil {
+		return err
+	}
+	return fileio.WriteFileAtomic(MarkerPath(path), markerData, perm)
+}
+
+// ReadFile waits for path's marker and returns the data file once it matches the marker.
+// It gives up when ctx is done.
+func ReadFile(ctx context.Context, path string) ([]byte, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		data, err := readIfComplete(path)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, errNotReady) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for %s: %w", MarkerPath(path), context.Cause(ctx))
+		case <-ticker.C:
+		}
+	}
+}
+
+// errNotReady means the writer has not finished (no marker yet, or the file changed under us)
+var errNotReady = errors.New("file not ready")
+
+// readIfComplete reads path if its marker exists and matches the contents
+func readIfComplete(path string) ([]byte, error) {
+	markerData, err := os.ReadFile(MarkerPath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotReady
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var marker Marker
+	if err := json.Unmarshal(markerData, &marker); err != nil {
+		// A marker is written atomically, so a bad one is not a partial write
+		return nil, fmt.Errorf("invalid marker %s: %w", MarkerPath(path), err)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotReady
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !marker.Matches(data) {
+		return nil, errNotReady
+	}
+	return data, nil
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWriteThenRead tests a complete handshake round trip
+func TestWriteThenRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output_weather.json")
+	if err := WriteFile(path, []byte(`[{"success": true}]`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := os.Stat(MarkerPath(path)); err != nil {
+		t.Fatalf("Expected marker to be written: %v", err)
+	}
+
+	data, err := ReadFile(context.Background(), path)
+	if err != nil || string(data) != `[{"success": true}]` {
+		t.Errorf("Expected written data, got %q (err: %v)", data, err)
+	}
+}
+
+// TestReadWaitsForMarker tests that a reader ignores a data file until its marker appears
+func TestReadWaitsForMarker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input_locations.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "Oslo"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		WriteFile(path, []byte(`[{"name": "Oslo"}]`), 0644)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, err := ReadFile(ctx, path)
+	if err != nil || string(data) != `[{"name": "Oslo"}]` {
+		t.Errorf("Expected the completed file, got %q (err: %v)", data, err)
+	}
+}
+
+// TestReadRejectsMismatchedFile tests that a file not matching its marker is never returned
+func TestReadRejectsMismatchedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output_weather.json")
+	if err := WriteFile(path, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a writer that replaced the data but has not written the new marker yet
+	if err := os.WriteFile(path, []byte(`[{"partial"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	if data, err := ReadFile(ctx, path); err == nil {
+		t.Errorf("Expected a timeout for a mismatched file, got %q", data)
+	}
+}
@@ -0,0 +1,40 @@
+// Package logging configures the process-wide slog logger from the logging config
+package logging
+
+import (
+	"io"
+
+	"weather-collector/config"
+	"weathermodels/logfile"
+	"weathermodels/logging"
+)
+
+// DefaultLogFile is used when log_to_file is set without a log_file path
+const DefaultLogFile = "data/logs/collector.log"
+
+// Options converts the logging config to the shared logger options
+func Options(cfg config.LoggingConfig) logging.Options {
+	opts := logging.Options{
+		Level:  cfg.LogLevel,
+		Debug:  cfg.EnableDebug,
+		Format: cfg.LogFormat,
+		Rotation: logfile.RotationPolicy{
+			MaxSize:    int64(cfg.LogMaxSizeMB) * 1024 * 1024,
+			Interval:   cfg.LogRotateInterval,
+			MaxBackups: cfg.LogMaxBackups,
+			MaxAge:     cfg.LogMaxAge,
+		},
+	}
+	if cfg.LogToFile {
+		opts.File = cfg.LogFile
+		if opts.File == "" {
+			opts.File = DefaultLogFile
+		}
+	}
+	return opts
+}
+
+// Setup installs the configured logger as the slog default; see logging.Setup
+func Setup(cfg config.LoggingConfig) (io.Closer, error) {
+	return logging.Setup(Options(cfg))
+}
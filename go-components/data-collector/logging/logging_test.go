@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// TestOptions tests the conversion of the logging config to the shared logger options
+func TestOptions(t *testing.T) {
+	opts := Options(config.LoggingConfig{EnableDebug: true, LogFormat: "json", LogToFile: true, LogMaxSizeMB: 5, LogMaxAge: time.Hour})
+	if !opts.Debug || opts.Format != "json" || opts.File != DefaultLogFile {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if opts.Rotation.MaxSize != 5*1024*1024 || opts.Rotation.MaxAge != time.Hour {
+		t.Errorf("Unexpected rotation policy: %+v", opts.Rotation)
+	}
+
+	if opts := Options(config.LoggingConfig{LogFile: "collector.log"}); opts.File != "" {
+		t.Errorf("Expected no log file without log_to_file, got '%s'", opts.File)
+	}
+}
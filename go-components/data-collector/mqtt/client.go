@@ -0,0 +1,270 @@
+// Package mqtt publishes current conditions to an MQTT broker so home-automation systems can
+// subscribe to a feed per location. It contains a minimal MQTT 3.1.1 client covering what the
+// publisher needs: CONNECT, PUBLISH at QoS 0-2 and DISCONNECT.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types (high nibble of the first header byte)
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPubrec     = 5
+	packetPubrel     = 6
+	packetPubcomp    = 7
+	packetDisconnect = 14
+)
+
+// keepAlive is advertised to the broker; connections only live for one publish round
+const keepAlive = 60 * time.Second
+
+// Client is a connection to an MQTT broker
+type Client struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	packetID uint16
+}
+
+// Options are the connection settings for Dial
+type Options struct {
+	BrokerURL string // "tcp://host:1883", "mqtt://host:1883" or "ssl://host:8883" / "mqtts://host:8883"
+	ClientID  string
+	Username  string
+	Password  string
+}
+
+// Dial connects to the broker and completes the MQTT handshake. ctx bounds the whole handshake.
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	broker, err := url.Parse(opts.BrokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker URL: %w", err)
+	}
+
+	var dialer net.Dialer
+	var conn net.Conn
+	switch broker.Scheme {
+	case "tcp", "mqtt":
+		conn, err = dialer.DialContext(ctx, "tcp", hostPort(broker, "1883"))
+	case "ssl", "tls", "mqtts":
+		tlsDialer := tls.Dialer{NetDialer: &dialer, Config: &tls.Config{ServerName: broker.Hostname()}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", hostPort(broker, "8883"))
+	default:
+		return nil, fmt.Errorf("unsupported broker scheme %q", broker.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	client := &Client{conn: conn, reader: bufio.NewReader(conn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if err := client.connect(opts); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// hostPort returns the broker address, adding the default port when the URL has none
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
+
+// connect sends CONNECT with a clean session and waits for an accepting CONNACK
+func (c *Client) connect(opts Options) error {
+	var flags byte = 0x02 // Clean session
+	payload := appendString(nil, opts.ClientID)
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+	}
+	if opts.Password != "" {
+		flags |= 0x40
+		payload = appendString(payload, opts.Password)
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags) // Protocol level 4 is MQTT 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(keepAlive/time.Second))
+	body = append(body, payload...)
+	if err := c.writePacket(packetConnect<<4, body); err != nil {
+		return err
+	}
+
+	packetType, response, err := c.readPacket()
+	if err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if packetType != packetConnack || len(response) != 2 {
+		return fmt.Errorf("unexpected packet type %d waiting for CONNACK", packetType)
+	}
+	if code := response[1]; code != 0 {
+		return fmt.Errorf("broker refused connection: %s", connackReason(code))
+	}
+	return nil
+}
+
+// connackReason describes a CONNACK return code
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("return code %d", code)
+}
+
+// Publish sends payload to topic and, for QoS 1 and 2, waits until the broker has acknowledged it
+func (c *Client) Publish(ctx context.Context, topic string, payload []byte, qos byte, retain bool) error {
+	if qos > 2 {
+		return fmt.Errorf("invalid QoS %d", qos)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+	}
+
+	header := byte(packetPublish<<4) | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	var id uint16
+	if qos > 0 {
+		id = c.nextPacketID()
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+	if err := c.writePacket(header, body); err != nil {
+		return err
+	}
+
+	switch qos {
+	case 1:
+		return c.expectAck(packetPuback, id)
+	case 2:
+		if err := c.expectAck(packetPubrec, id); err != nil {
+			return err
+		}
+		if err := c.writePacket(packetPubrel<<4|0x02, binary.BigEndian.AppendUint16(nil, id)); err != nil {
+			return err
+		}
+		return c.expectAck(packetPubcomp, id)
+	}
+	return nil
+}
+
+// Close sends DISCONNECT and closes the connection
+func (c *Client) Close() error {
+	err := c.writePacket(packetDisconnect<<4, nil)
+	return errors.Join(err, c.conn.Close())
+}
+
+// nextPacketID returns a non-zero packet identifier for a QoS 1 or 2 publish
+func (c *Client) nextPacketID() uint16 {
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+	return c.packetID
+}
+
+// expectAck reads the next packet and checks it acknowledges packet id with the given type
+func (c *Client) expectAck(want byte, id uint16) error {
+	packetType, body, err := c.readPacket()
+	if err != nil {
+		return fmt.Errorf("failed to read acknowledgement: %w", err)
+	}
+	if packetType != want || len(body) < 2 || binary.BigEndian.Uint16(body) != id {
+		return fmt.Errorf("unexpected packet type %d waiting for acknowledgement type %d of packet %d", packetType, want, id)
+	}
+	return nil
+}
+
+// writePacket writes a control packet with its remaining-length header
+func (c *Client) writePacket(header byte, body []byte) error {
+	packet := append([]byte{header}, encodeLength(len(body))...)
+	if _, err := c.conn.Write(append(packet, body...)); err != nil {
+		return fmt.Errorf("failed to write MQTT packet: %w", err)
+	}
+	return nil
+}
+
+// readPacket reads one control packet, returning its type and body
+func (c *Client) readPacket() (byte, []byte, error) {
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, err := decodeLength(c.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, body, nil
+}
+
+// appendString appends a length-prefixed UTF-8 string
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// encodeLength encodes a remaining length as the MQTT variable-length integer
+func encodeLength(n int) []byte {
+	var encoded []byte
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		encoded = append(encoded, digit)
+		if n == 0 {
+			return encoded
+		}
+	}
+}
+
+// decodeLength reads an MQTT variable-length integer (at most 4 bytes)
+func decodeLength(r io.ByteReader) (int, error) {
+	length, multiplier := 0, 1
+	for i := 0; i < 4; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			return length, nil
+		}
+		multiplier *= 128
+	}
+	return 0, errors.New("malformed remaining length")
+}
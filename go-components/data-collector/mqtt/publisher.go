@@ -0,0 +1,117 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// Publisher pushes the current conditions of each successful result to "<prefix>/<location>/current"
+type Publisher struct {
+	Options     Options
+	TopicPrefix string
+	QoS         byte
+	Retain      bool          // Brokers keep the last message so new subscribers get it immediately
+	Timeout     time.Duration // Time allowed for connecting and publishing a whole run (0 = no limit)
+}
+
+// NewPublisher creates a publisher from the MQTT configuration
+func NewPublisher(cfg *config.Config) *Publisher {
+	return &Publisher{
+		Options: Options{
+			BrokerURL: cfg.MQTT.BrokerURL,
+			ClientID:  cfg.MQTT.ClientID,
+			Username:  cfg.MQTT.Username,
+			Password:  cfg.MQTT.Password,
+		},
+		TopicPrefix: cfg.MQTT.TopicPrefix,
+		QoS:         byte(cfg.MQTT.QoS),
+		Retain:      cfg.MQTT.Retain,
+		Timeout:     cfg.MQTT.Timeout,
+	}
+}
+
+// Save publishes the current weather of every successful result over one broker connection
+func (p *Publisher) Save(ctx context.Context, results []collector.WeatherResult) error {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	client, err := Dial(ctx, p.Options)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		payload, err := message(result.Location, result.CurrentWeather)
+		if err != nil {
+			return fmt.Errorf("failed to encode MQTT message for %s: %w", result.Location.Name, err)
+		}
+		topic := p.Topic(result.Location)
+		if err := client.Publish(ctx, topic, payload, p.QoS, p.Retain); err != nil {
+			return fmt.Errorf("failed to publish %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+// Close implements storage.Store; connections only last for one Save
+func (p *Publisher) Close() error {
+	return nil
+}
+
+// message builds the JSON payload published per location: the fields of the current reading
+// preceded by its location. WeatherPoint has its own MarshalJSON, so it cannot simply be embedded.
+func message(loc collector.Location, point collector.WeatherPoint) ([]byte, error) {
+	location, err := json.Marshal(loc)
+	if err != nil {
+		return nil, err
+	}
+	reading, err := json.Marshal(point)
+	if err != nil {
+		return nil, err
+	}
+	payload := append([]byte(`{"location":`), location...)
+	payload = append(payload, ',')
+	return append(payload, reading[1:]...), nil
+}
+
+// Topic returns the topic for a location's current conditions
+func (p *Publisher) Topic(loc collector.Location) string {
+	return strings.TrimSuffix(p.TopicPrefix, "/") + "/" + topicSegment(loc.Name) + "/current"
+}
+
+// topicSegment turns a location name into a single topic level: lowercase letters and digits
+// (non-ASCII letters such as "ø" are kept) separated by dashes, so "/", "+" and "#" cannot change
+// the topic structure
+func topicSegment(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > unicode.MaxASCII {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "unnamed"
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+)
+
+// publishedMessage is a PUBLISH packet received by fakeBroker
+type publishedMessage struct {
+	topic   string
+	payload []byte
+	qos     byte
+	retain  bool
+}
+
+// fakeBroker accepts one connection, acknowledges CONNECT and every PUBLISH, and records the messages
+type fakeBroker struct {
+	listener net.Listener
+	connect  []byte // Body of the CONNECT packet
+	messages []publishedMessage
+	done     chan struct{}
+	refuse   byte // CONNACK return code to send
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return &fakeBroker{listener: listener, done: make(chan struct{})}
+}
+
+func (b *fakeBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve() {
+	defer close(b.done)
+	conn, err := b.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	read := func() (byte, []byte, bool) {
+		header, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, false
+		}
+		length, err := decodeLength(reader)
+		if err != nil {
+			return 0, nil, false
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return 0, nil, false
+		}
+		return header, body, true
+	}
+
+	for {
+		header, body, ok := read()
+		if !ok {
+			return
+		}
+		switch header >> 4 {
+		case packetConnect:
+			b.connect = body
+			conn.Write([]byte{packetConnack << 4, 2, 0, b.refuse})
+		case packetPublish:
+			qos := header >> 1 & 0x03
+			topicLen := int(binary.BigEndian.Uint16(body))
+			msg := publishedMessage{topic: string(body[2 : 2+topicLen]), qos: qos, retain: header&0x01 != 0}
+			rest := body[2+topicLen:]
+			if qos > 0 {
+				id := rest[:2]
+				rest = rest[2:]
+				ack := byte(packetPuback)
+				if qos == 2 {
+					ack = packetPubrec
+				}
+				conn.Write(append([]byte{ack << 4, 2}, id...))
+			}
+			msg.payload = rest
+			b.messages = append(b.messages, msg)
+		case packetPubrel:
+			conn.Write(append([]byte{packetPubcomp << 4, 2}, body...))
+		case packetDisconnect:
+			return
+		}
+	}
+}
+
+// testResults has two successful locations and one failure
+var testResults = []collector.WeatherResult{
+	{Location: collector.Location{Name: "Oslo"}, CurrentWeather: collector.WeatherPoint{Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), Temperature: 9.5}, Success: true},
+	{Location: collector.Location{Name: "Broken"}, Success: false},
+	{Location: collector.Location{Name: "Tromsø / Nord+"}, CurrentWeather: collector.WeatherPoint{Temperature: 3}, Success: true},
+}
+
+// TestPublisherSave tests that each successful location is published retained to its own topic
+func TestPublisherSave(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		broker := newFakeBroker(t)
+		go broker.serve()
+
+		publisher := &Publisher{
+			Options:     Options{BrokerURL: broker.url(), ClientID: "test", Username: "home", Password: "secret"},
+			TopicPrefix: "weather/",
+			QoS:         qos,
+			Retain:      true,
+			Timeout:     5 * time.Second,
+		}
+		if err := publisher.Save(context.Background(), testResults); err != nil {
+			t.Fatalf("QoS %d: Save failed: %v", qos, err)
+		}
+		<-broker.done
+
+		if len(broker.messages) != 2 {
+			t.Fatalf("QoS %d: expected 2 messages, got %d", qos, len(broker.messages))
+		}
+		first, second := broker.messages[0], broker.messages[1]
+		if first.topic != "weather/oslo/current" || second.topic != "weather/tromsø-nord/current" {
+			t.Errorf("QoS %d: unexpected topics %q and %q", qos, first.topic, second.topic)
+		}
+		if first.qos != qos || !first.retain {
+			t.Errorf("QoS %d: expected retained message at QoS %d, got qos=%d retain=%v", qos, qos, first.qos, first.retain)
+		}
+
+		var payload map[string]any
+		if err := json.Unmarshal(first.payload, &payload); err != nil {
+			t.Fatalf("QoS %d: invalid payload: %v", qos, err)
+		}
+		if payload["temperature"] != 9.5 || payload["location"].(map[string]any)["name"] != "Oslo" {
+			t.Errorf("QoS %d: unexpected payload %s", qos, first.payload)
+		}
+		if broker.connect[7]&0xC0 != 0xC0 {
+			t.Errorf("QoS %d: expected user name and password flags in CONNECT, got %08b", qos, broker.connect[7])
+		}
+	}
+}
+
+// TestPublisherRefused tests that a refused connection reports the broker's reason
+func TestPublisherRefused(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.refuse = 5
+	go broker.serve()
+
+	publisher := &Publisher{Options: Options{BrokerURL: broker.url(), ClientID: "test"}, TopicPrefix: "weather", Timeout: 5 * time.Second}
+	err := publisher.Save(context.Background(), testResults)
+	if err == nil || err.Error() != "broker refused connection: not authorized" {
+		t.Errorf("Expected a not authorized error, got %v", err)
+	}
+}
+
+// TestRemainingLength tests the variable-length encoding at its boundaries
+func TestRemainingLength(t *testing.T) {
+	for _, n := range []int{0, 127, 128, 16383, 16384, 2097151, 268435455} {
+		encoded := encodeLength(n)
+		decoded, err := decodeLength(bytes.NewReader(encoded))
+		if err != nil || decoded != n {
+			t.Errorf("Length %d: round trip gave %d (err: %v)", n, decoded, err)
+		}
+	}
+}
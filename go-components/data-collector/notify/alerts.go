@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// maxListedFailures caps how many failed locations an alert names
+const maxListedFailures = 10
+
+// Alerter watches collection runs and alerts operators through its channels when the failure rate
+// of a run exceeds the threshold or a location has had no successful collection for StaleAfter.
+// Each condition alerts once when it starts and again only after it has cleared, so a daemon that
+// keeps failing does not post on every cycle. A nil Alerter does nothing.
+type Alerter struct {
+	Channels         []Channel
+	FailureThreshold float64       // Failure rate (0-1) above which a run alerts (0 disables)
+	StaleAfter       time.Duration // Time without a successful collection before a location alerts (0 disables)
+
+	failing     bool                 // The previous run was above the failure threshold
+	lastSuccess map[string]time.Time // Per location: last successful collection, or when first seen
+	stale       map[string]bool      // Locations already alerted as stale
+}
+
+// NewAlerter creates an alerter from the notification configuration, or nil when no channel is configured
+func NewAlerter(cfg *config.Config) *Alerter {
+	channels := NewChannels(cfg)
+	if len(channels) == 0 {
+		return nil
+	}
+	return &Alerter{
+		Channels:         channels,
+		FailureThreshold: cfg.Notifications.FailureThreshold,
+		StaleAfter:       cfg.Notifications.StaleAfter,
+		lastSuccess:      make(map[string]time.Time),
+		stale:            make(map[string]bool),
+	}
+}
+
+// Check evaluates the results of a run finished at now and sends any alerts that are due
+func (a *Alerter) Check(ctx context.Context, results []collector.WeatherResult, now time.Time) {
+	if a == nil {
+		return
+	}
+	for _, message := range a.evaluate(results, now) {
+		a.send(ctx, message)
+	}
+}
+
+// evaluate updates the alert state with a run's results and returns the messages to send
+func (a *Alerter) evaluate(results []collector.WeatherResult, now time.Time) []string {
+	var messages []string
+	if message := a.checkFailureRate(results); message != "" {
+		messages = append(messages, message)
+	}
+	return append(messages, a.checkStale(results, now)...)
+}
+
+// checkFailureRate returns an alert when the run's failure rate first exceeds the threshold
+func (a *Alerter) checkFailureRate(results []collector.WeatherResult) string {
+	if a.FailureThreshold <= 0 || len(results) == 0 {
+		return ""
+	}
+
+	var failures []string
+	for _, result := range results {
+		if !result.Success {
+			failures = append(failures, fmt.Sprintf("%s (%s)", result.Location.Name, result.ErrorCode))
+		}
+	}
+	rate := float64(len(failures)) / float64(len(results))
+
+	wasFailing := a.failing
+	a.failing = rate > a.FailureThreshold
+	if !a.failing || wasFailing {
+		return ""
+	}
+
+	if len(failures) > maxListedFailures {
+		failures = append(failures[:maxListedFailures], fmt.Sprintf("and %d more", len(failures)-maxListedFailures))
+	}
+	return fmt.Sprintf("Weather collection: %d of %d locations failed (%.0f%%, threshold %.0f%%): %s",
+		len(failures), len(results), rate*100, a.FailureThreshold*100, strings.Join(failures, ", "))
+}
+
+// checkStale returns an alert for each location that has just gone StaleAfter without a successful collection.
+// A location that has never succeeded is measured from the first run it appeared in.
+func (a *Alerter) checkStale(results []collector.WeatherResult, now time.Time) []string {
+	if a.StaleAfter <= 0 {
+		return nil
+	}
+
+	var stale []string
+	for _, result := range results {
+		name := result.Location.Name
+		last, seen := a.lastSuccess[name]
+		if result.Success || !seen {
+			a.lastSuccess[name] = now
+			delete(a.stale, name)
+			continue
+		}
+		if now.Sub(last) >= a.StaleAfter && !a.stale[name] {
+			a.stale[name] = true
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+
+	messages := make([]string, len(stale))
+	for i, name := range stale {
+		last := a.lastSuccess[name]
+		messages[i] = fmt.Sprintf("Weather data for %s is stale: no successful collection for %s (since %s)",
+			name, now.Sub(last).Round(time.Minute), last.UTC().Format(time.RFC3339))
+	}
+	return messages
+}
+
+// send delivers message on every channel, logging channels that fail
+func (a *Alerter) send(ctx context.Context, message string) {
+	slog.Warn("Sending alert", "message", message)
+	for _, channel := range a.Channels {
+		if err := channel.Notify(ctx, message); err != nil {
+			slog.Warn("Could not send alert", "channel", channel.Name(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// recordingChannel keeps every message it is asked to deliver
+type recordingChannel struct {
+	messages []string
+}
+
+func (c *recordingChannel) Name() string { return "recording" }
+
+func (c *recordingChannel) Notify(ctx context.Context, message string) error {
+	c.messages = append(c.messages, message)
+	return nil
+}
+
+// runResults builds results for the named locations, failing those listed in failed
+func runResults(names []string, failed ...string) []collector.WeatherResult {
+	results := make([]collector.WeatherResult, len(names))
+	for i, name := range names {
+		results[i] = collector.WeatherResult{Location: collector.Location{Name: name}, Success: true}
+		for _, f := range failed {
+			if f == name {
+				results[i] = collector.WeatherResult{Location: collector.Location{Name: name}, ErrorCode: collector.ErrorCodeTimeout}
+			}
+		}
+	}
+	return results
+}
+
+// TestAlerterFailureRate tests that a run above the threshold alerts once until the rate recovers
+func TestAlerterFailureRate(t *testing.T) {
+	channel := &recordingChannel{}
+	alerter := &Alerter{Channels: []Channel{channel}, FailureThreshold: 0.5}
+	names := []string{"Oslo", "Bergen", "Tromsø"}
+	now := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	alerter.Check(context.Background(), runResults(names, "Oslo"), now)
+	if len(channel.messages) != 0 {
+		t.Fatalf("Expected no alert at 33%% failures, got %v", channel.messages)
+	}
+
+	alerter.Check(context.Background(), runResults(names, "Oslo", "Bergen"), now)
+	if len(channel.messages) != 1 || !strings.Contains(channel.messages[0], "2 of 3 locations failed") ||
+		!strings.Contains(channel.messages[0], "Bergen (timeout)") {
+		t.Fatalf("Expected a failure-rate alert, got %v", channel.messages)
+	}
+
+	alerter.Check(context.Background(), runResults(names, "Oslo", "Bergen", "Tromsø"), now)
+	if len(channel.messages) != 1 {
+		t.Errorf("Expected no repeat alert while still failing, got %v", channel.messages)
+	}
+
+	alerter.Check(context.Background(), runResults(names), now)
+	alerter.Check(context.Background(), runResults(names, "Oslo", "Bergen"), now)
+	if len(channel.messages) != 2 {
+		t.Errorf("Expected a new alert after recovering, got %v", channel.messages)
+	}
+}
+
+// TestAlerterStale tests that a location alerts once after StaleAfter without success
+func TestAlerterStale(t *testing.T) {
+	channel := &recordingChannel{}
+	alerter := &Alerter{
+		Channels:    []Channel{channel},
+		StaleAfter:  2 * time.Hour,
+		lastSuccess: make(map[string]time.Time),
+		stale:       make(map[string]bool),
+	}
+	names := []string{"Oslo", "Bergen"}
+	start := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	alerter.Check(context.Background(), runResults(names), start)
+	alerter.Check(context.Background(), runResults(names, "Bergen"), start.Add(time.Hour))
+	if len(channel.messages) != 0 {
+		t.Fatalf("Expected no alert before StaleAfter, got %v", channel.messages)
+	}
+
+	alerter.Check(context.Background(), runResults(names, "Bergen"), start.Add(2*time.Hour))
+	if len(channel.messages) != 1 || !strings.Contains(channel.messages[0], "Bergen is stale") {
+		t.Fatalf("Expected a stale alert for Bergen, got %v", channel.messages)
+	}
+
+	alerter.Check(context.Background(), runResults(names, "Bergen"), start.Add(3*time.Hour))
+	if len(channel.messages) != 1 {
+		t.Errorf("Expected no repeat stale alert, got %v", channel.messages)
+	}
+
+	// A location that has never succeeded is measured from its first run
+	alerter.Check(context.Background(), runResults([]string{"Svalbard"}, "Svalbard"), start.Add(3*time.Hour))
+	alerter.Check(context.Background(), runResults([]string{"Svalbard"}, "Svalbard"), start.Add(5*time.Hour))
+	if len(channel.messages) != 2 || !strings.Contains(channel.messages[1], "Svalbard is stale") {
+		t.Errorf("Expected a stale alert for Svalbard, got %v", channel.messages)
+	}
+}
+
+// TestNewAlerter tests that the alerter is only created with at least one channel
+func TestNewAlerter(t *testing.T) {
+	cfg := *config.Get()
+	if alerter := NewAlerter(&cfg); alerter != nil {
+		t.Fatalf("Expected no alerter without chat webhooks, got %+v", alerter)
+	}
+	var alerter *Alerter
+	alerter.Check(context.Background(), runResults([]string{"Oslo"}, "Oslo"), time.Now()) // Must not panic
+
+	cfg.Notifications.SlackWebhookURL = "https://hooks.slack.com/services/T/B/X"
+	cfg.Notifications.DiscordWebhookURL = "https://discord.com/api/webhooks/1/x"
+	alerter = NewAlerter(&cfg)
+	if alerter == nil || len(alerter.Channels) != 2 {
+		t.Fatalf("Expected Slack and Discord channels, got %+v", alerter)
+	}
+}
+
+// TestChatChannels tests the message payload each chat service expects
+func TestChatChannels(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	slack := &SlackChannel{URL: server.URL, client: server.Client()}
+	if err := slack.Notify(context.Background(), "hello"); err != nil || received["text"] != "hello" {
+		t.Errorf("Expected Slack text payload, got %v (err: %v)", received, err)
+	}
+
+	discord := &DiscordChannel{URL: server.URL, client: server.Client()}
+	if err := discord.Notify(context.Background(), "hello"); err != nil || received["content"] != "hello" {
+		t.Errorf("Expected Discord content payload, got %v (err: %v)", received, err)
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"weather-collector/config"
+)
+
+// Channel names used in logs
+const (
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+)
+
+// Channel delivers a human-readable alert message to operators
+type Channel interface {
+	// Name returns the short identifier used in logs (e.g. "slack")
+	Name() string
+	// Notify delivers message, returning an error if the channel rejected it
+	Notify(ctx context.Context, message string) error
+}
+
+// SlackChannel posts messages to a Slack incoming webhook
+type SlackChannel struct {
+	URL       string
+	UserAgent string
+	Timeout   time.Duration
+	client    *http.Client
+}
+
+// Name returns the channel name
+func (c *SlackChannel) Name() string {
+	return ChannelSlack
+}
+
+// Notify posts message as the text of a Slack message
+func (c *SlackChannel) Notify(ctx context.Context, message string) error {
+	return postJSON(ctx, c.client, c.URL, c.UserAgent, c.Timeout, map[string]string{"text": message})
+}
+
+// DiscordChannel posts messages to a Discord webhook
+type DiscordChannel struct {
+	URL       string
+	UserAgent string
+	Timeout   time.Duration
+	client    *http.Client
+}
+
+// Name returns the channel name
+func (c *DiscordChannel) Name() string {
+	return ChannelDiscord
+}
+
+// Notify posts message as the content of a Discord message
+func (c *DiscordChannel) Notify(ctx context.Context, message string) error {
+	return postJSON(ctx, c.client, c.URL, c.UserAgent, c.Timeout, map[string]string{"content": message})
+}
+
+// NewChannels creates a channel for every alert webhook in the configuration
+func NewChannels(cfg *config.Config) []Channel {
+	var channels []Channel
+	if url := cfg.Notifications.SlackWebhookURL; url != "" {
+		channels = append(channels, &SlackChannel{URL: url, UserAgent: cfg.API.UserAgent, Timeout: cfg.Notifications.Timeout, client: &http.Client{}})
+	}
+	if url := cfg.Notifications.DiscordWebhookURL; url != "" {
+		channels = append(channels, &DiscordChannel{URL: url, UserAgent: cfg.API.UserAgent, Timeout: cfg.Notifications.Timeout, client: &http.Client{}})
+	}
+	return channels
+}
@@ -0,0 +1,79 @@
+// Package notify sends collection run summaries to external endpoints, so the Python layer and
+// monitoring systems learn about finished or failed runs without polling the output files, and
+// alerts operators on chat channels (Slack, Discord) when locations fail or go stale.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"weather-collector/config"
+)
+
+// Webhook POSTs JSON payloads to a configured URL
+type Webhook struct {
+	URL       string
+	UserAgent string
+	Timeout   time.Duration // Time allowed for each delivery (0 = no limit)
+	client    *http.Client
+}
+
+// NewWebhook creates a webhook from the configuration, or nil when no webhook URL is set
+func NewWebhook(cfg *config.Config) *Webhook {
+	if cfg.Notifications.WebhookURL == "" {
+		return nil
+	}
+	return &Webhook{
+		URL:       cfg.Notifications.WebhookURL,
+		UserAgent: cfg.API.UserAgent,
+		Timeout:   cfg.Notifications.Timeout,
+		client:    &http.Client{},
+	}
+}
+
+// Send POSTs payload as JSON. Any non-2xx response is an error. A nil Webhook sends nothing.
+func (w *Webhook) Send(ctx context.Context, payload any) error {
+	if w == nil {
+		return nil
+	}
+	return postJSON(ctx, w.client, w.URL, w.UserAgent, w.Timeout, payload)
+}
+
+// postJSON POSTs payload as JSON to url, bounded by timeout when it is positive.
+// Any non-2xx response is an error.
+func postJSON(ctx context.Context, client *http.Client, url, userAgent string, timeout time.Duration, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) // Drain so the connection can be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weather-collector/config"
+)
+
+// TestNewWebhookDisabled tests that no webhook is created without a URL and a nil webhook sends nothing
+func TestNewWebhookDisabled(t *testing.T) {
+	cfg := *config.Get()
+	cfg.Notifications.WebhookURL = ""
+
+	webhook := NewWebhook(&cfg)
+	if webhook != nil {
+		t.Fatalf("Expected no webhook without a URL, got %+v", webhook)
+	}
+	if err := webhook.Send(context.Background(), map[string]int{"locations": 1}); err != nil {
+		t.Errorf("Expected a nil webhook to send nothing, got %v", err)
+	}
+}
+
+// TestWebhookSend tests that the payload is POSTed as JSON
+func TestWebhookSend(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request: %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Invalid JSON body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := *config.Get()
+	cfg.Notifications.WebhookURL = server.URL
+	payload := map[string]any{"status": "partial", "failed": 2}
+	if err := NewWebhook(&cfg).Send(context.Background(), payload); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if received["status"] != "partial" || received["failed"] != float64(2) {
+		t.Errorf("Unexpected payload: %v", received)
+	}
+}
+
+// TestWebhookSendErrors tests that error statuses and slow endpoints are reported
+func TestWebhookSendErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := *config.Get()
+	cfg.Notifications.WebhookURL = server.URL
+	if err := NewWebhook(&cfg).Send(context.Background(), struct{}{}); err == nil {
+		t.Error("Expected an error for a 500 response")
+	}
+
+	cfg.Notifications.WebhookURL = server.URL + "/slow"
+	cfg.Notifications.Timeout = 20 * time.Millisecond
+	if err := NewWebhook(&cfg).Send(context.Background(), struct{}{}); err == nil {
+		t.Error("Expected an error when the webhook times out")
+	}
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseCronNext tests next-run calculation for common expressions
+func TestParseCronNext(t *testing.T) {
+	// Friday 3 October 2025, 12:07
+	from := time.Date(2025, 10, 3, 12, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"*/30 * * * *", time.Date(2025, 10, 3, 12, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, 10, 3, 13, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 10, 3, 13, 0, 0, 0, time.UTC)},
+		{"5 6,18 * * *", time.Date(2025, 10, 3, 18, 5, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, 10, 6, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)},
+		{"10-20/5 12 * * *", time.Date(2025, 10, 3, 12, 10, 0, 0, time.UTC)},
+		// Day-of-month OR day-of-week when both are restricted
+		{"0 0 15 * sat", time.Date(2025, 10, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+			if next := schedule.Next(from); !next.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
+
+// TestParseCronInvalid tests rejection of malformed expressions
+func TestParseCronInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+	}
+
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+// TestCronNextIsStrictlyAfter tests that a matching start time is not returned again
+func TestCronNextIsStrictlyAfter(t *testing.T) {
+	schedule, err := ParseCron("0 * * * *")
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+
+	onTheHour := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	if next := schedule.Next(onTheHour); !next.Equal(onTheHour.Add(time.Hour)) {
+		t.Errorf("Expected %v, got %v", onTheHour.Add(time.Hour), next)
+	}
+}
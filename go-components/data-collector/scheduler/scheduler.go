@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Schedule decides when the next collection should start
+type Schedule interface {
+	// Next returns the first run time strictly after the given time
+	Next(after time.Time) time.Time
+}
+
+// IntervalSchedule runs at a fixed interval
+type IntervalSchedule struct {
+	Interval time.Duration
+}
+
+// Every creates a schedule that fires every interval
+func Every(interval time.Duration) IntervalSchedule {
+	return IntervalSchedule{Interval: interval}
+}
+
+// Next returns after + interval
+func (s IntervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.Interval)
+}
+
+// Run calls job at every time produced by schedule until ctx is cancelled.
+// A job that is running when ctx is cancelled is allowed to return on its own.
+// The next run is computed from the start of the previous one, so a slow job
+// shortens the following wait instead of drifting the schedule.
+func Run(ctx context.Context, schedule Schedule, job func(context.Context)) {
+	last := time.Now()
+	for {
+		next := schedule.Next(last)
+		if next.IsZero() {
+			slog.Warn("Schedule has no upcoming runs, stopping")
+			return
+		}
+		slog.Info("Next collection scheduled", "at", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.Info("Scheduler stopping", "cause", context.Cause(ctx))
+			return
+		case <-timer.C:
+		}
+
+		last = time.Now()
+		if next.After(last) {
+			last = next
+		}
+		job(ctx)
+	}
+}
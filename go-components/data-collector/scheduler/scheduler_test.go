@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestIntervalScheduleNext tests fixed interval arithmetic
+func TestIntervalScheduleNext(t *testing.T) {
+	start := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	next := Every(30 * time.Minute).Next(start)
+
+	if !next.Equal(start.Add(30 * time.Minute)) {
+		t.Errorf("Expected %v, got %v", start.Add(30*time.Minute), next)
+	}
+}
+
+// TestRunStopsOnCancel tests that Run fires repeatedly and exits when the context ends
+func TestRunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, Every(5*time.Millisecond), func(context.Context) {
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+
+	if runs.Load() != 3 {
+		t.Errorf("Expected 3 runs before cancellation, got %d", runs.Load())
+	}
+}
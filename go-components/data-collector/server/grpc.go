@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/weatherpb"
+	"weathermodels"
+)
+
+// grpcService implements the WeatherCollector gRPC service from weather.proto
+type grpcService struct {
+	weatherpb.UnimplementedWeatherCollectorServer
+
+	cfg       *config.Config
+	provider  collector.Provider
+	providers *collector.ProviderSet // Per-location provider overrides for streamed locations
+}
+
+// newGRPCService creates the service collecting through provider
+func newGRPCService(cfg *config.Config, provider collector.Provider) *grpcService {
+	return &grpcService{cfg: cfg, provider: provider, providers: collector.NewProviderSet(cfg, provider)}
+}
+
+// NewGRPCServer creates a gRPC server exposing the WeatherCollector service
+func NewGRPCServer(cfg *config.Config) (*grpc.Server, error) {
+	provider, err := collector.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer()
+	weatherpb.RegisterWeatherCollectorServer(grpcServer, newGRPCService(cfg, provider))
+	return grpcServer, nil
+}
+
+// ServeGRPC serves the gRPC API on the configured address until ctx is cancelled, then stops gracefully
+func ServeGRPC(ctx context.Context, cfg *config.Config) error {
+	if cfg.Server.GRPCAddress == "" {
+		return fmt.Errorf("server.grpc_address must be set for gRPC mode")
+	}
+
+	grpcServer, err := NewGRPCServer(cfg)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", cfg.Server.GRPCAddress)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutting down gRPC server")
+		grpcServer.GracefulStop()
+	}()
+
+	slog.Info("Serving gRPC collection API", "address", cfg.Server.GRPCAddress)
+	if err := grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Collect fetches weather for a batch of locations and returns results in request order
+func (g *grpcService) Collect(ctx context.Context, req *weatherpb.CollectRequest) (*weatherpb.CollectResponse, error) {
+	locations := make([]collector.Location, len(req.GetLocations()))
+	for i, loc := range req.GetLocations() {
+		locations[i] = fromProtoLocation(loc)
+	}
+
+	if g.cfg.Performance.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.cfg.Performance.RunTimeout)
+		defer cancel()
+	}
+
+	results := collector.CollectWithProvider(ctx, g.provider, locations)
+	response := &weatherpb.CollectResponse{Results: make([]*weatherpb.WeatherResult, len(results))}
+	for i, result := range results {
+		response.Results[i] = toProtoResult(result)
+	}
+	return response, nil
+}
+
+// StreamCollect fetches each location as it arrives (bounded by max workers) and
+// streams results back as soon as they complete
+func (g *grpcService) StreamCollect(stream weatherpb.WeatherCollector_StreamCollectServer) error {
+	ctx := stream.Context()
+	results := make(chan collector.WeatherResult)
+	recvErr := make(chan error, 1)
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		slots := make(chan struct{}, g.cfg.Performance.MaxWorkers)
+		for {
+			loc, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				recvErr <- err
+				return
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				recvErr <- ctx.Err()
+				return
+			}
+
+			wg.Add(1)
+			go func(location collector.Location) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				result := g.fetch(ctx, location)
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}(fromProtoLocation(loc))
+		}
+	}()
+
+	for result := range results {
+		if err := stream.Send(toProtoResult(result)); err != nil {
+			return err
+		}
+	}
+	return <-recvErr
+}
+
+// fetch collects a single location with its own provider, bounded by its own or the per-worker timeout
+func (g *grpcService) fetch(ctx context.Context, loc collector.Location) collector.WeatherResult {
+	if timeout := loc.FetchTimeout(g.cfg.Performance.WorkerTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return collector.FetchWithProvider(ctx, g.providers.For(loc), loc)
+}
+
+// fromProtoLocation converts a protobuf location into the collector type
+func fromProtoLocation(loc *weatherpb.Location) collector.Location {
+	return collector.Location{
+		Name:    loc.GetName(),
+		Lat:     loc.GetLat(),
+		Lon:     loc.GetLon(),
+		Alt:     int(loc.GetAlt()),
+		Coastal: loc.GetCoastal(),
+
+		Provider: loc.GetProvider(),
+		Timeout:  loc.GetTimeout(),
+		Priority: int(loc.GetPriority()),
+	}
+}
+
+// toProtoResult converts a collector result into its protobuf message
+func toProtoResult(result collector.WeatherResult) *weatherpb.WeatherResult {
+	forecast := make([]*weatherpb.WeatherPoint, len(result.Forecast))
+	for i, point := range result.Forecast {
+		forecast[i] = toProtoPoint(point)
+	}
+
+	nowcast := make([]*weatherpb.WeatherPoint, len(result.Nowcast))
+	for i, point := range result.Nowcast {
+		nowcast[i] = toProtoPoint(point)
+	}
+
+	marine := make([]*weatherpb.MarinePoint, len(result.Marine))
+	for i, point := range result.Marine {
+		marine[i] = &weatherpb.MarinePoint{
+			Timestamp:        weathermodels.FormatTimestamp(point.Timestamp),
+			WaveHeight:       point.WaveHeight,
+			WaveDirection:    point.WaveDirection,
+			SeaTemperature:   point.SeaTemperature,
+			CurrentSpeed:     point.CurrentSpeed,
+			CurrentDirection: point.CurrentDirection,
+		}
+	}
+
+	alerts := make([]*weatherpb.Alert, len(result.Alerts))
+	for i, alert := range result.Alerts {
+		alerts[i] = &weatherpb.Alert{
+			Id:             alert.ID,
+			Event:          alert.Event,
+			Title:          alert.Title,
+			Area:           alert.Area,
+			AwarenessLevel: alert.AwarenessLevel,
+			Severity:       alert.Severity,
+			Certainty:      alert.Certainty,
+			Description:    alert.Description,
+			Instruction:    alert.Instruction,
+			Onset:          alert.Onset,
+			Ends:           alert.Ends,
+		}
+	}
+
+	return &weatherpb.WeatherResult{
+		Location: &weatherpb.Location{
+			Name:    result.Location.Name,
+			Lat:     result.Location.Lat,
+			Lon:     result.Location.Lon,
+			Alt:     int32(result.Location.Alt),
+			Coastal: result.Location.Coastal,
+
+			Provider: result.Location.Provider,
+			Timeout:  result.Location.Timeout,
+			Priority: int32(result.Location.Priority),
+		},
+		CurrentWeather: toProtoPoint(result.CurrentWeather),
+		Forecast:       forecast,
+		Success:        result.Success,
+		Error:          result.Error,
+		Attempts:       int32(result.Attempts),
+		Source:         result.Source,
+		Alerts:         alerts,
+		Nowcast:        nowcast,
+		Marine:         marine,
+		SchemaVersion:  int32(result.SchemaVersion),
+		ErrorCode:      result.ErrorCode,
+		Meta: &weatherpb.CollectionMeta{
+			DurationMs: result.Meta.DurationMs,
+			Retries:    int32(result.Meta.Retries),
+			Provider:   result.Meta.Provider,
+			CacheHit:   result.Meta.CacheHit,
+			HttpStatus: int32(result.Meta.HTTPStatus),
+		},
+	}
+}
+
+// toProtoPoint converts a weather reading into its protobuf message
+func toProtoPoint(point collector.WeatherPoint) *weatherpb.WeatherPoint {
+	return &weatherpb.WeatherPoint{
+		Timestamp:                weathermodels.FormatTimestamp(point.Timestamp),
+		Temperature:              point.Temperature,
+		Pressure:                 point.Pressure,
+		Humidity:                 point.Humidity,
+		WindSpeed:                point.WindSpeed,
+		WindDirection:            point.WindDirection,
+		CloudCover:               point.CloudCover,
+		PrecipitationMm:          point.PrecipitationMm,
+		PrecipitationProbability: point.PrecipitationProbability,
+		SymbolCode:               point.SymbolCode,
+		DewPoint:                 point.DewPoint,
+		UvIndex:                  point.UVIndex,
+		WindGust:                 point.WindGust,
+		FogAreaFraction:          point.FogAreaFraction,
+		Source:                   point.Source,
+	}
+}
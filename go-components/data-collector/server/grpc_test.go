@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/weatherpb"
+)
+
+// echoProvider returns the location latitude as temperature, failing for "Broken"
+type echoProvider struct{}
+
+func (echoProvider) Name() string { return "echo" }
+
+func (echoProvider) Fetch(ctx context.Context, loc collector.Location) (collector.WeatherResult, error) {
+	if loc.Name == "Broken" {
+		return collector.WeatherResult{Location: loc}, errors.New("echo failure")
+	}
+	return collector.WeatherResult{
+		Location:       loc,
+		CurrentWeather: collector.WeatherPoint{Temperature: loc.Lat},
+		Success:        true,
+	}, nil
+}
+
+// newTestGRPCClient starts an in-memory gRPC server backed by echoProvider
+func newTestGRPCClient(t *testing.T) weatherpb.WeatherCollectorClient {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	weatherpb.RegisterWeatherCollectorServer(grpcServer, newGRPCService(config.Get(), echoProvider{}))
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return weatherpb.NewWeatherCollectorClient(conn)
+}
+
+// TestGRPCCollect tests the unary Collect RPC preserves order and reports failures
+func TestGRPCCollect(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	resp, err := client.Collect(context.Background(), &weatherpb.CollectRequest{
+		Locations: []*weatherpb.Location{
+			{Name: "Oslo", Lat: 59.91, Lon: 10.75},
+			{Name: "Broken", Lat: 1, Lon: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if len(resp.GetResults()) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(resp.GetResults()))
+	}
+	oslo := resp.GetResults()[0]
+	if !oslo.GetSuccess() || oslo.GetLocation().GetName() != "Oslo" || oslo.GetCurrentWeather().GetTemperature() != 59.91 {
+		t.Errorf("Unexpected Oslo result: %v", oslo)
+	}
+	broken := resp.GetResults()[1]
+	if broken.GetSuccess() || broken.GetError() == "" {
+		t.Errorf("Expected Broken to fail with an error, got %v", broken)
+	}
+}
+
+// TestGRPCStreamCollect tests that streamed locations each produce one streamed result
+func TestGRPCStreamCollect(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	stream, err := client.StreamCollect(context.Background())
+	if err != nil {
+		t.Fatalf("StreamCollect failed: %v", err)
+	}
+
+	names := []string{"Oslo", "Bergen", "Tromsø"}
+	for i, name := range names {
+		if err := stream.Send(&weatherpb.Location{Name: name, Lat: float64(60 + i), Lon: 10}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if !result.GetSuccess() {
+			t.Errorf("Expected success for %s: %s", result.GetLocation().GetName(), result.GetError())
+		}
+		seen[result.GetLocation().GetName()] = true
+	}
+
+	for _, name := range names {
+		if !seen[name] {
+			t.Errorf("Missing streamed result for %s", name)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// maxRequestBytes bounds the size of a POST /collect body
+const maxRequestBytes = 1 << 20
+
+// defaultShutdownTimeout is used when server.shutdown_timeout is not configured
+const defaultShutdownTimeout = 10 * time.Second
+
+// Server exposes weather collection over a small REST API:
+//
+//	POST /collect             body: [{"name": ..., "lat": ..., "lon": ...}] -> []WeatherResult
+//	GET  /weather/{lat}/{lon} -> WeatherResult
+//
+// Responses use the same JSON as the collector output file, in the configured units.
+type Server struct {
+	cfg     *config.Config
+	collect func(ctx context.Context, locations []collector.Location) []collector.WeatherResult
+}
+
+// New creates a server that collects through the configured provider
+func New(cfg *config.Config) *Server {
+	return &Server{
+		cfg:     cfg,
+		collect: collector.CollectWeatherData,
+	}
+}
+
+// Handler returns the HTTP routes for the server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /collect", s.handleCollect)
+	mux.HandleFunc("GET /weather/{lat}/{lon}", s.handleWeather)
+	mux.HandleFunc("GET /health", s.handleHealth)
+	return mux
+}
+
+// ListenAndServe serves on the configured address until ctx is cancelled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	if s.cfg.Server.Address == "" {
+		return fmt.Errorf("server.address must be set for serve mode")
+	}
+
+	httpServer := &http.Server{
+		Addr:              s.cfg.Server.Address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		slog.Info("Serving collection API", "address", s.cfg.Server.Address)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down server")
+	timeout := s.cfg.Server.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleCollect collects weather for every location in the request body
+func (s *Server) handleCollect(w http.ResponseWriter, r *http.Request) {
+	var locations []collector.Location
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err := decoder.Decode(&locations); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid location list: %v", err))
+		return
+	}
+	if len(locations) == 0 {
+		writeError(w, http.StatusBadRequest, "location list is empty")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, collector.ConvertUnits(s.collectWithDeadline(r.Context(), locations), s.cfg.OutputUnits()))
+}
+
+// handleWeather collects weather for a single coordinate pair
+func (s *Server) handleWeather(w http.ResponseWriter, r *http.Request) {
+	lat, latErr := strconv.ParseFloat(r.PathValue("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.PathValue("lon"), 64)
+	if latErr != nil || lonErr != nil {
+		writeError(w, http.StatusBadRequest, "lat and lon must be numbers")
+		return
+	}
+
+	location := collector.Location{
+		Name: fmt.Sprintf("%.4f,%.4f", lat, lon),
+		Lat:  lat,
+		Lon:  lon,
+	}
+	results := s.collectWithDeadline(r.Context(), []collector.Location{location})
+	writeJSON(w, http.StatusOK, results[0].ConvertUnits(s.cfg.OutputUnits()))
+}
+
+// handleHealth reports that the server is up, along with the state of each endpoint circuit breaker
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":           "ok",
+		"circuit_breakers": collector.BreakerStates(),
+	})
+}
+
+// collectWithDeadline applies the configured run timeout to a request's collection
+func (s *Server) collectWithDeadline(ctx context.Context, locations []collector.Location) []collector.WeatherResult {
+	if s.cfg.Performance.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Performance.RunTimeout)
+		defer cancel()
+	}
+	return s.collect(ctx, locations)
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		slog.Warn("Failed to write response", "error", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// newTestServer creates a server whose collection echoes the requested locations
+func newTestServer() *Server {
+	s := New(config.Get())
+	s.collect = func(ctx context.Context, locations []collector.Location) []collector.WeatherResult {
+		results := make([]collector.WeatherResult, len(locations))
+		for i, loc := range locations {
+			results[i] = collector.WeatherResult{
+				Location:       loc,
+				CurrentWeather: collector.WeatherPoint{Temperature: 10 + float64(i)},
+				Success:        true,
+			}
+		}
+		return results
+	}
+	return s
+}
+
+// TestCollectEndpoint tests POST /collect with a list of locations
+func TestCollectEndpoint(t *testing.T) {
+	body := `[{"name": "Oslo", "lat": 59.91, "lon": 10.75}, {"name": "Bergen", "lat": 60.39, "lon": 5.32}]`
+	req := httptest.NewRequest("POST", "/collect", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	newTestServer().Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var results []collector.WeatherResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("Response is not a result list: %v", err)
+	}
+	if len(results) != 2 || results[1].Location.Name != "Bergen" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+// TestCollectEndpointRejectsBadInput tests validation of the request body
+func TestCollectEndpointRejectsBadInput(t *testing.T) {
+	for _, body := range []string{"not json", "[]", `{"name": "Oslo"}`} {
+		req := httptest.NewRequest("POST", "/collect", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		newTestServer().Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Body %q: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+// TestWeatherEndpoint tests GET /weather/{lat}/{lon}
+func TestWeatherEndpoint(t *testing.T) {
+	req := httptest.NewRequest("GET", "/weather/59.91/10.75", nil)
+	rec := httptest.NewRecorder()
+
+	newTestServer().Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result collector.WeatherResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Response is not a single result: %v", err)
+	}
+	if result.Location.Lat != 59.91 || result.Location.Lon != 10.75 {
+		t.Errorf("Expected coordinates from the path, got %+v", result.Location)
+	}
+
+	req = httptest.NewRequest("GET", "/weather/north/10.75", nil)
+	rec = httptest.NewRecorder()
+	newTestServer().Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for non-numeric latitude, got %d", rec.Code)
+	}
+}
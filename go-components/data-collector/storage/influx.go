@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// Line protocol escaping, see https://docs.influxdata.com/influxdb/v2/reference/syntax/line-protocol/
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	stringFieldEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// InfluxExporter writes readings as InfluxDB line protocol, tagged by location name and coordinates,
+// to the InfluxDB v2 HTTP write API, a file, or both
+type InfluxExporter struct {
+	URL         string
+	Org         string
+	Bucket      string
+	Token       string
+	File        string
+	Measurement string
+	Forecast    bool
+	UserAgent   string
+	client      *http.Client
+}
+
+// NewInfluxExporter creates an exporter from the InfluxDB configuration
+func NewInfluxExporter(cfg *config.Config) *InfluxExporter {
+	influx := cfg.Storage.Influx
+	return &InfluxExporter{
+		URL:         influx.URL,
+		Org:         influx.Org,
+		Bucket:      influx.Bucket,
+		Token:       influx.Token,
+		File:        influx.File,
+		Measurement: influx.Measurement,
+		Forecast:    influx.Forecast,
+		UserAgent:   cfg.API.UserAgent,
+		client:      &http.Client{Timeout: cfg.API.Timeout},
+	}
+}
+
+// Save writes the readings of every successful result to the configured destinations
+func (e *InfluxExporter) Save(ctx context.Context, results []collector.WeatherResult) error {
+	lines := e.lines(results)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if e.File != "" {
+		if err := appendFile(e.File, lines); err != nil {
+			return fmt.Errorf("failed to write line protocol to %s: %w", e.File, err)
+		}
+	}
+	if e.URL != "" {
+		if err := e.write(ctx, lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Store; the exporter holds no open resources
+func (e *InfluxExporter) Close() error {
+	return nil
+}
+
+// lines renders successful results as line protocol with second precision
+func (e *InfluxExporter) lines(results []collector.WeatherResult) []byte {
+	var b bytes.Buffer
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		e.writeLine(&b, result.Location, kindCurrent, result.CurrentWeather)
+		if e.Forecast {
+			for _, point := range result.Forecast {
+				e.writeLine(&b, result.Location, kindForecast, point)
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+// writeLine appends one point, skipping points without a valid timestamp
+func (e *InfluxExporter) writeLine(b *bytes.Buffer, loc collector.Location, kind string, point collector.WeatherPoint) {
+	if point.Timestamp.IsZero() {
+		return
+	}
+
+	// Tags are written in key order, as InfluxDB recommends
+	b.WriteString(measurementEscaper.Replace(e.Measurement))
+	fmt.Fprintf(b, ",kind=%s,lat=%s,location=%s,lon=%s ",
+		kind, formatCoordinate(loc.Lat), tagEscaper.Replace(loc.Name), formatCoordinate(loc.Lon))
+
+	fields := []struct {
+		key   string
+		value float64
+	}{
+		{"cloud_cover", point.CloudCover},
+		{"dew_point", point.DewPoint},
+		{"fog_area_fraction", point.FogAreaFraction},
+		{"humidity", point.Humidity},
+		{"precipitation_mm", point.PrecipitationMm},
+		{"precipitation_probability", point.PrecipitationProbability},
+		{"pressure", point.Pressure},
+		{"temperature", point.Temperature},
+		{"uv_index", point.UVIndex},
+		{"wind_direction", point.WindDirection},
+		{"wind_gust", point.WindGust},
+		{"wind_speed", point.WindSpeed},
+	}
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(field.key)
+		b.WriteByte('=')
+		b.WriteString(strconv.FormatFloat(field.value, 'f', -1, 64))
+	}
+	if point.SymbolCode != "" {
+		fmt.Fprintf(b, `,symbol_code="%s"`, stringFieldEscaper.Replace(point.SymbolCode))
+	}
+	fmt.Fprintf(b, " %d\n", point.Timestamp.Unix())
+}
+
+// write POSTs lines to the InfluxDB v2 write API
+func (e *InfluxExporter) write(ctx context.Context, lines []byte) error {
+	query := url.Values{}
+	query.Set("org", e.Org)
+	query.Set("bucket", e.Bucket)
+	query.Set("precision", "s")
+	endpoint := strings.TrimRight(e.URL, "/") + "/api/v2/write?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(lines))
+	if err != nil {
+		return fmt.Errorf("failed to create InfluxDB request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("User-Agent", e.UserAgent)
+	if e.Token != "" {
+		req.Header.Set("Authorization", "Token "+e.Token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("InfluxDB write failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("InfluxDB returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// formatCoordinate renders a coordinate with the 4 decimals used for request and cache keys
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
+
+// appendFile appends data to path, creating the file and its directory if needed
+func appendFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// influxResults is one successful result with a forecast point and one failure
+var influxResults = []collector.WeatherResult{
+	{
+		Location: collector.Location{Name: "St. Moritz, CH", Lat: 46.4908, Lon: 9.8355},
+		CurrentWeather: collector.WeatherPoint{
+			Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), Temperature: -1.5, Humidity: 80, SymbolCode: `snow "heavy"`,
+		},
+		Forecast: []collector.WeatherPoint{{Timestamp: time.Date(2025, 10, 3, 13, 0, 0, 0, time.UTC), Temperature: -2}},
+		Success:  true,
+	},
+	{Location: collector.Location{Name: "Broken"}, Success: false},
+}
+
+// TestInfluxLines tests tags, escaping, fields and timestamps of the rendered line protocol
+func TestInfluxLines(t *testing.T) {
+	exporter := &InfluxExporter{Measurement: "weather", Forecast: true}
+	lines := strings.Split(strings.TrimSpace(string(exporter.lines(influxResults))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), lines)
+	}
+
+	current := lines[0]
+	if !strings.HasPrefix(current, `weather,kind=current,lat=46.4908,location=St.\ Moritz\,\ CH,lon=9.8355 `) {
+		t.Errorf("Unexpected measurement and tags: %s", current)
+	}
+	for _, want := range []string{"temperature=-1.5", "humidity=80", `symbol_code="snow \"heavy\""`} {
+		if !strings.Contains(current, want) {
+			t.Errorf("Expected %s in %s", want, current)
+		}
+	}
+	if !strings.HasSuffix(current, " 1759492800") {
+		t.Errorf("Expected a second-precision timestamp, got %s", current)
+	}
+	if !strings.Contains(lines[1], "kind=forecast") {
+		t.Errorf("Expected a forecast line, got %s", lines[1])
+	}
+
+	exporter.Forecast = false
+	if got := strings.Count(string(exporter.lines(influxResults)), "\n"); got != 1 {
+		t.Errorf("Expected only the current reading without forecast export, got %d lines", got)
+	}
+}
+
+// TestInfluxWriteAPI tests the request sent to the InfluxDB v2 write API
+func TestInfluxWriteAPI(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/write" || r.URL.Query().Get("bucket") != "weather" ||
+			r.URL.Query().Get("org") != "home" || r.URL.Query().Get("precision") != "s" {
+			t.Errorf("Unexpected write URL: %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("Unexpected Authorization header: %q", r.Header.Get("Authorization"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := *config.Get()
+	cfg.Storage.Influx = config.InfluxConfig{
+		Enabled: true, URL: server.URL + "/", Org: "home", Bucket: "weather", Token: "secret", Measurement: "weather",
+	}
+	if err := NewInfluxExporter(&cfg).Save(context.Background(), influxResults); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if strings.Count(body, "\n") != 1 {
+		t.Errorf("Expected one line, got %q", body)
+	}
+}
+
+// TestInfluxWriteErrors tests that a rejected write reports the status and message
+func TestInfluxWriteErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"unauthorized access"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	exporter := &InfluxExporter{URL: server.URL, Bucket: "weather", Measurement: "weather", client: server.Client()}
+	err := exporter.Save(context.Background(), influxResults)
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "unauthorized access") {
+		t.Errorf("Expected a 401 error with the message, got %v", err)
+	}
+}
+
+// TestInfluxFile tests that runs append to the line protocol file
+func TestInfluxFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "influx", "weather.lp")
+	exporter := &InfluxExporter{File: path, Measurement: "weather"}
+
+	for i := 0; i < 2; i++ {
+		if err := exporter.Save(context.Background(), influxResults); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 2 {
+		t.Errorf("Expected 2 appended lines, got %d", got)
+	}
+}
+
+// TestNewCombinesStores tests that the Influx exporter is opened alongside (or without) a database driver
+func TestNewCombinesStores(t *testing.T) {
+	cfg := *config.Get()
+	cfg.Storage.Influx = config.InfluxConfig{Enabled: true, File: filepath.Join(t.TempDir(), "weather.lp"), Measurement: "weather"}
+
+	store, err := New(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, ok := store.(*InfluxExporter); !ok {
+		t.Errorf("Expected the Influx exporter alone, got %T", store)
+	}
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" driver
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// Reading kinds stored in weather_readings.kind
+const (
+	kindCurrent  = "current"
+	kindForecast = "forecast"
+)
+
+// migrationLockID is the advisory lock key that serializes migrations between collectors sharing a database
+const migrationLockID = 0x77656174 // "weat"
+
+// migrations are applied in order and recorded in schema_migrations; never edit one that has shipped,
+// append a new one instead
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS weather_readings (
+		time             timestamptz      NOT NULL,
+		collected_at     timestamptz      NOT NULL,
+		location         text             NOT NULL,
+		lat              double precision NOT NULL,
+		lon              double precision NOT NULL,
+		kind             text             NOT NULL,
+		temperature      double precision,
+		pressure         double precision,
+		humidity         double precision,
+		wind_speed       double precision,
+		wind_direction   double precision,
+		cloud_cover      double precision,
+		precipitation_mm double precision,
+		symbol_code      text,
+		PRIMARY KEY (location, kind, time, collected_at)
+	)`,
+	`CREATE INDEX IF NOT EXISTS weather_readings_location_time ON weather_readings (location, time DESC)`,
+}
+
+// readingColumns are the weather_readings columns written for each reading, in insert order
+var readingColumns = []string{
+	"time", "collected_at", "location", "lat", "lon", "kind",
+	"temperature", "pressure", "humidity", "wind_speed", "wind_direction", "cloud_cover", "precipitation_mm", "symbol_code",
+}
+
+// PostgresStore persists readings in PostgreSQL, optionally as a TimescaleDB hypertable.
+// It talks to the database through the database/sql driver named by storage.postgres.sql_driver;
+// the pgx driver ("pgx") is linked in, others must be registered by the binary.
+type PostgresStore struct {
+	db        *sql.DB
+	batchSize int
+	now       func() time.Time
+}
+
+// OpenPostgres connects to the database, applies pending schema migrations and,
+// when configured, converts the readings table into a TimescaleDB hypertable
+func OpenPostgres(ctx context.Context, cfg config.PostgresConfig) (*PostgresStore, error) {
+	db, err := sql.Open(cfg.SQLDriver, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Postgres connection (is the %q driver registered?): %w", cfg.SQLDriver, err)
+	}
+	db.SetMaxOpenConns(cfg.MaxConns)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if cfg.Timescale {
+		if err := enableTimescale(ctx, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return &PostgresStore{db: db, batchSize: cfg.BatchSize, now: time.Now}, nil
+}
+
+// migrate applies every migration newer than the recorded schema version in one transaction,
+// holding an advisory lock so concurrent collectors do not race
+func migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to lock schema migrations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer     PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var version int
+	if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d failed: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", i+1); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// enableTimescale turns weather_readings into a hypertable partitioned on time (a no-op once it is one)
+func enableTimescale(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS timescaledb"); err != nil {
+		return fmt.Errorf("failed to enable TimescaleDB: %w", err)
+	}
+	if _, err := db.ExecContext(ctx,
+		"SELECT create_hypertable('weather_readings', 'time', if_not_exists => TRUE, migrate_data => TRUE)"); err != nil {
+		return fmt.Errorf("failed to create hypertable: %w", err)
+	}
+	return nil
+}
+
+// Save inserts the current reading and forecast of every successful result in batches of batchSize,
+// all in one transaction. Readings already stored for the same collection time are skipped.
+func (s *PostgresStore) Save(ctx context.Context, results []collector.WeatherResult) error {
+	rows := readingRows(results, s.now())
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start insert: %w", err)
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(rows); start += s.batchSize {
+		batch := rows[start:min(start+s.batchSize, len(rows))]
+		if _, err := tx.ExecContext(ctx, insertStatement(len(batch)), flatten(batch)...); err != nil {
+			return fmt.Errorf("failed to insert readings: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Close closes the connection pool
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// readingRows converts successful results into insert rows, skipping points without a valid timestamp
+func readingRows(results []collector.WeatherResult, collectedAt time.Time) [][]any {
+	var rows [][]any
+	add := func(loc collector.Location, kind string, point collector.WeatherPoint) {
+		if point.Timestamp.IsZero() {
+			return
+		}
+		rows = append(rows, []any{
+			point.Timestamp, collectedAt, loc.Name, loc.Lat, loc.Lon, kind,
+			point.Temperature, point.Pressure, point.Humidity, point.WindSpeed, point.WindDirection,
+			point.CloudCover, point.PrecipitationMm, point.SymbolCode,
+		})
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		add(result.Location, kindCurrent, result.CurrentWeather)
+		for _, point := range result.Forecast {
+			add(result.Location, kindForecast, point)
+		}
+	}
+	return rows
+}
+
+// insertStatement builds a multi-row INSERT for n readings with numbered placeholders
+func insertStatement(n int) string {
+	var b strings.Builder
+	b.WriteString("INSERT INTO weather_readings (")
+	b.WriteString(strings.Join(readingColumns, ", "))
+	b.WriteString(") VALUES ")
+
+	param := 1
+	for row := 0; row < n; row++ {
+		if row > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for col := range readingColumns {
+			if col > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", param)
+			param++
+		}
+		b.WriteByte(')')
+	}
+	b.WriteString(" ON CONFLICT DO NOTHING")
+	return b.String()
+}
+
+// flatten joins rows into one argument list matching insertStatement's placeholders
+func flatten(rows [][]any) []any {
+	args := make([]any, 0, len(rows)*len(readingColumns))
+	for _, row := range rows {
+		args = append(args, row...)
+	}
+	return args
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// fakeDB records the statements executed through the "fakepg" driver and answers the schema version query
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.NamedValue
+	version    int64
+}
+
+func (f *fakeDB) exec(query string, args []driver.NamedValue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, query)
+	f.args = append(f.args, args)
+	if strings.HasPrefix(query, "INSERT INTO schema_migrations") {
+		f.version = args[0].Value.(int64)
+	}
+}
+
+func (f *fakeDB) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, statement := range f.statements {
+		if strings.HasPrefix(strings.TrimSpace(statement), prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	fakeDBOnce sync.Once
+	fakeDBs    sync.Map // DSN -> *fakeDB
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	db, _ := fakeDBs.LoadOrStore(dsn, &fakeDB{})
+	return &fakeConn{db: db.(*fakeDB)}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return c, nil }
+func (c *fakeConn) Commit() error                             { return nil }
+func (c *fakeConn) Rollback() error                           { return nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.exec(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	return &fakeRows{values: []driver.Value{c.db.version}}, nil
+}
+
+// fakeRows returns a single row
+type fakeRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"version"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+// openFake opens a Postgres store backed by a fresh fake database
+func openFake(t *testing.T, cfg config.PostgresConfig) (*PostgresStore, *fakeDB) {
+	t.Helper()
+	fakeDBOnce.Do(func() { sql.Register("fakepg", fakeDriver{}) })
+
+	cfg.SQLDriver = "fakepg"
+	cfg.DSN = t.Name()
+	store, err := OpenPostgres(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("OpenPostgres failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	db, _ := fakeDBs.Load(cfg.DSN)
+	return store, db.(*fakeDB)
+}
+
+// TestOpenPostgresMigrates tests that pending migrations run once and TimescaleDB is enabled on request
+func TestOpenPostgresMigrates(t *testing.T) {
+	_, db := openFake(t, config.PostgresConfig{MaxConns: 1, BatchSize: 10, Timescale: true})
+	if got := db.count("CREATE TABLE IF NOT EXISTS weather_readings"); got != 1 {
+		t.Errorf("Expected the readings table to be created once, got %d", got)
+	}
+	if db.version != int64(len(migrations)) {
+		t.Errorf("Expected schema version %d, got %d", len(migrations), db.version)
+	}
+	if got := db.count("SELECT create_hypertable"); got != 1 {
+		t.Errorf("Expected a hypertable to be created, got %d", got)
+	}
+
+	// Reopening the same database applies nothing new
+	cfg := config.PostgresConfig{SQLDriver: "fakepg", DSN: t.Name(), MaxConns: 1, BatchSize: 10}
+	store, err := OpenPostgres(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	store.Close()
+	if got := db.count("CREATE TABLE IF NOT EXISTS weather_readings"); got != 1 {
+		t.Errorf("Expected migrations not to run again, got %d table creations", got)
+	}
+}
+
+// TestPostgresSaveBatches tests that readings are inserted in batches with one row per point
+func TestPostgresSaveBatches(t *testing.T) {
+	store, db := openFake(t, config.PostgresConfig{MaxConns: 1, BatchSize: 2})
+	collectedAt := time.Date(2025, 10, 3, 12, 5, 0, 0, time.UTC)
+	store.now = func() time.Time { return collectedAt }
+
+	results := []collector.WeatherResult{
+		{
+			Location:       collector.Location{Name: "Oslo", Lat: 59.91, Lon: 10.75},
+			CurrentWeather: collector.WeatherPoint{Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), Temperature: 9.5},
+			Forecast: []collector.WeatherPoint{
+				{Timestamp: time.Date(2025, 10, 3, 13, 0, 0, 0, time.UTC), Temperature: 10},
+				{Temperature: 11}, // No timestamp
+			},
+			Success: true,
+		},
+		{Location: collector.Location{Name: "Broken"}, Success: false},
+		{
+			Location:       collector.Location{Name: "Bergen", Lat: 60.39, Lon: 5.32},
+			CurrentWeather: collector.WeatherPoint{Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), Temperature: 8},
+			Success:        true,
+		},
+	}
+	if err := store.Save(context.Background(), results); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var inserts [][]driver.NamedValue
+	db.mu.Lock()
+	for i, statement := range db.statements {
+		if strings.HasPrefix(statement, "INSERT INTO weather_readings") {
+			inserts = append(inserts, db.args[i])
+		}
+	}
+	db.mu.Unlock()
+
+	if len(inserts) != 2 {
+		t.Fatalf("Expected 3 readings in 2 batches, got %d batches", len(inserts))
+	}
+	if len(inserts[0]) != 2*len(readingColumns) || len(inserts[1]) != len(readingColumns) {
+		t.Errorf("Unexpected batch sizes: %d and %d arguments", len(inserts[0]), len(inserts[1]))
+	}
+	first := inserts[0]
+	if first[1].Value != collectedAt || first[2].Value != "Oslo" || first[5].Value != kindCurrent || first[6].Value != 9.5 {
+		t.Errorf("Unexpected first reading: %v", first[:7])
+	}
+}
+
+// TestInsertStatement tests placeholder numbering across rows
+func TestInsertStatement(t *testing.T) {
+	statement := insertStatement(2)
+	if !strings.Contains(statement, "($1, $2,") || !strings.Contains(statement, "($15, $16,") ||
+		!strings.HasSuffix(statement, "$28) ON CONFLICT DO NOTHING") {
+		t.Errorf("Unexpected statement: %s", statement)
+	}
+}
+
+// TestNewDisabled tests that no store is opened without a driver
+func TestNewDisabled(t *testing.T) {
+	cfg := *config.Get()
+	cfg.Storage.Driver = DriverNone
+	store, err := New(context.Background(), &cfg)
+	if store != nil || err != nil {
+		t.Errorf("Expected no store and no error, got %v, %v", store, err)
+	}
+}
+
+// TestDefaultDriverRegistered tests that the default storage.postgres.sql_driver is linked in
+func TestDefaultDriverRegistered(t *testing.T) {
+	if driver := config.Get().Storage.Postgres.SQLDriver; !slices.Contains(sql.Drivers(), driver) {
+		t.Errorf("Expected the default driver %q to be registered, got %v", driver, sql.Drivers())
+	}
+}
@@ -0,0 +1,93 @@
+// Package storage persists collected readings in a database, alongside the integration files,
+// so larger installs can keep a central history instead of rotating output files. Readings can
+// also be exported as InfluxDB line protocol for existing Grafana/Influx setups.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weather-collector/events"
+	"weather-collector/mqtt"
+	"weather-collector/timeseries"
+)
+
+// Storage drivers accepted in storage.driver
+const (
+	DriverNone     = ""
+	DriverPostgres = "postgres"
+)
+
+// Store persists the results of collection runs
+type Store interface {
+	// Save stores the readings of every successful result, collected at the same time
+	Save(ctx context.Context, results []collector.WeatherResult) error
+	// Close releases the store's connections
+	Close() error
+}
+
+// New opens the store selected by storage.driver, plus the time-series history files, InfluxDB
+// exporter, MQTT publisher and event bus publisher when they are enabled, or returns nil when
+// none is configured
+func New(ctx context.Context, cfg *config.Config) (Store, error) {
+	var stores multiStore
+	switch cfg.Storage.Driver {
+	case DriverNone:
+	case DriverPostgres:
+		store, err := OpenPostgres(ctx, cfg.Storage.Postgres)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+	if cfg.Storage.Timeseries.Enabled {
+		stores = append(stores, timeseries.New(cfg.Storage.Timeseries))
+	}
+	if cfg.Storage.Influx.Enabled {
+		stores = append(stores, NewInfluxExporter(cfg))
+	}
+	if cfg.MQTT.Enabled {
+		stores = append(stores, mqtt.NewPublisher(cfg))
+	}
+	if cfg.Events.Driver != "" {
+		stores = append(stores, events.NewPublisher(cfg))
+	}
+
+	switch len(stores) {
+	case 0:
+		return nil, nil
+	case 1:
+		return stores[0], nil
+	}
+	return stores, nil
+}
+
+// multiStore saves to several stores, attempting every one even when an earlier store fails
+type multiStore []Store
+
+// Save saves results to every store and joins their errors
+func (m multiStore) Save(ctx context.Context, results []collector.WeatherResult) error {
+	var errs []error
+	for _, store := range m {
+		if err := store.Save(ctx, results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every store and joins their errors
+func (m multiStore) Close() error {
+	var errs []error
+	for _, store := range m {
+		if err := store.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,286 @@
+// Package timeseries maintains the per-location history files the pattern engine analyzes, so a
+// Go-only pipeline does not depend on the Python app to append readings. Each run's current
+// readings are merged into "<directory>/<location>.json": deduplicated by timestamp, sorted
+// oldest first and capped to the newest readings. The layout is the one written by
+// utils/intelligence_persistence.py, and readings written by other tools are kept as they are.
+// Each run's forecasts can also be kept in "<directory>/forecasts/<location>.json" for the
+// pattern engine to verify against the readings observed later.
+package timeseries
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"weather-collector/collector"
+	"weather-collector/config"
+	"weathermodels"
+	"weathermodels/fileio"
+)
+
+// SchemaVersion is the layout version of the history files; the pattern engine refuses files
+// newer than models.TimeseriesSchemaVersion
+const SchemaVersion = 1
+
+// pythonTimeLayout is datetime.isoformat() without a UTC offset, used by the Python app for
+// created_at, saved_at and readings it timestamped itself
+const pythonTimeLayout = "2006-01-02T15:04:05.999999"
+
+// File is a per-location history file
+type File struct {
+	SchemaVersion int               `json:"schema_version"`
+	Location      string            `json:"location"`
+	Coordinates   Coordinates       `json:"coordinates"`
+	CreatedAt     string            `json:"created_at"`
+	Readings      []json.RawMessage `json:"readings"`         // Kept as written so fields of other writers survive a merge
+	Alerts        json.RawMessage   `json:"alerts,omitempty"` // Warnings active at the last collection
+	Marine        json.RawMessage   `json:"marine,omitempty"` // Ocean forecast from the last collection
+	Metadata      Metadata          `json:"metadata"`
+}
+
+// Coordinates is the location's position
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+	Alt int     `json:"alt,omitempty"`
+}
+
+// Metadata summarizes the readings of a file
+type Metadata struct {
+	TotalReadings int    `json:"total_readings"`
+	FirstReading  string `json:"first_reading,omitempty"` // When the first reading was saved
+	LastReading   string `json:"last_reading,omitempty"`  // When the last reading was saved
+	Note          string `json:"note,omitempty"`
+}
+
+// Store merges collection results into the history files in Dir
+type Store struct {
+	Dir          string
+	MaxReadings  int // Newest readings kept per location (0 = no limit)
+	MaxForecasts int // Newest forecasts kept per location (0 = forecasts are not kept)
+	now          func() time.Time
+}
+
+// New creates a store from the timeseries storage configuration
+func New(cfg config.TimeseriesConfig) *Store {
+	return &Store{Dir: cfg.Directory, MaxReadings: cfg.MaxReadings, MaxForecasts: cfg.MaxForecasts, now: time.Now}
+}
+
+// Save merges the current reading of every successful result into its location's history file,
+// and its forecast into the location's forecast archive when forecasts are kept, attempting every
+// location even when an earlier one fails
+func (s *Store) Save(ctx context.Context, results []collector.WeatherResult) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create time-series directory: %w", err)
+	}
+
+	var errs []error
+	for _, result := range results {
+		if !result.Success || result.CurrentWeather.Timestamp.IsZero() {
+			continue
+		}
+		if err := s.merge(result); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update time series for %s: %w", result.Location.Name, err))
+		}
+		if err := s.archive(result); err != nil {
+			errs = append(errs, fmt.Errorf("failed to keep forecast for %s: %w", result.Location.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements storage.Store; the store holds no open resources
+func (s *Store) Close() error {
+	return nil
+}
+
+// Path returns the history file of a location, named like the Python app names it
+func (s *Store) Path(name string) string {
+	safe := strings.NewReplacer(" ", "_", ",", "", "/", "_").Replace(name)
+	return filepath.Join(s.Dir, safe+".json")
+}
+
+// ForecastPath returns the forecast archive of a location
+func (s *Store) ForecastPath(name string) string {
+	return filepath.Join(s.Dir, "forecasts", filepath.Base(s.Path(name)))
+}
+
+// archive adds the forecast of one result to its location's forecast archive, replacing a
+// forecast issued at the same time and keeping the newest MaxForecasts. Points before the current
+// reading, such as the history some providers return, are not kept.
+func (s *Store) archive(result collector.WeatherResult) error {
+	if s.MaxForecasts <= 0 {
+		return nil
+	}
+	issued := weathermodels.IssuedForecast{IssuedAt: s.now().UTC().Truncate(time.Second)}
+	for _, point := range result.Forecast {
+		if !point.Timestamp.Before(result.CurrentWeather.Timestamp) {
+			issued.Points = append(issued.Points, point)
+		}
+	}
+	if len(issued.Points) == 0 {
+		return nil
+	}
+
+	path := s.ForecastPath(result.Location.Name)
+	archive := weathermodels.ForecastArchive{Location: result.Location.Name}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &archive); err != nil {
+			return fmt.Errorf("invalid forecast archive %s: %w", path, err)
+		}
+		if archive.SchemaVersion > SchemaVersion {
+			return fmt.Errorf("unsupported schema_version %d in %s", archive.SchemaVersion, path)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	archive.Forecasts = slices.DeleteFunc(archive.Forecasts, func(f weathermodels.IssuedForecast) bool {
+		return f.IssuedAt.Equal(issued.IssuedAt)
+	})
+	archive.Forecasts = append(archive.Forecasts, issued)
+	slices.SortStableFunc(archive.Forecasts, func(a, b weathermodels.IssuedForecast) int { return a.IssuedAt.Compare(b.IssuedAt) })
+	if len(archive.Forecasts) > s.MaxForecasts {
+		archive.Forecasts = archive.Forecasts[len(archive.Forecasts)-s.MaxForecasts:]
+	}
+	archive.SchemaVersion = SchemaVersion
+
+	if data, err = json.MarshalIndent(archive, "", "  "); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return fileio.WriteFileAtomic(path, data, 0644)
+}
+
+// merge adds one result to its location's history file
+func (s *Store) merge(result collector.WeatherResult) error {
+	path := s.Path(result.Location.Name)
+	now := s.now()
+	savedAt := now.Format(pythonTimeLayout)
+
+	file, err := readFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		file = &File{Location: result.Location.Name, CreatedAt: savedAt, Metadata: Metadata{FirstReading: savedAt}}
+	} else if err != nil {
+		return err
+	}
+
+	reading, err := newReading(result.CurrentWeather, savedAt)
+	if err != nil {
+		return err
+	}
+	file.Readings = s.mergeReadings(file.Readings, reading)
+
+	file.SchemaVersion = SchemaVersion
+	file.Coordinates = Coordinates{Lat: result.Location.Lat, Lon: result.Location.Lon, Alt: result.Location.Alt}
+	if file.Alerts, err = marshalOrNil(result.Alerts); err != nil {
+		return err
+	}
+	if file.Marine, err = marshalOrNil(result.Marine); err != nil {
+		return err
+	}
+	file.Metadata.TotalReadings = len(file.Readings)
+	file.Metadata.LastReading = savedAt
+	if file.Metadata.FirstReading == "" {
+		file.Metadata.FirstReading = savedAt
+	}
+	if s.MaxReadings > 0 && len(file.Readings) == s.MaxReadings {
+		file.Metadata.Note = fmt.Sprintf("Limited to last %d readings", s.MaxReadings)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileio.WriteFileAtomic(path, data, 0644)
+}
+
+// mergeReadings adds reading to readings, replacing a reading with the same timestamp, sorts them
+// oldest first and keeps the newest MaxReadings. Readings without a parseable timestamp sort first.
+func (s *Store) mergeReadings(readings []json.RawMessage, reading json.RawMessage) []json.RawMessage {
+	type entry struct {
+		at  time.Time
+		raw json.RawMessage
+	}
+	newAt := readingTime(reading)
+	entries := make([]entry, 0, len(readings)+1)
+	for _, raw := range readings {
+		at := readingTime(raw)
+		if !at.IsZero() && at.Equal(newAt) {
+			continue // Replaced by the new reading
+		}
+		entries = append(entries, entry{at, raw})
+	}
+	entries = append(entries, entry{newAt, reading})
+	slices.SortStableFunc(entries, func(a, b entry) int { return a.at.Compare(b.at) })
+
+	if s.MaxReadings > 0 && len(entries) > s.MaxReadings {
+		entries = entries[len(entries)-s.MaxReadings:]
+	}
+	merged := make([]json.RawMessage, len(entries))
+	for i, e := range entries {
+		merged[i] = e.raw
+	}
+	return merged
+}
+
+// readFile reads and decodes a history file
+func readFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid time-series file %s: %w", path, err)
+	}
+	if file.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema_version %d in %s", file.SchemaVersion, path)
+	}
+	return &file, nil
+}
+
+// newReading encodes a weather point as a history reading with the time it was saved
+func newReading(point collector.WeatherPoint, savedAt string) (json.RawMessage, error) {
+	data, err := json.Marshal(point)
+	if err != nil {
+		return nil, err
+	}
+	// WeatherPoint has its own MarshalJSON, so saved_at is spliced in rather than added by embedding
+	return append([]byte(`{"saved_at":"`+savedAt+`",`), data[1:]...), nil
+}
+
+// readingTime returns a reading's timestamp, or the zero time if it has none that parses.
+// Naive timestamps written by Python's datetime.isoformat() are local time.
+func readingTime(raw json.RawMessage) time.Time {
+	var reading struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if json.Unmarshal(raw, &reading) != nil {
+		return time.Time{}
+	}
+	if t, err := weathermodels.ParseTimestamp(reading.Timestamp); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation(pythonTimeLayout, reading.Timestamp, time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// marshalOrNil encodes v, or returns nil for an empty slice so the field is omitted
+func marshalOrNil[T any](v []T) (json.RawMessage, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
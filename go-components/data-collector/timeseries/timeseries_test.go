@@ -0,0 +1,204 @@
+package timeseries
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"weather-collector/collector"
+	"weathermodels"
+)
+
+// newTestStore returns a store in a temporary directory with a fixed clock
+func newTestStore(t *testing.T, maxReadings int) *Store {
+	t.Helper()
+	return &Store{
+		Dir:         t.TempDir(),
+		MaxReadings: maxReadings,
+		now:         func() time.Time { return time.Date(2025, 10, 3, 12, 30, 0, 0, time.Local) },
+	}
+}
+
+// result returns a successful result for name with its current reading at the given hour
+func result(name string, hour int, temperature float64) collector.WeatherResult {
+	return collector.WeatherResult{
+		Location:       collector.Location{Name: name, Lat: 59.91, Lon: 10.75},
+		CurrentWeather: collector.WeatherPoint{Timestamp: time.Date(2025, 10, 3, hour, 0, 0, 0, time.UTC), Temperature: temperature},
+		Success:        true,
+	}
+}
+
+// testReading is the part of a reading the tests check; Python readings may have naive
+// timestamps that WeatherPoint does not decode
+type testReading struct {
+	Temperature float64 `json:"temperature"`
+}
+
+// readTestFile decodes a history file and its readings
+func readTestFile(t *testing.T, path string) (*File, []testReading) {
+	t.Helper()
+	file, err := readFile(path)
+	if err != nil {
+		t.Fatalf("readFile failed: %v", err)
+	}
+	points := make([]testReading, len(file.Readings))
+	for i, raw := range file.Readings {
+		if err := json.Unmarshal(raw, &points[i]); err != nil {
+			t.Fatalf("Invalid reading %d: %v", i, err)
+		}
+	}
+	return file, points
+}
+
+// TestSaveMergesRuns tests that runs are deduplicated by timestamp, sorted and capped
+func TestSaveMergesRuns(t *testing.T) {
+	store := newTestStore(t, 3)
+	ctx := context.Background()
+
+	runs := [][]collector.WeatherResult{
+		{result("Oslo, Norway", 12, 9.0)},
+		{result("Oslo, Norway", 10, 7.0)},
+		{result("Oslo, Norway", 12, 9.5)}, // Same timestamp, replaces the first run
+		{result("Oslo, Norway", 11, 8.0)},
+		{result("Oslo, Norway", 13, 10.0)},
+	}
+	for _, run := range runs {
+		if err := store.Save(ctx, run); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	file, points := readTestFile(t, store.Path("Oslo, Norway"))
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 readings, got %d", len(points))
+	}
+	for i, want := range []float64{8.0, 9.5, 10.0} {
+		if points[i].Temperature != want {
+			t.Errorf("Reading %d: expected %v, got %v", i, want, points[i].Temperature)
+		}
+	}
+	if file.SchemaVersion != SchemaVersion || file.Location != "Oslo, Norway" || file.Coordinates.Lat != 59.91 {
+		t.Errorf("Unexpected file header: %+v", file)
+	}
+	if file.Metadata.TotalReadings != 3 || file.Metadata.Note == "" {
+		t.Errorf("Unexpected metadata: %+v", file.Metadata)
+	}
+}
+
+// TestSaveSkipsFailures tests that failed results and results without a reading are not saved
+func TestSaveSkipsFailures(t *testing.T) {
+	store := newTestStore(t, 0)
+	results := []collector.WeatherResult{
+		{Location: collector.Location{Name: "Broken"}, Success: false, Error: "timeout"},
+		{Location: collector.Location{Name: "Empty"}, Success: true},
+	}
+	if err := store.Save(context.Background(), results); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	entries, _ := os.ReadDir(store.Dir)
+	if len(entries) != 0 {
+		t.Errorf("Expected no files, got %d", len(entries))
+	}
+}
+
+// TestSaveKeepsPythonReadings tests that readings written by the Python app keep their fields and
+// are ordered by their naive local timestamps
+func TestSaveKeepsPythonReadings(t *testing.T) {
+	store := newTestStore(t, 0)
+	existing := `{
+		"location": "New York",
+		"coordinates": {"lat": 40.71, "lon": -74.0},
+		"created_at": "2025-10-01T08:00:00.123456",
+		"readings": [
+			{"timestamp": "2025-10-04T09:00:00", "temperature": 15.0, "saved_at": "2025-10-04T09:00:01", "source": "python"}
+		],
+		"metadata": {"total_readings": 1, "first_reading": "2025-10-04T09:00:01"}
+	}`
+	path := filepath.Join(store.Dir, "New_York.json")
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save(context.Background(), []collector.WeatherResult{result("New York", 8, 12.0)}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	file, points := readTestFile(t, path)
+	if len(points) != 2 || points[0].Temperature != 12.0 {
+		t.Fatalf("Expected the new reading first, got %+v", points)
+	}
+	var python map[string]any
+	if err := json.Unmarshal(file.Readings[1], &python); err != nil || python["source"] != "python" {
+		t.Errorf("Expected the Python reading to keep its fields, got %s", file.Readings[1])
+	}
+	if file.CreatedAt != "2025-10-01T08:00:00.123456" || file.Metadata.FirstReading != "2025-10-04T09:00:01" {
+		t.Errorf("Expected creation metadata to be kept, got %q / %+v", file.CreatedAt, file.Metadata)
+	}
+	if file.Metadata.LastReading != "2025-10-03T12:30:00" {
+		t.Errorf("Expected last_reading to be the save time, got %q", file.Metadata.LastReading)
+	}
+}
+
+// TestSaveRejectsNewerSchema tests that a file written by a newer version is left untouched
+func TestSaveRejectsNewerSchema(t *testing.T) {
+	store := newTestStore(t, 0)
+	path := store.Path("Oslo")
+	newer := []byte(`{"schema_version": 99, "location": "Oslo", "readings": []}`)
+	if err := os.WriteFile(path, newer, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save(context.Background(), []collector.WeatherResult{result("Oslo", 12, 9.0)}); err == nil {
+		t.Error("Expected an error for a newer schema_version")
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(newer) {
+		t.Errorf("Expected the file to be unchanged, got %s", data)
+	}
+}
+
+// TestSaveArchivesForecasts tests that each run's forecast from the current reading on is kept,
+// replacing a forecast issued at the same time and capped to the newest forecasts
+func TestSaveArchivesForecasts(t *testing.T) {
+	store := newTestStore(t, 0)
+	store.MaxForecasts = 2
+	issued := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	runHours := []int{0, 0, 2, 6} // The second run is issued at the same time as the first
+	for i, temperature := range []float64{9, 10, 11, 12} {
+		now := issued.Add(time.Duration(runHours[i]) * time.Hour)
+		store.now = func() time.Time { return now }
+		run := result("Oslo", 12, 9.0)
+		for hour := 11; hour < 14; hour++ {
+			run.Forecast = append(run.Forecast, collector.WeatherPoint{Timestamp: time.Date(2025, 10, 3, hour, 0, 0, 0, time.UTC), Temperature: temperature})
+		}
+		if err := store.Save(context.Background(), []collector.WeatherResult{run}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(store.ForecastPath("Oslo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archive weathermodels.ForecastArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Forecasts) != 2 || !archive.Forecasts[0].IssuedAt.Equal(issued.Add(2*time.Hour)) || !archive.Forecasts[1].IssuedAt.Equal(issued.Add(6*time.Hour)) {
+		t.Fatalf("Expected the forecasts of the last two runs, got %+v", archive.Forecasts)
+	}
+	if points := archive.Forecasts[1].Points; len(points) != 2 || points[0].Temperature != 12 || points[0].Timestamp.Hour() != 12 {
+		t.Errorf("Expected the points from 12:00 on, got %+v", points)
+	}
+}
+
+// TestPath tests that file names match the Python app's
+func TestPath(t *testing.T) {
+	store := &Store{Dir: "ts"}
+	if got, want := store.Path("Rio de Janeiro, Brazil/RJ"), filepath.Join("ts", "Rio_de_Janeiro_Brazil_RJ.json"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
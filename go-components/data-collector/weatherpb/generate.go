@@ -0,0 +1,5 @@
+// Package weatherpb contains the protobuf/gRPC bindings for weather.proto, the schema
+// shared with the Python orchestrator. Regenerate with `go generate` after editing the schema.
+package weatherpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative weather.proto
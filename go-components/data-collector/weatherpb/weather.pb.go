@@ -0,0 +1,1629 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: weather.proto
+
+package weatherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Location is a geographic location for weather data collection
+type Location struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Lat   float64                `protobuf:"fixed64,2,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon   float64                `protobuf:"fixed64,3,opt,name=lon,proto3" json:"lon,omitempty"`
+	// Altitude in meters above sea level (0 = unknown)
+	Alt int32 `protobuf:"varint,4,opt,name=alt,proto3" json:"alt,omitempty"`
+	// Also collect an ocean forecast (waves, sea temperature, currents)
+	Coastal bool `protobuf:"varint,5,opt,name=coastal,proto3" json:"coastal,omitempty"`
+	// Weather provider for this location ("" = api.provider)
+	Provider string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Seconds allowed for this location (0 = performance.worker_timeout)
+	Timeout float64 `protobuf:"fixed64,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Higher priorities are fetched first
+	Priority      int32 `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_weather_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Location) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Location) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Location) GetAlt() int32 {
+	if x != nil {
+		return x.Alt
+	}
+	return 0
+}
+
+func (x *Location) GetCoastal() bool {
+	if x != nil {
+		return x.Coastal
+	}
+	return false
+}
+
+func (x *Location) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Location) GetTimeout() float64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *Location) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+// WeatherPoint is a single weather reading with an RFC3339 timestamp
+type WeatherPoint struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp                string                 `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Temperature              float64                `protobuf:"fixed64,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	Pressure                 float64                `protobuf:"fixed64,3,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	Humidity                 float64                `protobuf:"fixed64,4,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	WindSpeed                float64                `protobuf:"fixed64,5,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindDirection            float64                `protobuf:"fixed64,6,opt,name=wind_direction,json=windDirection,proto3" json:"wind_direction,omitempty"`
+	CloudCover               float64                `protobuf:"fixed64,7,opt,name=cloud_cover,json=cloudCover,proto3" json:"cloud_cover,omitempty"`
+	PrecipitationMm          float64                `protobuf:"fixed64,8,opt,name=precipitation_mm,json=precipitationMm,proto3" json:"precipitation_mm,omitempty"`
+	PrecipitationProbability float64                `protobuf:"fixed64,9,opt,name=precipitation_probability,json=precipitationProbability,proto3" json:"precipitation_probability,omitempty"`
+	SymbolCode               string                 `protobuf:"bytes,10,opt,name=symbol_code,json=symbolCode,proto3" json:"symbol_code,omitempty"`
+	DewPoint                 float64                `protobuf:"fixed64,11,opt,name=dew_point,json=dewPoint,proto3" json:"dew_point,omitempty"`
+	UvIndex                  float64                `protobuf:"fixed64,12,opt,name=uv_index,json=uvIndex,proto3" json:"uv_index,omitempty"`
+	WindGust                 float64                `protobuf:"fixed64,13,opt,name=wind_gust,json=windGust,proto3" json:"wind_gust,omitempty"`
+	FogAreaFraction          float64                `protobuf:"fixed64,14,opt,name=fog_area_fraction,json=fogAreaFraction,proto3" json:"fog_area_fraction,omitempty"`
+	// "observation", "forecast" or "nowcast" ("" = not recorded)
+	Source        string `protobuf:"bytes,15,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherPoint) Reset() {
+	*x = WeatherPoint{}
+	mi := &file_weather_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherPoint) ProtoMessage() {}
+
+func (x *WeatherPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherPoint.ProtoReflect.Descriptor instead.
+func (*WeatherPoint) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WeatherPoint) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *WeatherPoint) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetPressure() float64 {
+	if x != nil {
+		return x.Pressure
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetHumidity() float64 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetWindSpeed() float64 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetWindDirection() float64 {
+	if x != nil {
+		return x.WindDirection
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetCloudCover() float64 {
+	if x != nil {
+		return x.CloudCover
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetPrecipitationMm() float64 {
+	if x != nil {
+		return x.PrecipitationMm
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetPrecipitationProbability() float64 {
+	if x != nil {
+		return x.PrecipitationProbability
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetSymbolCode() string {
+	if x != nil {
+		return x.SymbolCode
+	}
+	return ""
+}
+
+func (x *WeatherPoint) GetDewPoint() float64 {
+	if x != nil {
+		return x.DewPoint
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetUvIndex() float64 {
+	if x != nil {
+		return x.UvIndex
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetWindGust() float64 {
+	if x != nil {
+		return x.WindGust
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetFogAreaFraction() float64 {
+	if x != nil {
+		return x.FogAreaFraction
+	}
+	return 0
+}
+
+func (x *WeatherPoint) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// WeatherResult is the collected weather data for a location
+type WeatherResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Location       *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	CurrentWeather *WeatherPoint          `protobuf:"bytes,2,opt,name=current_weather,json=currentWeather,proto3" json:"current_weather,omitempty"`
+	Forecast       []*WeatherPoint        `protobuf:"bytes,3,rep,name=forecast,proto3" json:"forecast,omitempty"`
+	Success        bool                   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	Error          string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Attempts       int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Source         string                 `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	Alerts         []*Alert               `protobuf:"bytes,8,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	// 5-minute precipitation nowcast; precipitation_mm holds the rate in mm/h
+	Nowcast []*WeatherPoint `protobuf:"bytes,9,rep,name=nowcast,proto3" json:"nowcast,omitempty"`
+	// Ocean forecast for coastal locations
+	Marine []*MarinePoint `protobuf:"bytes,10,rep,name=marine,proto3" json:"marine,omitempty"`
+	// Version of the result layout (collector.SchemaVersion)
+	SchemaVersion int32 `protobuf:"varint,11,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// Failure class when success is false, e.g. "timeout" (collector.ErrorCode)
+	ErrorCode string `protobuf:"bytes,12,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	// How the result was collected
+	Meta          *CollectionMeta `protobuf:"bytes,13,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherResult) Reset() {
+	*x = WeatherResult{}
+	mi := &file_weather_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherResult) ProtoMessage() {}
+
+func (x *WeatherResult) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherResult.ProtoReflect.Descriptor instead.
+func (*WeatherResult) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WeatherResult) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *WeatherResult) GetCurrentWeather() *WeatherPoint {
+	if x != nil {
+		return x.CurrentWeather
+	}
+	return nil
+}
+
+func (x *WeatherResult) GetForecast() []*WeatherPoint {
+	if x != nil {
+		return x.Forecast
+	}
+	return nil
+}
+
+func (x *WeatherResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *WeatherResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WeatherResult) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WeatherResult) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *WeatherResult) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *WeatherResult) GetNowcast() []*WeatherPoint {
+	if x != nil {
+		return x.Nowcast
+	}
+	return nil
+}
+
+func (x *WeatherResult) GetMarine() []*MarinePoint {
+	if x != nil {
+		return x.Marine
+	}
+	return nil
+}
+
+func (x *WeatherResult) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *WeatherResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *WeatherResult) GetMeta() *CollectionMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+// CollectionMeta describes how a result was collected (latency, retries, provider, cache, HTTP status)
+type CollectionMeta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DurationMs    float64                `protobuf:"fixed64,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Retries       int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	CacheHit      bool                   `protobuf:"varint,4,opt,name=cache_hit,json=cacheHit,proto3" json:"cache_hit,omitempty"`
+	HttpStatus    int32                  `protobuf:"varint,5,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionMeta) Reset() {
+	*x = CollectionMeta{}
+	mi := &file_weather_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionMeta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionMeta) ProtoMessage() {}
+
+func (x *CollectionMeta) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionMeta.ProtoReflect.Descriptor instead.
+func (*CollectionMeta) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CollectionMeta) GetDurationMs() float64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *CollectionMeta) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *CollectionMeta) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *CollectionMeta) GetCacheHit() bool {
+	if x != nil {
+		return x.CacheHit
+	}
+	return false
+}
+
+func (x *CollectionMeta) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+// MarinePoint is a single ocean forecast reading (met.no oceanforecast)
+type MarinePoint struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp        string                 `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	WaveHeight       float64                `protobuf:"fixed64,2,opt,name=wave_height,json=waveHeight,proto3" json:"wave_height,omitempty"`
+	WaveDirection    float64                `protobuf:"fixed64,3,opt,name=wave_direction,json=waveDirection,proto3" json:"wave_direction,omitempty"`
+	SeaTemperature   float64                `protobuf:"fixed64,4,opt,name=sea_temperature,json=seaTemperature,proto3" json:"sea_temperature,omitempty"`
+	CurrentSpeed     float64                `protobuf:"fixed64,5,opt,name=current_speed,json=currentSpeed,proto3" json:"current_speed,omitempty"`
+	CurrentDirection float64                `protobuf:"fixed64,6,opt,name=current_direction,json=currentDirection,proto3" json:"current_direction,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MarinePoint) Reset() {
+	*x = MarinePoint{}
+	mi := &file_weather_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarinePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarinePoint) ProtoMessage() {}
+
+func (x *MarinePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarinePoint.ProtoReflect.Descriptor instead.
+func (*MarinePoint) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MarinePoint) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *MarinePoint) GetWaveHeight() float64 {
+	if x != nil {
+		return x.WaveHeight
+	}
+	return 0
+}
+
+func (x *MarinePoint) GetWaveDirection() float64 {
+	if x != nil {
+		return x.WaveDirection
+	}
+	return 0
+}
+
+func (x *MarinePoint) GetSeaTemperature() float64 {
+	if x != nil {
+		return x.SeaTemperature
+	}
+	return 0
+}
+
+func (x *MarinePoint) GetCurrentSpeed() float64 {
+	if x != nil {
+		return x.CurrentSpeed
+	}
+	return 0
+}
+
+func (x *MarinePoint) GetCurrentDirection() float64 {
+	if x != nil {
+		return x.CurrentDirection
+	}
+	return 0
+}
+
+// Alert is an official severe-weather warning (met.no MetAlerts)
+type Alert struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Event          string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Title          string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Area           string                 `protobuf:"bytes,4,opt,name=area,proto3" json:"area,omitempty"`
+	AwarenessLevel string                 `protobuf:"bytes,5,opt,name=awareness_level,json=awarenessLevel,proto3" json:"awareness_level,omitempty"`
+	Severity       string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	Certainty      string                 `protobuf:"bytes,7,opt,name=certainty,proto3" json:"certainty,omitempty"`
+	Description    string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	Instruction    string                 `protobuf:"bytes,9,opt,name=instruction,proto3" json:"instruction,omitempty"`
+	Onset          string                 `protobuf:"bytes,10,opt,name=onset,proto3" json:"onset,omitempty"`
+	Ends           string                 `protobuf:"bytes,11,opt,name=ends,proto3" json:"ends,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_weather_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Alert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Alert) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Alert) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Alert) GetArea() string {
+	if x != nil {
+		return x.Area
+	}
+	return ""
+}
+
+func (x *Alert) GetAwarenessLevel() string {
+	if x != nil {
+		return x.AwarenessLevel
+	}
+	return ""
+}
+
+func (x *Alert) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Alert) GetCertainty() string {
+	if x != nil {
+		return x.Certainty
+	}
+	return ""
+}
+
+func (x *Alert) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Alert) GetInstruction() string {
+	if x != nil {
+		return x.Instruction
+	}
+	return ""
+}
+
+func (x *Alert) GetOnset() string {
+	if x != nil {
+		return x.Onset
+	}
+	return ""
+}
+
+func (x *Alert) GetEnds() string {
+	if x != nil {
+		return x.Ends
+	}
+	return ""
+}
+
+// Trend is a weather trend with direction and confidence
+type Trend struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variable      string                 `protobuf:"bytes,1,opt,name=variable,proto3" json:"variable,omitempty"`
+	Trend         string                 `protobuf:"bytes,2,opt,name=trend,proto3" json:"trend,omitempty"`
+	RateOfChange  float64                `protobuf:"fixed64,3,opt,name=rate_of_change,json=rateOfChange,proto3" json:"rate_of_change,omitempty"`
+	Confidence    float64                `protobuf:"fixed64,4,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Duration      string                 `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trend) Reset() {
+	*x = Trend{}
+	mi := &file_weather_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trend) ProtoMessage() {}
+
+func (x *Trend) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trend.ProtoReflect.Descriptor instead.
+func (*Trend) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Trend) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *Trend) GetTrend() string {
+	if x != nil {
+		return x.Trend
+	}
+	return ""
+}
+
+func (x *Trend) GetRateOfChange() float64 {
+	if x != nil {
+		return x.RateOfChange
+	}
+	return 0
+}
+
+func (x *Trend) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Trend) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+// Anomaly is a detected unusual reading
+type Anomaly struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variable      string                 `protobuf:"bytes,1,opt,name=variable,proto3" json:"variable,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Severity      string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Anomaly) Reset() {
+	*x = Anomaly{}
+	mi := &file_weather_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Anomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Anomaly) ProtoMessage() {}
+
+func (x *Anomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Anomaly.ProtoReflect.Descriptor instead.
+func (*Anomaly) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Anomaly) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *Anomaly) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Anomaly) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Anomaly) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Anomaly) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Anomaly) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// Pattern is an identified weather pattern
+type Pattern struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Confidence    float64                `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Strength      float64                `protobuf:"fixed64,4,opt,name=strength,proto3" json:"strength,omitempty"`
+	Variables     []string               `protobuf:"bytes,5,rep,name=variables,proto3" json:"variables,omitempty"`
+	Readings      []*WeatherPoint        `protobuf:"bytes,6,rep,name=readings,proto3" json:"readings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pattern) Reset() {
+	*x = Pattern{}
+	mi := &file_weather_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pattern) ProtoMessage() {}
+
+func (x *Pattern) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pattern.ProtoReflect.Descriptor instead.
+func (*Pattern) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Pattern) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Pattern) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Pattern) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Pattern) GetStrength() float64 {
+	if x != nil {
+		return x.Strength
+	}
+	return 0
+}
+
+func (x *Pattern) GetVariables() []string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *Pattern) GetReadings() []*WeatherPoint {
+	if x != nil {
+		return x.Readings
+	}
+	return nil
+}
+
+// StatisticalData holds summary statistics for one variable
+type StatisticalData struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Variable        string                 `protobuf:"bytes,1,opt,name=variable,proto3" json:"variable,omitempty"`
+	Mean            float64                `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	Median          float64                `protobuf:"fixed64,3,opt,name=median,proto3" json:"median,omitempty"`
+	Min             float64                `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max             float64                `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	StdDev          float64                `protobuf:"fixed64,6,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	SampleSize      int32                  `protobuf:"varint,7,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	ConfidenceLevel float64                `protobuf:"fixed64,8,opt,name=confidence_level,json=confidenceLevel,proto3" json:"confidence_level,omitempty"`
+	TrendStrength   float64                `protobuf:"fixed64,9,opt,name=trend_strength,json=trendStrength,proto3" json:"trend_strength,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StatisticalData) Reset() {
+	*x = StatisticalData{}
+	mi := &file_weather_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatisticalData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticalData) ProtoMessage() {}
+
+func (x *StatisticalData) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticalData.ProtoReflect.Descriptor instead.
+func (*StatisticalData) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatisticalData) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *StatisticalData) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetSampleSize() int32 {
+	if x != nil {
+		return x.SampleSize
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetConfidenceLevel() float64 {
+	if x != nil {
+		return x.ConfidenceLevel
+	}
+	return 0
+}
+
+func (x *StatisticalData) GetTrendStrength() float64 {
+	if x != nil {
+		return x.TrendStrength
+	}
+	return 0
+}
+
+// WeatherSummary contains high-level weather information
+type WeatherSummary struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CurrentTemperature float64                `protobuf:"fixed64,1,opt,name=current_temperature,json=currentTemperature,proto3" json:"current_temperature,omitempty"`
+	MinTemperature     float64                `protobuf:"fixed64,2,opt,name=min_temperature,json=minTemperature,proto3" json:"min_temperature,omitempty"`
+	MaxTemperature     float64                `protobuf:"fixed64,3,opt,name=max_temperature,json=maxTemperature,proto3" json:"max_temperature,omitempty"`
+	CurrentPressure    float64                `protobuf:"fixed64,4,opt,name=current_pressure,json=currentPressure,proto3" json:"current_pressure,omitempty"`
+	MinPressure        float64                `protobuf:"fixed64,5,opt,name=min_pressure,json=minPressure,proto3" json:"min_pressure,omitempty"`
+	MaxPressure        float64                `protobuf:"fixed64,6,opt,name=max_pressure,json=maxPressure,proto3" json:"max_pressure,omitempty"`
+	TrendNextHours     string                 `protobuf:"bytes,7,opt,name=trend_next_hours,json=trendNextHours,proto3" json:"trend_next_hours,omitempty"`
+	ForecastSummary    string                 `protobuf:"bytes,8,opt,name=forecast_summary,json=forecastSummary,proto3" json:"forecast_summary,omitempty"`
+	Confidence         float64                `protobuf:"fixed64,9,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Alerts             []string               `protobuf:"bytes,10,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WeatherSummary) Reset() {
+	*x = WeatherSummary{}
+	mi := &file_weather_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherSummary) ProtoMessage() {}
+
+func (x *WeatherSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherSummary.ProtoReflect.Descriptor instead.
+func (*WeatherSummary) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WeatherSummary) GetCurrentTemperature() float64 {
+	if x != nil {
+		return x.CurrentTemperature
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetMinTemperature() float64 {
+	if x != nil {
+		return x.MinTemperature
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetMaxTemperature() float64 {
+	if x != nil {
+		return x.MaxTemperature
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetCurrentPressure() float64 {
+	if x != nil {
+		return x.CurrentPressure
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetMinPressure() float64 {
+	if x != nil {
+		return x.MinPressure
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetMaxPressure() float64 {
+	if x != nil {
+		return x.MaxPressure
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetTrendNextHours() string {
+	if x != nil {
+		return x.TrendNextHours
+	}
+	return ""
+}
+
+func (x *WeatherSummary) GetForecastSummary() string {
+	if x != nil {
+		return x.ForecastSummary
+	}
+	return ""
+}
+
+func (x *WeatherSummary) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *WeatherSummary) GetAlerts() []string {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+// AnalysisResult is the complete pattern-engine output for a location
+type AnalysisResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AnalysisType    string                 `protobuf:"bytes,1,opt,name=analysis_type,json=analysisType,proto3" json:"analysis_type,omitempty"`
+	Timeframe       string                 `protobuf:"bytes,2,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	Location        string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	GeneratedAt     string                 `protobuf:"bytes,4,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	Trends          []*Trend               `protobuf:"bytes,5,rep,name=trends,proto3" json:"trends,omitempty"`
+	Anomalies       []*Anomaly             `protobuf:"bytes,6,rep,name=anomalies,proto3" json:"anomalies,omitempty"`
+	Patterns        []*Pattern             `protobuf:"bytes,7,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	WeatherSummary  *WeatherSummary        `protobuf:"bytes,8,opt,name=weather_summary,json=weatherSummary,proto3" json:"weather_summary,omitempty"`
+	StatisticalData []*StatisticalData     `protobuf:"bytes,9,rep,name=statistical_data,json=statisticalData,proto3" json:"statistical_data,omitempty"`
+	// Version of the analysis layout (models.AnalysisSchemaVersion)
+	SchemaVersion int32 `protobuf:"varint,10,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisResult) Reset() {
+	*x = AnalysisResult{}
+	mi := &file_weather_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResult) ProtoMessage() {}
+
+func (x *AnalysisResult) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResult.ProtoReflect.Descriptor instead.
+func (*AnalysisResult) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AnalysisResult) GetAnalysisType() string {
+	if x != nil {
+		return x.AnalysisType
+	}
+	return ""
+}
+
+func (x *AnalysisResult) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *AnalysisResult) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *AnalysisResult) GetGeneratedAt() string {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return ""
+}
+
+func (x *AnalysisResult) GetTrends() []*Trend {
+	if x != nil {
+		return x.Trends
+	}
+	return nil
+}
+
+func (x *AnalysisResult) GetAnomalies() []*Anomaly {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+func (x *AnalysisResult) GetPatterns() []*Pattern {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+func (x *AnalysisResult) GetWeatherSummary() *WeatherSummary {
+	if x != nil {
+		return x.WeatherSummary
+	}
+	return nil
+}
+
+func (x *AnalysisResult) GetStatisticalData() []*StatisticalData {
+	if x != nil {
+		return x.StatisticalData
+	}
+	return nil
+}
+
+func (x *AnalysisResult) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+// CollectRequest asks for weather for a batch of locations
+type CollectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locations     []*Location            `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectRequest) Reset() {
+	*x = CollectRequest{}
+	mi := &file_weather_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectRequest) ProtoMessage() {}
+
+func (x *CollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectRequest.ProtoReflect.Descriptor instead.
+func (*CollectRequest) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CollectRequest) GetLocations() []*Location {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+// CollectResponse returns results in the same order as the request
+type CollectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*WeatherResult       `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectResponse) Reset() {
+	*x = CollectResponse{}
+	mi := &file_weather_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectResponse) ProtoMessage() {}
+
+func (x *CollectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectResponse.ProtoReflect.Descriptor instead.
+func (*CollectResponse) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CollectResponse) GetResults() []*WeatherResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_weather_proto protoreflect.FileDescriptor
+
+const file_weather_proto_rawDesc = "" +
+	"\n" +
+	"\rweather.proto\x12\n" +
+	"weather.v1\"\xc0\x01\n" +
+	"\bLocation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03lat\x18\x02 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x03 \x01(\x01R\x03lon\x12\x10\n" +
+	"\x03alt\x18\x04 \x01(\x05R\x03alt\x12\x18\n" +
+	"\acoastal\x18\x05 \x01(\bR\acoastal\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x12\x18\n" +
+	"\atimeout\x18\a \x01(\x01R\atimeout\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\"\x8f\x04\n" +
+	"\fWeatherPoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\x12\x1a\n" +
+	"\bpressure\x18\x03 \x01(\x01R\bpressure\x12\x1a\n" +
+	"\bhumidity\x18\x04 \x01(\x01R\bhumidity\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\x05 \x01(\x01R\twindSpeed\x12%\n" +
+	"\x0ewind_direction\x18\x06 \x01(\x01R\rwindDirection\x12\x1f\n" +
+	"\vcloud_cover\x18\a \x01(\x01R\n" +
+	"cloudCover\x12)\n" +
+	"\x10precipitation_mm\x18\b \x01(\x01R\x0fprecipitationMm\x12;\n" +
+	"\x19precipitation_probability\x18\t \x01(\x01R\x18precipitationProbability\x12\x1f\n" +
+	"\vsymbol_code\x18\n" +
+	" \x01(\tR\n" +
+	"symbolCode\x12\x1b\n" +
+	"\tdew_point\x18\v \x01(\x01R\bdewPoint\x12\x19\n" +
+	"\buv_index\x18\f \x01(\x01R\auvIndex\x12\x1b\n" +
+	"\twind_gust\x18\r \x01(\x01R\bwindGust\x12*\n" +
+	"\x11fog_area_fraction\x18\x0e \x01(\x01R\x0ffogAreaFraction\x12\x16\n" +
+	"\x06source\x18\x0f \x01(\tR\x06source\"\xa4\x04\n" +
+	"\rWeatherResult\x120\n" +
+	"\blocation\x18\x01 \x01(\v2\x14.weather.v1.LocationR\blocation\x12A\n" +
+	"\x0fcurrent_weather\x18\x02 \x01(\v2\x18.weather.v1.WeatherPointR\x0ecurrentWeather\x124\n" +
+	"\bforecast\x18\x03 \x03(\v2\x18.weather.v1.WeatherPointR\bforecast\x12\x18\n" +
+	"\asuccess\x18\x04 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x16\n" +
+	"\x06source\x18\a \x01(\tR\x06source\x12)\n" +
+	"\x06alerts\x18\b \x03(\v2\x11.weather.v1.AlertR\x06alerts\x122\n" +
+	"\anowcast\x18\t \x03(\v2\x18.weather.v1.WeatherPointR\anowcast\x12/\n" +
+	"\x06marine\x18\n" +
+	" \x03(\v2\x17.weather.v1.MarinePointR\x06marine\x12%\n" +
+	"\x0eschema_version\x18\v \x01(\x05R\rschemaVersion\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\f \x01(\tR\terrorCode\x12.\n" +
+	"\x04meta\x18\r \x01(\v2\x1a.weather.v1.CollectionMetaR\x04meta\"\xa5\x01\n" +
+	"\x0eCollectionMeta\x12\x1f\n" +
+	"\vduration_ms\x18\x01 \x01(\x01R\n" +
+	"durationMs\x12\x18\n" +
+	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x1b\n" +
+	"\tcache_hit\x18\x04 \x01(\bR\bcacheHit\x12\x1f\n" +
+	"\vhttp_status\x18\x05 \x01(\x05R\n" +
+	"httpStatus\"\xee\x01\n" +
+	"\vMarinePoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x12\x1f\n" +
+	"\vwave_height\x18\x02 \x01(\x01R\n" +
+	"waveHeight\x12%\n" +
+	"\x0ewave_direction\x18\x03 \x01(\x01R\rwaveDirection\x12'\n" +
+	"\x0fsea_temperature\x18\x04 \x01(\x01R\x0eseaTemperature\x12#\n" +
+	"\rcurrent_speed\x18\x05 \x01(\x01R\fcurrentSpeed\x12+\n" +
+	"\x11current_direction\x18\x06 \x01(\x01R\x10currentDirection\"\xa8\x02\n" +
+	"\x05Alert\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
+	"\x04area\x18\x04 \x01(\tR\x04area\x12'\n" +
+	"\x0fawareness_level\x18\x05 \x01(\tR\x0eawarenessLevel\x12\x1a\n" +
+	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x1c\n" +
+	"\tcertainty\x18\a \x01(\tR\tcertainty\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12 \n" +
+	"\vinstruction\x18\t \x01(\tR\vinstruction\x12\x14\n" +
+	"\x05onset\x18\n" +
+	" \x01(\tR\x05onset\x12\x12\n" +
+	"\x04ends\x18\v \x01(\tR\x04ends\"\x9b\x01\n" +
+	"\x05Trend\x12\x1a\n" +
+	"\bvariable\x18\x01 \x01(\tR\bvariable\x12\x14\n" +
+	"\x05trend\x18\x02 \x01(\tR\x05trend\x12$\n" +
+	"\x0erate_of_change\x18\x03 \x01(\x01R\frateOfChange\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x04 \x01(\x01R\n" +
+	"confidence\x12\x1a\n" +
+	"\bduration\x18\x05 \x01(\tR\bduration\"\xa7\x01\n" +
+	"\aAnomaly\x12\x1a\n" +
+	"\bvariable\x18\x01 \x01(\tR\bvariable\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
+	"\bseverity\x18\x03 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x1c\n" +
+	"\tthreshold\x18\x05 \x01(\x01R\tthreshold\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\tR\ttimestamp\"\xcf\x01\n" +
+	"\aPattern\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\x12\x1a\n" +
+	"\bstrength\x18\x04 \x01(\x01R\bstrength\x12\x1c\n" +
+	"\tvariables\x18\x05 \x03(\tR\tvariables\x124\n" +
+	"\breadings\x18\x06 \x03(\v2\x18.weather.v1.WeatherPointR\breadings\"\x89\x02\n" +
+	"\x0fStatisticalData\x12\x1a\n" +
+	"\bvariable\x18\x01 \x01(\tR\bvariable\x12\x12\n" +
+	"\x04mean\x18\x02 \x01(\x01R\x04mean\x12\x16\n" +
+	"\x06median\x18\x03 \x01(\x01R\x06median\x12\x10\n" +
+	"\x03min\x18\x04 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x05 \x01(\x01R\x03max\x12\x17\n" +
+	"\astd_dev\x18\x06 \x01(\x01R\x06stdDev\x12\x1f\n" +
+	"\vsample_size\x18\a \x01(\x05R\n" +
+	"sampleSize\x12)\n" +
+	"\x10confidence_level\x18\b \x01(\x01R\x0fconfidenceLevel\x12%\n" +
+	"\x0etrend_strength\x18\t \x01(\x01R\rtrendStrength\"\x91\x03\n" +
+	"\x0eWeatherSummary\x12/\n" +
+	"\x13current_temperature\x18\x01 \x01(\x01R\x12currentTemperature\x12'\n" +
+	"\x0fmin_temperature\x18\x02 \x01(\x01R\x0eminTemperature\x12'\n" +
+	"\x0fmax_temperature\x18\x03 \x01(\x01R\x0emaxTemperature\x12)\n" +
+	"\x10current_pressure\x18\x04 \x01(\x01R\x0fcurrentPressure\x12!\n" +
+	"\fmin_pressure\x18\x05 \x01(\x01R\vminPressure\x12!\n" +
+	"\fmax_pressure\x18\x06 \x01(\x01R\vmaxPressure\x12(\n" +
+	"\x10trend_next_hours\x18\a \x01(\tR\x0etrendNextHours\x12)\n" +
+	"\x10forecast_summary\x18\b \x01(\tR\x0fforecastSummary\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\t \x01(\x01R\n" +
+	"confidence\x12\x16\n" +
+	"\x06alerts\x18\n" +
+	" \x03(\tR\x06alerts\"\xd5\x03\n" +
+	"\x0eAnalysisResult\x12#\n" +
+	"\ranalysis_type\x18\x01 \x01(\tR\fanalysisType\x12\x1c\n" +
+	"\ttimeframe\x18\x02 \x01(\tR\ttimeframe\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12!\n" +
+	"\fgenerated_at\x18\x04 \x01(\tR\vgeneratedAt\x12)\n" +
+	"\x06trends\x18\x05 \x03(\v2\x11.weather.v1.TrendR\x06trends\x121\n" +
+	"\tanomalies\x18\x06 \x03(\v2\x13.weather.v1.AnomalyR\tanomalies\x12/\n" +
+	"\bpatterns\x18\a \x03(\v2\x13.weather.v1.PatternR\bpatterns\x12C\n" +
+	"\x0fweather_summary\x18\b \x01(\v2\x1a.weather.v1.WeatherSummaryR\x0eweatherSummary\x12F\n" +
+	"\x10statistical_data\x18\t \x03(\v2\x1b.weather.v1.StatisticalDataR\x0fstatisticalData\x12%\n" +
+	"\x0eschema_version\x18\n" +
+	" \x01(\x05R\rschemaVersion\"D\n" +
+	"\x0eCollectRequest\x122\n" +
+	"\tlocations\x18\x01 \x03(\v2\x14.weather.v1.LocationR\tlocations\"F\n" +
+	"\x0fCollectResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.weather.v1.WeatherResultR\aresults2\x9c\x01\n" +
+	"\x10WeatherCollector\x12B\n" +
+	"\aCollect\x12\x1a.weather.v1.CollectRequest\x1a\x1b.weather.v1.CollectResponse\x12D\n" +
+	"\rStreamCollect\x12\x14.weather.v1.Location\x1a\x19.weather.v1.WeatherResult(\x010\x01B\x1dZ\x1bweather-collector/weatherpbb\x06proto3"
+
+var (
+	file_weather_proto_rawDescOnce sync.Once
+	file_weather_proto_rawDescData []byte
+)
+
+func file_weather_proto_rawDescGZIP() []byte {
+	file_weather_proto_rawDescOnce.Do(func() {
+		file_weather_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_weather_proto_rawDesc), len(file_weather_proto_rawDesc)))
+	})
+	return file_weather_proto_rawDescData
+}
+
+var file_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_weather_proto_goTypes = []any{
+	(*Location)(nil),        // 0: weather.v1.Location
+	(*WeatherPoint)(nil),    // 1: weather.v1.WeatherPoint
+	(*WeatherResult)(nil),   // 2: weather.v1.WeatherResult
+	(*CollectionMeta)(nil),  // 3: weather.v1.CollectionMeta
+	(*MarinePoint)(nil),     // 4: weather.v1.MarinePoint
+	(*Alert)(nil),           // 5: weather.v1.Alert
+	(*Trend)(nil),           // 6: weather.v1.Trend
+	(*Anomaly)(nil),         // 7: weather.v1.Anomaly
+	(*Pattern)(nil),         // 8: weather.v1.Pattern
+	(*StatisticalData)(nil), // 9: weather.v1.StatisticalData
+	(*WeatherSummary)(nil),  // 10: weather.v1.WeatherSummary
+	(*AnalysisResult)(nil),  // 11: weather.v1.AnalysisResult
+	(*CollectRequest)(nil),  // 12: weather.v1.CollectRequest
+	(*CollectResponse)(nil), // 13: weather.v1.CollectResponse
+}
+var file_weather_proto_depIdxs = []int32{
+	0,  // 0: weather.v1.WeatherResult.location:type_name -> weather.v1.Location
+	1,  // 1: weather.v1.WeatherResult.current_weather:type_name -> weather.v1.WeatherPoint
+	1,  // 2: weather.v1.WeatherResult.forecast:type_name -> weather.v1.WeatherPoint
+	5,  // 3: weather.v1.WeatherResult.alerts:type_name -> weather.v1.Alert
+	1,  // 4: weather.v1.WeatherResult.nowcast:type_name -> weather.v1.WeatherPoint
+	4,  // 5: weather.v1.WeatherResult.marine:type_name -> weather.v1.MarinePoint
+	3,  // 6: weather.v1.WeatherResult.meta:type_name -> weather.v1.CollectionMeta
+	1,  // 7: weather.v1.Pattern.readings:type_name -> weather.v1.WeatherPoint
+	6,  // 8: weather.v1.AnalysisResult.trends:type_name -> weather.v1.Trend
+	7,  // 9: weather.v1.AnalysisResult.anomalies:type_name -> weather.v1.Anomaly
+	8,  // 10: weather.v1.AnalysisResult.patterns:type_name -> weather.v1.Pattern
+	10, // 11: weather.v1.AnalysisResult.weather_summary:type_name -> weather.v1.WeatherSummary
+	9,  // 12: weather.v1.AnalysisResult.statistical_data:type_name -> weather.v1.StatisticalData
+	0,  // 13: weather.v1.CollectRequest.locations:type_name -> weather.v1.Location
+	2,  // 14: weather.v1.CollectResponse.results:type_name -> weather.v1.WeatherResult
+	12, // 15: weather.v1.WeatherCollector.Collect:input_type -> weather.v1.CollectRequest
+	0,  // 16: weather.v1.WeatherCollector.StreamCollect:input_type -> weather.v1.Location
+	13, // 17: weather.v1.WeatherCollector.Collect:output_type -> weather.v1.CollectResponse
+	2,  // 18: weather.v1.WeatherCollector.StreamCollect:output_type -> weather.v1.WeatherResult
+	17, // [17:19] is the sub-list for method output_type
+	15, // [15:17] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_weather_proto_init() }
+func file_weather_proto_init() {
+	if File_weather_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_weather_proto_rawDesc), len(file_weather_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_weather_proto_goTypes,
+		DependencyIndexes: file_weather_proto_depIdxs,
+		MessageInfos:      file_weather_proto_msgTypes,
+	}.Build()
+	File_weather_proto = out.File
+	file_weather_proto_goTypes = nil
+	file_weather_proto_depIdxs = nil
+}
@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: weather.proto
+
+package weatherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WeatherCollector_Collect_FullMethodName       = "/weather.v1.WeatherCollector/Collect"
+	WeatherCollector_StreamCollect_FullMethodName = "/weather.v1.WeatherCollector/StreamCollect"
+)
+
+// WeatherCollectorClient is the client API for WeatherCollector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WeatherCollector exposes collection to the Python orchestrator
+type WeatherCollectorClient interface {
+	// Collect fetches weather for a batch of locations and returns all results at once
+	Collect(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (*CollectResponse, error)
+	// StreamCollect fetches weather for each location as it arrives and streams results back
+	// as soon as they complete (not necessarily in request order)
+	StreamCollect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Location, WeatherResult], error)
+}
+
+type weatherCollectorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWeatherCollectorClient(cc grpc.ClientConnInterface) WeatherCollectorClient {
+	return &weatherCollectorClient{cc}
+}
+
+func (c *weatherCollectorClient) Collect(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (*CollectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectResponse)
+	err := c.cc.Invoke(ctx, WeatherCollector_Collect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherCollectorClient) StreamCollect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Location, WeatherResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WeatherCollector_ServiceDesc.Streams[0], WeatherCollector_StreamCollect_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Location, WeatherResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherCollector_StreamCollectClient = grpc.BidiStreamingClient[Location, WeatherResult]
+
+// WeatherCollectorServer is the server API for WeatherCollector service.
+// All implementations must embed UnimplementedWeatherCollectorServer
+// for forward compatibility.
+//
+// WeatherCollector exposes collection to the Python orchestrator
+type WeatherCollectorServer interface {
+	// Collect fetches weather for a batch of locations and returns all results at once
+	Collect(context.Context, *CollectRequest) (*CollectResponse, error)
+	// StreamCollect fetches weather for each location as it arrives and streams results back
+	// as soon as they complete (not necessarily in request order)
+	StreamCollect(grpc.BidiStreamingServer[Location, WeatherResult]) error
+	mustEmbedUnimplementedWeatherCollectorServer()
+}
+
+// UnimplementedWeatherCollectorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWeatherCollectorServer struct{}
+
+func (UnimplementedWeatherCollectorServer) Collect(context.Context, *CollectRequest) (*CollectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Collect not implemented")
+}
+func (UnimplementedWeatherCollectorServer) StreamCollect(grpc.BidiStreamingServer[Location, WeatherResult]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCollect not implemented")
+}
+func (UnimplementedWeatherCollectorServer) mustEmbedUnimplementedWeatherCollectorServer() {}
+func (UnimplementedWeatherCollectorServer) testEmbeddedByValue()                          {}
+
+// UnsafeWeatherCollectorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WeatherCollectorServer will
+// result in compilation errors.
+type UnsafeWeatherCollectorServer interface {
+	mustEmbedUnimplementedWeatherCollectorServer()
+}
+
+func RegisterWeatherCollectorServer(s grpc.ServiceRegistrar, srv WeatherCollectorServer) {
+	// If the following call pancis, it indicates UnimplementedWeatherCollectorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WeatherCollector_ServiceDesc, srv)
+}
+
+func _WeatherCollector_Collect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherCollectorServer).Collect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherCollector_Collect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherCollectorServer).Collect(ctx, req.(*CollectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherCollector_StreamCollect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WeatherCollectorServer).StreamCollect(&grpc.GenericServerStream[Location, WeatherResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherCollector_StreamCollectServer = grpc.BidiStreamingServer[Location, WeatherResult]
+
+// WeatherCollector_ServiceDesc is the grpc.ServiceDesc for WeatherCollector service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WeatherCollector_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "weather.v1.WeatherCollector",
+	HandlerType: (*WeatherCollectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Collect",
+			Handler:    _WeatherCollector_Collect_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCollect",
+			Handler:       _WeatherCollector_StreamCollect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "weather.proto",
+}
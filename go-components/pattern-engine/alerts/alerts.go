@@ -0,0 +1,246 @@
+// Package alerts tracks the alerts of the analyses from run to run, so that an alert is reported
+// once when it is raised and once when it clears rather than on every run. The active alerts are
+// kept in a state file between runs, and an alert that clears is not raised again until its
+// cooldown has passed since it was last raised, so a condition hovering at a threshold does not
+// raise it on every other run. A Dispatcher sends these transitions to webhooks, Slack and email.
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/fileio"
+)
+
+// Alert states of a Transition
+const (
+	StateRaised  = "raised"
+	StateCleared = "cleared"
+)
+
+// Config is the "alerts" section of the shared config file
+type Config struct {
+	Cooldown  time.Duration            `json:"cooldown"`  // Time from raising an alert until it can be raised again once cleared
+	Cooldowns map[string]time.Duration `json:"cooldowns"` // Cooldowns of particular alerts, by rule or alert name such as "storm_risk"
+	Channels  []ChannelConfig          `json:"channels"`  // Channels raised and cleared alerts are sent to
+	Timeout   time.Duration            `json:"timeout"`   // Time allowed for sending a run's alerts
+}
+
+// DefaultConfig returns the alert settings used when no config file is given
+func DefaultConfig() Config {
+	return Config{Cooldown: 6 * time.Hour, Timeout: 30 * time.Second}
+}
+
+// LoadConfig reads the "alerts" section of a config file, falling back to defaults if path is
+// empty, and validates it
+func LoadConfig(path string) (Config, error) {
+	file := struct {
+		Alerts Config `json:"alerts"`
+	}{Alerts: DefaultConfig()}
+
+	if path == "" {
+		return file.Alerts, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file.Alerts, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file.Alerts, err
+	}
+	if file.Alerts.Cooldown < 0 {
+		return file.Alerts, fmt.Errorf("alerts.cooldown cannot be negative (value: %v)", file.Alerts.Cooldown)
+	}
+	for name, cooldown := range file.Alerts.Cooldowns {
+		if cooldown < 0 {
+			return file.Alerts, fmt.Errorf("alerts.cooldowns.%s cannot be negative (value: %v)", name, cooldown)
+		}
+	}
+	return file.Alerts, nil
+}
+
+// cooldown returns the cooldown of an alert
+func (c Config) cooldown(alert string) time.Duration {
+	if cooldown, ok := c.Cooldowns[alert]; ok {
+		return cooldown
+	}
+	return c.Cooldown
+}
+
+// Transition is an alert of a location being raised or cleared
+type Transition struct {
+	Location string    `json:"location"`
+	Alert    string    `json:"alert"`              // e.g., "storm_risk", or the name of a rule
+	Severity string    `json:"severity,omitempty"` // "low", "moderate" or "high", where known
+	Message  string    `json:"message,omitempty"`  // the rule's description
+	State    string    `json:"state"`              // StateRaised or StateCleared
+	At       time.Time `json:"at"`                 // time of the run that saw the change
+	RaisedAt time.Time `json:"raised_at"`          // time the alert was raised
+}
+
+// Active is an alert of a location raised and not yet cleared
+type Active struct {
+	Location string    `json:"location"`
+	Alert    string    `json:"alert"`
+	Severity string    `json:"severity,omitempty"`
+	RaisedAt time.Time `json:"raised_at"`
+	LastSeen time.Time `json:"last_seen"` // time of the last run the alert was still present in
+}
+
+// state is the content of the state file
+type state struct {
+	Active     map[string]Active    `json:"active"`      // Active alerts, by key
+	LastRaised map[string]time.Time `json:"last_raised"` // Time each alert was last raised, by key, for cooldowns
+}
+
+// Tracker tracks the alerts of each location between runs
+type Tracker struct {
+	Config
+	path  string
+	state state
+}
+
+// NewTracker returns a tracker with an empty state kept in memory only, for long-running
+// processes that report the alerts of the analyses they see rather than of runs
+func NewTracker(cfg Config) *Tracker {
+	return &Tracker{Config: cfg, state: state{Active: make(map[string]Active), LastRaised: make(map[string]time.Time)}}
+}
+
+// Open returns a tracker with the state in the file at path; a missing file is an empty state
+func Open(path string, cfg Config) (*Tracker, error) {
+	t := &Tracker{Config: cfg, path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &t.state); err != nil {
+			return nil, fmt.Errorf("invalid alert state file %s: %w", path, err)
+		}
+	}
+	if t.state.Active == nil {
+		t.state.Active = make(map[string]Active)
+	}
+	if t.state.LastRaised == nil {
+		t.state.LastRaised = make(map[string]time.Time)
+	}
+	return t, nil
+}
+
+// Path returns the path of the state file
+func (t *Tracker) Path() string {
+	return t.path
+}
+
+// Active returns the active alerts, ordered by location and alert
+func (t *Tracker) Active() []Active {
+	active := make([]Active, 0, len(t.state.Active))
+	for _, a := range t.state.Active {
+		active = append(active, a)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].Location != active[j].Location {
+			return active[i].Location < active[j].Location
+		}
+		return active[i].Alert < active[j].Alert
+	})
+	return active
+}
+
+// Update compares the alerts of the summaries of a run's analyses to the active alerts at time now.
+// It returns the alerts raised, in the order of the analyses and their alerts, then the alerts
+// cleared by locations analyzed without them, by alert name. An alert reappearing within its
+// cooldown of being last raised is neither raised nor active until the cooldown has passed. The
+// alerts of locations missing from the run, such as those that failed, are left as they are.
+func (t *Tracker) Update(analyses []models.AnalysisResult, now time.Time) []Transition {
+	var transitions []Transition
+	for _, result := range analyses {
+		present := make(map[string]bool, len(result.WeatherSummary.Alerts))
+		for _, alert := range result.WeatherSummary.Alerts {
+			key := stateKey(result.Location, alert)
+			present[key] = true
+			severity, message := describe(&result, alert)
+
+			if active, ok := t.state.Active[key]; ok {
+				active.LastSeen, active.Severity = now, severity
+				t.state.Active[key] = active
+				continue
+			}
+			if last, ok := t.state.LastRaised[key]; ok && now.Sub(last) < t.cooldown(alert) {
+				continue
+			}
+			t.state.Active[key] = Active{Location: result.Location, Alert: alert, Severity: severity, RaisedAt: now, LastSeen: now}
+			t.state.LastRaised[key] = now
+			transitions = append(transitions, Transition{
+				Location: result.Location,
+				Alert:    alert,
+				Severity: severity,
+				Message:  message,
+				State:    StateRaised,
+				At:       now,
+				RaisedAt: now,
+			})
+		}
+
+		var cleared []Transition
+		for key, active := range t.state.Active {
+			if active.Location != result.Location || present[key] {
+				continue
+			}
+			delete(t.state.Active, key)
+			cleared = append(cleared, Transition{
+				Location: active.Location,
+				Alert:    active.Alert,
+				Severity: active.Severity,
+				State:    StateCleared,
+				At:       now,
+				RaisedAt: active.RaisedAt,
+			})
+		}
+		sort.Slice(cleared, func(i, j int) bool { return cleared[i].Alert < cleared[j].Alert })
+		transitions = append(transitions, cleared...)
+	}
+	return transitions
+}
+
+// Save writes the state to the tracker's file
+func (t *Tracker) Save() error {
+	data, err := json.MarshalIndent(t.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return err
+	}
+	return fileio.WriteFileAtomic(t.path, data, 0644)
+}
+
+// stateKey is the key of a location's alert in the state
+func stateKey(location, alert string) string {
+	return location + "|" + alert
+}
+
+// describe returns the severity and message of an alert of an analysis: those of the rule that
+// raised it, or the level of the storm or road icing risk
+func describe(result *models.AnalysisResult, alert string) (severity, message string) {
+	for _, ruleAlert := range result.RuleAlerts {
+		if ruleAlert.Rule == alert {
+			return ruleAlert.Severity, ruleAlert.Message
+		}
+	}
+	switch {
+	case alert == "storm_risk" && result.StormRisk != nil:
+		return result.StormRisk.Level, ""
+	case alert == "road_icing" && result.RoadIcing != nil:
+		return result.RoadIcing.Level, ""
+	}
+	return "", ""
+}
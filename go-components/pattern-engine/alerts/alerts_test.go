@@ -0,0 +1,108 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// analysis returns an analysis of a location whose summary has alerts
+func analysis(location string, alerts ...string) models.AnalysisResult {
+	return models.AnalysisResult{Location: location, WeatherSummary: models.WeatherSummary{Alerts: alerts}}
+}
+
+// transitionStates returns "state alert" of each transition
+func transitionStates(transitions []Transition) []string {
+	var states []string
+	for _, t := range transitions {
+		states = append(states, t.State+" "+t.Alert)
+	}
+	return states
+}
+
+// TestUpdate tests that alerts are reported when raised and cleared, kept across a saved state,
+// and held back within their cooldown
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "alert_state.json")
+	cfg := Config{Cooldown: 6 * time.Hour, Cooldowns: map[string]time.Duration{"frost": 0}}
+	now := time.Date(2025, 12, 1, 6, 0, 0, 0, time.UTC)
+
+	runs := []struct {
+		analyses []models.AnalysisResult
+		want     []string
+	}{
+		{[]models.AnalysisResult{analysis("Oslo", "storm_risk", "frost"), analysis("Bergen", "frost")}, []string{"raised storm_risk", "raised frost", "raised frost"}},
+		{[]models.AnalysisResult{analysis("Oslo", "storm_risk", "frost"), analysis("Bergen", "frost")}, nil},
+		{[]models.AnalysisResult{analysis("Oslo")}, []string{"cleared frost", "cleared storm_risk"}},        // Bergen failed to analyze
+		{[]models.AnalysisResult{analysis("Oslo", "storm_risk", "frost")}, []string{"raised frost"}},        // storm_risk within its cooldown
+		{[]models.AnalysisResult{analysis("Oslo", "frost"), analysis("Bergen")}, []string{"cleared frost"}}, // storm_risk still cooling down
+	}
+	for i, run := range runs {
+		tracker, err := Open(path, cfg)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		got := transitionStates(tracker.Update(run.analyses, now.Add(time.Duration(i)*time.Hour)))
+		if !reflect.DeepEqual(got, run.want) {
+			t.Errorf("Run %d: expected %v, got %v", i+1, run.want, got)
+		}
+		if err := tracker.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	tracker, err := Open(path, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	active := tracker.Active()
+	if len(active) != 1 || active[0].Location != "Oslo" || active[0].Alert != "frost" || !active[0].RaisedAt.Equal(now.Add(3*time.Hour)) {
+		t.Errorf("Expected Oslo's frost raised in the fourth run to be active, got %+v", active)
+	}
+	if got := transitionStates(tracker.Update([]models.AnalysisResult{analysis("Oslo", "frost", "storm_risk")}, now.Add(12*time.Hour))); !reflect.DeepEqual(got, []string{"raised storm_risk"}) {
+		t.Errorf("Expected storm_risk raised again after its cooldown, got %v", got)
+	}
+}
+
+// TestUpdateSeverity tests that rule alerts carry the severity and message of their rule
+func TestUpdateSeverity(t *testing.T) {
+	tracker, err := Open(filepath.Join(t.TempDir(), "alert_state.json"), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := analysis("Tromsø", "black_ice", "storm_risk")
+	result.RuleAlerts = []models.RuleAlert{{Rule: "black_ice", Severity: "high", Message: "Roads may be icy"}}
+	result.StormRisk = &models.StormRisk{Level: "moderate"}
+
+	transitions := tracker.Update([]models.AnalysisResult{result}, time.Now())
+	if len(transitions) != 2 || transitions[0].Severity != "high" || transitions[0].Message != "Roads may be icy" ||
+		transitions[1].Severity != "moderate" {
+		t.Errorf("Expected the rule's and storm risk's severities, got %+v", transitions)
+	}
+}
+
+// TestLoadConfig tests per-alert cooldowns and that negative cooldowns are rejected
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"alerts": {"cooldowns": {"frost": 43200000000000}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.cooldown("frost") != 12*time.Hour || cfg.cooldown("storm_risk") != 6*time.Hour {
+		t.Errorf("Unexpected cooldowns: %+v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"alerts": {"cooldown": -1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected an error for a negative cooldown")
+	}
+}
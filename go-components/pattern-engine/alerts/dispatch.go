@@ -0,0 +1,303 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Channel types accepted in alerts.channels[].type
+const (
+	ChannelWebhook = "webhook"
+	ChannelSlack   = "slack"
+	ChannelEmail   = "email"
+)
+
+// Default templates of the message body and email subject, executed with a Transition
+const (
+	DefaultTemplate        = "{{.Location}}: {{.Alert}} {{.State}}{{with .Severity}} ({{.}}){{end}}{{with .Message}}: {{.}}{{end}}"
+	DefaultSubjectTemplate = "Weather alert {{.State}}: {{.Alert}} at {{.Location}}"
+)
+
+// ChannelConfig is a channel of the "channels" key of the "alerts" config section, which alert
+// transitions are sent to
+type ChannelConfig struct {
+	Type     string            `json:"type"`     // ChannelWebhook, ChannelSlack or ChannelEmail
+	URL      string            `json:"url"`      // Webhook or Slack incoming webhook URL
+	Headers  map[string]string `json:"headers"`  // Extra headers of webhook requests, e.g. for authentication
+	Template string            `json:"template"` // text/template of the message body ("" = DefaultTemplate)
+	States   []string          `json:"states"`   // Transition states sent, StateRaised and/or StateCleared (empty = both)
+
+	SMTPAddr string   `json:"smtp_addr"` // Email: SMTP server "host:port"
+	Username string   `json:"username"`  // Email: SMTP user name ("" = no authentication)
+	Password string   `json:"password"`  // Email: SMTP password
+	From     string   `json:"from"`      // Email: sender address
+	To       []string `json:"to"`        // Email: recipient addresses
+	Subject  string   `json:"subject"`   // Email: text/template of the subject ("" = DefaultSubjectTemplate)
+}
+
+// sendMail sends an email; tests replace it to capture messages
+var sendMail = sendMailContext
+
+// channel is a configured channel with its templates parsed
+type channel struct {
+	ChannelConfig
+	body    *template.Template
+	subject *template.Template
+}
+
+// Dispatcher sends the transitions of a run to the configured channels
+type Dispatcher struct {
+	Timeout  time.Duration // Time allowed for sending a whole run (0 = no limit)
+	channels []channel
+	client   *http.Client
+}
+
+// NewDispatcher creates a dispatcher for the channels of the alerts configuration, or returns nil
+// when no channel is configured
+func NewDispatcher(cfg Config) (*Dispatcher, error) {
+	if len(cfg.Channels) == 0 {
+		return nil, nil
+	}
+	d := &Dispatcher{Timeout: cfg.Timeout, client: &http.Client{}}
+	for i, c := range cfg.Channels {
+		ch, err := newChannel(c)
+		if err != nil {
+			return nil, fmt.Errorf("alerts.channels[%d]: %w", i, err)
+		}
+		d.channels = append(d.channels, ch)
+	}
+	return d, nil
+}
+
+// newChannel validates a channel's configuration and parses its templates
+func newChannel(c ChannelConfig) (channel, error) {
+	switch c.Type {
+	case ChannelWebhook, ChannelSlack:
+		if c.URL == "" {
+			return channel{}, fmt.Errorf("%s channel needs a url", c.Type)
+		}
+	case ChannelEmail:
+		if c.SMTPAddr == "" || c.From == "" || len(c.To) == 0 {
+			return channel{}, errors.New("email channel needs an smtp_addr, a from address and to addresses")
+		}
+	default:
+		return channel{}, fmt.Errorf("unknown channel type %q", c.Type)
+	}
+	for _, state := range c.States {
+		if state != StateRaised && state != StateCleared {
+			return channel{}, fmt.Errorf("unknown state %q (must be %s or %s)", state, StateRaised, StateCleared)
+		}
+	}
+
+	ch := channel{ChannelConfig: c}
+	var err error
+	if ch.body, err = parseTemplate("template", c.Template, DefaultTemplate); err != nil {
+		return channel{}, err
+	}
+	if ch.subject, err = parseTemplate("subject", c.Subject, DefaultSubjectTemplate); err != nil {
+		return channel{}, err
+	}
+	return ch, nil
+}
+
+// parseTemplate parses text, or fallback when text is empty
+func parseTemplate(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// Dispatch sends each transition to every channel accepting its state. A failing channel does
+// not keep the transitions from the others; the failures are returned together. A nil
+// Dispatcher does nothing.
+func (d *Dispatcher) Dispatch(ctx context.Context, transitions []Transition) error {
+	if d == nil || len(transitions) == 0 {
+		return nil
+	}
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	var errs []error
+	for i := range d.channels {
+		ch := &d.channels[i]
+		for _, transition := range transitions {
+			if !ch.accepts(transition.State) {
+				continue
+			}
+			if err := d.send(ctx, ch, transition); err != nil {
+				errs = append(errs, fmt.Errorf("%s channel: %s %s at %s: %w", ch.Type, transition.Alert, transition.State, transition.Location, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// accepts reports whether the channel sends transitions to state
+func (c *channel) accepts(state string) bool {
+	if len(c.States) == 0 {
+		return true
+	}
+	for _, s := range c.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// send sends one transition to a channel
+func (d *Dispatcher) send(ctx context.Context, ch *channel, transition Transition) error {
+	text, err := render(ch.body, transition)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Type {
+	case ChannelWebhook:
+		// The transition's fields with the rendered message as "text"
+		payload := struct {
+			Transition
+			Text string `json:"text"`
+		}{transition, text}
+		return d.post(ctx, ch, payload)
+	case ChannelSlack:
+		return d.post(ctx, ch, map[string]string{"text": text})
+	case ChannelEmail:
+		subject, err := render(ch.subject, transition)
+		if err != nil {
+			return err
+		}
+		var auth smtp.Auth
+		if ch.Username != "" {
+			host, _, _ := strings.Cut(ch.SMTPAddr, ":")
+			auth = smtp.PlainAuth("", ch.Username, ch.Password, host)
+		}
+		return sendMail(ctx, ch.SMTPAddr, auth, ch.From, ch.To, emailMessage(ch.From, ch.To, subject, text))
+	}
+	return fmt.Errorf("unknown channel type %q", ch.Type)
+}
+
+// render executes a template with a transition
+func render(t *template.Template, transition Transition) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, transition); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", t.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// post sends payload as JSON to the channel's URL
+func (d *Dispatcher) post(ctx context.Context, ch *channel, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ch.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pattern-engine/2.0")
+	for name, value := range ch.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// sendMailContext is smtp.SendMail bounded by ctx: the connection is dialed with ctx, given its
+// deadline and closed when ctx is done, so a stalled server cannot hold up the run
+func sendMailContext(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := strings.Cut(addr, ":")
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// emailMessage formats a plain text email; header values have their line breaks removed
+func emailMessage(from string, to []string, subject, body string) []byte {
+	header := strings.NewReplacer("\r", "", "\n", " ")
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(strings.Join(to, ", ")))
+	fmt.Fprintf(&b, "Subject: %s\r\n", header.Replace(subject))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
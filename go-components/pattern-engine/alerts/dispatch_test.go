@@ -0,0 +1,168 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDispatch tests that transitions are sent to webhook, Slack and email channels with their
+// templates and state filters
+func TestDispatch(t *testing.T) {
+	var webhook []map[string]any
+	var slack []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hook":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				t.Errorf("Expected the configured header, got %q", r.Header.Get("Authorization"))
+			}
+			var payload map[string]any
+			json.NewDecoder(r.Body).Decode(&payload)
+			webhook = append(webhook, payload)
+		case "/slack":
+			var payload map[string]string
+			json.NewDecoder(r.Body).Decode(&payload)
+			slack = append(slack, payload)
+		}
+	}))
+	defer server.Close()
+
+	var mails []string
+	sendMail = func(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "mail.example.com:587" || auth == nil || from != "alerts@example.com" || len(to) != 2 {
+			t.Errorf("Unexpected email envelope: %s %v %s %v", addr, auth, from, to)
+		}
+		mails = append(mails, string(msg))
+		return nil
+	}
+	defer func() { sendMail = sendMailContext }()
+
+	d, err := NewDispatcher(Config{Channels: []ChannelConfig{
+		{Type: ChannelWebhook, URL: server.URL + "/hook", Headers: map[string]string{"Authorization": "Bearer secret"}},
+		{Type: ChannelSlack, URL: server.URL + "/slack", Template: ":warning: *{{.Alert}}* {{.State}} at {{.Location}}", States: []string{StateRaised}},
+		{Type: ChannelEmail, SMTPAddr: "mail.example.com:587", Username: "engine", Password: "pw", From: "alerts@example.com",
+			To: []string{"a@example.com", "b@example.com"}, Subject: "[{{.Severity}}] {{.Alert}}"},
+	}})
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+
+	at := time.Date(2025, 12, 1, 6, 0, 0, 0, time.UTC)
+	transitions := []Transition{
+		{Location: "Oslo", Alert: "black_ice", Severity: "high", Message: "Roads may be icy", State: StateRaised, At: at, RaisedAt: at},
+		{Location: "Bergen", Alert: "storm_risk", Severity: "moderate", State: StateCleared, At: at, RaisedAt: at.Add(-3 * time.Hour)},
+	}
+	if err := d.Dispatch(context.Background(), transitions); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+
+	if len(webhook) != 2 || webhook[0]["text"] != "Oslo: black_ice raised (high): Roads may be icy" ||
+		webhook[1]["state"] != StateCleared || webhook[1]["text"] != "Bergen: storm_risk cleared (moderate)" {
+		t.Errorf("Unexpected webhook payloads: %v", webhook)
+	}
+	if len(slack) != 1 || slack[0]["text"] != ":warning: *black_ice* raised at Oslo" {
+		t.Errorf("Expected only the raised alert on Slack, got %v", slack)
+	}
+	if len(mails) != 2 || !strings.Contains(mails[0], "Subject: [high] black_ice\r\n") ||
+		!strings.Contains(mails[0], "To: a@example.com, b@example.com\r\n") || !strings.HasSuffix(mails[0], "Roads may be icy\r\n") {
+		t.Errorf("Unexpected emails: %q", mails)
+	}
+}
+
+// TestDispatchFailure tests that a failing channel is reported without keeping the transitions
+// from the other channels
+func TestDispatchFailure(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		received++
+	}))
+	defer server.Close()
+
+	d, err := NewDispatcher(Config{Channels: []ChannelConfig{
+		{Type: ChannelWebhook, URL: server.URL + "/down"},
+		{Type: ChannelSlack, URL: server.URL + "/slack"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.Dispatch(context.Background(), []Transition{{Location: "Oslo", Alert: "frost", State: StateRaised}})
+	if err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("Expected the webhook's status in the error, got %v", err)
+	}
+	if received != 1 {
+		t.Errorf("Expected the Slack channel to receive the alert, got %d requests", received)
+	}
+}
+
+// TestDispatchEmailTimeout tests that an SMTP server that never answers does not hold up a run
+// past the dispatcher's timeout
+func TestDispatchEmailTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept() // Accepted, but no greeting is ever sent
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	d, err := NewDispatcher(Config{
+		Timeout:  200 * time.Millisecond,
+		Channels: []ChannelConfig{{Type: ChannelEmail, SMTPAddr: listener.Addr().String(), From: "alerts@example.com", To: []string{"a@example.com"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+
+	started := time.Now()
+	err = d.Dispatch(context.Background(), []Transition{{Location: "Oslo", Alert: "storm_risk", State: StateRaised}})
+	if err == nil {
+		t.Error("Expected the stalled send to fail")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("Expected Dispatch to return once the timeout expired, took %v", elapsed)
+	}
+}
+
+// TestNewDispatcher tests that no dispatcher is created without channels and that invalid
+// channels are rejected
+func TestNewDispatcher(t *testing.T) {
+	if d, err := NewDispatcher(DefaultConfig()); d != nil || err != nil {
+		t.Errorf("Expected no dispatcher without channels, got %v (err: %v)", d, err)
+	}
+
+	tests := []struct {
+		name    string
+		channel ChannelConfig
+	}{
+		{"Unknown type", ChannelConfig{Type: "pager", URL: "http://localhost"}},
+		{"Webhook without URL", ChannelConfig{Type: ChannelWebhook}},
+		{"Email without recipients", ChannelConfig{Type: ChannelEmail, SMTPAddr: "localhost:25", From: "a@example.com"}},
+		{"Unknown state", ChannelConfig{Type: ChannelSlack, URL: "http://localhost", States: []string{"expired"}}},
+		{"Invalid template", ChannelConfig{Type: ChannelSlack, URL: "http://localhost", Template: "{{.Alert"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDispatcher(Config{Channels: []ChannelConfig{tt.channel}}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// midsummerDay is the day of the year (July 1) splitting the spring frosts of the northern
+// hemisphere from its autumn frosts; the southern hemisphere's seasons are the other way round
+const midsummerDay = 182
+
+// NewAgroCalculator creates a new agricultural calculator with default settings
+func NewAgroCalculator() *AgroCalculator {
+	return &AgroCalculator{DefaultThresholds().Agro}
+}
+
+// AgroIndices computes the agricultural indices of the readings:
+//   - growing degree days over the local days the readings span, each day adding the amount its
+//     mean of the lowest and highest temperature (the highest capped at UpperTemperature) is
+//     above BaseTemperature;
+//   - chill hours, the hours from ChillMin to ChillMax, each reading counting until the next but
+//     an hour at most;
+//   - frost days, with a low at or below FrostTemperature, and from them the last spring frost
+//     and the first autumn frost after it, by the location's hemisphere.
+//
+// It returns nil without readings.
+func (ac *AgroCalculator) AgroIndices(locationData *models.LocationData) *models.AgroIndices {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	indices := &models.AgroIndices{BaseTemperature: ac.BaseTemperature}
+	for i, r := range readings {
+		if r.Temperature < ac.ChillMin || r.Temperature > ac.ChillMax {
+			continue
+		}
+		hours := 1.0
+		if i+1 < len(readings) {
+			hours = math.Min(readings[i+1].Timestamp.Sub(r.Timestamp).Hours(), 1)
+		}
+		indices.ChillHours += hours
+	}
+
+	southern := locationData.Coordinates.Latitude < 0
+	for _, day := range AggregateDaily(readings, LocalZone(locationData)) {
+		high := math.Min(day.Max, ac.UpperTemperature)
+		indices.GrowingDegreeDays += math.Max((day.Min+high)/2-ac.BaseTemperature, 0)
+		indices.Days++
+
+		if day.Min > ac.FrostTemperature {
+			continue
+		}
+		indices.FrostDays++
+		if spring := (day.Date.YearDay() < midsummerDay) != southern; spring {
+			indices.LastSpringFrost = day.Date
+			indices.FirstAutumnFrost = time.Time{} // Autumn frosts before it were a season ago
+		} else if indices.FirstAutumnFrost.IsZero() {
+			indices.FirstAutumnFrost = day.Date
+		}
+	}
+	if !indices.LastSpringFrost.IsZero() && !indices.FirstAutumnFrost.IsZero() {
+		indices.GrowingSeasonDays = int(math.Round(indices.FirstAutumnFrost.Sub(indices.LastSpringFrost).Hours()/24)) - 1 // Days of DST changes are not 24 hours
+	}
+	return indices
+}
@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestAgroIndicesGrowth tests growing degree days, capped on a hot day, and chill hours
+func TestAgroIndicesGrowth(t *testing.T) {
+	start := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 3 * 24 {
+		temperature := 20.0
+		if i%24 < 6 {
+			temperature = 5 // Chill hours before dawn
+		} else if i == 2*24+14 {
+			temperature = 40 // Counted as 30
+		}
+		readings = append(readings, models.WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Temperature: temperature})
+	}
+
+	indices := NewAgroCalculator().AgroIndices(&models.LocationData{Readings: readings})
+	if indices.Days != 3 || math.Abs(indices.GrowingDegreeDays-12.5) > 1e-9 || indices.ChillHours != 18 {
+		t.Errorf("Expected 12.5 growing degree days over 3 days and 18 chill hours, got %+v", indices)
+	}
+	if indices.FrostDays != 0 || !indices.LastSpringFrost.IsZero() || !indices.FirstAutumnFrost.IsZero() {
+		t.Errorf("Expected no frost, got %+v", indices)
+	}
+}
+
+// TestAgroIndicesFrost tests the last spring and first autumn frosts, which swap in the southern
+// hemisphere
+func TestAgroIndicesFrost(t *testing.T) {
+	frosts := map[string]bool{"04-10": true, "05-05": true, "09-20": true, "10-03": true}
+	var readings []models.WeatherPoint
+	for day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC); day.Month() < 11; day = day.AddDate(0, 0, 1) {
+		low := 5.0
+		if frosts[day.Format("01-02")] {
+			low = -1
+		}
+		for hour := 0; hour < 24; hour += 6 {
+			readings = append(readings, models.WeatherPoint{Timestamp: day.Add(time.Duration(hour) * time.Hour), Temperature: low + float64(hour)})
+		}
+	}
+
+	north := NewAgroCalculator().AgroIndices(&models.LocationData{Readings: readings, Coordinates: models.Coordinates{Latitude: 60}})
+	if north.FrostDays != 4 || !north.LastSpringFrost.Equal(time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)) ||
+		!north.FirstAutumnFrost.Equal(time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)) || north.GrowingSeasonDays != 137 {
+		t.Errorf("Expected frosts until May 5 and from September 20, 137 days apart, got %+v", north)
+	}
+
+	south := NewAgroCalculator().AgroIndices(&models.LocationData{Readings: readings, Coordinates: models.Coordinates{Latitude: -40}})
+	if !south.LastSpringFrost.Equal(time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)) || !south.FirstAutumnFrost.IsZero() ||
+		south.GrowingSeasonDays != 0 {
+		t.Errorf("Expected the last spring frost on October 3 in the south, got %+v", south)
+	}
+}
@@ -9,17 +9,27 @@ import (
 	"pattern-engine/utils"
 )
 
+// Anomaly scoring methods
+const (
+	AnomalyMethodStdDev = "std_dev" // Mean ± threshold_factor standard deviations
+	AnomalyMethodMAD    = "mad"     // Median ± threshold_factor scaled median absolute deviations
+	AnomalyMethodIQR    = "iqr"     // Tukey fences, iqr_factor interquartile ranges beyond the quartiles
+)
+
+// madScale scales the median absolute deviation to estimate the standard deviation of normally
+// distributed values, so the severity factors mean the same for both methods
+const madScale = 1.4826
+
 // AnomalyDetector detects unusual weather patterns and anomalies
 
 // NewAnomalyDetector creates a new anomaly detector with default settings
 func NewAnomalyDetector() *AnomalyDetector {
-	return &AnomalyDetector{
-		AnomalyThresholdFactor: 2.0, // 2 standard deviations from mean
-		MinReadingsForBaseline: 5,   // minimum readings for baseline calculation
-	}
+	return &AnomalyDetector{DefaultThresholds().Anomalies}
 }
 
-// DetectAnomalies identifies anomalous weather readings by comparing to statistical baselines
+// DetectAnomalies identifies anomalous weather readings by comparing to statistical baselines:
+// those of the whole series, or with BaselineWindow those of the readings within that period
+// before each reading, so that a series spanning seasons is judged against recent weather
 func (ad *AnomalyDetector) DetectAnomalies(locationData *models.LocationData) []models.Anomaly {
 	if len(locationData.Readings) < ad.MinReadingsForBaseline {
 		return []models.Anomaly{} // Not enough data for anomaly detection
@@ -33,30 +43,31 @@ func (ad *AnomalyDetector) DetectAnomalies(locationData *models.LocationData) []
 	var anomalies []models.Anomaly
 
 	// Calculate statistical baselines for different variables
-	temperatureStats := ad.calculateVariableStats(utils.GetTemperatureValues(locationData.Readings))
-	pressureStats := ad.calculateVariableStats(utils.GetPressureValues(locationData.Readings))
-	humidityStats := ad.calculateVariableStats(utils.GetHumidityValues(locationData.Readings))
-	windSpeedStats := ad.calculateVariableStats(utils.GetWindSpeedValues(locationData.Readings))
+	readings := locationData.Readings
+	temperatureStats := ad.baselines(readings, utils.GetTemperatureValues(readings))
+	pressureStats := ad.baselines(readings, utils.GetPressureValues(readings))
+	humidityStats := ad.baselines(readings, utils.GetHumidityValues(readings))
+	windSpeedStats := ad.baselines(readings, utils.GetWindSpeedValues(readings))
 
 	// Check each reading for anomalies
-	for _, reading := range locationData.Readings {
+	for i, reading := range locationData.Readings {
 		// Check for temperature anomalies
-		if tempAnomaly := ad.checkVariableAnomaly("temperature", reading.Temperature, temperatureStats, reading.Timestamp); tempAnomaly != nil {
+		if tempAnomaly := ad.checkVariableAnomaly("temperature", reading.Temperature, temperatureStats[i], reading.Timestamp); tempAnomaly != nil {
 			anomalies = append(anomalies, *tempAnomaly)
 		}
 
 		// Check for pressure anomalies
-		if pressureAnomaly := ad.checkVariableAnomaly("pressure", reading.Pressure, pressureStats, reading.Timestamp); pressureAnomaly != nil {
+		if pressureAnomaly := ad.checkVariableAnomaly("pressure", reading.Pressure, pressureStats[i], reading.Timestamp); pressureAnomaly != nil {
 			anomalies = append(anomalies, *pressureAnomaly)
 		}
 
 		// Check for humidity anomalies
-		if humidityAnomaly := ad.checkVariableAnomaly("humidity", reading.Humidity, humidityStats, reading.Timestamp); humidityAnomaly != nil {
+		if humidityAnomaly := ad.checkVariableAnomaly("humidity", reading.Humidity, humidityStats[i], reading.Timestamp); humidityAnomaly != nil {
 			anomalies = append(anomalies, *humidityAnomaly)
 		}
 
 		// Check for wind speed anomalies
-		if windAnomaly := ad.checkVariableAnomaly("wind_speed", reading.WindSpeed, windSpeedStats, reading.Timestamp); windAnomaly != nil {
+		if windAnomaly := ad.checkVariableAnomaly("wind_speed", reading.WindSpeed, windSpeedStats[i], reading.Timestamp); windAnomaly != nil {
 			anomalies = append(anomalies, *windAnomaly)
 		}
 
@@ -69,6 +80,29 @@ func (ad *AnomalyDetector) DetectAnomalies(locationData *models.LocationData) []
 	return anomalies
 }
 
+// baselines returns the baseline each of values, a variable of readings in chronological order,
+// is compared to. Without BaselineWindow every value has the statistics of the whole series;
+// with it, those of the values within BaselineWindow before it, excluding itself.
+func (ad *AnomalyDetector) baselines(readings []models.WeatherPoint, values []float64) []VariableStats {
+	stats := make([]VariableStats, len(values))
+	if ad.BaselineWindow <= 0 {
+		whole := ad.calculateVariableStats(values)
+		for i := range stats {
+			stats[i] = whole
+		}
+		return stats
+	}
+
+	start := 0
+	for i := range values {
+		for readings[i].Timestamp.Sub(readings[start].Timestamp) > ad.BaselineWindow {
+			start++
+		}
+		stats[i] = ad.calculateVariableStats(values[start:i])
+	}
+	return stats
+}
+
 // calculateVariableStats calculates statistical measures for a variable
 func (ad *AnomalyDetector) calculateVariableStats(values []float64) VariableStats {
 	if len(values) == 0 {
@@ -90,49 +124,70 @@ func (ad *AnomalyDetector) calculateVariableStats(values []float64) VariableStat
 	}
 	stdDev := math.Sqrt(sumSquares / float64(len(values)))
 
-	// Calculate min and max
-	min := values[0]
-	max := values[0]
-	for _, v := range values {
-		if v < min {
-			min = v
-		}
-		if v > max {
-			max = v
-		}
+	// Calculate the median, quartiles and median absolute deviation
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	median := percentile(sorted, 0.5)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
 	}
+	sort.Float64s(deviations)
 
 	return VariableStats{
 		Mean:       mean,
 		StdDev:     stdDev,
-		Min:        min,
-		Max:        max,
+		Min:        sorted[0],
+		Max:        sorted[len(sorted)-1],
 		SampleSize: len(values),
+		Median:     median,
+		MAD:        percentile(deviations, 0.5),
+		Q1:         percentile(sorted, 0.25),
+		Q3:         percentile(sorted, 0.75),
 	}
 }
 
+// methodFor returns the scoring method of a variable: its entry in Methods, or Method
+func (ad *AnomalyDetector) methodFor(variableName string) string {
+	if method, ok := ad.Methods[variableName]; ok {
+		return method
+	}
+	return ad.Method
+}
+
 // checkVariableAnomaly checks if a single reading value is anomalous
 func (ad *AnomalyDetector) checkVariableAnomaly(variableName string, value float64, stats VariableStats, timestamp time.Time) *models.Anomaly {
 	if stats.SampleSize < ad.MinReadingsForBaseline {
 		return nil
 	}
 
+	if ad.methodFor(variableName) == AnomalyMethodIQR {
+		return ad.checkFences(variableName, value, stats, timestamp)
+	}
+
+	// Mean and standard deviation, or their robust counterparts that a single outlier barely moves
+	center, spread := stats.Mean, stats.StdDev
+	if ad.methodFor(variableName) == AnomalyMethodMAD {
+		center, spread = stats.Median, madScale*stats.MAD
+	}
+
 	// Calculate how many standard deviations away from the mean the value is
-	deviation := math.Abs(value - stats.Mean)
-	if deviation <= ad.AnomalyThresholdFactor*stats.StdDev {
+	deviation := math.Abs(value - center)
+	if deviation <= ad.AnomalyThresholdFactor*spread {
 		return nil // Not an anomaly
 	}
 
 	severity := "low"
-	if deviation > (3.0 * stats.StdDev) {
+	if deviation > (ad.HighSeverityFactor * spread) {
 		severity = "high"
-	} else if deviation > (2.0 * stats.StdDev) {
+	} else if deviation > (ad.ModerateSeverityFactor * spread) {
 		severity = "moderate"
 	}
 
 	// Determine anomaly type based on value relative to mean
 	anomalyType := "unusual_high"
-	if value < stats.Mean {
+	if value < center {
 		anomalyType = "unusual_low"
 	}
 
@@ -141,11 +196,33 @@ func (ad *AnomalyDetector) checkVariableAnomaly(variableName string, value float
 		Type:      anomalyType,
 		Severity:  severity,
 		Value:     value,
-		Threshold: stats.Mean + (ad.AnomalyThresholdFactor * stats.StdDev),
+		Threshold: center + (ad.AnomalyThresholdFactor * spread),
 		Timestamp: timestamp,
 	}
 }
 
+// checkFences checks a value against Tukey fences IQRFactor interquartile ranges beyond the
+// quartiles; values beyond twice that distance are high severity
+func (ad *AnomalyDetector) checkFences(variableName string, value float64, stats VariableStats, timestamp time.Time) *models.Anomaly {
+	iqr := stats.Q3 - stats.Q1
+	anomaly := &models.Anomaly{Variable: variableName, Value: value, Timestamp: timestamp}
+	var beyond float64 // Distance past the quartile
+	switch {
+	case value > stats.Q3+ad.IQRFactor*iqr:
+		anomaly.Type, anomaly.Threshold, beyond = "unusual_high", stats.Q3+ad.IQRFactor*iqr, value-stats.Q3
+	case value < stats.Q1-ad.IQRFactor*iqr:
+		anomaly.Type, anomaly.Threshold, beyond = "unusual_low", stats.Q1-ad.IQRFactor*iqr, stats.Q1-value
+	default:
+		return nil // Not an anomaly
+	}
+
+	anomaly.Severity = "moderate"
+	if beyond > 2*ad.IQRFactor*iqr {
+		anomaly.Severity = "high"
+	}
+	return anomaly
+}
+
 // detectRapidPressureChange detects sudden pressure changes which might indicate weather fronts
 func (ad *AnomalyDetector) detectRapidPressureChange(currentReading models.WeatherPoint, allReadings []models.WeatherPoint) *models.Anomaly {
 	if len(allReadings) < 3 {
@@ -154,7 +231,7 @@ func (ad *AnomalyDetector) detectRapidPressureChange(currentReading models.Weath
 
 	// Find recent readings within a few hours for pressure change detection
 	recentReadings := []models.WeatherPoint{}
-	timeWindow := 4 * time.Hour // Look for changes within 4 hours
+	timeWindow := ad.PressureChangeWindow
 
 	for _, reading := range allReadings {
 		timeDiff := currentReading.Timestamp.Sub(reading.Timestamp)
@@ -173,13 +250,13 @@ func (ad *AnomalyDetector) detectRapidPressureChange(currentReading models.Weath
 	absChange := math.Abs(pressureChange)
 
 	// A rapid pressure change can indicate weather systems
-	if absChange > 3.0 { // 3 hPa change within 4 hours
+	if absChange > ad.PressureChange {
 		severity := "moderate"
 		anomalyType := "pressure_rise"
 		if pressureChange < 0 {
 			anomalyType = "pressure_drop"
 		}
-		if absChange > 5.0 {
+		if absChange > ad.HighPressureChange {
 			severity = "high"
 		}
 
@@ -188,7 +265,7 @@ func (ad *AnomalyDetector) detectRapidPressureChange(currentReading models.Weath
 			Type:      anomalyType,
 			Severity:  severity,
 			Value:     pressureChange,
-			Threshold: 3.0,
+			Threshold: ad.PressureChange,
 			Timestamp: currentReading.Timestamp,
 		}
 	}
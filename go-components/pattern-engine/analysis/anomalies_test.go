@@ -120,3 +120,69 @@ func TestDetectAnomaliesWithExtremeData(t *testing.T) {
 		t.Log("Note: No temperature anomaly detected, but this may be expected with certain thresholds")
 	}
 }
+
+// TestDetectAnomaliesBaselineWindow tests that a trailing baseline finds a spike hidden by a slow
+// warming of the whole series
+func TestDetectAnomaliesBaselineWindow(t *testing.T) {
+	baseTime := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 240 {
+		temp := 0.125 * float64(i) // Warming 30 °C over 10 days
+		if i == 120 {
+			temp += 6
+		}
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   baseTime.Add(time.Duration(i) * time.Hour),
+			Temperature: temp,
+			Pressure:    1013,
+		})
+	}
+	spike := baseTime.Add(120 * time.Hour)
+
+	whole := NewAnomalyDetector().DetectAnomalies(&models.LocationData{Readings: readings})
+	if len(whole) != 0 {
+		t.Errorf("Expected the whole-series baseline to miss the spike, got %+v", whole)
+	}
+
+	detector := NewAnomalyDetector()
+	detector.BaselineWindow = 48 * time.Hour
+	detector.MinReadingsForBaseline = 12 // Fewer readings of a steady warming make the latest look unusual
+	windowed := detector.DetectAnomalies(&models.LocationData{Readings: readings})
+	if len(windowed) != 1 || !windowed[0].Timestamp.Equal(spike) || windowed[0].Type != "unusual_high" {
+		t.Errorf("Expected only the spike against a 48-hour baseline, got %+v", windowed)
+	}
+}
+
+// TestDetectAnomaliesRobustMethods tests that a spike inflating the standard deviation enough to
+// hide itself is found by the median/MAD and IQR methods
+func TestDetectAnomaliesRobustMethods(t *testing.T) {
+	baseTime := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i, temp := range []float64{20, 21, 19, 20, 50} {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   baseTime.Add(time.Duration(i) * time.Hour),
+			Temperature: temp,
+			Pressure:    1013,
+		})
+	}
+
+	if anomalies := NewAnomalyDetector().DetectAnomalies(&models.LocationData{Readings: readings}); len(anomalies) != 0 {
+		t.Errorf("Expected the spike to hide itself from mean and standard deviation, got %+v", anomalies)
+	}
+
+	for _, method := range []string{AnomalyMethodMAD, AnomalyMethodIQR} {
+		detector := NewAnomalyDetector()
+		detector.Methods = map[string]string{"temperature": method}
+		anomalies := detector.DetectAnomalies(&models.LocationData{Readings: readings})
+		if len(anomalies) != 1 || anomalies[0].Value != 50 || anomalies[0].Severity != "high" || anomalies[0].Type != "unusual_high" {
+			t.Errorf("%s: expected only the spike as a high anomaly, got %+v", method, anomalies)
+		}
+	}
+
+	// Per-variable methods leave the others on the default
+	detector := NewAnomalyDetector()
+	detector.Methods = map[string]string{"pressure": AnomalyMethodMAD}
+	if anomalies := detector.DetectAnomalies(&models.LocationData{Readings: readings}); len(anomalies) != 0 {
+		t.Errorf("Expected temperature to keep the standard deviation method, got %+v", anomalies)
+	}
+}
@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"math"
+
+	"pattern-engine/models"
+)
+
+// calmWindSpeed is the wind speed (m/s) below which the air is calm (Beaufort force 0) and the
+// wind has no direction to speak of
+const calmWindSpeed = 0.3
+
+// compassPoints name the sectors of the wind rose, clockwise from north
+var compassPoints = [...]string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// CircularMean returns the mean of angles in degrees (0-360), averaging them as unit vectors so
+// that 350° and 10° average to 0° rather than 180°, and the length of the mean vector (0-1): 1
+// when every angle is the same, near 0 when they spread evenly around the circle
+func CircularMean(angles []float64) (mean, resultant float64) {
+	if len(angles) == 0 {
+		return 0, 0
+	}
+	var x, y float64
+	for _, a := range angles {
+		x += math.Cos(a * math.Pi / 180)
+		y += math.Sin(a * math.Pi / 180)
+	}
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360), math.Hypot(x, y) / float64(len(angles))
+}
+
+// meanDirection returns the circular mean wind direction of readings in degrees (0-360)
+func meanDirection(readings []models.WeatherPoint) float64 {
+	directions := make([]float64, len(readings))
+	for i, r := range readings {
+		directions[i] = r.WindDirection
+	}
+	mean, _ := CircularMean(directions)
+	return mean
+}
+
+// angleDifference returns the turn from direction a to direction b in degrees, positive clockwise
+// (-180 to 180)
+func angleDifference(a, b float64) float64 {
+	return math.Mod(b-a+540, 360) - 180
+}
+
+// unwrapDirections returns the readings with a wind that is not calm, their directions unwrapped
+// to turn by the shortest way from each reading to the next (350° then 10° becomes 350° then
+// 370°), so that veering is a steady rise rather than a drop at north
+func unwrapDirections(readings []models.WeatherPoint) []models.WeatherPoint {
+	var unwrapped []models.WeatherPoint
+	for _, r := range readings {
+		if r.WindSpeed < calmWindSpeed {
+			continue
+		}
+		if len(unwrapped) > 0 {
+			previous := unwrapped[len(unwrapped)-1].WindDirection
+			r.WindDirection = previous + angleDifference(previous, r.WindDirection)
+		}
+		unwrapped = append(unwrapped, r)
+	}
+	return unwrapped
+}
+
+// windAccumulator collects wind directions for their circular statistics and wind rose,
+// incrementally so that streamed readings need not be held
+type windAccumulator struct {
+	x, y   float64 // Sums of the unit vectors of the directions
+	n      int     // Readings with a wind that is not calm
+	calm   int     // Calm readings
+	counts [len(compassPoints)]int
+	speeds [len(compassPoints)]float64 // Sums of the wind speeds of each sector
+}
+
+// add adds a reading
+func (w *windAccumulator) add(r models.WeatherPoint) {
+	if r.WindSpeed < calmWindSpeed {
+		w.calm++
+		return
+	}
+	w.x += math.Cos(r.WindDirection * math.Pi / 180)
+	w.y += math.Sin(r.WindDirection * math.Pi / 180)
+	w.n++
+	width := 360.0 / float64(len(compassPoints))
+	sector := int(math.Mod(r.WindDirection+width/2+360, 360)/width) % len(compassPoints)
+	w.counts[sector]++
+	w.speeds[sector] += r.WindSpeed
+}
+
+// statistics returns the wind direction statistics: the circular mean, circular variance and
+// circular standard deviation of the directions, and the wind rose. It returns nil with fewer
+// than 2 readings with a wind that is not calm.
+func (w *windAccumulator) statistics(confidenceLevel float64) *models.StatisticalData {
+	if w.n < 2 {
+		return nil
+	}
+	resultant := math.Hypot(w.x, w.y) / float64(w.n)
+	stats := &models.StatisticalData{
+		Variable:         "wind_direction",
+		Mean:             math.Mod(math.Atan2(w.y, w.x)*180/math.Pi+360, 360),
+		StdDev:           math.Sqrt(max(-2*math.Log(max(resultant, 1e-12)), 0)) * 180 / math.Pi,
+		SampleSize:       w.n,
+		ConfidenceLevel:  confidenceLevel,
+		CircularVariance: 1 - resultant,
+		WindRose:         &models.WindRose{Calm: float64(w.calm) / float64(w.n+w.calm)},
+	}
+	for i, point := range compassPoints {
+		sector := models.WindRoseSector{
+			Direction: point,
+			Degrees:   float64(i) * 360 / float64(len(compassPoints)),
+			Frequency: float64(w.counts[i]) / float64(w.n+w.calm),
+		}
+		if w.counts[i] > 0 {
+			sector.MeanSpeed = w.speeds[i] / float64(w.counts[i])
+		}
+		stats.WindRose.Sectors = append(stats.WindRose.Sectors, sector)
+	}
+	return stats
+}
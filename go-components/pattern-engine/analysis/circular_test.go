@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestCircularMean tests that angles either side of north average to north
+func TestCircularMean(t *testing.T) {
+	mean, resultant := CircularMean([]float64{350, 10, 0})
+	if math.Min(mean, 360-mean) > 1e-9 || resultant < 0.98 {
+		t.Errorf("Expected a mean of 0° with a resultant near 1, got %.2f° (%.3f)", mean, resultant)
+	}
+	if _, resultant := CircularMean([]float64{0, 90, 180, 270}); resultant > 1e-9 {
+		t.Errorf("Expected no mean direction for opposite winds, got a resultant of %.3f", resultant)
+	}
+}
+
+// TestWindDirectionStatistics tests the circular statistics and wind rose of mostly northerly
+// winds, with calm readings left out of the directions
+func TestWindDirectionStatistics(t *testing.T) {
+	var readings []models.WeatherPoint
+	for i, direction := range []float64{355, 5, 10, 350, 0, 90, 0, 0} {
+		speed := 4.0
+		if i >= 6 {
+			speed = 0 // Calm
+		}
+		readings = append(readings, models.WeatherPoint{Temperature: 10, WindSpeed: speed, WindDirection: direction})
+	}
+
+	stats := NewStatisticalAnalyzer().AnalyzeStatistics(&models.LocationData{Readings: readings})
+	wind := stats[len(stats)-1]
+	if wind.Variable != "wind_direction" || wind.SampleSize != 6 || math.Min(wind.Mean, 360-wind.Mean) > 15 ||
+		wind.CircularVariance <= 0 || wind.CircularVariance > 0.2 {
+		t.Fatalf("Expected northerly winds with little variance, got %+v", wind)
+	}
+
+	rose := wind.WindRose
+	if rose == nil || len(rose.Sectors) != 16 || rose.Calm != 0.25 {
+		t.Fatalf("Expected 16 sectors and a quarter calm, got %+v", rose)
+	}
+	if n := rose.Sectors[0]; n.Direction != "N" || n.Frequency != 0.625 || n.MeanSpeed != 4 {
+		t.Errorf("Expected 5 of 8 readings from the north, got %+v", n)
+	}
+	if e := rose.Sectors[4]; e.Direction != "E" || e.Frequency != 0.125 {
+		t.Errorf("Expected 1 of 8 readings from the east, got %+v", e)
+	}
+}
+
+// TestWindDirectionTrend tests that a wind turning clockwise through north is veering, rather
+// than jumping back from 360° to 0°
+func TestWindDirectionTrend(t *testing.T) {
+	start := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 8 {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:     start.Add(time.Duration(i) * time.Hour),
+			WindSpeed:     6,
+			WindDirection: math.Mod(330+10*float64(i), 360), // 330° to 40°
+		})
+	}
+
+	trends := NewTrendAnalyzer().AnalyzeTrends(&models.LocationData{Readings: readings})
+	wind := trends[len(trends)-1]
+	if wind.Variable != "wind_direction" || wind.Trend != "veering" || math.Abs(wind.ChangeRate-10) > 1e-9 {
+		t.Errorf("Expected wind veering by 10°/h, got %+v", wind)
+	}
+}
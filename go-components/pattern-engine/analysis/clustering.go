@@ -10,9 +10,7 @@ import (
 
 // NewPatternRecognizer creates a new pattern recognizer with default settings
 func NewPatternRecognizer() *PatternRecognizer {
-	return &PatternRecognizer{
-		MinPatternConfidence: 0.6, // minimum 60% confidence
-	}
+	return &PatternRecognizer{DefaultThresholds().Patterns}
 }
 
 // RecognizePatterns identifies weather patterns in the data
@@ -53,6 +51,9 @@ func (pr *PatternRecognizer) RecognizePatterns(locationData *models.LocationData
 		patterns = append(patterns, *precipitationPattern)
 	}
 
+	// Detect front passages
+	patterns = append(patterns, pr.detectFrontPassages(locationData.Readings)...)
+
 	// Detect stable weather patterns
 	if stablePattern := pr.detectStablePattern(locationData.Readings); stablePattern != nil {
 		patterns = append(patterns, *stablePattern)
@@ -77,7 +78,7 @@ func (pr *PatternRecognizer) detectWarmingPattern(readings []models.WeatherPoint
 	// Count positive temperature changes
 	positiveChanges := 0
 	for _, change := range tempChanges {
-		if change > 0.5 { // threshold for significant warming
+		if change > pr.TemperatureStep {
 			positiveChanges++
 		}
 	}
@@ -115,7 +116,7 @@ func (pr *PatternRecognizer) detectCoolingPattern(readings []models.WeatherPoint
 	// Count negative temperature changes
 	negativeChanges := 0
 	for _, change := range tempChanges {
-		if change < -0.5 { // threshold for significant cooling
+		if change < -pr.TemperatureStep {
 			negativeChanges++
 		}
 	}
@@ -148,7 +149,7 @@ func (pr *PatternRecognizer) detectHighPressurePattern(readings []models.Weather
 	totalPressure := 0.0
 	for _, reading := range readings {
 		totalPressure += reading.Pressure
-		if reading.Pressure > 1020.0 { // typical high pressure threshold
+		if reading.Pressure > pr.HighPressure {
 			highPressureCount++
 		}
 	}
@@ -156,7 +157,7 @@ func (pr *PatternRecognizer) detectHighPressurePattern(readings []models.Weather
 	avgPressure := totalPressure / float64(len(readings))
 	confidence := float64(highPressureCount) / float64(len(readings))
 
-	if confidence >= pr.MinPatternConfidence && avgPressure > 1015.0 {
+	if confidence >= pr.MinPatternConfidence && avgPressure > pr.HighPressureMean {
 		return &models.Pattern{
 			Name:        "high_pressure_system",
 			Description: "High pressure system with consistently elevated atmospheric pressure",
@@ -181,7 +182,7 @@ func (pr *PatternRecognizer) detectLowPressurePattern(readings []models.WeatherP
 	totalPressure := 0.0
 	for _, reading := range readings {
 		totalPressure += reading.Pressure
-		if reading.Pressure < 1000.0 { // typical low pressure threshold
+		if reading.Pressure < pr.LowPressure {
 			lowPressureCount++
 		}
 	}
@@ -189,7 +190,7 @@ func (pr *PatternRecognizer) detectLowPressurePattern(readings []models.WeatherP
 	avgPressure := totalPressure / float64(len(readings))
 	confidence := float64(lowPressureCount) / float64(len(readings))
 
-	if confidence >= pr.MinPatternConfidence && avgPressure < 1010.0 {
+	if confidence >= pr.MinPatternConfidence && avgPressure < pr.LowPressureMean {
 		return &models.Pattern{
 			Name:        "low_pressure_system",
 			Description: "Low pressure system with consistently reduced atmospheric pressure",
@@ -212,7 +213,7 @@ func (pr *PatternRecognizer) detectPrecipitationPattern(readings []models.Weathe
 	// Look for increasing precipitation patterns
 	precipitationEvents := 0
 	for _, reading := range readings {
-		if reading.PrecipitationMm > 0.1 || reading.PrecipitationProbability > 50 {
+		if reading.PrecipitationMm > pr.PrecipitationMm || reading.PrecipitationProbability > pr.PrecipitationProbability {
 			precipitationEvents++
 		}
 	}
@@ -223,10 +224,10 @@ func (pr *PatternRecognizer) detectPrecipitationPattern(readings []models.Weathe
 		description := "Precipitation expected or occurring"
 		patternName := "precipitation_pattern"
 
-		if confidence >= 0.7 {
+		if confidence >= pr.ConsistentPrecipitation {
 			description = "Consistent precipitation pattern"
 			patternName = "consistent_precipitation"
-		} else if confidence >= 0.4 {
+		} else if confidence >= pr.IntermittentPrecipitation {
 			description = "Intermittent precipitation pattern"
 			patternName = "intermittent_precipitation"
 		}
@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/derive"
+	"pattern-engine/models"
+)
+
+// maxComfortGap is the longest gap between muggy or bitter readings that still counts as one period
+const maxComfortGap = 3 * time.Hour
+
+// NewComfortAssessor creates a new comfort assessor with default settings
+func NewComfortAssessor() *ComfortAssessor {
+	return &ComfortAssessor{ComfortThresholds: DefaultThresholds().Comfort}
+}
+
+// AssessComfort reports how the weather feels from the current conditions (the latest reading at
+// or before now) on. The feel of a reading is its apparent temperature; it is muggy when its
+// humidex reaches MuggyHumidex and bitter when its wind chill is at or below BitterWindChill, and
+// runs of muggy or bitter coming readings become periods. Discomfort is how far the apparent
+// temperature is outside ComfortMin to ComfortMax; the trend compares that of the last reading
+// within TrendWindow to the current one, changing by TrendChange at least to count. It returns
+// nil without readings.
+func (ca *ComfortAssessor) AssessComfort(locationData *models.LocationData) *models.ComfortAssessment {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	now := time.Now()
+	if ca.now != nil {
+		now = ca.now()
+	}
+	current := max(sort.Search(len(readings), func(i int) bool { return readings[i].Timestamp.After(now) })-1, 0)
+
+	r := readings[current]
+	comfort := &models.ComfortAssessment{
+		FeelsLike: derive.ApparentTemperature(r.Temperature, r.Humidity, r.WindSpeed),
+		Humidex:   ca.humidex(r),
+		WindChill: derive.WindChill(r.Temperature, r.WindSpeed),
+		Trend:     "steady",
+		Outlook:   "comfort steady",
+	}
+	comfort.Level = ca.level(r)
+
+	end := current
+	for i := current; i < len(readings); i++ {
+		if !readings[i].Timestamp.After(r.Timestamp.Add(ca.TrendWindow)) {
+			end = i
+		}
+		level := ca.level(readings[i])
+		if level != "muggy" && level != "bitter" {
+			continue
+		}
+		comfort.Periods = ca.extendPeriod(comfort.Periods, level, readings[i])
+	}
+
+	change := ca.discomfort(readings[end]) - ca.discomfort(r)
+	switch {
+	case change <= -ca.TrendChange:
+		comfort.Trend = "improving"
+		comfort.Outlook = "becoming more comfortable " + partOfDay(hourOfDay(readings[end].Timestamp, LocalZone(locationData)))
+	case change >= ca.TrendChange:
+		comfort.Trend = "worsening"
+		comfort.Outlook = "becoming less comfortable " + partOfDay(hourOfDay(readings[end].Timestamp, LocalZone(locationData)))
+	}
+	return comfort
+}
+
+// level returns how a reading feels: "muggy", "bitter", "hot", "cold" or "comfortable"
+func (ca *ComfortAssessor) level(r models.WeatherPoint) string {
+	feelsLike := derive.ApparentTemperature(r.Temperature, r.Humidity, r.WindSpeed)
+	switch {
+	case ca.humidex(r) >= ca.MuggyHumidex:
+		return "muggy"
+	case derive.WindChill(r.Temperature, r.WindSpeed) <= ca.BitterWindChill:
+		return "bitter"
+	case feelsLike > ca.ComfortMax:
+		return "hot"
+	case feelsLike < ca.ComfortMin:
+		return "cold"
+	}
+	return "comfortable"
+}
+
+// discomfort returns how far (°C) the apparent temperature of a reading is outside the comfortable
+// range
+func (ca *ComfortAssessor) discomfort(r models.WeatherPoint) float64 {
+	feelsLike := derive.ApparentTemperature(r.Temperature, r.Humidity, r.WindSpeed)
+	return math.Max(math.Max(feelsLike-ca.ComfortMax, ca.ComfortMin-feelsLike), 0)
+}
+
+// humidex returns the humidex of a reading; without humidity it is the temperature
+func (ca *ComfortAssessor) humidex(r models.WeatherPoint) float64 {
+	if r.Humidity <= 0 {
+		return r.Temperature
+	}
+	return derive.Humidex(r.Temperature, derive.DewPoint(r.Temperature, r.Humidity))
+}
+
+// extendPeriod adds a muggy or bitter reading to the last period when it continues it, or starts a
+// new period with it
+func (ca *ComfortAssessor) extendPeriod(periods []models.ComfortPeriod, level string, r models.WeatherPoint) []models.ComfortPeriod {
+	peak := ca.humidex(r)
+	if level == "bitter" {
+		peak = derive.WindChill(r.Temperature, r.WindSpeed)
+	}
+	if n := len(periods); n > 0 && periods[n-1].Type == level && r.Timestamp.Sub(periods[n-1].End) <= maxComfortGap {
+		last := &periods[n-1]
+		last.End = r.Timestamp
+		if (level == "muggy" && peak > last.Peak) || (level == "bitter" && peak < last.Peak) {
+			last.Peak = peak
+		}
+		return periods
+	}
+	return append(periods, models.ComfortPeriod{Type: level, Start: r.Timestamp, End: r.Timestamp, Peak: peak})
+}
+
+// partOfDay names the part of the day of a local hour, such as "this evening"
+func partOfDay(hour float64) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "this morning"
+	case hour >= 12 && hour < 17:
+		return "this afternoon"
+	case hour >= 17 && hour < 21:
+		return "this evening"
+	}
+	return "tonight"
+}
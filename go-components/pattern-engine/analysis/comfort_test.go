@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestAssessComfort tests a muggy afternoon becoming comfortable in the evening
+func TestAssessComfort(t *testing.T) {
+	now := time.Date(2025, 7, 10, 14, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i, weather := range []struct{ temperature, humidity float64 }{
+		{32, 60}, {31, 60}, {29, 55}, {25, 50}, {22, 50}, {22, 50}, {22, 50},
+	} {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   now.Add(time.Duration(i) * time.Hour),
+			Temperature: weather.temperature,
+			Humidity:    weather.humidity,
+			WindSpeed:   1,
+		})
+	}
+	assessor := NewComfortAssessor()
+	assessor.now = func() time.Time { return now }
+
+	comfort := assessor.AssessComfort(&models.LocationData{Readings: readings})
+	if comfort == nil || comfort.Level != "muggy" || comfort.Humidex < 40 || comfort.FeelsLike <= 32 {
+		t.Fatalf("Expected a muggy afternoon feeling hotter than it is, got %+v", comfort)
+	}
+	if len(comfort.Periods) != 1 || comfort.Periods[0].Type != "muggy" || !comfort.Periods[0].End.Equal(now.Add(2*time.Hour)) ||
+		comfort.Periods[0].Peak != comfort.Humidex {
+		t.Errorf("Expected one muggy period until 16:00 peaking now, got %+v", comfort.Periods)
+	}
+	if comfort.Trend != "improving" || comfort.Outlook != "becoming more comfortable this evening" {
+		t.Errorf("Expected it to become more comfortable this evening, got %q (%q)", comfort.Outlook, comfort.Trend)
+	}
+}
+
+// TestAssessComfortBitter tests a bitter wind chill holding steady
+func TestAssessComfortBitter(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	readings := []models.WeatherPoint{
+		{Timestamp: now, Temperature: -5, Humidity: 80, WindSpeed: 8},
+		{Timestamp: now.Add(time.Hour), Temperature: -5, Humidity: 80, WindSpeed: 8},
+	}
+	assessor := NewComfortAssessor()
+	assessor.now = func() time.Time { return now }
+
+	comfort := assessor.AssessComfort(&models.LocationData{Readings: readings})
+	if comfort.Level != "bitter" || comfort.WindChill > -10 || comfort.Trend != "steady" {
+		t.Errorf("Expected a steady bitter wind chill, got %+v", comfort)
+	}
+	if len(comfort.Periods) != 1 || comfort.Periods[0].Type != "bitter" || comfort.Periods[0].Peak != comfort.WindChill {
+		t.Errorf("Expected one bitter period, got %+v", comfort.Periods)
+	}
+}
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"pattern-engine/models"
+)
+
+// minLagOverlap is the fewest hours two series must overlap at a lag for their correlation to count
+const minLagOverlap = 12
+
+// correlationVariables are the variables whose lead and lag relationships are measured
+var correlationVariables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }},
+	{"pressure", func(r models.WeatherPoint) float64 { return r.Pressure }},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }},
+	{"wind_speed", func(r models.WeatherPoint) float64 { return r.WindSpeed }},
+	{"cloud_cover", func(r models.WeatherPoint) float64 { return r.CloudCover }},
+	{"precipitation_mm", func(r models.WeatherPoint) float64 { return r.PrecipitationMm }},
+}
+
+// NewLagCorrelator creates a new lag correlator with default settings
+func NewLagCorrelator() *LagCorrelator {
+	return &LagCorrelator{DefaultThresholds().Correlations}
+}
+
+// AnalyzeLagCorrelations measures how each pair of variables moves together, and which moves
+// first. The readings are resampled to whole hours and their linear trends removed, so that two
+// variables merely drifting over the period do not correlate; then each pair's correlation is
+// computed with one variable shifted by up to MaxLagHours either way, and the strongest is kept.
+// Pairs are reported strongest first, leaving out variables that do not vary.
+func (lc *LagCorrelator) AnalyzeLagCorrelations(locationData *models.LocationData) []models.LagCorrelation {
+	readings := locationData.Readings
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	var names []string
+	var series [][]float64
+	for _, variable := range correlationVariables {
+		y := ResampleHourly(readings, variable.value)
+		if len(y) < lc.MaxLagHours+minLagOverlap {
+			return []models.LagCorrelation{}
+		}
+		if detrend(y) > 1e-9 {
+			names = append(names, variable.name)
+			series = append(series, y)
+		}
+	}
+
+	correlations := []models.LagCorrelation{}
+	for a := range series {
+		for b := a + 1; b < len(series); b++ {
+			best, bestLag := 0.0, 0
+			for lag := -lc.MaxLagHours; lag <= lc.MaxLagHours; lag++ {
+				if r := laggedCorrelation(series[a], series[b], lag); math.Abs(r) > math.Abs(best) {
+					best, bestLag = r, lag
+				}
+			}
+			leader, follower := names[a], names[b]
+			if bestLag < 0 {
+				leader, follower, bestLag = follower, leader, -bestLag
+			}
+			correlations = append(correlations, models.LagCorrelation{
+				Leader:      leader,
+				Follower:    follower,
+				LagHours:    bestLag,
+				Correlation: best,
+			})
+		}
+	}
+
+	sort.SliceStable(correlations, func(i, j int) bool {
+		return math.Abs(correlations[i].Correlation) > math.Abs(correlations[j].Correlation)
+	})
+	return correlations
+}
+
+// detrend removes the linear trend of y in place and returns the variance left
+func detrend(y []float64) float64 {
+	slope, intercept := fitLine(y)
+	var variance float64
+	for i := range y {
+		y[i] -= intercept + slope*float64(i)
+		variance += y[i] * y[i]
+	}
+	return variance / float64(len(y))
+}
+
+// laggedCorrelation returns the Pearson correlation of a with b lag hours later, over the hours
+// both cover
+func laggedCorrelation(a, b []float64, lag int) float64 {
+	start, end := max(0, -lag), min(len(a), len(b)-lag)
+	n := float64(end - start)
+	if n < minLagOverlap {
+		return 0
+	}
+	var meanA, meanB float64
+	for t := start; t < end; t++ {
+		meanA += a[t] / n
+		meanB += b[t+lag] / n
+	}
+	var sab, saa, sbb float64
+	for t := start; t < end; t++ {
+		da, db := a[t]-meanA, b[t+lag]-meanB
+		sab += da * db
+		saa += da * da
+		sbb += db * db
+	}
+	if saa == 0 || sbb == 0 {
+		return 0
+	}
+	return sab / math.Sqrt(saa*sbb)
+}
@@ -0,0 +1,45 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestAnalyzeLagCorrelations tests that precipitation following falling pressure by 6 hours is
+// found with its lag and sign
+func TestAnalyzeLagCorrelations(t *testing.T) {
+	start := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 96 {
+		h := float64(i)
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:       start.Add(time.Duration(i) * time.Hour),
+			Temperature:     12 + math.Sin(h*0.7), // Unrelated
+			Pressure:        1010 + 8*math.Sin(2*math.Pi*h/30),
+			PrecipitationMm: 2 - 2*math.Sin(2*math.Pi*(h-6)/30), // Heaviest 6 hours after the lowest pressure
+			Humidity:        80,
+		})
+	}
+
+	correlations := NewLagCorrelator().AnalyzeLagCorrelations(&models.LocationData{Readings: readings})
+	if len(correlations) != 3 { // Temperature, pressure and precipitation vary
+		t.Fatalf("Expected 3 pairs, got %+v", correlations)
+	}
+	strongest := correlations[0]
+	if strongest.Leader != "pressure" || strongest.Follower != "precipitation_mm" || strongest.LagHours != 6 || strongest.Correlation > -0.99 {
+		t.Errorf("Expected pressure to lead precipitation by 6 hours, anti-correlated, got %+v", strongest)
+	}
+	for _, c := range correlations[1:] {
+		if math.Abs(c.Correlation) > 0.5 {
+			t.Errorf("Expected temperature to be unrelated, got %+v", c)
+		}
+	}
+
+	short := &models.LocationData{Readings: readings[:20]}
+	if correlations := NewLagCorrelator().AnalyzeLagCorrelations(short); len(correlations) != 0 {
+		t.Errorf("Expected no correlations from 20 hours with a 12-hour lag, got %+v", correlations)
+	}
+}
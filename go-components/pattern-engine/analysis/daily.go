@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// minDaySpan is the part of a day its readings must span for the day's extremes to count, so
+// that the partial days at the ends of a series are left out
+const minDaySpan = 18 * time.Hour
+
+// DailyAggregate is the temperature of one local day
+type DailyAggregate struct {
+	Date     time.Time // Local midnight starting the day
+	Min      float64   // Lowest temperature
+	Max      float64   // Highest temperature
+	Mean     float64   // Mean temperature of the readings
+	Readings int       // Readings in the day
+}
+
+// AggregateDaily groups readings in chronological order by day in zone, and returns the days
+// their readings span at least 18 hours of, oldest first
+func AggregateDaily(readings []models.WeatherPoint, zone *time.Location) []DailyAggregate {
+	var days []DailyAggregate
+	var first, last time.Time // Span of the current day's readings
+	for _, r := range readings {
+		local := r.Timestamp.In(zone)
+		date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, zone)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			if len(days) > 0 && last.Sub(first) < minDaySpan {
+				days = days[:len(days)-1]
+			}
+			days = append(days, DailyAggregate{Date: date, Min: r.Temperature, Max: r.Temperature})
+			first = r.Timestamp
+		}
+		day := &days[len(days)-1]
+		day.Min = min(day.Min, r.Temperature)
+		day.Max = max(day.Max, r.Temperature)
+		day.Mean += (r.Temperature - day.Mean) / float64(day.Readings+1)
+		day.Readings++
+		last = r.Timestamp
+	}
+	if len(days) > 0 && last.Sub(first) < minDaySpan {
+		days = days[:len(days)-1]
+	}
+	return days
+}
+
+// solarZone returns the time zone of local solar time at longitude, to the minute
+func solarZone(longitude float64) *time.Location {
+	return time.FixedZone("solar", int(longitude/15*60)*60)
+}
+
+// LocalZone returns the time zone of a location's days and hours of the day: its IANA time zone,
+// or local solar time at its longitude without one the host knows
+func LocalZone(locationData *models.LocationData) *time.Location {
+	if locationData.Timezone != "" {
+		if zone, err := time.LoadLocation(locationData.Timezone); err == nil {
+			return zone
+		}
+	}
+	return solarZone(locationData.Coordinates.Longitude)
+}
+
+// NewExtremeEventDetector creates a new extreme event detector with default settings
+func NewExtremeEventDetector() *ExtremeEventDetector {
+	return &ExtremeEventDetector{DefaultThresholds().Extremes}
+}
+
+// DetectExtremeEvents finds heat waves and cold snaps: at least MinDays consecutive local days
+// whose highest temperature is above the HeatPercentile, or below the ColdPercentile, of the
+// days' highest temperatures. It needs MinBaselineDays days for the percentiles.
+func (ed *ExtremeEventDetector) DetectExtremeEvents(locationData *models.LocationData) []models.ExtremeEvent {
+	readings := locationData.Readings
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+	days := AggregateDaily(readings, LocalZone(locationData))
+	if len(days) < ed.MinBaselineDays {
+		return []models.ExtremeEvent{}
+	}
+
+	maxima := make([]float64, len(days))
+	for i, day := range days {
+		maxima[i] = day.Max
+	}
+	sort.Float64s(maxima)
+	heat := percentile(maxima, ed.HeatPercentile/100)
+	cold := percentile(maxima, ed.ColdPercentile/100)
+
+	events := ed.runs(days, "heat_wave", heat, func(t float64) float64 { return t - heat })
+	events = append(events, ed.runs(days, "cold_snap", cold, func(t float64) float64 { return cold - t })...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+// runs returns the events of the given type: runs of at least MinDays consecutive days whose
+// highest temperature is beyond threshold, excess being the distance beyond it (positive)
+func (ed *ExtremeEventDetector) runs(days []DailyAggregate, eventType string, threshold float64, excess func(float64) float64) []models.ExtremeEvent {
+	events := []models.ExtremeEvent{}
+	start := -1 // First day of the current run
+	for i := 0; i <= len(days); i++ {
+		beyond := i < len(days) && excess(days[i].Max) > 0
+		consecutive := i > 0 && i < len(days) && days[i].Date.Equal(days[i-1].Date.AddDate(0, 0, 1)) // Days of 23 or 25 hours at DST changes
+		if start >= 0 && (!beyond || !consecutive) {
+			if i-start >= ed.MinDays {
+				event := models.ExtremeEvent{
+					Type:         eventType,
+					Start:        days[start].Date,
+					End:          days[i-1].Date.AddDate(0, 0, 1),
+					DurationDays: i - start,
+					Threshold:    threshold,
+				}
+				for _, day := range days[start:i] {
+					if e := excess(day.Max); e > event.Intensity {
+						event.Intensity, event.PeakTemperature, event.PeakDate = e, day.Max, day.Date
+					}
+				}
+				events = append(events, event)
+			}
+			start = -1
+		}
+		if beyond && start < 0 {
+			start = i
+		}
+	}
+	return events
+}
@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestDetectExtremeEvents tests that a run of hot days is a heat wave and a run of cold days a
+// cold snap, by local solar day, and that a short run is neither
+func TestDetectExtremeEvents(t *testing.T) {
+	const longitude = 30 // Local solar time is 2 hours ahead of UTC
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, solarZone(longitude))
+	highs := map[int]float64{3: 27, 5: 30, 6: 33, 7: 31, 8: 29, 14: 6, 15: 4, 16: 5}
+	var readings []models.WeatherPoint
+	for i := range 20*24 + 6 { // Ending with a partial day, which is left out
+		day := i / 24
+		high, ok := highs[day]
+		if !ok {
+			high = 20
+		}
+		reading := models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour).UTC(),
+			Temperature: high - 8 + 8*math.Sin(math.Pi*float64(i%24)/24), // Peaking at local noon
+		}
+		readings = append(readings, reading)
+	}
+
+	detector := NewExtremeEventDetector()
+	detector.HeatPercentile, detector.ColdPercentile = 75, 25
+	events := detector.DetectExtremeEvents(&models.LocationData{
+		Readings:    readings,
+		Coordinates: models.Coordinates{Longitude: longitude},
+	})
+	if len(events) != 2 {
+		t.Fatalf("Expected a heat wave and a cold snap, got %+v", events)
+	}
+
+	heat := events[0]
+	if heat.Type != "heat_wave" || !heat.Start.Equal(start.AddDate(0, 0, 5)) || !heat.End.Equal(start.AddDate(0, 0, 9)) ||
+		heat.DurationDays != 4 {
+		t.Errorf("Expected a 4-day heat wave from day 5, got %+v", heat)
+	}
+	if !heat.PeakDate.Equal(start.AddDate(0, 0, 6)) || math.Abs(heat.PeakTemperature-33) > 1e-9 ||
+		math.Abs(heat.Intensity-(33-heat.Threshold)) > 1e-9 {
+		t.Errorf("Expected the heat wave to peak at 33°C on day 6, got %+v", heat)
+	}
+
+	cold := events[1]
+	if cold.Type != "cold_snap" || !cold.Start.Equal(start.AddDate(0, 0, 14)) || cold.DurationDays != 3 ||
+		math.Abs(cold.PeakTemperature-4) > 1e-9 || math.Abs(cold.Threshold-20) > 1e-9 {
+		t.Errorf("Expected a 3-day cold snap from day 14 peaking at 4°C, got %+v", cold)
+	}
+
+	if events := detector.DetectExtremeEvents(&models.LocationData{Readings: readings[:5*24]}); len(events) != 0 {
+		t.Errorf("Expected no events without a baseline, got %+v", events)
+	}
+}
+
+// TestAggregateDaily tests that readings are grouped by local solar day and that days spanning
+// too little of the day are left out
+func TestAggregateDaily(t *testing.T) {
+	start := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC) // Midnight local solar time at 180°E
+	var readings []models.WeatherPoint
+	for i := range 30 {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: float64(i % 24),
+		})
+	}
+
+	days := AggregateDaily(readings, solarZone(180))
+	if len(days) != 1 {
+		t.Fatalf("Expected one whole day, got %+v", days)
+	}
+	if d := days[0]; d.Date.UTC() != start || d.Min != 0 || d.Max != 23 || d.Mean != 11.5 || d.Readings != 24 {
+		t.Errorf("Expected a day from 0 to 23°C averaging 11.5°C, got %+v", d)
+	}
+}
+
+// TestLocalZone tests that a location's days are those of its time zone, or else of solar time
+func TestLocalZone(t *testing.T) {
+	location := &models.LocationData{Coordinates: models.Coordinates{Longitude: 10.75}, Timezone: "Europe/Oslo"}
+	// 23:30 UTC is 01:30 in summer time (UTC+2) and 00:13 in solar time (UTC+0:43)
+	at := time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC)
+	if local := at.In(LocalZone(location)); local.Day() != 2 || local.Hour() != 1 {
+		t.Errorf("Expected 01:30 on July 2 in Oslo, got %v", local)
+	}
+
+	location.Timezone = "Nowhere/Unknown"
+	if local := at.In(LocalZone(location)); local.Day() != 2 || local.Hour() != 0 || local.Minute() != 13 {
+		t.Errorf("Expected 00:13 solar time for an unknown zone, got %v", local)
+	}
+}
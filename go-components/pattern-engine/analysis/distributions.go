@@ -0,0 +1,164 @@
+package analysis
+
+import "math"
+
+// studentTCDF returns the probability that a Student's t variable with df degrees of freedom is
+// at most t
+func studentTCDF(t, df float64) float64 {
+	tail := 0.5 * regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentTQuantile returns the value a Student's t variable with df degrees of freedom is at most
+// with probability p (0 < p < 1), found by bisection of studentTCDF
+func studentTQuantile(p, df float64) float64 {
+	if p == 0.5 {
+		return 0
+	}
+	lo, hi := -1.0, 1.0
+	for studentTCDF(lo, df) > p {
+		lo *= 2
+	}
+	for studentTCDF(hi, df) < p {
+		hi *= 2
+	}
+	for range 100 {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// tInterval returns the half-width of the two-sided confidence interval at level confidence of an
+// estimate with standard error se and df degrees of freedom
+func tInterval(se, df, confidence float64) float64 {
+	if df < 1 || se == 0 {
+		return 0
+	}
+	return studentTQuantile((1+confidence)/2, df) * se
+}
+
+// regularizedIncompleteBeta returns I_x(a, b), evaluated with the continued fraction of Numerical
+// Recipes (betacf), using the symmetry I_x(a, b) = 1 - I_(1-x)(b, a) where it converges faster
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction of the incomplete beta function by the
+// modified Lentz method
+func betaContinuedFraction(x, a, b float64) float64 {
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1.0; m <= 300; m++ {
+		// Even step
+		num := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Odd step
+		num = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-14 {
+			break
+		}
+	}
+	return h
+}
+
+// chiSquareSurvival returns the probability that a chi-square variable with k degrees of freedom
+// exceeds x
+func chiSquareSurvival(x, k float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return 1 - regularizedGammaP(k/2, x/2)
+}
+
+// regularizedGammaP returns the regularized lower incomplete gamma function P(a, x), by its series
+// below a+1 and its continued fraction above (Numerical Recipes gser and gcf)
+func regularizedGammaP(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	lga, _ := math.Lgamma(a)
+	front := math.Exp(-x + a*math.Log(x) - lga)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1.0; n <= 500; n++ {
+			term *= x / (a + n)
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return front * sum
+	}
+
+	// Modified Lentz method
+	const tiny = 1e-300
+	b := x + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+	for i := 1.0; i <= 500; i++ {
+		an := -i * (i - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return 1 - front*h
+}
@@ -0,0 +1,46 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+// TestStudentTQuantile tests quantiles against tabulated values of Student's t distribution
+func TestStudentTQuantile(t *testing.T) {
+	tests := []struct {
+		p, df, want float64
+	}{
+		{0.975, 1, 12.7062},
+		{0.975, 4, 2.7764},
+		{0.95, 10, 1.8125},
+		{0.975, 30, 2.0423},
+		{0.995, 1000, 2.5808},
+		{0.025, 4, -2.7764},
+	}
+	for _, tt := range tests {
+		if got := studentTQuantile(tt.p, tt.df); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("studentTQuantile(%v, %v) = %v, want %v", tt.p, tt.df, got, tt.want)
+		}
+	}
+	if got := studentTCDF(2.7764, 4); math.Abs(got-0.975) > 1e-4 {
+		t.Errorf("studentTCDF(2.7764, 4) = %v, want 0.975", got)
+	}
+}
+
+// TestChiSquareSurvival tests upper tail probabilities against tabulated chi-square values
+func TestChiSquareSurvival(t *testing.T) {
+	tests := []struct {
+		x, k, want float64
+	}{
+		{3.841, 1, 0.05},
+		{9.488, 4, 0.05},
+		{22.458, 6, 0.001},
+		{1, 6, 0.9856},
+		{0, 3, 1},
+	}
+	for _, tt := range tests {
+		if got := chiSquareSurvival(tt.x, tt.k); math.Abs(got-tt.want) > 1e-4+0.01*tt.want {
+			t.Errorf("chiSquareSurvival(%v, %v) = %v, want %v", tt.x, tt.k, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// anemometerHeight is the height (m) the wind speed of the readings is measured at
+const anemometerHeight = 10
+
+// NewEnergyEstimator creates a new energy estimator with default settings
+func NewEnergyEstimator() *EnergyEstimator {
+	return &EnergyEstimator{DefaultThresholds().Energy}
+}
+
+// EstimateEnergy estimates the solar and wind power potential at each reading. Solar power is the
+// clear-sky irradiance on level ground at the sun's elevation (Haurwitz 1945), dimmed by the
+// cloud cover (Kasten and Czeplak 1980), times PerformanceRatio: kW per kW peak of panels. Wind
+// power is the output of a turbine as a share of its rated power, on a cubic power curve from
+// CutInSpeed to RatedSpeed and none from CutOutSpeed, at the wind speed raised from 10 m to
+// HubHeight by the power law with ShearExponent.
+func (ee *EnergyEstimator) EstimateEnergy(locationData *models.LocationData) []models.EnergyEstimate {
+	readings := locationData.Readings
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	estimates := make([]models.EnergyEstimate, 0, len(readings))
+	for _, r := range readings {
+		elevation := SolarElevation(r.Timestamp, locationData.Coordinates.Latitude, locationData.Coordinates.Longitude)
+		irradiance := clearSkyIrradiance(elevation) * (1 - 0.75*math.Pow(unitClamp(r.CloudCover/100), 3.4))
+		hubSpeed := r.WindSpeed * math.Pow(ee.HubHeight/anemometerHeight, ee.ShearExponent)
+
+		estimates = append(estimates, models.EnergyEstimate{
+			Timestamp:      r.Timestamp,
+			SolarElevation: elevation,
+			Irradiance:     irradiance,
+			SolarOutput:    irradiance / 1000 * ee.PerformanceRatio, // Panels are rated at 1000 W/m²
+			HubWindSpeed:   hubSpeed,
+			WindOutput:     ee.powerCurve(hubSpeed),
+		})
+	}
+	return estimates
+}
+
+// powerCurve returns the output of a turbine at a hub-height wind speed, as a share of its rated
+// power (0-1)
+func (ee *EnergyEstimator) powerCurve(speed float64) float64 {
+	switch {
+	case speed < ee.CutInSpeed || speed >= ee.CutOutSpeed:
+		return 0
+	case speed >= ee.RatedSpeed:
+		return 1
+	}
+	cutIn := math.Pow(ee.CutInSpeed, 3)
+	return (math.Pow(speed, 3) - cutIn) / (math.Pow(ee.RatedSpeed, 3) - cutIn)
+}
+
+// clearSkyIrradiance returns the global horizontal irradiance (W/m²) of a clear sky with the sun
+// at an elevation in degrees, by the Haurwitz model
+func clearSkyIrradiance(elevation float64) float64 {
+	if elevation <= 0 {
+		return 0
+	}
+	cosZenith := math.Sin(elevation * math.Pi / 180)
+	return 1098 * cosZenith * math.Exp(-0.057/cosZenith)
+}
+
+// SolarElevation returns the elevation of the sun above the horizon in degrees (negative below
+// it) at t, at a latitude and longitude, by the NOAA approximation of the solar declination and
+// equation of time
+func SolarElevation(t time.Time, latitude, longitude float64) float64 {
+	utc := t.UTC()
+	hour := float64(utc.Hour()) + float64(utc.Minute())/60 + float64(utc.Second())/3600
+	gamma := 2 * math.Pi / 365 * (float64(utc.YearDay()-1) + (hour-12)/24) // Fractional year (radians)
+
+	declination := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+	equationOfTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma)) // Minutes
+
+	solarTime := hour*60 + equationOfTime + 4*longitude // Minutes
+	hourAngle := (solarTime/4 - 180) * math.Pi / 180
+	lat := latitude * math.Pi / 180
+	cosZenith := math.Sin(lat)*math.Sin(declination) + math.Cos(lat)*math.Cos(declination)*math.Cos(hourAngle)
+	return 90 - math.Acos(math.Max(math.Min(cosZenith, 1), -1))*180/math.Pi
+}
@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestSolarElevation tests the sun's elevation at noon and midnight
+func TestSolarElevation(t *testing.T) {
+	// Oslo at solar noon of the summer solstice: 90 - 59.91 + 23.44
+	if elevation := SolarElevation(time.Date(2025, 6, 21, 11, 13, 0, 0, time.UTC), 59.91, 10.75); math.Abs(elevation-53.5) > 0.5 {
+		t.Errorf("Expected the sun 53.5° high at midsummer noon in Oslo, got %.2f", elevation)
+	}
+	if elevation := SolarElevation(time.Date(2025, 3, 20, 12, 7, 0, 0, time.UTC), 0, 0); elevation < 88 {
+		t.Errorf("Expected the sun overhead at the equator at the equinox, got %.2f", elevation)
+	}
+	if elevation := SolarElevation(time.Date(2025, 6, 21, 23, 13, 0, 0, time.UTC), 59.91, 10.75); elevation >= 0 {
+		t.Errorf("Expected the sun below the horizon at midnight, got %.2f", elevation)
+	}
+}
+
+// TestEstimateEnergy tests solar output under clear and overcast skies and at night, and the
+// turbine's power curve from below cut-in to past cut-out
+func TestEstimateEnergy(t *testing.T) {
+	noon := time.Date(2025, 6, 21, 11, 13, 0, 0, time.UTC)
+	readings := []models.WeatherPoint{
+		{Timestamp: noon, CloudCover: 0, WindSpeed: 2},
+		{Timestamp: noon.Add(time.Hour), CloudCover: 100, WindSpeed: 5},
+		{Timestamp: noon.Add(2 * time.Hour), CloudCover: 0, WindSpeed: 10},
+		{Timestamp: noon.Add(12 * time.Hour), CloudCover: 0, WindSpeed: 20},
+	}
+	estimates := NewEnergyEstimator().EstimateEnergy(&models.LocationData{
+		Readings:    readings,
+		Coordinates: models.Coordinates{Latitude: 59.91, Longitude: 10.75},
+	})
+	if len(estimates) != len(readings) {
+		t.Fatalf("Expected an estimate per reading, got %d", len(estimates))
+	}
+
+	clear, overcast, night := estimates[0], estimates[1], estimates[3]
+	if clear.Irradiance < 700 || clear.Irradiance > 1000 || math.Abs(clear.SolarOutput-clear.Irradiance/1000*0.8) > 1e-9 {
+		t.Errorf("Expected 700-1000 W/m² under a clear midsummer sky, got %+v", clear)
+	}
+	if ratio := overcast.Irradiance / clearSkyIrradiance(overcast.SolarElevation); math.Abs(ratio-0.25) > 1e-9 {
+		t.Errorf("Expected an overcast sky to let through a quarter of the sunlight, got %.3f", ratio)
+	}
+	if night.SolarOutput != 0 {
+		t.Errorf("Expected no solar output at night, got %+v", night)
+	}
+
+	// 10 m wind speeds of 2, 5, 10 and 20 m/s are 2.8, 6.9, 13.9 and 27.8 m/s at a 100 m hub
+	for i, want := range []float64{0, 0.1814, 1, 0} {
+		if got := estimates[i].WindOutput; math.Abs(got-want) > 1e-3 {
+			t.Errorf("Expected a wind output of %.4f at %.1f m/s, got %.4f", want, estimates[i].HubWindSpeed, got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/derive"
+	"pattern-engine/models"
+)
+
+// maxFogGap is the longest gap between foggy readings that still counts as one fog window
+const maxFogGap = 3 * time.Hour
+
+// NewFogDetector creates a new fog detector with default settings
+func NewFogDetector() *FogDetector {
+	return &FogDetector{DefaultThresholds().Fog}
+}
+
+// DetectFog finds the windows in which fog is likely: readings at night (NightStartHour to
+// NightEndHour local solar time, from the location's longitude) whose temperature is within
+// MaxSpread of the dew point, with humidity of at least MinHumidity and wind of at most
+// MaxWindSpeed. Each window of such readings becomes a "fog_risk" pattern from its first to its
+// last reading; the narrower the spread, the likelier the fog. The measured readings are needed,
+// as removing the daily cycle would remove the night's cooling that forms the fog.
+func (fd *FogDetector) DetectFog(locationData *models.LocationData) []models.Pattern {
+	readings := locationData.Readings
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	var patterns []models.Pattern
+	start := -1 // First reading of the current window
+	for i := 0; i <= len(readings); i++ {
+		foggy := i < len(readings) && fd.foggy(readings[i], locationData.Coordinates.Longitude)
+		if foggy && start >= 0 && readings[i].Timestamp.Sub(readings[i-1].Timestamp) > maxFogGap {
+			patterns = append(patterns, fd.fogPattern(readings[start:i]))
+			start = -1
+		}
+		if foggy && start < 0 {
+			start = i
+		}
+		if !foggy && start >= 0 {
+			patterns = append(patterns, fd.fogPattern(readings[start:i]))
+			start = -1
+		}
+	}
+	return patterns
+}
+
+// foggy reports whether fog can form at a reading, at a location at longitude
+func (fd *FogDetector) foggy(r models.WeatherPoint, longitude float64) bool {
+	hour := solarHour(r.Timestamp, longitude)
+	night := hour >= fd.NightStartHour || hour < fd.NightEndHour
+	if fd.NightStartHour < fd.NightEndHour {
+		night = hour >= fd.NightStartHour && hour < fd.NightEndHour
+	}
+	return night && r.Temperature-dewPoint(r) <= fd.MaxSpread && r.Humidity >= fd.MinHumidity &&
+		r.WindSpeed <= fd.MaxWindSpeed
+}
+
+// fogPattern returns the fog_risk pattern of a window of foggy readings
+func (fd *FogDetector) fogPattern(window []models.WeatherPoint) models.Pattern {
+	var spread, fogFraction float64
+	for _, r := range window {
+		spread += (r.Temperature - dewPoint(r)) / float64(len(window))
+		fogFraction = math.Max(fogFraction, r.FogAreaFraction/100)
+	}
+	likelihood := unitClamp(1 - spread/fd.MaxSpread)
+	first, last := window[0].Timestamp, window[len(window)-1].Timestamp
+
+	return models.Pattern{
+		Name: "fog_risk",
+		Description: fmt.Sprintf("Fog likely from %s to %s UTC: temperature within %.1f°C of the dew point in light winds",
+			first.UTC().Format("Jan 2 15:04"), last.UTC().Format("Jan 2 15:04"), spread),
+		Confidence: 0.6 + 0.4*likelihood, // The conditions for fog are met; a narrower spread makes it likelier
+		Strength:   math.Max(likelihood, fogFraction),
+		Variables:  []string{"temperature", "dew_point", "humidity", "wind_speed"},
+		Readings:   window,
+		Timestamp:  first,
+		Until:      last,
+	}
+}
+
+// dewPoint returns the dew point of a reading: the reported one, or else one computed from the
+// temperature and humidity by the Magnus formula
+func dewPoint(r models.WeatherPoint) float64 {
+	if r.DewPoint != 0 || r.Humidity <= 0 {
+		return r.DewPoint
+	}
+	return derive.DewPoint(r.Temperature, r.Humidity)
+}
+
+// solarHour returns the local solar time of t at longitude, in hours (0-24)
+func solarHour(t time.Time, longitude float64) float64 {
+	utc := t.UTC()
+	hour := float64(utc.Hour()) + float64(utc.Minute())/60 + longitude/15
+	return math.Mod(hour+24, 24)
+}
@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestDetectFog tests that a calm, humid night near the dew point is a fog window, while a windy
+// one and a humid afternoon are not
+func TestDetectFog(t *testing.T) {
+	start := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	var calm, windy []models.WeatherPoint
+	for i := range 24 {
+		reading := models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: 6, // At the dew point, but in the afternoon
+			DewPoint:    5.5,
+			Humidity:    97,
+			WindSpeed:   1.5,
+		}
+		if hour := reading.Timestamp.Hour(); hour >= 18 && hour < 21 || hour >= 9 && hour < 12 {
+			reading.Temperature, reading.Humidity = 14, 60
+		}
+		calm = append(calm, reading)
+		reading.WindSpeed = 8
+		windy = append(windy, reading)
+	}
+
+	fog := NewFogDetector().DetectFog(&models.LocationData{Readings: calm})
+	if len(fog) != 1 {
+		t.Fatalf("Expected one fog window, got %+v", fog)
+	}
+	if f := fog[0]; f.Name != "fog_risk" || !f.Timestamp.Equal(start.Add(9*time.Hour)) || !f.Until.Equal(start.Add(20*time.Hour)) ||
+		len(f.Readings) != 12 || f.Confidence <= 0.6 {
+		t.Errorf("Expected fog from 21:00 to 08:00, got %s from %v to %v (%d readings, confidence %.2f)",
+			f.Name, f.Timestamp, f.Until, len(f.Readings), f.Confidence)
+	}
+
+	if fog := NewFogDetector().DetectFog(&models.LocationData{Readings: windy}); len(fog) != 0 {
+		t.Errorf("Expected no fog in a windy night, got %+v", fog)
+	}
+}
+
+// TestDewPoint tests the Magnus formula and that a reported dew point is used as is
+func TestDewPoint(t *testing.T) {
+	if td := dewPoint(models.WeatherPoint{Temperature: 20, Humidity: 50}); math.Abs(td-9.26) > 0.05 {
+		t.Errorf("Expected a dew point of about 9.26°C at 20°C and 50%%, got %.2f", td)
+	}
+	if td := dewPoint(models.WeatherPoint{Temperature: 20, Humidity: 50, DewPoint: 11}); td != 11 {
+		t.Errorf("Expected the reported dew point, got %.2f", td)
+	}
+}
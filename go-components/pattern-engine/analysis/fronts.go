@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"math"
+
+	"pattern-engine/models"
+	"pattern-engine/utils"
+)
+
+// detectFrontPassages detects fronts passing over the location, at the lowest pressure of a
+// trough where pressure falls by FrontPressureFall within FrontWindow and rises by
+// FrontPressureRise within FrontWindow after. A slow low has no such trough. The trough is a
+// front when it is joined by at least some of the wind veering (turning clockwise) by
+// FrontWindShift degrees, the temperature changing by FrontTemperatureChange and humidity
+// spiking by FrontHumiditySpike around it; a falling temperature makes it a cold front and a
+// rising one a warm front. Readings must be in chronological order.
+func (pr *PatternRecognizer) detectFrontPassages(readings []models.WeatherPoint) []models.Pattern {
+	var fronts []models.Pattern
+	for i := 0; i < len(readings); i++ {
+		passage := readings[i].Timestamp
+		lo, hi := i, i+1 // Readings within FrontWindow either side of the passage
+		for lo > 0 && passage.Sub(readings[lo-1].Timestamp) <= pr.FrontWindow {
+			lo--
+		}
+		for hi < len(readings) && readings[hi].Timestamp.Sub(passage) <= pr.FrontWindow {
+			hi++
+		}
+		if lo == i || hi == i+1 || !lowestAt(readings[lo:hi], i-lo) {
+			continue
+		}
+		before, after := readings[lo:i], readings[i+1:hi]
+
+		// The trough: a rapid fall, then a rise
+		fall := maxPressure(before) - readings[i].Pressure
+		rise := maxPressure(after) - readings[i].Pressure
+		if fall < pr.FrontPressureFall || rise < pr.FrontPressureRise {
+			continue
+		}
+
+		evidence := 1.0 // Signs of a front, of 4
+		variables := []string{"pressure"}
+		veer := angleDifference(meanDirection(before), meanDirection(after))
+		if veer >= pr.FrontWindShift {
+			evidence++
+			variables = append(variables, "wind_direction")
+		}
+		temperatureChange := calculateAverage(utils.GetTemperatureValues(after)) - calculateAverage(utils.GetTemperatureValues(before))
+		if math.Abs(temperatureChange) >= pr.FrontTemperatureChange {
+			evidence++
+			variables = append(variables, "temperature")
+		}
+		var peakHumidity float64
+		for _, r := range readings[lo:hi] {
+			if r.Timestamp.Sub(passage).Abs() <= pr.FrontWindow/2 {
+				peakHumidity = math.Max(peakHumidity, r.Humidity)
+			}
+		}
+		if peakHumidity-calculateAverage(utils.GetHumidityValues(before)) >= pr.FrontHumiditySpike {
+			evidence++
+			variables = append(variables, "humidity")
+		}
+
+		confidence := evidence / 4
+		if confidence < pr.MinPatternConfidence {
+			continue
+		}
+		description := "Front passage: pressure fell and rose again"
+		switch {
+		case temperatureChange <= -pr.FrontTemperatureChange:
+			description = "Cold front passage: pressure fell and rose again as the temperature dropped"
+		case temperatureChange >= pr.FrontTemperatureChange:
+			description = "Warm front passage: pressure fell and rose again as the temperature rose"
+		}
+		fronts = append(fronts, models.Pattern{
+			Name:        "front_passage",
+			Description: description,
+			Confidence:  confidence,
+			Strength:    math.Min(1.0, (fall+rise)/(4*(pr.FrontPressureFall+pr.FrontPressureRise))),
+			Variables:   variables,
+			Readings:    readings[lo:hi],
+			Timestamp:   passage,
+		})
+
+		// One front per trough
+		for i+1 < len(readings) && readings[i+1].Timestamp.Sub(passage) <= pr.FrontWindow {
+			i++
+		}
+	}
+	return fronts
+}
+
+// lowestAt reports whether the reading at index i has the lowest pressure of readings, and is the
+// first with it
+func lowestAt(readings []models.WeatherPoint, i int) bool {
+	for j, r := range readings {
+		if r.Pressure < readings[i].Pressure || (r.Pressure == readings[i].Pressure && j < i) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxPressure returns the highest pressure of readings
+func maxPressure(readings []models.WeatherPoint) float64 {
+	highest := math.Inf(-1)
+	for _, r := range readings {
+		highest = math.Max(highest, r.Pressure)
+	}
+	return highest
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestDetectFrontPassages tests that a cold front is found at its pressure trough, and that a
+// slowly deepening low is not taken for a front
+func TestDetectFrontPassages(t *testing.T) {
+	start := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	var front, slowLow []models.WeatherPoint
+	for i := range 48 {
+		h := float64(i)
+		reading := models.WeatherPoint{
+			Timestamp:     start.Add(time.Duration(i) * time.Hour),
+			Temperature:   15,
+			Pressure:      1012 - 0.8*(24-math.Abs(h-24)), // Lowest at hour 24
+			Humidity:      70,
+			WindDirection: 200,
+		}
+		if i > 24 {
+			reading.Temperature, reading.WindDirection = 9, 290
+		}
+		if i >= 23 && i <= 25 {
+			reading.Humidity = 92
+		}
+		front = append(front, reading)
+
+		slowLow = append(slowLow, models.WeatherPoint{
+			Timestamp:     reading.Timestamp,
+			Temperature:   15 - 0.1*h,
+			Pressure:      1012 - 0.3*h,
+			Humidity:      70 + 0.4*h,
+			WindDirection: 200 + h,
+		})
+	}
+
+	fronts := NewPatternRecognizer().detectFrontPassages(front)
+	if len(fronts) != 1 {
+		t.Fatalf("Expected one front, got %+v", fronts)
+	}
+	f := fronts[0]
+	if f.Name != "front_passage" || !f.Timestamp.Equal(start.Add(24*time.Hour)) || f.Confidence != 1 ||
+		f.Description != "Cold front passage: pressure fell and rose again as the temperature dropped" {
+		t.Errorf("Expected a cold front at hour 24 with every sign, got %s at %v (confidence %.2f): %s",
+			f.Name, f.Timestamp, f.Confidence, f.Description)
+	}
+	if len(f.Readings) != 13 {
+		t.Errorf("Expected the readings 6 hours either side of the front, got %d", len(f.Readings))
+	}
+
+	if fronts := NewPatternRecognizer().detectFrontPassages(slowLow); len(fronts) != 0 {
+		t.Errorf("Expected no front in a slow low, got %+v", fronts)
+	}
+}
+
+// TestMeanDirection tests that wind directions are averaged around the compass
+func TestMeanDirection(t *testing.T) {
+	readings := []models.WeatherPoint{{WindDirection: 350}, {WindDirection: 10}}
+	if d := meanDirection(readings); math.Abs(angleDifference(d, 0)) > 1e-9 {
+		t.Errorf("Expected 350° and 10° to average to north, got %.1f", d)
+	}
+	if veer := angleDifference(350, 20); math.Abs(veer-30) > 1e-9 {
+		t.Errorf("Expected a 30° veer from 350° to 20°, got %.1f", veer)
+	}
+	if back := angleDifference(20, 350); math.Abs(back+30) > 1e-9 {
+		t.Errorf("Expected a 30° back from 20° to 350°, got %.1f", back)
+	}
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// NewRoadIcingDetector creates a new road icing detector with default settings
+func NewRoadIcingDetector() *RoadIcingDetector {
+	return &RoadIcingDetector{RoadIcingThresholds: DefaultThresholds().RoadIcing}
+}
+
+// AssessRoadIcing scores each coming reading, from the current conditions (the latest reading at
+// or before now) on, for the risk of ice on roads. The road surface is taken to be at the air
+// temperature, less up to NightCooling as a clear sky radiates its heat away at night (the sun
+// below the horizon at the location). A road at or below freezing scores fully for cold, fading
+// out at MaxTemperature; the score is that times how wet the road is, from the precipitation over
+// PrecipitationWindow up to the reading (fully wet at WetPrecipitation) or frost deposited from
+// humid air (from MinHumidity, fully at saturation). The risk is that of the highest score; its
+// onset is the first reading scoring at least AlertScore. It returns nil when the readings end
+// more than PrecipitationWindow before now, as a history has no ice to come.
+func (rd *RoadIcingDetector) AssessRoadIcing(locationData *models.LocationData) *models.RoadIcingRisk {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	now := time.Now()
+	if rd.now != nil {
+		now = rd.now()
+	}
+	if readings[len(readings)-1].Timestamp.Before(now.Add(-rd.PrecipitationWindow)) {
+		return nil
+	}
+	current := max(sort.Search(len(readings), func(i int) bool { return readings[i].Timestamp.After(now) })-1, 0)
+
+	risk := &models.RoadIcingRisk{Score: -1}
+	start := 0 // First reading within PrecipitationWindow before reading i
+	var recent float64
+	for i, r := range readings {
+		recent += r.PrecipitationMm
+		for ; readings[start].Timestamp.Before(r.Timestamp.Add(-rd.PrecipitationWindow)); start++ {
+			recent -= readings[start].PrecipitationMm
+		}
+		if i < current {
+			continue
+		}
+
+		surface := r.Temperature
+		if SolarElevation(r.Timestamp, locationData.Coordinates.Latitude, locationData.Coordinates.Longitude) <= 0 {
+			surface -= rd.NightCooling * (1 - unitClamp(r.CloudCover/100))
+		}
+		cold := unitClamp(1 - surface/rd.MaxTemperature)
+		wet := math.Max(unitClamp(recent/rd.WetPrecipitation), unitClamp((r.Humidity-rd.MinHumidity)/(100-rd.MinHumidity)))
+		hour := models.RoadIcingHour{
+			Timestamp:           r.Timestamp,
+			SurfaceTemperature:  surface,
+			RecentPrecipitation: math.Max(recent, 0), // Rounding can leave a hair below 0
+			Score:               cold * wet,
+		}
+		risk.Hours = append(risk.Hours, hour)
+
+		if hour.Score >= rd.AlertScore && risk.Onset.IsZero() {
+			risk.Onset = r.Timestamp
+			risk.LeadTimeHours = math.Max(risk.Onset.Sub(now).Hours(), 0)
+		}
+		if hour.Score > risk.Score {
+			risk.Score, risk.PeakTime = hour.Score, r.Timestamp
+		}
+	}
+
+	risk.Level = "low"
+	if risk.Score >= rd.HighScore {
+		risk.Level = "high"
+	} else if risk.Score >= rd.AlertScore {
+		risk.Level = "moderate"
+	}
+	return risk
+}
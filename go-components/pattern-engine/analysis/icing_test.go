@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestAssessRoadIcing tests a winter evening in Oslo turning icy as the sky clears after rain
+func TestAssessRoadIcing(t *testing.T) {
+	now := time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC) // After dark
+	readings := []models.WeatherPoint{
+		{Timestamp: now.Add(-time.Hour), Temperature: 6, PrecipitationMm: 0.25, Humidity: 70, CloudCover: 100},
+		{Timestamp: now, Temperature: 4, Humidity: 70, CloudCover: 100},
+		{Timestamp: now.Add(time.Hour), Temperature: 1, PrecipitationMm: 0.25, Humidity: 70, CloudCover: 100},
+		{Timestamp: now.Add(2 * time.Hour), Temperature: 0.5, Humidity: 70},                     // Clear sky cools the road by 2°C
+		{Timestamp: now.Add(3 * time.Hour), Temperature: 0, PrecipitationMm: 0.6, Humidity: 95}, // Wet and freezing
+	}
+	detector := NewRoadIcingDetector()
+	detector.now = func() time.Time { return now }
+	locationData := &models.LocationData{Readings: readings, Coordinates: models.Coordinates{Latitude: 59.91, Longitude: 10.75}}
+
+	risk := detector.AssessRoadIcing(locationData)
+	if risk == nil || len(risk.Hours) != 4 {
+		t.Fatalf("Expected a score for each reading from now on, got %+v", risk)
+	}
+	for i, want := range []struct {
+		surface, recent, score float64
+	}{
+		{4, 0.25, 0},
+		{1, 0.5, 1.0 / 3},
+		{-1.5, 0.5, 0.5},
+		{-2, 1.1, 1},
+	} {
+		hour := risk.Hours[i]
+		if math.Abs(hour.SurfaceTemperature-want.surface) > 1e-9 || math.Abs(hour.RecentPrecipitation-want.recent) > 1e-9 ||
+			math.Abs(hour.Score-want.score) > 1e-9 {
+			t.Errorf("Expected a %.1f°C road after %.2f mm to score %.2f, got %+v", want.surface, want.recent, want.score, hour)
+		}
+	}
+	if risk.Level != "high" || !risk.PeakTime.Equal(now.Add(3*time.Hour)) || !risk.Onset.Equal(now.Add(2*time.Hour)) ||
+		risk.LeadTimeHours != 2 {
+		t.Errorf("Expected a high risk from 2 hours on, got %+v", risk)
+	}
+
+	detector.now = func() time.Time { return now.Add(48 * time.Hour) }
+	if risk := detector.AssessRoadIcing(locationData); risk != nil {
+		t.Errorf("Expected no risk for a history, got %+v", risk)
+	}
+}
@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+
+	"pattern-engine/models"
+)
+
+// ErrNoNearbyLocations is returned by Interpolate when no analyzed location is close enough to
+// the point to estimate its weather
+var ErrNoNearbyLocations = errors.New("no analyzed location near the point")
+
+// Interpolator estimates the weather at any point from the analyses of the locations around it,
+// by inverse-distance weighting: each location counts in proportion to 1/distance^Power
+type Interpolator struct {
+	Power         float64 // Exponent of the distance; higher values favor the nearest locations
+	MaxDistanceKm float64 // Locations farther from the point are not used (0 = no limit)
+}
+
+// NewInterpolator creates a new interpolator with default settings
+func NewInterpolator() *Interpolator {
+	return &Interpolator{Power: 2, MaxDistanceKm: 300}
+}
+
+// Interpolate estimates the current temperature and pressure at a point, and the trend of each
+// variable: the trend most of the weight has, with the weighted rate of change. Analyses without
+// coordinates, such as those written before coordinates were recorded, are not used. At an
+// analyzed location the estimate is that location's own.
+func (ip *Interpolator) Interpolate(analyses []models.AnalysisResult, at models.Coordinates) (models.Interpolation, error) {
+	interpolation := models.Interpolation{
+		Coordinates: at,
+		Method:      "inverse_distance",
+		Trends:      []models.InterpolatedTrend{},
+	}
+
+	type source struct {
+		analysis *models.AnalysisResult
+		distance float64
+		weight   float64
+	}
+	var sources []source
+	for i := range analyses {
+		a := &analyses[i]
+		if a.Coordinates.Latitude == 0 && a.Coordinates.Longitude == 0 {
+			continue
+		}
+		d := distanceKm(a.Coordinates, at)
+		if ip.MaxDistanceKm > 0 && d > ip.MaxDistanceKm {
+			continue
+		}
+		sources = append(sources, source{analysis: a, distance: d})
+	}
+	if len(sources) == 0 {
+		return interpolation, ErrNoNearbyLocations
+	}
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].distance < sources[j].distance })
+
+	// A point within a meter of a location is that location
+	if sources[0].distance < 0.001 {
+		sources = sources[:1]
+	}
+	var total float64
+	for i := range sources {
+		sources[i].weight = 1 / math.Pow(max(sources[i].distance, 0.001), ip.Power)
+		total += sources[i].weight
+	}
+
+	var variables []string
+	rates := map[string]float64{}            // Weighted rate of change of each variable
+	weights := map[string]float64{}          // Weight of the locations with a trend of each variable
+	votes := map[string]map[string]float64{} // Weight behind each trend of each variable
+	for i := range sources {
+		s := &sources[i]
+		s.weight /= total
+		interpolation.Temperature += s.weight * s.analysis.WeatherSummary.CurrentTemp
+		interpolation.Pressure += s.weight * s.analysis.WeatherSummary.CurrentPressure
+		interpolation.Sources = append(interpolation.Sources, models.InterpolationSource{
+			Location:   s.analysis.Location,
+			DistanceKm: s.distance,
+			Weight:     s.weight,
+		})
+
+		for _, trend := range s.analysis.Trends {
+			if votes[trend.Variable] == nil {
+				variables = append(variables, trend.Variable)
+				votes[trend.Variable] = map[string]float64{}
+			}
+			rates[trend.Variable] += s.weight * trend.ChangeRate
+			weights[trend.Variable] += s.weight
+			votes[trend.Variable][trend.Trend] += s.weight
+		}
+	}
+
+	for _, variable := range variables {
+		estimate := models.InterpolatedTrend{Variable: variable, ChangeRate: rates[variable] / weights[variable]}
+		// Sorted so that a tie goes the same way every time
+		for _, trend := range slices.Sorted(maps.Keys(votes[variable])) {
+			if share := votes[variable][trend] / weights[variable]; share > estimate.Agreement {
+				estimate.Trend, estimate.Agreement = trend, share
+			}
+		}
+		interpolation.Trends = append(interpolation.Trends, estimate)
+	}
+	return interpolation, nil
+}
@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"pattern-engine/models"
+)
+
+// interpolationAnalysis returns an analysis of a location with a current temperature and pressure
+// and a temperature trend
+func interpolationAnalysis(name string, lat, lon, temperature, pressure float64, trend string, rate float64) models.AnalysisResult {
+	return models.AnalysisResult{
+		Location:       name,
+		Coordinates:    models.Coordinates{Latitude: lat, Longitude: lon},
+		WeatherSummary: models.WeatherSummary{CurrentTemp: temperature, CurrentPressure: pressure},
+		Trends:         []models.Trend{{Variable: "temperature", Trend: trend, ChangeRate: rate}},
+	}
+}
+
+// TestInterpolate tests that a point between locations is estimated from them, the nearest
+// counting most, and that far and unplaced analyses are not used
+func TestInterpolate(t *testing.T) {
+	analyses := []models.AnalysisResult{
+		interpolationAnalysis("West", 60, 10, 10, 1000, "rising", 0.4),
+		interpolationAnalysis("East", 60, 11, 14, 1010, "falling", -0.2),
+		interpolationAnalysis("Far", 40, 10, 30, 1030, "rising", 2),
+		{Location: "Unplaced", WeatherSummary: models.WeatherSummary{CurrentTemp: 50}},
+	}
+
+	midpoint, err := NewInterpolator().Interpolate(analyses, models.Coordinates{Latitude: 60, Longitude: 10.5})
+	if err != nil {
+		t.Fatalf("Interpolate failed: %v", err)
+	}
+	if len(midpoint.Sources) != 2 || math.Abs(midpoint.Temperature-12) > 0.01 || math.Abs(midpoint.Pressure-1005) > 0.05 {
+		t.Errorf("Expected the average of West and East halfway between them, got %+v", midpoint)
+	}
+
+	nearWest, err := NewInterpolator().Interpolate(analyses, models.Coordinates{Latitude: 60, Longitude: 10.25})
+	if err != nil {
+		t.Fatalf("Interpolate failed: %v", err)
+	}
+	if nearWest.Sources[0].Location != "West" || nearWest.Sources[0].Weight < 0.85 || nearWest.Temperature > 11 {
+		t.Errorf("Expected West to dominate a point a quarter of the way to East, got %+v", nearWest)
+	}
+	if len(nearWest.Trends) != 1 || nearWest.Trends[0].Trend != "rising" || nearWest.Trends[0].Agreement < 0.85 ||
+		nearWest.Trends[0].ChangeRate < 0.3 {
+		t.Errorf("Expected West's rising temperature, got %+v", nearWest.Trends)
+	}
+
+	atEast, err := NewInterpolator().Interpolate(analyses, models.Coordinates{Latitude: 60, Longitude: 11})
+	if err != nil || len(atEast.Sources) != 1 || atEast.Temperature != 14 || atEast.Trends[0].Trend != "falling" {
+		t.Errorf("Expected East's own weather at East, got %+v (err: %v)", atEast, err)
+	}
+
+	if _, err := NewInterpolator().Interpolate(analyses, models.Coordinates{Latitude: -30, Longitude: 150}); !errors.Is(err, ErrNoNearbyLocations) {
+		t.Errorf("Expected ErrNoNearbyLocations far from every location, got %v", err)
+	}
+}
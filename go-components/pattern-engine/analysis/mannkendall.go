@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"pattern-engine/models"
+)
+
+// Trend methods
+const (
+	TrendMethodLeastSquares = "least_squares" // Least-squares slope with a t-test
+	TrendMethodMannKendall  = "mann_kendall"  // Sen's slope with the Mann-Kendall test, robust to outliers
+)
+
+// maxKendallReadings is the most readings the Mann-Kendall test compares pairwise; longer series
+// are thinned evenly to this many
+const maxKendallReadings = 1000
+
+// kendallFit is the non-parametric trend of a variable
+type kendallFit struct {
+	slope        float64 // Sen's slope: the median of the slopes between every pair of readings, per hour
+	lower, upper float64 // Confidence interval of the slope
+	pValue       float64 // Two-sided p-value of the Mann-Kendall test
+}
+
+// mannKendall tests readings in chronological order for a monotonic trend, with the normal
+// approximation of the Mann-Kendall statistic corrected for ties, and estimates its slope and
+// confidence interval at level confidence by Sen's method
+func mannKendall(readings []models.WeatherPoint, value func(models.WeatherPoint) float64, confidence float64) kendallFit {
+	if n := len(readings); n > maxKendallReadings {
+		thinned := make([]models.WeatherPoint, maxKendallReadings)
+		for i := range thinned {
+			thinned[i] = readings[i*(n-1)/(maxKendallReadings-1)]
+		}
+		readings = thinned
+	}
+	n := len(readings)
+	if n < 3 {
+		return kendallFit{pValue: 1}
+	}
+
+	var s float64
+	slopes := make([]float64, 0, n*(n-1)/2)
+	for i := range readings {
+		for j := i + 1; j < n; j++ {
+			dy := value(readings[j]) - value(readings[i])
+			if dy > 0 {
+				s++
+			} else if dy < 0 {
+				s--
+			}
+			if dx := readings[j].Timestamp.Sub(readings[i].Timestamp).Hours(); dx > 0 {
+				slopes = append(slopes, dy/dx)
+			}
+		}
+	}
+
+	// Variance of S, less the contribution of groups of tied values
+	values := make([]float64, n)
+	for i, r := range readings {
+		values[i] = value(r)
+	}
+	sort.Float64s(values)
+	nf := float64(n)
+	variance := nf * (nf - 1) * (2*nf + 5)
+	for i := 0; i < n; {
+		j := i
+		for j < n && values[j] == values[i] {
+			j++
+		}
+		t := float64(j - i)
+		variance -= t * (t - 1) * (2*t + 5)
+		i = j
+	}
+	variance /= 18
+
+	fit := kendallFit{pValue: 1}
+	if variance > 0 {
+		z := 0.0 // Continuity-corrected
+		if s > 0 {
+			z = (s - 1) / math.Sqrt(variance)
+		} else if s < 0 {
+			z = (s + 1) / math.Sqrt(variance)
+		}
+		fit.pValue = math.Erfc(math.Abs(z) / math.Sqrt2)
+	}
+	if len(slopes) == 0 {
+		return fit
+	}
+
+	// The interval's bounds are the slopes ranked C/2 either side of the median, where C is the
+	// spread of S at the confidence level
+	sort.Float64s(slopes)
+	fit.slope = percentile(slopes, 0.5)
+	c := math.Sqrt2 * math.Erfinv(confidence) * math.Sqrt(variance)
+	m := float64(len(slopes))
+	lower := int(math.Max(0, math.Floor((m-c)/2)-1))
+	upper := int(math.Min(m-1, math.Ceil((m+c)/2)))
+	fit.lower, fit.upper = slopes[lower], slopes[upper]
+	return fit
+}
@@ -0,0 +1,62 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// risingWithOutlier returns hourly readings with temperature rising 0.5 °C/h and a faulty last
+// reading far below the rest
+func risingWithOutlier() []models.WeatherPoint {
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 12 {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: 10 + 0.5*float64(i) + 0.1*math.Sin(float64(i)),
+			Pressure:    1013,
+		})
+	}
+	readings[11].Temperature = -30
+	return readings
+}
+
+// TestMannKendallRobustToOutliers tests that Sen's slope follows the trend despite an outlier
+// that hides it from least squares
+func TestMannKendallRobustToOutliers(t *testing.T) {
+	leastSquares := NewTrendAnalyzer().AnalyzeTrends(&models.LocationData{Readings: risingWithOutlier()})
+	if leastSquares[0].Trend == "rising" {
+		t.Fatalf("Expected the outlier to hide the trend from least squares, got %+v", leastSquares[0])
+	}
+
+	analyzer := NewTrendAnalyzer()
+	analyzer.Method = TrendMethodMannKendall
+	trends := analyzer.AnalyzeTrends(&models.LocationData{Readings: risingWithOutlier()})
+	temp := trends[0]
+	if temp.Method != TrendMethodMannKendall || temp.Trend != "rising" || math.Abs(temp.ChangeRate-0.5) > 0.05 {
+		t.Errorf("Expected a rising Sen's slope of 0.5, got %+v", temp)
+	}
+	if temp.PValue > 0.01 || temp.CiLower > temp.ChangeRate || temp.CiUpper < temp.ChangeRate {
+		t.Errorf("Expected a significant slope inside its interval, got %+v", temp)
+	}
+	if pressure := trends[1]; pressure.Trend != "stable" || pressure.PValue != 1 || pressure.ChangeRate != 0 {
+		t.Errorf("Expected constant pressure to be stable, got %+v", pressure)
+	}
+}
+
+// TestMannKendallStatistic tests the p-value against a hand-computed Mann-Kendall test
+func TestMannKendallStatistic(t *testing.T) {
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i, v := range []float64{1, 3, 2, 4, 5} {
+		readings = append(readings, models.WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Humidity: v})
+	}
+	// S = 8 of 10 pairs, Var(S) = 5*4*15/18 = 16.67, Z = 7/4.08 = 1.715
+	fit := mannKendall(readings, func(r models.WeatherPoint) float64 { return r.Humidity }, 0.95)
+	if math.Abs(fit.pValue-0.0864) > 1e-3 || fit.slope != 1 {
+		t.Errorf("Expected p-value 0.0864 and slope 1, got %+v", fit)
+	}
+}
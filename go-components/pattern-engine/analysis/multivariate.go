@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"pattern-engine/models"
+)
+
+// multivariateVariables are the variables whose combination is checked
+var multivariateVariables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }},
+	{"pressure", func(r models.WeatherPoint) float64 { return r.Pressure }},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }},
+	{"wind_speed", func(r models.WeatherPoint) float64 { return r.WindSpeed }},
+	{"cloud_cover", func(r models.WeatherPoint) float64 { return r.CloudCover }},
+	{"precipitation_mm", func(r models.WeatherPoint) float64 { return r.PrecipitationMm }},
+}
+
+// NewMultivariateDetector creates a new multivariate anomaly detector with default settings
+func NewMultivariateDetector() *MultivariateDetector {
+	return &MultivariateDetector{DefaultThresholds().Multivariate}
+}
+
+// DetectMultivariateAnomalies finds readings whose combination of variables is unusual given how
+// the variables vary together, such as saturated air under clear skies, even when no variable is
+// unusual by itself. Each reading's Mahalanobis distance from the mean of the readings is tested
+// against the chi-square distribution at significance level Alpha, and split into the
+// contributions of the variables. Variables that do not vary are left out.
+func (md *MultivariateDetector) DetectMultivariateAnomalies(locationData *models.LocationData) []models.MultivariateAnomaly {
+	readings := locationData.Readings
+	if len(readings) < md.MinReadings {
+		return []models.MultivariateAnomaly{}
+	}
+
+	// Deviations from the mean of each variable that varies
+	var names []string
+	var columns [][]float64
+	for _, variable := range multivariateVariables {
+		column := make([]float64, len(readings))
+		var mean float64
+		for i, r := range readings {
+			column[i] = variable.value(r)
+			mean += column[i] / float64(len(readings))
+		}
+		var variance float64
+		for i := range column {
+			column[i] -= mean
+			variance += column[i] * column[i]
+		}
+		if variance/float64(len(readings)) > 1e-9 {
+			names = append(names, variable.name)
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) < 2 {
+		return []models.MultivariateAnomaly{} // A single variable is the anomaly detector's job
+	}
+
+	// Sample covariance matrix and its inverse
+	p := len(columns)
+	covariance := make([][]float64, p)
+	for j := range covariance {
+		covariance[j] = make([]float64, p)
+		for k := range covariance[j] {
+			for i := range readings {
+				covariance[j][k] += columns[j][i] * columns[k][i]
+			}
+			covariance[j][k] /= float64(len(readings) - 1)
+		}
+	}
+	inverse, ok := invert(covariance)
+	if !ok {
+		return []models.MultivariateAnomaly{} // Variables that move in lockstep
+	}
+
+	anomalies := []models.MultivariateAnomaly{}
+	deviation := make([]float64, p)
+	for i, r := range readings {
+		for j := range columns {
+			deviation[j] = columns[j][i]
+		}
+
+		// D² = dᵀ Σ⁻¹ d, of which variable j contributes d_j (Σ⁻¹ d)_j
+		contributions := make([]float64, p)
+		var squared float64
+		for j := range deviation {
+			for k := range deviation {
+				contributions[j] += deviation[j] * inverse[j][k] * deviation[k]
+			}
+			squared += contributions[j]
+		}
+		pValue := chiSquareSurvival(squared, float64(p))
+		if pValue >= md.Alpha {
+			continue
+		}
+
+		anomaly := models.MultivariateAnomaly{
+			Timestamp: r.Timestamp,
+			Distance:  math.Sqrt(math.Max(0, squared)),
+			PValue:    pValue,
+			Severity:  "moderate",
+		}
+		if pValue < md.Alpha/10 {
+			anomaly.Severity = "high"
+		}
+		for j, name := range names {
+			anomaly.Contributions = append(anomaly.Contributions, models.Contribution{
+				Variable: name,
+				Value:    multivariateValue(r, name),
+				Share:    contributions[j] / squared,
+			})
+		}
+		sort.Slice(anomaly.Contributions, func(a, b int) bool {
+			return anomaly.Contributions[a].Share > anomaly.Contributions[b].Share
+		})
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies
+}
+
+// multivariateValue returns the value of one of multivariateVariables in a reading
+func multivariateValue(r models.WeatherPoint, name string) float64 {
+	for _, variable := range multivariateVariables {
+		if variable.name == name {
+			return variable.value(r)
+		}
+	}
+	return 0
+}
+
+// invert inverts a square matrix by Gauss-Jordan elimination with partial pivoting; it fails when
+// the matrix is (nearly) singular
+func invert(m [][]float64) ([][]float64, bool) {
+	n := len(m)
+	a := make([][]float64, n) // m augmented with the identity
+	for i := range a {
+		a[i] = make([]float64, 2*n)
+		copy(a[i], m[i])
+		a[i][n+i] = 1
+	}
+
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12*math.Max(1, math.Abs(m[col][col])) {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		scale := a[col][col]
+		for k := range a[col] {
+			a[col][k] /= scale
+		}
+		for row := range n {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			f := a[row][col]
+			for k := range a[row] {
+				a[row][k] -= f * a[col][k]
+			}
+		}
+	}
+
+	inverse := make([][]float64, n)
+	for i := range inverse {
+		inverse[i] = a[i][n:]
+	}
+	return inverse, true
+}
@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// correlatedReadings returns hourly readings in which cloud cover and precipitation follow
+// humidity and pressure falls as humidity rises
+func correlatedReadings(hours int) []models.WeatherPoint {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range hours {
+		x := float64(i)
+		humidity := 60 + 20*math.Sin(x/5)
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:       start.Add(time.Duration(i) * time.Hour),
+			Temperature:     15 + 3*math.Sin(x/7),
+			Pressure:        1013 - (humidity-60)/2 + math.Sin(x*0.9),
+			Humidity:        humidity,
+			WindSpeed:       4 + math.Sin(x/3),
+			CloudCover:      humidity + 3*math.Sin(x*1.3),
+			PrecipitationMm: math.Max(0, (humidity-70)/5) + 0.1*math.Sin(x*2.1),
+		})
+	}
+	return readings
+}
+
+// TestDetectMultivariateAnomalies tests that an inconsistent combination of ordinary values is
+// found, with the variables behind it
+func TestDetectMultivariateAnomalies(t *testing.T) {
+	readings := correlatedReadings(96)
+	if anomalies := NewMultivariateDetector().DetectMultivariateAnomalies(&models.LocationData{Readings: readings}); len(anomalies) != 0 {
+		t.Fatalf("Expected consistent readings to have no anomalies, got %+v", anomalies)
+	}
+
+	// Humid and wet, but clear skies and high pressure: each within the usual range
+	odd := &readings[50]
+	odd.Humidity, odd.CloudCover, odd.PrecipitationMm, odd.Pressure = 78, 45, 1.5, 1020
+
+	anomalies := NewMultivariateDetector().DetectMultivariateAnomalies(&models.LocationData{Readings: readings})
+	if len(anomalies) != 1 || !anomalies[0].Timestamp.Equal(odd.Timestamp) || anomalies[0].Severity != "high" {
+		t.Fatalf("Expected only the inconsistent reading as a high anomaly, got %+v", anomalies)
+	}
+
+	contributions := anomalies[0].Contributions
+	var total float64
+	for _, c := range contributions {
+		total += c.Share
+	}
+	if math.Abs(total-1) > 1e-9 || len(contributions) != 6 {
+		t.Errorf("Expected shares of all 6 variables summing to 1, got %+v", contributions)
+	}
+	for _, c := range contributions[3:] {
+		if c.Variable == "humidity" || c.Variable == "cloud_cover" || c.Variable == "pressure" || c.Share > 0.05 {
+			t.Errorf("Expected humidity, cloud cover and pressure to contribute most, got %+v", contributions)
+			break
+		}
+	}
+}
+
+// TestDetectMultivariateAnomaliesConstant tests that constant variables are left out
+func TestDetectMultivariateAnomaliesConstant(t *testing.T) {
+	readings := correlatedReadings(48)
+	for i := range readings {
+		readings[i].WindSpeed, readings[i].CloudCover = 5, 0
+	}
+	readings[20].Pressure += 12 // Off the usual relation with humidity
+
+	anomalies := NewMultivariateDetector().DetectMultivariateAnomalies(&models.LocationData{Readings: readings})
+	if len(anomalies) != 1 || len(anomalies[0].Contributions) != 4 || anomalies[0].Contributions[0].Variable != "pressure" {
+		t.Errorf("Expected a pressure anomaly among 4 varying variables, got %+v", anomalies)
+	}
+}
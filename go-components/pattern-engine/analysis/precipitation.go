@@ -0,0 +1,124 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// precipitationWindows are the lengths (hours) of the windows the wettest accumulation is found over
+var precipitationWindows = []int{1, 6, 24}
+
+// NewPrecipitationAccumulator creates a new precipitation accumulator with default settings
+func NewPrecipitationAccumulator() *PrecipitationAccumulator {
+	return &PrecipitationAccumulator{PrecipitationThresholds: DefaultThresholds().Precipitation}
+}
+
+// AnalyzePrecipitation accumulates the precipitation of the readings, each reading's amount
+// falling in the hour after it: the total, the total expected from the current conditions (the
+// latest reading at or before now) to the last reading, the wettest 1, 6 and 24 hours, the hours
+// of each intensity, and the longest dry and wet streaks. An hour is wet from WetHour mm;
+// readings more than an hour apart end a streak. It returns nil without readings.
+func (pa *PrecipitationAccumulator) AnalyzePrecipitation(locationData *models.LocationData) *models.PrecipitationAnalysis {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	now := time.Now()
+	if pa.now != nil {
+		now = pa.now()
+	}
+	current := max(sort.Search(len(readings), func(i int) bool { return readings[i].Timestamp.After(now) })-1, 0)
+	last := readings[len(readings)-1].Timestamp
+	forecast := !last.Before(now) // A history has nothing to expect
+
+	result := &models.PrecipitationAnalysis{}
+	for i, r := range readings {
+		result.Total += r.PrecipitationMm
+		if forecast && i >= current {
+			result.ExpectedTotal += r.PrecipitationMm
+		}
+		if r.PrecipitationMm > result.PeakRate {
+			result.PeakRate, result.PeakTime = r.PrecipitationMm, r.Timestamp
+		}
+		switch pa.intensity(r.PrecipitationMm) {
+		case "drizzle":
+			result.IntensityHours.Drizzle++
+		case "moderate":
+			result.IntensityHours.Moderate++
+		case "heavy":
+			result.IntensityHours.Heavy++
+		}
+	}
+	if forecast {
+		result.HorizonHours = last.Add(time.Hour).Sub(readings[current].Timestamp).Hours()
+	}
+	result.PeakIntensity = pa.intensity(result.PeakRate)
+
+	for _, hours := range precipitationWindows {
+		result.Windows = append(result.Windows, wettestWindow(readings, hours))
+	}
+	result.LongestDry = pa.longestStreak(readings, false)
+	result.LongestWet = pa.longestStreak(readings, true)
+	return result
+}
+
+// intensity returns the intensity class of an hourly amount: "none" below WetHour, "drizzle"
+// below ModerateRate, "moderate" below HeavyRate, and "heavy"
+func (pa *PrecipitationAccumulator) intensity(mm float64) string {
+	switch {
+	case mm < pa.WetHour:
+		return "none"
+	case mm < pa.ModerateRate:
+		return "drizzle"
+	case mm < pa.HeavyRate:
+		return "moderate"
+	}
+	return "heavy"
+}
+
+// wettestWindow returns the window of the given hours, starting at a reading, with the most
+// precipitation of readings in chronological order
+func wettestWindow(readings []models.WeatherPoint, hours int) models.PrecipitationWindow {
+	wettest := models.PrecipitationWindow{Hours: hours, Start: readings[0].Timestamp}
+	var total float64
+	end := 0 // First reading after the window
+	for start := range readings {
+		for end < len(readings) && readings[end].Timestamp.Before(readings[start].Timestamp.Add(time.Duration(hours)*time.Hour)) {
+			total += readings[end].PrecipitationMm
+			end++
+		}
+		if total > wettest.Total {
+			wettest.Total, wettest.Start = total, readings[start].Timestamp
+		}
+		total -= readings[start].PrecipitationMm
+	}
+	return wettest
+}
+
+// longestStreak returns the longest run of wet (or dry) hourly readings in chronological order,
+// or nil without one
+func (pa *PrecipitationAccumulator) longestStreak(readings []models.WeatherPoint, wet bool) *models.PrecipitationStreak {
+	var longest *models.PrecipitationStreak
+	start := -1 // First reading of the current streak
+	for i := 0; i <= len(readings); i++ {
+		matches := i < len(readings) && (readings[i].PrecipitationMm >= pa.WetHour) == wet
+		if start >= 0 && (!matches || readings[i].Timestamp.Sub(readings[i-1].Timestamp) > time.Hour) {
+			streak := models.PrecipitationStreak{Start: readings[start].Timestamp, End: readings[i-1].Timestamp.Add(time.Hour)}
+			streak.Hours = streak.End.Sub(streak.Start).Hours()
+			if longest == nil || streak.Hours > longest.Hours {
+				longest = &streak
+			}
+			start = -1
+		}
+		if matches && start < 0 {
+			start = i
+		}
+	}
+	return longest
+}
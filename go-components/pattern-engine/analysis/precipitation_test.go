@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestAnalyzePrecipitation tests the totals, wettest windows, intensities and streaks of a shower
+// passing over in the coming hours
+func TestAnalyzePrecipitation(t *testing.T) {
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	now := start.Add(3 * time.Hour)
+	var readings []models.WeatherPoint
+	for i, mm := range []float64{0, 0.4, 0, 0.2, 1, 3, 8, 0.5, 0.3, 0, 0, 0} {
+		readings = append(readings, models.WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), PrecipitationMm: mm})
+	}
+	accumulator := NewPrecipitationAccumulator()
+	accumulator.now = func() time.Time { return now }
+
+	p := accumulator.AnalyzePrecipitation(&models.LocationData{Readings: readings})
+	if math.Abs(p.Total-13.4) > 1e-9 || math.Abs(p.ExpectedTotal-13) > 1e-9 || p.HorizonHours != 9 {
+		t.Errorf("Expected 13.4 mm, 13 mm of it over the coming 9 hours, got %+v", p)
+	}
+	if p.PeakRate != 8 || p.PeakIntensity != "heavy" || !p.PeakTime.Equal(start.Add(6*time.Hour)) ||
+		p.IntensityHours != (models.PrecipitationIntensity{Drizzle: 5, Moderate: 1, Heavy: 1}) {
+		t.Errorf("Expected a heavy peak of 8 mm/h among 5 hours of drizzle, got %+v", p)
+	}
+
+	for i, want := range []struct {
+		start time.Time
+		total float64
+	}{
+		{start.Add(6 * time.Hour), 8},
+		{start.Add(3 * time.Hour), 13},
+		{start, 13.4},
+	} {
+		if w := p.Windows[i]; !w.Start.Equal(want.start) || math.Abs(w.Total-want.total) > 1e-9 {
+			t.Errorf("Expected the wettest %d hours to hold %.1f mm from %v, got %+v", w.Hours, want.total, want.start, w)
+		}
+	}
+
+	if wet := p.LongestWet; wet == nil || wet.Hours != 6 || !wet.Start.Equal(now) {
+		t.Errorf("Expected 6 wet hours from now, got %+v", wet)
+	}
+	if dry := p.LongestDry; dry == nil || dry.Hours != 3 || !dry.End.Equal(start.Add(12*time.Hour)) {
+		t.Errorf("Expected the last 3 hours dry, got %+v", dry)
+	}
+
+	accumulator.now = func() time.Time { return now.Add(48 * time.Hour) }
+	if p := accumulator.AnalyzePrecipitation(&models.LocationData{Readings: readings}); p.ExpectedTotal != 0 || p.HorizonHours != 0 {
+		t.Errorf("Expected nothing to come in a history, got %+v", p)
+	}
+}
@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// stuckVariables are the variables checked for values stuck at one reading; precipitation, cloud
+// cover and the like rest at 0 or 100 for hours as a matter of course
+var stuckVariables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }},
+	{"pressure", func(r models.WeatherPoint) float64 { return r.Pressure }},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }},
+	{"wind_speed", func(r models.WeatherPoint) float64 { return r.WindSpeed }},
+}
+
+// NewDataQualityChecker creates a new data quality checker with default settings
+func NewDataQualityChecker() *DataQualityChecker {
+	return &DataQualityChecker{DataQualityThresholds: DefaultThresholds().DataQuality}
+}
+
+// CheckQuality reports the quality of the readings: the cadence, the median interval between
+// readings; gaps, intervals longer than GapFactor cadences, with the readings missing from them;
+// timestamps with more than one reading; runs of at least StuckReadings readings with the same
+// value of a variable; and how old the latest reading is, stale from StaleAfter. Each problem
+// found is also described in Warnings. It returns nil without readings.
+func (dq *DataQualityChecker) CheckQuality(locationData *models.LocationData) *models.DataQuality {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	now := time.Now()
+	if dq.now != nil {
+		now = dq.now()
+	}
+
+	quality := &models.DataQuality{Readings: len(readings), Coverage: 1}
+	var intervals []time.Duration
+	for i := 1; i < len(readings); i++ {
+		if interval := readings[i].Timestamp.Sub(readings[i-1].Timestamp); interval > 0 {
+			intervals = append(intervals, interval)
+		} else if len(quality.Duplicates) == 0 || !quality.Duplicates[len(quality.Duplicates)-1].Equal(readings[i].Timestamp) {
+			quality.Duplicates = append(quality.Duplicates, readings[i].Timestamp)
+		}
+	}
+
+	if len(intervals) > 0 {
+		sorted := slices.Clone(intervals)
+		slices.Sort(sorted)
+		cadence := sorted[len(sorted)/2]
+		quality.CadenceMinutes = cadence.Minutes()
+
+		missing := 0
+		for i := 1; i < len(readings); i++ {
+			interval := readings[i].Timestamp.Sub(readings[i-1].Timestamp)
+			if float64(interval) <= dq.GapFactor*float64(cadence) {
+				continue
+			}
+			gap := models.DataGap{
+				Start:   readings[i-1].Timestamp,
+				End:     readings[i].Timestamp,
+				Missing: int(math.Round(float64(interval)/float64(cadence))) - 1,
+			}
+			quality.Gaps = append(quality.Gaps, gap)
+			missing += gap.Missing
+		}
+		distinct := len(intervals) + 1
+		quality.Coverage = float64(distinct) / float64(distinct+missing)
+	}
+
+	for _, v := range stuckVariables {
+		start := 0
+		for i := 1; i <= len(readings); i++ {
+			if i < len(readings) && v.value(readings[i]) == v.value(readings[start]) {
+				continue
+			}
+			if i-start >= dq.StuckReadings {
+				quality.Stuck = append(quality.Stuck, models.StuckValue{
+					Variable: v.name,
+					Value:    v.value(readings[start]),
+					Start:    readings[start].Timestamp,
+					End:      readings[i-1].Timestamp,
+					Readings: i - start,
+				})
+			}
+			start = i
+		}
+	}
+
+	latest := readings[len(readings)-1].Timestamp
+	quality.LatestReading = latest
+	if age := now.Sub(latest); age > 0 {
+		quality.AgeHours = age.Hours()
+	}
+	quality.Stale = now.Sub(latest) > dq.StaleAfter
+
+	quality.Warnings = QualityWarnings(quality)
+	return quality
+}
+
+// QualityWarnings describes the problems of a quality report
+func QualityWarnings(quality *models.DataQuality) []string {
+	var warnings []string
+	if len(quality.Gaps) > 0 {
+		missing := 0
+		for _, gap := range quality.Gaps {
+			missing += gap.Missing
+		}
+		warnings = append(warnings, fmt.Sprintf("%d gaps missing %d readings (%.0f%% coverage)", len(quality.Gaps), missing, quality.Coverage*100))
+	}
+	if len(quality.Duplicates) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d timestamps with duplicate readings", len(quality.Duplicates)))
+	}
+	for _, stuck := range quality.Stuck {
+		warnings = append(warnings, fmt.Sprintf("%s stuck at %g for %d readings from %s", stuck.Variable, stuck.Value, stuck.Readings,
+			stuck.Start.Format(time.RFC3339)))
+	}
+	if quality.Stale {
+		warnings = append(warnings, fmt.Sprintf("latest reading is %.1f hours old", quality.AgeHours))
+	}
+	return warnings
+}
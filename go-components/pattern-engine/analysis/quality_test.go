@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestCheckQuality tests that gaps, duplicates, stuck values and staleness are found in hourly readings
+func TestCheckQuality(t *testing.T) {
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for _, hour := range []int{0, 1, 2, 2, 3, 7, 8, 9, 10, 11, 12, 13, 14} { // 4:00 to 6:00 missing, 2:00 twice
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(hour) * time.Hour),
+			Temperature: float64(hour),
+			Pressure:    1000 + float64(hour),
+			Humidity:    min(80, 70+float64(hour)), // 80 from 10:00 on
+			WindSpeed:   float64(hour % 3),
+		})
+	}
+	checker := NewDataQualityChecker()
+	checker.now = func() time.Time { return start.Add(20 * time.Hour) }
+
+	quality := checker.CheckQuality(&models.LocationData{Readings: readings})
+	if quality == nil || quality.Readings != 13 || quality.CadenceMinutes != 60 {
+		t.Fatalf("Expected 13 hourly readings, got %+v", quality)
+	}
+	if len(quality.Gaps) != 1 || quality.Gaps[0].Missing != 3 || !quality.Gaps[0].Start.Equal(start.Add(3*time.Hour)) {
+		t.Errorf("Expected a gap of 3 readings after 3:00, got %+v", quality.Gaps)
+	}
+	if quality.Coverage != 12.0/15 {
+		t.Errorf("Expected 12 of 15 readings, got a coverage of %v", quality.Coverage)
+	}
+	if len(quality.Duplicates) != 1 || !quality.Duplicates[0].Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Expected 2:00 duplicated, got %v", quality.Duplicates)
+	}
+	if len(quality.Stuck) != 0 {
+		t.Errorf("Expected no stuck values at 6 readings, got %+v", quality.Stuck)
+	}
+	if !quality.Stale || quality.AgeHours != 6 || len(quality.Warnings) != 3 {
+		t.Errorf("Expected stale readings and three warnings, got %+v", quality)
+	}
+
+	checker.StuckReadings = 5
+	checker.now = func() time.Time { return start.Add(15 * time.Hour) }
+	quality = checker.CheckQuality(&models.LocationData{Readings: readings})
+	if len(quality.Stuck) != 1 || quality.Stuck[0].Variable != "humidity" || quality.Stuck[0].Value != 80 || quality.Stuck[0].Readings != 5 {
+		t.Errorf("Expected humidity stuck at 80 for 5 readings, got %+v", quality.Stuck)
+	}
+	if quality.Stale {
+		t.Errorf("Expected current readings an hour old, got %+v", quality)
+	}
+}
+
+// TestCheckQualitySingleReading tests that one reading has full coverage and no cadence
+func TestCheckQualitySingleReading(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	checker := NewDataQualityChecker()
+	checker.now = func() time.Time { return now }
+
+	quality := checker.CheckQuality(&models.LocationData{Readings: []models.WeatherPoint{{Timestamp: now.Add(time.Hour)}}})
+	if quality.Coverage != 1 || quality.CadenceMinutes != 0 || quality.Stale || quality.AgeHours != 0 || quality.Warnings != nil {
+		t.Errorf("Unexpected quality of a reading ahead: %+v", quality)
+	}
+	if checker.CheckQuality(&models.LocationData{}) != nil {
+		t.Error("Expected no quality report without readings")
+	}
+}
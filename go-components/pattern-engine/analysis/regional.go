@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// NewRegionalAnalyzer creates a new regional analyzer with default settings
+func NewRegionalAnalyzer() *RegionalAnalyzer {
+	return &RegionalAnalyzer{DefaultThresholds().Regional}
+}
+
+// AnalyzeRegion relates the readings of several locations. Each location's readings are resampled
+// to the whole hours all of them cover and their linear trends removed; then for each variable,
+// each pair of locations is correlated with one shifted by up to MaxLagHours either way, and the
+// strongest correlation is kept, so that a pressure drop reaching one city four hours after
+// another shows as a 4-hour lag. Pairs below MinCorrelation are left out; the rest are reported
+// strongest first. Locations with fewer than two readings are ignored, and without two locations
+// overlapping long enough to measure a lag the analysis has no locations.
+func (ra *RegionalAnalyzer) AnalyzeRegion(locations []models.LocationData) models.RegionalAnalysis {
+	region := models.RegionalAnalysis{
+		SchemaVersion: models.AnalysisSchemaVersion,
+		AnalysisType:  "regional_weather_analysis",
+		GeneratedAt:   time.Now(),
+		Correlations:  []models.StationCorrelation{},
+	}
+
+	var stations []*models.LocationData
+	var start, end time.Time
+	for i := range locations {
+		readings := locations[i].Readings
+		if len(readings) < 2 {
+			continue
+		}
+		sort.Slice(readings, func(i, j int) bool {
+			return readings[i].Timestamp.Before(readings[j].Timestamp)
+		})
+		first, last := readings[0].Timestamp, readings[len(readings)-1].Timestamp
+		if len(stations) == 0 || first.After(start) {
+			start = first
+		}
+		if len(stations) == 0 || last.Before(end) {
+			end = last
+		}
+		stations = append(stations, &locations[i])
+	}
+
+	// The first whole hour every location covers
+	if truncated := start.Truncate(time.Hour); truncated.Before(start) {
+		start = truncated.Add(time.Hour)
+	}
+	hours := int(end.Sub(start)/time.Hour) + 1
+	if len(stations) < 2 || end.Before(start) || hours < ra.MaxLagHours+minLagOverlap {
+		return region
+	}
+	region.Start, region.End = start, start.Add(time.Duration(hours-1)*time.Hour)
+	for _, station := range stations {
+		region.Locations = append(region.Locations, station.Name)
+	}
+
+	for _, variable := range correlationVariables {
+		series := make([][]float64, len(stations))
+		for i, station := range stations {
+			y := resample(station.Readings, variable.value, start, hours)
+			if detrend(y) > 1e-9 {
+				series[i] = y
+			}
+		}
+
+		for a := range stations {
+			for b := a + 1; b < len(stations); b++ {
+				if series[a] == nil || series[b] == nil {
+					continue // A location where the variable does not vary
+				}
+				best, bestLag := 0.0, 0
+				for lag := -ra.MaxLagHours; lag <= ra.MaxLagHours; lag++ {
+					if r := laggedCorrelation(series[a], series[b], lag); math.Abs(r) > math.Abs(best) {
+						best, bestLag = r, lag
+					}
+				}
+				if math.Abs(best) < ra.MinCorrelation {
+					continue
+				}
+
+				leader, follower := stations[a], stations[b]
+				if bestLag < 0 {
+					leader, follower, bestLag = follower, leader, -bestLag
+				}
+				correlation := models.StationCorrelation{
+					Variable:    variable.name,
+					Leader:      leader.Name,
+					Follower:    follower.Name,
+					LagHours:    bestLag,
+					Correlation: best,
+					DistanceKm:  distanceKm(leader.Coordinates, follower.Coordinates),
+				}
+				if bestLag > 0 {
+					correlation.SpeedKmh = correlation.DistanceKm / float64(bestLag)
+				}
+				region.Correlations = append(region.Correlations, correlation)
+			}
+		}
+	}
+
+	sort.SliceStable(region.Correlations, func(i, j int) bool {
+		return math.Abs(region.Correlations[i].Correlation) > math.Abs(region.Correlations[j].Correlation)
+	})
+	return region
+}
+
+// distanceKm returns the great-circle distance between two coordinates (haversine formula)
+func distanceKm(a, b models.Coordinates) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat, dLon := lat2-lat1, (b.Longitude-a.Longitude)*math.Pi/180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(min(h, 1)))
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// frontLocation returns hourly readings from offset hours after start, through a pressure trough
+// passing at passage hours after start
+func frontLocation(name string, lat, lon float64, start time.Time, offset, passage float64) models.LocationData {
+	location := models.LocationData{Name: name, Coordinates: models.Coordinates{Latitude: lat, Longitude: lon}}
+	for h := offset; h < 72; h++ {
+		d := (h - passage) / 6
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(h * float64(time.Hour))),
+			Temperature: 10,
+			Pressure:    1015 - 20*math.Exp(-d*d),
+		})
+	}
+	return location
+}
+
+// TestAnalyzeRegion tests that a front reaching three cities in turn is found with its delays,
+// on the hours all three cover
+func TestAnalyzeRegion(t *testing.T) {
+	start := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	locations := []models.LocationData{
+		frontLocation("Stockholm", 59.33, 18.07, start, 2.5, 34), // Half-hourly offsets, starting later
+		frontLocation("Oslo", 59.91, 10.75, start, 0, 30),
+		frontLocation("Helsinki", 60.17, 24.94, start, 0, 40),
+		{Name: "Empty"},
+	}
+
+	region := NewRegionalAnalyzer().AnalyzeRegion(locations)
+	if len(region.Locations) != 3 || !region.Start.Equal(start.Add(3*time.Hour)) || !region.End.Equal(start.Add(71*time.Hour)) {
+		t.Fatalf("Expected 3 locations over hours 3-71, got %v from %v to %v", region.Locations, region.Start, region.End)
+	}
+	if len(region.Correlations) != 3 { // Pressure of each pair; temperature does not vary
+		t.Fatalf("Expected 3 correlated pairs, got %+v", region.Correlations)
+	}
+
+	lags := map[[2]string]int{{"Oslo", "Stockholm"}: 4, {"Stockholm", "Helsinki"}: 6, {"Oslo", "Helsinki"}: 10}
+	for _, c := range region.Correlations {
+		lag, ok := lags[[2]string{c.Leader, c.Follower}]
+		if !ok || c.Variable != "pressure" || c.LagHours != lag || c.Correlation < 0.95 {
+			t.Errorf("Unexpected correlation %+v", c)
+		}
+		if c.SpeedKmh <= 0 || math.Abs(c.SpeedKmh*float64(c.LagHours)-c.DistanceKm) > 1e-9 {
+			t.Errorf("Expected the speed to be the distance over the lag, got %+v", c)
+		}
+	}
+
+	if region := NewRegionalAnalyzer().AnalyzeRegion(locations[:1]); len(region.Locations) != 0 {
+		t.Errorf("Expected no regional analysis of one location, got %+v", region)
+	}
+}
+
+// TestDistanceKm tests the great-circle distance against a known one
+func TestDistanceKm(t *testing.T) {
+	oslo := models.Coordinates{Latitude: 59.91, Longitude: 10.75}
+	stockholm := models.Coordinates{Latitude: 59.33, Longitude: 18.07}
+	if d := distanceKm(oslo, stockholm); math.Abs(d-416) > 5 {
+		t.Errorf("Expected about 416 km from Oslo to Stockholm, got %.1f", d)
+	}
+	if d := distanceKm(oslo, oslo); d != 0 {
+		t.Errorf("Expected no distance to the same place, got %f", d)
+	}
+}
@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"pattern-engine/models"
+)
+
+// Names of the built-in analyzers
+const (
+	TrendsAnalyzer        = "trends"
+	AnomaliesAnalyzer     = "anomalies"
+	PatternsAnalyzer      = "patterns"
+	StatisticsAnalyzer    = "statistics"
+	SeasonalityAnalyzer   = "seasonality"
+	SpectrumAnalyzer      = "spectrum"
+	MultivariateAnalyzer  = "multivariate"
+	CorrelationsAnalyzer  = "correlations"
+	StormAnalyzer         = "storm"
+	FogAnalyzer           = "fog"
+	ExtremesAnalyzer      = "extremes"
+	WindAnalyzer          = "wind"
+	PrecipitationAnalyzer = "precipitation"
+	AgroAnalyzer          = "agro"
+	EnergyAnalyzer        = "energy"
+	RoadIcingAnalyzer     = "road_icing"
+	ComfortAnalyzer       = "comfort"
+	DataQualityAnalyzer   = "data_quality"
+)
+
+// Analyzer is one analysis of a location's readings. The result of a built-in analyzer is stored
+// in its own field of models.AnalysisResult; results of other analyzers are stored in
+// AnalysisResult.Extensions under their name, so they must encode to JSON.
+type Analyzer interface {
+	Name() string
+	Analyze(locationData *models.LocationData) (any, error)
+}
+
+// Registry holds analyzers by name, in registration order
+type Registry struct {
+	analyzers []Analyzer
+}
+
+// NewRegistry returns a registry with the built-in analyzers at their default settings
+func NewRegistry() *Registry {
+	return NewRegistryWithThresholds(DefaultThresholds())
+}
+
+// Register adds an analyzer; names must be unique and non-empty
+func (r *Registry) Register(a Analyzer) error {
+	name := a.Name()
+	if name == "" || strings.ContainsAny(name, ", ") {
+		return fmt.Errorf("invalid analyzer name %q", name)
+	}
+	if _, ok := r.Get(name); ok {
+		return fmt.Errorf("analyzer %q is already registered", name)
+	}
+	r.analyzers = append(r.analyzers, a)
+	return nil
+}
+
+// Get returns the analyzer registered under name
+func (r *Registry) Get(name string) (Analyzer, bool) {
+	for _, a := range r.analyzers {
+		if a.Name() == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of the registered analyzers in registration order
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.analyzers))
+	for i, a := range r.analyzers {
+		names[i] = a.Name()
+	}
+	return names
+}
+
+// Select returns the analyzers named in names, in registration order, or every analyzer when
+// names is empty. Unknown names are an error.
+func (r *Registry) Select(names []string) ([]Analyzer, error) {
+	if len(names) == 0 {
+		return r.analyzers, nil
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := r.Get(name); !ok {
+			return nil, fmt.Errorf("unknown analyzer %q (available: %s)", name, strings.Join(r.Names(), ", "))
+		}
+		wanted[name] = true
+	}
+	var selected []Analyzer
+	for _, a := range r.analyzers {
+		if wanted[a.Name()] {
+			selected = append(selected, a)
+		}
+	}
+	return selected, nil
+}
+
+// ParseNames splits a comma-separated analyzer list such as "trends,anomalies"
+func ParseNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Name implements Analyzer
+func (ta *TrendAnalyzer) Name() string { return TrendsAnalyzer }
+
+// Analyze implements Analyzer with AnalyzeTrends
+func (ta *TrendAnalyzer) Analyze(locationData *models.LocationData) (any, error) {
+	return ta.AnalyzeTrends(locationData), nil
+}
+
+// Name implements Analyzer
+func (ad *AnomalyDetector) Name() string { return AnomaliesAnalyzer }
+
+// Analyze implements Analyzer with DetectAnomalies
+func (ad *AnomalyDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return ad.DetectAnomalies(locationData), nil
+}
+
+// Name implements Analyzer
+func (pr *PatternRecognizer) Name() string { return PatternsAnalyzer }
+
+// Analyze implements Analyzer with RecognizePatterns
+func (pr *PatternRecognizer) Analyze(locationData *models.LocationData) (any, error) {
+	return pr.RecognizePatterns(locationData), nil
+}
+
+// Name implements Analyzer
+func (sa *StatisticalAnalyzer) Name() string { return StatisticsAnalyzer }
+
+// Analyze implements Analyzer with AnalyzeStatistics
+func (sa *StatisticalAnalyzer) Analyze(locationData *models.LocationData) (any, error) {
+	return sa.AnalyzeStatistics(locationData), nil
+}
+
+// Name implements Analyzer
+func (sa *SeasonalityDetector) Name() string { return SeasonalityAnalyzer }
+
+// Analyze implements Analyzer with DetectSeasonality
+func (sa *SeasonalityDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return sa.DetectSeasonality(locationData), nil
+}
+
+// Name implements Analyzer
+func (sa *SpectralAnalyzer) Name() string { return SpectrumAnalyzer }
+
+// Analyze implements Analyzer with AnalyzeSpectrum
+func (sa *SpectralAnalyzer) Analyze(locationData *models.LocationData) (any, error) {
+	return sa.AnalyzeSpectrum(locationData), nil
+}
+
+// Name implements Analyzer
+func (md *MultivariateDetector) Name() string { return MultivariateAnalyzer }
+
+// Analyze implements Analyzer with DetectMultivariateAnomalies
+func (md *MultivariateDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return md.DetectMultivariateAnomalies(locationData), nil
+}
+
+// Name implements Analyzer
+func (lc *LagCorrelator) Name() string { return CorrelationsAnalyzer }
+
+// Analyze implements Analyzer with AnalyzeLagCorrelations
+func (lc *LagCorrelator) Analyze(locationData *models.LocationData) (any, error) {
+	return lc.AnalyzeLagCorrelations(locationData), nil
+}
+
+// Name implements Analyzer
+func (sd *StormDetector) Name() string { return StormAnalyzer }
+
+// Analyze implements Analyzer with AssessStormRisk
+func (sd *StormDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return sd.AssessStormRisk(locationData), nil
+}
+
+// Name implements Analyzer
+func (fd *FogDetector) Name() string { return FogAnalyzer }
+
+// Analyze implements Analyzer with DetectFog
+func (fd *FogDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return fd.DetectFog(locationData), nil
+}
+
+// Name implements Analyzer
+func (ed *ExtremeEventDetector) Name() string { return ExtremesAnalyzer }
+
+// Analyze implements Analyzer with DetectExtremeEvents
+func (ed *ExtremeEventDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return ed.DetectExtremeEvents(locationData), nil
+}
+
+// Name implements Analyzer
+func (wc *WindClassifier) Name() string { return WindAnalyzer }
+
+// Analyze implements Analyzer with ClassifyWind
+func (wc *WindClassifier) Analyze(locationData *models.LocationData) (any, error) {
+	return wc.ClassifyWind(locationData), nil
+}
+
+// Name implements Analyzer
+func (pa *PrecipitationAccumulator) Name() string { return PrecipitationAnalyzer }
+
+// Analyze implements Analyzer with AnalyzePrecipitation
+func (pa *PrecipitationAccumulator) Analyze(locationData *models.LocationData) (any, error) {
+	return pa.AnalyzePrecipitation(locationData), nil
+}
+
+// Name implements Analyzer
+func (ac *AgroCalculator) Name() string { return AgroAnalyzer }
+
+// Analyze implements Analyzer with AgroIndices
+func (ac *AgroCalculator) Analyze(locationData *models.LocationData) (any, error) {
+	return ac.AgroIndices(locationData), nil
+}
+
+// Name implements Analyzer
+func (ee *EnergyEstimator) Name() string { return EnergyAnalyzer }
+
+// Analyze implements Analyzer with EstimateEnergy
+func (ee *EnergyEstimator) Analyze(locationData *models.LocationData) (any, error) {
+	return ee.EstimateEnergy(locationData), nil
+}
+
+// Name implements Analyzer
+func (rd *RoadIcingDetector) Name() string { return RoadIcingAnalyzer }
+
+// Analyze implements Analyzer with AssessRoadIcing
+func (rd *RoadIcingDetector) Analyze(locationData *models.LocationData) (any, error) {
+	return rd.AssessRoadIcing(locationData), nil
+}
+
+// Name implements Analyzer
+func (ca *ComfortAssessor) Name() string { return ComfortAnalyzer }
+
+// Analyze implements Analyzer with AssessComfort
+func (ca *ComfortAssessor) Analyze(locationData *models.LocationData) (any, error) {
+	return ca.AssessComfort(locationData), nil
+}
+
+// Name implements Analyzer
+func (dq *DataQualityChecker) Name() string { return DataQualityAnalyzer }
+
+// Analyze implements Analyzer with CheckQuality
+func (dq *DataQualityChecker) Analyze(locationData *models.LocationData) (any, error) {
+	return dq.CheckQuality(locationData), nil
+}
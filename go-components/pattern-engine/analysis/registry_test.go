@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"pattern-engine/models"
+	"slices"
+	"testing"
+	"time"
+)
+
+// constantAnalyzer is a third-party analyzer returning a fixed value
+type constantAnalyzer struct{ name string }
+
+func (c constantAnalyzer) Name() string { return c.name }
+
+func (c constantAnalyzer) Analyze(*models.LocationData) (any, error) { return 42, nil }
+
+// TestRegistryBuiltins tests that the built-in analyzers are registered in order
+func TestRegistryBuiltins(t *testing.T) {
+	names := NewRegistry().Names()
+	want := []string{TrendsAnalyzer, AnomaliesAnalyzer, PatternsAnalyzer, StatisticsAnalyzer, SeasonalityAnalyzer, SpectrumAnalyzer, MultivariateAnalyzer, CorrelationsAnalyzer, StormAnalyzer, FogAnalyzer, ExtremesAnalyzer, WindAnalyzer, PrecipitationAnalyzer, AgroAnalyzer, EnergyAnalyzer, RoadIcingAnalyzer, ComfortAnalyzer, DataQualityAnalyzer}
+	if !slices.Equal(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
+
+// TestRegistryRegister tests that names must be unique and valid
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(constantAnalyzer{"answer"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register(constantAnalyzer{"answer"}); err == nil {
+		t.Error("Expected an error for a duplicate name")
+	}
+	if err := registry.Register(constantAnalyzer{"a,b"}); err == nil {
+		t.Error("Expected an error for a name with a comma")
+	}
+	if _, ok := registry.Get("answer"); !ok {
+		t.Error("Expected the registered analyzer to be found")
+	}
+}
+
+// TestRegistrySelect tests that selection keeps registration order and rejects unknown names
+func TestRegistrySelect(t *testing.T) {
+	registry := NewRegistry()
+
+	selected, err := registry.Select(ParseNames(" statistics, trends ,"))
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(selected) != 2 || selected[0].Name() != TrendsAnalyzer || selected[1].Name() != StatisticsAnalyzer {
+		t.Errorf("Unexpected selection: %v", selected)
+	}
+
+	if all, _ := registry.Select(nil); len(all) != 18 {
+		t.Errorf("Expected every analyzer without names, got %d", len(all))
+	}
+	if _, err := registry.Select([]string{"forecast"}); err == nil {
+		t.Error("Expected an error for an unknown analyzer")
+	}
+}
+
+// TestNewRegistryWithThresholds tests that the built-in analyzers use the given thresholds
+func TestNewRegistryWithThresholds(t *testing.T) {
+	thresholds := DefaultThresholds()
+	thresholds.Patterns.HighPressure = 1010
+	thresholds.Patterns.HighPressureMean = 1010
+	registry := NewRegistryWithThresholds(thresholds)
+
+	var readings []models.WeatherPoint
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		readings = append(readings, models.WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Pressure: 1012, Temperature: 20})
+	}
+
+	hasHighPressure := func(r *Registry) bool {
+		analyzer, _ := r.Get(PatternsAnalyzer)
+		output, _ := analyzer.Analyze(&models.LocationData{Readings: readings})
+		for _, pattern := range output.([]models.Pattern) {
+			if pattern.Name == "high_pressure_system" {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasHighPressure(registry) {
+		t.Error("Expected a high pressure system at 1012 hPa with a 1010 hPa threshold")
+	}
+	if hasHighPressure(NewRegistry()) {
+		t.Error("Expected no high pressure system at 1012 hPa with the default thresholds")
+	}
+	if len(registry.Names()) != len(NewRegistry().Names()) {
+		t.Errorf("Expected every built-in analyzer, got %v", registry.Names())
+	}
+}
@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"pattern-engine/derive"
+	"pattern-engine/models"
+)
+
+// ResampleSettings configure the resampling of readings onto a uniform time grid before the
+// regression and spectral analyses, read from the "resample" key of the "analysis" config section
+type ResampleSettings struct {
+	Interval time.Duration `json:"interval"` // Spacing of the grid (0 = readings are analyzed as they are)
+	MaxGap   time.Duration `json:"max_gap"`  // Longest gap between readings interpolated across
+}
+
+// DefaultResampleSettings returns the resampling settings used without a config file: readings are
+// analyzed as they are
+func DefaultResampleSettings() ResampleSettings {
+	return ResampleSettings{MaxGap: 3 * time.Hour}
+}
+
+// resampledFields are the measured variables interpolated onto the grid
+var resampledFields = []func(*models.WeatherPoint) *float64{
+	func(r *models.WeatherPoint) *float64 { return &r.Temperature },
+	func(r *models.WeatherPoint) *float64 { return &r.Pressure },
+	func(r *models.WeatherPoint) *float64 { return &r.Humidity },
+	func(r *models.WeatherPoint) *float64 { return &r.WindSpeed },
+	func(r *models.WeatherPoint) *float64 { return &r.CloudCover },
+	func(r *models.WeatherPoint) *float64 { return &r.PrecipitationMm },
+	func(r *models.WeatherPoint) *float64 { return &r.PrecipitationProbability },
+	func(r *models.WeatherPoint) *float64 { return &r.DewPoint },
+	func(r *models.WeatherPoint) *float64 { return &r.UVIndex },
+	func(r *models.WeatherPoint) *float64 { return &r.WindGust },
+	func(r *models.WeatherPoint) *float64 { return &r.FogAreaFraction },
+}
+
+// Resample returns the readings at the times of a grid of Interval from the first reading to the
+// last, aligned to whole intervals, and how they were resampled. Each grid reading is interpolated
+// linearly between the readings around its time, with the wind direction along the shorter arc,
+// the symbol of the earlier reading and the derived variables computed anew. Grid times in a gap
+// longer than MaxGap would only be guesses: they have no reading, and are counted as missing
+// rather than carried as NaN values the analyses and JSON cannot hold. Without an interval or two
+// readings the readings are returned unchanged and the resampling is nil.
+func Resample(readings []models.WeatherPoint, settings ResampleSettings) ([]models.WeatherPoint, *models.Resampling) {
+	if settings.Interval <= 0 || len(readings) < 2 {
+		return readings, nil
+	}
+	readings = slices.Clone(readings)
+	slices.SortStableFunc(readings, func(a, b models.WeatherPoint) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	first, last := readings[0].Timestamp, readings[len(readings)-1].Timestamp
+	start := first.Truncate(settings.Interval)
+	if start.Before(first) {
+		start = start.Add(settings.Interval)
+	}
+
+	resampling := &models.Resampling{IntervalMinutes: settings.Interval.Minutes(), Original: len(readings)}
+	var grid []models.WeatherPoint
+	j := 0 // Index of the latest reading at or before the grid time
+	for t := start; !t.After(last); t = t.Add(settings.Interval) {
+		for j+1 < len(readings) && !readings[j+1].Timestamp.After(t) {
+			j++
+		}
+		before := readings[j]
+		if before.Timestamp.Equal(t) || j+1 == len(readings) {
+			grid = append(grid, before)
+			continue
+		}
+		after := readings[j+1]
+		if after.Timestamp.Sub(before.Timestamp) > settings.MaxGap {
+			resampling.Missing++
+			continue
+		}
+		grid = append(grid, interpolateReading(before, after, t))
+		resampling.Interpolated++
+	}
+
+	derive.Apply(grid)
+	resampling.Readings = len(grid)
+	return grid, resampling
+}
+
+// interpolateReading returns the reading at time t between two readings
+func interpolateReading(before, after models.WeatherPoint, t time.Time) models.WeatherPoint {
+	w := t.Sub(before.Timestamp).Seconds() / after.Timestamp.Sub(before.Timestamp).Seconds()
+	r := before
+	r.Timestamp = t
+	for _, field := range resampledFields {
+		*field(&r) += w * (*field(&after) - *field(&before))
+	}
+	r.WindDirection = math.Mod(before.WindDirection+w*angleDifference(before.WindDirection, after.WindDirection)+360, 360)
+	return r
+}
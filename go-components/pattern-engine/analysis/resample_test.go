@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestResample tests that irregular readings are interpolated onto the grid, across short gaps only
+func TestResample(t *testing.T) {
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	readings := []models.WeatherPoint{
+		{Timestamp: at(90), Temperature: 12, WindDirection: 10, SymbolCode: "rain"},
+		{Timestamp: at(10), Temperature: 10, WindDirection: 350, SymbolCode: "cloudy"},
+		{Timestamp: at(120), Temperature: 13, WindDirection: 10},
+		{Timestamp: at(600), Temperature: 20, WindDirection: 10}, // After a gap of 8 hours
+	}
+
+	grid, resampling := Resample(readings, ResampleSettings{Interval: time.Hour, MaxGap: 3 * time.Hour})
+	if resampling == nil || resampling.Original != 4 || resampling.Readings != 3 || resampling.Interpolated != 1 || resampling.Missing != 7 {
+		t.Fatalf("Expected 1:00 interpolated, 2:00 and 10:00 read and 7 hours missing, got %+v", resampling)
+	}
+	want := []time.Time{at(60), at(120), at(600)}
+	for i, r := range grid {
+		if !r.Timestamp.Equal(want[i]) {
+			t.Errorf("Grid reading %d: expected %v, got %v", i, want[i], r.Timestamp)
+		}
+	}
+	if interpolated := grid[0]; math.Abs(interpolated.Temperature-11.25) > 1e-9 ||
+		math.Abs(interpolated.WindDirection-2.5) > 1e-9 || interpolated.SymbolCode != "cloudy" {
+		t.Errorf("Expected 11.25°C from 2.5° under the earlier symbol, got %+v", interpolated)
+	}
+	if readings[0].Timestamp != at(90) {
+		t.Error("Expected the readings left in their order")
+	}
+
+	if same, resampling := Resample(readings, DefaultResampleSettings()); resampling != nil || len(same) != 4 {
+		t.Errorf("Expected the readings unchanged without an interval, got %d (%+v)", len(same), resampling)
+	}
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// RollingStatistics returns the rolling statistics of a variable over each window of
+// RollingWindowHours, one series per window. Each point covers the readings in the window ending at
+// a reading; points start once the readings span a whole window, so windows longer than the
+// readings have no series.
+func (sa *StatisticalAnalyzer) RollingStatistics(readings []models.WeatherPoint, value func(models.WeatherPoint) float64) []models.RollingStatistics {
+	sorted := make([]models.WeatherPoint, len(readings))
+	copy(sorted, readings)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var rolling []models.RollingStatistics
+	for _, hours := range sa.RollingWindowHours {
+		if series, ok := rollingSeries(sorted, value, time.Duration(hours)*time.Hour); ok {
+			series.WindowHours = hours
+			rolling = append(rolling, series)
+		}
+	}
+	return rolling
+}
+
+// rollingSeries computes the statistics of a window sliding over readings in chronological order.
+// Sums of the values (less the first, to keep the variance accurate for large values such as
+// pressure) are updated as readings enter and leave the window, and the extremes are kept in
+// monotonic queues of reading indexes.
+func rollingSeries(readings []models.WeatherPoint, value func(models.WeatherPoint) float64, window time.Duration) (models.RollingStatistics, bool) {
+	if len(readings) < 2 || readings[len(readings)-1].Timestamp.Sub(readings[0].Timestamp) < window {
+		return models.RollingStatistics{}, false
+	}
+
+	shift := value(readings[0])
+	var series models.RollingStatistics
+	var sum, sumSquares float64
+	var minQueue, maxQueue []int // Indexes in the window with increasing (min) or decreasing (max) values
+	start := 0
+	for i, r := range readings {
+		v := value(r)
+		sum += v - shift
+		sumSquares += (v - shift) * (v - shift)
+		for len(minQueue) > 0 && value(readings[minQueue[len(minQueue)-1]]) >= v {
+			minQueue = minQueue[:len(minQueue)-1]
+		}
+		minQueue = append(minQueue, i)
+		for len(maxQueue) > 0 && value(readings[maxQueue[len(maxQueue)-1]]) <= v {
+			maxQueue = maxQueue[:len(maxQueue)-1]
+		}
+		maxQueue = append(maxQueue, i)
+
+		// Drop readings at or before the start of the window ending at this reading
+		for r.Timestamp.Sub(readings[start].Timestamp) >= window {
+			old := value(readings[start]) - shift
+			sum -= old
+			sumSquares -= old * old
+			if minQueue[0] == start {
+				minQueue = minQueue[1:]
+			}
+			if maxQueue[0] == start {
+				maxQueue = maxQueue[1:]
+			}
+			start++
+		}
+		if r.Timestamp.Sub(readings[0].Timestamp) < window {
+			continue // The window reaches back before the first reading
+		}
+
+		n := float64(i - start + 1)
+		mean := sum / n
+		series.Timestamps = append(series.Timestamps, r.Timestamp)
+		series.Mean = append(series.Mean, mean+shift)
+		series.StdDev = append(series.StdDev, math.Sqrt(math.Max(0, sumSquares/n-mean*mean)))
+		series.Min = append(series.Min, value(readings[minQueue[0]]))
+		series.Max = append(series.Max, value(readings[maxQueue[0]]))
+	}
+	return series, true
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestRollingStatistics tests the moving average, standard deviation and extremes of each window
+func TestRollingStatistics(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 10 {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: float64(i),
+			Pressure:    1000 + float64(i%2), // Alternates 1000, 1001
+		})
+	}
+	// Out of order readings are sorted
+	readings[3], readings[7] = readings[7], readings[3]
+
+	analyzer := &StatisticalAnalyzer{StatisticsThresholds: StatisticsThresholds{RollingWindowHours: []int{3, 12}}}
+	temperature := analyzer.RollingStatistics(readings, func(r models.WeatherPoint) float64 { return r.Temperature })
+	if len(temperature) != 1 {
+		t.Fatalf("Expected only the 3-hour series from 9 hours of readings, got %+v", temperature)
+	}
+
+	series := temperature[0]
+	if series.WindowHours != 3 || len(series.Timestamps) != 7 || !series.Timestamps[0].Equal(start.Add(3*time.Hour)) {
+		t.Fatalf("Expected 7 points from 03:00, got %+v", series)
+	}
+	for i := range series.Timestamps {
+		hour := float64(i + 3) // The window ending at 03:00 holds the readings of 01:00-03:00
+		if series.Mean[i] != hour-1 || series.Min[i] != hour-2 || series.Max[i] != hour {
+			t.Errorf("Point %d: expected mean %v in [%v, %v], got %v in [%v, %v]",
+				i, hour-1, hour-2, hour, series.Mean[i], series.Min[i], series.Max[i])
+		}
+		if math.Abs(series.StdDev[i]-math.Sqrt(2.0/3)) > 1e-9 {
+			t.Errorf("Point %d: expected standard deviation %v, got %v", i, math.Sqrt(2.0/3), series.StdDev[i])
+		}
+	}
+
+	analyzer.RollingWindowHours = []int{2}
+	pressure := analyzer.RollingStatistics(readings, func(r models.WeatherPoint) float64 { return r.Pressure })
+	for i, std := range pressure[0].StdDev {
+		if math.Abs(pressure[0].Mean[i]-1000.5) > 1e-9 || math.Abs(std-0.5) > 1e-9 {
+			t.Errorf("Point %d: expected 1000.5 ± 0.5, got %v ± %v", i, pressure[0].Mean[i], std)
+		}
+	}
+}
+
+// TestAnalyzeStatisticsRolling tests that each variable's statistics carry its rolling series
+func TestAnalyzeStatisticsRolling(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	location := &models.LocationData{Readings: diurnalReadings(start, 30)}
+
+	for _, stat := range NewStatisticalAnalyzer().AnalyzeStatistics(location) {
+		if len(stat.Rolling) != 3 {
+			t.Errorf("Expected 3-, 6- and 24-hour series for %s, got %d", stat.Variable, len(stat.Rolling))
+			continue
+		}
+		if day := stat.Rolling[2]; day.WindowHours != 24 || len(day.Mean) != 6 {
+			t.Errorf("Expected 6 points of the 24-hour %s series, got %d", stat.Variable, len(day.Mean))
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"pattern-engine/models"
+)
+
+// RunningStats accumulates the mean, standard deviation, skewness, kurtosis, min and max of a
+// variable one value at a time (Welford's method, extended to higher moments), so long histories
+// do not have to be held in memory
+type RunningStats struct {
+	n    int
+	mean float64
+	m2   float64 // Sum of squared differences from the mean
+	m3   float64 // Sum of cubed differences from the mean
+	m4   float64 // Sum of fourth powers of differences from the mean
+	min  float64
+	max  float64
+}
+
+// Add adds a value
+func (s *RunningStats) Add(v float64) {
+	s.n++
+	if s.n == 1 {
+		s.min, s.max = v, v
+	} else {
+		s.min = math.Min(s.min, v)
+		s.max = math.Max(s.max, v)
+	}
+	n := float64(s.n)
+	delta := v - s.mean
+	deltaN := delta / n
+	term := delta * deltaN * (n - 1)
+	// The higher moments are updated from the lower ones before those change
+	s.m4 += term*deltaN*deltaN*(n*n-3*n+3) + 6*deltaN*deltaN*s.m2 - 4*deltaN*s.m3
+	s.m3 += term*deltaN*(n-2) - 3*deltaN*s.m2
+	s.mean += deltaN
+	s.m2 += delta * (v - s.mean)
+}
+
+// Count returns the number of values added
+func (s RunningStats) Count() int { return s.n }
+
+// Mean returns the mean of the values
+func (s RunningStats) Mean() float64 { return s.mean }
+
+// StdDev returns the population standard deviation, as AnalyzeStatistics reports it
+func (s RunningStats) StdDev() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.n))
+}
+
+// Skewness returns the skewness of the values, as AnalyzeStatistics reports it
+func (s RunningStats) Skewness() float64 {
+	skewness, _ := shape(s.n, s.m2, s.m3, s.m4)
+	return skewness
+}
+
+// Kurtosis returns the excess kurtosis of the values, as AnalyzeStatistics reports it
+func (s RunningStats) Kurtosis() float64 {
+	_, kurtosis := shape(s.n, s.m2, s.m3, s.m4)
+	return kurtosis
+}
+
+// Min returns the smallest value
+func (s RunningStats) Min() float64 { return s.min }
+
+// Max returns the largest value
+func (s RunningStats) Max() float64 { return s.max }
+
+// statisticsVariables are the variables AnalyzeStatistics reports, in order
+var statisticsVariables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }},
+	{"pressure", func(r models.WeatherPoint) float64 { return r.Pressure }},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }},
+	{"wind_speed", func(r models.WeatherPoint) float64 { return r.WindSpeed }},
+	{"precipitation_mm", func(r models.WeatherPoint) float64 { return r.PrecipitationMm }},
+}
+
+// StatisticsAccumulator collects the statistics of AnalyzeStatistics incrementally, for readings
+// that are streamed rather than loaded at once
+type StatisticsAccumulator struct {
+	stats [5]RunningStats // Indexed like statisticsVariables
+	wind  windAccumulator
+}
+
+// Add adds a chunk of readings
+func (a *StatisticsAccumulator) Add(readings []models.WeatherPoint) {
+	for _, r := range readings {
+		for i, variable := range statisticsVariables {
+			a.stats[i].Add(variable.value(r))
+		}
+		a.wind.add(r)
+	}
+}
+
+// Count returns the number of readings added
+func (a *StatisticsAccumulator) Count() int {
+	return a.stats[0].Count()
+}
+
+// Stats returns the running statistics of a variable reported by AnalyzeStatistics, such as
+// "temperature", or empty statistics for any other variable
+func (a *StatisticsAccumulator) Stats(variable string) RunningStats {
+	for i, v := range statisticsVariables {
+		if v.name == variable {
+			return a.stats[i]
+		}
+	}
+	return RunningStats{}
+}
+
+// AccumulatedStatistics returns the accumulated statistics in the layout of AnalyzeStatistics.
+// Exact percentiles need every value, so medians, the other percentiles and rolling statistics
+// are taken from window, the most recent readings.
+func (sa *StatisticalAnalyzer) AccumulatedStatistics(acc *StatisticsAccumulator, window []models.WeatherPoint) []models.StatisticalData {
+	var stats []models.StatisticalData
+	for i, variable := range statisticsVariables {
+		s := acc.stats[i]
+		if s.Count() < 2 {
+			continue // Need at least 2 values for statistics
+		}
+		sorted := windowValues(window, variable.value)
+		stat := models.StatisticalData{
+			Variable:        variable.name,
+			Mean:            s.Mean(),
+			Median:          percentile(sorted, 0.5),
+			Min:             s.Min(),
+			Max:             s.Max(),
+			StdDev:          s.StdDev(),
+			SampleSize:      s.Count(),
+			ConfidenceLevel: sa.ConfidenceLevel,
+			TrendStrength:   calculateTrendStrengthFromStats(s.Mean(), s.StdDev(), s.Count()),
+			CiLower:         s.Mean() - sa.meanInterval(s.StdDev(), s.Count()),
+			CiUpper:         s.Mean() + sa.meanInterval(s.StdDev(), s.Count()),
+			Skewness:        s.Skewness(),
+			Kurtosis:        s.Kurtosis(),
+		}
+		setPercentiles(&stat, sorted)
+		stats = append(stats, stat)
+	}
+	if windStats := acc.wind.statistics(sa.ConfidenceLevel); windStats != nil {
+		stats = append(stats, *windStats)
+	}
+	sa.addRollingStatistics(stats, window)
+	return stats
+}
+
+// windowValues returns the values of a variable over readings, sorted
+func windowValues(readings []models.WeatherPoint, value func(models.WeatherPoint) float64) []float64 {
+	values := make([]float64, len(readings))
+	for i, r := range readings {
+		values[i] = value(r)
+	}
+	sort.Float64s(values)
+	return values
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"math"
+	"pattern-engine/models"
+	"testing"
+	"time"
+)
+
+// TestRunningStats tests that the running statistics match a direct computation
+func TestRunningStats(t *testing.T) {
+	var s RunningStats
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.Add(v)
+	}
+	if s.Count() != 8 || s.Mean() != 5 || s.StdDev() != 2 || s.Min() != 2 || s.Max() != 9 {
+		t.Errorf("Unexpected statistics: n=%d mean=%v std=%v min=%v max=%v", s.Count(), s.Mean(), s.StdDev(), s.Min(), s.Max())
+	}
+	if math.Abs(s.Skewness()-0.65625) > 1e-9 || math.Abs(s.Kurtosis()+0.21875) > 1e-9 {
+		t.Errorf("Unexpected shape: skewness=%v kurtosis=%v", s.Skewness(), s.Kurtosis())
+	}
+}
+
+// TestAccumulatedStatisticsMatchAnalyzeStatistics tests that statistics accumulated in chunks
+// equal those of AnalyzeStatistics when the window holds every reading
+func TestAccumulatedStatisticsMatchAnalyzeStatistics(t *testing.T) {
+	baseTime := time.Now()
+	var readings []models.WeatherPoint
+	for i := range 25 {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:       baseTime.Add(time.Duration(i) * time.Hour),
+			Temperature:     10 + math.Sin(float64(i)),
+			Pressure:        1010 - float64(i%7),
+			Humidity:        60 + float64(i%5),
+			WindSpeed:       float64(i % 3),
+			PrecipitationMm: float64(i%4) * 0.5,
+		})
+	}
+
+	analyzer := NewStatisticalAnalyzer()
+	var acc StatisticsAccumulator
+	for start := 0; start < len(readings); start += 10 {
+		acc.Add(readings[start:min(start+10, len(readings))])
+	}
+	got := analyzer.AccumulatedStatistics(&acc, readings)
+	want := analyzer.AnalyzeStatistics(&models.LocationData{Readings: readings})
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d variables, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Variable != want[i].Variable || got[i].SampleSize != want[i].SampleSize || got[i].Median != want[i].Median ||
+			math.Abs(got[i].Mean-want[i].Mean) > 1e-9 || math.Abs(got[i].StdDev-want[i].StdDev) > 1e-9 ||
+			got[i].P90 != want[i].P90 || math.Abs(got[i].Skewness-want[i].Skewness) > 1e-9 || math.Abs(got[i].Kurtosis-want[i].Kurtosis) > 1e-9 {
+			t.Errorf("Variable %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if acc.Count() != 25 || acc.Stats("pressure").Min() != 1004 {
+		t.Errorf("Unexpected accumulator: count=%d pressure min=%v", acc.Count(), acc.Stats("pressure").Min())
+	}
+}
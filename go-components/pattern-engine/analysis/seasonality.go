@@ -0,0 +1,197 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// minSeasonalReadings is the fewest readings a daily cycle is fitted to
+const minSeasonalReadings = 8
+
+// dayHours is the period of the daily cycle
+const dayHours = 24.0
+
+// seasonalVariables are the variables checked for a daily cycle
+var seasonalVariables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+	set   func(*models.WeatherPoint, float64)
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }, func(r *models.WeatherPoint, v float64) { r.Temperature = v }},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }, func(r *models.WeatherPoint, v float64) { r.Humidity = v }},
+}
+
+// NewSeasonalityDetector creates a new seasonality detector with default settings
+func NewSeasonalityDetector() *SeasonalityDetector {
+	return &SeasonalityDetector{DefaultThresholds().Seasonality}
+}
+
+// DetectSeasonality finds the daily cycles of temperature and humidity. Each cycle is the
+// sinusoid over the local hour of day (see LocalZone) that best fits the readings around their linear trend;
+// it is reported when the readings span MinSpanHours and it explains MinStrength of the variance.
+func (sa *SeasonalityDetector) DetectSeasonality(locationData *models.LocationData) []models.Seasonality {
+	readings := locationData.Readings
+	if len(readings) < minSeasonalReadings {
+		return []models.Seasonality{}
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+	if readings[len(readings)-1].Timestamp.Sub(readings[0].Timestamp).Hours() < sa.MinSpanHours {
+		return []models.Seasonality{}
+	}
+
+	zone := LocalZone(locationData)
+	cycles := []models.Seasonality{}
+	for _, variable := range seasonalVariables {
+		if cycle, ok := fitDailyCycle(readings, variable.value, zone); ok && cycle.Strength >= sa.MinStrength {
+			cycle.Variable = variable.name
+			cycles = append(cycles, cycle)
+		}
+	}
+	return cycles
+}
+
+// Adjust returns the location data with its daily cycles removed, and the cycles. Without
+// Deseasonalize, or without a cycle, locationData itself is returned.
+func (sa *SeasonalityDetector) Adjust(locationData *models.LocationData) (*models.LocationData, []models.Seasonality) {
+	cycles := sa.DetectSeasonality(locationData)
+	if !sa.Deseasonalize || len(cycles) == 0 {
+		return locationData, cycles
+	}
+	adjusted := *locationData
+	adjusted.Readings = Deseasonalize(locationData.Readings, cycles, LocalZone(locationData))
+	return &adjusted, cycles
+}
+
+// Deseasonalize returns a copy of readings with the daily cycles, over the hour of day in zone,
+// subtracted, so that what remains is the weather beyond the usual warm afternoons and cool nights
+func Deseasonalize(readings []models.WeatherPoint, cycles []models.Seasonality, zone *time.Location) []models.WeatherPoint {
+	adjusted := make([]models.WeatherPoint, len(readings))
+	copy(adjusted, readings)
+	for _, cycle := range cycles {
+		for _, variable := range seasonalVariables {
+			if variable.name != cycle.Variable {
+				continue
+			}
+			for i := range adjusted {
+				v := variable.value(adjusted[i]) - CycleAt(cycle, adjusted[i].Timestamp, zone)
+				variable.set(&adjusted[i], v)
+			}
+		}
+	}
+	return adjusted
+}
+
+// fitDailyCycle fits a linear trend plus a*cos + b*sin of the hour of day in zone to the readings
+// by least squares. The cycle's strength is the share of the variation around the trend alone
+// that it explains.
+func fitDailyCycle(readings []models.WeatherPoint, value func(models.WeatherPoint) float64, zone *time.Location) (models.Seasonality, bool) {
+	n := float64(len(readings))
+	base := readings[0].Timestamp
+
+	// Columns: hours since the first reading, cos and sin of the hour of day; centered below
+	var cols [3][]float64
+	y := make([]float64, len(readings))
+	var means [3]float64
+	var meanY float64
+	for i := range cols {
+		cols[i] = make([]float64, len(readings))
+	}
+	for i, reading := range readings {
+		angle := 2 * math.Pi * hourOfDay(reading.Timestamp, zone) / dayHours
+		cols[0][i] = reading.Timestamp.Sub(base).Hours()
+		cols[1][i], cols[2][i] = math.Cos(angle), math.Sin(angle)
+		y[i] = value(reading)
+		for j := range cols {
+			means[j] += cols[j][i] / n
+		}
+		meanY += y[i] / n
+	}
+
+	// Normal equations of the centered columns
+	var xtx [3][3]float64
+	var xty [3]float64
+	var syy float64
+	for i := range y {
+		dy := y[i] - meanY
+		syy += dy * dy
+		for j := range cols {
+			dj := cols[j][i] - means[j]
+			xty[j] += dj * dy
+			for k := range cols {
+				xtx[j][k] += dj * (cols[k][i] - means[k])
+			}
+		}
+	}
+	beta, ok := solve3(xtx, xty)
+	if !ok || xtx[0][0] == 0 {
+		return models.Seasonality{}, false // Readings at too few hours of the day
+	}
+
+	trendOnly := syy - xty[0]*xty[0]/xtx[0][0] // Variation left around the trend alone
+	joint := syy - (beta[0]*xty[0] + beta[1]*xty[1] + beta[2]*xty[2])
+	if trendOnly <= 0 {
+		return models.Seasonality{}, false // No variation around the trend
+	}
+
+	a, b := beta[1], beta[2]
+	peak := math.Atan2(b, a) * dayHours / (2 * math.Pi)
+	if peak < 0 {
+		peak += dayHours
+	}
+	return models.Seasonality{
+		Amplitude: math.Hypot(a, b),
+		PeakHour:  peak,
+		Strength:  math.Max(0, math.Min(1, 1-joint/trendOnly)),
+	}, true
+}
+
+// solve3 solves a 3x3 linear system by Gaussian elimination with partial pivoting; it fails
+// when the system is (nearly) singular
+func solve3(m [3][3]float64, v [3]float64) ([3]float64, bool) {
+	for col := range 3 {
+		pivot := col
+		for row := col + 1; row < 3; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-9 {
+			return [3]float64{}, false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		v[col], v[pivot] = v[pivot], v[col]
+		for row := col + 1; row < 3; row++ {
+			f := m[row][col] / m[col][col]
+			for k := col; k < 3; k++ {
+				m[row][k] -= f * m[col][k]
+			}
+			v[row] -= f * v[col]
+		}
+	}
+	var x [3]float64
+	for row := 2; row >= 0; row-- {
+		sum := v[row]
+		for k := row + 1; k < 3; k++ {
+			sum -= m[row][k] * x[k]
+		}
+		x[row] = sum / m[row][row]
+	}
+	return x, true
+}
+
+// CycleAt returns the deviation of a daily cycle, found over the hour of day in zone, from its
+// mean at time t
+func CycleAt(cycle models.Seasonality, t time.Time, zone *time.Location) float64 {
+	return cycle.Amplitude * math.Cos(2*math.Pi*(hourOfDay(t, zone)-cycle.PeakHour)/dayHours)
+}
+
+// hourOfDay returns the time of day in zone as fractional hours
+func hourOfDay(t time.Time, zone *time.Location) float64 {
+	t = t.In(zone)
+	return float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// diurnalReadings returns hourly readings from start with a temperature cycle peaking at 15:00 UTC
+// and constant humidity
+func diurnalReadings(start time.Time, hours int) []models.WeatherPoint {
+	var readings []models.WeatherPoint
+	for i := range hours {
+		ts := start.Add(time.Duration(i) * time.Hour)
+		noise := 0.2 * math.Sin(float64(i)*1.7) // Deterministic wobble around the cycle
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   ts,
+			Temperature: 10 + 5*math.Cos(2*math.Pi*(float64(ts.Hour())-15)/24) + noise,
+			Humidity:    70,
+		})
+	}
+	return readings
+}
+
+// TestDetectSeasonality tests that the daily temperature cycle is found with its amplitude and peak
+func TestDetectSeasonality(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	location := &models.LocationData{Readings: diurnalReadings(start, 72)}
+
+	cycles := NewSeasonalityDetector().DetectSeasonality(location)
+	if len(cycles) != 1 || cycles[0].Variable != "temperature" {
+		t.Fatalf("Expected only a temperature cycle, got %+v", cycles)
+	}
+	if math.Abs(cycles[0].Amplitude-5) > 0.2 || math.Abs(cycles[0].PeakHour-15) > 0.2 || cycles[0].Strength < 0.9 {
+		t.Errorf("Expected amplitude 5 peaking at 15:00, got %+v", cycles[0])
+	}
+
+	short := &models.LocationData{Readings: diurnalReadings(start, 12)}
+	if cycles := NewSeasonalityDetector().DetectSeasonality(short); len(cycles) != 0 {
+		t.Errorf("Expected no cycle from 12 hours of readings, got %+v", cycles)
+	}
+}
+
+// TestAdjust tests that removing the cycle leaves the weather around it and the input untouched
+func TestAdjust(t *testing.T) {
+	start := time.Date(2025, 7, 1, 3, 0, 0, 0, time.UTC)
+	location := &models.LocationData{Name: "Bergen, Norway", Readings: diurnalReadings(start, 37)}
+
+	adjusted, cycles := NewSeasonalityDetector().Adjust(location)
+	if len(cycles) != 1 || adjusted == location || adjusted.Name != location.Name {
+		t.Fatalf("Expected adjusted location data, got %+v", cycles)
+	}
+	for i, r := range adjusted.Readings {
+		if math.Abs(r.Temperature-10) > 0.5 {
+			t.Errorf("Reading %d: expected about 10°C without the cycle, got %.2f", i, r.Temperature)
+		}
+	}
+	if location.Readings[12].Temperature < 14 {
+		t.Errorf("Expected the original readings to keep the cycle, got %.2f at 15:00", location.Readings[12].Temperature)
+	}
+
+	detector := NewSeasonalityDetector()
+	detector.Deseasonalize = false
+	if same, cycles := detector.Adjust(location); same != location || len(cycles) != 1 {
+		t.Error("Expected the location data itself without deseasonalize")
+	}
+}
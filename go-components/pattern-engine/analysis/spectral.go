@@ -0,0 +1,233 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// minSpectrumHours is the fewest hours of readings a spectrum is computed from: two days, so a
+// daily cycle is seen twice
+const minSpectrumHours = 48
+
+// minCycles is how many times the readings must cover a period for it to be reported
+const minCycles = 1.5
+
+// spectralVariables are the variables searched for periodicities
+var spectralVariables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }},
+	{"pressure", func(r models.WeatherPoint) float64 { return r.Pressure }},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }},
+	{"wind_speed", func(r models.WeatherPoint) float64 { return r.WindSpeed }},
+}
+
+// periodBands name the periodicities of weather, by their range of periods in hours
+var periodBands = []struct {
+	label    string
+	min, max float64
+}{
+	{"semidiurnal", 11, 13}, // Atmospheric pressure tide
+	{"diurnal", 20, 28},     // Daily heating and cooling
+	{"synoptic", 72, 120},   // Passing weather systems, every 3-5 days
+}
+
+// NewSpectralAnalyzer creates a new spectral analyzer with default settings
+func NewSpectralAnalyzer() *SpectralAnalyzer {
+	return &SpectralAnalyzer{DefaultThresholds().Spectrum}
+}
+
+// AnalyzeSpectrum finds the dominant periods of each variable from the power spectrum (FFT) of its
+// readings resampled to whole hours. Only periods the readings cover one and a half times are
+// reported, up to MaxPeaks per variable with at least MinPowerShare of the variable's power.
+func (sa *SpectralAnalyzer) AnalyzeSpectrum(locationData *models.LocationData) []models.Periodicity {
+	readings := locationData.Readings
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	periodicities := []models.Periodicity{}
+	for _, variable := range spectralVariables {
+		y := ResampleHourly(readings, variable.value)
+		if len(y) <= minSpectrumHours {
+			continue
+		}
+		periodicities = append(periodicities, sa.dominantPeriods(variable.name, y)...)
+	}
+	return periodicities
+}
+
+// spectrumPadding is how many times finer than the readings' own resolution the spectrum is
+// sampled, by zero-padding, so that peaks between two of those frequencies are located
+const spectrumPadding = 4
+
+// dominantPeriods returns the strongest peaks of the power spectrum of an hourly series
+func (sa *SpectralAnalyzer) dominantPeriods(variable string, y []float64) []models.Periodicity {
+	n := len(y)
+	size := spectrumPadding
+	for size < spectrumPadding*n {
+		size *= 2
+	}
+
+	// Remove the linear trend, which would otherwise swamp the low frequencies, and taper the
+	// ends with a Hann window
+	slope, intercept := fitLine(y)
+	x := make([]complex128, size)
+	for i, v := range y {
+		hann := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+		x[i] = complex((v-intercept-slope*float64(i))*hann, 0)
+	}
+	fft(x)
+
+	// One-sided power spectrum without the mean and Nyquist terms
+	half := size / 2
+	power := make([]float64, half)
+	var total float64
+	for k := 1; k < half; k++ {
+		power[k] = real(x[k])*real(x[k]) + imag(x[k])*imag(x[k])
+		total += power[k]
+	}
+	if total == 0 {
+		return nil
+	}
+	background := total / float64(half-1)
+	independent := float64(n / 2) // Frequencies the readings resolve without padding
+
+	var peaks []models.Periodicity
+	for k := 2; k < half-1; k++ {
+		period := float64(size) / float64(k)
+		if period > float64(n)/minCycles || power[k] <= power[k-1] || power[k] < power[k+1] {
+			continue // Too long to tell from the trend, or not a local maximum
+		}
+
+		// The peak's power is that of its whole lobe, down to the minimum on either side
+		lobe := power[k]
+		for i := k - 1; i >= 1 && power[i] <= power[i+1]; i-- {
+			lobe += power[i]
+		}
+		for i := k + 1; i < half && power[i] <= power[i-1]; i++ {
+			lobe += power[i]
+		}
+		share := lobe / total
+		if share < sa.MinPowerShare {
+			continue
+		}
+
+		peaks = append(peaks, models.Periodicity{
+			Variable:    variable,
+			PeriodHours: period,
+			Label:       periodLabel(period),
+			Power:       share,
+			// Probability that none of the independent frequencies of white noise with the same
+			// mean power would reach this peak (Fisher's test)
+			Confidence: math.Pow(1-math.Exp(-power[k]/background), independent),
+		})
+	}
+
+	sort.Slice(peaks, func(i, j int) bool { return peaks[i].Power > peaks[j].Power })
+	if len(peaks) > sa.MaxPeaks {
+		peaks = peaks[:sa.MaxPeaks]
+	}
+	return peaks
+}
+
+// periodLabel names the weather periodicity a period in hours belongs to, or "" for none
+func periodLabel(period float64) string {
+	for _, band := range periodBands {
+		if period >= band.min && period <= band.max {
+			return band.label
+		}
+	}
+	return ""
+}
+
+// fitLine returns the least-squares line through y against its index
+func fitLine(y []float64) (slope, intercept float64) {
+	n := float64(len(y))
+	var meanX, meanY float64
+	for i, v := range y {
+		meanX += float64(i) / n
+		meanY += v / n
+	}
+	var sxy, sxx float64
+	for i, v := range y {
+		sxy += (float64(i) - meanX) * (v - meanY)
+		sxx += (float64(i) - meanX) * (float64(i) - meanX)
+	}
+	if sxx == 0 {
+		return 0, meanY
+	}
+	slope = sxy / sxx
+	return slope, meanY - slope*meanX
+}
+
+// fft computes the discrete Fourier transform of x in place; len(x) must be a power of two
+func fft(x []complex128) {
+	n := len(x)
+	// Bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	// Butterflies
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := range size / 2 {
+				even, odd := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
+
+// ResampleHourly returns a variable at whole hours before the last reading of readings in
+// chronological order, oldest first, interpolating linearly between the readings around each hour
+func ResampleHourly(readings []models.WeatherPoint, value func(models.WeatherPoint) float64) []float64 {
+	if len(readings) < 2 {
+		return nil
+	}
+	first, last := readings[0].Timestamp, readings[len(readings)-1].Timestamp
+	hours := int(last.Sub(first) / time.Hour)
+	return resample(readings, value, last.Add(-time.Duration(hours)*time.Hour), hours+1)
+}
+
+// resample returns a variable of readings in chronological order at n hourly times from start,
+// interpolating linearly between the readings around each time; times outside the readings take
+// the nearest reading
+func resample(readings []models.WeatherPoint, value func(models.WeatherPoint) float64, start time.Time, n int) []float64 {
+	y := make([]float64, n)
+	j := 0 // Index of the reading at or before the current hour
+	for i := range y {
+		t := start.Add(time.Duration(i) * time.Hour)
+		for j+1 < len(readings) && !readings[j+1].Timestamp.After(t) {
+			j++
+		}
+		if j+1 == len(readings) {
+			y[i] = value(readings[j])
+			continue
+		}
+		before, after := readings[j], readings[j+1]
+		span := after.Timestamp.Sub(before.Timestamp).Seconds()
+		if span <= 0 {
+			y[i] = value(after)
+			continue
+		}
+		w := max(t.Sub(before.Timestamp).Seconds()/span, 0)
+		y[i] = value(before) + w*(value(after)-value(before))
+	}
+	return y
+}
@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// periodsOf returns the periodicities found for a variable
+func periodsOf(periodicities []models.Periodicity, variable string) []models.Periodicity {
+	var found []models.Periodicity
+	for _, p := range periodicities {
+		if p.Variable == variable {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// TestAnalyzeSpectrum tests that the diurnal, semidiurnal and synoptic periods are found and labelled
+func TestAnalyzeSpectrum(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 8 * 24 {
+		h := float64(i)
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: 10 + 5*math.Sin(2*math.Pi*h/24) + 0.3*math.Sin(h*1.7),
+			// A low passing every 4 days, with the twice-daily pressure tide on top
+			Pressure: 1012 + 8*math.Sin(2*math.Pi*h/96) + 1.5*math.Sin(2*math.Pi*h/12),
+			Humidity: 70,
+		})
+	}
+
+	periodicities := NewSpectralAnalyzer().AnalyzeSpectrum(&models.LocationData{Readings: readings})
+
+	temperature := periodsOf(periodicities, "temperature")
+	if len(temperature) == 0 || temperature[0].Label != "diurnal" || math.Abs(temperature[0].PeriodHours-24) > 1.5 {
+		t.Fatalf("Expected a dominant diurnal temperature period, got %+v", temperature)
+	}
+	if temperature[0].Power < 0.8 || temperature[0].Confidence < 0.99 {
+		t.Errorf("Expected the diurnal period to hold most of the power with high confidence, got %+v", temperature[0])
+	}
+
+	labels := map[string]bool{}
+	for _, p := range periodsOf(periodicities, "pressure") {
+		labels[p.Label] = true
+	}
+	if !labels["synoptic"] || !labels["semidiurnal"] {
+		t.Errorf("Expected synoptic and semidiurnal pressure periods, got %+v", periodsOf(periodicities, "pressure"))
+	}
+
+	if humidity := periodsOf(periodicities, "humidity"); len(humidity) != 0 {
+		t.Errorf("Expected no periods of constant humidity, got %+v", humidity)
+	}
+}
+
+// TestAnalyzeSpectrumShort tests that fewer than two days of readings give no periodicities
+func TestAnalyzeSpectrumShort(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	location := &models.LocationData{Readings: diurnalReadings(start, 36)}
+	if periodicities := NewSpectralAnalyzer().AnalyzeSpectrum(location); len(periodicities) != 0 {
+		t.Errorf("Expected no periodicities from 36 hours, got %+v", periodicities)
+	}
+}
+
+// TestAnalyzeSpectrumMaxPeaks tests that at most MaxPeaks periods are reported per variable
+func TestAnalyzeSpectrumMaxPeaks(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i := range 8 * 24 {
+		h := float64(i)
+		readings = append(readings, models.WeatherPoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Pressure:  1012 + 8*math.Sin(2*math.Pi*h/96) + 3*math.Sin(2*math.Pi*h/24) + 2*math.Sin(2*math.Pi*h/12),
+		})
+	}
+
+	analyzer := &SpectralAnalyzer{SpectrumThresholds{MaxPeaks: 1, MinPowerShare: 0.01}}
+	pressure := periodsOf(analyzer.AnalyzeSpectrum(&models.LocationData{Readings: readings}), "pressure")
+	if len(pressure) != 1 || pressure[0].Label != "synoptic" {
+		t.Errorf("Expected only the strongest, synoptic, pressure period, got %+v", pressure)
+	}
+}
+
+// TestResampleHourly tests that irregular readings are interpolated to whole hours before the last
+func TestResampleHourly(t *testing.T) {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	readings := []models.WeatherPoint{
+		{Timestamp: start, Temperature: 0},
+		{Timestamp: start.Add(30 * time.Minute), Temperature: 1},
+		{Timestamp: start.Add(3*time.Hour + 30*time.Minute), Temperature: 7},
+	}
+	got := ResampleHourly(readings, func(r models.WeatherPoint) float64 { return r.Temperature })
+	want := []float64{1, 3, 5, 7} // At 0:30, 1:30, 2:30 and 3:30
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+}
@@ -10,7 +10,8 @@ import (
 // NewStatisticalAnalyzer creates a new statistical analyzer with default settings
 func NewStatisticalAnalyzer() *StatisticalAnalyzer {
 	return &StatisticalAnalyzer{
-		ConfidenceLevel: 0.95, // 95% confidence interval
+		ConfidenceLevel:      0.95, // 95% confidence interval
+		StatisticsThresholds: DefaultThresholds().Statistics,
 	}
 }
 
@@ -43,9 +44,30 @@ func (sa *StatisticalAnalyzer) AnalyzeStatistics(locationData *models.LocationDa
 		stats = append(stats, *precipStats)
 	}
 
+	// Analyze wind direction statistics, which are circular
+	var wind windAccumulator
+	for _, r := range locationData.Readings {
+		wind.add(r)
+	}
+	if windStats := wind.statistics(sa.ConfidenceLevel); windStats != nil {
+		stats = append(stats, *windStats)
+	}
+
+	sa.addRollingStatistics(stats, locationData.Readings)
 	return stats
 }
 
+// addRollingStatistics adds the rolling statistics of readings to each variable's statistics
+func (sa *StatisticalAnalyzer) addRollingStatistics(stats []models.StatisticalData, readings []models.WeatherPoint) {
+	for i := range stats {
+		for _, variable := range statisticsVariables {
+			if variable.name == stats[i].Variable {
+				stats[i].Rolling = sa.RollingStatistics(readings, variable.value)
+			}
+		}
+	}
+}
+
 // analyzeVariableStats calculates statistical measures for a specific variable
 func (sa *StatisticalAnalyzer) analyzeVariableStats(variableName string, values []float64) *models.StatisticalData {
 	if len(values) < 2 {
@@ -73,13 +95,16 @@ func (sa *StatisticalAnalyzer) analyzeVariableStats(variableName string, values
 		median = sortedValues[n/2]
 	}
 
-	// Calculate standard deviation
-	var sumSquares float64
+	// Calculate standard deviation and the higher moments for skewness and kurtosis
+	var sumSquares, sumCubes, sumFourths float64
 	for _, v := range values {
 		diff := v - mean
 		sumSquares += diff * diff
+		sumCubes += diff * diff * diff
+		sumFourths += diff * diff * diff * diff
 	}
 	stdDev := math.Sqrt(sumSquares / float64(len(values)))
+	skewness, kurtosis := shape(len(values), sumSquares, sumCubes, sumFourths)
 
 	// Calculate min and max
 	min := sortedValues[0]
@@ -88,7 +113,7 @@ func (sa *StatisticalAnalyzer) analyzeVariableStats(variableName string, values
 	// Calculate trend strength based on standard deviation and sample size
 	trendStrength := calculateTrendStrengthFromStats(mean, stdDev, len(values))
 
-	return &models.StatisticalData{
+	stats := &models.StatisticalData{
 		Variable:        variableName,
 		Mean:            mean,
 		Median:          median,
@@ -98,7 +123,58 @@ func (sa *StatisticalAnalyzer) analyzeVariableStats(variableName string, values
 		SampleSize:      len(values),
 		ConfidenceLevel: sa.ConfidenceLevel,
 		TrendStrength:   trendStrength,
+		CiLower:         mean - sa.meanInterval(stdDev, len(values)),
+		CiUpper:         mean + sa.meanInterval(stdDev, len(values)),
+		Skewness:        skewness,
+		Kurtosis:        kurtosis,
+	}
+	setPercentiles(stats, sortedValues)
+	return stats
+}
+
+// meanInterval returns the half-width of the t-distribution confidence interval at
+// ConfidenceLevel of the mean of n values with population standard deviation stdDev
+func (sa *StatisticalAnalyzer) meanInterval(stdDev float64, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	sampleStdDev := stdDev * math.Sqrt(float64(n)/float64(n-1))
+	return tInterval(sampleStdDev/math.Sqrt(float64(n)), float64(n-1), sa.ConfidenceLevel)
+}
+
+// setPercentiles sets the percentiles and interquartile range of stats from sorted values
+func setPercentiles(stats *models.StatisticalData, sorted []float64) {
+	stats.P10 = percentile(sorted, 0.10)
+	stats.P25 = percentile(sorted, 0.25)
+	stats.P75 = percentile(sorted, 0.75)
+	stats.P90 = percentile(sorted, 0.90)
+	stats.IQR = stats.P75 - stats.P25
+}
+
+// percentile returns the p-th quantile (0-1) of sorted values, interpolating linearly between the
+// closest ranks, so that the 0.5 quantile is the median
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// shape returns the skewness and excess kurtosis of n values from the sums of the second, third and
+// fourth powers of their deviations from the mean; both are 0 for constant values
+func shape(n int, sumSquares, sumCubes, sumFourths float64) (skewness, kurtosis float64) {
+	variance := sumSquares / float64(n)
+	if n == 0 || variance < 1e-12 {
+		return 0, 0
 	}
+	skewness = sumCubes / float64(n) / math.Pow(variance, 1.5)
+	kurtosis = sumFourths/float64(n)/(variance*variance) - 3
+	return skewness, kurtosis
 }
 
 // getTemperatureValues extracts temperature values from readings
@@ -107,6 +107,10 @@ func TestCalculateStatisticsWithMultipleReadings(t *testing.T) {
 		if tempStat.SampleSize != 5 {
 			t.Errorf("Expected sample size 5, got %d", tempStat.SampleSize)
 		}
+		// Sample standard deviation 1.5811 over 5 readings with t(0.975, 4) = 2.7764
+		if abs(tempStat.CiLower-18.0367) > 1e-3 || abs(tempStat.CiUpper-21.9633) > 1e-3 {
+			t.Errorf("Expected a 95%% interval of [18.04, 21.96], got [%.4f, %.4f]", tempStat.CiLower, tempStat.CiUpper)
+		}
 	}
 
 	// Check pressure statistics
@@ -123,6 +127,35 @@ func TestCalculateStatisticsWithMultipleReadings(t *testing.T) {
 	}
 }
 
+// TestStatisticsDistributionShape tests the percentiles, interquartile range, skewness and kurtosis
+func TestStatisticsDistributionShape(t *testing.T) {
+	baseTime := time.Now()
+	temperatures := []float64{22, 18, 20, 19, 21, 20, 20, 20, 20, 20}
+	precipitation := []float64{0, 0, 9, 0, 0, 0, 1, 0, 0, 0} // Mostly dry with one downpour
+	var readings []models.WeatherPoint
+	for i := range temperatures {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:       baseTime.Add(time.Duration(i) * time.Hour),
+			Temperature:     temperatures[i],
+			PrecipitationMm: precipitation[i],
+		})
+	}
+	stats := NewStatisticalAnalyzer().AnalyzeStatistics(&models.LocationData{Readings: readings})
+
+	temp := findStatByVariable(stats, "temperature")
+	if abs(temp.P10-18.9) > 1e-9 || temp.P25 != 20 || temp.P75 != 20 || abs(temp.P90-21.1) > 1e-9 || temp.IQR != 0 {
+		t.Errorf("Unexpected temperature percentiles: %+v", temp)
+	}
+	if abs(temp.Skewness) > 1e-9 || temp.Kurtosis <= 0 {
+		t.Errorf("Expected symmetric, heavy-tailed temperatures, got skewness %v kurtosis %v", temp.Skewness, temp.Kurtosis)
+	}
+
+	precip := findStatByVariable(stats, "precipitation_mm")
+	if precip.Median != 0 || abs(precip.P90-1.8) > 1e-9 || precip.Skewness < 2 {
+		t.Errorf("Expected a long tail of precipitation, got %+v", precip)
+	}
+}
+
 // Helper function to find statistic by variable name
 func findStatByVariable(stats []models.StatisticalData, variable string) *models.StatisticalData {
 	for _, stat := range stats {
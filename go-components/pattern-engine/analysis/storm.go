@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// Weights of the signs of a storm in the storm score; they sum to 1
+const (
+	stormPressureWeight      = 0.3
+	stormWindWeight          = 0.3
+	stormPrecipitationWeight = 0.2
+	stormSymbolWeight        = 0.2
+)
+
+// NewStormDetector creates a new storm detector with default settings
+func NewStormDetector() *StormDetector {
+	return &StormDetector{StormThresholds: DefaultThresholds().Storm}
+}
+
+// AssessStormRisk scores each coming reading, from the current conditions (the latest reading at
+// or before now) on, for signs of a storm: pressure falling over TendencyWindow, strong or rising
+// wind, a high precipitation probability, and thunder or heavy precipitation symbols. The risk is
+// that of the highest score; its onset is the first reading scoring at least AlertScore. It
+// returns nil when the readings end more than TendencyWindow before now, as a history has no
+// storm to come.
+func (sd *StormDetector) AssessStormRisk(locationData *models.LocationData) *models.StormRisk {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	now := time.Now()
+	if sd.now != nil {
+		now = sd.now()
+	}
+	if readings[len(readings)-1].Timestamp.Before(now.Add(-sd.TendencyWindow)) {
+		return nil
+	}
+	current := max(sort.Search(len(readings), func(i int) bool { return readings[i].Timestamp.After(now) })-1, 0)
+
+	risk := &models.StormRisk{Score: -1}
+	for i := current; i < len(readings); i++ {
+		factors := sd.stormFactors(readings, i)
+		score := stormPressureWeight*factors[0].Score +
+			stormWindWeight*(factors[1].Score+factors[2].Score)/2 +
+			stormPrecipitationWeight*factors[3].Score +
+			stormSymbolWeight*factors[4].Score
+
+		if score >= sd.AlertScore && risk.Onset.IsZero() {
+			risk.Onset = readings[i].Timestamp
+			risk.LeadTimeHours = math.Max(risk.Onset.Sub(now).Hours(), 0)
+		}
+		if score > risk.Score {
+			risk.Score, risk.PeakTime, risk.Factors = score, readings[i].Timestamp, factors
+		}
+	}
+
+	risk.Level = "low"
+	if risk.Score >= sd.HighScore {
+		risk.Level = "high"
+	} else if risk.Score >= sd.AlertScore {
+		risk.Level = "moderate"
+	}
+	return risk
+}
+
+// stormFactors returns the signs of a storm at reading i of readings in chronological order:
+// the pressure fall rate, wind speed, wind speed rise, precipitation probability and symbol code.
+// The fall and rise are measured from the earliest reading within TendencyWindow before it.
+func (sd *StormDetector) stormFactors(readings []models.WeatherPoint, i int) []models.StormFactor {
+	r := readings[i]
+	start := sort.Search(i, func(j int) bool {
+		return !readings[j].Timestamp.Before(r.Timestamp.Add(-sd.TendencyWindow))
+	})
+	var fallRate, windRise float64
+	if hours := r.Timestamp.Sub(readings[start].Timestamp).Hours(); hours > 0 {
+		fallRate = (readings[start].Pressure - r.Pressure) / hours
+		// Scaled to the whole window, so a rise over part of it is not understated
+		windRise = (r.WindSpeed - readings[start].WindSpeed) * sd.TendencyWindow.Hours() / hours
+	}
+	wind := math.Max(r.WindSpeed, r.WindGust/1.5) // Gusts typically run half again above the mean wind
+	symbol := symbolStormValue(r.SymbolCode)
+
+	return []models.StormFactor{
+		{Factor: "pressure_fall_rate", Value: fallRate, Score: unitClamp(fallRate / sd.PressureFallRate)},
+		{Factor: "wind_speed", Value: wind, Score: unitClamp(wind / sd.WindSpeed)},
+		{Factor: "wind_speed_rise", Value: windRise, Score: unitClamp(windRise / sd.WindSpeedRise)},
+		{Factor: "precipitation_probability", Value: r.PrecipitationProbability, Score: unitClamp(r.PrecipitationProbability / 100)},
+		{Factor: "symbol_code", Value: symbol, Score: symbol},
+	}
+}
+
+// symbolStormValue returns 1 for a symbol code with thunder, such as "heavyrainandthunder", 0.5
+// for heavy precipitation, such as "heavysnowshowers_day", and 0 otherwise
+func symbolStormValue(code string) float64 {
+	switch {
+	case strings.Contains(code, "thunder"):
+		return 1
+	case strings.HasPrefix(code, "heavy"):
+		return 0.5
+	}
+	return 0
+}
+
+// unitClamp limits v to 0-1
+func unitClamp(v float64) float64 {
+	return math.Min(math.Max(v, 0), 1)
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestAssessStormRisk tests that a deepening low with strengthening wind and thunder ahead is
+// scored high with its lead time, and that calm weather and histories are not
+func TestAssessStormRisk(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	var storm, calm []models.WeatherPoint
+	for i := -6; i <= 12; i++ {
+		reading := models.WeatherPoint{
+			Timestamp:   now.Add(time.Duration(i) * time.Hour),
+			Pressure:    1012,
+			WindSpeed:   4,
+			SymbolCode:  "cloudy",
+			Temperature: 8,
+		}
+		calm = append(calm, reading)
+		if i > 3 { // From 4 hours ahead: the low deepens, the wind picks up, thunderstorms
+			reading.Pressure -= 2 * float64(i-3)
+			reading.WindSpeed += 3 * float64(i-3)
+			reading.PrecipitationProbability = 90
+			reading.SymbolCode = "heavyrainandthunder"
+		}
+		storm = append(storm, reading)
+	}
+	detector := NewStormDetector()
+	detector.now = func() time.Time { return now }
+
+	risk := detector.AssessStormRisk(&models.LocationData{Readings: storm})
+	if risk == nil || risk.Level != "high" || risk.Score < 0.9 {
+		t.Fatalf("Expected a high storm risk, got %+v", risk)
+	}
+	if !risk.Onset.Equal(now.Add(4*time.Hour)) || risk.LeadTimeHours != 4 {
+		t.Errorf("Expected the storm 4 hours ahead, got onset %v (lead %.1fh)", risk.Onset, risk.LeadTimeHours)
+	}
+	if f := risk.Factors[0]; f.Factor != "pressure_fall_rate" || math.Abs(f.Value-2) > 1e-9 || f.Score != 1 {
+		t.Errorf("Expected a 2 hPa/h fall at the peak, got %+v", f)
+	}
+
+	if risk := detector.AssessStormRisk(&models.LocationData{Readings: calm}); risk == nil || risk.Level != "low" || !risk.Onset.IsZero() {
+		t.Errorf("Expected a low storm risk in calm weather, got %+v", risk)
+	}
+
+	detector.now = func() time.Time { return now.Add(30 * 24 * time.Hour) }
+	if risk := detector.AssessStormRisk(&models.LocationData{Readings: storm}); risk != nil {
+		t.Errorf("Expected no storm risk from a history, got %+v", risk)
+	}
+}
+
+// TestSymbolStormValue tests the storm value of symbol codes
+func TestSymbolStormValue(t *testing.T) {
+	for code, want := range map[string]float64{
+		"rainshowersandthunder_day": 1,
+		"heavysleet":                0.5,
+		"lightrain":                 0,
+		"":                          0,
+	} {
+		if got := symbolStormValue(code); got != want {
+			t.Errorf("symbolStormValue(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
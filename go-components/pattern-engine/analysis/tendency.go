@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// tendencyPeriod is the period of the pressure tendency, as reported in synoptic observations
+const tendencyPeriod = 3 * time.Hour
+
+// maxTendencyGap is the longest gap between the readings pressure is interpolated between, so that
+// the middle of the period is measured rather than guessed
+const maxTendencyGap = 90 * time.Minute
+
+// steadyPressureChange is the change (hPa) under which pressure counts as steady: within the
+// 0.1 hPa precision of reports, rounded either way
+const steadyPressureChange = 0.2
+
+// pressureCharacteristics describe the WMO pressure tendency characteristics (code table 0200)
+var pressureCharacteristics = [...]string{
+	0: "increasing, then decreasing",
+	1: "increasing, then steady or increasing more slowly",
+	2: "increasing (steadily or unsteadily)",
+	3: "decreasing or steady, then increasing, or increasing more rapidly",
+	4: "steady",
+	5: "decreasing, then increasing",
+	6: "decreasing, then steady or decreasing more slowly",
+	7: "decreasing (steadily or unsteadily)",
+	8: "steady or increasing, then decreasing, or decreasing more rapidly",
+}
+
+// PressureTendency returns the pressure tendency over the 3 hours up to the latest of readings in
+// chronological order: the change and its WMO characteristic, from the shape of the two halves of
+// the period. It returns nil when the readings do not cover the period at least every 90 minutes.
+func PressureTendency(readings []models.WeatherPoint) *models.PressureTendency {
+	if len(readings) == 0 {
+		return nil
+	}
+	latest := readings[len(readings)-1]
+	start, ok := pressureAt(readings, latest.Timestamp.Add(-tendencyPeriod))
+	middle, ok2 := pressureAt(readings, latest.Timestamp.Add(-tendencyPeriod/2))
+	if !ok || !ok2 {
+		return nil
+	}
+
+	change := latest.Pressure - start
+	code := pressureCharacteristic(change, middle-start, latest.Pressure-middle)
+	return &models.PressureTendency{
+		Change:         math.Round(change*10) / 10,
+		Characteristic: code,
+		Description:    pressureCharacteristics[code],
+	}
+}
+
+// pressureCharacteristic returns the WMO characteristic code of a 3-hour pressure change whose
+// halves change by first and second
+func pressureCharacteristic(change, first, second float64) int {
+	rising := func(d float64) bool { return d >= steadyPressureChange }
+	falling := func(d float64) bool { return d <= -steadyPressureChange }
+
+	switch {
+	case !rising(change) && !falling(change): // The same as 3 hours ago
+		switch {
+		case rising(first) && falling(second):
+			return 0
+		case falling(first) && rising(second):
+			return 5
+		}
+		return 4
+	case rising(change):
+		switch {
+		case rising(first) && falling(second):
+			return 0
+		case !rising(first):
+			return 3
+		case !rising(second) || second <= first-steadyPressureChange:
+			return 1
+		case second >= first+steadyPressureChange:
+			return 3
+		}
+		return 2
+	default:
+		switch {
+		case falling(first) && rising(second):
+			return 5
+		case !falling(first):
+			return 8
+		case !falling(second) || second >= first+steadyPressureChange:
+			return 6
+		case second <= first-steadyPressureChange:
+			return 8
+		}
+		return 7
+	}
+}
+
+// pressureAt returns the pressure at t, interpolated between the readings either side of it
+// unless they are more than maxTendencyGap apart
+func pressureAt(readings []models.WeatherPoint, t time.Time) (float64, bool) {
+	i := sort.Search(len(readings), func(i int) bool { return !readings[i].Timestamp.Before(t) })
+	switch {
+	case i == len(readings):
+		return 0, false
+	case readings[i].Timestamp.Equal(t):
+		return readings[i].Pressure, true
+	case i == 0:
+		return 0, false
+	}
+	before, after := readings[i-1], readings[i]
+	gap := after.Timestamp.Sub(before.Timestamp)
+	if gap > maxTendencyGap {
+		return 0, false
+	}
+	f := float64(t.Sub(before.Timestamp)) / float64(gap)
+	return before.Pressure + f*(after.Pressure-before.Pressure), true
+}
@@ -0,0 +1,49 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestPressureTendency tests the WMO characteristic of the shapes of a 3-hour pressure change
+func TestPressureTendency(t *testing.T) {
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name      string
+		pressures []float64 // Hourly, the last 4 spanning the period
+		code      int
+		change    float64
+	}{
+		{"Rising then falling, higher", []float64{1010, 1012, 1013, 1012}, 0, 2},
+		{"Rising then steady", []float64{1010, 1012, 1013, 1013}, 1, 3},
+		{"Rising steadily", []float64{1010, 1011, 1012, 1013}, 2, 3},
+		{"Falling then rising, higher", []float64{1010, 1009, 1010, 1012}, 3, 2},
+		{"Rising more rapidly", []float64{1010, 1010.5, 1011.5, 1013}, 3, 3},
+		{"Steady", []float64{1010, 1010.1, 1010, 1010}, 4, 0},
+		{"Falling then rising, same", []float64{1010, 1009, 1008.5, 1010}, 5, 0},
+		{"Falling then steady", []float64{1013, 1011, 1010, 1010}, 6, -3},
+		{"Falling steadily", []float64{1013, 1012, 1011, 1010}, 7, -3},
+		{"Falling more rapidly", []float64{1013, 1012.5, 1011.5, 1010}, 8, -3},
+		{"Rising then falling, lower", []float64{1012, 1013, 1012, 1010}, 8, -2},
+		{"Hours before the period", []float64{1000, 1005, 1013, 1012, 1011, 1010}, 7, -3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var readings []models.WeatherPoint
+			for i, p := range tt.pressures {
+				readings = append(readings, models.WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Pressure: p})
+			}
+			tendency := PressureTendency(readings)
+			if tendency == nil || tendency.Characteristic != tt.code || tendency.Change != tt.change ||
+				tendency.Description != pressureCharacteristics[tt.code] {
+				t.Errorf("Expected characteristic %d with a change of %.1f hPa, got %+v", tt.code, tt.change, tendency)
+			}
+		})
+	}
+
+	sparse := []models.WeatherPoint{{Timestamp: start, Pressure: 1010}, {Timestamp: start.Add(3 * time.Hour), Pressure: 1013}}
+	if tendency := PressureTendency(sparse); tendency != nil {
+		t.Errorf("Expected no tendency from readings 3 hours apart, got %+v", tendency)
+	}
+}
@@ -0,0 +1,457 @@
+package analysis
+
+import "time"
+
+// Thresholds are the tunable limits of the built-in analyzers, read from the "thresholds" key
+// of the "analysis" config section
+type Thresholds struct {
+	Trends        TrendThresholds         `json:"trends"`
+	Anomalies     AnomalyThresholds       `json:"anomalies"`
+	Patterns      PatternThresholds       `json:"patterns"`
+	Multivariate  MultivariateThresholds  `json:"multivariate"`
+	Statistics    StatisticsThresholds    `json:"statistics"`
+	Seasonality   SeasonalityThresholds   `json:"seasonality"`
+	Spectrum      SpectrumThresholds      `json:"spectrum"`
+	Correlations  CorrelationThresholds   `json:"correlations"`
+	Regional      RegionalThresholds      `json:"regional"`
+	Storm         StormThresholds         `json:"storm"`
+	Fog           FogThresholds           `json:"fog"`
+	Extremes      ExtremeThresholds       `json:"extremes"`
+	Wind          WindThresholds          `json:"wind"`
+	Precipitation PrecipitationThresholds `json:"precipitation"`
+	Agro          AgroThresholds          `json:"agro"`
+	Energy        EnergyThresholds        `json:"energy"`
+	RoadIcing     RoadIcingThresholds     `json:"road_icing"`
+	Comfort       ComfortThresholds       `json:"comfort"`
+	DataQuality   DataQualityThresholds   `json:"data_quality"`
+}
+
+// TrendThresholds configure the trend analyzer; rates are changes per hour
+type TrendThresholds struct {
+	Method                 string  `json:"method"`              // TrendMethodLeastSquares or TrendMethodMannKendall
+	MinReadingsForAnalysis int     `json:"min_readings"`        // Readings needed to look for trends
+	MinTrendSignificance   float64 `json:"min_significance"`    // Rates below this are reported as stable
+	TemperatureRate        float64 `json:"temperature_rate"`    // °C/h above which temperature is rising or falling
+	PressureRate           float64 `json:"pressure_rate"`       // hPa/h above which pressure is rising or falling
+	HumidityRate           float64 `json:"humidity_rate"`       // %/h above which humidity is increasing or decreasing
+	WindSpeedRate          float64 `json:"wind_speed_rate"`     // m/s per hour above which wind is increasing or decreasing
+	WindDirectionRate      float64 `json:"wind_direction_rate"` // Degrees per hour above which wind is veering (turning clockwise) or backing
+	ConfidenceLevel        float64 `json:"confidence_level"`    // Level of the confidence intervals of the rates (e.g. 0.95)
+	Alpha                  float64 `json:"alpha"`               // Significance level a rising or falling trend must reach (e.g. 0.05)
+}
+
+// AnomalyThresholds configure the anomaly detector; factors are multiples of the standard deviation
+type AnomalyThresholds struct {
+	Method                 string            `json:"method"`                   // AnomalyMethodStdDev, AnomalyMethodMAD or AnomalyMethodIQR
+	Methods                map[string]string `json:"methods"`                  // Method of particular variables, e.g. {"wind_speed": "iqr"}
+	IQRFactor              float64           `json:"iqr_factor"`               // Interquartile ranges beyond the quartiles that is an anomaly (iqr)
+	AnomalyThresholdFactor float64           `json:"threshold_factor"`         // Deviation from the mean that is an anomaly
+	MinReadingsForBaseline int               `json:"min_baseline_readings"`    // Readings needed to establish a baseline
+	BaselineWindow         time.Duration     `json:"baseline_window"`          // Period before each reading its baseline covers; 0 for the whole series
+	ModerateSeverityFactor float64           `json:"moderate_severity_factor"` // Deviation above which an anomaly is moderate
+	HighSeverityFactor     float64           `json:"high_severity_factor"`     // Deviation above which an anomaly is high
+	PressureChangeWindow   time.Duration     `json:"pressure_change_window"`   // Period a rapid pressure change happens within
+	PressureChange         float64           `json:"pressure_change"`          // hPa change within the window that is an anomaly
+	HighPressureChange     float64           `json:"high_pressure_change"`     // hPa change within the window that is high severity
+}
+
+// MultivariateThresholds configure the multivariate anomaly detector
+type MultivariateThresholds struct {
+	MinReadings int     `json:"min_readings"` // Readings needed to estimate how the variables vary together
+	Alpha       float64 `json:"alpha"`        // Significance level of an anomaly; high severity below a tenth of it
+}
+
+// PatternThresholds configure the pattern recognizer
+type PatternThresholds struct {
+	MinPatternConfidence      float64       `json:"min_confidence"`             // Minimum confidence to report a pattern
+	TemperatureStep           float64       `json:"temperature_step"`           // °C change between readings that counts as warming or cooling
+	HighPressure              float64       `json:"high_pressure"`              // hPa above which a reading is high pressure
+	HighPressureMean          float64       `json:"high_pressure_mean"`         // Mean hPa a high pressure system must exceed
+	LowPressure               float64       `json:"low_pressure"`               // hPa below which a reading is low pressure
+	LowPressureMean           float64       `json:"low_pressure_mean"`          // Mean hPa a low pressure system must stay under
+	PrecipitationMm           float64       `json:"precipitation_mm"`           // mm above which a reading has precipitation
+	PrecipitationProbability  float64       `json:"precipitation_probability"`  // Probability (%) above which a reading has precipitation
+	ConsistentPrecipitation   float64       `json:"consistent_precipitation"`   // Share of wet readings for consistent precipitation
+	IntermittentPrecipitation float64       `json:"intermittent_precipitation"` // Share of wet readings for intermittent precipitation
+	FrontWindow               time.Duration `json:"front_window"`               // Period either side of a front passage its signs are looked for in
+	FrontPressureFall         float64       `json:"front_pressure_fall"`        // hPa pressure must fall within the window before a front
+	FrontPressureRise         float64       `json:"front_pressure_rise"`        // hPa pressure must rise within the window after a front
+	FrontWindShift            float64       `json:"front_wind_shift"`           // Degrees the wind veers across a front
+	FrontTemperatureChange    float64       `json:"front_temperature_change"`   // °C the temperature changes across a front
+	FrontHumiditySpike        float64       `json:"front_humidity_spike"`       // Humidity (%) rise at a front above that before it
+}
+
+// StatisticsThresholds configure the statistical analyzer
+type StatisticsThresholds struct {
+	RollingWindowHours []int `json:"rolling_window_hours"` // Windows of the rolling statistics, in hours
+}
+
+// SeasonalityThresholds configure the seasonality detector
+type SeasonalityThresholds struct {
+	MinSpanHours  float64 `json:"min_span_hours"` // Hours the readings must span to look for a daily cycle
+	MinStrength   float64 `json:"min_strength"`   // Share of the variation a cycle must explain to be reported
+	Deseasonalize bool    `json:"deseasonalize"`  // Remove detected cycles from readings before trend, anomaly and pattern analysis
+}
+
+// SpectrumThresholds configure the spectral analyzer
+type SpectrumThresholds struct {
+	MaxPeaks      int     `json:"max_peaks"`       // Most periods reported per variable
+	MinPowerShare float64 `json:"min_power_share"` // Share of a variable's spectral power a period must have
+}
+
+// CorrelationThresholds configure the lag correlator
+type CorrelationThresholds struct {
+	MaxLagHours int `json:"max_lag_hours"` // Longest lead or lag looked for between two variables
+}
+
+// RegionalThresholds configure the regional analyzer
+type RegionalThresholds struct {
+	MaxLagHours    int     `json:"max_lag_hours"`   // Longest delay looked for between two locations
+	MinCorrelation float64 `json:"min_correlation"` // Correlation (either sign) a pair of locations must reach to be reported
+}
+
+// StormThresholds configure the storm detector
+type StormThresholds struct {
+	TendencyWindow   time.Duration `json:"tendency_window"`    // Period pressure falls and wind rises over
+	PressureFallRate float64       `json:"pressure_fall_rate"` // hPa/h fall over the window that scores fully
+	WindSpeed        float64       `json:"wind_speed"`         // m/s (sustained, or gusts over 1.5) that scores fully
+	WindSpeedRise    float64       `json:"wind_speed_rise"`    // m/s rise over the window that scores fully
+	AlertScore       float64       `json:"alert_score"`        // Score from which a storm is approaching (moderate risk)
+	HighScore        float64       `json:"high_score"`         // Score from which the risk is high
+}
+
+// FogThresholds configure the fog detector; hours are local solar time
+type FogThresholds struct {
+	MaxSpread      float64 `json:"max_spread"`       // °C between temperature and dew point under which fog can form
+	MinHumidity    float64 `json:"min_humidity"`     // Humidity (%) fog needs
+	MaxWindSpeed   float64 `json:"max_wind_speed"`   // m/s above which the air is mixed too well for fog
+	NightStartHour float64 `json:"night_start_hour"` // Hour from which the night's cooling can form fog
+	NightEndHour   float64 `json:"night_end_hour"`   // Hour by which the morning sun has usually lifted it
+}
+
+// ExtremeThresholds configure the extreme event detector
+type ExtremeThresholds struct {
+	MinDays         int     `json:"min_days"`          // Consecutive days a heat wave or cold snap lasts at least
+	HeatPercentile  float64 `json:"heat_percentile"`   // Percentile (0-100) of the daily highs a heat wave's highs are above
+	ColdPercentile  float64 `json:"cold_percentile"`   // Percentile (0-100) of the daily highs a cold snap's highs are below
+	MinBaselineDays int     `json:"min_baseline_days"` // Days needed for the percentiles
+}
+
+// WindThresholds configure the wind classifier
+type WindThresholds struct {
+	HighWindSpeed float64 `json:"high_wind_speed"` // m/s of sustained wind from which the wind is high
+	HighGustSpeed float64 `json:"high_gust_speed"` // m/s of gusts from which the wind is high
+}
+
+// PrecipitationThresholds configure the precipitation accumulator; amounts are mm in an hour
+type PrecipitationThresholds struct {
+	WetHour      float64 `json:"wet_hour"`      // Amount from which an hour is wet
+	ModerateRate float64 `json:"moderate_rate"` // Amount from which precipitation is moderate rather than drizzle
+	HeavyRate    float64 `json:"heavy_rate"`    // Amount from which precipitation is heavy
+}
+
+// AgroThresholds configure the agricultural calculator; temperatures are °C
+type AgroThresholds struct {
+	BaseTemperature  float64 `json:"base_temperature"`  // Temperature above which crops grow, for growing degree days
+	UpperTemperature float64 `json:"upper_temperature"` // Temperature above which growth does not speed up further
+	ChillMin         float64 `json:"chill_min"`         // Lowest temperature of a chill hour
+	ChillMax         float64 `json:"chill_max"`         // Highest temperature of a chill hour
+	FrostTemperature float64 `json:"frost_temperature"` // Low at or below which a day has frost
+}
+
+// EnergyThresholds configure the energy estimator; wind speeds are m/s at the hub
+type EnergyThresholds struct {
+	PerformanceRatio float64 `json:"performance_ratio"` // Share of the panels' rated output left after losses
+	CutInSpeed       float64 `json:"cut_in_speed"`      // Wind speed from which the turbine generates
+	RatedSpeed       float64 `json:"rated_speed"`       // Wind speed from which the turbine generates its rated power
+	CutOutSpeed      float64 `json:"cut_out_speed"`     // Wind speed from which the turbine shuts down
+	HubHeight        float64 `json:"hub_height"`        // m above ground
+	ShearExponent    float64 `json:"shear_exponent"`    // Power-law exponent of wind speed over height
+}
+
+// RoadIcingThresholds configure the road icing detector; temperatures are °C
+type RoadIcingThresholds struct {
+	MaxTemperature      float64       `json:"max_temperature"`      // Road temperature from which ice cannot form
+	NightCooling        float64       `json:"night_cooling"`        // How far a road cools below the air on a clear night
+	PrecipitationWindow time.Duration `json:"precipitation_window"` // Period recent precipitation is summed over
+	WetPrecipitation    float64       `json:"wet_precipitation"`    // mm over the window that leaves the road fully wet
+	MinHumidity         float64       `json:"min_humidity"`         // Humidity (%) from which frost can deposit on the road
+	AlertScore          float64       `json:"alert_score"`          // Score from which the risk is moderate
+	HighScore           float64       `json:"high_score"`           // Score from which the risk is high
+}
+
+// ComfortThresholds configure the comfort assessor; temperatures are °C as felt
+type ComfortThresholds struct {
+	ComfortMin      float64       `json:"comfort_min"`       // Apparent temperature from which it is comfortable
+	ComfortMax      float64       `json:"comfort_max"`       // Apparent temperature up to which it is comfortable
+	MuggyHumidex    float64       `json:"muggy_humidex"`     // Humidex from which it is muggy
+	BitterWindChill float64       `json:"bitter_wind_chill"` // Wind chill at or below which it is bitter
+	TrendWindow     time.Duration `json:"trend_window"`      // Period ahead the comfort trend looks over
+	TrendChange     float64       `json:"trend_change"`      // Change in discomfort from which comfort is improving or worsening
+}
+
+// DataQualityThresholds configure the data quality checker
+type DataQualityThresholds struct {
+	GapFactor     float64       `json:"gap_factor"`     // Intervals longer than this many cadences are gaps
+	StuckReadings int           `json:"stuck_readings"` // Readings in a row with the same value of a variable that are stuck
+	StaleAfter    time.Duration `json:"stale_after"`    // Age of the latest reading from which the data is stale
+}
+
+// DefaultThresholds returns the thresholds the analyzers use without a config file
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Trends: TrendThresholds{
+			Method:                 TrendMethodLeastSquares,
+			MinReadingsForAnalysis: 3,
+			MinTrendSignificance:   0.1, // minimum change rate to consider a trend
+			TemperatureRate:        0.1,
+			PressureRate:           0.5,
+			HumidityRate:           1.0,
+			WindSpeedRate:          0.1,
+			WindDirectionRate:      5,
+			ConfidenceLevel:        0.95,
+			Alpha:                  0.05,
+		},
+		Anomalies: AnomalyThresholds{
+			Method:                 AnomalyMethodStdDev,
+			IQRFactor:              1.5, // Tukey's fences
+			AnomalyThresholdFactor: 2.0, // 2 standard deviations from mean
+			MinReadingsForBaseline: 5,   // minimum readings for baseline calculation
+			ModerateSeverityFactor: 2.0,
+			HighSeverityFactor:     3.0,
+			PressureChangeWindow:   4 * time.Hour,
+			PressureChange:         3.0, // 3 hPa change within 4 hours
+			HighPressureChange:     5.0,
+		},
+		Multivariate: MultivariateThresholds{
+			MinReadings: 24,
+			Alpha:       0.001,
+		},
+		Patterns: PatternThresholds{
+			MinPatternConfidence:      0.6,    // minimum 60% confidence
+			TemperatureStep:           0.5,    // threshold for significant warming or cooling
+			HighPressure:              1020.0, // typical high pressure threshold
+			HighPressureMean:          1015.0,
+			LowPressure:               1000.0, // typical low pressure threshold
+			LowPressureMean:           1010.0,
+			PrecipitationMm:           0.1,
+			PrecipitationProbability:  50,
+			ConsistentPrecipitation:   0.7,
+			IntermittentPrecipitation: 0.4,
+			FrontWindow:               6 * time.Hour,
+			FrontPressureFall:         2.0,
+			FrontPressureRise:         1.0,
+			FrontWindShift:            30,
+			FrontTemperatureChange:    2.0,
+			FrontHumiditySpike:        10,
+		},
+		Statistics: StatisticsThresholds{
+			RollingWindowHours: []int{3, 6, 24},
+		},
+		Seasonality: SeasonalityThresholds{
+			MinSpanHours:  24,
+			MinStrength:   0.5,
+			Deseasonalize: true,
+		},
+		Correlations: CorrelationThresholds{
+			MaxLagHours: 12,
+		},
+		Spectrum: SpectrumThresholds{
+			MaxPeaks:      3,
+			MinPowerShare: 0.01, // the pressure tide is small beside passing weather systems
+		},
+		Regional: RegionalThresholds{
+			MaxLagHours:    12, // fronts cross a few hundred km in that time
+			MinCorrelation: 0.5,
+		},
+		Storm: StormThresholds{
+			TendencyWindow:   3 * time.Hour,
+			PressureFallRate: 1.5,  // 4.5 hPa in 3 hours
+			WindSpeed:        20.0, // storm force on the Beaufort scale is 24.5 m/s
+			WindSpeedRise:    8.0,
+			AlertScore:       0.5,
+			HighScore:        0.75,
+		},
+		Fog: FogThresholds{
+			MaxSpread:      2.0,
+			MinHumidity:    90,
+			MaxWindSpeed:   3.0, // light winds
+			NightStartHour: 18,
+			NightEndHour:   10,
+		},
+		Extremes: ExtremeThresholds{
+			MinDays:         3,
+			HeatPercentile:  90,
+			ColdPercentile:  10,
+			MinBaselineDays: 10,
+		},
+		Wind: WindThresholds{
+			HighWindSpeed: 17.2, // Gale, force 8 on the Beaufort scale
+			HighGustSpeed: 25.0,
+		},
+		Precipitation: PrecipitationThresholds{
+			WetHour:      0.1, // The smallest amount reported
+			ModerateRate: 2.5, // American Meteorological Society rain intensities
+			HeavyRate:    7.6,
+		},
+		Agro: AgroThresholds{
+			BaseTemperature:  10, // Maize and most warm-season crops
+			UpperTemperature: 30,
+			ChillMin:         0, // Chill hours between 0 and 7.2°C (45°F)
+			ChillMax:         7.2,
+			FrostTemperature: 0,
+		},
+		Energy: EnergyThresholds{
+			PerformanceRatio: 0.8,
+			CutInSpeed:       3, // A typical utility-scale turbine
+			RatedSpeed:       12,
+			CutOutSpeed:      25,
+			HubHeight:        100,
+			ShearExponent:    1.0 / 7, // Neutral stability over open land
+		},
+		RoadIcing: RoadIcingThresholds{
+			MaxTemperature:      3,
+			NightCooling:        2,
+			PrecipitationWindow: 6 * time.Hour,
+			WetPrecipitation:    1,
+			MinHumidity:         80,
+			AlertScore:          0.5,
+			HighScore:           0.75,
+		},
+		Comfort: ComfortThresholds{
+			ComfortMin:      18,
+			ComfortMax:      24,
+			MuggyHumidex:    30,  // Environment Canada: some discomfort
+			BitterWindChill: -10, // Environment Canada: uncomfortable
+			TrendWindow:     6 * time.Hour,
+			TrendChange:     2,
+		},
+		DataQuality: DataQualityThresholds{
+			GapFactor:     1.5,
+			StuckReadings: 6,
+			StaleAfter:    3 * time.Hour,
+		},
+	}
+}
+
+// NewRegistryWithThresholds returns a registry with the built-in analyzers using thresholds
+func NewRegistryWithThresholds(thresholds Thresholds) *Registry {
+	return &Registry{analyzers: []Analyzer{
+		&TrendAnalyzer{thresholds.Trends},
+		&AnomalyDetector{thresholds.Anomalies},
+		&PatternRecognizer{thresholds.Patterns},
+		&StatisticalAnalyzer{ConfidenceLevel: 0.95, StatisticsThresholds: thresholds.Statistics},
+		&SeasonalityDetector{thresholds.Seasonality},
+		&SpectralAnalyzer{thresholds.Spectrum},
+		&MultivariateDetector{thresholds.Multivariate},
+		&LagCorrelator{thresholds.Correlations},
+		&StormDetector{StormThresholds: thresholds.Storm},
+		&FogDetector{thresholds.Fog},
+		&ExtremeEventDetector{thresholds.Extremes},
+		&WindClassifier{thresholds.Wind},
+		&PrecipitationAccumulator{PrecipitationThresholds: thresholds.Precipitation},
+		&AgroCalculator{thresholds.Agro},
+		&EnergyEstimator{thresholds.Energy},
+		&RoadIcingDetector{RoadIcingThresholds: thresholds.RoadIcing},
+		&ComfortAssessor{ComfortThresholds: thresholds.Comfort},
+		&DataQualityChecker{DataQualityThresholds: thresholds.DataQuality},
+	}}
+}
+
+// Tunable is implemented by analyzers with thresholds, so they can be rerun with the thresholds
+// of a location profile
+type Tunable interface {
+	Analyzer
+	// WithThresholds returns a copy of the analyzer using its section of thresholds
+	WithThresholds(thresholds Thresholds) Analyzer
+}
+
+// WithThresholds implements Tunable
+func (ta *TrendAnalyzer) WithThresholds(thresholds Thresholds) Analyzer {
+	return &TrendAnalyzer{thresholds.Trends}
+}
+
+// WithThresholds implements Tunable
+func (ad *AnomalyDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &AnomalyDetector{thresholds.Anomalies}
+}
+
+// WithThresholds implements Tunable
+func (pr *PatternRecognizer) WithThresholds(thresholds Thresholds) Analyzer {
+	return &PatternRecognizer{thresholds.Patterns}
+}
+
+// WithThresholds implements Tunable
+func (md *MultivariateDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &MultivariateDetector{thresholds.Multivariate}
+}
+
+// WithThresholds implements Tunable
+func (lc *LagCorrelator) WithThresholds(thresholds Thresholds) Analyzer {
+	return &LagCorrelator{thresholds.Correlations}
+}
+
+// WithThresholds implements Tunable
+func (sd *StormDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &StormDetector{StormThresholds: thresholds.Storm, now: sd.now}
+}
+
+// WithThresholds implements Tunable
+func (fd *FogDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &FogDetector{thresholds.Fog}
+}
+
+// WithThresholds implements Tunable
+func (dq *DataQualityChecker) WithThresholds(thresholds Thresholds) Analyzer {
+	return &DataQualityChecker{DataQualityThresholds: thresholds.DataQuality, now: dq.now}
+}
+
+// WithThresholds implements Tunable
+func (ca *ComfortAssessor) WithThresholds(thresholds Thresholds) Analyzer {
+	return &ComfortAssessor{ComfortThresholds: thresholds.Comfort, now: ca.now}
+}
+
+// WithThresholds implements Tunable
+func (rd *RoadIcingDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &RoadIcingDetector{RoadIcingThresholds: thresholds.RoadIcing, now: rd.now}
+}
+
+// WithThresholds implements Tunable
+func (ee *EnergyEstimator) WithThresholds(thresholds Thresholds) Analyzer {
+	return &EnergyEstimator{thresholds.Energy}
+}
+
+// WithThresholds implements Tunable
+func (ac *AgroCalculator) WithThresholds(thresholds Thresholds) Analyzer {
+	return &AgroCalculator{thresholds.Agro}
+}
+
+// WithThresholds implements Tunable
+func (pa *PrecipitationAccumulator) WithThresholds(thresholds Thresholds) Analyzer {
+	return &PrecipitationAccumulator{PrecipitationThresholds: thresholds.Precipitation, now: pa.now}
+}
+
+// WithThresholds implements Tunable
+func (wc *WindClassifier) WithThresholds(thresholds Thresholds) Analyzer {
+	return &WindClassifier{thresholds.Wind}
+}
+
+// WithThresholds implements Tunable
+func (ed *ExtremeEventDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &ExtremeEventDetector{thresholds.Extremes}
+}
+
+// WithThresholds implements Tunable
+func (sa *StatisticalAnalyzer) WithThresholds(thresholds Thresholds) Analyzer {
+	return &StatisticalAnalyzer{ConfidenceLevel: sa.ConfidenceLevel, StatisticsThresholds: thresholds.Statistics}
+}
+
+// WithThresholds implements Tunable
+func (sa *SeasonalityDetector) WithThresholds(thresholds Thresholds) Analyzer {
+	return &SeasonalityDetector{thresholds.Seasonality}
+}
+
+// WithThresholds implements Tunable
+func (sa *SpectralAnalyzer) WithThresholds(thresholds Thresholds) Analyzer {
+	return &SpectralAnalyzer{thresholds.Spectrum}
+}
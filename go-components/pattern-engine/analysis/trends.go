@@ -10,12 +10,30 @@ import (
 
 // NewTrendAnalyzer creates a new trend analyzer with default settings
 func NewTrendAnalyzer() *TrendAnalyzer {
-	return &TrendAnalyzer{
-		MinReadingsForAnalysis: 3,
-		MinTrendSignificance:   0.1, // minimum change rate to consider a trend
-	}
+	return &TrendAnalyzer{DefaultThresholds().Trends}
+}
+
+// trendVariable is a variable AnalyzeTrends reports, with its rate threshold and the words for a
+// rising and falling trend
+type trendVariable struct {
+	name            string
+	value           func(models.WeatherPoint) float64
+	rate            func(TrendThresholds) float64
+	rising, falling string
+}
+
+// trendVariables are the variables AnalyzeTrends reports, in order
+var trendVariables = []trendVariable{
+	{"temperature", func(wp models.WeatherPoint) float64 { return wp.Temperature }, func(t TrendThresholds) float64 { return t.TemperatureRate }, "rising", "falling"},
+	{"pressure", func(wp models.WeatherPoint) float64 { return wp.Pressure }, func(t TrendThresholds) float64 { return t.PressureRate }, "rising", "falling"},
+	{"humidity", func(wp models.WeatherPoint) float64 { return wp.Humidity }, func(t TrendThresholds) float64 { return t.HumidityRate }, "increasing", "decreasing"},
+	{"wind_speed", func(wp models.WeatherPoint) float64 { return wp.WindSpeed }, func(t TrendThresholds) float64 { return t.WindSpeedRate }, "increasing", "decreasing"},
 }
 
+// windDirectionTrend is the trend of the wind direction, reported over the unwrapped directions of
+// the readings with wind (see unwrapDirections)
+var windDirectionTrend = trendVariable{"wind_direction", func(wp models.WeatherPoint) float64 { return wp.WindDirection }, func(t TrendThresholds) float64 { return t.WindDirectionRate }, "veering", "backing"}
+
 // AnalyzeTrends analyzes trends in weather data (both historical and forecast)
 func (ta *TrendAnalyzer) AnalyzeTrends(locationData *models.LocationData) []models.Trend {
 	if len(locationData.Readings) < ta.MinReadingsForAnalysis {
@@ -28,183 +46,98 @@ func (ta *TrendAnalyzer) AnalyzeTrends(locationData *models.LocationData) []mode
 	})
 
 	var trends []models.Trend
-
-	// Analyze temperature trend
-	if tempTrend := ta.analyzeTemperatureTrend(locationData.Readings); tempTrend != nil {
-		trends = append(trends, *tempTrend)
-	}
-
-	// Analyze pressure trend
-	if pressureTrend := ta.analyzePressureTrend(locationData.Readings); pressureTrend != nil {
-		trends = append(trends, *pressureTrend)
-	}
-
-	// Analyze humidity trend
-	if humidityTrend := ta.analyzeHumidityTrend(locationData.Readings); humidityTrend != nil {
-		trends = append(trends, *humidityTrend)
+	for _, variable := range trendVariables {
+		if trend := ta.analyzeTrend(locationData.Readings, variable); trend != nil {
+			trends = append(trends, *trend)
+		}
 	}
-
-	// Analyze wind speed trend
-	if windSpeedTrend := ta.analyzeWindSpeedTrend(locationData.Readings); windSpeedTrend != nil {
-		trends = append(trends, *windSpeedTrend)
+	if windy := unwrapDirections(locationData.Readings); len(windy) >= ta.MinReadingsForAnalysis {
+		if trend := ta.analyzeTrend(windy, windDirectionTrend); trend != nil {
+			trends = append(trends, *trend)
+		}
 	}
 
 	return trends
 }
 
-// analyzeTemperatureTrend analyzes temperature trends
-func (ta *TrendAnalyzer) analyzeTemperatureTrend(readings []models.WeatherPoint) *models.Trend {
+// analyzeTrend analyzes the linear trend of a variable. It is rising or falling when its rate of
+// change exceeds the variable's rate, and the slope differs from zero at significance level Alpha;
+// otherwise it is stable.
+func (ta *TrendAnalyzer) analyzeTrend(readings []models.WeatherPoint, variable trendVariable) *models.Trend {
 	if len(readings) < 2 {
 		return nil
 	}
 
-	// Calculate linear regression for temperature trend
-	slope, confidence := calculateLinearTrend(readings, func(wp models.WeatherPoint) float64 {
-		return wp.Temperature
-	})
-
-	if math.Abs(slope) < ta.MinTrendSignificance {
-		return &models.Trend{
-			Variable:   "temperature",
-			Trend:      "stable",
-			ChangeRate: slope,
-			Confidence: confidence,
-			Duration:   calculateDuration(readings),
-		}
-	}
-
-	trendType := "stable"
-	if slope > 0.1 {
-		trendType = "rising"
-	} else if slope < -0.1 {
-		trendType = "falling"
-	}
-
-	return &models.Trend{
-		Variable:   "temperature",
-		Trend:      trendType,
-		ChangeRate: slope,
-		Confidence: confidence,
+	// Calculate linear regression for the trend
+	fit := regress(readings, variable.value)
+	margin := tInterval(fit.stdErr, float64(fit.n-2), ta.ConfidenceLevel)
+	trend := &models.Trend{
+		Variable:   variable.name,
+		Method:     TrendMethodLeastSquares,
+		ChangeRate: fit.slope,
+		CiLower:    fit.slope - margin,
+		CiUpper:    fit.slope + margin,
+		PValue:     fit.pValue(),
+		RSquared:   fit.correlation * fit.correlation,
 		Duration:   calculateDuration(readings),
 	}
-}
-
-// analyzePressureTrend analyzes pressure trends
-func (ta *TrendAnalyzer) analyzePressureTrend(readings []models.WeatherPoint) *models.Trend {
-	if len(readings) < 2 {
-		return nil
+	if ta.Method == TrendMethodMannKendall {
+		kendall := mannKendall(readings, variable.value, ta.ConfidenceLevel)
+		trend.Method = TrendMethodMannKendall
+		trend.ChangeRate, trend.CiLower, trend.CiUpper = kendall.slope, kendall.lower, kendall.upper
+		trend.PValue = kendall.pValue
 	}
+	trend.Confidence = 1 - trend.PValue
 
-	// Calculate linear regression for pressure trend
-	slope, confidence := calculateLinearTrend(readings, func(wp models.WeatherPoint) float64 {
-		return wp.Pressure
-	})
-
-	if math.Abs(slope) < ta.MinTrendSignificance {
-		return &models.Trend{
-			Variable:   "pressure",
-			Trend:      "stable",
-			ChangeRate: slope,
-			Confidence: confidence,
-			Duration:   calculateDuration(readings),
+	trend.Trend = "stable"
+	if math.Abs(trend.ChangeRate) >= ta.MinTrendSignificance && trend.PValue <= ta.Alpha {
+		if rate := variable.rate(ta.TrendThresholds); trend.ChangeRate > rate {
+			trend.Trend = variable.rising
+		} else if trend.ChangeRate < -rate {
+			trend.Trend = variable.falling
 		}
 	}
-
-	trendType := "stable"
-	if slope > 0.5 {
-		trendType = "rising" // pressure rising
-	} else if slope < -0.5 {
-		trendType = "falling" // pressure dropping
-	}
-
-	return &models.Trend{
-		Variable:   "pressure",
-		Trend:      trendType,
-		ChangeRate: slope,
-		Confidence: confidence,
-		Duration:   calculateDuration(readings),
-	}
+	return trend
 }
 
-// analyzeHumidityTrend analyzes humidity trends
-func (ta *TrendAnalyzer) analyzeHumidityTrend(readings []models.WeatherPoint) *models.Trend {
-	if len(readings) < 2 {
-		return nil
-	}
-
-	// Calculate linear regression for humidity trend
-	slope, confidence := calculateLinearTrend(readings, func(wp models.WeatherPoint) float64 {
-		return wp.Humidity
-	})
-
-	if math.Abs(slope) < ta.MinTrendSignificance {
-		return &models.Trend{
-			Variable:   "humidity",
-			Trend:      "stable",
-			ChangeRate: slope,
-			Confidence: confidence,
-			Duration:   calculateDuration(readings),
-		}
-	}
-
-	trendType := "stable"
-	if slope > 1.0 {
-		trendType = "increasing"
-	} else if slope < -1.0 {
-		trendType = "decreasing"
-	}
-
-	return &models.Trend{
-		Variable:   "humidity",
-		Trend:      trendType,
-		ChangeRate: slope,
-		Confidence: confidence,
-		Duration:   calculateDuration(readings),
-	}
+// LinearTrend returns the least-squares slope of a variable per hour over readings in
+// chronological order, and the confidence of the fit (the absolute correlation)
+func LinearTrend(readings []models.WeatherPoint, value func(models.WeatherPoint) float64) (slope, confidence float64) {
+	return calculateLinearTrend(readings, value)
 }
 
-// analyzeWindSpeedTrend analyzes wind speed trends
-func (ta *TrendAnalyzer) analyzeWindSpeedTrend(readings []models.WeatherPoint) *models.Trend {
-	if len(readings) < 2 {
-		return nil
-	}
-
-	// Calculate linear regression for wind speed trend
-	slope, confidence := calculateLinearTrend(readings, func(wp models.WeatherPoint) float64 {
-		return wp.WindSpeed
-	})
+// calculateLinearTrend calculates the slope of a linear trend using least squares regression
+func calculateLinearTrend(readings []models.WeatherPoint, valueExtractor func(models.WeatherPoint) float64) (float64, float64) {
+	fit := regress(readings, valueExtractor)
+	return fit.slope, math.Abs(fit.correlation)
+}
 
-	if math.Abs(slope) < ta.MinTrendSignificance {
-		return &models.Trend{
-			Variable:   "wind_speed",
-			Trend:      "stable",
-			ChangeRate: slope,
-			Confidence: confidence,
-			Duration:   calculateDuration(readings),
-		}
-	}
+// regression is a least-squares line of a variable against hours since the first reading
+type regression struct {
+	n           int
+	slope       float64 // Change per hour
+	correlation float64 // Pearson correlation of the variable with time
+	stdErr      float64 // Standard error of the slope; 0 with fewer than 3 readings
+}
 
-	trendType := "stable"
-	if slope > 0.1 {
-		trendType = "increasing"
-	} else if slope < -0.1 {
-		trendType = "decreasing"
+// pValue returns the two-sided p-value of the t-test that the slope is zero: the probability of a
+// slope at least this steep in readings without a trend. A perfect fit has p-value 0; fewer than
+// 3 readings, or readings with no variation, have p-value 1.
+func (r regression) pValue() float64 {
+	if r.n < 3 || r.slope == 0 {
+		return 1
 	}
-
-	return &models.Trend{
-		Variable:   "wind_speed",
-		Trend:      trendType,
-		ChangeRate: slope,
-		Confidence: confidence,
-		Duration:   calculateDuration(readings),
+	if r.stdErr == 0 {
+		return 0
 	}
+	return 2 * studentTCDF(-math.Abs(r.slope/r.stdErr), float64(r.n-2))
 }
 
-// calculateLinearTrend calculates the slope of a linear trend using least squares regression
-func calculateLinearTrend(readings []models.WeatherPoint, valueExtractor func(models.WeatherPoint) float64) (float64, float64) {
+// regress fits the least-squares line of a variable over readings in chronological order
+func regress(readings []models.WeatherPoint, valueExtractor func(models.WeatherPoint) float64) regression {
 	n := len(readings)
 	if n < 2 {
-		return 0, 0
+		return regression{n: n}
 	}
 
 	// Convert timestamps to time since Unix epoch in hours for slope calculation
@@ -236,16 +169,26 @@ func calculateLinearTrend(readings []models.WeatherPoint, valueExtractor func(mo
 	}
 
 	if denominator == 0 {
-		return 0, 0
+		return regression{n: n}
 	}
 
 	slope := numerator / denominator
 
 	// Calculate correlation coefficient for confidence
 	correlation := calculateCorrelation(xValues, yValues, meanX, meanY, slope)
-	confidence := math.Abs(correlation)
 
-	return slope, confidence
+	// Standard error of the slope from the residuals around the line
+	var stdErr float64
+	if n > 2 {
+		var sumSquares float64
+		for i := range xValues {
+			residual := yValues[i] - meanY - slope*(xValues[i]-meanX)
+			sumSquares += residual * residual
+		}
+		stdErr = math.Sqrt(sumSquares / float64(n-2) / denominator)
+	}
+
+	return regression{n: n, slope: slope, correlation: correlation, stdErr: stdErr}
 }
 
 // calculateCorrelation calculates the Pearson correlation coefficient
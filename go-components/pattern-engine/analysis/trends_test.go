@@ -91,3 +91,56 @@ func TestAnalyzeTrendsWithValidData(t *testing.T) {
 		t.Error("Expected temperature trend not found")
 	}
 }
+
+// TestTrendConfidenceInterval tests the t-distribution confidence interval of a rate of change
+func TestTrendConfidenceInterval(t *testing.T) {
+	baseTime := time.Now()
+	var readings []models.WeatherPoint
+	for i, temp := range []float64{0, 1.5, 2, 2.5, 4} {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   baseTime.Add(time.Duration(i) * time.Hour),
+			Temperature: temp,
+		})
+	}
+
+	trends := NewTrendAnalyzer().AnalyzeTrends(&models.LocationData{Readings: readings})
+	// Slope 0.9 with standard error 0.1155 and t(0.975, 3) = 3.1824
+	temp := trends[0]
+	if temp.Variable != "temperature" || abs(temp.CiLower-0.5325) > 1e-3 || abs(temp.CiUpper-1.2675) > 1e-3 {
+		t.Errorf("Expected a rate of 0.9 in [0.5325, 1.2675], got %+v", temp)
+	}
+	// Constant pressure has no uncertainty
+	if pressure := trends[1]; pressure.CiLower != 0 || pressure.CiUpper != 0 {
+		t.Errorf("Expected an empty interval for constant pressure, got %+v", pressure)
+	}
+}
+
+// TestTrendSignificance tests p-values and R², and that steep but insignificant trends are stable
+func TestTrendSignificance(t *testing.T) {
+	baseTime := time.Now()
+	var readings []models.WeatherPoint
+	for i, temp := range []float64{0, 1.5, 2, 2.5, 4} {
+		readings = append(readings, models.WeatherPoint{
+			Timestamp:   baseTime.Add(time.Duration(i) * time.Hour),
+			Temperature: temp,
+			Pressure:    []float64{1010, 1016, 1009, 1017, 1014}[i], // Rising 0.9 hPa/h by chance
+		})
+	}
+
+	trends := NewTrendAnalyzer().AnalyzeTrends(&models.LocationData{Readings: readings})
+	temp, pressure := trends[0], trends[1]
+	// t = 0.9 / 0.1155 = 7.79 with 3 degrees of freedom
+	if temp.Trend != "rising" || abs(temp.PValue-0.0044) > 5e-4 || abs(temp.RSquared-81.0/85) > 1e-9 ||
+		abs(temp.Confidence-(1-temp.PValue)) > 1e-12 {
+		t.Errorf("Expected a significant rising temperature, got %+v", temp)
+	}
+	if pressure.Trend != "stable" || pressure.ChangeRate < 0.5 || pressure.PValue < 0.05 {
+		t.Errorf("Expected an insignificant pressure trend to be stable, got %+v", pressure)
+	}
+
+	lenient := NewTrendAnalyzer()
+	lenient.Alpha = 0.9
+	if trends := lenient.AnalyzeTrends(&models.LocationData{Readings: readings}); trends[1].Trend != "rising" {
+		t.Errorf("Expected pressure to be rising at alpha 0.9, got %+v", trends[1])
+	}
+}
@@ -1,5 +1,7 @@
 package analysis
 
+import "time"
+
 // VariableStats holds statistical information about a variable
 type VariableStats struct {
 	Mean       float64
@@ -7,26 +9,109 @@ type VariableStats struct {
 	Min        float64
 	Max        float64
 	SampleSize int
+	Median     float64
+	MAD        float64 // Median absolute deviation from the median
+	Q1, Q3     float64 // Lower and upper quartiles
 }
 
 // TrendAnalyzer performs trend analysis on weather data
 type TrendAnalyzer struct {
-	MinReadingsForAnalysis int
-	MinTrendSignificance   float64
+	TrendThresholds
 }
 
 // AnomalyDetector detects unusual weather patterns and anomalies
 type AnomalyDetector struct {
-	AnomalyThresholdFactor float64 // multiplier for standard deviation to detect anomalies
-	MinReadingsForBaseline int     // minimum readings to establish baseline
+	AnomalyThresholds
 }
 
 // PatternRecognizer identifies common weather patterns in data
 type PatternRecognizer struct {
-	MinPatternConfidence float64 // minimum confidence to report a pattern
+	PatternThresholds
 }
 
 // StatisticalAnalyzer performs statistical analysis on weather data
 type StatisticalAnalyzer struct {
 	ConfidenceLevel float64 // Confidence level for confidence intervals (e.g., 0.95 for 95%)
+	StatisticsThresholds
+}
+
+// SeasonalityDetector detects daily cycles and removes them before trend, anomaly and pattern analysis
+type SeasonalityDetector struct {
+	SeasonalityThresholds
+}
+
+// SpectralAnalyzer finds periodicities in the frequency domain
+type SpectralAnalyzer struct {
+	SpectrumThresholds
+}
+
+// MultivariateDetector detects readings whose combination of variables is unusual
+type MultivariateDetector struct {
+	MultivariateThresholds
+}
+
+// LagCorrelator measures lead and lag relationships between variables
+type LagCorrelator struct {
+	CorrelationThresholds
+}
+
+// StormDetector scores the risk of a storm in the coming readings
+type StormDetector struct {
+	StormThresholds
+	now func() time.Time // Current time (nil = time.Now)
+}
+
+// PrecipitationAccumulator accumulates precipitation and classifies its intensity
+type PrecipitationAccumulator struct {
+	PrecipitationThresholds
+	now func() time.Time // Current time (nil = time.Now)
+}
+
+// AgroCalculator computes agricultural indices: growing degree days, chill hours and frosts
+type AgroCalculator struct {
+	AgroThresholds
+}
+
+// EnergyEstimator estimates the solar and wind power potential of each reading
+type EnergyEstimator struct {
+	EnergyThresholds
+}
+
+// RoadIcingDetector scores the risk of ice on roads in the coming readings
+type RoadIcingDetector struct {
+	RoadIcingThresholds
+	now func() time.Time // Current time (nil = time.Now)
+}
+
+// ComfortAssessor reports how the weather feels and whether it is becoming more comfortable
+type ComfortAssessor struct {
+	ComfortThresholds
+	now func() time.Time // Current time (nil = time.Now)
+}
+
+// DataQualityChecker reports gaps, duplicates, stuck values and staleness of the readings
+type DataQualityChecker struct {
+	DataQualityThresholds
+	now func() time.Time // Current time (nil = time.Now)
+}
+
+// FogDetector finds the hours fog is likely to form in
+type FogDetector struct {
+	FogThresholds
+}
+
+// WindClassifier places the wind on the Beaufort scale and flags high wind
+type WindClassifier struct {
+	WindThresholds
+}
+
+// ExtremeEventDetector finds heat waves and cold snaps lasting several days
+type ExtremeEventDetector struct {
+	ExtremeThresholds
+}
+
+// RegionalAnalyzer relates the weather of several locations, such as a front passing one after
+// another; unlike the other analyzers it is not run per location
+type RegionalAnalyzer struct {
+	RegionalThresholds
 }
@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"sort"
+
+	"pattern-engine/models"
+)
+
+// minGustFactorSpeed is the wind speed (m/s) from which readings count towards the gust factor;
+// in lighter wind a gust of a few m/s gives a large factor that says little
+const minGustFactorSpeed = 2.0
+
+// beaufortScale is the Beaufort scale: the lowest wind speed (m/s, 10 m above ground) of each
+// force, from force 0, and its description (WMO)
+var beaufortScale = []struct {
+	speed       float64
+	description string
+}{
+	{0, "calm"},
+	{0.3, "light air"},
+	{1.6, "light breeze"},
+	{3.4, "gentle breeze"},
+	{5.5, "moderate breeze"},
+	{8.0, "fresh breeze"},
+	{10.8, "strong breeze"},
+	{13.9, "near gale"},
+	{17.2, "gale"},
+	{20.8, "strong gale"},
+	{24.5, "storm"},
+	{28.5, "violent storm"},
+	{32.7, "hurricane force"},
+}
+
+// Beaufort returns the force (0-12) on the Beaufort scale of a wind speed (m/s) and its
+// description, such as "fresh breeze"
+func Beaufort(speed float64) (int, string) {
+	force := sort.Search(len(beaufortScale), func(i int) bool { return beaufortScale[i].speed > speed }) - 1
+	force = max(force, 0)
+	return force, beaufortScale[force].description
+}
+
+// NewWindClassifier creates a new wind classifier with default settings
+func NewWindClassifier() *WindClassifier {
+	return &WindClassifier{DefaultThresholds().Wind}
+}
+
+// ClassifyWind places the wind of the current (latest) reading and the strongest wind of the
+// readings on the Beaufort scale, and measures the gust factor: the mean ratio of gusts to the
+// sustained wind, over readings with gusts and at least 2 m/s of wind. The wind is high when any
+// reading's sustained wind reaches HighWindSpeed or its gusts reach HighGustSpeed. It returns nil
+// without readings.
+func (wc *WindClassifier) ClassifyWind(locationData *models.LocationData) *models.WindClassification {
+	readings := locationData.Readings
+	if len(readings) == 0 {
+		return nil
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+
+	current := readings[len(readings)-1]
+	wind := &models.WindClassification{WindSpeed: current.WindSpeed}
+	wind.Beaufort, wind.Description = Beaufort(current.WindSpeed)
+
+	var gustFactors float64
+	var gusty int
+	strongest := current
+	for _, r := range readings {
+		if r.WindSpeed > strongest.WindSpeed {
+			strongest = r
+		}
+		wind.MaxGust = max(wind.MaxGust, r.WindGust)
+		if r.WindGust > 0 && r.WindSpeed >= minGustFactorSpeed {
+			gustFactors += r.WindGust / r.WindSpeed
+			gusty++
+		}
+		if r.WindSpeed >= wc.HighWindSpeed || r.WindGust >= wc.HighGustSpeed {
+			wind.HighWind = true
+		}
+	}
+	wind.MaxWindSpeed, wind.MaxWindTime = strongest.WindSpeed, strongest.Timestamp
+	wind.MaxBeaufort, wind.MaxDescription = Beaufort(strongest.WindSpeed)
+	if gusty > 0 {
+		wind.GustFactor = gustFactors / float64(gusty)
+	}
+	return wind
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestBeaufort tests the forces at the edges of the Beaufort scale
+func TestBeaufort(t *testing.T) {
+	for _, tt := range []struct {
+		speed       float64
+		force       int
+		description string
+	}{
+		{0, 0, "calm"},
+		{0.29, 0, "calm"},
+		{0.3, 1, "light air"},
+		{10, 5, "fresh breeze"},
+		{17.2, 8, "gale"},
+		{40, 12, "hurricane force"},
+	} {
+		if force, description := Beaufort(tt.speed); force != tt.force || description != tt.description {
+			t.Errorf("Beaufort(%v) = %d %q, want %d %q", tt.speed, force, description, tt.force, tt.description)
+		}
+	}
+}
+
+// TestClassifyWind tests the current and strongest wind, the gust factor, and that gusts alone
+// can make the wind high
+func TestClassifyWind(t *testing.T) {
+	start := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	readings := []models.WeatherPoint{
+		{Timestamp: start, WindSpeed: 12, WindGust: 18},
+		{Timestamp: start.Add(time.Hour), WindSpeed: 16, WindGust: 26},
+		{Timestamp: start.Add(2 * time.Hour), WindSpeed: 1, WindGust: 4}, // Too light for the gust factor
+		{Timestamp: start.Add(3 * time.Hour), WindSpeed: 6, WindGust: 9},
+	}
+
+	wind := NewWindClassifier().ClassifyWind(&models.LocationData{Readings: readings})
+	if wind.Beaufort != 4 || wind.Description != "moderate breeze" || wind.WindSpeed != 6 {
+		t.Errorf("Expected a moderate breeze now, got %+v", wind)
+	}
+	if wind.MaxBeaufort != 7 || wind.MaxDescription != "near gale" || !wind.MaxWindTime.Equal(start.Add(time.Hour)) || wind.MaxGust != 26 {
+		t.Errorf("Expected a near gale gusting to 26 m/s an hour in, got %+v", wind)
+	}
+	if want := (1.5 + 26.0/16 + 1.5) / 3; math.Abs(wind.GustFactor-want) > 1e-9 {
+		t.Errorf("Expected a gust factor of %.3f, got %.3f", want, wind.GustFactor)
+	}
+	if !wind.HighWind {
+		t.Error("Expected gusts of 26 m/s to be high wind")
+	}
+
+	classifier := NewWindClassifier()
+	classifier.HighGustSpeed = 30
+	if wind := classifier.ClassifyWind(&models.LocationData{Readings: readings}); wind.HighWind {
+		t.Errorf("Expected no high wind below the thresholds, got %+v", wind)
+	}
+}
@@ -0,0 +1,225 @@
+// Package chart draws the key series of an analysis as line and bar charts, SVG or PNG, with the
+// anomalies found marked on the series and the moments the weather changed, such as a front's
+// passage, as labelled vertical lines; seeing them against the readings shows why they were
+// found. Charts are drawn from the readings the analysis holds (models.AnalysisResult.Readings).
+package chart
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// Formats of the chart files
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+)
+
+// Size of a chart and margins of its plot area (px)
+const (
+	width        = 800
+	height       = 240
+	marginLeft   = 70
+	marginRight  = 20
+	marginTop    = 34
+	marginBottom = 28
+)
+
+// Chart is a series of readings with what was found in it
+type Chart struct {
+	Variable string         // e.g. "temperature"; names the chart's file
+	Title    string         // e.g. "Temperature (°C)"
+	Unit     string         // Symbol of the values' unit, as written after a number
+	Bars     bool           // Draw the values as bars from zero, as for precipitation, instead of a line
+	Points   []Point        // Readings in chronological order
+	Markers  []Point        // Anomalies, drawn as dots at their time and value
+	Events   []Event        // Changes of the weather, drawn as vertical lines
+	Zone     *time.Location // Time zone of the time labels (nil = UTC)
+}
+
+// Point is a value at a time
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Event is a labelled moment of a chart
+type Event struct {
+	Time  time.Time
+	Label string // e.g. "front passage"
+}
+
+// series are the variables charted, in order
+var series = []struct {
+	variable string
+	title    string
+	bars     bool
+	value    func(models.WeatherPoint) (float64, bool)
+}{
+	{"temperature", "Temperature", false, func(r models.WeatherPoint) (float64, bool) { return r.Temperature, true }},
+	{"pressure", "Pressure", false, func(r models.WeatherPoint) (float64, bool) { return r.Pressure, r.Pressure != 0 }}, // A pressure of zero is a missing one
+	{"precipitation", "Precipitation", true, func(r models.WeatherPoint) (float64, bool) { return r.PrecipitationMm, true }},
+}
+
+// FromAnalysis returns the temperature, pressure and precipitation charts of an analysis, each
+// with the anomalies of its variable and the moments of the patterns in its time span. Variables
+// without two readings, and precipitation when none fell, have no chart.
+func FromAnalysis(result models.AnalysisResult) []Chart {
+	system := units.Metric()
+	if result.Units != nil {
+		system = *result.Units
+	}
+	zone := time.UTC
+	if result.Timezone != "" {
+		if z, err := time.LoadLocation(result.Timezone); err == nil {
+			zone = z
+		}
+	}
+
+	var charts []Chart
+	for _, s := range series {
+		symbol := system.Symbol(s.variable)
+		c := Chart{
+			Variable: s.variable,
+			Title:    fmt.Sprintf("%s (%s)", s.title, strings.TrimSpace(symbol)),
+			Unit:     symbol,
+			Bars:     s.bars,
+			Zone:     zone,
+		}
+		wet := false
+		for _, r := range result.Readings {
+			if v, ok := s.value(r); ok {
+				c.Points = append(c.Points, Point{r.Timestamp, v})
+				wet = wet || v > 0
+			}
+		}
+		if len(c.Points) < 2 || (s.bars && !wet) {
+			continue
+		}
+		start, end := c.Points[0].Time, c.Points[len(c.Points)-1].Time
+		for _, a := range result.Anomalies {
+			if (a.Variable == s.variable || a.Variable == s.variable+"_mm") && !a.Timestamp.Before(start) && !a.Timestamp.After(end) {
+				c.Markers = append(c.Markers, Point{a.Timestamp, a.Value})
+			}
+		}
+		for _, p := range result.Patterns {
+			if !p.Timestamp.IsZero() && !p.Timestamp.Before(start) && !p.Timestamp.After(end) {
+				c.Events = append(c.Events, Event{p.Timestamp, strings.ReplaceAll(p.Name, "_", " ")})
+			}
+		}
+		charts = append(charts, c)
+	}
+	return charts
+}
+
+// Encode renders a chart in format, FormatSVG or FormatPNG
+func (c Chart) Encode(format string) ([]byte, error) {
+	switch format {
+	case FormatSVG:
+		return c.SVG(), nil
+	case FormatPNG:
+		return c.PNG()
+	}
+	return nil, fmt.Errorf("unknown chart format %q (expected %q or %q)", format, FormatSVG, FormatPNG)
+}
+
+// tick is a labelled position on an axis
+type tick struct {
+	pos   float64
+	label string
+}
+
+// layout maps the times and values of a chart to pixels of its plot area
+type layout struct {
+	left, right, top, bottom float64
+	start, end               time.Time
+	low, high                float64
+	xTicks, yTicks           []tick
+}
+
+// newLayout fits the chart's times and values, and zero for bars, to the plot area, extended to
+// round values for the value axis's ticks
+func (c Chart) newLayout() layout {
+	l := layout{
+		left: marginLeft, right: width - marginRight, top: marginTop, bottom: height - marginBottom,
+		start: c.Points[0].Time, end: c.Points[len(c.Points)-1].Time,
+		low: c.Points[0].Value, high: c.Points[0].Value,
+	}
+	for _, points := range [][]Point{c.Points, c.Markers} {
+		for _, p := range points {
+			l.low, l.high = math.Min(l.low, p.Value), math.Max(l.high, p.Value)
+		}
+	}
+	if c.Bars {
+		l.low = math.Min(l.low, 0)
+	}
+	if l.high == l.low {
+		l.low, l.high = l.low-1, l.high+1
+	}
+
+	step := niceStep((l.high - l.low) / 4)
+	l.low, l.high = math.Floor(l.low/step)*step, math.Ceil(l.high/step)*step
+	decimals := max(0, int(-math.Floor(math.Log10(step))))
+	for v := l.low; v <= l.high+step/2; v += step {
+		l.yTicks = append(l.yTicks, tick{l.y(v), formatValue(v, decimals)})
+	}
+
+	zone := c.zone()
+	for i := range 5 {
+		t := l.start.Add(time.Duration(float64(l.end.Sub(l.start)) * float64(i) / 4))
+		l.xTicks = append(l.xTicks, tick{l.x(t), t.In(zone).Format("Jan 2 15:04")})
+	}
+	return l
+}
+
+// zone returns the time zone of the chart's time labels
+func (c Chart) zone() *time.Location {
+	if c.Zone == nil {
+		return time.UTC
+	}
+	return c.Zone
+}
+
+// x returns the horizontal position of a time
+func (l layout) x(t time.Time) float64 {
+	span := l.end.Sub(l.start)
+	if span <= 0 {
+		return l.left
+	}
+	return l.left + (l.right-l.left)*float64(t.Sub(l.start))/float64(span)
+}
+
+// y returns the vertical position of a value
+func (l layout) y(v float64) float64 {
+	return l.bottom - (l.bottom-l.top)*(v-l.low)/(l.high-l.low)
+}
+
+// barWidth returns the width of a chart's bars: the spacing of its readings, less a gap
+func (l layout) barWidth(points int) float64 {
+	return math.Max(1, (l.right-l.left)/float64(max(points-1, 1))*0.8)
+}
+
+// niceStep returns the round step (1, 2 or 5 times a power of ten) nearest above rough
+func niceStep(rough float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(rough)))
+	for _, m := range []float64{1, 2, 5} {
+		if m*magnitude >= rough {
+			return m * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatValue formats a tick's value
+func formatValue(v float64, decimals int) string {
+	s := fmt.Sprintf("%.*f", decimals, v)
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
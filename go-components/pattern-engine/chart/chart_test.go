@@ -0,0 +1,117 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// testAnalysis returns an analysis of a day of hourly readings with a temperature spike, a
+// front's passage and rain in the afternoon
+func testAnalysis() models.AnalysisResult {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	result := models.AnalysisResult{
+		Location: "Oslo",
+		Anomalies: []models.Anomaly{
+			{Variable: "temperature", Type: "spike", Severity: "high", Value: 25.5, Timestamp: start.Add(12 * time.Hour)},
+			{Variable: "humidity", Type: "spike", Severity: "high", Value: 99, Timestamp: start.Add(12 * time.Hour)},
+		},
+		Patterns: []models.Pattern{
+			{Name: "front_passage", Timestamp: start.Add(15 * time.Hour)},
+			{Name: "stable_weather"}, // Over the readings, not at a moment
+		},
+	}
+	for i := range 24 {
+		p := models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: 10 + float64(i%6),
+			Pressure:    1010 - float64(i)*0.25,
+		}
+		if i >= 15 && i < 18 {
+			p.PrecipitationMm = 1.5
+		}
+		result.Readings = append(result.Readings, p)
+	}
+	return result
+}
+
+// TestFromAnalysis tests that each series is charted with the anomalies of its variable and the
+// moments of the patterns, and that precipitation is charted only when it fell
+func TestFromAnalysis(t *testing.T) {
+	charts := FromAnalysis(testAnalysis())
+	if len(charts) != 3 {
+		t.Fatalf("Expected temperature, pressure and precipitation charts, got %d", len(charts))
+	}
+	temperature := charts[0]
+	if temperature.Variable != "temperature" || temperature.Title != "Temperature (°C)" || len(temperature.Points) != 24 {
+		t.Errorf("Unexpected temperature chart %+v", temperature)
+	}
+	if len(temperature.Markers) != 1 || temperature.Markers[0].Value != 25.5 {
+		t.Errorf("Expected the temperature spike marked, got %v", temperature.Markers)
+	}
+	if len(temperature.Events) != 1 || temperature.Events[0].Label != "front passage" {
+		t.Errorf("Expected the front's passage as an event, got %v", temperature.Events)
+	}
+	if !charts[2].Bars || charts[2].Title != "Precipitation (mm)" {
+		t.Errorf("Expected precipitation as bars, got %+v", charts[2])
+	}
+
+	dry := testAnalysis()
+	for i := range dry.Readings {
+		dry.Readings[i].PrecipitationMm = 0
+	}
+	imperial := units.Imperial()
+	dry.Units = &imperial
+	charts = FromAnalysis(dry)
+	if len(charts) != 2 || charts[1].Title != "Pressure (inHg)" {
+		t.Errorf("Expected only temperature and pressure charts in inHg, got %+v", charts)
+	}
+	if charts := FromAnalysis(models.AnalysisResult{}); len(charts) != 0 {
+		t.Errorf("Expected no charts without readings, got %d", len(charts))
+	}
+}
+
+// TestEncode tests that a chart renders as valid SVG and PNG of the chart's size
+func TestEncode(t *testing.T) {
+	for _, c := range FromAnalysis(testAnalysis()) {
+		data, err := c.Encode(FormatSVG)
+		if err != nil {
+			t.Fatalf("SVG failed: %v", err)
+		}
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				if !errors.Is(err, io.EOF) {
+					t.Errorf("Invalid SVG of %s: %v", c.Variable, err)
+				}
+				break
+			}
+		}
+		if c.Variable == "temperature" && (!strings.Contains(string(data), "<circle") || !strings.Contains(string(data), ">front passage</text>")) {
+			t.Errorf("Expected the anomaly and the event drawn, got %s", data)
+		}
+
+		data, err = c.Encode(FormatPNG)
+		if err != nil {
+			t.Fatalf("PNG failed: %v", err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Invalid PNG of %s: %v", c.Variable, err)
+		}
+		if size := img.Bounds().Size(); size.X != width || size.Y != height {
+			t.Errorf("Unexpected PNG size %v", size)
+		}
+	}
+	if _, err := (Chart{}).Encode("gif"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
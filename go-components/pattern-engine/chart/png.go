@@ -0,0 +1,127 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// PNG renders the chart as a PNG image, laid out like the SVG
+func (c Chart) PNG() ([]byte, error) {
+	l := c.newLayout()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	series, marker, event, grid, text := rgb(colorSeries), rgb(colorMarker), rgb(colorEvent), rgb(colorGrid), rgb(colorText)
+
+	drawText(img, c.Title, l.left, 20, text)
+	for _, t := range l.yTicks {
+		drawLine(img, l.left, t.pos, l.right, t.pos, grid)
+		drawText(img, t.label, l.left-6-textWidth(t.label), t.pos+4, text)
+	}
+	for i, t := range l.xTicks {
+		x := t.pos - textWidth(t.label)/2
+		if i == 0 {
+			x = t.pos
+		} else if i == len(l.xTicks)-1 {
+			x = t.pos - textWidth(t.label)
+		}
+		drawText(img, t.label, x, l.bottom+18, text)
+	}
+
+	for _, e := range c.Events {
+		x := l.x(e.Time)
+		for y := l.top; y < l.bottom; y += 7 { // Dashed
+			drawLine(img, x, y, x, math.Min(y+4, l.bottom), event)
+		}
+		drawText(img, e.Label, x+3, l.top+10, event)
+	}
+
+	if c.Bars {
+		w := l.barWidth(len(c.Points))
+		for _, p := range c.Points {
+			x := l.x(p.Time)
+			rect := image.Rect(int(math.Round(x-w/2)), int(math.Round(l.y(max(p.Value, 0)))), int(math.Round(x+w/2)), int(math.Round(l.y(min(p.Value, 0)))))
+			draw.Draw(img, rect, image.NewUniform(series), image.Point{}, draw.Src)
+		}
+	} else {
+		for i := 1; i < len(c.Points); i++ {
+			p, q := c.Points[i-1], c.Points[i]
+			x0, y0, x1, y1 := l.x(p.Time), l.y(p.Value), l.x(q.Time), l.y(q.Value)
+			drawLine(img, x0, y0, x1, y1, series)
+			drawLine(img, x0, y0+1, x1, y1+1, series) // Twice as thick
+		}
+	}
+
+	for _, m := range c.Markers {
+		fillCircle(img, l.x(m.Time), l.y(m.Value), 4, marker)
+	}
+	frame := image.Rect(int(l.left), int(l.top), int(l.right), int(l.bottom))
+	drawLine(img, float64(frame.Min.X), float64(frame.Min.Y), float64(frame.Max.X), float64(frame.Min.Y), text)
+	drawLine(img, float64(frame.Min.X), float64(frame.Max.Y), float64(frame.Max.X), float64(frame.Max.Y), text)
+	drawLine(img, float64(frame.Min.X), float64(frame.Min.Y), float64(frame.Min.X), float64(frame.Max.Y), text)
+	drawLine(img, float64(frame.Max.X), float64(frame.Min.Y), float64(frame.Max.X), float64(frame.Max.Y), text)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rgb parses a color written as "#rrggbb"
+func rgb(hex string) color.RGBA {
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// drawLine draws a line a pixel wide between two points
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.RGBA) {
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0)))
+	for i := 0; i <= steps; i++ {
+		f := 0.0
+		if steps > 0 {
+			f = float64(i) / float64(steps)
+		}
+		img.SetRGBA(int(math.Round(x0+(x1-x0)*f)), int(math.Round(y0+(y1-y0)*f)), c)
+	}
+}
+
+// fillCircle draws a disc of radius r centered on a point
+func fillCircle(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for y := math.Floor(cy - r); y <= cy+r; y++ {
+		for x := math.Floor(cx - r); x <= cx+r; x++ {
+			if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r {
+				img.SetRGBA(int(x), int(y), c)
+			}
+		}
+	}
+}
+
+// asciiText spells out the symbols of the units outside the ASCII range of the PNG's font
+var asciiText = strings.NewReplacer("°", "deg")
+
+// drawText writes text with its baseline starting at a point, in a fixed-width font
+func drawText(img *image.RGBA, text string, x, y float64, c color.RGBA) {
+	text = asciiText.Replace(text)
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(int(math.Round(x)), int(math.Round(y))),
+	}
+	d.DrawString(text)
+}
+
+// textWidth returns the width of text in the font of drawText (px)
+func textWidth(text string) float64 {
+	return float64(font.MeasureString(basicfont.Face7x13, asciiText.Replace(text)).Round())
+}
@@ -0,0 +1,76 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Colors of the parts of a chart, shared by the SVG and PNG renderings
+const (
+	colorSeries = "#2a6fb0"
+	colorMarker = "#dd3333"
+	colorEvent  = "#888"
+	colorGrid   = "#e4e4e4"
+	colorText   = "#333"
+)
+
+// SVG renders the chart as a standalone SVG document
+func (c Chart) SVG() []byte {
+	l := c.newLayout()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `<text x="%s" y="20" font-size="14" fill="%s">%s</text>`+"\n", num(l.left), colorText, html.EscapeString(c.Title))
+
+	for _, t := range l.yTicks {
+		fmt.Fprintf(&b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n", num(l.left), num(t.pos), num(l.right), num(t.pos), colorGrid)
+		fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="end" fill="%s">%s</text>`+"\n", num(l.left-6), num(t.pos+4), colorText, html.EscapeString(t.label))
+	}
+	for i, t := range l.xTicks {
+		anchor := "middle"
+		if i == 0 {
+			anchor = "start"
+		} else if i == len(l.xTicks)-1 {
+			anchor = "end"
+		}
+		fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="%s" fill="%s">%s</text>`+"\n", num(t.pos), num(l.bottom+18), anchor, colorText, html.EscapeString(t.label))
+	}
+
+	for _, e := range c.Events {
+		x := l.x(e.Time)
+		fmt.Fprintf(&b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-dasharray="4 3"/>`+"\n", num(x), num(l.top), num(x), num(l.bottom), colorEvent)
+		fmt.Fprintf(&b, `<text x="%s" y="%s" fill="%s">%s</text>`+"\n", num(x+3), num(l.top+10), colorEvent, html.EscapeString(e.Label))
+	}
+
+	if c.Bars {
+		w := l.barWidth(len(c.Points))
+		for _, p := range c.Points {
+			top, base := l.y(max(p.Value, 0)), l.y(min(p.Value, 0))
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n", num(l.x(p.Time)-w/2), num(top), num(w), num(base-top), colorSeries)
+		}
+	} else {
+		points := make([]string, len(c.Points))
+		for i, p := range c.Points {
+			points[i] = num(l.x(p.Time)) + "," + num(l.y(p.Value))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n", strings.Join(points, " "), colorSeries)
+	}
+
+	for _, m := range c.Markers {
+		fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="4" fill="%s"><title>%s</title></circle>`+"\n", num(l.x(m.Time)), num(l.y(m.Value)), colorMarker,
+			html.EscapeString(strconv.FormatFloat(math.Round(m.Value*100)/100, 'f', -1, 64)+c.Unit+" at "+m.Time.In(c.zone()).Format("Jan 2 15:04")))
+	}
+	fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="none" stroke="%s"/>`+"\n",
+		num(l.left), num(l.top), num(l.right-l.left), num(l.bottom-l.top), colorText)
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
+
+// num formats a coordinate to a tenth of a pixel
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
@@ -0,0 +1,436 @@
+// Package cli implements the analyze command of the weather CLI (go-components/weather):
+// per-location time-series files are analyzed by the engine package and the analyses written
+// to the analysis directory.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pattern-engine/alerts"
+	"pattern-engine/analysis"
+	"pattern-engine/chart"
+	"pattern-engine/engine"
+	"pattern-engine/events"
+	"pattern-engine/logging"
+	"pattern-engine/models"
+	"pattern-engine/storage"
+	"pattern-engine/verification"
+)
+
+// Formats of the analysis files
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// Markdown reports written with -markdown
+const (
+	markdownFull    = "full"
+	markdownCompact = "compact"
+)
+
+// Default locations of the engine's input and output, relative to the working directory
+const (
+	DefaultTimeseriesDir = "data/intelligence/timeseries"
+	DefaultAnalysisDir   = "data/intelligence/analysis"
+	DefaultSummaryFile   = "data/intelligence/run_summary.json"
+	DefaultAlertState    = "data/intelligence/alert_state.json"
+	DefaultDatabase      = "data/intelligence/analyses.db"
+)
+
+// Analyze runs the "analyze" command: every time-series file is analyzed and the results written
+// to the analysis directory. It returns the process exit code.
+func Analyze(args []string) int {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a JSON configuration file; its \"logging\", \"events\", \"analysis\", \"alerts\", \"verification\" and \"units\" sections are used")
+	logFormat := flags.String("log-format", "", "log output format: text or json (overrides logging.log_format)")
+	compress := flags.Bool("compress", false, "gzip analysis files (written as .json.gz or .csv.gz)")
+	format := flags.String("format", formatJSON, "format of the analysis files: json (a file per location) or csv (trends, anomalies and statistics tables of every location)")
+	html := flags.Bool("html", false, "also write a self-contained HTML report per location (summary, trends, anomalies and sparklines) next to the analysis files")
+	charts := flags.String("charts", "", "also write temperature, pressure and precipitation charts per location, with anomalies and pattern moments marked, next to the analysis files and linked from the -html reports: svg or png (empty = none)")
+	markdown := flags.String("markdown", "", "also write a Markdown report per location next to the analysis files, to post in chats and issues: full, or compact with only the summary, alerts and top patterns (empty = none)")
+	timeseriesDir := flags.String("timeseries-dir", DefaultTimeseriesDir, "directory of per-location time-series files to analyze")
+	analysisDir := flags.String("analysis-dir", DefaultAnalysisDir, "directory analysis files are written to")
+	summaryFile := flags.String("summary-file", DefaultSummaryFile, "path of the machine-readable run summary")
+	strict := flags.Bool("strict", false, "fail files with readings, alerts or marine points that cannot be parsed instead of dropping those entries")
+	analyzers := flags.String("analyzers", "", "comma-separated analyzers to run, e.g. trends,anomalies (overrides analysis.analyzers; default all)")
+	regional := flags.Bool("regional", false, "also analyze the locations together for correlations and delays between them, written to a region_analysis file (loads whole files)")
+	verificationDir := flags.String("verification-dir", DefaultVerificationDir, "directory of the verification reports whose forecast skill is added to the analyses and weights their confidence (empty = none)")
+	alertState := flags.String("alert-state", DefaultAlertState, "path of the file active alerts are kept in between runs, so each is reported when raised and cleared (empty = report and send no transitions)")
+	printNarratives := flags.Bool("narrative", false, "print each location's narrative, a short paragraph of the analysis's conclusions, to standard output")
+	database := flags.String("database", "", "path of a SQLite database the analyses are also stored in, for the query command, e.g. "+DefaultDatabase+", or the postgres:// URL of a PostgreSQL one such as the collector's (empty = none)")
+	memoryBudget := flags.Int64("memory-budget", 0, "megabytes of readings held per file; larger files are streamed and trends are found in their most recent readings (0 = load whole files)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitConfigError
+	}
+
+	if *format != formatJSON && *format != formatCSV {
+		fmt.Fprintf(os.Stderr, "Unknown format %q (expected %q or %q)\n", *format, formatJSON, formatCSV)
+		return exitConfigError
+	}
+	if *charts != "" && *charts != chart.FormatSVG && *charts != chart.FormatPNG {
+		fmt.Fprintf(os.Stderr, "Unknown chart format %q (expected %q or %q)\n", *charts, chart.FormatSVG, chart.FormatPNG)
+		return exitConfigError
+	}
+	if *markdown != "" && *markdown != markdownFull && *markdown != markdownCompact {
+		fmt.Fprintf(os.Stderr, "Unknown Markdown report %q (expected %q or %q)\n", *markdown, markdownFull, markdownCompact)
+		return exitConfigError
+	}
+
+	logCloser, publisher, code := setup(*configPath, *logFormat)
+	if code != exitOK {
+		return code
+	}
+	defer logCloser.Close()
+
+	slog.Info("Reading time-series data", "directory", *timeseriesDir)
+
+	files, err := os.ReadDir(*timeseriesDir)
+	if err != nil {
+		return fail(exitError, "Failed to read directory", err)
+	}
+
+	// Process each location's time-series data, tracking the outcome for the run summary
+	run, code := newAnalysisRun(*configPath, *analyzers, *verificationDir, engine.Options{OutputDir: *analysisDir, Compress: *compress, MemoryBudget: *memoryBudget << 20})
+	if code != exitOK {
+		return code
+	}
+	run.printNarratives = *printNarratives
+	run.csv = *format == formatCSV
+	run.html = *html
+	run.charts = *charts
+	run.markdown = *markdown
+	if *database != "" {
+		if code := run.openDatabase(*database); code != exitOK {
+			return code
+		}
+		defer run.store.Close()
+	}
+	if *alertState != "" {
+		if code := run.trackAlerts(*configPath, *alertState); code != exitOK {
+			return code
+		}
+	}
+	var analyzedPaths []string // Files analyzed, for the regional analysis
+	for _, file := range files {
+		// Compressed time-series files (.json.gz) are decompressed transparently
+		if !file.IsDir() && (strings.HasSuffix(file.Name(), ".json") || strings.HasSuffix(file.Name(), ".json.gz")) {
+			filePath := filepath.Join(*timeseriesDir, file.Name())
+			slog.Info("Analyzing file", "file", file.Name())
+			run.summary.Files++
+
+			result, err := run.engine.AnalyzeFile(filePath, *strict)
+			if run.record(result, err, file.Name()) {
+				analyzedPaths = append(analyzedPaths, filePath)
+			}
+		}
+	}
+
+	if *regional {
+		var locations []models.LocationData
+		for _, path := range analyzedPaths {
+			if locationData, err := engine.LoadLocationData(path, *strict); err == nil {
+				locations = append(locations, locationData)
+			}
+		}
+		run.analyzeRegion(locations)
+	}
+
+	run.finish(publisher, *summaryFile)
+	return run.summary.ExitCode
+}
+
+// AnalyzeLocations analyzes locations already in memory, such as the results of a collection in
+// the same process, instead of reading time-series files. configPath is used as by Analyze;
+// analyses are written to analysisDir (skipped when it is empty) and the run summary to
+// summaryFile (skipped when it is empty). It returns the analyses and the exit code.
+func AnalyzeLocations(configPath string, locations []models.LocationData, analysisDir string, compress bool, summaryFile string) ([]models.AnalysisResult, int) {
+	logCloser, publisher, code := setup(configPath, "")
+	if code != exitOK {
+		return nil, code
+	}
+	defer logCloser.Close()
+
+	run, code := newAnalysisRun(configPath, "", DefaultVerificationDir, engine.Options{OutputDir: analysisDir, Compress: compress})
+	if code != exitOK {
+		return nil, code
+	}
+	for i := range locations {
+		run.summary.Files++
+		result, err := run.engine.Analyze(&locations[i])
+		run.record(result, err, locations[i].Name)
+	}
+
+	run.finish(publisher, summaryFile)
+	return run.analyses, run.summary.ExitCode
+}
+
+// setup configures logging (logFormat overrides logging.log_format when set) and the event bus
+// publisher from the configuration at configPath. On failure it returns exitConfigError and no closer.
+func setup(configPath, logFormat string) (io.Closer, *events.Publisher, int) {
+	logCfg, err := logging.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, fail(exitConfigError, "Failed to load config", err)
+	}
+	if logFormat != "" {
+		logCfg.LogFormat = logFormat
+	}
+	logCloser, err := logging.Setup(logCfg)
+	if err != nil {
+		return nil, nil, fail(exitConfigError, "Failed to set up logging", err)
+	}
+
+	eventsCfg, err := events.LoadConfig(configPath)
+	if err != nil {
+		logCloser.Close()
+		return nil, nil, fail(exitConfigError, "Failed to load config", err)
+	}
+	publisher, err := events.NewPublisher(eventsCfg)
+	if err != nil {
+		logCloser.Close()
+		return nil, nil, fail(exitConfigError, "Invalid event bus configuration", err)
+	}
+
+	slog.Info("Weather Pattern Engine v2.0 starting")
+	return logCloser, publisher, exitOK
+}
+
+// analysisRun holds the engine and the outcome of one analysis run
+type analysisRun struct {
+	engine          *engine.Engine
+	save            bool            // Write analysis files; in-memory runs may only return the analyses
+	csv             bool            // Write the analyses as CSV tables once the run is done instead of a JSON file each
+	html            bool            // Also write an HTML report of each analysis
+	charts          string          // Also write the charts of each analysis in this format ("" = none)
+	markdown        string          // Also write a markdownFull or markdownCompact report of each analysis ("" = none)
+	printNarratives bool            // Print the narrative of each analysis to standard output
+	alerts          *alerts.Tracker // Tracks the alerts between runs (nil = not tracked)
+	dispatcher      *alerts.Dispatcher
+	store           *storage.Store // Database the analyses are also stored in (nil = none)
+	summary         runSummary
+	analyses        []models.AnalysisResult
+}
+
+// newAnalysisRun creates the engine for a run, running the analyzers in the comma-separated
+// analyzers list or else those of the "analysis" section at configPath, with the thresholds and
+// location profiles of that section, the units of the "units" section and the forecast skill of
+// the reports in verificationDir (skipped when it is empty). Without an output directory analyses
+// are only returned. On failure it returns exitConfigError.
+func newAnalysisRun(configPath, analyzers, verificationDir string, opts engine.Options) (*analysisRun, int) {
+	cfg, err := engine.LoadConfig(configPath)
+	if err != nil {
+		return nil, fail(exitConfigError, "Failed to load config", err)
+	}
+	if opts.Units, err = engine.LoadUnits(configPath); err != nil {
+		return nil, fail(exitConfigError, "Failed to load config", err)
+	}
+	if verificationDir != "" {
+		verificationCfg, err := verification.LoadConfig(configPath)
+		if err != nil {
+			return nil, fail(exitConfigError, "Failed to load config", err)
+		}
+		// Analyses do not depend on the reports, so unreadable ones are only logged
+		if opts.Skills, err = verification.LoadSkills(verificationDir, verificationCfg); err != nil {
+			slog.Warn("Could not read verification reports", "directory", verificationDir, "error", err)
+		}
+	}
+	opts.Registry = engine.NewRegistry(cfg)
+	opts.Analyzers = cfg.Analyzers
+	opts.Profiles = cfg.Profiles
+	opts.Rules = cfg.Rules
+	opts.Resample = cfg.Resample
+	opts.Regional = &analysis.RegionalAnalyzer{RegionalThresholds: cfg.Thresholds.Regional}
+	if analyzers != "" {
+		opts.Analyzers = analysis.ParseNames(analyzers)
+	}
+	e, err := engine.New(opts)
+	if err != nil {
+		return nil, fail(exitConfigError, "Invalid analyzers", err)
+	}
+	return &analysisRun{
+		engine:  e,
+		save:    opts.OutputDir != "",
+		summary: runSummary{StartedAt: time.Now()},
+	}, exitOK
+}
+
+// trackAlerts tracks the alerts of the run against the state file at statePath, with the
+// cooldowns of the "alerts" section at configPath, and sends the alerts raised and cleared to the
+// channels of that section. On failure it returns exitConfigError.
+func (r *analysisRun) trackAlerts(configPath, statePath string) int {
+	cfg, err := alerts.LoadConfig(configPath)
+	if err != nil {
+		return fail(exitConfigError, "Failed to load config", err)
+	}
+	if r.dispatcher, err = alerts.NewDispatcher(cfg); err != nil {
+		return fail(exitConfigError, "Invalid alert channel configuration", err)
+	}
+	if r.alerts, err = alerts.Open(statePath, cfg); err != nil {
+		return fail(exitConfigError, "Failed to read alert state", err)
+	}
+	return exitOK
+}
+
+// openDatabase opens the database at path for the run's analyses to be stored in. On failure it
+// returns exitConfigError.
+func (r *analysisRun) openDatabase(path string) int {
+	store, err := storage.Open(context.Background(), path)
+	if err != nil {
+		return fail(exitConfigError, "Failed to open database", err)
+	}
+	r.store = store
+	return exitOK
+}
+
+// record saves the analysis of one location, and its charts, reports and database rows if the
+// run has them, then records the outcome in the run summary under source, the time-series file or
+// location name. It reports whether the location was analyzed.
+func (r *analysisRun) record(result models.AnalysisResult, err error, source string) bool {
+	if errors.Is(err, engine.ErrInsufficientData) {
+		slog.Warn("Insufficient data for analysis (need at least 2 readings)", "source", source)
+		r.summary.Skipped++
+		return false
+	}
+	if err == nil && r.save && !r.csv {
+		var path string
+		if path, err = r.engine.Save(result); err == nil {
+			slog.Info("Analysis saved", "location", result.Location, "path", path)
+		}
+	}
+	var charts []string
+	if err == nil && r.save && r.charts != "" {
+		if charts, err = r.engine.SaveCharts(result, r.charts); err == nil {
+			slog.Info("Charts saved", "location", result.Location, "paths", charts)
+		}
+	}
+	if err == nil && r.save && r.html {
+		var path string
+		if path, err = r.engine.SaveHTML(result, charts); err == nil {
+			slog.Info("Report saved", "location", result.Location, "path", path)
+		}
+	}
+	if err == nil && r.save && r.markdown != "" {
+		var path string
+		if path, err = r.engine.SaveMarkdown(result, r.markdown == markdownCompact); err == nil {
+			slog.Info("Report saved", "location", result.Location, "path", path)
+		}
+	}
+	if err == nil && r.store != nil {
+		if err = r.store.Save(context.Background(), result); err != nil {
+			err = fmt.Errorf("failed to store analysis in database: %w", err)
+		}
+	}
+	if err != nil {
+		slog.Error("Failed to analyze", "source", source, "error", err)
+		r.summary.fail(source)
+		return false
+	}
+	r.summary.Analyzed++
+	result.Readings = nil // Only the reports need them; the run keeps every analysis
+	r.analyses = append(r.analyses, result)
+	if r.printNarratives {
+		fmt.Printf("%s: %s\n", result.Location, result.Narrative)
+	}
+	return true
+}
+
+// analyzeRegion analyzes the locations together and saves the regional analysis. It is an
+// addition to the per-location analyses, so its failure does not change the run's outcome.
+func (r *analysisRun) analyzeRegion(locations []models.LocationData) {
+	region, err := r.engine.AnalyzeRegion(locations)
+	if errors.Is(err, engine.ErrInsufficientData) {
+		slog.Warn("Insufficient data for regional analysis (need 2 locations with overlapping readings)", "locations", len(locations))
+		return
+	}
+	slog.Info("Regional analysis complete", "locations", len(region.Locations), "correlations", len(region.Correlations))
+	if r.save {
+		if path, err := r.engine.SaveRegion(region); err != nil {
+			slog.Error("Failed to save regional analysis", "error", err)
+		} else {
+			slog.Info("Regional analysis saved", "path", path)
+		}
+	}
+}
+
+// finish writes the CSV tables of a CSV run and publishes the analyses, then completes and writes
+// the run summary (skipped when summaryFile is empty)
+func (r *analysisRun) finish(publisher *events.Publisher, summaryFile string) {
+	if r.csv && r.save && len(r.analyses) > 0 {
+		r.saveCSV()
+	}
+
+	// Publishing is best effort: the analysis files are already written
+	if err := publisher.Publish(context.Background(), r.analyses); err != nil {
+		slog.Error("Could not publish analyses", "error", err)
+	} else if publisher != nil && len(r.analyses) > 0 {
+		slog.Info("Published analyses", "subject", publisher.Subject, "count", len(r.analyses))
+	}
+
+	if r.alerts != nil {
+		r.updateAlerts()
+	}
+
+	r.summary.finish()
+	if summaryFile != "" {
+		writeRunSummary(summaryFile, r.summary)
+	}
+	slog.Info("Weather intelligence analysis complete", "status", r.summary.Status,
+		"analyzed", r.summary.Analyzed, "skipped", r.summary.Skipped, "failed", r.summary.Failed)
+}
+
+// saveCSV writes the CSV tables of the run's analyses. Without them no analysis was saved, so
+// every location analyzed counts as failed.
+func (r *analysisRun) saveCSV() {
+	paths, err := r.engine.SaveCSV(r.analyses)
+	if err != nil {
+		slog.Error("Failed to save analysis tables", "error", err)
+		for _, result := range r.analyses {
+			r.summary.fail(result.Location)
+		}
+		r.summary.Analyzed = 0
+		return
+	}
+	for _, path := range paths {
+		slog.Info("Analysis table saved", "path", path)
+	}
+}
+
+// updateAlerts records the alerts raised and cleared by the run's analyses, sends them to the
+// alert channels and saves the alert state, logging rather than failing the run on error
+func (r *analysisRun) updateAlerts() {
+	transitions := r.alerts.Update(r.analyses, time.Now())
+	for _, transition := range transitions {
+		slog.Info("Alert "+transition.State, "location", transition.Location, "alert", transition.Alert, "severity", transition.Severity)
+		if transition.State == alerts.StateRaised {
+			r.summary.AlertsRaised++
+		} else {
+			r.summary.AlertsCleared++
+		}
+	}
+	// Sending is best effort, like publishing: the state is saved either way so that alerts are
+	// not raised again on the next run
+	if err := r.dispatcher.Dispatch(context.Background(), transitions); err != nil {
+		slog.Error("Could not send alerts", "error", err)
+	}
+	if err := r.alerts.Save(); err != nil {
+		slog.Warn("Could not save alert state", "path", r.alerts.Path(), "error", err)
+	}
+}
+
+// fail logs err and returns code, for commands to return as their exit code
+func fail(code int, msg string, err error) int {
+	slog.Error(msg, "error", err)
+	return code
+}
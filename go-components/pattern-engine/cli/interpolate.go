@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"math"
+	"os"
+
+	"pattern-engine/analysis"
+	"pattern-engine/engine"
+	"pattern-engine/models"
+)
+
+// Interpolate runs the "interpolate" command: the weather at a point is estimated from the newest
+// analyses of the locations around it and written as JSON to stdout. It returns the process exit
+// code.
+func Interpolate(args []string) int {
+	flags := flag.NewFlagSet("interpolate", flag.ContinueOnError)
+	analysisDir := flags.String("analysis-dir", DefaultAnalysisDir, "directory of the analysis files to interpolate between")
+	lat := flags.Float64("lat", math.NaN(), "latitude of the point (required)")
+	lon := flags.Float64("lon", math.NaN(), "longitude of the point (required)")
+	power := flags.Float64("power", 2, "exponent of the distance in the weights; higher values favor the nearest locations")
+	maxDistance := flags.Float64("max-distance", 300, "km beyond which locations are not used (0 = no limit)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitConfigError
+	}
+	if math.IsNaN(*lat) || math.IsNaN(*lon) || math.Abs(*lat) > 90 || math.Abs(*lon) > 180 {
+		return fail(exitConfigError, "Invalid point", errors.New("-lat (-90 to 90) and -lon (-180 to 180) are required"))
+	}
+	if *power <= 0 || *maxDistance < 0 {
+		return fail(exitConfigError, "Invalid interpolation settings", errors.New("-power must be positive and -max-distance cannot be negative"))
+	}
+
+	analyses, err := engine.LoadAnalyses(*analysisDir)
+	if err != nil {
+		return fail(exitError, "Failed to read analyses", err)
+	}
+	interpolator := &analysis.Interpolator{Power: *power, MaxDistanceKm: *maxDistance}
+	interpolation, err := interpolator.Interpolate(analyses, models.Coordinates{Latitude: *lat, Longitude: *lon})
+	if err != nil {
+		return fail(exitError, "Failed to interpolate", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(interpolation); err != nil {
+		return fail(exitError, "Failed to write interpolation", err)
+	}
+	return exitOK
+}
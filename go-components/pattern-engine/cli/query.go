@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"pattern-engine/storage"
+)
+
+// formatTable is the query command's output of aligned columns; it also writes formatJSON
+const formatTable = "table"
+
+// Query runs the "query" command: rows of a table of the analysis database, which analyze writes
+// with -database, are written to stdout as an aligned table or JSON. The table is the first
+// argument, e.g. "query anomalies -location Oslo -severity high -since 7d". It returns the
+// process exit code.
+func Query(args []string) int {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: query <%s> [flags]\n", strings.Join(storage.Tables(), "|"))
+		flags.PrintDefaults()
+	}
+	database := flags.String("database", DefaultDatabase, "path of the SQLite database of analyses, or the postgres:// URL of a PostgreSQL one")
+	location := flags.String("location", "", "locations whose name starts with this, ignoring case (default all)")
+	since := flags.String("since", "", "rows from this time on: a period back from now such as 7d or 12h, or a date such as 2025-10-01 (default all); anomalies and patterns by their time, other tables by the time of their analysis")
+	severity := flags.String("severity", "", "anomalies of this severity, e.g. high (default all)")
+	variable := flags.String("variable", "", "trends, anomalies or statistics of this variable, e.g. pressure (default all)")
+	limit := flags.Int("limit", 0, "most rows written, newest first (0 = all)")
+	format := flags.String("format", formatTable, "output format: table or json")
+	var table string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		table, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitConfigError
+	}
+	if table == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return exitConfigError
+	}
+
+	q := storage.Query{Table: table, Location: *location, Severity: *severity, Variable: *variable, Limit: *limit}
+	if *since != "" {
+		var err error
+		if q.Since, err = parseSince(*since, time.Now()); err != nil {
+			return fail(exitConfigError, "Invalid -since", err)
+		}
+	}
+	if *format != formatTable && *format != formatJSON {
+		return fail(exitConfigError, "Invalid format", fmt.Errorf("unknown format %q (expected %q or %q)", *format, formatTable, formatJSON))
+	}
+	// Open would create an empty database, hiding a wrong path
+	if _, err := os.Stat(*database); err != nil && !storage.IsPostgres(*database) {
+		return fail(exitConfigError, "No analysis database (analyze with -database stores the analyses)", err)
+	}
+
+	ctx := context.Background()
+	store, err := storage.Open(ctx, *database)
+	if err != nil {
+		return fail(exitError, "Failed to open database", err)
+	}
+	defer store.Close()
+	rows, err := store.Query(ctx, q)
+	if err != nil {
+		return fail(exitConfigError, "Failed to query analyses", err)
+	}
+
+	if *format == formatJSON {
+		err = writeRowsJSON(rows)
+	} else {
+		err = writeRowsTable(rows)
+	}
+	if err != nil {
+		return fail(exitError, "Failed to write rows", err)
+	}
+	return exitOK
+}
+
+// parseSince parses the -since flag: a number of days ("7d"), a duration ("12h"), a date or an
+// RFC 3339 time, the first two back from now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a period such as 7d or 12h nor a date such as 2025-10-01", value)
+}
+
+// writeRowsJSON writes rows to stdout as a JSON array of objects keyed by column
+func writeRowsJSON(rows storage.Rows) error {
+	objects := make([]map[string]any, len(rows.Values))
+	for i, values := range rows.Values {
+		objects[i] = make(map[string]any, len(values))
+		for j, column := range rows.Columns {
+			objects[i][column] = values[j]
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(objects)
+}
+
+// writeRowsTable writes rows to stdout as columns aligned with spaces, under a header
+func writeRowsTable(rows storage.Rows) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.ToUpper(strings.Join(rows.Columns, "\t")))
+	for _, values := range rows.Values {
+		cells := make([]string, len(values))
+		for i, v := range values {
+			switch v := v.(type) {
+			case nil:
+				cells[i] = "-"
+			case float64:
+				cells[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				cells[i] = fmt.Sprint(v)
+			}
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	return writer.Flush()
+}
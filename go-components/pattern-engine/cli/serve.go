@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"pattern-engine/alerts"
+	"pattern-engine/engine"
+	"pattern-engine/server"
+)
+
+// DefaultServeAddress is where the analysis API listens unless -addr is given
+const DefaultServeAddress = "127.0.0.1:8081"
+
+// DefaultWatchInterval is how often the analysis directory is checked for new analyses to stream
+const DefaultWatchInterval = 10 * time.Second
+
+// Serve runs the "serve-analysis" command: the analysis REST API (see package server) is served
+// until the process is interrupted, streaming the analyses other processes save to the analysis
+// directory on GET /events. It returns the process exit code.
+func Serve(args []string) int {
+	flags := flag.NewFlagSet("serve-analysis", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a JSON configuration file; its \"logging\", \"analysis\", \"verification\" and \"units\" sections are used")
+	logFormat := flags.String("log-format", "", "log output format: text or json (overrides logging.log_format)")
+	addr := flags.String("addr", DefaultServeAddress, "listen address")
+	analysisDir := flags.String("analysis-dir", DefaultAnalysisDir, "directory analyses are saved to and the latest analyses read from")
+	verificationDir := flags.String("verification-dir", DefaultVerificationDir, "directory of the verification reports whose forecast skill is added to the analyses (empty = none)")
+	watchInterval := flags.Duration("watch-interval", DefaultWatchInterval, "how often the analysis directory is checked for new analyses to stream on GET /events (0 = only stream those of POST /analyze)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitConfigError
+	}
+
+	logCloser, _, code := setup(*configPath, *logFormat)
+	if code != exitOK {
+		return code
+	}
+	defer logCloser.Close()
+
+	run, code := newAnalysisRun(*configPath, "", *verificationDir, engine.Options{OutputDir: *analysisDir})
+	if code != exitOK {
+		return code
+	}
+
+	alertsCfg, err := alerts.LoadConfig(*configPath)
+	if err != nil {
+		return fail(exitConfigError, "Failed to load config", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	srv := server.New(run.engine, *analysisDir, alertsCfg)
+	if *watchInterval > 0 && *analysisDir != "" {
+		go func() {
+			if err := srv.Watch(ctx, *watchInterval); err != nil {
+				slog.Error("Not streaming new analyses", "directory", *analysisDir, "error", err)
+			}
+		}()
+	}
+	if err := srv.ListenAndServe(ctx, *addr); err != nil {
+		return fail(exitError, "Server failed", err)
+	}
+	return exitOK
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"weathermodels/fileio"
+)
+
+// Process exit codes, matching the data collector's, so orchestration scripts can tell a partial
+// failure from a total one
+const (
+	exitOK             = 0 // Every time-series file was analyzed (or skipped for lack of data)
+	exitError          = 1 // Unexpected failure (unreadable time-series directory)
+	exitPartialFailure = 2 // Some files could not be analyzed
+	exitTotalFailure   = 3 // No file could be analyzed
+	exitConfigError    = 4 // Invalid configuration or flags
+)
+
+// Run statuses reported in runSummary.Status
+const (
+	statusOK      = "ok"
+	statusPartial = "partial"
+	statusFailed  = "failed"
+)
+
+// runSummary is the machine-readable outcome of an analysis run
+type runSummary struct {
+	Status      string    `json:"status"` // "ok", "partial" or "failed"
+	ExitCode    int       `json:"exit_code"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	DurationMs  float64   `json:"duration_ms"`
+	Files       int       `json:"files"`    // Time-series files found (locations for an in-memory run)
+	Analyzed    int       `json:"analyzed"` // Files with an analysis written
+	Skipped     int       `json:"skipped"`  // Files with too few readings to analyze
+	Failed      int       `json:"failed"`   // Files that could not be parsed or whose analysis could not be saved
+	FailedFiles []string  `json:"failed_files,omitempty"`
+
+	AlertsRaised  int `json:"alerts_raised,omitempty"`  // Alerts raised since the last run (with alert state)
+	AlertsCleared int `json:"alerts_cleared,omitempty"` // Alerts cleared since the last run
+}
+
+// fail records a file that could not be analyzed
+func (s *runSummary) fail(file string) {
+	s.Failed++
+	s.FailedFiles = append(s.FailedFiles, file)
+}
+
+// finish sets the status and exit code once every file has been processed
+func (s *runSummary) finish() {
+	s.FinishedAt = time.Now()
+	s.DurationMs = float64(s.FinishedAt.Sub(s.StartedAt).Microseconds()) / 1000
+
+	switch {
+	case s.Failed == 0:
+		s.Status, s.ExitCode = statusOK, exitOK
+	case s.Failed == s.Files:
+		s.Status, s.ExitCode = statusFailed, exitTotalFailure
+	default:
+		s.Status, s.ExitCode = statusPartial, exitPartialFailure
+	}
+}
+
+// writeRunSummary writes the summary to summaryFile, logging rather than failing the run on error
+func writeRunSummary(summaryFile string, summary runSummary) {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(summaryFile), 0755)
+	}
+	if err == nil {
+		err = fileio.WriteFileAtomic(summaryFile, data, 0644)
+	}
+	if err != nil {
+		slog.Warn("Could not write run summary", "path", summaryFile, "error", err)
+	}
+}
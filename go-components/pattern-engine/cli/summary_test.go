@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestExitCodes tests the exit code and status of a run with each mix of outcomes, and of runs
+// stopped by bad flags
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  int
+		failed int
+		code   int
+		status string
+	}{
+		{name: "Every file analyzed", files: 3, code: exitOK, status: statusOK},
+		{name: "No files", files: 0, code: exitOK, status: statusOK},
+		{name: "Some files failed", files: 3, failed: 1, code: exitPartialFailure, status: statusPartial},
+		{name: "Every file failed", files: 2, failed: 2, code: exitTotalFailure, status: statusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := runSummary{StartedAt: time.Now(), Files: tt.files}
+			for range tt.failed {
+				summary.fail("oslo.json")
+			}
+			summary.finish()
+			if summary.ExitCode != tt.code || summary.Status != tt.status {
+				t.Errorf("Expected %s (%d), got %s (%d)", tt.status, tt.code, summary.Status, summary.ExitCode)
+			}
+		})
+	}
+
+	if code := Analyze([]string{"-bogus"}); code != exitConfigError {
+		t.Errorf("Expected exit code %d for an unknown flag, got %d", exitConfigError, code)
+	}
+	if code := Analyze([]string{"-h"}); code != exitOK {
+		t.Errorf("Expected exit code %d for -h, got %d", exitOK, code)
+	}
+}
+
+// TestWriteRunSummary tests the JSON of a run summary, written with its directory
+func TestWriteRunSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intelligence", "run_summary.json")
+	summary := runSummary{StartedAt: time.Now().Add(-time.Second), Files: 3, Analyzed: 1, Skipped: 1}
+	summary.fail("bergen.json")
+	summary.finish()
+	writeRunSummary(path, summary)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the summary file: %v", err)
+	}
+	var written runSummary
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written.Status != statusPartial || written.ExitCode != exitPartialFailure || written.Analyzed != 1 || written.Skipped != 1 {
+		t.Errorf("Unexpected summary: %s", data)
+	}
+	if !slices.Equal(written.FailedFiles, []string{"bergen.json"}) || written.DurationMs < 1000 {
+		t.Errorf("Unexpected failures or duration: %s", data)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"status", "exit_code", "started_at", "finished_at", "duration_ms", "files", "analyzed", "skipped", "failed", "failed_files"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Expected %q in the summary JSON, got %s", key, data)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pattern-engine/engine"
+	"pattern-engine/verification"
+	"weathermodels/fileio"
+)
+
+// DefaultVerificationDir is where verification reports are written, relative to the working directory
+const DefaultVerificationDir = "data/intelligence/verification"
+
+// Verify runs the "verify" command: the forecasts the collector kept for each location are
+// verified against the readings of the location's time-series file, and an accuracy report is
+// written for each. It returns the process exit code.
+func Verify(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a JSON configuration file; its \"logging\" and \"verification\" sections are used")
+	logFormat := flags.String("log-format", "", "log output format: text or json (overrides logging.log_format)")
+	timeseriesDir := flags.String("timeseries-dir", DefaultTimeseriesDir, "directory of per-location time-series files with the observed readings")
+	forecastDir := flags.String("forecast-dir", "", "directory of the forecasts kept by the collector (default: the forecasts directory of -timeseries-dir)")
+	verificationDir := flags.String("verification-dir", DefaultVerificationDir, "directory verification reports are written to")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitConfigError
+	}
+	if *forecastDir == "" {
+		*forecastDir = filepath.Join(*timeseriesDir, "forecasts")
+	}
+
+	logCloser, _, code := setup(*configPath, *logFormat)
+	if code != exitOK {
+		return code
+	}
+	defer logCloser.Close()
+
+	cfg, err := verification.LoadConfig(*configPath)
+	if err != nil {
+		return fail(exitConfigError, "Failed to load config", err)
+	}
+
+	files, err := os.ReadDir(*forecastDir)
+	if err != nil {
+		return fail(exitError, "Failed to read directory", err)
+	}
+
+	var verified, failed int
+	for _, file := range files {
+		if file.IsDir() || !(strings.HasSuffix(file.Name(), ".json") || strings.HasSuffix(file.Name(), ".json"+fileio.GzipExt)) {
+			continue
+		}
+		if err := verifyFile(filepath.Join(*forecastDir, file.Name()), *timeseriesDir, *verificationDir, cfg); err != nil {
+			slog.Error("Failed to verify forecasts", "file", file.Name(), "error", err)
+			failed++
+			continue
+		}
+		verified++
+	}
+
+	slog.Info("Forecast verification complete", "verified", verified, "failed", failed)
+	switch {
+	case failed == 0:
+		return exitOK
+	case verified > 0:
+		return exitPartialFailure
+	}
+	return exitTotalFailure
+}
+
+// verifyFile verifies the forecast archive at path against the time-series file of the same name
+// in timeseriesDir and writes the report to verificationDir
+func verifyFile(path, timeseriesDir, verificationDir string, cfg verification.Config) error {
+	archive, err := verification.LoadArchive(path)
+	if err != nil {
+		return err
+	}
+	timeseriesPath := filepath.Join(timeseriesDir, filepath.Base(path))
+	if _, err := os.Stat(timeseriesPath); errors.Is(err, fs.ErrNotExist) {
+		// The history file may be compressed when the archive is not, or the other way round
+		if trimmed, ok := strings.CutSuffix(timeseriesPath, fileio.GzipExt); ok {
+			timeseriesPath = trimmed
+		} else {
+			timeseriesPath += fileio.GzipExt
+		}
+	}
+	locationData, err := engine.LoadLocationData(timeseriesPath, false)
+	if err != nil {
+		return err
+	}
+
+	report := verification.Verify(archive, locationData.Readings, cfg)
+	reportPath, err := verification.SaveReport(verificationDir, report)
+	if err != nil {
+		return err
+	}
+	for _, v := range report.Variables {
+		slog.Debug("Forecast accuracy", "location", report.Location, "variable", v.Variable, "count", v.Count,
+			"mae", v.MAE, "rmse", v.RMSE, "bias", v.Bias)
+	}
+	slog.Info("Verification report saved", "location", report.Location, "forecasts", report.Forecasts, "path", reportPath)
+	return nil
+}
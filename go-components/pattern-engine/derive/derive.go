@@ -0,0 +1,97 @@
+// Package derive computes the variables of a reading that follow from its measured values: the
+// dew point, heat index, wind chill and apparent temperature. The engine derives them for every
+// reading before analysis, so each analyzer and the summary can use them (WeatherPoint.Derived).
+package derive
+
+import (
+	"math"
+
+	"pattern-engine/models"
+)
+
+// Magnus formula coefficients for the saturation vapour pressure over water (Sonntag 1990)
+const (
+	magnusA = 17.62
+	magnusB = 243.12 // °C
+)
+
+// Limits of the heat index and wind chill formulas
+const (
+	heatIndexMinTemperature = 26.7 // °C (80°F); below it humidity adds little to the heat felt
+	windChillMaxTemperature = 10   // °C
+	windChillMinWindSpeed   = 4.8  // km/h
+)
+
+// Apply sets the derived variables of each reading
+func Apply(readings []models.WeatherPoint) {
+	for i := range readings {
+		readings[i].Derived = Compute(readings[i])
+	}
+}
+
+// Compute returns the derived variables of a reading. The dew point is left 0 for a reading
+// without humidity.
+func Compute(r models.WeatherPoint) models.Derived {
+	derived := models.Derived{
+		HeatIndex:           HeatIndex(r.Temperature, r.Humidity),
+		WindChill:           WindChill(r.Temperature, r.WindSpeed),
+		ApparentTemperature: ApparentTemperature(r.Temperature, r.Humidity, r.WindSpeed),
+	}
+	if r.Humidity > 0 {
+		derived.DewPoint = DewPoint(r.Temperature, r.Humidity)
+	}
+	return derived
+}
+
+// DewPoint returns the dew point (°C) at a temperature (°C) and relative humidity (%, above 0)
+// by the Magnus formula
+func DewPoint(temperature, humidity float64) float64 {
+	gamma := math.Log(humidity/100) + magnusA*temperature/(magnusB+temperature)
+	return magnusB * gamma / (magnusA - gamma)
+}
+
+// HeatIndex returns the temperature (°C) felt at a temperature (°C) and relative humidity (%) by
+// the US National Weather Service regression (Rothfusz 1990, with its adjustments for dry and
+// very humid air). Below 26.7°C it is the temperature.
+func HeatIndex(temperature, humidity float64) float64 {
+	if temperature < heatIndexMinTemperature {
+		return temperature
+	}
+	t, rh := temperature*9/5+32, humidity
+	hi := -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+		0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+	switch {
+	case rh < 13 && t <= 112:
+		hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	case rh > 85 && t <= 87:
+		hi += (rh - 85) / 10 * (87 - t) / 5
+	}
+	return (hi - 32) * 5 / 9
+}
+
+// WindChill returns the temperature (°C) felt at a temperature (°C) and wind speed (m/s) by the
+// wind chill index of Environment Canada and the US National Weather Service (2001). At
+// temperatures above 10°C or wind under 4.8 km/h it is the temperature.
+func WindChill(temperature, windSpeed float64) float64 {
+	v := windSpeed * 3.6 // km/h
+	if temperature > windChillMaxTemperature || v < windChillMinWindSpeed {
+		return temperature
+	}
+	p := math.Pow(v, 0.16)
+	return 13.12 + 0.6215*temperature - 11.37*p + 0.3965*temperature*p
+}
+
+// Humidex returns the humidity index of Environment Canada (Masterton and Richardson 1979) at a
+// temperature and dew point (°C): the temperature felt in humid heat
+func Humidex(temperature, dewPoint float64) float64 {
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPoint))) // hPa
+	return temperature + 0.5555*(vapourPressure-10)
+}
+
+// ApparentTemperature returns the temperature (°C) felt in the shade at a temperature (°C),
+// relative humidity (%) and wind speed (m/s) by Steadman's formula (1994), as used by the
+// Australian Bureau of Meteorology
+func ApparentTemperature(temperature, humidity, windSpeed float64) float64 {
+	vapourPressure := humidity / 100 * 6.105 * math.Exp(17.27*temperature/(237.7+temperature)) // hPa
+	return temperature + 0.33*vapourPressure - 0.70*windSpeed - 4.00
+}
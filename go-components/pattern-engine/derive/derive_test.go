@@ -0,0 +1,45 @@
+package derive
+
+import (
+	"math"
+	"testing"
+
+	"pattern-engine/models"
+)
+
+// TestFormulas tests the derived variables against published tables
+func TestFormulas(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"Dew point at 20°C and 50%", DewPoint(20, 50), 9.26},
+		{"Heat index at 90°F and 70% (NWS chart: 106°F)", HeatIndex(32.22, 70), 41.1},
+		{"Heat index below its range", HeatIndex(20, 90), 20},
+		{"Wind chill at -10°C in 20 km/h (Environment Canada: -18)", WindChill(-10, 20/3.6), -17.9},
+		{"Wind chill above its range", WindChill(15, 10), 15},
+		{"Humidex at 30°C with a dew point of 20°C (Environment Canada: 38)", Humidex(30, 20), 37.6},
+		{"Apparent temperature at 25°C, 50% and 2 m/s", ApparentTemperature(25, 50, 2), 24.8},
+	} {
+		if math.Abs(tt.got-tt.want) > 0.05 {
+			t.Errorf("%s: got %.2f, want %.2f", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// TestApply tests that every reading gets its derived variables, and no dew point without humidity
+func TestApply(t *testing.T) {
+	readings := []models.WeatherPoint{
+		{Temperature: -10, Humidity: 80, WindSpeed: 20 / 3.6},
+		{Temperature: 20},
+	}
+	Apply(readings)
+
+	if d := readings[0].Derived; d.WindChill != WindChill(-10, 20/3.6) || d.DewPoint != DewPoint(-10, 80) ||
+		d.ApparentTemperature != ApparentTemperature(-10, 80, 20/3.6) {
+		t.Errorf("Unexpected derived variables %+v", d)
+	}
+	if d := readings[1].Derived; d.DewPoint != 0 || d.HeatIndex != 20 {
+		t.Errorf("Expected no dew point without humidity, got %+v", d)
+	}
+}
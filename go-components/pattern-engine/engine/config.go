@@ -0,0 +1,875 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"pattern-engine/analysis"
+	"pattern-engine/forecasting"
+	"pattern-engine/models"
+	"pattern-engine/rules"
+	"weathermodels/units"
+)
+
+// anomalyMethods are the scoring methods of the anomaly detector
+var anomalyMethods = []string{analysis.AnomalyMethodStdDev, analysis.AnomalyMethodMAD, analysis.AnomalyMethodIQR}
+
+// anomalyVariables are the variables the anomaly detector scores, which can each have a method
+var anomalyVariables = []string{"temperature", "pressure", "humidity", "wind_speed"}
+
+// Config is the "analysis" section of the shared config file
+type Config struct {
+	Analyzers  []string                  `json:"analyzers"`  // Analyzers to run, e.g. ["trends", "anomalies"] (empty = all)
+	Thresholds analysis.Thresholds       `json:"thresholds"` // Limits of the built-in analyzers; omitted keys keep their defaults
+	Profiles   []Profile                 `json:"profiles"`   // Threshold overrides for particular locations, tried in order
+	Forecast   forecasting.Config        `json:"forecast"`   // Settings of the forecast analyzer
+	Resample   analysis.ResampleSettings `json:"resample"`   // Uniform grid of the regression and spectral analyses
+	Rules      []rules.Rule              `json:"rules"`      // Alert rules evaluated against each location's readings
+}
+
+// Profile overrides the anomaly and pattern thresholds for the locations it names or whose
+// latitude falls in its band, such as tropical or polar stations
+type Profile struct {
+	Name        string          `json:"name"`         // Profile name, used in logs
+	Locations   []string        `json:"locations"`    // Location names, matched case-insensitively
+	MinLatitude *float64        `json:"min_latitude"` // Latitude band, inclusive; an open end is unbounded
+	MaxLatitude *float64        `json:"max_latitude"`
+	Anomalies   json.RawMessage `json:"anomalies"` // Keys of analysis.thresholds.anomalies to override
+	Patterns    json.RawMessage `json:"patterns"`  // Keys of analysis.thresholds.patterns to override
+
+	// Thresholds are the analysis thresholds with the overrides applied, set by LoadConfig
+	Thresholds analysis.Thresholds `json:"-"`
+}
+
+// Matches reports whether the profile applies to a location
+func (p *Profile) Matches(location *models.LocationData) bool {
+	for _, name := range p.Locations {
+		if strings.EqualFold(name, location.Name) {
+			return true
+		}
+	}
+	if p.MinLatitude == nil && p.MaxLatitude == nil {
+		return false
+	}
+	lat := location.Coordinates.Latitude
+	return (p.MinLatitude == nil || lat >= *p.MinLatitude) && (p.MaxLatitude == nil || lat <= *p.MaxLatitude)
+}
+
+// ValidationError represents a configuration validation error, like the collector's
+type ValidationError struct {
+	Field   string // The configuration field that failed validation
+	Value   any    // The invalid value
+	Message string // Human-readable error message
+}
+
+// Error implements the error interface for ValidationError
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("config validation failed for '%s': %s (value: %v)",
+		e.Field, e.Message, e.Value)
+}
+
+// DefaultConfig returns the analysis settings used when no config file is given: every analyzer
+// runs with the default thresholds and forecast settings on the readings as they are
+func DefaultConfig() Config {
+	return Config{
+		Thresholds: analysis.DefaultThresholds(),
+		Forecast:   forecasting.DefaultConfig(),
+		Resample:   analysis.DefaultResampleSettings(),
+	}
+}
+
+// LoadConfig reads the "analysis" section of a config file, falling back to defaults if path is
+// empty, and validates it
+func LoadConfig(path string) (Config, error) {
+	file := struct {
+		Analysis Config `json:"analysis"`
+	}{Analysis: DefaultConfig()}
+
+	if path == "" {
+		return file.Analysis, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file.Analysis, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file.Analysis, err
+	}
+	if err := ValidateThresholds(file.Analysis.Thresholds); err != nil {
+		return file.Analysis, err
+	}
+	if err := resolveProfiles(&file.Analysis); err != nil {
+		return file.Analysis, err
+	}
+	if err := validateForecast(file.Analysis.Forecast); err != nil {
+		return file.Analysis, err
+	}
+	if err := validateResample(file.Analysis.Resample); err != nil {
+		return file.Analysis, err
+	}
+	if err := compileRules(file.Analysis.Rules); err != nil {
+		return file.Analysis, err
+	}
+	return file.Analysis, nil
+}
+
+// LoadUnits reads the "units" section of a config file, which the collector shares, and returns
+// the units analyses are written in; without a path or section they are metric
+func LoadUnits(path string) (units.System, error) {
+	if path == "" {
+		return units.Metric(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return units.Metric(), err
+	}
+	var file struct {
+		Units units.Config `json:"units"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return units.Metric(), err
+	}
+	system, err := file.Units.Resolve()
+	if err != nil {
+		return units.Metric(), ValidationError{
+			Field:   "units",
+			Value:   file.Units,
+			Message: err.Error(),
+		}
+	}
+	return system, nil
+}
+
+// compileRules checks the names and severities of the alert rules and compiles their conditions
+func compileRules(alertRules []rules.Rule) error {
+	names := make(map[string]bool, len(alertRules))
+	for i := range alertRules {
+		rule := &alertRules[i]
+		field := fmt.Sprintf("analysis.rules[%d]", i)
+		if rule.Name == "" || names[rule.Name] {
+			return ValidationError{
+				Field:   field + ".name",
+				Value:   rule.Name,
+				Message: "rule name must be unique and non-empty",
+			}
+		}
+		names[rule.Name] = true
+
+		if rule.Severity == "" {
+			rule.Severity = rules.SeverityModerate
+		}
+		if !slices.Contains(rules.Severities, rule.Severity) {
+			return ValidationError{
+				Field:   field + ".severity",
+				Value:   rule.Severity,
+				Message: "severity must be one of " + strings.Join(rules.Severities, ", "),
+			}
+		}
+
+		if err := rule.Compile(); err != nil {
+			return ValidationError{
+				Field:   field + ".condition",
+				Value:   rule.Condition,
+				Message: err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// validateForecast checks the forecast settings
+func validateForecast(cfg forecasting.Config) error {
+	if cfg.Model != forecasting.ModelTrend && cfg.Model != forecasting.ModelHoltWinters {
+		return ValidationError{
+			Field:   "analysis.forecast.model",
+			Value:   cfg.Model,
+			Message: "forecast model must be " + forecasting.ModelTrend + " or " + forecasting.ModelHoltWinters,
+		}
+	}
+
+	if cfg.HorizonHours < 1 {
+		return ValidationError{
+			Field:   "analysis.forecast.horizon_hours",
+			Value:   cfg.HorizonHours,
+			Message: "forecast horizon must be at least 1 hour",
+		}
+	}
+
+	if cfg.WindowHours < 1 {
+		return ValidationError{
+			Field:   "analysis.forecast.window_hours",
+			Value:   cfg.WindowHours,
+			Message: "forecast window must be at least 1 hour",
+		}
+	}
+
+	if cfg.Confidence <= 0 || cfg.Confidence >= 1 {
+		return ValidationError{
+			Field:   "analysis.forecast.confidence",
+			Value:   cfg.Confidence,
+			Message: "forecast confidence must be between 0 and 1 (exclusive)",
+		}
+	}
+
+	for _, smoothing := range []struct {
+		field string
+		value float64
+	}{
+		{"level_smoothing", cfg.LevelSmoothing},
+		{"trend_smoothing", cfg.TrendSmoothing},
+		{"seasonal_smoothing", cfg.SeasonalSmoothing},
+	} {
+		if smoothing.value <= 0 || smoothing.value > 1 {
+			return ValidationError{
+				Field:   "analysis.forecast." + smoothing.field,
+				Value:   smoothing.value,
+				Message: "smoothing factor must be above 0 and at most 1",
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateResample checks the resampling settings
+func validateResample(settings analysis.ResampleSettings) error {
+	if settings.Interval < 0 {
+		return ValidationError{
+			Field:   "analysis.resample.interval",
+			Value:   settings.Interval,
+			Message: "resample interval must not be negative",
+		}
+	}
+
+	if settings.Interval > 0 && settings.MaxGap < settings.Interval {
+		return ValidationError{
+			Field:   "analysis.resample.max_gap",
+			Value:   settings.MaxGap,
+			Message: "longest gap interpolated across must be at least the resample interval",
+		}
+	}
+
+	return nil
+}
+
+// resolveProfiles applies the overrides of each profile to the configured thresholds and
+// validates the result
+func resolveProfiles(cfg *Config) error {
+	names := make(map[string]bool, len(cfg.Profiles))
+	for i := range cfg.Profiles {
+		p := &cfg.Profiles[i]
+		field := fmt.Sprintf("analysis.profiles[%d]", i)
+		if p.Name == "" || names[p.Name] {
+			return ValidationError{
+				Field:   field + ".name",
+				Value:   p.Name,
+				Message: "profile name must be unique and non-empty",
+			}
+		}
+		names[p.Name] = true
+		field = "analysis.profiles." + p.Name
+
+		if len(p.Locations) == 0 && p.MinLatitude == nil && p.MaxLatitude == nil {
+			return ValidationError{
+				Field:   field,
+				Value:   p.Name,
+				Message: "profile must name locations or set a latitude band",
+			}
+		}
+		for name, lat := range map[string]*float64{"min_latitude": p.MinLatitude, "max_latitude": p.MaxLatitude} {
+			if lat != nil && (*lat < -90 || *lat > 90) {
+				return ValidationError{
+					Field:   field + "." + name,
+					Value:   *lat,
+					Message: "latitude must be between -90 and 90",
+				}
+			}
+		}
+		if p.MinLatitude != nil && p.MaxLatitude != nil && *p.MinLatitude > *p.MaxLatitude {
+			return ValidationError{
+				Field:   field + ".max_latitude",
+				Value:   *p.MaxLatitude,
+				Message: "latitude band maximum cannot be below its minimum",
+			}
+		}
+
+		p.Thresholds = cfg.Thresholds
+		p.Thresholds.Anomalies.Methods = maps.Clone(cfg.Thresholds.Anomalies.Methods) // Not shared with the overrides
+		if len(p.Anomalies) > 0 {
+			if err := json.Unmarshal(p.Anomalies, &p.Thresholds.Anomalies); err != nil {
+				return fmt.Errorf("%s.anomalies: %w", field, err)
+			}
+		}
+		if len(p.Patterns) > 0 {
+			if err := json.Unmarshal(p.Patterns, &p.Thresholds.Patterns); err != nil {
+				return fmt.Errorf("%s.patterns: %w", field, err)
+			}
+		}
+		if err := validateThresholds(field, p.Thresholds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateThresholds checks analysis thresholds, returning a ValidationError for the first
+// invalid one
+func ValidateThresholds(t analysis.Thresholds) error {
+	return validateThresholds("analysis.thresholds", t)
+}
+
+// validateThresholds checks thresholds configured under prefix
+func validateThresholds(prefix string, t analysis.Thresholds) error {
+	trends := t.Trends
+	if trends.Method != analysis.TrendMethodLeastSquares && trends.Method != analysis.TrendMethodMannKendall {
+		return ValidationError{
+			Field:   prefix + ".trends.method",
+			Value:   trends.Method,
+			Message: "trend method must be least_squares or mann_kendall",
+		}
+	}
+
+	if trends.MinReadingsForAnalysis < 2 {
+		return ValidationError{
+			Field:   prefix + ".trends.min_readings",
+			Value:   trends.MinReadingsForAnalysis,
+			Message: "trend analysis needs at least 2 readings",
+		}
+	}
+
+	if trends.MinTrendSignificance < 0 {
+		return ValidationError{
+			Field:   prefix + ".trends.min_significance",
+			Value:   trends.MinTrendSignificance,
+			Message: "minimum trend significance cannot be negative",
+		}
+	}
+
+	for _, rate := range []struct {
+		field string
+		value float64
+	}{
+		{"temperature_rate", trends.TemperatureRate},
+		{"pressure_rate", trends.PressureRate},
+		{"humidity_rate", trends.HumidityRate},
+		{"wind_speed_rate", trends.WindSpeedRate},
+		{"wind_direction_rate", trends.WindDirectionRate},
+	} {
+		if rate.value < 0 {
+			return ValidationError{
+				Field:   prefix + ".trends." + rate.field,
+				Value:   rate.value,
+				Message: "trend rate cannot be negative",
+			}
+		}
+	}
+
+	if trends.ConfidenceLevel <= 0 || trends.ConfidenceLevel >= 1 {
+		return ValidationError{
+			Field:   prefix + ".trends.confidence_level",
+			Value:   trends.ConfidenceLevel,
+			Message: "confidence level must be between 0 and 1 (exclusive)",
+		}
+	}
+
+	if trends.Alpha <= 0 || trends.Alpha >= 1 {
+		return ValidationError{
+			Field:   prefix + ".trends.alpha",
+			Value:   trends.Alpha,
+			Message: "significance level must be between 0 and 1 (exclusive)",
+		}
+	}
+
+	anomalies := t.Anomalies
+	if anomalies.AnomalyThresholdFactor <= 0 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.threshold_factor",
+			Value:   anomalies.AnomalyThresholdFactor,
+			Message: "anomaly threshold factor must be positive",
+		}
+	}
+
+	if anomalies.MinReadingsForBaseline < 2 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.min_baseline_readings",
+			Value:   anomalies.MinReadingsForBaseline,
+			Message: "an anomaly baseline needs at least 2 readings",
+		}
+	}
+
+	for _, variable := range slices.Sorted(maps.Keys(anomalies.Methods)) {
+		method := anomalies.Methods[variable]
+		if !slices.Contains(anomalyVariables, variable) {
+			return ValidationError{
+				Field:   prefix + ".anomalies.methods",
+				Value:   variable,
+				Message: "unknown variable; expected one of " + strings.Join(anomalyVariables, ", "),
+			}
+		}
+		if !slices.Contains(anomalyMethods, method) {
+			return ValidationError{
+				Field:   prefix + ".anomalies.methods." + variable,
+				Value:   method,
+				Message: "anomaly method must be std_dev, mad or iqr",
+			}
+		}
+	}
+
+	if !slices.Contains(anomalyMethods, anomalies.Method) {
+		return ValidationError{
+			Field:   prefix + ".anomalies.method",
+			Value:   anomalies.Method,
+			Message: "anomaly method must be std_dev, mad or iqr",
+		}
+	}
+
+	if anomalies.IQRFactor <= 0 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.iqr_factor",
+			Value:   anomalies.IQRFactor,
+			Message: "IQR factor must be positive",
+		}
+	}
+
+	if anomalies.BaselineWindow < 0 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.baseline_window",
+			Value:   anomalies.BaselineWindow,
+			Message: "baseline window cannot be negative (0 compares to the whole series)",
+		}
+	}
+
+	if anomalies.ModerateSeverityFactor <= 0 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.moderate_severity_factor",
+			Value:   anomalies.ModerateSeverityFactor,
+			Message: "moderate severity factor must be positive",
+		}
+	}
+
+	if anomalies.HighSeverityFactor < anomalies.ModerateSeverityFactor {
+		return ValidationError{
+			Field:   prefix + ".anomalies.high_severity_factor",
+			Value:   anomalies.HighSeverityFactor,
+			Message: "high severity factor cannot be below the moderate severity factor",
+		}
+	}
+
+	if anomalies.PressureChangeWindow <= 0 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.pressure_change_window",
+			Value:   anomalies.PressureChangeWindow,
+			Message: "pressure change window must be positive",
+		}
+	}
+
+	if anomalies.PressureChange <= 0 {
+		return ValidationError{
+			Field:   prefix + ".anomalies.pressure_change",
+			Value:   anomalies.PressureChange,
+			Message: "pressure change threshold must be positive",
+		}
+	}
+
+	if anomalies.HighPressureChange < anomalies.PressureChange {
+		return ValidationError{
+			Field:   prefix + ".anomalies.high_pressure_change",
+			Value:   anomalies.HighPressureChange,
+			Message: "high pressure change cannot be below the pressure change threshold",
+		}
+	}
+
+	multivariate := t.Multivariate
+	if multivariate.MinReadings < 3 {
+		return ValidationError{
+			Field:   prefix + ".multivariate.min_readings",
+			Value:   multivariate.MinReadings,
+			Message: "multivariate anomalies need at least 3 readings",
+		}
+	}
+
+	if multivariate.Alpha <= 0 || multivariate.Alpha >= 1 {
+		return ValidationError{
+			Field:   prefix + ".multivariate.alpha",
+			Value:   multivariate.Alpha,
+			Message: "significance level must be between 0 and 1 (exclusive)",
+		}
+	}
+
+	patterns := t.Patterns
+	if patterns.MinPatternConfidence <= 0 || patterns.MinPatternConfidence > 1 {
+		return ValidationError{
+			Field:   prefix + ".patterns.min_confidence",
+			Value:   patterns.MinPatternConfidence,
+			Message: "minimum pattern confidence must be between 0 (exclusive) and 1",
+		}
+	}
+
+	if patterns.TemperatureStep <= 0 {
+		return ValidationError{
+			Field:   prefix + ".patterns.temperature_step",
+			Value:   patterns.TemperatureStep,
+			Message: "temperature step must be positive",
+		}
+	}
+
+	if patterns.LowPressure >= patterns.HighPressure {
+		return ValidationError{
+			Field:   prefix + ".patterns.low_pressure",
+			Value:   patterns.LowPressure,
+			Message: "low pressure threshold must be below the high pressure threshold",
+		}
+	}
+
+	if patterns.PrecipitationMm < 0 {
+		return ValidationError{
+			Field:   prefix + ".patterns.precipitation_mm",
+			Value:   patterns.PrecipitationMm,
+			Message: "precipitation threshold cannot be negative",
+		}
+	}
+
+	if patterns.PrecipitationProbability < 0 || patterns.PrecipitationProbability > 100 {
+		return ValidationError{
+			Field:   prefix + ".patterns.precipitation_probability",
+			Value:   patterns.PrecipitationProbability,
+			Message: "precipitation probability must be between 0 and 100",
+		}
+	}
+
+	if patterns.IntermittentPrecipitation < 0 || patterns.ConsistentPrecipitation > 1 ||
+		patterns.IntermittentPrecipitation > patterns.ConsistentPrecipitation {
+		return ValidationError{
+			Field:   prefix + ".patterns.intermittent_precipitation",
+			Value:   patterns.IntermittentPrecipitation,
+			Message: "precipitation shares must be between 0 and 1, intermittent no more than consistent",
+		}
+	}
+
+	if patterns.FrontWindow <= 0 {
+		return ValidationError{
+			Field:   prefix + ".patterns.front_window",
+			Value:   patterns.FrontWindow,
+			Message: "front window must be positive",
+		}
+	}
+
+	if patterns.FrontPressureFall <= 0 || patterns.FrontPressureRise <= 0 {
+		return ValidationError{
+			Field:   prefix + ".patterns.front_pressure_fall",
+			Value:   patterns.FrontPressureFall,
+			Message: "pressure fall and rise of a front must be positive",
+		}
+	}
+
+	if patterns.FrontWindShift < 0 || patterns.FrontWindShift > 180 {
+		return ValidationError{
+			Field:   prefix + ".patterns.front_wind_shift",
+			Value:   patterns.FrontWindShift,
+			Message: "wind shift must be between 0 and 180 degrees",
+		}
+	}
+
+	if patterns.FrontTemperatureChange <= 0 || patterns.FrontHumiditySpike <= 0 {
+		return ValidationError{
+			Field:   prefix + ".patterns.front_temperature_change",
+			Value:   patterns.FrontTemperatureChange,
+			Message: "temperature change and humidity spike of a front must be positive",
+		}
+	}
+
+	for _, hours := range t.Statistics.RollingWindowHours {
+		if hours < 1 {
+			return ValidationError{
+				Field:   prefix + ".statistics.rolling_window_hours",
+				Value:   hours,
+				Message: "rolling windows must be at least 1 hour",
+			}
+		}
+	}
+
+	seasonality := t.Seasonality
+	if seasonality.MinSpanHours < 24 {
+		return ValidationError{
+			Field:   prefix + ".seasonality.min_span_hours",
+			Value:   seasonality.MinSpanHours,
+			Message: "a daily cycle needs readings spanning at least 24 hours",
+		}
+	}
+
+	if seasonality.MinStrength <= 0 || seasonality.MinStrength > 1 {
+		return ValidationError{
+			Field:   prefix + ".seasonality.min_strength",
+			Value:   seasonality.MinStrength,
+			Message: "minimum cycle strength must be between 0 (exclusive) and 1",
+		}
+	}
+
+	if t.Correlations.MaxLagHours < 0 {
+		return ValidationError{
+			Field:   prefix + ".correlations.max_lag_hours",
+			Value:   t.Correlations.MaxLagHours,
+			Message: "maximum lag cannot be negative",
+		}
+	}
+
+	spectrum := t.Spectrum
+	if spectrum.MaxPeaks < 1 {
+		return ValidationError{
+			Field:   prefix + ".spectrum.max_peaks",
+			Value:   spectrum.MaxPeaks,
+			Message: "at least 1 period must be reported per variable",
+		}
+	}
+
+	if spectrum.MinPowerShare < 0 || spectrum.MinPowerShare > 1 {
+		return ValidationError{
+			Field:   prefix + ".spectrum.min_power_share",
+			Value:   spectrum.MinPowerShare,
+			Message: "minimum power share must be between 0 and 1",
+		}
+	}
+
+	storm := t.Storm
+	if storm.TendencyWindow <= 0 {
+		return ValidationError{
+			Field:   prefix + ".storm.tendency_window",
+			Value:   storm.TendencyWindow,
+			Message: "tendency window must be positive",
+		}
+	}
+
+	for _, limit := range []struct {
+		field string
+		value float64
+	}{
+		{"pressure_fall_rate", storm.PressureFallRate},
+		{"wind_speed", storm.WindSpeed},
+		{"wind_speed_rise", storm.WindSpeedRise},
+	} {
+		if limit.value <= 0 {
+			return ValidationError{
+				Field:   prefix + ".storm." + limit.field,
+				Value:   limit.value,
+				Message: "storm limit must be positive",
+			}
+		}
+	}
+
+	if storm.AlertScore <= 0 || storm.HighScore < storm.AlertScore || storm.HighScore > 1 {
+		return ValidationError{
+			Field:   prefix + ".storm.alert_score",
+			Value:   storm.AlertScore,
+			Message: "storm scores must be between 0 (exclusive) and 1, the alert score no more than the high score",
+		}
+	}
+
+	fog := t.Fog
+	if fog.MaxSpread <= 0 || fog.MaxWindSpeed < 0 {
+		return ValidationError{
+			Field:   prefix + ".fog.max_spread",
+			Value:   fog.MaxSpread,
+			Message: "fog spread must be positive and wind speed not negative",
+		}
+	}
+
+	if fog.MinHumidity < 0 || fog.MinHumidity > 100 {
+		return ValidationError{
+			Field:   prefix + ".fog.min_humidity",
+			Value:   fog.MinHumidity,
+			Message: "fog humidity must be between 0 and 100",
+		}
+	}
+
+	for _, hour := range []struct {
+		field string
+		value float64
+	}{
+		{"night_start_hour", fog.NightStartHour},
+		{"night_end_hour", fog.NightEndHour},
+	} {
+		if hour.value < 0 || hour.value >= 24 {
+			return ValidationError{
+				Field:   prefix + ".fog." + hour.field,
+				Value:   hour.value,
+				Message: "hour must be between 0 and 24",
+			}
+		}
+	}
+
+	extremes := t.Extremes
+	if extremes.MinDays < 1 {
+		return ValidationError{
+			Field:   prefix + ".extremes.min_days",
+			Value:   extremes.MinDays,
+			Message: "an extreme event lasts at least 1 day",
+		}
+	}
+
+	if extremes.ColdPercentile < 0 || extremes.ColdPercentile >= extremes.HeatPercentile || extremes.HeatPercentile > 100 {
+		return ValidationError{
+			Field:   prefix + ".extremes.heat_percentile",
+			Value:   extremes.HeatPercentile,
+			Message: "percentiles must be between 0 and 100, the cold percentile below the heat percentile",
+		}
+	}
+
+	if extremes.MinBaselineDays < 2 {
+		return ValidationError{
+			Field:   prefix + ".extremes.min_baseline_days",
+			Value:   extremes.MinBaselineDays,
+			Message: "percentiles of the daily highs need at least 2 days",
+		}
+	}
+
+	wind := t.Wind
+	if wind.HighWindSpeed <= 0 || wind.HighGustSpeed <= 0 {
+		return ValidationError{
+			Field:   prefix + ".wind.high_wind_speed",
+			Value:   wind.HighWindSpeed,
+			Message: "high wind and gust speeds must be positive",
+		}
+	}
+
+	precipitation := t.Precipitation
+	if precipitation.WetHour <= 0 || precipitation.ModerateRate <= precipitation.WetHour || precipitation.HeavyRate <= precipitation.ModerateRate {
+		return ValidationError{
+			Field:   prefix + ".precipitation.moderate_rate",
+			Value:   precipitation.ModerateRate,
+			Message: "precipitation amounts must be positive, the wet hour below the moderate rate and that below the heavy rate",
+		}
+	}
+
+	agro := t.Agro
+	if agro.UpperTemperature <= agro.BaseTemperature {
+		return ValidationError{
+			Field:   prefix + ".agro.upper_temperature",
+			Value:   agro.UpperTemperature,
+			Message: "upper temperature must be above the base temperature",
+		}
+	}
+
+	if agro.ChillMax <= agro.ChillMin {
+		return ValidationError{
+			Field:   prefix + ".agro.chill_max",
+			Value:   agro.ChillMax,
+			Message: "chill hour maximum must be above the minimum",
+		}
+	}
+
+	energy := t.Energy
+	if energy.PerformanceRatio <= 0 || energy.PerformanceRatio > 1 {
+		return ValidationError{
+			Field:   prefix + ".energy.performance_ratio",
+			Value:   energy.PerformanceRatio,
+			Message: "performance ratio must be above 0 and at most 1",
+		}
+	}
+
+	if energy.CutInSpeed <= 0 || energy.RatedSpeed <= energy.CutInSpeed || energy.CutOutSpeed <= energy.RatedSpeed {
+		return ValidationError{
+			Field:   prefix + ".energy.rated_speed",
+			Value:   energy.RatedSpeed,
+			Message: "turbine speeds must be positive, the cut-in speed below the rated speed and that below the cut-out speed",
+		}
+	}
+
+	if energy.HubHeight <= 0 || energy.ShearExponent < 0 {
+		return ValidationError{
+			Field:   prefix + ".energy.hub_height",
+			Value:   energy.HubHeight,
+			Message: "hub height must be positive and the shear exponent cannot be negative",
+		}
+	}
+
+	icing := t.RoadIcing
+	if icing.MaxTemperature <= 0 || icing.NightCooling < 0 || icing.PrecipitationWindow <= 0 || icing.WetPrecipitation <= 0 {
+		return ValidationError{
+			Field:   prefix + ".road_icing.max_temperature",
+			Value:   icing.MaxTemperature,
+			Message: "road icing limits must be positive and the night cooling cannot be negative",
+		}
+	}
+
+	if icing.MinHumidity < 0 || icing.MinHumidity >= 100 {
+		return ValidationError{
+			Field:   prefix + ".road_icing.min_humidity",
+			Value:   icing.MinHumidity,
+			Message: "minimum humidity must be from 0 to below 100",
+		}
+	}
+
+	if icing.AlertScore <= 0 || icing.HighScore < icing.AlertScore || icing.HighScore > 1 {
+		return ValidationError{
+			Field:   prefix + ".road_icing.alert_score",
+			Value:   icing.AlertScore,
+			Message: "road icing scores must be between 0 (exclusive) and 1, the alert score no more than the high score",
+		}
+	}
+
+	comfort := t.Comfort
+	if comfort.ComfortMax <= comfort.ComfortMin {
+		return ValidationError{
+			Field:   prefix + ".comfort.comfort_max",
+			Value:   comfort.ComfortMax,
+			Message: "comfortable maximum must be above the minimum",
+		}
+	}
+
+	if comfort.TrendWindow <= 0 || comfort.TrendChange <= 0 {
+		return ValidationError{
+			Field:   prefix + ".comfort.trend_window",
+			Value:   comfort.TrendWindow,
+			Message: "comfort trend window and change must be positive",
+		}
+	}
+
+	quality := t.DataQuality
+	if quality.GapFactor <= 1 {
+		return ValidationError{
+			Field:   prefix + ".data_quality.gap_factor",
+			Value:   quality.GapFactor,
+			Message: "gap factor must be above 1",
+		}
+	}
+
+	if quality.StuckReadings < 2 {
+		return ValidationError{
+			Field:   prefix + ".data_quality.stuck_readings",
+			Value:   quality.StuckReadings,
+			Message: "a stuck value needs at least 2 readings",
+		}
+	}
+
+	if quality.StaleAfter <= 0 {
+		return ValidationError{
+			Field:   prefix + ".data_quality.stale_after",
+			Value:   quality.StaleAfter,
+			Message: "stale age must be positive",
+		}
+	}
+
+	regional := t.Regional
+	if regional.MaxLagHours < 0 {
+		return ValidationError{
+			Field:   prefix + ".regional.max_lag_hours",
+			Value:   regional.MaxLagHours,
+			Message: "maximum lag cannot be negative",
+		}
+	}
+
+	if regional.MinCorrelation < 0 || regional.MinCorrelation > 1 {
+		return ValidationError{
+			Field:   prefix + ".regional.min_correlation",
+			Value:   regional.MinCorrelation,
+			Message: "minimum correlation must be between 0 and 1",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"pattern-engine/analysis"
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// writeConfig writes a config file for LoadConfig
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadConfigThresholds tests that thresholds in the analysis section override only the keys
+// they set
+func TestLoadConfigThresholds(t *testing.T) {
+	path := writeConfig(t, `{"analysis": {"thresholds": {"patterns": {"high_pressure": 1025}, "anomalies": {"threshold_factor": 2.5, "pressure_change_window": 10800000000000}}}}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	defaults := analysis.DefaultThresholds()
+	if cfg.Thresholds.Patterns.HighPressure != 1025 || cfg.Thresholds.Patterns.LowPressure != defaults.Patterns.LowPressure {
+		t.Errorf("Unexpected pattern thresholds: %+v", cfg.Thresholds.Patterns)
+	}
+	if cfg.Thresholds.Anomalies.AnomalyThresholdFactor != 2.5 || cfg.Thresholds.Anomalies.PressureChangeWindow != 3*time.Hour ||
+		cfg.Thresholds.Anomalies.MinReadingsForBaseline != defaults.Anomalies.MinReadingsForBaseline {
+		t.Errorf("Unexpected anomaly thresholds: %+v", cfg.Thresholds.Anomalies)
+	}
+	if cfg.Thresholds.Trends != defaults.Trends {
+		t.Errorf("Expected default trend thresholds, got %+v", cfg.Thresholds.Trends)
+	}
+
+	if cfg, err := LoadConfig(""); err != nil || !reflect.DeepEqual(cfg.Thresholds, defaults) || len(cfg.Profiles) != 0 {
+		t.Errorf("Expected default thresholds without a config file, got %+v (err: %v)", cfg.Thresholds, err)
+	}
+}
+
+// TestLoadConfigAnomalyMethods tests per-variable anomaly methods, and that a profile's methods
+// do not leak into the configured thresholds
+func TestLoadConfigAnomalyMethods(t *testing.T) {
+	path := writeConfig(t, `{"analysis": {
+		"thresholds": {"anomalies": {"method": "mad", "methods": {"wind_speed": "iqr"}}},
+		"profiles": [{"name": "coast", "locations": ["Brest"], "anomalies": {"methods": {"pressure": "std_dev"}}}]
+	}}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	want := map[string]string{"wind_speed": "iqr"}
+	if anomalies := cfg.Thresholds.Anomalies; anomalies.Method != analysis.AnomalyMethodMAD || !reflect.DeepEqual(anomalies.Methods, want) {
+		t.Errorf("Unexpected anomaly methods: %+v", anomalies)
+	}
+	want["pressure"] = "std_dev"
+	if coast := cfg.Profiles[0].Thresholds.Anomalies; !reflect.DeepEqual(coast.Methods, want) {
+		t.Errorf("Expected the profile to add pressure to the methods, got %v", coast.Methods)
+	}
+}
+
+// TestLoadConfigInvalidThresholds tests that invalid thresholds are rejected with the failing field
+func TestLoadConfigInvalidThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		field  string
+	}{
+		{"Too few trend readings", `{"analysis": {"thresholds": {"trends": {"min_readings": 1}}}}`, "analysis.thresholds.trends.min_readings"},
+		{"Negative trend rate", `{"analysis": {"thresholds": {"trends": {"pressure_rate": -0.5}}}}`, "analysis.thresholds.trends.pressure_rate"},
+		{"Negative wind direction rate", `{"analysis": {"thresholds": {"trends": {"wind_direction_rate": -5}}}}`, "analysis.thresholds.trends.wind_direction_rate"},
+		{"Trend confidence of 1", `{"analysis": {"thresholds": {"trends": {"confidence_level": 1}}}}`, "analysis.thresholds.trends.confidence_level"},
+		{"Zero alpha", `{"analysis": {"thresholds": {"trends": {"alpha": 0}}}}`, "analysis.thresholds.trends.alpha"},
+		{"Unknown trend method", `{"analysis": {"thresholds": {"trends": {"method": "lowess"}}}}`, "analysis.thresholds.trends.method"},
+		{"Zero anomaly factor", `{"analysis": {"thresholds": {"anomalies": {"threshold_factor": 0}}}}`, "analysis.thresholds.anomalies.threshold_factor"},
+		{"Unknown anomaly method", `{"analysis": {"thresholds": {"anomalies": {"method": "zscore"}}}}`, "analysis.thresholds.anomalies.method"},
+		{"Method of an unknown variable", `{"analysis": {"thresholds": {"anomalies": {"methods": {"visibility": "mad"}}}}}`, "analysis.thresholds.anomalies.methods"},
+		{"Unknown variable method", `{"analysis": {"thresholds": {"anomalies": {"methods": {"pressure": "zscore"}}}}}`, "analysis.thresholds.anomalies.methods.pressure"},
+		{"Zero IQR factor", `{"analysis": {"thresholds": {"anomalies": {"iqr_factor": 0}}}}`, "analysis.thresholds.anomalies.iqr_factor"},
+		{"Negative baseline window", `{"analysis": {"thresholds": {"anomalies": {"baseline_window": -1}}}}`, "analysis.thresholds.anomalies.baseline_window"},
+		{"Severity factors reversed", `{"analysis": {"thresholds": {"anomalies": {"high_severity_factor": 1.5}}}}`, "analysis.thresholds.anomalies.high_severity_factor"},
+		{"Multivariate alpha of 1", `{"analysis": {"thresholds": {"multivariate": {"alpha": 1}}}}`, "analysis.thresholds.multivariate.alpha"},
+		{"Confidence above 1", `{"analysis": {"thresholds": {"patterns": {"min_confidence": 1.2}}}}`, "analysis.thresholds.patterns.min_confidence"},
+		{"Pressure thresholds reversed", `{"analysis": {"thresholds": {"patterns": {"low_pressure": 1030}}}}`, "analysis.thresholds.patterns.low_pressure"},
+		{"Probability above 100", `{"analysis": {"thresholds": {"patterns": {"precipitation_probability": 150}}}}`, "analysis.thresholds.patterns.precipitation_probability"},
+		{"Wind shift past 180", `{"analysis": {"thresholds": {"patterns": {"front_wind_shift": 200}}}}`, "analysis.thresholds.patterns.front_wind_shift"},
+		{"Storm alert above high", `{"analysis": {"thresholds": {"storm": {"alert_score": 0.9}}}}`, "analysis.thresholds.storm.alert_score"},
+		{"Fog night ending at 24", `{"analysis": {"thresholds": {"fog": {"night_end_hour": 24}}}}`, "analysis.thresholds.fog.night_end_hour"},
+		{"Cold percentile above heat", `{"analysis": {"thresholds": {"extremes": {"cold_percentile": 95}}}}`, "analysis.thresholds.extremes.heat_percentile"},
+		{"Heavy below moderate rate", `{"analysis": {"thresholds": {"precipitation": {"heavy_rate": 2}}}}`, "analysis.thresholds.precipitation.moderate_rate"},
+		{"Gap factor of 1", `{"analysis": {"thresholds": {"data_quality": {"gap_factor": 1}}}}`, "analysis.thresholds.data_quality.gap_factor"},
+		{"Comfort maximum below minimum", `{"analysis": {"thresholds": {"comfort": {"comfort_max": 15}}}}`, "analysis.thresholds.comfort.comfort_max"},
+		{"Saturated icing humidity", `{"analysis": {"thresholds": {"road_icing": {"min_humidity": 100}}}}`, "analysis.thresholds.road_icing.min_humidity"},
+		{"Cut-out below rated speed", `{"analysis": {"thresholds": {"energy": {"cut_out_speed": 10}}}}`, "analysis.thresholds.energy.rated_speed"},
+		{"Upper below base temperature", `{"analysis": {"thresholds": {"agro": {"upper_temperature": 5}}}}`, "analysis.thresholds.agro.upper_temperature"},
+		{"Zero high wind speed", `{"analysis": {"thresholds": {"wind": {"high_wind_speed": 0}}}}`, "analysis.thresholds.wind.high_wind_speed"},
+		{"Empty rolling window", `{"analysis": {"thresholds": {"statistics": {"rolling_window_hours": [6, 0]}}}}`, "analysis.thresholds.statistics.rolling_window_hours"},
+		{"Seasonality span under a day", `{"analysis": {"thresholds": {"seasonality": {"min_span_hours": 12}}}}`, "analysis.thresholds.seasonality.min_span_hours"},
+		{"Negative lag", `{"analysis": {"thresholds": {"correlations": {"max_lag_hours": -1}}}}`, "analysis.thresholds.correlations.max_lag_hours"},
+		{"No spectral peaks", `{"analysis": {"thresholds": {"spectrum": {"max_peaks": 0}}}}`, "analysis.thresholds.spectrum.max_peaks"},
+		{"Power share over 1", `{"analysis": {"thresholds": {"spectrum": {"min_power_share": 1.5}}}}`, "analysis.thresholds.spectrum.min_power_share"},
+		{"Regional correlation over 1", `{"analysis": {"thresholds": {"regional": {"min_correlation": 2}}}}`, "analysis.thresholds.regional.min_correlation"},
+		{"Forecast without a horizon", `{"analysis": {"forecast": {"horizon_hours": 0}}}`, "analysis.forecast.horizon_hours"},
+		{"Forecast confidence of 1", `{"analysis": {"forecast": {"confidence": 1}}}`, "analysis.forecast.confidence"},
+		{"Unknown forecast model", `{"analysis": {"forecast": {"model": "arima"}}}`, "analysis.forecast.model"},
+		{"Zero seasonal smoothing", `{"analysis": {"forecast": {"model": "holt_winters", "seasonal_smoothing": 0}}}`, "analysis.forecast.seasonal_smoothing"},
+		{"Negative resample interval", `{"analysis": {"resample": {"interval": -1}}}`, "analysis.resample.interval"},
+		{"Resample gap under the interval", `{"analysis": {"resample": {"interval": 3600000000000, "max_gap": 1800000000000}}}`, "analysis.resample.max_gap"},
+		{"Unnamed profile", `{"analysis": {"profiles": [{"locations": ["Bergen"]}]}}`, "analysis.profiles[0].name"},
+		{"Profile matching nothing", `{"analysis": {"profiles": [{"name": "empty"}]}}`, "analysis.profiles.empty"},
+		{"Latitude out of range", `{"analysis": {"profiles": [{"name": "polar", "max_latitude": 95}]}}`, "analysis.profiles.polar.max_latitude"},
+		{"Latitude band reversed", `{"analysis": {"profiles": [{"name": "band", "min_latitude": 30, "max_latitude": 10}]}}`, "analysis.profiles.band.max_latitude"},
+		{"Unnamed rule", `{"analysis": {"rules": [{"condition": "temperature < 0"}]}}`, "analysis.rules[0].name"},
+		{"Unknown rule severity", `{"analysis": {"rules": [{"name": "frost", "condition": "temperature < 0", "severity": "extreme"}]}}`, "analysis.rules[0].severity"},
+		{"Rule of an unknown variable", `{"analysis": {"rules": [{"name": "frost", "condition": "visibility < 100"}]}}`, "analysis.rules[0].condition"},
+		{"Invalid profile override", `{"analysis": {"profiles": [{"name": "tropical", "max_latitude": 23.5, "patterns": {"min_confidence": 0}}]}}`, "analysis.profiles.tropical.patterns.min_confidence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.config))
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected a validation error for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+// TestLoadConfigProfiles tests that profile overrides apply on top of the configured thresholds
+func TestLoadConfigProfiles(t *testing.T) {
+	path := writeConfig(t, `{"analysis": {
+		"thresholds": {"patterns": {"high_pressure_mean": 1018}},
+		"profiles": [{"name": "tropical", "min_latitude": -23.5, "max_latitude": 23.5, "patterns": {"high_pressure": 1012, "high_pressure_mean": 1010}}]
+	}}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.Profiles) != 1 {
+		t.Fatalf("Expected 1 profile, got %d", len(cfg.Profiles))
+	}
+	tropical := cfg.Profiles[0].Thresholds
+	if tropical.Patterns.HighPressure != 1012 || tropical.Patterns.HighPressureMean != 1010 ||
+		tropical.Patterns.LowPressure != cfg.Thresholds.Patterns.LowPressure || !reflect.DeepEqual(tropical.Anomalies, cfg.Thresholds.Anomalies) {
+		t.Errorf("Unexpected profile thresholds: %+v", tropical)
+	}
+	if cfg.Thresholds.Patterns.HighPressureMean != 1018 {
+		t.Errorf("Expected the profile not to change the configured thresholds, got %+v", cfg.Thresholds.Patterns)
+	}
+
+	for _, tt := range []struct {
+		location models.LocationData
+		want     bool
+	}{
+		{models.LocationData{Name: "Singapore", Coordinates: models.Coordinates{Latitude: 1.35}}, true},
+		{models.LocationData{Name: "Rio de Janeiro", Coordinates: models.Coordinates{Latitude: -22.9}}, true},
+		{models.LocationData{Name: "Tromsø", Coordinates: models.Coordinates{Latitude: 69.65}}, false},
+	} {
+		if got := cfg.Profiles[0].Matches(&tt.location); got != tt.want {
+			t.Errorf("Matches(%s) = %v, want %v", tt.location.Name, got, tt.want)
+		}
+	}
+}
+
+// TestLoadUnits tests the units of the shared "units" section, with metric ones by default
+func TestLoadUnits(t *testing.T) {
+	if system, err := LoadUnits(""); err != nil || system != units.Metric() {
+		t.Errorf("Expected metric units without a config file, got %+v (err: %v)", system, err)
+	}
+	system, err := LoadUnits(writeConfig(t, `{"units": {"system": "imperial", "wind_speed": "kn"}}`))
+	if want := (units.System{Temperature: units.Fahrenheit, WindSpeed: units.Knots, Pressure: units.InchesOfMercury, Precipitation: units.Inches}); err != nil || system != want {
+		t.Errorf("Expected %+v, got %+v (err: %v)", want, system, err)
+	}
+
+	_, err = LoadUnits(writeConfig(t, `{"units": {"pressure": "bar"}}`))
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "units" {
+		t.Errorf("Expected a validation error for units, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// csvTable is a section of the analyses written as a flat CSV table, a row per trend, anomaly
+// or variable's statistics with the location and time of its analysis first
+type csvTable struct {
+	name   string   // File name prefix, e.g. "trends"
+	header []string // Columns after "location" and "generated_at"; new columns are only ever appended
+	rows   func(result models.AnalysisResult, system units.System) [][]string
+}
+
+// csvTables are the sections SaveCSV writes, each to its own file
+var csvTables = []csvTable{
+	{
+		name:   "trends",
+		header: []string{"variable", "trend", "rate_of_change", "unit", "ci_lower", "ci_upper", "p_value", "r_squared", "confidence", "duration"},
+		rows: func(result models.AnalysisResult, system units.System) [][]string {
+			var rows [][]string
+			for _, t := range result.Trends {
+				rows = append(rows, []string{
+					t.Variable, t.Trend, formatFloat(t.ChangeRate), unitOf(system, t.Variable, "/h"), formatFloat(t.CiLower),
+					formatFloat(t.CiUpper), formatFloat(t.PValue), formatFloat(t.RSquared), formatFloat(t.Confidence), t.Duration,
+				})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "anomalies",
+		header: []string{"timestamp", "variable", "type", "severity", "value", "threshold", "unit"},
+		rows: func(result models.AnalysisResult, system units.System) [][]string {
+			var rows [][]string
+			for _, a := range result.Anomalies {
+				rows = append(rows, []string{
+					formatTime(a.Timestamp), a.Variable, a.Type, a.Severity, formatFloat(a.Value), formatFloat(a.Threshold), unitOf(system, a.Variable, ""),
+				})
+			}
+			return rows
+		},
+	},
+	{
+		name: "statistics",
+		header: []string{"variable", "unit", "sample_size", "mean", "median", "min", "max", "std_dev", "ci_lower", "ci_upper", "confidence_level",
+			"p10", "p25", "p75", "p90", "iqr", "skewness", "kurtosis", "trend_strength", "circular_variance"},
+		rows: func(result models.AnalysisResult, system units.System) [][]string {
+			var rows [][]string
+			for _, s := range result.StatisticalData {
+				rows = append(rows, []string{
+					s.Variable, unitOf(system, s.Variable, ""), strconv.Itoa(s.SampleSize), formatFloat(s.Mean), formatFloat(s.Median), formatFloat(s.Min), formatFloat(s.Max),
+					formatFloat(s.StdDev), formatFloat(s.CiLower), formatFloat(s.CiUpper), formatFloat(s.ConfidenceLevel),
+					formatFloat(s.P10), formatFloat(s.P25), formatFloat(s.P75), formatFloat(s.P90), formatFloat(s.IQR),
+					formatFloat(s.Skewness), formatFloat(s.Kurtosis), formatFloat(s.TrendStrength), formatFloat(s.CircularVariance),
+				})
+			}
+			return rows
+		},
+	},
+}
+
+// SaveCSV writes the trends, anomalies and statistics of analyses as flat CSV tables for
+// spreadsheets and R: one timestamped file per section in the output directory (gzipped as
+// .csv.gz with Options.Compress), with a row per trend, anomaly or variable of every analysis. It
+// returns the paths of the files written.
+func (e *Engine) SaveCSV(results []models.AnalysisResult) ([]string, error) {
+	var paths []string
+	for _, table := range csvTables {
+		data, err := encodeCSV(table, results)
+		if err != nil {
+			return paths, err
+		}
+		path, err := e.write(table.name, ".csv", data, e.opts.Compress)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// encodeCSV renders a table of the analyses, each in the units it was written in
+func encodeCSV(table csvTable, results []models.AnalysisResult) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(append([]string{"location", "generated_at"}, table.header...)); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		system := units.Metric()
+		if result.Units != nil {
+			system = *result.Units
+		}
+		for _, row := range table.rows(result, system) {
+			if err := writer.Write(append([]string{result.Location, formatTime(result.GeneratedAt)}, row...)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// unitOf returns the unit of a variable in system without the leading space, followed by suffix
+// ("" for variables without a unit)
+func unitOf(system units.System, variable, suffix string) string {
+	unit := strings.TrimSpace(system.Symbol(variable))
+	if variable == "wind_direction" {
+		unit = "°"
+	}
+	if unit == "" {
+		return ""
+	}
+	return unit + suffix
+}
+
+// formatFloat formats a number with the shortest exact representation
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatTime formats a time as RFC 3339, or "" for the zero time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// TestSaveCSV tests that the trends, anomalies and statistics of several analyses are written as
+// one table each, with the units every analysis was written in
+func TestSaveCSV(t *testing.T) {
+	generated := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	imperial := units.Imperial()
+	results := []models.AnalysisResult{
+		{
+			Location:    "Oslo",
+			GeneratedAt: generated,
+			Trends:      []models.Trend{{Variable: "pressure", Trend: "falling", ChangeRate: -1.5, Duration: "6h"}},
+			Anomalies: []models.Anomaly{{Variable: "temperature", Type: "spike", Severity: "high", Value: 25.5, Threshold: 20,
+				Timestamp: generated.Add(-time.Hour)}},
+			StatisticalData: []models.StatisticalData{{Variable: "wind_direction", Mean: 180, SampleSize: 24}},
+		},
+		{
+			Location:        "Denver, CO",
+			GeneratedAt:     generated,
+			Units:           &imperial,
+			Trends:          []models.Trend{{Variable: "temperature", Trend: "rising", ChangeRate: 0.9, Duration: "12h"}},
+			StatisticalData: []models.StatisticalData{{Variable: "temperature", Mean: 50, SampleSize: 24}},
+		},
+	}
+	dir := t.TempDir()
+	paths, err := newTestEngine(t, Options{OutputDir: dir}).SaveCSV(results)
+	if err != nil {
+		t.Fatalf("SaveCSV failed: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("Expected 3 tables, got %v", paths)
+	}
+
+	tables := make(map[string][][]string)
+	for _, path := range paths {
+		if filepath.Dir(path) != dir || !strings.HasSuffix(path, ".csv") {
+			t.Errorf("Unexpected path %s", path)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(file).ReadAll()
+		file.Close()
+		if err != nil {
+			t.Fatalf("Invalid CSV in %s: %v", path, err)
+		}
+		tables[strings.SplitN(filepath.Base(path), "_", 2)[0]] = records
+	}
+
+	trends := tables["trends"]
+	if len(trends) != 3 || strings.Join(trends[0][:4], ",") != "location,generated_at,variable,trend" {
+		t.Fatalf("Unexpected trends table: %v", trends)
+	}
+	if got := strings.Join(trends[1][:6], ","); got != "Oslo,2025-10-03T12:00:00Z,pressure,falling,-1.5,hPa/h" {
+		t.Errorf("Unexpected trend row: %s", got)
+	}
+	if got := strings.Join(trends[2][:6], ","); got != "Denver, CO,2025-10-03T12:00:00Z,temperature,rising,0.9,°F/h" {
+		t.Errorf("Unexpected trend row: %s", got)
+	}
+
+	anomalies := tables["anomalies"]
+	if len(anomalies) != 2 || strings.Join(anomalies[1][2:], ",") != "2025-10-03T11:00:00Z,temperature,spike,high,25.5,20,°C" {
+		t.Errorf("Unexpected anomalies table: %v", anomalies)
+	}
+
+	statistics := tables["statistics"]
+	if len(statistics) != 3 || statistics[1][3] != "°" || statistics[2][3] != "°F" || statistics[2][5] != "50" {
+		t.Errorf("Unexpected statistics table: %v", statistics)
+	}
+}
@@ -0,0 +1,679 @@
+// Package engine analyzes per-location weather data for trends, anomalies, patterns and
+// statistics. It is the library behind the analyze command (see pattern-engine/cli) and can be
+// used by any Go program holding models.LocationData:
+//
+//	e := engine.New(engine.Options{OutputDir: "data/intelligence/analysis"})
+//	result, err := e.Analyze(&locationData)
+//	path, err := e.Save(result)
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"pattern-engine/analysis"
+	"pattern-engine/chart"
+	"pattern-engine/derive"
+	"pattern-engine/forecasting"
+	"pattern-engine/models"
+	"pattern-engine/narrative"
+	"pattern-engine/report"
+	"pattern-engine/rules"
+	"weathermodels/fileio"
+	"weathermodels/units"
+)
+
+// ErrInsufficientData is returned by Analyze for locations with fewer than two readings
+var ErrInsufficientData = errors.New("insufficient data for analysis")
+
+// Options configures an Engine
+type Options struct {
+	OutputDir    string                           // Directory Save writes analysis files to
+	Compress     bool                             // Gzip analysis files (written as .json.gz)
+	MemoryBudget int64                            // Bytes of readings AnalyzeFile holds per file (0 = load whole files)
+	Registry     *analysis.Registry               // Analyzers available (nil = NewRegistry(DefaultConfig()))
+	Analyzers    []string                         // Names of the analyzers to run (empty = every registered analyzer)
+	Profiles     []Profile                        // Thresholds for matching locations, as LoadConfig resolves them; the first match applies
+	Regional     *analysis.RegionalAnalyzer       // Analyzer of AnalyzeRegion (nil = analysis.NewRegionalAnalyzer())
+	Rules        []rules.Rule                     // Alert rules evaluated against each location's readings
+	Skills       map[string]*models.ForecastSkill // Skill of each location's verified forecasts, by location name
+	Resample     analysis.ResampleSettings        // Grid of the trend, anomaly, pattern, correlation and spectral analyses (zero = none)
+	Units        units.System                     // Units of the analyses and their narratives; they are analyzed in metric units (zero = metric)
+}
+
+// Engine runs the analyses of the pattern engine; it is safe to reuse across locations
+type Engine struct {
+	opts      Options
+	analyzers []analysis.Analyzer
+	profiles  [][]analysis.Analyzer // Analyzers with the thresholds of each profile, indexed like opts.Profiles
+	rules     []rules.Rule          // opts.Rules, compiled
+}
+
+// New creates an engine with the given options; naming an unregistered analyzer or a rule with
+// an invalid condition is an error
+func New(opts Options) (*Engine, error) {
+	registry := opts.Registry
+	if registry == nil {
+		registry = NewRegistry(DefaultConfig())
+	}
+	analyzers, err := registry.Select(opts.Analyzers)
+	if err != nil {
+		return nil, err
+	}
+	e := &Engine{opts: opts, analyzers: analyzers, rules: slices.Clone(opts.Rules)}
+	for i := range e.rules {
+		if err := e.rules[i].Compile(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", e.rules[i].Name, err)
+		}
+	}
+	for _, profile := range opts.Profiles {
+		tuned := make([]analysis.Analyzer, len(analyzers))
+		for i, a := range analyzers {
+			tuned[i] = a
+			if t, ok := a.(analysis.Tunable); ok {
+				tuned[i] = t.WithThresholds(profile.Thresholds)
+			}
+		}
+		e.profiles = append(e.profiles, tuned)
+	}
+	return e, nil
+}
+
+// NewRegistry returns a registry with the built-in analyzers using the thresholds of cfg,
+// followed by the forecaster with its forecast settings
+func NewRegistry(cfg Config) *analysis.Registry {
+	registry := analysis.NewRegistryWithThresholds(cfg.Thresholds)
+	if err := registry.Register(forecasting.New(cfg.Forecast)); err != nil {
+		panic(err) // The forecaster's name cannot clash with a built-in analyzer
+	}
+	return registry
+}
+
+// analyzersFor returns the analyzers for a location, tuned by the first profile that matches it
+func (e *Engine) analyzersFor(locationData *models.LocationData, logger *slog.Logger) []analysis.Analyzer {
+	for i := range e.opts.Profiles {
+		if e.opts.Profiles[i].Matches(locationData) {
+			logger.Info("Using threshold profile", "profile", e.opts.Profiles[i].Name)
+			return e.profiles[i]
+		}
+	}
+	return e.analyzers
+}
+
+// deseasonalize returns locationData with the daily cycles found by the seasonality detector
+// among analyzers removed (see analysis.SeasonalityDetector.Adjust), and the cycles. Without the
+// detector, locationData is returned unchanged.
+func deseasonalize(analyzers []analysis.Analyzer, locationData *models.LocationData) (*models.LocationData, []models.Seasonality) {
+	for _, analyzer := range analyzers {
+		if detector, ok := analyzer.(*analysis.SeasonalityDetector); ok {
+			return detector.Adjust(locationData)
+		}
+	}
+	return locationData, nil
+}
+
+// observations returns locationData with only its observed readings: forecast and nowcast points
+// are not the weather that was, and would pass for measurements in an analysis of the history.
+// Without any forecast or nowcast points, locationData is returned unchanged.
+func observations(locationData *models.LocationData) *models.LocationData {
+	if !slices.ContainsFunc(locationData.Readings, isPredicted) {
+		return locationData
+	}
+	observed := *locationData
+	observed.Readings = slices.DeleteFunc(slices.Clone(locationData.Readings), isPredicted)
+	slog.Debug("Left out forecast readings", "location", locationData.Name,
+		"count", len(locationData.Readings)-len(observed.Readings))
+	return &observed
+}
+
+// isPredicted reports whether a reading is a forecast or nowcast rather than an observation
+func isPredicted(r models.WeatherPoint) bool {
+	return !r.Observed()
+}
+
+// lookahead reports whether an analyzer looks at the weather to come, and so is given the forecast
+// and nowcast points along with the observed readings. The others, including analyzers registered
+// by other programs, analyze the history and are given only the observed readings.
+func lookahead(analyzer analysis.Analyzer) bool {
+	switch analyzer.(type) {
+	case *analysis.StormDetector, *analysis.RoadIcingDetector, *analysis.PrecipitationAccumulator,
+		*analysis.ComfortAssessor, *analysis.FogDetector, *analysis.EnergyEstimator, *forecasting.Forecaster:
+		return true
+	}
+	return false
+}
+
+// Analyze runs the selected analyzers on the location data. Analyzers of the history, such as
+// statistics, trends, anomalies and data quality, see only the observed readings, and are skipped
+// with fewer than two; those of the weather to come, such as storm risk and expected
+// precipitation, also see forecast and nowcast points (see lookahead). Locations with fewer than
+// two readings are not analyzed and return ErrInsufficientData.
+func (e *Engine) Analyze(locationData *models.LocationData) (models.AnalysisResult, error) {
+	if len(locationData.Readings) < 2 {
+		return models.AnalysisResult{}, ErrInsufficientData
+	}
+	return e.analyze(locationData, nil)
+}
+
+// analyze analyzes the readings of locationData. For a streamed file, totals has the statistics
+// of every reading, which replace the statistics, summary extremes and timeframe of the readings
+// held in memory.
+func (e *Engine) analyze(locationData *models.LocationData, totals *streamTotals) (models.AnalysisResult, error) {
+	logger := slog.With("location", locationData.Name)
+	result := models.AnalysisResult{
+		SchemaVersion: models.AnalysisSchemaVersion,
+		AnalysisType:  "comprehensive_weather_analysis",
+		Location:      locationData.Name,
+		Coordinates:   locationData.Coordinates,
+		Timezone:      locationData.Timezone,
+		GeneratedAt:   time.Now(),
+	}
+
+	derive.Apply(locationData.Readings)
+	history := observations(locationData)
+	var wind *models.WindClassification // Kept for the summary
+	var comfort *models.ComfortAssessment
+	analyzers := e.analyzersFor(locationData, logger)
+	// Regressions and spectra weigh every reading alike, so a burst of readings would outweigh the
+	// hours around it; they are found on a uniform grid
+	gridded := history
+	if readings, resampling := analysis.Resample(history.Readings, e.opts.Resample); resampling != nil {
+		resampled := *history
+		resampled.Readings = readings
+		gridded = &resampled
+		result.Resampling = resampling
+		logger.Info("Resampled readings",
+			"interval_minutes", resampling.IntervalMinutes,
+			"original", resampling.Original,
+			"readings", resampling.Readings,
+			"interpolated", resampling.Interpolated,
+			"missing", resampling.Missing)
+	}
+	adjusted, cycles := deseasonalize(analyzers, gridded)
+	if len(history.Readings) < 2 {
+		logger.Info("Too few observed readings to analyze the history", "observed", len(history.Readings))
+	}
+
+	for _, analyzer := range analyzers {
+		var output any
+		input := history
+		if lookahead(analyzer) {
+			input = locationData
+		} else if len(history.Readings) < 2 {
+			continue
+		}
+		switch a := analyzer.(type) {
+		case *analysis.StatisticalAnalyzer:
+			if totals != nil {
+				output = a.AccumulatedStatistics(&totals.stats, history.Readings)
+			}
+		case *analysis.SeasonalityDetector:
+			output = cycles
+		case *analysis.TrendAnalyzer, *analysis.AnomalyDetector, *analysis.PatternRecognizer, *analysis.MultivariateDetector,
+			*analysis.LagCorrelator:
+			// Found in the readings without their daily cycle, so an afternoon is not a warming trend
+			input = adjusted
+		case *analysis.SpectralAnalyzer:
+			input = gridded
+		}
+		if output == nil {
+			var err error
+			if output, err = analyzer.Analyze(input); err != nil {
+				return result, fmt.Errorf("%s analyzer: %w", analyzer.Name(), err)
+			}
+		}
+
+		switch output := output.(type) {
+		case []models.Trend:
+			result.Trends = output
+			for _, trend := range output {
+				logger.Info("Trend",
+					"variable", trend.Variable,
+					"trend", trend.Trend,
+					"change_rate", trend.ChangeRate,
+					"p_value", trend.PValue,
+					"confidence", trend.Confidence)
+			}
+		case []models.Anomaly:
+			result.Anomalies = output
+			for _, anomaly := range output {
+				logger.Info("Anomaly",
+					"variable", anomaly.Variable,
+					"type", anomaly.Type,
+					"value", anomaly.Value,
+					"severity", anomaly.Severity)
+			}
+		case []models.MultivariateAnomaly:
+			result.MultivariateAnomalies = output
+			for _, anomaly := range output {
+				logger.Info("Multivariate anomaly",
+					"timestamp", anomaly.Timestamp,
+					"distance", anomaly.Distance,
+					"severity", anomaly.Severity,
+					"main_variable", anomaly.Contributions[0].Variable)
+			}
+		case *models.StormRisk:
+			result.StormRisk = output
+			if output != nil {
+				logger.Info("Storm risk",
+					"score", output.Score,
+					"level", output.Level,
+					"peak_time", output.PeakTime,
+					"lead_time_hours", output.LeadTimeHours)
+			}
+		case *models.RoadIcingRisk:
+			result.RoadIcing = output
+			if output != nil {
+				logger.Info("Road icing risk",
+					"score", output.Score,
+					"level", output.Level,
+					"peak_time", output.PeakTime,
+					"lead_time_hours", output.LeadTimeHours)
+			}
+		case *models.AgroIndices:
+			result.AgroIndices = output
+			if output != nil {
+				logger.Info("Agricultural indices",
+					"growing_degree_days", output.GrowingDegreeDays,
+					"chill_hours", output.ChillHours,
+					"frost_days", output.FrostDays)
+			}
+		case []models.EnergyEstimate:
+			result.Energy = output
+			var solar, wind float64
+			for _, estimate := range output {
+				solar, wind = solar+estimate.SolarOutput, wind+estimate.WindOutput
+			}
+			if len(output) > 0 {
+				logger.Info("Energy potential",
+					"hours", len(output),
+					"mean_solar_output", solar/float64(len(output)),
+					"mean_wind_output", wind/float64(len(output)))
+			}
+		case *models.PrecipitationAnalysis:
+			result.Precipitation = output
+			if output != nil {
+				logger.Info("Precipitation",
+					"total", output.Total,
+					"expected_total", output.ExpectedTotal,
+					"peak_rate", output.PeakRate,
+					"peak_intensity", output.PeakIntensity)
+			}
+		case *models.ComfortAssessment:
+			comfort = output
+			if output != nil {
+				logger.Info("Comfort",
+					"feels_like", output.FeelsLike,
+					"level", output.Level,
+					"trend", output.Trend,
+					"periods", len(output.Periods))
+			}
+		case *models.DataQuality:
+			result.DataQuality = output
+			if output != nil {
+				logger.Info("Data quality",
+					"cadence_minutes", output.CadenceMinutes,
+					"coverage", output.Coverage,
+					"gaps", len(output.Gaps),
+					"duplicates", len(output.Duplicates),
+					"stale", output.Stale)
+				for _, warning := range output.Warnings {
+					logger.Warn("Data quality problem", "problem", warning)
+				}
+			}
+		case *models.WindClassification:
+			wind = output
+			if output != nil {
+				logger.Info("Wind",
+					"beaufort", output.Beaufort,
+					"max_beaufort", output.MaxBeaufort,
+					"gust_factor", output.GustFactor,
+					"high_wind", output.HighWind)
+			}
+		case []models.ExtremeEvent:
+			result.ExtremeEvents = output
+			for _, event := range output {
+				logger.Info("Extreme event",
+					"type", event.Type,
+					"start", event.Start,
+					"duration_days", event.DurationDays,
+					"peak_temperature", event.PeakTemperature)
+			}
+		case []models.Pattern:
+			for i, pattern := range output {
+				if input == adjusted && adjusted != gridded {
+					output[i].Readings = measured(gridded.Readings, pattern.Readings)
+				}
+				logger.Info("Pattern",
+					"name", pattern.Name,
+					"description", pattern.Description,
+					"confidence", pattern.Confidence,
+					"strength", pattern.Strength)
+			}
+			result.Patterns = append(result.Patterns, output...) // The recognizer's and the fog detector's
+		case []models.Seasonality:
+			result.Seasonality = output
+			for _, cycle := range output {
+				logger.Info("Daily cycle",
+					"variable", cycle.Variable,
+					"amplitude", cycle.Amplitude,
+					"peak_hour", cycle.PeakHour,
+					"strength", cycle.Strength)
+			}
+		case []models.Periodicity:
+			result.Periodicities = output
+			for _, periodicity := range output {
+				logger.Info("Periodicity",
+					"variable", periodicity.Variable,
+					"period_hours", periodicity.PeriodHours,
+					"label", periodicity.Label,
+					"power", periodicity.Power,
+					"confidence", periodicity.Confidence)
+			}
+		case []models.LagCorrelation:
+			result.LagCorrelations = output
+			for _, correlation := range output {
+				logger.Debug("Lag correlation",
+					"leader", correlation.Leader,
+					"follower", correlation.Follower,
+					"lag_hours", correlation.LagHours,
+					"correlation", correlation.Correlation)
+			}
+		case []models.ForecastPoint:
+			result.Forecast = output
+			for _, point := range output {
+				logger.Debug("Forecast",
+					"variable", point.Variable,
+					"timestamp", point.Timestamp,
+					"value", point.Value,
+					"lower", point.Lower,
+					"upper", point.Upper)
+			}
+		case []models.StatisticalData:
+			result.StatisticalData = output
+			for _, stat := range output {
+				logger.Info("Statistics",
+					"variable", stat.Variable,
+					"mean", stat.Mean,
+					"std_dev", stat.StdDev,
+					"min", stat.Min,
+					"max", stat.Max,
+					"samples", stat.SampleSize)
+			}
+		default:
+			if result.Extensions == nil {
+				result.Extensions = make(map[string]any)
+			}
+			result.Extensions[analyzer.Name()] = output
+			logger.Info("Analyzer result", "analyzer", analyzer.Name())
+		}
+	}
+
+	// Generate summary statistics: the current conditions and extremes are those observed, unless
+	// there are only forecasts
+	summarized := history
+	if len(summarized.Readings) == 0 {
+		summarized = locationData
+	}
+	summary := generateWeatherSummary(summarized)
+	timeframe := calculateDuration(locationData.Readings)
+	if totals != nil {
+		totals.apply(&summary)
+		timeframe = formatDuration(totals.latest.Sub(totals.earliest))
+	}
+	if skill := e.opts.Skills[locationData.Name]; skill != nil {
+		// The skill of the forecasts takes over the confidence from the number of readings as
+		// more of them are verified
+		result.ForecastSkill = skill
+		summary.Confidence = skill.Weight*skill.Score + (1-skill.Weight)*summary.Confidence
+		logger.Info("Forecast skill",
+			"score", skill.Score,
+			"weight", skill.Weight,
+			"forecasts", skill.Forecasts,
+			"confidence", summary.Confidence)
+	}
+	result.RuleAlerts = rules.Evaluate(e.rules, locationData.Readings)
+	for _, alert := range result.RuleAlerts {
+		logger.Info("Rule alert",
+			"rule", alert.Rule,
+			"severity", alert.Severity,
+			"start", alert.Start,
+			"readings", len(alert.Readings))
+	}
+	summary.Wind, summary.Comfort = wind, comfort
+	summarize(&summary, &result)
+	logger.Info("Summary",
+		"min_temperature", summary.MinTemperature,
+		"max_temperature", summary.MaxTemperature,
+		"min_pressure", summary.MinPressure,
+		"max_pressure", summary.MaxPressure,
+		"duration", timeframe)
+
+	result.Timeframe = timeframe
+	result.WeatherSummary = summary
+	result.Readings = locationData.Readings
+	result.Narrative = narrative.Describe(&result, e.opts.Units)
+	convertUnits(&result, e.opts.Units)
+	return result, nil
+}
+
+// Save writes an analysis to a timestamped JSON file in the output directory (gzipped as
+// .json.gz with Options.Compress) and returns its path
+func (e *Engine) Save(result models.AnalysisResult) (string, error) {
+	return e.save(fileName(result.Location)+"_analysis", result)
+}
+
+// SaveHTML writes the report of an analysis (see report.HTML) to a timestamped HTML file in the
+// output directory, next to its JSON file, and returns its path. charts are the paths of chart
+// files in the output directory to link, as SaveCharts returns them. Reports are never gzipped,
+// so they open in a browser.
+func (e *Engine) SaveHTML(result models.AnalysisResult, charts []string) (string, error) {
+	names := make([]string, len(charts))
+	for i, path := range charts {
+		names[i] = filepath.Base(path)
+	}
+	data, err := report.HTML(result, names)
+	if err != nil {
+		return "", fmt.Errorf("rendering report: %w", err)
+	}
+	return e.write(fileName(result.Location)+"_report", ".html", data, false)
+}
+
+// SaveCharts writes the charts of an analysis (see chart.FromAnalysis) in format, chart.FormatSVG
+// or chart.FormatPNG, to a timestamped file each in the output directory, uncompressed like
+// SaveHTML, and returns their paths
+func (e *Engine) SaveCharts(result models.AnalysisResult, format string) ([]string, error) {
+	var paths []string
+	for _, c := range chart.FromAnalysis(result) {
+		data, err := c.Encode(format)
+		if err != nil {
+			return paths, fmt.Errorf("rendering %s chart: %w", c.Variable, err)
+		}
+		path, err := e.write(fileName(result.Location)+"_"+c.Variable+"_chart", "."+format, data, false)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// SaveMarkdown writes the Markdown report of an analysis (see report.Markdown), compact or full,
+// to a timestamped .md file in the output directory, uncompressed like SaveHTML, and returns its
+// path
+func (e *Engine) SaveMarkdown(result models.AnalysisResult, compact bool) (string, error) {
+	return e.write(fileName(result.Location)+"_report", ".md", report.Markdown(result, compact), false)
+}
+
+// fileName returns a location's name as used in file names
+func fileName(location string) string {
+	safeLocation := strings.ReplaceAll(location, " ", "_")
+	safeLocation = strings.ReplaceAll(safeLocation, ",", "")
+	return strings.ReplaceAll(safeLocation, "/", "_")
+}
+
+// AnalyzeRegion analyzes locations together (see analysis.RegionalAnalyzer.AnalyzeRegion), each
+// by its observed readings with their daily cycles removed as for Analyze, so that the sun rising
+// everywhere at once does not relate them. Without two locations whose readings overlap long enough it returns
+// ErrInsufficientData.
+func (e *Engine) AnalyzeRegion(locations []models.LocationData) (models.RegionalAnalysis, error) {
+	regional := e.opts.Regional
+	if regional == nil {
+		regional = analysis.NewRegionalAnalyzer()
+	}
+
+	adjusted := make([]models.LocationData, len(locations))
+	for i := range locations {
+		logger := slog.With("location", locations[i].Name)
+		observed := observations(&locations[i])
+		location, _ := deseasonalize(e.analyzersFor(observed, logger), observed)
+		adjusted[i] = *location
+	}
+
+	region := regional.AnalyzeRegion(adjusted)
+	if len(region.Locations) < 2 {
+		return region, ErrInsufficientData
+	}
+	for _, c := range region.Correlations {
+		slog.Debug("Regional correlation", "variable", c.Variable, "leader", c.Leader, "follower", c.Follower,
+			"lag_hours", c.LagHours, "correlation", c.Correlation)
+	}
+	return region, nil
+}
+
+// SaveRegion writes a regional analysis to a timestamped JSON file in the output directory, like
+// Save, and returns its path
+func (e *Engine) SaveRegion(region models.RegionalAnalysis) (string, error) {
+	return e.save("region_analysis", region)
+}
+
+// save writes v to a JSON file named after name and the current time in the output directory
+func (e *Engine) save(name string, v any) (string, error) {
+	// Convert to JSON with indentation
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling analysis to JSON: %w", err)
+	}
+	return e.write(name, ".json", jsonData, e.opts.Compress)
+}
+
+// write writes data to a file named after name and the current time with extension ext in the
+// output directory, gzipped if compress is set, and returns its path
+func (e *Engine) write(name, ext string, data []byte, compress bool) (string, error) {
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(e.opts.OutputDir, 0755); err != nil {
+		return "", fmt.Errorf("creating analysis directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s/%s_%s%s", e.opts.OutputDir, name, time.Now().Format(analysisTimeLayout), ext)
+
+	if compress {
+		var err error
+		if data, err = fileio.Compress(data); err != nil {
+			return "", fmt.Errorf("compressing analysis: %w", err)
+		}
+		filename += fileio.GzipExt
+	}
+
+	// Write to a temp file and rename so readers never see a partial analysis
+	if err := fileio.WriteFileAtomic(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("writing analysis to %s: %w", filename, err)
+	}
+
+	return filename, nil
+}
+
+// measured returns the readings as measured at the times of adjusted, a run of readings with
+// their daily cycles removed; readings is in chronological order
+func measured(readings, adjusted []models.WeatherPoint) []models.WeatherPoint {
+	if len(adjusted) == 0 {
+		return adjusted
+	}
+	start := sort.Search(len(readings), func(i int) bool {
+		return !readings[i].Timestamp.Before(adjusted[0].Timestamp)
+	})
+	return readings[start:min(start+len(adjusted), len(readings))]
+}
+
+// generateWeatherSummary creates a weather summary from the readings
+func generateWeatherSummary(locationData *models.LocationData) models.WeatherSummary {
+	if len(locationData.Readings) == 0 {
+		return models.WeatherSummary{}
+	}
+
+	var summary models.WeatherSummary
+
+	// Initialize with first reading values
+	summary.CurrentTemp = locationData.Readings[len(locationData.Readings)-1].Temperature
+	summary.MinTemperature = locationData.Readings[0].Temperature
+	summary.MaxTemperature = locationData.Readings[0].Temperature
+	summary.CurrentPressure = locationData.Readings[len(locationData.Readings)-1].Pressure
+	current := locationData.Readings[len(locationData.Readings)-1].Derived
+	summary.CurrentDewPoint = current.DewPoint
+	summary.CurrentHeatIndex = current.HeatIndex
+	summary.CurrentWindChill = current.WindChill
+	summary.CurrentApparentTemperature = current.ApparentTemperature
+	summary.MinPressure = locationData.Readings[0].Pressure
+	summary.MaxPressure = locationData.Readings[0].Pressure
+
+	// Find min/max values across all readings
+	for _, reading := range locationData.Readings {
+		if reading.Temperature < summary.MinTemperature {
+			summary.MinTemperature = reading.Temperature
+		}
+		if reading.Temperature > summary.MaxTemperature {
+			summary.MaxTemperature = reading.Temperature
+		}
+		if reading.Pressure < summary.MinPressure {
+			summary.MinPressure = reading.Pressure
+		}
+		if reading.Pressure > summary.MaxPressure {
+			summary.MaxPressure = reading.Pressure
+		}
+	}
+
+	summary.PressureTendency = analysis.PressureTendency(locationData.Readings)
+	summary.Alerts = locationData.Alerts
+
+	summary.Confidence = summaryConfidence(len(locationData.Readings))
+	return summary
+}
+
+// summaryConfidence calculates an overall confidence based on data availability
+func summaryConfidence(readings int) float64 {
+	if readings >= 10 {
+		return 0.9
+	} else if readings >= 5 {
+		return 0.7
+	}
+	return 0.5
+}
+
+// calculateDuration calculates the time span of the readings
+func calculateDuration(readings []models.WeatherPoint) string {
+	if len(readings) < 2 {
+		return "0h"
+	}
+
+	return formatDuration(readings[len(readings)-1].Timestamp.Sub(readings[0].Timestamp))
+}
+
+// formatDuration formats a time span as whole days, or whole hours under a day
+func formatDuration(duration time.Duration) string {
+	hours := int(duration.Hours())
+
+	if hours >= 24 {
+		days := hours / 24
+		return fmt.Sprintf("%dd", days)
+	}
+
+	return fmt.Sprintf("%dh", hours)
+}
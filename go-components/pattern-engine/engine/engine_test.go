@@ -0,0 +1,782 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/analysis"
+	"pattern-engine/models"
+	"pattern-engine/rules"
+	"weathermodels/fileio"
+	"weathermodels/units"
+)
+
+// testLocation returns a location with hourly readings of falling pressure
+func testLocation(readings int) models.LocationData {
+	location := models.LocationData{Name: "Bergen, Norway", Alerts: []string{"yellow_wind_warning"}}
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	for i := range readings {
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: 10 + float64(i)*0.5,
+			Pressure:    1015 - float64(i)*2,
+			Humidity:    70,
+		})
+	}
+	return location
+}
+
+// newTestEngine creates an engine, failing the test on invalid options
+func newTestEngine(t *testing.T, opts Options) *Engine {
+	t.Helper()
+	e, err := New(opts)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return e
+}
+
+// TestAnalyze tests that an analysis covers the readings without writing anything
+func TestAnalyze(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "analysis")
+	e := newTestEngine(t, Options{OutputDir: dir})
+	location := testLocation(6)
+
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Location != "Bergen, Norway" || result.Timeframe != "5h" || result.SchemaVersion != models.AnalysisSchemaVersion {
+		t.Errorf("Unexpected result header: %+v", result)
+	}
+	if result.WeatherSummary.MinPressure != 1005 || result.WeatherSummary.CurrentTemp != 12.5 || len(result.WeatherSummary.Alerts) != 1 {
+		t.Errorf("Unexpected summary: %+v", result.WeatherSummary)
+	}
+	if summary := result.WeatherSummary; summary.CurrentDewPoint != location.Readings[5].Derived.DewPoint ||
+		math.Abs(summary.CurrentDewPoint-7.2) > 0.05 || summary.CurrentApparentTemperature == 0 {
+		t.Errorf("Expected the derived variables of the current reading, got %+v", summary)
+	}
+	if tendency := result.WeatherSummary.PressureTendency; tendency == nil || tendency.Change != -6 || tendency.Characteristic != 7 {
+		t.Errorf("Expected pressure falling steadily by 6 hPa, got %+v", tendency)
+	}
+	if len(result.Forecast) == 0 || !result.Forecast[0].Timestamp.After(location.Readings[5].Timestamp) {
+		t.Errorf("Expected a forecast past the last reading, got %+v", result.Forecast)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected Analyze not to create %s", dir)
+	}
+}
+
+// countAnalyzer is a third-party analyzer counting readings
+type countAnalyzer struct{}
+
+func (countAnalyzer) Name() string { return "count" }
+
+func (countAnalyzer) Analyze(locationData *models.LocationData) (any, error) {
+	return len(locationData.Readings), nil
+}
+
+// TestAnalyzeSelectedAnalyzers tests that only the selected analyzers run and that results of
+// registered third-party analyzers are kept under their name
+func TestAnalyzeSelectedAnalyzers(t *testing.T) {
+	registry := analysis.NewRegistry()
+	if err := registry.Register(countAnalyzer{}); err != nil {
+		t.Fatal(err)
+	}
+	e := newTestEngine(t, Options{Registry: registry, Analyzers: []string{"statistics", "count"}})
+	location := testLocation(6)
+
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Trends != nil || result.Anomalies != nil || result.Patterns != nil || len(result.StatisticalData) == 0 {
+		t.Errorf("Expected only statistics, got %+v", result)
+	}
+	if result.Extensions["count"] != 6 {
+		t.Errorf("Expected the count extension, got %v", result.Extensions)
+	}
+
+	if _, err := New(Options{Analyzers: []string{"count"}}); err == nil {
+		t.Error("Expected an error for an analyzer missing from the default registry")
+	}
+}
+
+// TestAnalyzeRules tests that the alerts of matching rules are in the analysis and its summary
+func TestAnalyzeRules(t *testing.T) {
+	e := newTestEngine(t, Options{Rules: []rules.Rule{
+		{Name: "warm_and_humid", Condition: "temperature >= 12 AND humidity > 60", Severity: rules.SeverityLow},
+		{Name: "hard_frost", Condition: "temperature < -10"},
+	}})
+	location := testLocation(6)
+
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.RuleAlerts) != 1 || result.RuleAlerts[0].Rule != "warm_and_humid" || len(result.RuleAlerts[0].Readings) != 2 {
+		t.Fatalf("Expected only the warm_and_humid alert, got %+v", result.RuleAlerts)
+	}
+	if !slices.Contains(result.WeatherSummary.Alerts, "warm_and_humid") {
+		t.Errorf("Expected the rule in the summary alerts, got %v", result.WeatherSummary.Alerts)
+	}
+
+	if _, err := New(Options{Rules: []rules.Rule{{Name: "broken", Condition: "temperature <"}}}); err == nil {
+		t.Error("Expected an error for a rule with an invalid condition")
+	}
+}
+
+// TestAnalyzeForecastSkill tests that a verified location's forecast skill is added to its
+// analysis and weights the summary's confidence
+func TestAnalyzeForecastSkill(t *testing.T) {
+	skill := &models.ForecastSkill{Score: 0.5, Weight: 0.75, Statements: []string{"temperature forecasts have 2.0°C MAE at 24h lead"}}
+	location := testLocation(6)
+	e := newTestEngine(t, Options{Skills: map[string]*models.ForecastSkill{location.Name: skill}})
+
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.ForecastSkill != skill {
+		t.Errorf("Expected the location's forecast skill, got %+v", result.ForecastSkill)
+	}
+	// 6 readings have a confidence of 0.7 from their number
+	if want := 0.75*0.5 + 0.25*0.7; math.Abs(result.WeatherSummary.Confidence-want) > 1e-9 {
+		t.Errorf("Expected a confidence of %v, got %v", want, result.WeatherSummary.Confidence)
+	}
+
+	other := testLocation(6)
+	other.Name = "Elsewhere"
+	if result, _ := e.Analyze(&other); result.ForecastSkill != nil || result.WeatherSummary.Confidence != 0.7 {
+		t.Errorf("Expected no skill for an unverified location, got %+v (confidence %v)", result.ForecastSkill, result.WeatherSummary.Confidence)
+	}
+}
+
+// TestAnalyzeObservations tests that forecast and nowcast readings are left out of the analyses
+// of the history
+func TestAnalyzeObservations(t *testing.T) {
+	location := testLocation(6)
+	last := location.Readings[5].Timestamp
+	for i, source := range []string{models.SourceForecast, models.SourceNowcast} {
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp: last.Add(time.Duration(i+1) * time.Hour), Temperature: 40, Pressure: 1015, Humidity: 70, Source: source,
+		})
+	}
+	e := newTestEngine(t, Options{})
+
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.WeatherSummary.MaxTemperature != 12.5 || result.DataQuality == nil || result.DataQuality.Readings != 6 {
+		t.Errorf("Expected the six observed readings only, got a maximum of %v°C and %+v", result.WeatherSummary.MaxTemperature, result.DataQuality)
+	}
+	if len(location.Readings) != 8 {
+		t.Errorf("Expected the location's readings kept, got %d", len(location.Readings))
+	}
+
+	location.Readings = location.Readings[5:]
+	result, err = e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze of one observed reading and forecasts failed: %v", err)
+	}
+	if len(result.Trends) != 0 || len(result.StatisticalData) != 0 || result.DataQuality != nil {
+		t.Errorf("Expected no analysis of a history of one reading, got %+v", result)
+	}
+	if result.WeatherSummary.CurrentTemp != 12.5 || result.Timeframe != "2h" {
+		t.Errorf("Expected the observed conditions over the readings' 2h, got %v°C over %s", result.WeatherSummary.CurrentTemp, result.Timeframe)
+	}
+}
+
+// TestAnalyzeForecast tests that the current conditions and a forecast, as collected in one run,
+// are analyzed for the weather to come
+func TestAnalyzeForecast(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	location := models.LocationData{Name: "Bergen, Norway", Readings: []models.WeatherPoint{
+		{Timestamp: now, Temperature: 10, Pressure: 1010, Humidity: 80, Source: models.SourceObservation},
+	}}
+	for i := 1; i < 24; i++ {
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp: now.Add(time.Duration(i) * time.Hour), Temperature: 10, Pressure: 1010, Humidity: 90,
+			PrecipitationMm: 5, Source: models.SourceForecast,
+		})
+	}
+
+	result, err := newTestEngine(t, Options{}).Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if p := result.Precipitation; p == nil || p.ExpectedTotal != 115 || p.HorizonHours != 24 {
+		t.Errorf("Expected 115 mm over the next 24h, got %+v", p)
+	}
+	if !slices.Contains(result.WeatherSummary.Alerts, "precipitation_expected") {
+		t.Errorf("Expected precipitation_expected, got %v", result.WeatherSummary.Alerts)
+	}
+}
+
+// TestAnalyzeResampled tests that irregular readings are resampled onto the configured grid
+func TestAnalyzeResampled(t *testing.T) {
+	location := testLocation(12)
+	location.Readings[8].Timestamp = location.Readings[8].Timestamp.Add(20 * time.Minute)
+	location.Readings = slices.Delete(location.Readings, 5, 7) // 5:00 and 6:00
+	e := newTestEngine(t, Options{Resample: analysis.ResampleSettings{Interval: time.Hour, MaxGap: 3 * time.Hour}})
+
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	want := models.Resampling{IntervalMinutes: 60, Original: 10, Readings: 12, Interpolated: 3}
+	if result.Resampling == nil || *result.Resampling != want {
+		t.Errorf("Expected %+v, got %+v", want, result.Resampling)
+	}
+	if len(location.Readings) != 10 {
+		t.Errorf("Expected the location's readings kept, got %d", len(location.Readings))
+	}
+
+	if result, _ := newTestEngine(t, Options{}).Analyze(&location); result.Resampling != nil {
+		t.Errorf("Expected no resampling without an interval, got %+v", result.Resampling)
+	}
+}
+
+// TestAnalyzeUnits tests that an analysis in imperial units has its values, forecast skill and
+// narrative converted from the metric analysis, without changing the shared skill
+func TestAnalyzeUnits(t *testing.T) {
+	accuracy := models.ForecastAccuracy{
+		Variable:  "temperature",
+		Accuracy:  models.Accuracy{Count: 10, MAE: 2},
+		LeadTimes: []models.LeadTimeAccuracy{{LeadHours: 24, Accuracy: models.Accuracy{Count: 10, MAE: 2}}},
+	}
+	skill := &models.ForecastSkill{Score: 0.5, Weight: 0.75, Statements: []string{"temperature forecasts have 2.0°C MAE at 24h lead"},
+		Variables: []models.ForecastAccuracy{accuracy}}
+	location := testLocation(12)
+	skills := map[string]*models.ForecastSkill{location.Name: skill}
+
+	metric, err := newTestEngine(t, Options{Skills: skills}).Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	imperial, err := newTestEngine(t, Options{Skills: skills, Units: units.Imperial()}).Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if metric.Units != nil || imperial.Units == nil || *imperial.Units != units.Imperial() {
+		t.Errorf("Expected units only on the imperial analysis, got %v and %v", metric.Units, imperial.Units)
+	}
+	if want := metric.WeatherSummary.MaxTemperature*9/5 + 32; math.Abs(imperial.WeatherSummary.MaxTemperature-want) > 1e-9 {
+		t.Errorf("Expected a high of %.1f°F, got %.1f", want, imperial.WeatherSummary.MaxTemperature)
+	}
+	if want := metric.WeatherSummary.MinPressure / 33.8639; math.Abs(imperial.WeatherSummary.MinPressure-want) > 1e-3 {
+		t.Errorf("Expected a low of %.2f inHg, got %.2f", want, imperial.WeatherSummary.MinPressure)
+	}
+	for i, trend := range imperial.Trends {
+		if trend.Variable == "temperature" && math.Abs(trend.ChangeRate-metric.Trends[i].ChangeRate*1.8) > 1e-9 {
+			t.Errorf("Expected a temperature rate of %v°F/h, got %v", metric.Trends[i].ChangeRate*1.8, trend.ChangeRate)
+		}
+	}
+	if !strings.Contains(imperial.Narrative, "°F") || !strings.Contains(imperial.Narrative, "inHg") {
+		t.Errorf("Expected a narrative in imperial units, got %q", imperial.Narrative)
+	}
+
+	if got := imperial.ForecastSkill.Statements; len(got) != 1 || got[0] != "temperature forecasts have 3.6°F MAE at 24h lead" {
+		t.Errorf("Unexpected skill statements: %v", got)
+	}
+	if imperial.ForecastSkill.Variables[0].MAE != 3.6 || skill.Variables[0].MAE != 2 || metric.ForecastSkill != skill {
+		t.Errorf("Expected the skill converted in a copy, got %+v (shared %+v)", imperial.ForecastSkill.Variables[0], skill.Variables[0])
+	}
+}
+
+// TestAnalyzeProfiles tests that the first matching profile's thresholds are used for a location
+func TestAnalyzeProfiles(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, `{"analysis": {"profiles": [
+		{"name": "tropical", "max_latitude": 23.5, "anomalies": {"pressure_change": 10, "high_pressure_change": 10}},
+		{"name": "coastal", "locations": ["bergen, norway"], "anomalies": {"pressure_change": 1.5}},
+		{"name": "polar", "min_latitude": 60, "anomalies": {"pressure_change": 10, "high_pressure_change": 10}}
+	]}}`))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	e := newTestEngine(t, Options{Analyzers: []string{analysis.AnomaliesAnalyzer}, Profiles: cfg.Profiles})
+
+	pressureDrops := func(location models.LocationData) int {
+		result, err := e.Analyze(&location)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		drops := 0
+		for _, anomaly := range result.Anomalies {
+			if anomaly.Type == "pressure_drop" {
+				drops++
+			}
+		}
+		return drops
+	}
+
+	// Pressure falls 2 hPa an hour: below the default 3 hPa threshold, above the coastal profile's
+	bergen := testLocation(6)
+	bergen.Coordinates.Latitude = 60.39
+	if drops := pressureDrops(bergen); drops != 5 {
+		t.Errorf("Expected 5 pressure drops with the coastal profile, got %d", drops)
+	}
+	oslo := testLocation(6)
+	oslo.Name, oslo.Coordinates.Latitude = "Oslo, Norway", 59.91
+	if drops := pressureDrops(oslo); drops != 0 {
+		t.Errorf("Expected no pressure drops without a matching profile, got %d", drops)
+	}
+}
+
+// TestAnalyzeDeseasonalized tests that an afternoon warm-up is not reported as a rising trend
+// once the daily cycle is removed
+func TestAnalyzeDeseasonalized(t *testing.T) {
+	// A day of a 10°C daily swing from 21:00: the cool night comes early and the warm afternoon
+	// late, so a straight line through the readings rises
+	location := models.LocationData{Name: "Bergen, Norway"}
+	start := time.Date(2025, 7, 1, 21, 0, 0, 0, time.UTC)
+	for i := range 25 {
+		ts := start.Add(time.Duration(i) * time.Hour)
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp:   ts,
+			Temperature: 15 + 5*math.Cos(2*math.Pi*float64(ts.Hour()-15)/24),
+			Pressure:    1013,
+			Humidity:    70,
+		})
+	}
+
+	temperatureTrend := func(e *Engine) (string, []models.Seasonality) {
+		data := location
+		data.Readings = append([]models.WeatherPoint(nil), location.Readings...)
+		result, err := e.Analyze(&data)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, trend := range result.Trends {
+			if trend.Variable == "temperature" {
+				return trend.Trend, result.Seasonality
+			}
+		}
+		return "", result.Seasonality
+	}
+
+	trend, cycles := temperatureTrend(newTestEngine(t, Options{}))
+	if trend != "stable" || len(cycles) != 1 || cycles[0].Variable != "temperature" {
+		t.Errorf("Expected a stable temperature with its daily cycle, got %q and %+v", trend, cycles)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Thresholds.Seasonality.Deseasonalize = false
+	if trend, _ := temperatureTrend(newTestEngine(t, Options{Registry: NewRegistry(cfg)})); trend != "rising" {
+		t.Errorf("Expected the warm-up to be a rising trend without deseasonalizing, got %q", trend)
+	}
+}
+
+// TestAnalyzePatternReadings tests that the readings of patterns found in deseasonalized readings
+// are the measured ones
+func TestAnalyzePatternReadings(t *testing.T) {
+	location := models.LocationData{Name: "Bergen, Norway"}
+	measuredTemps := map[time.Time]float64{}
+	start := time.Date(2025, 7, 1, 21, 0, 0, 0, time.UTC)
+	for i := range 25 {
+		ts := start.Add(time.Duration(i) * time.Hour)
+		temperature := 15 + 5*math.Cos(2*math.Pi*float64(ts.Hour()-15)/24)
+		measuredTemps[ts] = temperature
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp:   ts,
+			Temperature: temperature,
+			Pressure:    1030, // A high pressure system
+			Humidity:    70,
+		})
+	}
+
+	result, err := newTestEngine(t, Options{}).Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Seasonality) == 0 {
+		t.Fatal("Expected the daily cycle to be removed before pattern recognition")
+	}
+	found := false
+	for _, pattern := range result.Patterns {
+		if pattern.Name != "high_pressure_system" {
+			continue
+		}
+		found = true
+		if len(pattern.Readings) == 0 {
+			t.Fatal("Expected the readings of the pattern")
+		}
+		for _, reading := range pattern.Readings {
+			if want := measuredTemps[reading.Timestamp]; reading.Temperature != want {
+				t.Fatalf("Expected the measured %.2f°C at %s, got %.2f°C", want, reading.Timestamp, reading.Temperature)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a high pressure system, got %+v", result.Patterns)
+	}
+}
+
+// TestAnalyzeStormSummary tests that a coming storm and its wind are surfaced in the weather
+// summary without changing the location's own alerts
+func TestAnalyzeStormSummary(t *testing.T) {
+	location := models.LocationData{Name: "Stavanger", Alerts: []string{"yellow_wind_warning"}}
+	now := time.Now().Truncate(time.Hour)
+	for i := range 12 {
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp:                now.Add(time.Duration(i) * time.Hour),
+			Temperature:              9,
+			Pressure:                 1005 - 2.5*float64(i),
+			WindSpeed:                8 + 2*float64(i),
+			PrecipitationProbability: 95,
+			SymbolCode:               "heavyrainandthunder",
+		})
+	}
+
+	result, err := newTestEngine(t, Options{}).Analyze(&location)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.StormRisk == nil || result.StormRisk.Level != "high" {
+		t.Fatalf("Expected a high storm risk, got %+v", result.StormRisk)
+	}
+	summary := result.WeatherSummary
+	if summary.ForecastSummary != "storm_approaching" || !slices.Equal(summary.Alerts, []string{"yellow_wind_warning", "storm_risk", "high_wind"}) {
+		t.Errorf("Expected a storm in the summary, got %q with alerts %v", summary.ForecastSummary, summary.Alerts)
+	}
+	if wind := summary.Wind; wind == nil || wind.Beaufort != 11 || wind.Description != "violent storm" || !wind.HighWind {
+		t.Errorf("Expected a violent storm on the Beaufort scale, got %+v", wind)
+	}
+	if len(location.Alerts) != 1 {
+		t.Errorf("Expected the location's alerts unchanged, got %v", location.Alerts)
+	}
+}
+
+// TestAnalyzeInsufficientData tests that a single reading is not analyzed
+func TestAnalyzeInsufficientData(t *testing.T) {
+	location := testLocation(1)
+	if _, err := newTestEngine(t, Options{}).Analyze(&location); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("Expected ErrInsufficientData, got %v", err)
+	}
+}
+
+// TestSaveCompressed tests that a compressed analysis is written as .json.gz and reads back
+func TestSaveCompressed(t *testing.T) {
+	dir := t.TempDir()
+	e := newTestEngine(t, Options{OutputDir: dir, Compress: true})
+	location := testLocation(3)
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := e.Save(result)
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "Bergen_Norway_analysis_") || !strings.HasSuffix(path, ".json.gz") {
+		t.Errorf("Unexpected path %s", path)
+	}
+	data, err := fileio.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved models.AnalysisResult
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Location != result.Location {
+		t.Errorf("Expected the analysis back, got %+v (err: %v)", saved, err)
+	}
+}
+
+// TestSaveHTML tests that the report of an analysis is written next to it, uncompressed even when
+// analyses are, with sparklines of the readings analyzed
+func TestSaveHTML(t *testing.T) {
+	dir := t.TempDir()
+	e := newTestEngine(t, Options{OutputDir: dir, Compress: true})
+	location := testLocation(6)
+	result, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Readings) != 6 {
+		t.Fatalf("Expected the 6 readings analyzed with the analysis, got %d", len(result.Readings))
+	}
+
+	path, err := e.SaveHTML(result, nil)
+	if err != nil {
+		t.Fatalf("SaveHTML failed: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "Bergen_Norway_report_") || !strings.HasSuffix(path, ".html") {
+		t.Errorf("Unexpected path %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") || strings.Count(string(data), "<polyline") != 2 {
+		t.Errorf("Expected a page with 2 sparklines, got %s", data)
+	}
+}
+
+// TestAnalyzeRegion tests that a pressure trough reaching a second city later is found despite
+// both cities' daily temperature cycles, and that the regional analysis is saved
+func TestAnalyzeRegion(t *testing.T) {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	var locations []models.LocationData
+	for _, city := range []struct {
+		name    string
+		lon     float64
+		passage float64
+	}{{"Bergen", 5.32, 36}, {"Oslo", 10.75, 41}} {
+		location := models.LocationData{Name: city.name, Coordinates: models.Coordinates{Latitude: 60, Longitude: city.lon}}
+		for i := range 96 {
+			h := float64(i)
+			d := (h - city.passage) / 8
+			location.Readings = append(location.Readings, models.WeatherPoint{
+				Timestamp:   start.Add(time.Duration(i) * time.Hour),
+				Temperature: 10 + 5*math.Sin(2*math.Pi*(h-9)/24),
+				Pressure:    1012 - 15*math.Exp(-d*d),
+				Humidity:    70,
+			})
+		}
+		locations = append(locations, location)
+	}
+
+	dir := t.TempDir()
+	e := newTestEngine(t, Options{OutputDir: dir})
+	region, err := e.AnalyzeRegion(locations)
+	if err != nil {
+		t.Fatalf("AnalyzeRegion failed: %v", err)
+	}
+	if len(region.Correlations) != 1 {
+		t.Fatalf("Expected only the pressure trough to relate the cities, got %+v", region.Correlations)
+	}
+	if c := region.Correlations[0]; c.Variable != "pressure" || c.Leader != "Bergen" || c.Follower != "Oslo" || c.LagHours != 5 {
+		t.Errorf("Expected the trough to reach Oslo 5 hours after Bergen, got %+v", c)
+	}
+
+	path, err := e.SaveRegion(region)
+	if err != nil || !strings.HasPrefix(filepath.Base(path), "region_analysis_") {
+		t.Errorf("Expected a region analysis file, got %s (err: %v)", path, err)
+	}
+
+	if _, err := e.AnalyzeRegion(locations[:1]); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("Expected ErrInsufficientData for one location, got %v", err)
+	}
+}
+
+// TestLoadAnalyses tests that the newest analysis of each location is loaded, with its
+// coordinates, and regional analyses are skipped
+func TestLoadAnalyses(t *testing.T) {
+	dir := t.TempDir()
+	e := newTestEngine(t, Options{OutputDir: dir, Compress: true})
+	location := testLocation(4)
+	location.Coordinates = models.Coordinates{Latitude: 60.39, Longitude: 5.32}
+	older, err := e.Analyze(&location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer := older
+	newer.GeneratedAt = older.GeneratedAt.Add(time.Hour)
+	newer.Timeframe = "newer"
+	for _, result := range []models.AnalysisResult{older, newer} {
+		data, _ := json.Marshal(result)
+		name := fmt.Sprintf("Bergen_analysis_%d.json", result.GeneratedAt.Unix())
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.SaveRegion(models.RegionalAnalysis{Locations: []string{"Bergen", "Oslo"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	analyses, err := LoadAnalyses(dir)
+	if err != nil {
+		t.Fatalf("LoadAnalyses failed: %v", err)
+	}
+	if len(analyses) != 1 || analyses[0].Timeframe != "newer" || analyses[0].Coordinates != location.Coordinates {
+		t.Errorf("Expected the newer Bergen analysis, got %+v", analyses)
+	}
+}
+
+// TestLoadLatestAnalysis tests that the newest analysis file of a location is picked by name,
+// ignoring case, without reading the files of other locations
+func TestLoadLatestAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	files := map[string]models.AnalysisResult{
+		"New_York_analysis_20251003_120000.json":    {Location: "New York", GeneratedAt: start, Timeframe: "older"},
+		"New_York_analysis_20251003_130000.json.gz": {Location: "New York", GeneratedAt: start.Add(time.Hour), Timeframe: "newer"},
+		"New_York_report_20251003_140000.md":        {},
+		"region_analysis_20251003_150000.json":      {},
+	}
+	for name, result := range files {
+		data, _ := json.Marshal(result)
+		if strings.HasSuffix(name, fileio.GzipExt) {
+			data, _ = fileio.Compress(data)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another location's file would fail the read if it were opened
+	if err := os.WriteFile(filepath.Join(dir, "Oslo_analysis_20251003_160000.json.gz"), []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := LoadLatestAnalysis(dir, "new york")
+	if err != nil {
+		t.Fatalf("LoadLatestAnalysis failed: %v", err)
+	}
+	if latest.Timeframe != "newer" {
+		t.Errorf("Expected the newer analysis, got %+v", latest)
+	}
+	if _, err := LoadLatestAnalysis(dir, "Bergen"); !errors.Is(err, ErrNoAnalysis) {
+		t.Errorf("Expected ErrNoAnalysis for Bergen, got %v", err)
+	}
+	if _, err := LoadLatestAnalysis(filepath.Join(dir, "missing"), "New York"); !errors.Is(err, ErrNoAnalysis) {
+		t.Errorf("Expected ErrNoAnalysis without an analysis directory, got %v", err)
+	}
+}
+
+// TestLoadLocationData tests that readings, collector alerts and marine points are parsed and
+// readings without a valid timestamp are dropped
+func TestLoadLocationData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Oslo.json")
+	data := `{
+		"schema_version": 1,
+		"location": "Oslo",
+		"coordinates": {"lat": 59.91, "lon": 10.75, "alt": 23},
+		"readings": [
+			{"timestamp": "2025-10-03T12:00:00Z", "temperature": 9.5, "pressure": 1012},
+			{"timestamp": "not a time", "temperature": 99},
+			{"timestamp": "2025-10-03T13:00:00Z", "temperature": 9.0, "pressure": 1011}
+		],
+		"alerts": [{"event": "wind", "awareness_level": "yellow"}, "flood_warning"],
+		"marine": [{"timestamp": "2025-10-03T12:00:00Z", "wave_height": 1.5}]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	location, err := LoadLocationData(path, false)
+	if err != nil {
+		t.Fatalf("LoadLocationData failed: %v", err)
+	}
+	if location.Name != "Oslo" || location.Coordinates.Altitude != 23 || location.Timezone != "Europe/Oslo" || len(location.Readings) != 2 {
+		t.Errorf("Unexpected location: %+v", location)
+	}
+	if len(location.Alerts) != 2 || location.Alerts[0] != "yellow_wind_warning" || location.Alerts[1] != "flood_warning" {
+		t.Errorf("Unexpected alerts: %v", location.Alerts)
+	}
+	if len(location.Marine) != 1 || location.Marine[0].WaveHeight != 1.5 {
+		t.Errorf("Unexpected marine points: %+v", location.Marine)
+	}
+}
+
+// TestLoadLocationDataMerged tests that overlapping runs leave one reading per time: the observed
+// one saved last, or the latest forecast without one; a forecast source outranks the saved time
+func TestLoadLocationDataMerged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Oslo.json")
+	data := `{"location": "Oslo", "readings": [
+		{"timestamp": "2025-10-03T13:00:00Z", "saved_at": "2025-10-03T12:00:00Z", "temperature": 6},
+		{"timestamp": "2025-10-03T12:00:00Z", "temperature": 1},
+		{"timestamp": "2025-10-03T14:00:00+02:00", "saved_at": "2025-10-03T12:30:00Z", "temperature": 3},
+		{"timestamp": "2025-10-03T12:00:00Z", "saved_at": "2025-10-03T12:05:00Z", "temperature": 2},
+		{"timestamp": "2025-10-03T12:00:00Z", "saved_at": "2025-10-03T11:00:00Z", "temperature": 4},
+		{"timestamp": "2025-10-03T13:00:00Z", "saved_at": "2025-10-03T11:00:00Z", "temperature": 5},
+		{"timestamp": "2025-10-03T11:00:00Z", "saved_at": "2025-10-03T11:00:00Z", "temperature": 0},
+		{"timestamp": "2025-10-03T11:00:00Z", "saved_at": "2025-10-03T12:00:00Z", "source": "forecast", "temperature": 9}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	location, err := LoadLocationData(path, false)
+	if err != nil {
+		t.Fatalf("LoadLocationData failed: %v", err)
+	}
+	want := []float64{0, 3, 6}
+	if len(location.Readings) != len(want) {
+		t.Fatalf("Expected %d readings, got %+v", len(want), location.Readings)
+	}
+	for i, r := range location.Readings {
+		if r.Temperature != want[i] || r.Timestamp.UTC().Hour() != 11+i {
+			t.Errorf("Reading %d: expected %v°C at %d:00, got %+v", i, want[i], 11+i, r)
+		}
+	}
+}
+
+// TestLoadLocationDataTimezone tests that a file's own time zone is kept over the one of its
+// coordinates, and that a file without coordinates has none
+func TestLoadLocationDataTimezone(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"location": "Oslo", "timezone": "UTC", "coordinates": {"lat": 59.91, "lon": 10.75}, "readings": []}`, "UTC"},
+		{`{"location": "Rio", "coordinates": {"lat": -22.91, "lon": -43.17}, "readings": []}`, "America/Sao_Paulo"},
+		{`{"location": "Nowhere", "readings": []}`, ""},
+	}
+	for _, tt := range tests {
+		location, _, err := DecodeLocationData([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("DecodeLocationData failed: %v", err)
+		}
+		if location.Timezone != tt.want {
+			t.Errorf("%s: expected time zone %q, got %q", location.Name, tt.want, location.Timezone)
+		}
+	}
+}
+
+// TestLoadLocationDataStrict tests that strict loading reports every bad entry with its position
+func TestLoadLocationDataStrict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Oslo.json")
+	data := `{
+		"location": "Oslo",
+		"readings": [
+			{"timestamp": "2025-10-03T12:00:00Z", "temperature": 9.5},
+			{"timestamp": "2025-10-03T13:00:00Z", "temperature": "warm"},
+			{"temperature": 8.0},
+			[1, 2]
+		],
+		"alerts": [42]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	location, err := LoadLocationData(path, false)
+	if err != nil || len(location.Readings) != 1 {
+		t.Fatalf("Expected 1 reading when lenient, got %d (err: %v)", len(location.Readings), err)
+	}
+
+	_, err = LoadLocationData(path, true)
+	if err == nil {
+		t.Fatal("Expected an error in strict mode")
+	}
+	var entryErr *EntryError
+	if !errors.As(err, &entryErr) || entryErr.Section != "readings" || entryErr.Index != 1 {
+		t.Errorf("Expected the first error at readings[1], got %v", err)
+	}
+	for _, want := range []string{"readings[2]: missing timestamp", "readings[3]", "alerts[0]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+}
+
+// TestLoadLocationDataNewerSchema tests that files from a newer writer are refused
+func TestLoadLocationDataNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Oslo.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 99, "location": "Oslo"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocationData(path, false); err == nil || !strings.Contains(err.Error(), "unsupported schema_version") {
+		t.Errorf("Expected an unsupported schema error, got %v", err)
+	}
+}
@@ -0,0 +1,342 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/fileio"
+)
+
+// EntryError reports an entry of a time-series file that could not be parsed
+type EntryError struct {
+	Section string // "readings", "alerts" or "marine"
+	Index   int    // Position of the entry in its section
+	Err     error
+}
+
+func (e *EntryError) Error() string {
+	return fmt.Sprintf("%s[%d]: %v", e.Section, e.Index, e.Err)
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+// pythonTimeLayout is datetime.isoformat() without a UTC offset, used by the Python app for saved_at
+const pythonTimeLayout = "2006-01-02T15:04:05.999999"
+
+// fileReading is a reading of a time-series file with the time it was saved by the collection
+// run that wrote it
+type fileReading struct {
+	models.WeatherPoint
+	SavedAt time.Time // Zero if the reading has no saved_at
+}
+
+// observed reports whether the reading is an observation: by its source where it has one, or else
+// by being saved at or after its time rather than forecast ahead of it
+func (r fileReading) observed() bool {
+	if r.Source != "" {
+		return r.Observed()
+	}
+	return r.SavedAt.IsZero() || !r.Timestamp.After(r.SavedAt)
+}
+
+// errMissingTimestamp is reported for readings and marine points without a timestamp
+var errMissingTimestamp = errors.New("missing timestamp")
+
+// fileCoordinates are the coordinates of a time-series file; Python may write the altitude as a float
+type fileCoordinates struct {
+	Lat *float64 `json:"lat"`
+	Lon *float64 `json:"lon"`
+	Alt float64  `json:"alt"`
+}
+
+// fileAlert is an alert object written by the collector
+type fileAlert struct {
+	Event          string `json:"event"`
+	AwarenessLevel string `json:"awareness_level"`
+}
+
+// LoadLocationData reads a per-location time-series file (gzipped when it ends in .json.gz).
+// Readings, alerts and marine points that cannot be parsed are dropped with a warning; with
+// strict set they fail the load instead, reported as one *EntryError each.
+func LoadLocationData(filePath string, strict bool) (models.LocationData, error) {
+	data, err := fileio.ReadFile(filePath)
+	if err != nil {
+		return models.LocationData{}, err
+	}
+
+	locationData, entryErrs, err := DecodeLocationData(data)
+	if err != nil {
+		return models.LocationData{}, err
+	}
+	if err := checkEntries(filePath, entryErrs, strict); err != nil {
+		return models.LocationData{}, err
+	}
+	return locationData, nil
+}
+
+// DecodeLocationData decodes a time-series file, its readings merged to one per timestamp (see
+// mergeReadings) and sorted oldest first. An error is returned for a file that is not a
+// time-series file; entries that cannot be parsed are left out and returned as entryErrs.
+func DecodeLocationData(data []byte) (locationData models.LocationData, entryErrs []error, err error) {
+	var readings []fileReading
+	locationData, entryErrs, err = streamLocationData(bytes.NewReader(data), maxChunkSize, func(chunk []fileReading) error {
+		readings = append(readings, chunk...)
+		return nil
+	})
+	locationData.Readings = mergeReadings(readings)
+	if merged := len(readings) - len(locationData.Readings); merged > 0 {
+		slog.Debug("Merged readings with the same timestamp", "location", locationData.Name, "merged", merged)
+	}
+	return locationData, entryErrs, err
+}
+
+// mergeReadings returns the readings oldest first with one reading per timestamp, where
+// overlapping collection runs wrote several. An observed reading is preferred to a forecast or
+// nowcast (see fileReading.observed), then the one saved last; readings without a saved time are
+// taken as saved before any other, and ties go to the later in the file.
+func mergeReadings(readings []fileReading) []models.WeatherPoint {
+	slices.SortStableFunc(readings, func(a, b fileReading) int { return a.Timestamp.Compare(b.Timestamp) })
+	merged := make([]models.WeatherPoint, 0, len(readings))
+	for i := 0; i < len(readings); {
+		best := i
+		j := i + 1
+		for ; j < len(readings) && readings[j].Timestamp.Equal(readings[i].Timestamp); j++ {
+			if !preferred(readings[best], readings[j]) {
+				best = j
+			}
+		}
+		merged = append(merged, readings[best].WeatherPoint)
+		i = j
+	}
+	return merged
+}
+
+// preferred reports whether reading a is kept over reading b of the same time
+func preferred(a, b fileReading) bool {
+	if a.observed() != b.observed() {
+		return a.observed()
+	}
+	return a.SavedAt.After(b.SavedAt)
+}
+
+// checkEntries fails a load with the entries of filePath that could not be parsed in strict
+// mode, or logs that they were dropped
+func checkEntries(filePath string, entryErrs []error, strict bool) error {
+	if len(entryErrs) == 0 {
+		return nil
+	}
+	if strict {
+		return errors.Join(entryErrs...)
+	}
+	slog.Warn("Dropped entries that could not be parsed",
+		"file", filePath, "count", len(entryErrs), "first_error", entryErrs[0])
+	return nil
+}
+
+// decodeReading decodes one reading, which must have a timestamp, with the time it was saved
+func decodeReading(raw json.RawMessage) (fileReading, error) {
+	var reading fileReading
+	if err := decodeEntry(raw, &reading.WeatherPoint); err != nil {
+		return reading, err
+	}
+	if reading.Timestamp.IsZero() {
+		return reading, errMissingTimestamp
+	}
+	var saved struct {
+		SavedAt string `json:"saved_at"`
+	}
+	if json.Unmarshal(raw, &saved) == nil {
+		reading.SavedAt = parseSavedAt(saved.SavedAt)
+	}
+	return reading, nil
+}
+
+// parseSavedAt returns the time a reading was saved, or the zero time if it has none that
+// parses. Naive times written by Python's datetime.isoformat() are local time.
+func parseSavedAt(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation(pythonTimeLayout, s, time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// parseAlerts converts raw alerts into summary labels, adding an *EntryError to entryErrs for
+// each that cannot be parsed
+func parseAlerts(raws []json.RawMessage, entryErrs []error) ([]string, []error) {
+	var alerts []string
+	for i, raw := range raws {
+		alert, err := parseAlert(raw)
+		if err != nil {
+			entryErrs = append(entryErrs, &EntryError{"alerts", i, err})
+		} else if alert != "" {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, entryErrs
+}
+
+// parseMarine decodes raw ocean forecast points, adding an *EntryError to entryErrs for each
+// that cannot be parsed or has no timestamp
+func parseMarine(raws []json.RawMessage, entryErrs []error) ([]models.MarinePoint, []error) {
+	var points []models.MarinePoint
+	for i, raw := range raws {
+		var point models.MarinePoint
+		err := decodeEntry(raw, &point)
+		if err == nil && point.Timestamp.IsZero() {
+			err = errMissingTimestamp
+		}
+		if err != nil {
+			entryErrs = append(entryErrs, &EntryError{"marine", i, err})
+			continue
+		}
+		points = append(points, point)
+	}
+	return points, entryErrs
+}
+
+// decodeEntry decodes one reading or marine point, which must be a JSON object
+func decodeEntry(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || raw[0] != '{' {
+		return fmt.Errorf("expected an object, got %s", raw)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// checkSchemaVersion verifies a time-series file's schema_version is one the engine can read.
+// Files written before versioning have no schema_version and are read as version 1.
+func checkSchemaVersion(version *float64) error {
+	if version == nil {
+		return nil
+	}
+	if *version < 1 || *version != float64(int(*version)) {
+		return fmt.Errorf("invalid schema_version %v", *version)
+	}
+	if int(*version) > models.TimeseriesSchemaVersion {
+		return fmt.Errorf("unsupported schema_version %d (this engine reads up to %d)", int(*version), models.TimeseriesSchemaVersion)
+	}
+	return nil
+}
+
+// parseAlert converts a raw alert (a collector MetAlerts object or a plain label) into a summary
+// label such as "yellow_wind_warning"
+func parseAlert(raw json.RawMessage) (string, error) {
+	var label string
+	if err := json.Unmarshal(raw, &label); err == nil {
+		return label, nil
+	}
+	var alert fileAlert
+	if err := decodeEntry(raw, &alert); err != nil {
+		return "", err
+	}
+	return AlertLabel(alert.Event, alert.AwarenessLevel), nil
+}
+
+// AlertLabel returns the summary label of a warning, such as "yellow_wind_warning", or "" without
+// an event. The awareness level is left out when it is unknown.
+func AlertLabel(event, awarenessLevel string) string {
+	if event == "" {
+		return ""
+	}
+	if awarenessLevel != "" {
+		return fmt.Sprintf("%s_%s_warning", awarenessLevel, event)
+	}
+	return event + "_warning"
+}
+
+// ErrNoAnalysis is returned by LoadLatestAnalysis for locations without a saved analysis
+var ErrNoAnalysis = errors.New("no analysis saved")
+
+// analysisTimeLayout is the time in the names of the files the engine writes
+const analysisTimeLayout = "20060102_150405"
+
+// LoadLatestAnalysis reads the newest analysis of location, whose name is matched ignoring case,
+// from the analysis files in dir. The files are picked by the name Save gives them, made of the
+// location and the time, so only those of the location are read.
+func LoadLatestAnalysis(dir, location string) (models.AnalysisResult, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return models.AnalysisResult{}, ErrNoAnalysis
+	}
+	if err != nil {
+		return models.AnalysisResult{}, err
+	}
+
+	prefix := fileName(location) + "_analysis_"
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || len(name) < len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+			continue
+		}
+		stamp, ok := strings.CutSuffix(strings.TrimSuffix(name[len(prefix):], fileio.GzipExt), ".json")
+		if _, err := time.Parse(analysisTimeLayout, stamp); ok && err == nil {
+			names = append(names, name)
+		}
+	}
+	// The times sort as text; the newest comes first
+	slices.SortFunc(names, func(a, b string) int { return strings.Compare(b[len(prefix):], a[len(prefix):]) })
+
+	for _, name := range names {
+		data, err := fileio.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return models.AnalysisResult{}, err
+		}
+		var result models.AnalysisResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			slog.Warn("Skipping invalid analysis file", "path", filepath.Join(dir, name), "error", err)
+			continue
+		}
+		// Names that differ only by spaces or commas share file names
+		if strings.EqualFold(result.Location, location) {
+			return result, nil
+		}
+	}
+	return models.AnalysisResult{}, ErrNoAnalysis
+}
+
+// LoadAnalyses reads the newest analysis of each location from the analysis files (gzipped or
+// not) in dir, as written by Save. Other files, such as regional analyses, are skipped.
+func LoadAnalyses(dir string) ([]models.AnalysisResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var analyses []models.AnalysisResult
+	newest := map[string]int{} // Index in analyses of each location's analysis
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json"+fileio.GzipExt)) {
+			continue
+		}
+		data, err := fileio.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var result models.AnalysisResult
+		if err := json.Unmarshal(data, &result); err != nil || result.Location == "" {
+			continue // Not a location's analysis
+		}
+		if i, ok := newest[result.Location]; !ok {
+			newest[result.Location] = len(analyses)
+			analyses = append(analyses, result)
+		} else if result.GeneratedAt.After(analyses[i].GeneratedAt) {
+			analyses[i] = result
+		}
+	}
+	return analyses, nil
+}
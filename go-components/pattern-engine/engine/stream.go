@@ -0,0 +1,292 @@
+package engine
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"pattern-engine/analysis"
+	"pattern-engine/models"
+	"pattern-engine/timezone"
+	"weathermodels/fileio"
+)
+
+// readingSize approximates the memory of one decoded reading, including its symbol code, for
+// turning Options.MemoryBudget into a number of readings
+const readingSize = 256
+
+// maxChunkSize is the largest number of readings decoded between analyzer updates
+const maxChunkSize = 1000
+
+// streamTotals accumulates every reading of a streamed file, of which only the most recent are
+// held in memory for trend, anomaly and pattern analysis
+type streamTotals struct {
+	stats     analysis.StatisticsAccumulator // Of the observed readings
+	predicted int                            // Number of forecast and nowcast readings
+	earliest  time.Time
+	latest    time.Time
+}
+
+// add adds a chunk of readings, of which only the observed count towards the statistics
+func (t *streamTotals) add(readings []models.WeatherPoint) {
+	observed := readings
+	if slices.ContainsFunc(readings, isPredicted) {
+		observed = slices.DeleteFunc(slices.Clone(readings), isPredicted)
+		t.predicted += len(readings) - len(observed)
+	}
+	t.stats.Add(observed)
+	for _, r := range readings {
+		if t.earliest.IsZero() || r.Timestamp.Before(t.earliest) {
+			t.earliest = r.Timestamp
+		}
+		if r.Timestamp.After(t.latest) {
+			t.latest = r.Timestamp
+		}
+	}
+}
+
+// apply replaces the extremes and confidence of a summary of the recent readings with those of
+// every observed reading, if there were any
+func (t *streamTotals) apply(summary *models.WeatherSummary) {
+	if t.stats.Count() == 0 {
+		return
+	}
+	temperature, pressure := t.stats.Stats("temperature"), t.stats.Stats("pressure")
+	summary.MinTemperature, summary.MaxTemperature = temperature.Min(), temperature.Max()
+	summary.MinPressure, summary.MaxPressure = pressure.Min(), pressure.Max()
+	summary.Confidence = summaryConfidence(t.stats.Count())
+}
+
+// AnalyzeFile analyzes a time-series file (gzipped when it ends in .json.gz), with entries that
+// cannot be parsed handled as by LoadLocationData. Without Options.MemoryBudget the file is
+// loaded whole and analyzed like Analyze. With it, readings are streamed: statistics, summary
+// extremes cover every observed reading and the timeframe every reading, while the other analyses
+// come from the most recent readings that fit in the budget, split as for Analyze. Streamed readings are taken
+// as they are, without the merging of readings with the same timestamp done when a file is loaded.
+func (e *Engine) AnalyzeFile(filePath string, strict bool) (models.AnalysisResult, error) {
+	if e.opts.MemoryBudget <= 0 {
+		locationData, err := LoadLocationData(filePath, strict)
+		if err != nil {
+			return models.AnalysisResult{}, err
+		}
+		return e.Analyze(&locationData)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return models.AnalysisResult{}, err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if fileio.IsGzip(filePath) {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return models.AnalysisResult{}, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	// Keep the newest readings that fit in the budget, leaving room for the chunk being added
+	windowSize := max(int(e.opts.MemoryBudget/readingSize), 4)
+	chunkSize := min(windowSize/2, maxChunkSize)
+	windowSize -= chunkSize
+
+	var totals streamTotals
+	var window []models.WeatherPoint
+	locationData, entryErrs, err := StreamLocationData(r, chunkSize, func(chunk []models.WeatherPoint) error {
+		totals.add(chunk)
+		window = append(window, chunk...)
+		if len(window) > windowSize {
+			window = append(window[:0], window[len(window)-windowSize:]...)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.AnalysisResult{}, err
+	}
+	if err := checkEntries(filePath, entryErrs, strict); err != nil {
+		return models.AnalysisResult{}, err
+	}
+
+	if totals.predicted > 0 {
+		slog.Debug("Left forecast readings out of the statistics", "location", locationData.Name, "count", totals.predicted)
+	}
+
+	if len(window) < 2 {
+		return models.AnalysisResult{}, ErrInsufficientData
+	}
+	locationData.Readings = window
+	return e.analyze(&locationData, &totals)
+}
+
+// StreamLocationData decodes a time-series file from r without holding its readings: they are
+// passed to onChunk in file order, up to chunkSize at a time, in a slice that is reused after
+// onChunk returns. The returned location data has no readings, and the file's time zone or else
+// the one resolved from its coordinates; entries that cannot be parsed are left out and returned
+// as entryErrs.
+func StreamLocationData(r io.Reader, chunkSize int, onChunk func([]models.WeatherPoint) error) (locationData models.LocationData, entryErrs []error, err error) {
+	points := make([]models.WeatherPoint, 0, chunkSize)
+	return streamLocationData(r, chunkSize, func(chunk []fileReading) error {
+		points = points[:0]
+		for _, reading := range chunk {
+			points = append(points, reading.WeatherPoint)
+		}
+		return onChunk(points)
+	})
+}
+
+// streamLocationData is StreamLocationData with the time each reading was saved
+func streamLocationData(r io.Reader, chunkSize int, onChunk func([]fileReading) error) (locationData models.LocationData, entryErrs []error, err error) {
+	dec := json.NewDecoder(r)
+	chunk := make([]fileReading, 0, chunkSize)
+	located := false // The file has coordinates to resolve a time zone from
+
+	err = decodeObject(dec, func(key string) error {
+		switch key {
+		case "schema_version":
+			// Refuse layouts newer than this engine understands instead of misreading them
+			var version *float64
+			if err := dec.Decode(&version); err != nil {
+				return err
+			}
+			return checkSchemaVersion(version)
+		case "location":
+			return dec.Decode(&locationData.Name)
+		case "coordinates":
+			var c *fileCoordinates
+			if err := dec.Decode(&c); err != nil {
+				return err
+			}
+			if c != nil && c.Lat != nil && c.Lon != nil {
+				locationData.Coordinates = models.Coordinates{Latitude: *c.Lat, Longitude: *c.Lon, Altitude: int(c.Alt)}
+				located = true
+			}
+			return nil
+		case "timezone":
+			return dec.Decode(&locationData.Timezone)
+		case "readings":
+			return decodeArray(dec, func(i int) error {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return err
+				}
+				reading, err := decodeReading(raw)
+				if err != nil {
+					entryErrs = append(entryErrs, &EntryError{"readings", i, err})
+					return nil
+				}
+				if chunk = append(chunk, reading); len(chunk) < chunkSize {
+					return nil
+				}
+				err = onChunk(chunk)
+				chunk = chunk[:0]
+				return err
+			})
+		case "alerts":
+			var raws []json.RawMessage
+			if err := dec.Decode(&raws); err != nil {
+				return err
+			}
+			locationData.Alerts, entryErrs = parseAlerts(raws, entryErrs)
+			return nil
+		case "marine":
+			var raws []json.RawMessage
+			if err := dec.Decode(&raws); err != nil {
+				return err
+			}
+			locationData.Marine, entryErrs = parseMarine(raws, entryErrs)
+			return nil
+		}
+		return skipValue(dec)
+	})
+	if err == nil && len(chunk) > 0 {
+		err = onChunk(chunk)
+	}
+	if err != nil {
+		return models.LocationData{}, nil, fmt.Errorf("invalid time-series file: %w", err)
+	}
+	if locationData.Timezone == "" && located {
+		locationData.Timezone = timezone.Resolve(locationData.Coordinates.Latitude, locationData.Coordinates.Longitude)
+	}
+	return locationData, entryErrs, nil
+}
+
+// decodeObject reads a JSON object from dec, calling field for each key with the decoder
+// positioned at its value; field must consume the value
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", token)
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token() // closing }
+	return err
+}
+
+// decodeArray reads a JSON array (or null) from dec, calling element with the index of each
+// element with the decoder positioned at it; element must consume the value
+func decodeArray(dec *json.Decoder, element func(i int) error) error {
+	token, err := dec.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected an array, got %v", token)
+	}
+	for i := 0; dec.More(); i++ {
+		if err := element(i); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // closing ]
+	return err
+}
+
+// expectDelim reads the next token and checks it is the given delimiter
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q, got %v", want, token)
+	}
+	return nil
+}
+
+// skipValue consumes the next value, however deeply nested
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/fileio"
+)
+
+// writeLargeFile writes a gzipped time-series file with hourly readings, the alerts after them
+func writeLargeFile(t *testing.T, readings int) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString(`{"schema_version": 1, "location": "Tromsø", "coordinates": {"lat": 69.65, "lon": 18.96}, "readings": [`)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range readings {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		reading, _ := json.Marshal(map[string]any{
+			"timestamp":   start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"temperature": 5 + 10*math.Sin(float64(i)/24),
+			"pressure":    1010 + float64(i%50),
+			"saved_at":    "2025-01-01T00:00:00.000001",
+		})
+		b.Write(reading)
+	}
+	b.WriteString(`], "alerts": ["orange_snow_warning"], "metadata": {"total_readings": 1}}`)
+
+	data, err := fileio.Compress([]byte(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "Tromsø.json.gz")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestStreamLocationDataChunks tests that readings arrive in bounded chunks and fields after the
+// readings are still decoded
+func TestStreamLocationDataChunks(t *testing.T) {
+	path := writeLargeFile(t, 250)
+	data, err := fileio.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, chunks := 0, 0
+	locationData, entryErrs, err := StreamLocationData(strings.NewReader(string(data)), 100, func(chunk []models.WeatherPoint) error {
+		if len(chunk) > 100 {
+			t.Errorf("Chunk of %d readings exceeds the chunk size", len(chunk))
+		}
+		total += len(chunk)
+		chunks++
+		return nil
+	})
+	if err != nil || len(entryErrs) != 0 {
+		t.Fatalf("StreamLocationData failed: %v %v", err, entryErrs)
+	}
+	if total != 250 || chunks != 3 || len(locationData.Readings) != 0 {
+		t.Errorf("Expected 250 readings in 3 chunks and none kept, got %d in %d (kept %d)", total, chunks, len(locationData.Readings))
+	}
+	if locationData.Name != "Tromsø" || locationData.Coordinates.Latitude != 69.65 || len(locationData.Alerts) != 1 {
+		t.Errorf("Unexpected location data: %+v", locationData)
+	}
+}
+
+// TestAnalyzeFileMemoryBudget tests that a streamed analysis covers every reading in its
+// statistics, extremes and timeframe while holding only the budgeted readings
+func TestAnalyzeFileMemoryBudget(t *testing.T) {
+	path := writeLargeFile(t, 5000)
+
+	whole, err := newTestEngine(t, Options{}).AnalyzeFile(path, true)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	streamed, err := newTestEngine(t, Options{MemoryBudget: 200 * readingSize}).AnalyzeFile(path, true)
+	if err != nil {
+		t.Fatalf("Streamed AnalyzeFile failed: %v", err)
+	}
+
+	if streamed.Timeframe != whole.Timeframe || streamed.Location != "Tromsø" {
+		t.Errorf("Expected timeframe %s, got %s", whole.Timeframe, streamed.Timeframe)
+	}
+	if streamed.WeatherSummary.MinTemperature != whole.WeatherSummary.MinTemperature ||
+		streamed.WeatherSummary.MaxPressure != whole.WeatherSummary.MaxPressure ||
+		streamed.WeatherSummary.CurrentTemp != whole.WeatherSummary.CurrentTemp {
+		t.Errorf("Summary differs: streamed %+v, whole %+v", streamed.WeatherSummary, whole.WeatherSummary)
+	}
+	if len(streamed.StatisticalData) != len(whole.StatisticalData) {
+		t.Fatalf("Expected %d statistics, got %d", len(whole.StatisticalData), len(streamed.StatisticalData))
+	}
+	for i, stat := range streamed.StatisticalData {
+		if stat.SampleSize != 5000 || math.Abs(stat.Mean-whole.StatisticalData[i].Mean) > 1e-6 {
+			t.Errorf("Statistics for %s differ: streamed %+v, whole %+v", stat.Variable, stat, whole.StatisticalData[i])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+
+	"pattern-engine/models"
+)
+
+// expectedPrecipitationMm is the precipitation (mm) forecast from the current reading on from
+// which precipitation is expected
+const expectedPrecipitationMm = 1.0
+
+// trendWords are the words of the temperature, pressure and wind trends in
+// WeatherSummary.TrendNextHours, by variable and trend
+var trendWords = map[string]map[string]string{
+	"temperature": {"rising": "warming", "falling": "cooling"},
+	"pressure":    {"rising": "pressure_rising", "falling": "pressure_falling"},
+	"wind_speed":  {"increasing": "wind_increasing", "decreasing": "wind_easing"},
+}
+
+// summarize draws the conclusions of the analysis into the summary, next to the location's own
+// alerts:
+//   - TrendNextHours, the words of the temperature, pressure and wind trends and the comfort
+//     outlook when comfort is changing, or "steady";
+//   - ForecastSummary, "storm_approaching" at a moderate or high storm risk, else "deteriorating"
+//     for falling pressure or a low pressure system or front, "clearing" for rising pressure or a
+//     high pressure system, and "stable" otherwise;
+//   - Alerts, adding "storm_risk", "road_icing", "high_wind", "precipitation_expected", "fog_risk",
+//     "unusual_<variable>" for a high severity anomaly and the names of the rules raising alerts.
+func summarize(summary *models.WeatherSummary, result *models.AnalysisResult) {
+	var trends []string
+	for _, trend := range result.Trends {
+		if word := trendWords[trend.Variable][trend.Trend]; word != "" {
+			trends = append(trends, word)
+		}
+	}
+	if comfort := summary.Comfort; comfort != nil && comfort.Trend != "steady" {
+		trends = append(trends, comfort.Outlook)
+	}
+	summary.TrendNextHours = "steady"
+	if len(trends) > 0 {
+		summary.TrendNextHours = strings.Join(trends, ", ")
+	}
+
+	summary.ForecastSummary = forecastSummary(result)
+
+	alert := func(name string) {
+		if !slices.Contains(summary.Alerts, name) {
+			summary.Alerts = append(slices.Clip(summary.Alerts), name) // Leave the location's alerts be
+		}
+	}
+	if risk := result.StormRisk; risk != nil && risk.Level != "low" {
+		alert("storm_risk")
+	}
+	if risk := result.RoadIcing; risk != nil && risk.Level != "low" {
+		alert("road_icing")
+	}
+	if wind := summary.Wind; wind != nil && wind.HighWind {
+		alert("high_wind")
+	}
+	if p := result.Precipitation; p != nil && p.ExpectedTotal >= expectedPrecipitationMm {
+		alert("precipitation_expected")
+	}
+	for _, pattern := range result.Patterns {
+		if pattern.Name == "fog_risk" {
+			alert("fog_risk")
+		}
+	}
+	for _, anomaly := range result.Anomalies {
+		if anomaly.Severity == "high" {
+			alert("unusual_" + anomaly.Variable)
+		}
+	}
+	for _, ruleAlert := range result.RuleAlerts {
+		alert(ruleAlert.Rule)
+	}
+}
+
+// forecastSummary returns the one-word outlook of an analysis for WeatherSummary.ForecastSummary
+func forecastSummary(result *models.AnalysisResult) string {
+	if risk := result.StormRisk; risk != nil && risk.Level != "low" {
+		return "storm_approaching"
+	}
+	for _, trend := range result.Trends {
+		if trend.Variable == "pressure" && trend.Trend == "falling" {
+			return "deteriorating"
+		}
+		if trend.Variable == "pressure" && trend.Trend == "rising" {
+			return "clearing"
+		}
+	}
+	for _, pattern := range result.Patterns {
+		switch pattern.Name {
+		case "low_pressure_system", "front_passage":
+			return "deteriorating"
+		case "high_pressure_system":
+			return "clearing"
+		}
+	}
+	return "stable"
+}
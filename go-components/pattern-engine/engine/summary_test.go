@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"pattern-engine/models"
+)
+
+// TestSummarize tests the conclusions drawn from the results of the analyzers
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   models.AnalysisResult
+		comfort  *models.ComfortAssessment
+		trend    string
+		forecast string
+		alerts   []string
+	}{
+		{
+			name:     "Nothing to report",
+			trend:    "steady",
+			forecast: "stable",
+			alerts:   []string{"yellow_wind_warning"},
+		},
+		{
+			name: "Cooling under falling pressure with rain and comfort",
+			result: models.AnalysisResult{
+				Trends: []models.Trend{
+					{Variable: "temperature", Trend: "falling"},
+					{Variable: "pressure", Trend: "falling"},
+					{Variable: "humidity", Trend: "increasing"},
+				},
+				Precipitation: &models.PrecipitationAnalysis{ExpectedTotal: 4},
+			},
+			comfort:  &models.ComfortAssessment{Trend: "improving", Outlook: "becoming more comfortable this evening"},
+			trend:    "cooling, pressure_falling, becoming more comfortable this evening",
+			forecast: "deteriorating",
+			alerts:   []string{"yellow_wind_warning", "precipitation_expected"},
+		},
+		{
+			name: "Storm over a high pressure system",
+			result: models.AnalysisResult{
+				StormRisk: &models.StormRisk{Level: "moderate"},
+				RoadIcing: &models.RoadIcingRisk{Level: "low"},
+				Patterns:  []models.Pattern{{Name: "high_pressure_system"}, {Name: "fog_risk"}, {Name: "fog_risk"}},
+				Anomalies: []models.Anomaly{{Variable: "pressure", Severity: "high"}, {Variable: "humidity", Severity: "low"}},
+			},
+			comfort:  &models.ComfortAssessment{Trend: "steady", Outlook: "comfort steady"},
+			trend:    "steady",
+			forecast: "storm_approaching",
+			alerts:   []string{"yellow_wind_warning", "storm_risk", "fog_risk", "unusual_pressure"},
+		},
+		{
+			name: "Clearing with ice",
+			result: models.AnalysisResult{
+				Trends:    []models.Trend{{Variable: "pressure", Trend: "rising"}, {Variable: "wind_speed", Trend: "decreasing"}},
+				RoadIcing: &models.RoadIcingRisk{Level: "high"},
+			},
+			trend:    "pressure_rising, wind_easing",
+			forecast: "clearing",
+			alerts:   []string{"yellow_wind_warning", "road_icing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := models.WeatherSummary{Alerts: []string{"yellow_wind_warning"}, Comfort: tt.comfort}
+			summarize(&summary, &tt.result)
+			if summary.TrendNextHours != tt.trend || summary.ForecastSummary != tt.forecast || !slices.Equal(summary.Alerts, tt.alerts) {
+				t.Errorf("Expected %q, %q and alerts %v, got %q, %q and %v",
+					tt.trend, tt.forecast, tt.alerts, summary.TrendNextHours, summary.ForecastSummary, summary.Alerts)
+			}
+		})
+	}
+}
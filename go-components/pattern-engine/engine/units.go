@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"pattern-engine/analysis"
+	"pattern-engine/models"
+	"pattern-engine/verification"
+	"weathermodels/units"
+)
+
+// convertUnits converts the values of an analysis from the metric units it was made in to those
+// of system and records them in Units. Values of the same variable as the readings, such as a
+// mean or a forecast, convert like them; differences of values, such as a rate of change, a
+// spread or an error, are scaled without the offset of the temperature scales. The results of
+// extensions are left as their analyzers wrote them.
+func convertUnits(result *models.AnalysisResult, system units.System) {
+	if system.IsMetric() {
+		return
+	}
+	result.Units = &system
+
+	for i := range result.Trends {
+		t := &result.Trends[i]
+		t.ChangeRate = system.ConvertChange(t.Variable, t.ChangeRate)
+		t.CiLower = system.ConvertChange(t.Variable, t.CiLower)
+		t.CiUpper = system.ConvertChange(t.Variable, t.CiUpper)
+	}
+	for i := range result.Anomalies {
+		a := &result.Anomalies[i]
+		a.Value = system.Convert(a.Variable, a.Value)
+		a.Threshold = system.Convert(a.Variable, a.Threshold)
+	}
+	result.Readings = system.ConvertPoints(result.Readings)
+	for i := range result.Patterns {
+		result.Patterns[i].Readings = system.ConvertPoints(result.Patterns[i].Readings)
+	}
+	for i := range result.MultivariateAnomalies {
+		for j := range result.MultivariateAnomalies[i].Contributions {
+			c := &result.MultivariateAnomalies[i].Contributions[j]
+			c.Value = system.Convert(c.Variable, c.Value)
+		}
+	}
+	if risk := result.StormRisk; risk != nil {
+		for i := range risk.Factors {
+			f := &risk.Factors[i]
+			switch f.Factor {
+			case "pressure_fall_rate":
+				f.Value = system.ConvertPressure(f.Value)
+			case "wind_speed", "wind_speed_rise":
+				f.Value = system.ConvertWindSpeed(f.Value)
+			}
+		}
+	}
+	if icing := result.RoadIcing; icing != nil {
+		for i := range icing.Hours {
+			h := &icing.Hours[i]
+			h.SurfaceTemperature = system.ConvertTemperature(h.SurfaceTemperature)
+			h.RecentPrecipitation = system.ConvertPrecipitation(h.RecentPrecipitation)
+		}
+	}
+	for i := range result.ExtremeEvents {
+		e := &result.ExtremeEvents[i]
+		e.PeakTemperature = system.ConvertTemperature(e.PeakTemperature)
+		e.Intensity = system.ConvertTemperatureChange(e.Intensity)
+		e.Threshold = system.ConvertTemperature(e.Threshold)
+	}
+	if p := result.Precipitation; p != nil {
+		p.Total = system.ConvertPrecipitation(p.Total)
+		p.ExpectedTotal = system.ConvertPrecipitation(p.ExpectedTotal)
+		p.PeakRate = system.ConvertPrecipitation(p.PeakRate)
+		for i := range p.Windows {
+			p.Windows[i].Total = system.ConvertPrecipitation(p.Windows[i].Total)
+		}
+	}
+	if agro := result.AgroIndices; agro != nil {
+		agro.GrowingDegreeDays = system.ConvertTemperatureChange(agro.GrowingDegreeDays)
+		agro.BaseTemperature = system.ConvertTemperature(agro.BaseTemperature)
+	}
+	for i := range result.Energy {
+		result.Energy[i].HubWindSpeed = system.ConvertWindSpeed(result.Energy[i].HubWindSpeed)
+	}
+	for i := range result.RuleAlerts {
+		result.RuleAlerts[i].Readings = system.ConvertPoints(result.RuleAlerts[i].Readings)
+	}
+	if quality := result.DataQuality; quality != nil && len(quality.Stuck) > 0 {
+		for i := range quality.Stuck {
+			quality.Stuck[i].Value = system.Convert(quality.Stuck[i].Variable, quality.Stuck[i].Value)
+		}
+		quality.Warnings = analysis.QualityWarnings(quality)
+	}
+	convertSummary(&result.WeatherSummary, system)
+	for i := range result.StatisticalData {
+		convertStatistics(&result.StatisticalData[i], system)
+	}
+	for i := range result.Seasonality {
+		s := &result.Seasonality[i]
+		s.Amplitude = system.ConvertChange(s.Variable, s.Amplitude)
+	}
+	for i := range result.Forecast {
+		f := &result.Forecast[i]
+		f.Value = system.Convert(f.Variable, f.Value)
+		f.Lower = system.Convert(f.Variable, f.Lower)
+		f.Upper = system.Convert(f.Variable, f.Upper)
+	}
+	result.ForecastSkill = verification.ConvertSkill(result.ForecastSkill, system)
+}
+
+// convertSummary converts the values of a weather summary
+func convertSummary(summary *models.WeatherSummary, system units.System) {
+	for _, t := range []*float64{
+		&summary.CurrentTemp, &summary.MinTemperature, &summary.MaxTemperature, &summary.CurrentDewPoint,
+		&summary.CurrentHeatIndex, &summary.CurrentWindChill, &summary.CurrentApparentTemperature,
+	} {
+		*t = system.ConvertTemperature(*t)
+	}
+	for _, p := range []*float64{&summary.CurrentPressure, &summary.MinPressure, &summary.MaxPressure} {
+		*p = system.ConvertPressure(*p)
+	}
+	if wind := summary.Wind; wind != nil {
+		wind.WindSpeed = system.ConvertWindSpeed(wind.WindSpeed)
+		wind.MaxWindSpeed = system.ConvertWindSpeed(wind.MaxWindSpeed)
+		wind.MaxGust = system.ConvertWindSpeed(wind.MaxGust)
+	}
+	if comfort := summary.Comfort; comfort != nil {
+		comfort.FeelsLike = system.ConvertTemperature(comfort.FeelsLike)
+		comfort.Humidex = system.ConvertTemperature(comfort.Humidex)
+		comfort.WindChill = system.ConvertTemperature(comfort.WindChill)
+		for i := range comfort.Periods {
+			comfort.Periods[i].Peak = system.ConvertTemperature(comfort.Periods[i].Peak)
+		}
+	}
+	if tendency := summary.PressureTendency; tendency != nil {
+		tendency.Change = system.ConvertPressure(tendency.Change)
+	}
+}
+
+// convertStatistics converts the statistics of a variable: the values, percentiles and bounds like
+// the variable, the spreads as differences and the wind rose's speeds as wind speeds
+func convertStatistics(stat *models.StatisticalData, system units.System) {
+	for _, v := range []*float64{
+		&stat.Mean, &stat.Median, &stat.Min, &stat.Max, &stat.CiLower, &stat.CiUpper,
+		&stat.P10, &stat.P25, &stat.P75, &stat.P90,
+	} {
+		*v = system.Convert(stat.Variable, *v)
+	}
+	stat.StdDev = system.ConvertChange(stat.Variable, stat.StdDev)
+	stat.IQR = system.ConvertChange(stat.Variable, stat.IQR)
+	for i := range stat.Rolling {
+		r := &stat.Rolling[i]
+		for _, column := range [][]float64{r.Mean, r.Min, r.Max} {
+			for j := range column {
+				column[j] = system.Convert(stat.Variable, column[j])
+			}
+		}
+		for j := range r.StdDev {
+			r.StdDev[j] = system.ConvertChange(stat.Variable, r.StdDev[j])
+		}
+	}
+	if rose := stat.WindRose; rose != nil {
+		for i := range rose.Sectors {
+			rose.Sectors[i].MeanSpeed = system.ConvertWindSpeed(rose.Sectors[i].MeanSpeed)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package events publishes analysis results to the event bus the data collector publishes its
+// weather results to, a NATS subject or a Kafka topic. It reads the same "events" section of the
+// shared config file and publishes with the same bus clients, those of weathermodels/eventbus.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/eventbus"
+)
+
+// Event bus drivers accepted in events.driver
+const (
+	DriverNATS  = eventbus.DriverNATS
+	DriverKafka = eventbus.DriverKafka
+)
+
+// Config mirrors the collector's EventsConfig
+type Config struct {
+	Driver          string        `json:"driver"`           // Event bus: "" (disabled), "nats" or "kafka"
+	URL             string        `json:"url"`              // NATS server ("nats://host:4222", "tls://host:4222") or Kafka REST Proxy base URL
+	WeatherSubject  string        `json:"weather_subject"`  // NATS subject or Kafka topic for weather results (collector)
+	AnalysisSubject string        `json:"analysis_subject"` // NATS subject or Kafka topic for analysis results
+	Username        string        `json:"username"`         // User name ("" = anonymous)
+	Password        string        `json:"password"`         // Password
+	Token           string        `json:"token"`            // NATS authentication token
+	Timeout         time.Duration `json:"timeout"`          // Time allowed to connect and publish a run
+}
+
+// DefaultConfig returns the event settings used when no config file is given: publishing disabled
+func DefaultConfig() Config {
+	return Config{
+		WeatherSubject:  "weather.results",
+		AnalysisSubject: "weather.analysis",
+		Timeout:         30 * time.Second,
+	}
+}
+
+// LoadConfig reads the "events" section of a config file, falling back to defaults if path is empty
+func LoadConfig(path string) (Config, error) {
+	file := struct {
+		Events Config `json:"events"`
+	}{Events: DefaultConfig()}
+
+	if path == "" {
+		return file.Events, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file.Events, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file.Events, err
+	}
+	return file.Events, nil
+}
+
+// Publisher publishes each AnalysisResult of a run as one JSON message
+type Publisher struct {
+	Bus     eventbus.Bus
+	Subject string        // NATS subject or Kafka topic
+	Timeout time.Duration // Time allowed for connecting and publishing a whole run (0 = no limit)
+}
+
+// NewPublisher creates a publisher from the events configuration, or returns nil when no
+// event bus is configured
+func NewPublisher(cfg Config) (*Publisher, error) {
+	switch cfg.Driver {
+	case "":
+		return nil, nil
+	case DriverNATS, DriverKafka:
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", cfg.Driver)
+	}
+	if cfg.URL == "" || cfg.AnalysisSubject == "" {
+		return nil, fmt.Errorf("event bus %q needs a url and an analysis_subject", cfg.Driver)
+	}
+
+	return &Publisher{
+		Bus: eventbus.Bus{
+			Driver: cfg.Driver,
+			NATS: eventbus.NATSOptions{
+				URL:      cfg.URL,
+				Name:     "pattern-engine",
+				Username: cfg.Username,
+				Password: cfg.Password,
+				Token:    cfg.Token,
+			},
+			Kafka: &eventbus.KafkaProducer{
+				URL:       cfg.URL,
+				Username:  cfg.Username,
+				Password:  cfg.Password,
+				UserAgent: "pattern-engine/2.0",
+				Client:    &http.Client{},
+			},
+		},
+		Subject: cfg.AnalysisSubject,
+		Timeout: cfg.Timeout,
+	}, nil
+}
+
+// Publish publishes every analysis of a run, keyed by location name. A nil Publisher does nothing.
+func (p *Publisher) Publish(ctx context.Context, analyses []models.AnalysisResult) error {
+	if p == nil || len(analyses) == 0 {
+		return nil
+	}
+	messages := make([]eventbus.Message, len(analyses))
+	for i, analysis := range analyses {
+		value, err := json.Marshal(analysis)
+		if err != nil {
+			return fmt.Errorf("failed to encode event for %s: %w", analysis.Location, err)
+		}
+		messages[i] = eventbus.Message{Key: analysis.Location, Value: value}
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	return p.Bus.Publish(ctx, p.Subject, messages)
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pattern-engine/models"
+)
+
+// TestLoadConfig tests that the events section is read and unset fields keep their defaults
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"logging": {"log_level": 3}, "events": {"driver": "nats", "url": "nats://localhost:4222"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Driver != DriverNATS || cfg.AnalysisSubject != "weather.analysis" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	if publisher, err := NewPublisher(DefaultConfig()); publisher != nil || err != nil {
+		t.Errorf("Expected no publisher without a driver, got %v (err: %v)", publisher, err)
+	}
+	if _, err := NewPublisher(Config{Driver: "rabbitmq", URL: "amqp://localhost", AnalysisSubject: "a"}); err == nil {
+		t.Error("Expected an error for an unknown driver")
+	}
+}
+
+// TestPublishKafka tests that each analysis is produced to the analysis topic keyed by location
+func TestPublishKafka(t *testing.T) {
+	var body struct {
+		Records []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/weather.analysis" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":1},{"partition":0,"offset":2}]}`))
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.Driver, cfg.URL = DriverKafka, server.URL
+	publisher, err := NewPublisher(cfg)
+	if err != nil {
+		t.Fatalf("NewPublisher failed: %v", err)
+	}
+	analyses := []models.AnalysisResult{{Location: "Oslo", AnalysisType: "comprehensive_weather_analysis"}, {Location: "Bergen"}}
+	if err := publisher.Publish(context.Background(), analyses); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if len(body.Records) != 2 || body.Records[0].Key != "Oslo" || body.Records[1].Key != "Bergen" {
+		t.Fatalf("Expected records keyed by location, got %+v", body.Records)
+	}
+	var first models.AnalysisResult
+	if err := json.Unmarshal(body.Records[0].Value, &first); err != nil || first.AnalysisType != "comprehensive_weather_analysis" {
+		t.Errorf("Expected the analysis as record value, got %s (err: %v)", body.Records[0].Value, err)
+	}
+
+	var nilPublisher *Publisher
+	if err := nilPublisher.Publish(context.Background(), analyses); err != nil {
+		t.Errorf("Expected a nil publisher to do nothing, got %v", err)
+	}
+}
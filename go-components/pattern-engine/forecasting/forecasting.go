@@ -0,0 +1,227 @@
+// Package forecasting extrapolates a location's recent readings a few hours ahead. By default each
+// variable follows the least-squares trend of the analysis package, bent by the change in slope
+// over the most recent readings; the Holt-Winters model instead follows the daily cycle of
+// temperature and humidity. Prediction intervals widen with the forecast horizon.
+package forecasting
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"pattern-engine/analysis"
+	"pattern-engine/models"
+)
+
+// ForecastAnalyzer is the name the forecaster is registered under
+const ForecastAnalyzer = "forecast"
+
+// minReadings is the fewest readings in the window a variable is forecast from
+const minReadings = 3
+
+// Forecast models
+const (
+	ModelTrend       = "trend"        // Least-squares trend with curvature, for every variable
+	ModelHoltWinters = "holt_winters" // Holt-Winters with a daily season for temperature and humidity, trend for the rest
+)
+
+// Config is the "forecast" key of the "analysis" config section
+type Config struct {
+	Model        string  `json:"model"`         // ModelTrend or ModelHoltWinters
+	HorizonHours int     `json:"horizon_hours"` // Hours past the last reading to forecast
+	WindowHours  int     `json:"window_hours"`  // Hours of recent readings the trend model is fitted to
+	Confidence   float64 `json:"confidence"`    // Probability the prediction intervals cover (e.g. 0.9)
+
+	// Holt-Winters smoothing factors (0-1]; higher values follow recent readings more closely.
+	// The model is fitted to every reading and needs two days of them, falling back to the trend.
+	LevelSmoothing    float64 `json:"level_smoothing"`
+	TrendSmoothing    float64 `json:"trend_smoothing"`
+	SeasonalSmoothing float64 `json:"seasonal_smoothing"`
+}
+
+// DefaultConfig returns the forecast settings used without a config file
+func DefaultConfig() Config {
+	return Config{
+		Model:        ModelTrend,
+		HorizonHours: 6,
+		WindowHours:  24,
+		Confidence:   0.9,
+
+		LevelSmoothing:    0.4,
+		TrendSmoothing:    0.05,
+		SeasonalSmoothing: 0.3,
+	}
+}
+
+// Forecaster forecasts temperature, pressure, humidity and wind speed; it implements
+// analysis.Analyzer
+type Forecaster struct {
+	Config
+}
+
+// New creates a forecaster with the given settings
+func New(cfg Config) *Forecaster {
+	return &Forecaster{cfg}
+}
+
+// variables are the forecast variables, with the range of values they can take and whether they
+// follow a daily cycle
+var variables = []struct {
+	name     string
+	value    func(models.WeatherPoint) float64
+	min, max float64
+	seasonal bool
+}{
+	{"temperature", func(r models.WeatherPoint) float64 { return r.Temperature }, math.Inf(-1), math.Inf(1), true},
+	{"pressure", func(r models.WeatherPoint) float64 { return r.Pressure }, 0, math.Inf(1), false},
+	{"humidity", func(r models.WeatherPoint) float64 { return r.Humidity }, 0, 100, true},
+	{"wind_speed", func(r models.WeatherPoint) float64 { return r.WindSpeed }, 0, math.Inf(1), false},
+}
+
+// model is a fitted forecast model of one variable
+type model interface {
+	// predict returns the value h hours past the last reading and its standard error
+	predict(h float64) (value, spread float64)
+}
+
+// Name implements analysis.Analyzer
+func (f *Forecaster) Name() string { return ForecastAnalyzer }
+
+// Analyze implements analysis.Analyzer with Forecast
+func (f *Forecaster) Analyze(locationData *models.LocationData) (any, error) {
+	return f.Forecast(locationData), nil
+}
+
+// Forecast returns hourly forecasts of each variable for HorizonHours past the last reading,
+// grouped by variable. Variables with fewer than 3 readings in the window are not forecast.
+func (f *Forecaster) Forecast(locationData *models.LocationData) []models.ForecastPoint {
+	readings := locationData.Readings
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+	if len(readings) == 0 {
+		return []models.ForecastPoint{}
+	}
+
+	// Fit the most recent readings only; older weather says little about the next hours
+	last := readings[len(readings)-1].Timestamp
+	start := sort.Search(len(readings), func(i int) bool {
+		return last.Sub(readings[i].Timestamp) <= time.Duration(f.WindowHours)*time.Hour
+	})
+	window := readings[start:]
+
+	z := math.Sqrt2 * math.Erfinv(f.Confidence)
+	forecast := []models.ForecastPoint{}
+	for _, variable := range variables {
+		var fit model
+		modelName := ModelTrend
+		if f.Model == ModelHoltWinters && variable.seasonal {
+			if hw, ok := fitHoltWinters(readings, variable.value, f.Config); ok {
+				fit, modelName = hw, ModelHoltWinters
+			}
+		}
+		if fit == nil {
+			trend, ok := fitTrend(window, variable.value)
+			if !ok {
+				continue
+			}
+			fit = trend
+		}
+		for h := 1; h <= f.HorizonHours; h++ {
+			value, spread := fit.predict(float64(h))
+			forecast = append(forecast, models.ForecastPoint{
+				Variable:  variable.name,
+				Model:     modelName,
+				Timestamp: last.Add(time.Duration(h) * time.Hour),
+				Value:     clamp(value, variable.min, variable.max),
+				Lower:     clamp(value-z*spread, variable.min, variable.max),
+				Upper:     clamp(value+z*spread, variable.min, variable.max),
+			})
+		}
+	}
+	return forecast
+}
+
+// trendFit is a linear trend with a curvature term, fitted to readings in hours since the first
+type trendFit struct {
+	n         int
+	meanX     float64
+	sxx       float64 // Sum of squared deviations of x from its mean
+	lastX     float64
+	level     float64 // Trend value at the last reading
+	slope     float64 // Change per hour
+	curvature float64 // Change in slope per hour
+	residual  float64 // Standard deviation of the readings around the linear trend
+}
+
+// fitTrend fits the least-squares trend of a variable and the change in slope between the older
+// and newer half of the readings
+func fitTrend(readings []models.WeatherPoint, value func(models.WeatherPoint) float64) (trendFit, bool) {
+	n := len(readings)
+	if n < minReadings {
+		return trendFit{}, false
+	}
+	base := readings[0].Timestamp
+	hours := func(r models.WeatherPoint) float64 { return r.Timestamp.Sub(base).Hours() }
+
+	var fit trendFit
+	fit.n = n
+	fit.lastX = hours(readings[n-1])
+	var meanY float64
+	for _, r := range readings {
+		fit.meanX += hours(r)
+		meanY += value(r)
+	}
+	fit.meanX /= float64(n)
+	meanY /= float64(n)
+	for _, r := range readings {
+		fit.sxx += (hours(r) - fit.meanX) * (hours(r) - fit.meanX)
+	}
+	if fit.sxx == 0 {
+		return trendFit{}, false // All readings at one time
+	}
+
+	fit.slope, _ = analysis.LinearTrend(readings, value)
+	intercept := meanY - fit.slope*fit.meanX
+	fit.level = intercept + fit.slope*fit.lastX
+
+	var sumSquares float64
+	for _, r := range readings {
+		diff := value(r) - (intercept + fit.slope*hours(r))
+		sumSquares += diff * diff
+	}
+	fit.residual = math.Sqrt(sumSquares / float64(n-2))
+
+	// Curvature: how much steeper (or flatter) the newer half of the readings is than the older
+	if n >= 2*minReadings {
+		older, newer := readings[:n/2], readings[n/2:]
+		olderSlope, _ := analysis.LinearTrend(older, value)
+		newerSlope, _ := analysis.LinearTrend(newer, value)
+		if gap := midpoint(newer, hours) - midpoint(older, hours); gap > 0 {
+			fit.curvature = (newerSlope - olderSlope) / gap
+		}
+	}
+	return fit, true
+}
+
+// predict implements model
+func (fit trendFit) predict(h float64) (value, spread float64) {
+	value = fit.level + fit.slope*h + fit.curvature*h*h/2
+	x := fit.lastX + h
+	spread = fit.residual * math.Sqrt(1+1/float64(fit.n)+(x-fit.meanX)*(x-fit.meanX)/fit.sxx)
+	return value, spread
+}
+
+// midpoint returns the mean time of readings in hours
+func midpoint(readings []models.WeatherPoint, hours func(models.WeatherPoint) float64) float64 {
+	var sum float64
+	for _, r := range readings {
+		sum += hours(r)
+	}
+	return sum / float64(len(readings))
+}
+
+// clamp limits v to [lo, hi]
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
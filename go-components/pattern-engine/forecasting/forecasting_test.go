@@ -0,0 +1,105 @@
+package forecasting
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// hourlyLocation returns a location with hourly readings from the given functions of the hour
+func hourlyLocation(hours int, temperature, humidity func(h float64) float64) *models.LocationData {
+	location := &models.LocationData{Name: "Bergen, Norway"}
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	for i := range hours {
+		h := float64(i)
+		location.Readings = append(location.Readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: temperature(h),
+			Pressure:    1010,
+			Humidity:    humidity(h),
+			WindSpeed:   4 + math.Mod(h, 2), // Alternates 4 and 5 m/s
+		})
+	}
+	return location
+}
+
+// pointsFor returns the forecast points of a variable
+func pointsFor(forecast []models.ForecastPoint, variable string) []models.ForecastPoint {
+	var points []models.ForecastPoint
+	for _, p := range forecast {
+		if p.Variable == variable {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// TestForecastLinear tests that a steady trend is extrapolated hourly past the last reading
+func TestForecastLinear(t *testing.T) {
+	location := hourlyLocation(12, func(h float64) float64 { return 10 + 0.5*h }, func(float64) float64 { return 70 })
+	forecast := New(DefaultConfig()).Forecast(location)
+
+	temperature := pointsFor(forecast, "temperature")
+	if len(temperature) != 6 || len(forecast) != 24 {
+		t.Fatalf("Expected 6 hourly points for each of 4 variables, got %d (%d temperature)", len(forecast), len(temperature))
+	}
+	last := location.Readings[11].Timestamp
+	for i, p := range temperature {
+		want := 10 + 0.5*float64(11+i+1)
+		if !p.Timestamp.Equal(last.Add(time.Duration(i+1)*time.Hour)) || math.Abs(p.Value-want) > 1e-9 {
+			t.Errorf("Point %d: expected %.2f at %s, got %+v", i, want, last.Add(time.Duration(i+1)*time.Hour), p)
+		}
+		if math.Abs(p.Upper-p.Value) > 1e-9 || math.Abs(p.Lower-p.Value) > 1e-9 {
+			t.Errorf("Expected no spread around an exact trend, got %+v", p)
+		}
+	}
+}
+
+// TestForecastCurvatureAndIntervals tests that a bending trend is followed and that noisy
+// variables get intervals that widen with the horizon
+func TestForecastCurvatureAndIntervals(t *testing.T) {
+	location := hourlyLocation(12, func(h float64) float64 { return 0.1 * h * h }, func(float64) float64 { return 70 })
+	forecast := New(DefaultConfig()).Forecast(location)
+
+	// Without the curvature term the forecast would follow the average slope and fall behind
+	temperature := pointsFor(forecast, "temperature")
+	slopeOnly := 0.1*11*11 + 1.1*6 // Last value plus the overall least-squares slope for 6 hours
+	if got := temperature[5].Value; got <= slopeOnly {
+		t.Errorf("Expected the curvature to raise the 6-hour forecast above %.2f, got %.2f", slopeOnly, got)
+	}
+
+	wind := pointsFor(forecast, "wind_speed")
+	first, lastPoint := wind[0].Upper-wind[0].Lower, wind[5].Upper-wind[5].Lower
+	if first <= 0 || lastPoint <= first {
+		t.Errorf("Expected intervals widening with the horizon, got %.3f then %.3f", first, lastPoint)
+	}
+}
+
+// TestForecastBounds tests that values are kept within the range of the variable
+func TestForecastBounds(t *testing.T) {
+	location := hourlyLocation(12, func(float64) float64 { return 10 }, func(h float64) float64 { return 60 + 4*h })
+	forecast := New(Config{HorizonHours: 12, WindowHours: 24, Confidence: 0.9}).Forecast(location)
+
+	for _, p := range pointsFor(forecast, "humidity") {
+		if p.Value > 100 || p.Upper > 100 || p.Lower < 0 {
+			t.Errorf("Humidity forecast out of range: %+v", p)
+		}
+	}
+}
+
+// TestForecastWindow tests that only recent readings are fitted and too few are not forecast
+func TestForecastWindow(t *testing.T) {
+	// Falling for 12 hours, then rising for the last 4
+	location := hourlyLocation(16, func(h float64) float64 { return math.Abs(h - 12) }, func(float64) float64 { return 70 })
+	forecast := New(Config{HorizonHours: 1, WindowHours: 3, Confidence: 0.9}).Forecast(location)
+	if temperature := pointsFor(forecast, "temperature"); len(temperature) != 1 || math.Abs(temperature[0].Value-4) > 1e-9 {
+		t.Errorf("Expected the rise of the last readings to continue to 4, got %+v", temperature)
+	}
+
+	short := hourlyLocation(2, func(float64) float64 { return 10 }, func(float64) float64 { return 70 })
+	if forecast := New(DefaultConfig()).Forecast(short); len(forecast) != 0 {
+		t.Errorf("Expected no forecast from 2 readings, got %d points", len(forecast))
+	}
+}
@@ -0,0 +1,87 @@
+package forecasting
+
+import (
+	"math"
+
+	"pattern-engine/analysis"
+	"pattern-engine/models"
+)
+
+// seasonLength is the seasonal period of the Holt-Winters model in hours: the daily cycle
+const seasonLength = 24
+
+// holtWintersFit is an additive Holt-Winters (triple exponential smoothing) model of a variable
+// on an hourly grid ending at the last reading
+type holtWintersFit struct {
+	level    float64
+	trend    float64
+	season   [seasonLength]float64 // Seasonal component of the hours after the last reading, in order
+	alpha    float64
+	beta     float64
+	gamma    float64
+	residual float64 // Standard deviation of the one-hour-ahead errors while fitting
+}
+
+// fitHoltWinters fits a Holt-Winters model with a 24-hour season to readings in chronological
+// order. It needs two full seasons of readings, which it resamples to whole hours before the last.
+func fitHoltWinters(readings []models.WeatherPoint, value func(models.WeatherPoint) float64, cfg Config) (holtWintersFit, bool) {
+	y := analysis.ResampleHourly(readings, value)
+	n := len(y)
+	if n < 2*seasonLength {
+		return holtWintersFit{}, false
+	}
+
+	// Initial level and trend from the means of the first two seasons, seasonal components from
+	// the first season's deviations from its mean
+	first, second := mean(y[:seasonLength]), mean(y[seasonLength:2*seasonLength])
+	level := first
+	trend := (second - first) / seasonLength
+	season := make([]float64, n)
+	for i := range seasonLength {
+		season[i] = y[i] - first
+	}
+
+	fit := holtWintersFit{alpha: cfg.LevelSmoothing, beta: cfg.TrendSmoothing, gamma: cfg.SeasonalSmoothing}
+	var sumSquares float64
+	for t := seasonLength; t < n; t++ {
+		s := season[t-seasonLength]
+		err := y[t] - (level + trend + s)
+		sumSquares += err * err
+
+		previous := level
+		level = fit.alpha*(y[t]-s) + (1-fit.alpha)*(level+trend)
+		trend = fit.beta*(level-previous) + (1-fit.beta)*trend
+		season[t] = fit.gamma*(y[t]-level) + (1-fit.gamma)*s
+	}
+
+	fit.level, fit.trend = level, trend
+	copy(fit.season[:], season[n-seasonLength:])
+	fit.residual = math.Sqrt(sumSquares / float64(n-seasonLength))
+	return fit, true
+}
+
+// predict implements model; the error grows with each hour of level, trend and seasonal updates
+// the forecast skips
+func (fit holtWintersFit) predict(h float64) (value, spread float64) {
+	steps := int(h)
+	value = fit.level + h*fit.trend + fit.season[(steps-1)%seasonLength]
+
+	variance := 1.0
+	for j := 1; j < steps; j++ {
+		c := fit.alpha * (1 + float64(j)*fit.beta)
+		if j%seasonLength == 0 {
+			c += fit.gamma * (1 - fit.alpha)
+		}
+		variance += c * c
+	}
+	return value, fit.residual * math.Sqrt(variance)
+}
+
+// mean returns the mean of values
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,57 @@
+package forecasting
+
+import (
+	"math"
+	"testing"
+)
+
+// diurnal is a temperature with a daily cycle peaking mid-afternoon
+func diurnal(h float64) float64 {
+	return 10 + 5*math.Sin(2*math.Pi*(h-9)/24)
+}
+
+// TestHoltWintersDiurnal tests that Holt-Winters follows the daily cycle where the trend model
+// overshoots
+func TestHoltWintersDiurnal(t *testing.T) {
+	// Three days ending at 14:00, while temperature still rises towards its peak at 15:00
+	humidity := func(h float64) float64 { return 70 - 2*(diurnal(h)-10) }
+	location := hourlyLocation(63, diurnal, humidity)
+
+	cfg := DefaultConfig()
+	trend := pointsFor(New(cfg).Forecast(location), "temperature")
+	cfg.Model = ModelHoltWinters
+	forecast := New(cfg).Forecast(location)
+	seasonal := pointsFor(forecast, "temperature")
+
+	var trendErr, seasonalErr float64
+	for i := range seasonal {
+		actual := diurnal(float64(62 + i + 1))
+		trendErr = math.Max(trendErr, math.Abs(trend[i].Value-actual))
+		seasonalErr = math.Max(seasonalErr, math.Abs(seasonal[i].Value-actual))
+		if seasonal[i].Model != ModelHoltWinters {
+			t.Errorf("Expected a Holt-Winters point, got %+v", seasonal[i])
+		}
+	}
+	if seasonalErr > 0.5 || seasonalErr >= trendErr {
+		t.Errorf("Expected Holt-Winters (max error %.2f) within 0.5°C and better than the trend (max error %.2f)", seasonalErr, trendErr)
+	}
+
+	if h := pointsFor(forecast, "humidity"); len(h) != 6 || h[0].Model != ModelHoltWinters {
+		t.Errorf("Expected a Holt-Winters humidity forecast, got %+v", h)
+	}
+	if p := pointsFor(forecast, "pressure"); len(p) != 6 || p[0].Model != ModelTrend {
+		t.Errorf("Expected pressure to keep the trend model, got %+v", p)
+	}
+}
+
+// TestHoltWintersFallback tests that less than two days of readings are forecast with the trend
+func TestHoltWintersFallback(t *testing.T) {
+	location := hourlyLocation(30, diurnal, func(float64) float64 { return 70 })
+	cfg := DefaultConfig()
+	cfg.Model = ModelHoltWinters
+
+	temperature := pointsFor(New(cfg).Forecast(location), "temperature")
+	if len(temperature) != 6 || temperature[0].Model != ModelTrend {
+		t.Errorf("Expected a trend forecast from 30 hours of readings, got %+v", temperature)
+	}
+}
@@ -0,0 +1,93 @@
+// Package logging configures the process-wide slog logger for the pattern engine.
+// It reads the same "logging" section as the data collector's config file so both
+// binaries can share one configuration.
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"weathermodels/logfile"
+	"weathermodels/logging"
+)
+
+// DefaultLogFile is used when log_to_file is set without a log_file path
+const DefaultLogFile = "data/logs/pattern-engine.log"
+
+// Config mirrors the collector's LoggingConfig
+type Config struct {
+	EnableDebug bool   `json:"enable_debug"` // Show detailed debug logs
+	LogToFile   bool   `json:"log_to_file"`  // Also write logs to log_file
+	LogFile     string `json:"log_file"`     // Log file path when log_to_file is set
+	LogLevel    int    `json:"log_level"`    // Log level (0=Error, 1=Warn, 2=Info, 3=Debug)
+	LogFormat   string `json:"log_format"`   // Log output format: "text" or "json"
+
+	// Log file rotation; zero disables the corresponding limit
+	LogMaxSizeMB      int           `json:"log_max_size_mb"`     // Rotate once the file reaches this size
+	LogRotateInterval time.Duration `json:"log_rotate_interval"` // Rotate at each interval boundary (e.g. daily)
+	LogMaxBackups     int           `json:"log_max_backups"`     // Rotated files to keep
+	LogMaxAge         time.Duration `json:"log_max_age"`         // Delete rotated files older than this
+}
+
+// DefaultConfig returns the logging settings used when no config file is given
+func DefaultConfig() Config {
+	return Config{
+		LogFile:   DefaultLogFile,
+		LogLevel:  2, // Info level
+		LogFormat: "text",
+
+		LogMaxSizeMB:      10,
+		LogRotateInterval: 24 * time.Hour,
+		LogMaxBackups:     7,
+		LogMaxAge:         30 * 24 * time.Hour,
+	}
+}
+
+// LoadConfig reads the "logging" section of a config file, falling back to defaults if path is empty
+func LoadConfig(path string) (Config, error) {
+	file := struct {
+		Logging Config `json:"logging"`
+	}{Logging: DefaultConfig()}
+
+	if path == "" {
+		return file.Logging, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file.Logging, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file.Logging, err
+	}
+	return file.Logging, nil
+}
+
+// Options converts the logging config to the shared logger options
+func Options(cfg Config) logging.Options {
+	opts := logging.Options{
+		Level:  cfg.LogLevel,
+		Debug:  cfg.EnableDebug,
+		Format: cfg.LogFormat,
+		Rotation: logfile.RotationPolicy{
+			MaxSize:    int64(cfg.LogMaxSizeMB) * 1024 * 1024,
+			Interval:   cfg.LogRotateInterval,
+			MaxBackups: cfg.LogMaxBackups,
+			MaxAge:     cfg.LogMaxAge,
+		},
+	}
+	if cfg.LogToFile {
+		opts.File = cfg.LogFile
+		if opts.File == "" {
+			opts.File = DefaultLogFile
+		}
+	}
+	return opts
+}
+
+// Setup installs the configured logger as the slog default; see logging.Setup
+func Setup(cfg Config) (io.Closer, error) {
+	return logging.Setup(Options(cfg))
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadConfig tests reading the shared logging section and defaults for missing fields
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"api": {"timeout": 1}, "logging": {"log_level": 3, "log_format": "json"}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.LogLevel != 3 || cfg.LogFormat != "json" {
+		t.Errorf("Expected level 3 json, got %+v", cfg)
+	}
+	if cfg.LogFile != DefaultLogFile {
+		t.Errorf("Expected default log file, got '%s'", cfg.LogFile)
+	}
+
+	if cfg, err := LoadConfig(""); err != nil || cfg != DefaultConfig() {
+		t.Errorf("Expected defaults for empty path, got %+v (%v)", cfg, err)
+	}
+}
+
+// TestOptions tests the conversion of the logging config to the shared logger options
+func TestOptions(t *testing.T) {
+	opts := Options(Config{LogLevel: 1, LogFormat: "json", LogToFile: true, LogMaxSizeMB: 10, LogMaxBackups: 7})
+	if opts.Level != 1 || opts.Format != "json" || opts.File != DefaultLogFile {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if opts.Rotation.MaxSize != 10*1024*1024 || opts.Rotation.MaxBackups != 7 {
+		t.Errorf("Unexpected rotation policy: %+v", opts.Rotation)
+	}
+
+	if opts := Options(DefaultConfig()); opts.File != "" {
+		t.Errorf("Expected no log file without log_to_file, got '%s'", opts.File)
+	}
+}
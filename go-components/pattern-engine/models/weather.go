@@ -1,40 +1,63 @@
 package models
 
-import "time"
-
-// WeatherPoint represents a single weather reading at a specific time
-type WeatherPoint struct {
-	Timestamp                time.Time `json:"timestamp"`
-	Temperature              float64   `json:"temperature"`
-	Pressure                 float64   `json:"pressure"`
-	Humidity                 float64   `json:"humidity"`
-	WindSpeed                float64   `json:"wind_speed"`
-	WindDirection            float64   `json:"wind_direction"`
-	CloudCover               float64   `json:"cloud_cover"`
-	PrecipitationMm          float64   `json:"precipitation_mm"`
-	PrecipitationProbability float64   `json:"precipitation_probability"`
-	SymbolCode               string    `json:"symbol_code"`
-}
+import (
+	"time"
+
+	"weathermodels"
+	"weathermodels/units"
+)
+
+// WeatherPoint represents a single weather reading at a specific time.
+// It is the shared type, so analysis input and the collector's output cannot drift apart.
+type WeatherPoint = weathermodels.WeatherPoint
+
+// Sources of a reading (see WeatherPoint.Source)
+const (
+	SourceObservation = weathermodels.SourceObservation
+	SourceForecast    = weathermodels.SourceForecast
+	SourceNowcast     = weathermodels.SourceNowcast
+)
+
+// Derived holds the variables the derive package computes from a reading's measured values
+type Derived = weathermodels.Derived
 
 // LocationData represents all weather data for a specific location
 type LocationData struct {
 	Name        string         `json:"location"`
 	Coordinates Coordinates    `json:"coordinates"`
+	Timezone    string         `json:"timezone,omitempty"` // IANA time zone of days and hours of the day, e.g. "Europe/Oslo" ("" = local solar time)
 	Readings    []WeatherPoint `json:"readings"`
+	Alerts      []string       `json:"alerts,omitempty"` // Active official warnings, e.g. "yellow_wind_warning"
+	Marine      []MarinePoint  `json:"marine,omitempty"` // Ocean forecast for coastal locations
 }
 
+// MarinePoint represents a single ocean forecast reading for a coastal location
+type MarinePoint = weathermodels.MarinePoint
+
+// ForecastArchive is the forecasts the collector kept for a location, for verification
+type ForecastArchive = weathermodels.ForecastArchive
+
+// IssuedForecast is one of the forecasts of a ForecastArchive
+type IssuedForecast = weathermodels.IssuedForecast
+
 // Coordinates represents geographic coordinates
 type Coordinates struct {
 	Latitude  float64 `json:"lat"`
 	Longitude float64 `json:"lon"`
+	Altitude  int     `json:"alt,omitempty"` // Meters above sea level (0 = unknown)
 }
 
 // Trend represents a weather trend with direction and confidence
 type Trend struct {
 	Variable   string  `json:"variable"`       // e.g., "temperature", "pressure"
 	Trend      string  `json:"trend"`          // e.g., "rising", "falling", "stable"
+	Method     string  `json:"method"`         // "least_squares" or "mann_kendall" (Sen's slope)
 	ChangeRate float64 `json:"rate_of_change"` // units per hour
-	Confidence float64 `json:"confidence"`     // 0.0-1.0
+	CiLower    float64 `json:"ci_lower"`       // lower bound of the confidence interval of the rate of change
+	CiUpper    float64 `json:"ci_upper"`       // upper bound of the confidence interval of the rate of change
+	PValue     float64 `json:"p_value"`        // probability of a rate this large without a trend
+	RSquared   float64 `json:"r_squared"`      // share of the variation the trend explains (0.0-1.0)
+	Confidence float64 `json:"confidence"`     // 1 - PValue
 	Duration   string  `json:"duration"`       // e.g., "6h", "24h"
 }
 
@@ -48,41 +71,424 @@ type Anomaly struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// MultivariateAnomaly is a reading whose combination of variables is unusual given how they
+// vary together, even if no variable is unusual by itself
+type MultivariateAnomaly struct {
+	Timestamp     time.Time      `json:"timestamp"`
+	Distance      float64        `json:"distance"`      // Mahalanobis distance from the mean of the readings
+	PValue        float64        `json:"p_value"`       // probability of a distance this large (chi-square)
+	Severity      string         `json:"severity"`      // "moderate" or "high"
+	Contributions []Contribution `json:"contributions"` // variables by their share of the distance, largest first
+}
+
+// Contribution is a variable's part in a multivariate anomaly
+type Contribution struct {
+	Variable string  `json:"variable"` // e.g., "humidity", "cloud_cover"
+	Value    float64 `json:"value"`    // the variable's value in the reading
+	Share    float64 `json:"share"`    // share of the squared distance; negative when the variable makes the reading more usual
+}
+
+// StormRisk is the risk of a storm in the coming readings, fused from the pressure tendency,
+// wind, precipitation probability and symbol codes
+type StormRisk struct {
+	Score         float64       `json:"score"`           // highest storm score of the coming readings (0.0-1.0)
+	Level         string        `json:"level"`           // "low", "moderate" or "high"
+	PeakTime      time.Time     `json:"peak_time"`       // time of the highest score
+	Onset         time.Time     `json:"onset,omitzero"`  // first time the risk is moderate or high
+	LeadTimeHours float64       `json:"lead_time_hours"` // hours from the analysis to Onset (0 = now or no onset)
+	Factors       []StormFactor `json:"factors"`         // parts of the score at its peak
+}
+
+// RoadIcingRisk is the risk of ice on roads in the coming readings
+type RoadIcingRisk struct {
+	Score         float64         `json:"score"`           // highest icing score of the coming readings (0.0-1.0)
+	Level         string          `json:"level"`           // "low", "moderate" or "high"
+	PeakTime      time.Time       `json:"peak_time"`       // time of the highest score
+	Onset         time.Time       `json:"onset,omitzero"`  // first time the risk is moderate or high
+	LeadTimeHours float64         `json:"lead_time_hours"` // hours from the analysis to Onset (0 = now or no onset)
+	Hours         []RoadIcingHour `json:"hours"`           // score of each coming reading
+}
+
+// RoadIcingHour is the road icing score of a reading
+type RoadIcingHour struct {
+	Timestamp           time.Time `json:"timestamp"`
+	SurfaceTemperature  float64   `json:"surface_temperature"`  // °C, the air temperature less the night's radiative cooling
+	RecentPrecipitation float64   `json:"recent_precipitation"` // mm over the window up to the reading
+	Score               float64   `json:"score"`                // 0.0-1.0
+}
+
+// RuleAlert is an alert raised by a configured rule (see package rules)
+type RuleAlert struct {
+	Rule      string         `json:"rule"`              // name of the rule
+	Severity  string         `json:"severity"`          // "low", "moderate" or "high"
+	Message   string         `json:"message,omitempty"` // the rule's description
+	Condition string         `json:"condition"`         // e.g., "temperature < 0 AND precipitation_probability > 60"
+	Start     time.Time      `json:"start"`             // first reading matching the condition
+	End       time.Time      `json:"end"`               // last reading matching the condition
+	Readings  []WeatherPoint `json:"readings"`          // readings matching the condition
+}
+
+// StormFactor is one sign of a storm and its part in the storm score
+type StormFactor struct {
+	Factor string  `json:"factor"` // "pressure_fall_rate", "wind_speed", "wind_speed_rise", "precipitation_probability" or "symbol_code"
+	Value  float64 `json:"value"`  // hPa/h, m/s, %, or 1 for thunder and 0.5 for heavy precipitation symbols
+	Score  float64 `json:"score"`  // how strongly the value signals a storm (0.0-1.0)
+}
+
+// AgroIndices are agricultural indices of the readings
+type AgroIndices struct {
+	GrowingDegreeDays float64   `json:"growing_degree_days"`           // °C days above BaseTemperature over the whole local days
+	BaseTemperature   float64   `json:"base_temperature"`              // °C
+	Days              int       `json:"days"`                          // whole local days of the readings
+	ChillHours        float64   `json:"chill_hours"`                   // hours from 0 to 7.2°C, by default
+	FrostDays         int       `json:"frost_days"`                    // days with a low at or below freezing, by default
+	LastSpringFrost   time.Time `json:"last_spring_frost,omitzero"`    // local midnight starting the last frost day of spring
+	FirstAutumnFrost  time.Time `json:"first_autumn_frost,omitzero"`   // local midnight starting the first frost day of autumn after it
+	GrowingSeasonDays int       `json:"growing_season_days,omitempty"` // frost-free days between them
+}
+
+// EnergyEstimate is the solar and wind power potential at a reading
+type EnergyEstimate struct {
+	Timestamp      time.Time `json:"timestamp"`
+	SolarElevation float64   `json:"solar_elevation"` // degrees above the horizon
+	Irradiance     float64   `json:"irradiance"`      // W/m² on level ground under the reading's cloud cover
+	SolarOutput    float64   `json:"solar_output"`    // kW per kW peak of level panels
+	HubWindSpeed   float64   `json:"hub_wind_speed"`  // m/s at the turbine's hub
+	WindOutput     float64   `json:"wind_output"`     // share of the turbine's rated power (0.0-1.0)
+}
+
+// PrecipitationAnalysis accumulates the precipitation of the readings, each reading's amount
+// falling in the hour after it
+type PrecipitationAnalysis struct {
+	Total          float64                `json:"total"`                 // mm over the readings
+	ExpectedTotal  float64                `json:"expected_total"`        // mm forecast from the current reading on
+	HorizonHours   float64                `json:"horizon_hours"`         // hours ExpectedTotal covers (0 for a history)
+	Windows        []PrecipitationWindow  `json:"windows"`               // wettest 1, 6 and 24 hours
+	IntensityHours PrecipitationIntensity `json:"intensity_hours"`       // wet hours of each intensity
+	PeakRate       float64                `json:"peak_rate"`             // mm in the wettest hour
+	PeakTime       time.Time              `json:"peak_time,omitzero"`    // start of the wettest hour
+	PeakIntensity  string                 `json:"peak_intensity"`        // "none", "drizzle", "moderate" or "heavy"
+	LongestDry     *PrecipitationStreak   `json:"longest_dry,omitempty"` // longest run of dry hours
+	LongestWet     *PrecipitationStreak   `json:"longest_wet,omitempty"` // longest run of wet hours
+}
+
+// PrecipitationWindow is the wettest window of a length
+type PrecipitationWindow struct {
+	Hours int       `json:"hours"` // length of the window
+	Start time.Time `json:"start"` // start of the wettest window
+	Total float64   `json:"total"` // mm in the window
+}
+
+// PrecipitationIntensity counts wet hours by intensity
+type PrecipitationIntensity struct {
+	Drizzle  int `json:"drizzle"`
+	Moderate int `json:"moderate"`
+	Heavy    int `json:"heavy"`
+}
+
+// PrecipitationStreak is a run of dry or wet hours
+type PrecipitationStreak struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`   // end of the last hour
+	Hours float64   `json:"hours"` // End - Start
+}
+
+// ExtremeEvent is a heat wave or cold snap: consecutive local days with highs beyond a percentile
+// of the location's daily highs
+type ExtremeEvent struct {
+	Type            string    `json:"type"`             // "heat_wave" or "cold_snap"
+	Start           time.Time `json:"start"`            // local midnight starting the first day
+	End             time.Time `json:"end"`              // local midnight ending the last day
+	DurationDays    int       `json:"duration_days"`    // days the event lasts
+	PeakDate        time.Time `json:"peak_date"`        // local midnight starting the most extreme day
+	PeakTemperature float64   `json:"peak_temperature"` // high of the most extreme day
+	Intensity       float64   `json:"intensity"`        // °C the most extreme day's high is beyond Threshold
+	Threshold       float64   `json:"threshold"`        // percentile of the daily highs the event's highs are beyond
+}
+
 // Pattern represents identified weather patterns
 type Pattern struct {
-	Name        string         `json:"name"`        // e.g., "cold_front", "warm_front", "pressure_system"
-	Description string         `json:"description"` // detailed description
-	Confidence  float64        `json:"confidence"`  // 0.0-1.0
-	Strength    float64        `json:"strength"`    // 0.0-1.0
-	Variables   []string       `json:"variables"`   // weather variables involved
-	Readings    []WeatherPoint `json:"readings"`    // data points supporting the pattern
+	Name        string         `json:"name"`               // e.g., "cold_front", "warm_front", "pressure_system"
+	Description string         `json:"description"`        // detailed description
+	Confidence  float64        `json:"confidence"`         // 0.0-1.0
+	Strength    float64        `json:"strength"`           // 0.0-1.0
+	Variables   []string       `json:"variables"`          // weather variables involved
+	Readings    []WeatherPoint `json:"readings"`           // data points supporting the pattern
+	Timestamp   time.Time      `json:"timestamp,omitzero"` // time of a pattern that happens at a moment, e.g., a front's passage, or the start of one over a period
+	Until       time.Time      `json:"until,omitzero"`     // end of a pattern over a period, e.g., a fog window
 }
 
+// Schema versions of the JSON files read and written by the engine. Files without a
+// schema_version predate versioning and are treated as version 1.
+const (
+	TimeseriesSchemaVersion = 1 // Newest time-series file layout the engine can read
+	AnalysisSchemaVersion   = 1 // Layout of the analysis files the engine writes
+)
+
 // AnalysisResult represents the complete analysis output
 type AnalysisResult struct {
-	AnalysisType    string            `json:"analysis_type"` // e.g., "trend_analysis", "anomaly_detection"
-	Timeframe       string            `json:"timeframe"`     // e.g., "24_hours", "7_days"
-	Location        string            `json:"location"`
-	GeneratedAt     time.Time         `json:"generated_at"`
-	Trends          []Trend           `json:"trends,omitempty"`
-	Anomalies       []Anomaly         `json:"anomalies,omitempty"`
-	Patterns        []Pattern         `json:"patterns,omitempty"`
-	WeatherSummary  WeatherSummary    `json:"weather_summary,omitzero"`
-	StatisticalData []StatisticalData `json:"statistical_data,omitempty"`
+	SchemaVersion         int                    `json:"schema_version"` // see AnalysisSchemaVersion
+	AnalysisType          string                 `json:"analysis_type"`  // e.g., "trend_analysis", "anomaly_detection"
+	Timeframe             string                 `json:"timeframe"`      // e.g., "24_hours", "7_days"
+	Location              string                 `json:"location"`
+	Coordinates           Coordinates            `json:"coordinates,omitzero"`
+	Timezone              string                 `json:"timezone,omitempty"` // IANA time zone of the days and hours of the day in the analysis
+	Units                 *units.System          `json:"units,omitempty"`    // Units of the values when not metric; the units the fields give are the metric ones
+	GeneratedAt           time.Time              `json:"generated_at"`
+	Trends                []Trend                `json:"trends,omitempty"`
+	Anomalies             []Anomaly              `json:"anomalies,omitempty"`
+	Patterns              []Pattern              `json:"patterns,omitempty"`
+	MultivariateAnomalies []MultivariateAnomaly  `json:"multivariate_anomalies,omitempty"` // Readings with an unusual combination of variables
+	StormRisk             *StormRisk             `json:"storm_risk,omitempty"`             // Risk of a storm in the coming readings
+	RoadIcing             *RoadIcingRisk         `json:"road_icing,omitempty"`             // Risk of ice on roads in the coming readings
+	ExtremeEvents         []ExtremeEvent         `json:"extreme_events,omitempty"`         // Heat waves and cold snaps, oldest first
+	Precipitation         *PrecipitationAnalysis `json:"precipitation_analysis,omitempty"` // Accumulation, intensity and streaks of precipitation
+	AgroIndices           *AgroIndices           `json:"agro_indices,omitempty"`           // Growing degree days, chill hours and frosts
+	Energy                []EnergyEstimate       `json:"energy,omitempty"`                 // Solar and wind power potential of each reading
+	RuleAlerts            []RuleAlert            `json:"rule_alerts,omitempty"`            // Alerts of the configured rules
+	DataQuality           *DataQuality           `json:"data_quality,omitempty"`           // Gaps, duplicates, stuck values and staleness of the readings
+	Resampling            *Resampling            `json:"resampling,omitempty"`             // How the readings were resampled for the regression and spectral analyses
+	WeatherSummary        WeatherSummary         `json:"weather_summary,omitzero"`
+	Narrative             string                 `json:"narrative,omitempty"` // The conclusions in a short paragraph (see package narrative)
+	StatisticalData       []StatisticalData      `json:"statistical_data,omitempty"`
+	Seasonality           []Seasonality          `json:"seasonality,omitempty"`      // Daily cycles found in the readings
+	Periodicities         []Periodicity          `json:"periodicities,omitempty"`    // Dominant periods of the readings' spectrum
+	LagCorrelations       []LagCorrelation       `json:"lag_correlations,omitempty"` // How each pair of variables moves together, strongest first
+	Forecast              []ForecastPoint        `json:"forecast,omitempty"`         // Hourly forecast of each variable past the last reading
+	ForecastSkill         *ForecastSkill         `json:"forecast_skill,omitempty"`   // Accuracy of the location's past forecasts, where verified
+	Extensions            map[string]any         `json:"extensions,omitempty"`       // Results of analyzers other than the built-in ones, by name
+
+	// Readings are the observations analyzed (those held in memory for a streamed file) in the
+	// units of the analysis, for the reports; they are not written to the analysis files
+	Readings []WeatherPoint `json:"-"`
+}
+
+// Seasonality is the daily cycle of a variable
+type Seasonality struct {
+	Variable  string  `json:"variable"`  // e.g., "temperature", "humidity"
+	Amplitude float64 `json:"amplitude"` // half the difference between the cycle's peak and trough
+	PeakHour  float64 `json:"peak_hour"` // hour of the day (local time, 0-24) the cycle peaks
+	Strength  float64 `json:"strength"`  // share of the variation around the trend the cycle explains (0.0-1.0)
+}
+
+// Periodicity is a dominant period in the power spectrum of a variable
+type Periodicity struct {
+	Variable    string  `json:"variable"`     // e.g., "temperature", "pressure"
+	PeriodHours float64 `json:"period_hours"` // length of one cycle
+	Label       string  `json:"label"`        // "diurnal", "semidiurnal", "synoptic", or "" for other periods
+	Power       float64 `json:"power"`        // share of the variable's spectral power at this period (0.0-1.0)
+	Confidence  float64 `json:"confidence"`   // confidence the period is not noise (0.0-1.0)
+}
+
+// LagCorrelation is the strongest correlation between two variables with one shifted in time
+type LagCorrelation struct {
+	Leader      string  `json:"leader"`      // variable whose changes come first, e.g., "pressure"
+	Follower    string  `json:"follower"`    // variable whose changes follow, e.g., "precipitation_mm"
+	LagHours    int     `json:"lag_hours"`   // hours by which Follower follows Leader (0 = together)
+	Correlation float64 `json:"correlation"` // Pearson correlation at that lag (-1.0-1.0); negative when one falls as the other rises
+}
+
+// RegionalAnalysis relates the readings of several locations analyzed together
+type RegionalAnalysis struct {
+	SchemaVersion int                  `json:"schema_version"` // see AnalysisSchemaVersion
+	AnalysisType  string               `json:"analysis_type"`  // "regional_weather_analysis"
+	GeneratedAt   time.Time            `json:"generated_at"`
+	Locations     []string             `json:"locations"`    // Locations whose readings overlap, in input order
+	Start         time.Time            `json:"start"`        // first hour every location's readings cover
+	End           time.Time            `json:"end"`          // last hour every location's readings cover
+	Correlations  []StationCorrelation `json:"correlations"` // Related pairs of locations, strongest first
+}
+
+// StationCorrelation is the strongest correlation of a variable between two locations with one
+// shifted in time, such as a pressure drop reaching one city hours after another
+type StationCorrelation struct {
+	Variable    string  `json:"variable"`            // e.g., "pressure", "temperature"
+	Leader      string  `json:"leader"`              // location where changes come first
+	Follower    string  `json:"follower"`            // location where changes follow
+	LagHours    int     `json:"lag_hours"`           // hours by which Follower follows Leader (0 = together)
+	Correlation float64 `json:"correlation"`         // Pearson correlation at that lag (-1.0-1.0)
+	DistanceKm  float64 `json:"distance_km"`         // great-circle distance between the locations
+	SpeedKmh    float64 `json:"speed_kmh,omitempty"` // distance over lag, how fast changes travel (0 = together)
+}
+
+// Interpolation is the weather estimated at a point from the analyses of the locations around it
+type Interpolation struct {
+	Coordinates Coordinates           `json:"coordinates"` // point estimated
+	Method      string                `json:"method"`      // "inverse_distance"
+	Temperature float64               `json:"temperature"` // estimated current temperature
+	Pressure    float64               `json:"pressure"`    // estimated current pressure
+	Trends      []InterpolatedTrend   `json:"trends"`      // estimated trend of each variable the locations have
+	Sources     []InterpolationSource `json:"sources"`     // locations the estimate is made from, nearest first
+}
+
+// InterpolatedTrend is the trend of a variable estimated at a point
+type InterpolatedTrend struct {
+	Variable   string  `json:"variable"`       // e.g., "temperature", "pressure"
+	Trend      string  `json:"trend"`          // "rising", "falling", "stable", ... as most of the weight has it
+	ChangeRate float64 `json:"rate_of_change"` // weighted rate of change, units per hour
+	Agreement  float64 `json:"agreement"`      // share of the weight with that trend (0.0-1.0)
+}
+
+// InterpolationSource is a location an interpolation is made from
+type InterpolationSource struct {
+	Location   string  `json:"location"`
+	DistanceKm float64 `json:"distance_km"` // great-circle distance from the point
+	Weight     float64 `json:"weight"`      // share of the estimate (0.0-1.0)
+}
+
+// ForecastPoint is the forecast value of one variable at one time, with its prediction interval
+type ForecastPoint struct {
+	Variable  string    `json:"variable"`  // e.g., "temperature", "pressure"
+	Model     string    `json:"model"`     // forecast model, e.g., "trend", "holt_winters"
+	Timestamp time.Time `json:"timestamp"` // time the forecast is for
+	Value     float64   `json:"value"`     // most likely value
+	Lower     float64   `json:"lower"`     // lower bound of the prediction interval
+	Upper     float64   `json:"upper"`     // upper bound of the prediction interval
+}
+
+// VerificationReport is the accuracy of the forecasts kept for a location, verified against the
+// readings observed at the times they were for
+type VerificationReport struct {
+	Location    string             `json:"location"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Forecasts   int                `json:"forecasts"`      // forecasts with at least one point verified
+	Start       time.Time          `json:"start,omitzero"` // time of the first point verified
+	End         time.Time          `json:"end,omitzero"`   // time of the last point verified
+	Variables   []ForecastAccuracy `json:"variables"`      // accuracy of each variable, in a fixed order
+}
+
+// ForecastSkill is how accurate the forecasts for a location have been, from its latest
+// verification report
+type ForecastSkill struct {
+	VerifiedAt time.Time          `json:"verified_at"` // time the report was generated
+	Forecasts  int                `json:"forecasts"`   // forecasts verified
+	Score      float64            `json:"score"`       // skill of the forecasts from their errors (0.0-1.0, 1 = no error)
+	Weight     float64            `json:"weight"`      // share of the summary's confidence the score makes up, from the points verified (0.0-1.0)
+	Statements []string           `json:"statements"`  // e.g., "temperature forecasts have 1.2°C MAE at 24h lead"
+	Variables  []ForecastAccuracy `json:"variables"`   // accuracy of each variable verified
+}
+
+// ForecastAccuracy is the accuracy of the forecasts of one variable, overall and by lead time
+type ForecastAccuracy struct {
+	Variable  string             `json:"variable"` // e.g., "temperature", "pressure"
+	Accuracy                     // over every lead time
+	LeadTimes []LeadTimeAccuracy `json:"lead_times,omitempty"` // shortest lead times first
+}
+
+// LeadTimeAccuracy is the accuracy of the forecast points with lead times up to LeadHours and
+// above the previous bucket's
+type LeadTimeAccuracy struct {
+	LeadHours int `json:"lead_hours"`
+	Accuracy
+}
+
+// Accuracy is how far forecast values were from the observed ones
+type Accuracy struct {
+	Count int     `json:"count"` // forecast points verified
+	MAE   float64 `json:"mae"`   // mean absolute error
+	RMSE  float64 `json:"rmse"`  // root mean squared error
+	Bias  float64 `json:"bias"`  // mean error, forecast minus observed (positive = forecasts too high)
 }
 
 // WeatherSummary contains high-level weather information
 type WeatherSummary struct {
-	CurrentTemp     float64  `json:"current_temperature"`
-	MinTemperature  float64  `json:"min_temperature"`
-	MaxTemperature  float64  `json:"max_temperature"`
-	CurrentPressure float64  `json:"current_pressure"`
-	MinPressure     float64  `json:"min_pressure"`
-	MaxPressure     float64  `json:"max_pressure"`
-	TrendNextHours  string   `json:"trend_next_hours"` // e.g., "warming", "cooling"
-	ForecastSummary string   `json:"forecast_summary"` // e.g., "storm_approaching", "clearing", "stable"
-	Confidence      float64  `json:"confidence"`       // Overall confidence score
-	Alerts          []string `json:"alerts,omitempty"` // e.g., "frost_warning", "high_wind", "precipitation_expected"
+	CurrentTemp                float64             `json:"current_temperature"`
+	MinTemperature             float64             `json:"min_temperature"`
+	MaxTemperature             float64             `json:"max_temperature"`
+	CurrentPressure            float64             `json:"current_pressure"`
+	MinPressure                float64             `json:"min_pressure"`
+	MaxPressure                float64             `json:"max_pressure"`
+	CurrentDewPoint            float64             `json:"current_dew_point"`            // Derived from the current reading (see WeatherPoint.Derived)
+	CurrentHeatIndex           float64             `json:"current_heat_index"`           // Derived from the current reading
+	CurrentWindChill           float64             `json:"current_wind_chill"`           // Derived from the current reading
+	CurrentApparentTemperature float64             `json:"current_apparent_temperature"` // Derived from the current reading
+	Wind                       *WindClassification `json:"wind,omitempty"`               // Beaufort force of the current and strongest wind
+	Comfort                    *ComfortAssessment  `json:"comfort,omitempty"`            // How the weather feels, now and in the coming hours
+	PressureTendency           *PressureTendency   `json:"pressure_tendency,omitempty"`  // Over the 3 hours to the current reading
+	TrendNextHours             string              `json:"trend_next_hours"`             // e.g., "cooling, pressure_falling", "steady"
+	ForecastSummary            string              `json:"forecast_summary"`             // "storm_approaching", "deteriorating", "clearing" or "stable"
+	Confidence                 float64             `json:"confidence"`                   // Overall confidence score, from the forecast skill where verified and the number of readings
+	Alerts                     []string            `json:"alerts,omitempty"`             // The location's alerts, then e.g. "storm_risk", "high_wind", "precipitation_expected"
+}
+
+// DataQuality is how complete and current the readings of a location are
+type DataQuality struct {
+	Readings       int          `json:"readings"`
+	CadenceMinutes float64      `json:"cadence_minutes"`      // typical (median) interval between readings
+	Coverage       float64      `json:"coverage"`             // share of the readings expected at the cadence that are present (0.0-1.0)
+	Gaps           []DataGap    `json:"gaps,omitempty"`       // intervals with readings missing, oldest first
+	Duplicates     []time.Time  `json:"duplicates,omitempty"` // timestamps with more than one reading
+	Stuck          []StuckValue `json:"stuck,omitempty"`      // runs of readings with a variable stuck at one value
+	LatestReading  time.Time    `json:"latest_reading"`       // time of the latest reading
+	AgeHours       float64      `json:"age_hours"`            // hours since the latest reading (0 for a reading ahead)
+	Stale          bool         `json:"stale"`                // the latest reading is too old to describe the weather now
+	Warnings       []string     `json:"warnings,omitempty"`   // the problems above, e.g., "2 gaps missing 5 readings (83% coverage)"
+}
+
+// DataGap is an interval between readings longer than the cadence allows
+type DataGap struct {
+	Start   time.Time `json:"start"`   // time of the reading before the gap
+	End     time.Time `json:"end"`     // time of the reading after the gap
+	Missing int       `json:"missing"` // readings missing at the cadence
+}
+
+// StuckValue is a run of readings with the same value of a variable, as from a stuck sensor
+type StuckValue struct {
+	Variable string    `json:"variable"` // e.g., "temperature", "pressure"
+	Value    float64   `json:"value"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Readings int       `json:"readings"`
+}
+
+// Resampling is how the readings were resampled onto a uniform grid for the trend, anomaly,
+// pattern, correlation and spectral analyses
+type Resampling struct {
+	IntervalMinutes float64 `json:"interval_minutes"` // spacing of the grid
+	Original        int     `json:"original"`         // readings before resampling
+	Readings        int     `json:"readings"`         // readings on the grid
+	Interpolated    int     `json:"interpolated"`     // grid readings interpolated between two readings
+	Missing         int     `json:"missing"`          // grid times left out in gaps too long to interpolate across
+}
+
+// ComfortAssessment is how the weather feels from the current reading on
+type ComfortAssessment struct {
+	FeelsLike float64         `json:"feels_like"`        // apparent temperature of the current reading (°C)
+	Humidex   float64         `json:"humidex"`           // of the current reading (°C)
+	WindChill float64         `json:"wind_chill"`        // of the current reading (°C)
+	Level     string          `json:"level"`             // "muggy", "bitter", "hot", "cold" or "comfortable"
+	Periods   []ComfortPeriod `json:"periods,omitempty"` // coming muggy and bitter periods
+	Trend     string          `json:"trend"`             // "improving", "worsening" or "steady"
+	Outlook   string          `json:"outlook"`           // e.g., "becoming more comfortable this evening"
+}
+
+// ComfortPeriod is a run of muggy or bitter readings
+type ComfortPeriod struct {
+	Type  string    `json:"type"`  // "muggy" or "bitter"
+	Start time.Time `json:"start"` // first reading
+	End   time.Time `json:"end"`   // last reading
+	Peak  float64   `json:"peak"`  // highest humidex or lowest wind chill (°C)
+}
+
+// WindClassification places the wind of the readings on the Beaufort scale
+type WindClassification struct {
+	WindSpeed      float64   `json:"wind_speed"`            // current sustained wind (m/s)
+	Beaufort       int       `json:"beaufort"`              // Beaufort force of the current wind (0-12)
+	Description    string    `json:"description"`           // e.g., "fresh breeze", "gale"
+	MaxWindSpeed   float64   `json:"max_wind_speed"`        // strongest sustained wind of the readings (m/s)
+	MaxWindTime    time.Time `json:"max_wind_time"`         // time of the strongest wind
+	MaxBeaufort    int       `json:"max_beaufort"`          // Beaufort force of the strongest wind
+	MaxDescription string    `json:"max_description"`       // e.g., "strong gale"
+	MaxGust        float64   `json:"max_gust,omitempty"`    // strongest gust of the readings (m/s)
+	GustFactor     float64   `json:"gust_factor,omitempty"` // mean ratio of gusts to sustained wind; about 1.5 over land, more in turbulent air
+	HighWind       bool      `json:"high_wind"`             // the wind reaches the high wind thresholds at some reading
+}
+
+// PressureTendency is the change in pressure over 3 hours, as barometers and synoptic reports give it
+type PressureTendency struct {
+	Change         float64 `json:"change"`         // hPa over the 3 hours
+	Characteristic int     `json:"characteristic"` // WMO code (table 0200), 0-8; 0-3 higher than 3 hours ago, 4 the same, 5-8 lower
+	Description    string  `json:"description"`    // e.g., "decreasing, then steady or decreasing more slowly"
 }
 
 // StatisticalData contains statistical analysis results
@@ -96,4 +502,45 @@ type StatisticalData struct {
 	SampleSize      int     `json:"sample_size"`      // number of samples used
 	ConfidenceLevel float64 `json:"confidence_level"` // confidence interval (0.0-1.0)
 	TrendStrength   float64 `json:"trend_strength"`   // strength of trend (0.0-1.0)
+	CiLower         float64 `json:"ci_lower"`         // lower bound of the confidence interval of the mean, at ConfidenceLevel
+	CiUpper         float64 `json:"ci_upper"`         // upper bound of the confidence interval of the mean, at ConfidenceLevel
+	P10             float64 `json:"p10"`              // 10th percentile
+	P25             float64 `json:"p25"`              // 25th percentile (lower quartile)
+	P75             float64 `json:"p75"`              // 75th percentile (upper quartile)
+	P90             float64 `json:"p90"`              // 90th percentile
+	IQR             float64 `json:"iqr"`              // interquartile range, P75 - P25
+	Skewness        float64 `json:"skewness"`         // asymmetry; positive for a long tail of high values
+	Kurtosis        float64 `json:"kurtosis"`         // excess kurtosis; positive for heavier tails than a normal distribution
+
+	// For wind_direction, which wraps at 360°, Mean and StdDev are circular (degrees) and the
+	// fields below replace the median, extremes, percentiles and moments
+	CircularVariance float64   `json:"circular_variance,omitempty"` // 1 - length of the mean direction vector; 0 for a steady direction, near 1 for a variable one
+	WindRose         *WindRose `json:"wind_rose,omitempty"`         // how often the wind blows from each direction
+
+	Rolling []RollingStatistics `json:"rolling,omitempty"` // statistics over a sliding window, one series per window size
+}
+
+// WindRose is the distribution of wind directions over the 16 compass points
+type WindRose struct {
+	Calm    float64          `json:"calm"`    // share of readings with calm air (below 0.3 m/s), without a direction
+	Sectors []WindRoseSector `json:"sectors"` // clockwise from north
+}
+
+// WindRoseSector is a compass point of a wind rose
+type WindRoseSector struct {
+	Direction string  `json:"direction"`  // e.g., "N", "WSW"
+	Degrees   float64 `json:"degrees"`    // center of the 22.5° sector the wind blows from
+	Frequency float64 `json:"frequency"`  // share of all readings (0.0-1.0)
+	MeanSpeed float64 `json:"mean_speed"` // mean wind speed from the sector (m/s)
+}
+
+// RollingStatistics is a series of statistics over a window sliding along the readings, stored
+// column by column. Element i of each column covers the window ending at Timestamps[i].
+type RollingStatistics struct {
+	WindowHours int         `json:"window_hours"` // length of the window
+	Timestamps  []time.Time `json:"timestamps"`   // end of each window, at a reading
+	Mean        []float64   `json:"mean"`         // moving average
+	StdDev      []float64   `json:"std_dev"`      // standard deviation within the window
+	Min         []float64   `json:"min"`
+	Max         []float64   `json:"max"`
 }
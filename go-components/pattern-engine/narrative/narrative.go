@@ -0,0 +1,153 @@
+// Package narrative writes the conclusions of an analysis as a short paragraph for people: how
+// the weather has been changing, what is coming, how it will feel and the alerts, such as
+// "Pressure has fallen 6 hPa in 8 hours with strengthening southerly winds; a storm is likely
+// within 5 hours. Alerts: storm risk, high wind."
+package narrative
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// reportedTrends are the variables the narrative reports the change of
+var reportedTrends = []string{"temperature", "pressure", "humidity"}
+
+// compassWinds name the wind from each of the eight compass points, from the north clockwise
+var compassWinds = []string{"northerly", "north-easterly", "easterly", "south-easterly", "southerly", "south-westerly", "westerly", "north-westerly"}
+
+// Describe returns the narrative of an analysis whose summary has been filled in, still in metric
+// units, with the amounts in the units of system
+func Describe(result *models.AnalysisResult, system units.System) string {
+	var clauses []string
+	if changes := describeTrends(result, system); changes != "" {
+		clauses = append(clauses, changes)
+	}
+	if outlook := describeOutlook(result, system); outlook != "" {
+		clauses = append(clauses, outlook)
+	}
+	if len(clauses) == 0 {
+		clauses = append(clauses, "Conditions are steady")
+	}
+
+	sentences := []string{strings.Join(clauses, "; ")}
+	if comfort := result.WeatherSummary.Comfort; comfort != nil && comfort.Trend != "steady" {
+		sentences = append(sentences, comfort.Outlook)
+	}
+	if alerts := result.WeatherSummary.Alerts; len(alerts) > 0 {
+		words := make([]string, len(alerts))
+		for i, alert := range alerts {
+			words[i] = strings.ReplaceAll(alert, "_", " ")
+		}
+		sentences = append(sentences, "alerts: "+strings.Join(words, ", "))
+	}
+	for i, sentence := range sentences {
+		sentences[i] = strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+	}
+	return strings.Join(sentences, " ")
+}
+
+// describeTrends describes the temperature, pressure and humidity trends with the change over
+// their duration, and a wind speed trend with the prevailing wind
+func describeTrends(result *models.AnalysisResult, system units.System) string {
+	var changes []string
+	var wind string
+	for _, trend := range result.Trends {
+		switch {
+		case slices.Contains(reportedTrends, trend.Variable) && (trend.Trend == "rising" || trend.Trend == "falling" || trend.Trend == "increasing" || trend.Trend == "decreasing"):
+			hours := durationHours(trend.Duration)
+			verb := "risen"
+			if trend.ChangeRate < 0 {
+				verb = "fallen"
+			}
+			change := system.ConvertChange(trend.Variable, math.Abs(trend.ChangeRate*hours))
+			changes = append(changes, fmt.Sprintf("%s has %s %s%s in %s",
+				variableName(trend.Variable), verb, formatQuantity(change, trend.Variable, system), system.Symbol(trend.Variable), formatHours(hours)))
+		case trend.Variable == "wind_speed" && trend.Trend == "increasing":
+			wind = "strengthening"
+		case trend.Variable == "wind_speed" && trend.Trend == "decreasing":
+			wind = "easing"
+		}
+	}
+
+	phrase := strings.Join(changes, " and ")
+	if wind == "" {
+		return phrase
+	}
+	winds := "winds"
+	for _, stat := range result.StatisticalData {
+		if stat.Variable == "wind_direction" {
+			winds = compassWind(stat.Mean) + " winds"
+		}
+	}
+	if phrase == "" {
+		return winds + " are " + wind
+	}
+	return phrase + " with " + wind + " " + winds
+}
+
+// describeOutlook describes a coming storm, else the precipitation expected
+func describeOutlook(result *models.AnalysisResult, system units.System) string {
+	if risk := result.StormRisk; risk != nil && risk.Level != "low" {
+		if risk.LeadTimeHours < 1 {
+			return "stormy conditions are under way"
+		}
+		return fmt.Sprintf("a storm is likely within %s", formatHours(math.Ceil(risk.LeadTimeHours)))
+	}
+	if p := result.Precipitation; p != nil && p.ExpectedTotal >= 1 {
+		return fmt.Sprintf("%s%s of precipitation is expected over the next %s",
+			formatQuantity(system.ConvertPrecipitation(p.ExpectedTotal), "precipitation", system), system.Symbol("precipitation"), formatHours(p.HorizonHours))
+	}
+	return ""
+}
+
+// variableName is the name of a variable in a sentence
+func variableName(variable string) string {
+	if variable == "temperature" {
+		return "the temperature"
+	}
+	return variable
+}
+
+// compassWind names the wind from a direction in degrees, such as "southerly" from 180
+func compassWind(direction float64) string {
+	point := int(math.Round(math.Mod(direction+360, 360)/45)) % len(compassWinds)
+	return compassWinds[point]
+}
+
+// durationHours returns the hours of a trend duration, "8h" or "2d"
+func durationHours(duration string) float64 {
+	if days, ok := strings.CutSuffix(duration, "d"); ok {
+		n, _ := strconv.Atoi(days)
+		return float64(n) * 24
+	}
+	n, _ := strconv.Atoi(strings.TrimSuffix(duration, "h"))
+	return float64(n)
+}
+
+// formatHours formats a number of hours, such as "8 hours" or "1 hour"
+func formatHours(hours float64) string {
+	if hours == 1 {
+		return "1 hour"
+	}
+	return formatAmount(hours) + " hours"
+}
+
+// formatAmount formats an amount with one decimal, dropping a trailing ".0"
+func formatAmount(amount float64) string {
+	return strings.TrimSuffix(strconv.FormatFloat(amount, 'f', 1, 64), ".0")
+}
+
+// formatQuantity formats an amount of a variable like formatAmount, with two decimals in the
+// units too coarse for one: an inch of mercury is 34 hPa and an inch of precipitation 25 mm
+func formatQuantity(amount float64, variable string, system units.System) string {
+	if symbol := system.Symbol(variable); symbol == " "+units.InchesOfMercury || symbol == " "+units.Inches {
+		return strings.TrimSuffix(strings.TrimRight(strconv.FormatFloat(amount, 'f', 2, 64), "0"), ".")
+	}
+	return formatAmount(amount)
+}
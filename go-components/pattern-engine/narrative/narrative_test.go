@@ -0,0 +1,78 @@
+package narrative
+
+import (
+	"testing"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// TestDescribe tests the narratives of a coming storm, a wet day and a quiet one, in metric and
+// imperial units
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name   string
+		result models.AnalysisResult
+		system units.System
+		want   string
+	}{
+		{
+			name: "Storm",
+			result: models.AnalysisResult{
+				Trends: []models.Trend{
+					{Variable: "pressure", Trend: "falling", ChangeRate: -0.75, Duration: "8h"},
+					{Variable: "wind_speed", Trend: "increasing", ChangeRate: 1, Duration: "8h"},
+				},
+				StatisticalData: []models.StatisticalData{{Variable: "wind_direction", Mean: 172}},
+				StormRisk:       &models.StormRisk{Level: "high", LeadTimeHours: 4.2},
+				WeatherSummary:  models.WeatherSummary{Alerts: []string{"storm_risk", "high_wind"}},
+			},
+			want: "Pressure has fallen 6 hPa in 8 hours with strengthening southerly winds; a storm is likely within 5 hours. Alerts: storm risk, high wind.",
+		},
+		{
+			name: "Rain",
+			result: models.AnalysisResult{
+				Trends: []models.Trend{
+					{Variable: "temperature", Trend: "rising", ChangeRate: 0.05, Duration: "2d"},
+					{Variable: "humidity", Trend: "stable", ChangeRate: 0.01, Duration: "2d"},
+				},
+				Precipitation: &models.PrecipitationAnalysis{ExpectedTotal: 12.34, HorizonHours: 24},
+				WeatherSummary: models.WeatherSummary{
+					Comfort: &models.ComfortAssessment{Trend: "improving", Outlook: "becoming more comfortable this evening"},
+				},
+			},
+			want: "The temperature has risen 2.4°C in 48 hours; 12.3 mm of precipitation is expected over the next 24 hours. Becoming more comfortable this evening.",
+		},
+		{
+			name: "Rain in imperial units",
+			result: models.AnalysisResult{
+				Trends: []models.Trend{
+					{Variable: "temperature", Trend: "rising", ChangeRate: 0.05, Duration: "2d"},
+					{Variable: "pressure", Trend: "falling", ChangeRate: -0.75, Duration: "8h"},
+				},
+				Precipitation: &models.PrecipitationAnalysis{ExpectedTotal: 12.34, HorizonHours: 24},
+			},
+			system: units.Imperial(),
+			want:   "The temperature has risen 4.3°F in 48 hours and pressure has fallen 0.18 inHg in 8 hours; 0.49 in of precipitation is expected over the next 24 hours.",
+		},
+		{
+			name: "Quiet",
+			result: models.AnalysisResult{
+				Trends: []models.Trend{{Variable: "wind_speed", Trend: "decreasing", Duration: "6h"}},
+			},
+			want: "Winds are easing.",
+		},
+		{
+			name: "Steady",
+			want: "Conditions are steady.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Describe(&tt.result, tt.system); got != tt.want {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
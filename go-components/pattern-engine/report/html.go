@@ -0,0 +1,326 @@
+// Package report renders analyses as documents for people: a self-contained HTML page per
+// location, for those who just want to open something in a browser, with the summary, the
+// trends, the anomalies and sparklines of the temperature and pressure readings, and Markdown
+// to post in chats and issues or publish on a static site.
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // The zones of the analyses load on hosts without a zoneinfo database
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// Size of a sparkline (px)
+const (
+	sparklineWidth  = 320
+	sparklineHeight = 48
+)
+
+// sparklineVariables are the variables drawn as sparklines, in order
+var sparklineVariables = []string{"temperature", "pressure"}
+
+// page is what the HTML template renders
+type page struct {
+	Location    string
+	Generated   string
+	Timeframe   string
+	Coordinates string
+	Narrative   string
+	Summary     []field
+	Alerts      []string
+	Sparklines  []sparkline
+	Charts      []string
+	Trends      []trendRow
+	Anomalies   []anomalyRow
+}
+
+// field is a labelled value of the summary
+type field struct {
+	Label, Value string
+}
+
+// sparkline is a variable's readings as a polyline, with its anomalies marked
+type sparkline struct {
+	Title          string
+	Width, Height  int
+	Points         string // "x,y x,y ..." in the SVG's coordinates
+	Markers        []point
+	Min, Max, Last string
+	Start, End     string
+}
+
+// point is a point of a sparkline
+type point struct {
+	X, Y string
+}
+
+// trendRow is a row of the trends table
+type trendRow struct {
+	Variable, Trend, Rate, PValue, RSquared, Duration string
+}
+
+// anomalyRow is an item of the anomaly list
+type anomalyRow struct {
+	Time, Variable, Type, Severity, Value, Threshold string
+}
+
+// HTML renders an analysis as a self-contained HTML page: styles and sparklines are inline, so
+// the file can be opened, mailed or archived on its own. The images of charts, given as paths
+// relative to the page (see package chart), are linked below the sparklines; they have to be
+// kept next to the page. Values are in the units of the analysis and times in its location's
+// time zone.
+func HTML(result models.AnalysisResult, charts []string) ([]byte, error) {
+	system := systemOf(result)
+	zone := zoneOf(result)
+	s := result.WeatherSummary
+	p := page{
+		Location:  result.Location,
+		Generated: result.GeneratedAt.In(zone).Format("2006-01-02 15:04 MST"),
+		Timeframe: result.Timeframe,
+		Narrative: result.Narrative,
+		Charts:    charts,
+		Summary: []field{
+			{"Outlook", strings.ReplaceAll(s.ForecastSummary, "_", " ")},
+			{"Temperature", formatQuantity(s.CurrentTemp, "temperature", system)},
+			{"Temperature range", formatQuantity(s.MinTemperature, "temperature", system) + " to " + formatQuantity(s.MaxTemperature, "temperature", system)},
+			{"Pressure", formatQuantity(s.CurrentPressure, "pressure", system)},
+			{"Pressure range", formatQuantity(s.MinPressure, "pressure", system) + " to " + formatQuantity(s.MaxPressure, "pressure", system)},
+			{"Feels like", formatQuantity(s.CurrentApparentTemperature, "temperature", system)},
+			{"Confidence", formatNumber(s.Confidence*100, 0) + "%"},
+		},
+	}
+	for _, alert := range s.Alerts {
+		p.Alerts = append(p.Alerts, strings.ReplaceAll(alert, "_", " "))
+	}
+	if s.TrendNextHours != "" {
+		p.Summary = append(p.Summary, field{"Next hours", strings.ReplaceAll(s.TrendNextHours, "_", " ")})
+	}
+	if c := result.Coordinates; c != (models.Coordinates{}) {
+		p.Coordinates = formatNumber(c.Latitude, 4) + ", " + formatNumber(c.Longitude, 4)
+	}
+	for _, variable := range sparklineVariables {
+		if line, ok := newSparkline(variable, result, system, zone); ok {
+			p.Sparklines = append(p.Sparklines, line)
+		}
+	}
+	for _, t := range result.Trends {
+		p.Trends = append(p.Trends, trendRow{
+			Variable: variableName(t.Variable),
+			Trend:    t.Trend,
+			Rate:     formatRate(t.ChangeRate, t.Variable, system),
+			PValue:   formatNumber(t.PValue, 3),
+			RSquared: formatNumber(t.RSquared, 2),
+			Duration: t.Duration,
+		})
+	}
+	for _, a := range result.Anomalies {
+		p.Anomalies = append(p.Anomalies, anomalyRow{
+			Time:      a.Timestamp.In(zone).Format("2006-01-02 15:04"),
+			Variable:  variableName(a.Variable),
+			Type:      strings.ReplaceAll(a.Type, "_", " "),
+			Severity:  a.Severity,
+			Value:     formatQuantity(a.Value, a.Variable, system),
+			Threshold: formatQuantity(a.Threshold, a.Variable, system),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newSparkline draws the readings of a variable, scaled to fill the sparkline, or reports false
+// when fewer than two readings have it
+func newSparkline(variable string, result models.AnalysisResult, system units.System, zone *time.Location) (sparkline, bool) {
+	var times []time.Time
+	var values []float64
+	for _, r := range result.Readings {
+		if v, ok := readingValue(r, variable); ok {
+			times, values = append(times, r.Timestamp), append(values, v)
+		}
+	}
+	if len(values) < 2 {
+		return sparkline{}, false
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	start, span := times[0], times[len(times)-1].Sub(times[0])
+	// A margin keeps the line and markers inside the box
+	const margin = 3.0
+	x := func(t time.Time) float64 {
+		if span <= 0 {
+			return margin
+		}
+		return margin + (sparklineWidth-2*margin)*float64(t.Sub(start))/float64(span)
+	}
+	y := func(v float64) float64 {
+		if high == low {
+			return sparklineHeight / 2
+		}
+		return margin + (sparklineHeight-2*margin)*(high-v)/(high-low)
+	}
+
+	points := make([]string, len(values))
+	for i := range values {
+		points[i] = formatNumber(x(times[i]), 1) + "," + formatNumber(y(values[i]), 1)
+	}
+	line := sparkline{
+		Title:  variableName(variable),
+		Width:  sparklineWidth,
+		Height: sparklineHeight,
+		Points: strings.Join(points, " "),
+		Min:    formatQuantity(low, variable, system),
+		Max:    formatQuantity(high, variable, system),
+		Last:   formatQuantity(values[len(values)-1], variable, system),
+		Start:  start.In(zone).Format("Jan 2 15:04"),
+		End:    times[len(times)-1].In(zone).Format("Jan 2 15:04"),
+	}
+	for _, a := range result.Anomalies {
+		if a.Variable == variable && !a.Timestamp.Before(start) && !a.Timestamp.After(times[len(times)-1]) {
+			line.Markers = append(line.Markers, point{formatNumber(x(a.Timestamp), 1), formatNumber(y(math.Max(low, math.Min(high, a.Value))), 1)})
+		}
+	}
+	return line, true
+}
+
+// readingValue returns a variable of a reading; a pressure of zero is a missing one
+func readingValue(r models.WeatherPoint, variable string) (float64, bool) {
+	switch variable {
+	case "temperature":
+		return r.Temperature, true
+	case "pressure":
+		return r.Pressure, r.Pressure != 0
+	}
+	return 0, false
+}
+
+// systemOf returns the units an analysis was written in
+func systemOf(result models.AnalysisResult) units.System {
+	if result.Units != nil {
+		return *result.Units
+	}
+	return units.Metric()
+}
+
+// zoneOf returns the time zone of an analysis's location, or UTC when it is unknown
+func zoneOf(result models.AnalysisResult) *time.Location {
+	if result.Timezone != "" {
+		if zone, err := time.LoadLocation(result.Timezone); err == nil {
+			return zone
+		}
+	}
+	return time.UTC
+}
+
+// variableName returns a variable's name as written in prose, e.g. "wind speed"
+func variableName(variable string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(variable, "_mm"), "_", " ")
+}
+
+// formatQuantity formats a value of a variable with its unit, to two decimals in inches and one
+// otherwise
+func formatQuantity(value float64, variable string, system units.System) string {
+	symbol := system.Symbol(variable)
+	return formatNumber(value, decimals(symbol)) + symbol
+}
+
+// formatRate formats a rate of change per hour of a variable with its unit, to a decimal more
+// than its values
+func formatRate(rate float64, variable string, system units.System) string {
+	symbol := system.Symbol(variable)
+	return formatNumber(rate, decimals(symbol)+1) + symbol + "/h"
+}
+
+// decimals returns the decimals values are written to in a unit
+func decimals(symbol string) int {
+	if symbol == " "+units.InchesOfMercury || symbol == " "+units.Inches {
+		return 2
+	}
+	return 1
+}
+
+// formatNumber formats a number to at most decimals decimals, without trailing zeros
+func formatNumber(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Weather report: {{.Location}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.meta { color: #666; margin-top: 0; }
+.narrative { font-size: 1.1em; }
+.alerts li { color: #b00; font-weight: bold; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.3em 0.8em; text-align: left; }
+th { background: #f4f4f4; }
+.summary th { background: none; font-weight: normal; color: #666; }
+.sparklines { display: flex; flex-wrap: wrap; gap: 2em; }
+.sparkline svg { display: block; background: #fafafa; border: 1px solid #eee; }
+.sparkline polyline { fill: none; stroke: #2a6fb0; stroke-width: 1.5; }
+.sparkline circle { fill: #d33; }
+img.chart { max-width: 100%; }
+.sparkline .range { color: #666; font-size: 0.85em; }
+.severity-high { color: #b00; font-weight: bold; }
+.severity-moderate { color: #c60; }
+</style>
+</head>
+<body>
+<h1>{{.Location}}</h1>
+<p class="meta">Analysis of {{.Timeframe}} of readings, generated {{.Generated}}{{with .Coordinates}} &middot; {{.}}{{end}}</p>
+{{with .Narrative}}<p class="narrative">{{.}}</p>{{end}}
+{{with .Alerts}}<h2>Alerts</h2>
+<ul class="alerts">{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<h2>Summary</h2>
+<table class="summary">
+{{range .Summary}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{with .Sparklines}}<div class="sparklines">
+{{range .}}<div class="sparkline">
+<h3>{{.Title}}: {{.Last}}</h3>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="{{.Title}} from {{.Start}} to {{.End}}">
+<polyline points="{{.Points}}"/>
+{{range .Markers}}<circle cx="{{.X}}" cy="{{.Y}}" r="3"/>
+{{end}}</svg>
+<div class="range">{{.Start}} &ndash; {{.End}} &middot; min {{.Min}} &middot; max {{.Max}}</div>
+</div>
+{{end}}</div>{{end}}
+{{with .Charts}}<h2>Charts</h2>
+{{range .}}<p><img class="chart" src="{{.}}" alt="Chart of the readings"></p>
+{{end}}{{end}}
+<h2>Trends</h2>
+{{if .Trends}}<table>
+<tr><th>Variable</th><th>Trend</th><th>Rate of change</th><th>p-value</th><th>R&sup2;</th><th>Duration</th></tr>
+{{range .Trends}}<tr><td>{{.Variable}}</td><td>{{.Trend}}</td><td>{{.Rate}}</td><td>{{.PValue}}</td><td>{{.RSquared}}</td><td>{{.Duration}}</td></tr>
+{{end}}</table>{{else}}<p>No trends.</p>{{end}}
+<h2>Anomalies</h2>
+{{if .Anomalies}}<ul>
+{{range .Anomalies}}<li><span class="severity-{{.Severity}}">{{.Severity}}</span> {{.Type}} of {{.Variable}} at {{.Time}}: {{.Value}} (threshold {{.Threshold}})</li>
+{{end}}</ul>{{else}}<p>No anomalies.</p>{{end}}
+</body>
+</html>
+`))
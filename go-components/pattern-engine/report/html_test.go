@@ -0,0 +1,86 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// testAnalysis returns an analysis of a day of hourly readings with a temperature spike
+func testAnalysis() models.AnalysisResult {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	result := models.AnalysisResult{
+		Location:    "Oslo <Blindern>",
+		Timezone:    "Europe/Oslo",
+		GeneratedAt: start.Add(24 * time.Hour),
+		Timeframe:   "23h",
+		Narrative:   "Pressure has fallen 6 hPa in 8 hours.",
+		Trends:      []models.Trend{{Variable: "pressure", Trend: "falling", ChangeRate: -0.75, PValue: 0.001, RSquared: 0.9, Duration: "8h"}},
+		Anomalies: []models.Anomaly{{Variable: "temperature", Type: "spike", Severity: "high", Value: 25.5, Threshold: 20,
+			Timestamp: start.Add(12 * time.Hour)}},
+		WeatherSummary: models.WeatherSummary{ForecastSummary: "deteriorating", Alerts: []string{"storm_risk"}, Confidence: 0.8},
+	}
+	for i := range 24 {
+		result.Readings = append(result.Readings, models.WeatherPoint{
+			Timestamp:   start.Add(time.Duration(i) * time.Hour),
+			Temperature: 10 + float64(i%6),
+			Pressure:    1010 - float64(i)*0.25,
+		})
+	}
+	return result
+}
+
+// TestHTML tests that a report has the summary, trends, anomalies and a sparkline of each
+// variable, with the anomaly marked, times in the location's zone and names escaped
+func TestHTML(t *testing.T) {
+	data, err := HTML(testAnalysis(), nil)
+	if err != nil {
+		t.Fatalf("HTML failed: %v", err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		"<title>Weather report: Oslo &lt;Blindern&gt;</title>",
+		"Pressure has fallen 6 hPa in 8 hours.",
+		"<li>storm risk</li>",
+		"<td>-0.75 hPa/h</td>",
+		"spike of temperature at 2025-10-03 14:00: 25.5°C (threshold 20°C)",
+		"<h3>temperature: 15°C</h3>",
+		"<h3>pressure: 1004.2 hPa</h3>",
+		`<circle cx="`,
+		"deteriorating",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Report lacks %q", want)
+		}
+	}
+	if n := strings.Count(page, "<polyline"); n != 2 {
+		t.Errorf("Expected 2 sparklines, got %d", n)
+	}
+}
+
+// TestHTMLUnits tests that values are written with the units of the analysis, that a report
+// without readings has no sparklines and that charts are linked
+func TestHTMLUnits(t *testing.T) {
+	result := testAnalysis()
+	imperial := units.Imperial()
+	result.Units = &imperial
+	result.Trends[0].ChangeRate = -0.02214
+	result.Readings = nil
+	data, err := HTML(result, []string{"Denver_CO_temperature_chart_20251003_120000.png"})
+	if err != nil {
+		t.Fatalf("HTML failed: %v", err)
+	}
+	page := string(data)
+	if !strings.Contains(page, "<td>-0.022 inHg/h</td>") {
+		t.Errorf("Expected the rate in inHg, got %s", page)
+	}
+	if strings.Contains(page, "<svg") {
+		t.Error("Expected no sparklines without readings")
+	}
+	if !strings.Contains(page, `<img class="chart" src="Denver_CO_temperature_chart_20251003_120000.png"`) {
+		t.Error("Expected the chart linked")
+	}
+}
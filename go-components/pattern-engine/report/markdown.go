@@ -0,0 +1,107 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"pattern-engine/models"
+)
+
+// compactPatterns is how many patterns a compact Markdown report lists, the most confident first
+const compactPatterns = 3
+
+// Markdown renders an analysis as Markdown, for chat messages, issues or a static site: the
+// summary, alerts, trends, anomalies, patterns and statistics, or with compact only the summary,
+// the alerts and the top patterns. Values are in the units of the analysis and times in its
+// location's time zone.
+func Markdown(result models.AnalysisResult, compact bool) []byte {
+	system := systemOf(result)
+	zone := zoneOf(result)
+	s := result.WeatherSummary
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", escapeMarkdown(result.Location))
+	fmt.Fprintf(&b, "_%s of readings, generated %s_\n\n", escapeMarkdown(result.Timeframe), result.GeneratedAt.In(zone).Format("2006-01-02 15:04 MST"))
+	if result.Narrative != "" {
+		fmt.Fprintf(&b, "%s\n\n", escapeMarkdown(result.Narrative))
+	}
+	fmt.Fprintf(&b, "**Outlook:** %s · **Temperature:** %s (%s to %s) · **Pressure:** %s · **Confidence:** %s%%\n\n",
+		escapeMarkdown(strings.ReplaceAll(s.ForecastSummary, "_", " ")),
+		formatQuantity(s.CurrentTemp, "temperature", system),
+		formatQuantity(s.MinTemperature, "temperature", system), formatQuantity(s.MaxTemperature, "temperature", system),
+		formatQuantity(s.CurrentPressure, "pressure", system), formatNumber(s.Confidence*100, 0))
+
+	if len(s.Alerts) > 0 {
+		b.WriteString("**Alerts:**\n\n")
+		for _, alert := range s.Alerts {
+			fmt.Fprintf(&b, "- %s\n", escapeMarkdown(strings.ReplaceAll(alert, "_", " ")))
+		}
+		b.WriteString("\n")
+	}
+
+	patterns := slices.Clone(result.Patterns)
+	slices.SortStableFunc(patterns, func(a, b models.Pattern) int { return cmp.Compare(b.Confidence, a.Confidence) })
+	if compact {
+		patterns = patterns[:min(len(patterns), compactPatterns)]
+	}
+	if len(patterns) > 0 {
+		b.WriteString("**Patterns:**\n\n")
+		for _, p := range patterns {
+			fmt.Fprintf(&b, "- %s (%s%% confidence)", escapeMarkdown(strings.ReplaceAll(p.Name, "_", " ")), formatNumber(p.Confidence*100, 0))
+			if p.Description != "" {
+				fmt.Fprintf(&b, ": %s", escapeMarkdown(p.Description))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	if compact {
+		return []byte(b.String())
+	}
+
+	if len(result.Trends) > 0 {
+		b.WriteString("### Trends\n\n| Variable | Trend | Rate of change | p-value | R² | Duration |\n|---|---|---|---|---|---|\n")
+		for _, t := range result.Trends {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", escapeMarkdown(variableName(t.Variable)), escapeMarkdown(t.Trend),
+				formatRate(t.ChangeRate, t.Variable, system), formatNumber(t.PValue, 3), formatNumber(t.RSquared, 2), escapeMarkdown(t.Duration))
+		}
+		b.WriteString("\n")
+	}
+	if len(result.Anomalies) > 0 {
+		b.WriteString("### Anomalies\n\n| Time | Variable | Type | Severity | Value | Threshold |\n|---|---|---|---|---|---|\n")
+		for _, a := range result.Anomalies {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", a.Timestamp.In(zone).Format("2006-01-02 15:04"),
+				escapeMarkdown(variableName(a.Variable)), escapeMarkdown(strings.ReplaceAll(a.Type, "_", " ")), escapeMarkdown(a.Severity),
+				formatQuantity(a.Value, a.Variable, system), formatQuantity(a.Threshold, a.Variable, system))
+		}
+		b.WriteString("\n")
+	}
+	if len(result.StatisticalData) > 0 {
+		b.WriteString("### Statistics\n\n| Variable | Mean | Min | Max | Std. dev. | Readings |\n|---|---|---|---|---|---|\n")
+		for _, st := range result.StatisticalData {
+			symbol := system.Symbol(st.Variable)
+			spread := formatNumber(st.StdDev, decimals(symbol)+1) + symbol
+			low, high := formatQuantity(st.Min, st.Variable, system), formatQuantity(st.Max, st.Variable, system)
+			if st.Variable == "wind_direction" { // Circular, without extremes
+				low, high = "", ""
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d |\n", escapeMarkdown(variableName(st.Variable)),
+				formatQuantity(st.Mean, st.Variable, system), low, high, spread, st.SampleSize)
+		}
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// markdownEscaper escapes the characters Markdown would take as formatting, and the pipes that
+// would split a table cell
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `|`, `\|`, `<`, `\<`, `>`, `\>`, `#`, `\#`,
+)
+
+// escapeMarkdown returns text to be shown as is in Markdown
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
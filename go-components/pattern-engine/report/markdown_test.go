@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"pattern-engine/models"
+)
+
+// TestMarkdown tests that a full report has the summary, alerts, patterns and tables, with the
+// location's name escaped
+func TestMarkdown(t *testing.T) {
+	result := testAnalysis()
+	result.Location = "Oslo_Blindern | NO"
+	result.Patterns = []models.Pattern{
+		{Name: "front_passage", Confidence: 0.6, Description: "Cold front passed"},
+		{Name: "low_pressure_system", Confidence: 0.9},
+	}
+	result.StatisticalData = []models.StatisticalData{{Variable: "temperature", Mean: 12.5, Min: 10, Max: 15, StdDev: 1.71, SampleSize: 24}}
+	report := string(Markdown(result, false))
+	for _, want := range []string{
+		`## Oslo\_Blindern \| NO`,
+		"Pressure has fallen 6 hPa in 8 hours.",
+		"**Outlook:** deteriorating",
+		"- storm risk\n",
+		"- low pressure system (90% confidence)\n- front passage (60% confidence): Cold front passed\n",
+		"| pressure | falling | -0.75 hPa/h | 0.001 | 0.9 | 8h |",
+		"| 2025-10-03 14:00 | temperature | spike | high | 25.5°C | 20°C |",
+		"| temperature | 12.5°C | 10°C | 15°C | 1.71°C | 24 |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report lacks %q:\n%s", want, report)
+		}
+	}
+}
+
+// TestMarkdownCompact tests that a compact report has only the summary, alerts and the most
+// confident patterns
+func TestMarkdownCompact(t *testing.T) {
+	result := testAnalysis()
+	for i := range 5 {
+		result.Patterns = append(result.Patterns, models.Pattern{Name: fmt.Sprintf("pattern%d", i), Confidence: float64(i) / 10})
+	}
+	report := string(Markdown(result, true))
+	for _, want := range []string{"**Outlook:**", "- storm risk", "- pattern4", "- pattern3", "- pattern2"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Compact report lacks %q:\n%s", want, report)
+		}
+	}
+	for _, unwanted := range []string{"pattern1", "### Trends", "### Anomalies"} {
+		if strings.Contains(report, unwanted) {
+			t.Errorf("Compact report has %q:\n%s", unwanted, report)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package rules evaluates the alert rules of the config file against a location's readings. A
+// rule's condition compares reading variables to numbers and combines the comparisons with AND,
+// OR, NOT and parentheses, such as "temperature < 0 AND precipitation_probability > 60"; AND
+// binds tighter than OR. Each rule matching any reading raises one alert with the readings it
+// matched.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pattern-engine/models"
+)
+
+// Rule severities
+const (
+	SeverityLow      = "low"
+	SeverityModerate = "moderate"
+	SeverityHigh     = "high"
+)
+
+// Severities are the severities a rule can have
+var Severities = []string{SeverityLow, SeverityModerate, SeverityHigh}
+
+// variables are the reading variables conditions can compare, by name
+var variables = map[string]func(models.WeatherPoint) float64{
+	"temperature":               func(r models.WeatherPoint) float64 { return r.Temperature },
+	"pressure":                  func(r models.WeatherPoint) float64 { return r.Pressure },
+	"humidity":                  func(r models.WeatherPoint) float64 { return r.Humidity },
+	"wind_speed":                func(r models.WeatherPoint) float64 { return r.WindSpeed },
+	"wind_gust":                 func(r models.WeatherPoint) float64 { return r.WindGust },
+	"wind_direction":            func(r models.WeatherPoint) float64 { return r.WindDirection },
+	"cloud_cover":               func(r models.WeatherPoint) float64 { return r.CloudCover },
+	"precipitation_mm":          func(r models.WeatherPoint) float64 { return r.PrecipitationMm },
+	"precipitation_probability": func(r models.WeatherPoint) float64 { return r.PrecipitationProbability },
+	"uv_index":                  func(r models.WeatherPoint) float64 { return r.UVIndex },
+	"fog_area_fraction":         func(r models.WeatherPoint) float64 { return r.FogAreaFraction },
+	"dew_point":                 func(r models.WeatherPoint) float64 { return r.Derived.DewPoint },
+	"heat_index":                func(r models.WeatherPoint) float64 { return r.Derived.HeatIndex },
+	"wind_chill":                func(r models.WeatherPoint) float64 { return r.Derived.WindChill },
+	"apparent_temperature":      func(r models.WeatherPoint) float64 { return r.Derived.ApparentTemperature },
+}
+
+// comparisons are the comparison operators of conditions
+var comparisons = map[string]func(a, b float64) bool{
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"==": func(a, b float64) bool { return a == b },
+	"=":  func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// Rule is an alert rule of the "rules" key of the "analysis" config section
+type Rule struct {
+	Name      string `json:"name"`      // Alert name, e.g. "freezing_rain"
+	Condition string `json:"condition"` // e.g. "temperature < 0 AND precipitation_probability > 60"
+	Severity  string `json:"severity"`  // SeverityLow, SeverityModerate or SeverityHigh
+	Message   string `json:"message"`   // Optional description for people
+
+	match func(models.WeatherPoint) bool // The compiled condition, set by Compile
+}
+
+// Compile parses the rule's condition, which must be done before Match
+func (r *Rule) Compile() error {
+	p := &parser{}
+	var err error
+	if p.tokens, err = tokenize(r.Condition); err != nil {
+		return err
+	}
+	if len(p.tokens) == 0 {
+		return errors.New("condition is empty")
+	}
+	if r.match, err = p.parseOr(); err != nil {
+		return err
+	}
+	if p.pos < len(p.tokens) {
+		return fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return nil
+}
+
+// Match reports whether a reading meets the rule's condition. An uncompiled rule matches nothing.
+func (r *Rule) Match(reading models.WeatherPoint) bool {
+	return r.match != nil && r.match(reading)
+}
+
+// Evaluate raises an alert for each rule matching any of the readings
+func Evaluate(rules []Rule, readings []models.WeatherPoint) []models.RuleAlert {
+	var alerts []models.RuleAlert
+	for i := range rules {
+		rule := &rules[i]
+		var alert *models.RuleAlert
+		for _, r := range readings {
+			if !rule.Match(r) {
+				continue
+			}
+			if alert == nil {
+				alerts = append(alerts, models.RuleAlert{
+					Rule:      rule.Name,
+					Severity:  rule.Severity,
+					Message:   rule.Message,
+					Condition: rule.Condition,
+					Start:     r.Timestamp,
+					End:       r.Timestamp,
+				})
+				alert = &alerts[len(alerts)-1]
+			}
+			alert.Readings = append(alert.Readings, r)
+			if r.Timestamp.Before(alert.Start) {
+				alert.Start = r.Timestamp
+			}
+			if r.Timestamp.After(alert.End) {
+				alert.End = r.Timestamp
+			}
+		}
+	}
+	return alerts
+}
+
+// tokenize splits a condition into parentheses, operators and words (names, numbers and AND, OR
+// and NOT)
+func tokenize(condition string) ([]string, error) {
+	const operators = "<>=!&|"
+	var tokens []string
+	for i := 0; i < len(condition); {
+		c := condition[i]
+		j := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '(' || c == ')':
+		case strings.IndexByte(operators, c) >= 0:
+			for j < len(condition) && strings.IndexByte(operators, condition[j]) >= 0 {
+				j++
+			}
+		case isWordByte(c):
+			for j < len(condition) && isWordByte(condition[j]) {
+				j++
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i+1)
+		}
+		tokens = append(tokens, condition[i:j])
+		i = j
+	}
+	return tokens, nil
+}
+
+// isWordByte reports whether c can be part of a name or number
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-' || c == '+'
+}
+
+// parser parses the tokens of a condition by recursive descent
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the next token and moves past it, or "" at the end
+func (p *parser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+// accept moves past the next token if it is one of words, matched case-insensitively
+func (p *parser) accept(words ...string) bool {
+	if p.pos < len(p.tokens) {
+		for _, word := range words {
+			if strings.EqualFold(p.tokens[p.pos], word) {
+				p.pos++
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseOr parses conditions joined by OR
+func (p *parser) parseOr() (func(models.WeatherPoint) bool, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("OR", "||") {
+		var right func(models.WeatherPoint) bool
+		if right, err = p.parseAnd(); err == nil {
+			l := left
+			left = func(r models.WeatherPoint) bool { return l(r) || right(r) }
+		}
+	}
+	return left, err
+}
+
+// parseAnd parses conditions joined by AND
+func (p *parser) parseAnd() (func(models.WeatherPoint) bool, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.accept("AND", "&&") {
+		var right func(models.WeatherPoint) bool
+		if right, err = p.parseUnary(); err == nil {
+			l := left
+			left = func(r models.WeatherPoint) bool { return l(r) && right(r) }
+		}
+	}
+	return left, err
+}
+
+// parseUnary parses a negated or parenthesized condition, or a comparison
+func (p *parser) parseUnary() (func(models.WeatherPoint) bool, error) {
+	if p.accept("NOT", "!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(r models.WeatherPoint) bool { return !inner(r) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, errors.New("missing )")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses a variable compared to a number, such as "wind_gust >= 20"
+func (p *parser) parseComparison() (func(models.WeatherPoint) bool, error) {
+	name := p.next()
+	value, ok := variables[strings.ToLower(name)]
+	if !ok {
+		if name == "" {
+			return nil, errors.New("condition ends early")
+		}
+		return nil, fmt.Errorf("unknown variable %q", name)
+	}
+	operator := p.next()
+	compare, ok := comparisons[operator]
+	if !ok {
+		return nil, fmt.Errorf("expected a comparison after %s, got %q", name, operator)
+	}
+	number := p.next()
+	limit, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number after %s %s, got %q", name, operator, number)
+	}
+	return func(r models.WeatherPoint) bool { return compare(value(r), limit) }, nil
+}
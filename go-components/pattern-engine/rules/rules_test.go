@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestMatch tests conditions combining comparisons with AND, OR, NOT and parentheses
+func TestMatch(t *testing.T) {
+	reading := models.WeatherPoint{
+		Temperature:              -1.5,
+		PrecipitationProbability: 80,
+		WindSpeed:                12,
+		Derived:                  models.Derived{WindChill: -9},
+	}
+	for _, tt := range []struct {
+		condition string
+		want      bool
+	}{
+		{"temperature < 0 AND precipitation_probability > 60", true},
+		{"temperature<0 and precipitation_probability>90", false},
+		{"temperature > 0 OR wind_speed >= 12", true},
+		{"temperature > 0 OR wind_speed > 12 AND precipitation_probability > 60", false},
+		{"(temperature > 0 OR wind_speed > 10) AND precipitation_probability > 60", true},
+		{"NOT temperature >= -1.5", false},
+		{"!(wind_chill < -10) && temperature != 0", true},
+		{"temperature == -1.5 || humidity = 50", true},
+	} {
+		rule := Rule{Condition: tt.condition}
+		if err := rule.Compile(); err != nil {
+			t.Errorf("Compile(%q) failed: %v", tt.condition, err)
+			continue
+		}
+		if got := rule.Match(reading); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+}
+
+// TestCompileErrors tests that malformed conditions are rejected
+func TestCompileErrors(t *testing.T) {
+	for _, condition := range []string{
+		"",
+		"visibility < 100",
+		"temperature",
+		"temperature <",
+		"temperature < cold",
+		"temperature ~ 0",
+		"temperature < 0 AND",
+		"(temperature < 0",
+		"temperature < 0)",
+		"temperature < 0 humidity > 90",
+	} {
+		rule := Rule{Condition: condition}
+		if err := rule.Compile(); err == nil {
+			t.Errorf("Expected an error compiling %q", condition)
+		}
+	}
+}
+
+// TestEvaluate tests that each matching rule raises one alert with its readings
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	var readings []models.WeatherPoint
+	for i, temperature := range []float64{2, -1, 1, -3, -2} {
+		readings = append(readings, models.WeatherPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Temperature: temperature})
+	}
+	rules := []Rule{
+		{Name: "frost", Condition: "temperature < 0", Severity: SeverityModerate, Message: "Frost on the ground"},
+		{Name: "heat", Condition: "temperature > 30", Severity: SeverityHigh},
+	}
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alerts := Evaluate(rules, readings)
+	if len(alerts) != 1 {
+		t.Fatalf("Expected only the frost alert, got %+v", alerts)
+	}
+	frost := alerts[0]
+	if frost.Rule != "frost" || frost.Severity != SeverityModerate || frost.Message != "Frost on the ground" || len(frost.Readings) != 3 ||
+		!frost.Start.Equal(start.Add(time.Hour)) || !frost.End.Equal(start.Add(4*time.Hour)) {
+		t.Errorf("Expected frost from 01:00 to 04:00 in 3 readings, got %+v", frost)
+	}
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pattern-engine/alerts"
+	"pattern-engine/models"
+	"weathermodels/fileio"
+)
+
+// Event types of the GET /events stream
+const (
+	EventAnalysis = "analysis" // data: AnalysisResult
+	EventAlert    = "alert"    // data: alerts.Transition
+)
+
+// heartbeatInterval is how often an idle event stream gets a comment, so proxies keep it open and
+// clients notice a dead connection
+const heartbeatInterval = 15 * time.Second
+
+// subscriberBuffer is how many events a client can fall behind by before it is disconnected
+const subscriberBuffer = 64
+
+// event is a message of the event stream
+type event struct {
+	name     string
+	location string
+	data     []byte // JSON on a single line
+}
+
+// subscriber is a client of the event stream, receiving the events of location or of every
+// location when it is empty
+type subscriber struct {
+	location string
+	events   chan event
+}
+
+// feed fans the analyses the server sees out to the clients of the event stream, along with the
+// alerts they raise and clear. An analysis is sent only if it is newer than the last one sent of
+// its location, so one both returned by POST /analyze and found in the analysis directory is sent
+// once.
+type feed struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]bool
+	closed      bool
+	latest      map[string]time.Time // Time the last analysis sent of each location was generated
+	files       map[string]time.Time // Modification time of each analysis file read
+	alerts      *alerts.Tracker
+}
+
+// newFeed creates a feed tracking alerts with the cooldowns of cfg
+func newFeed(cfg alerts.Config) *feed {
+	return &feed{
+		subscribers: make(map[*subscriber]bool),
+		latest:      make(map[string]time.Time),
+		files:       make(map[string]time.Time),
+		alerts:      alerts.NewTracker(cfg),
+	}
+}
+
+// subscribe adds a client receiving the events of location, or of every location when it is
+// empty. It returns nil once the feed is closed.
+func (f *feed) subscribe(location string) *subscriber {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	sub := &subscriber{location: location, events: make(chan event, subscriberBuffer)}
+	f.subscribers[sub] = true
+	return sub
+}
+
+// unsubscribe removes a client, if it was not already dropped
+func (f *feed) unsubscribe(sub *subscriber) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.subscribers[sub] {
+		delete(f.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// close ends the stream of every client, for the server to shut down
+func (f *feed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for sub := range f.subscribers {
+		delete(f.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publish sends an analysis and the alerts it raises and clears to the clients, unless an
+// analysis of the location as new has already been sent
+func (f *feed) publish(result models.AnalysisResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.advance(result) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		slog.Warn("Failed to encode analysis event", "location", result.Location, "error", err)
+		return
+	}
+	f.send(event{name: EventAnalysis, location: result.Location, data: data})
+	for _, transition := range f.alerts.Update([]models.AnalysisResult{result}, time.Now()) {
+		data, err := json.Marshal(transition)
+		if err != nil {
+			slog.Warn("Failed to encode alert event", "location", transition.Location, "error", err)
+			continue
+		}
+		f.send(event{name: EventAlert, location: transition.Location, data: data})
+	}
+}
+
+// advance records result as the latest analysis of its location, reporting whether it is newer
+// than the latest one so far. f.mu must be held.
+func (f *feed) advance(result models.AnalysisResult) bool {
+	if latest, ok := f.latest[result.Location]; ok && !result.GeneratedAt.After(latest) {
+		return false
+	}
+	f.latest[result.Location] = result.GeneratedAt
+	return true
+}
+
+// send queues an event for the clients it is for. A client whose queue is full is disconnected
+// rather than holding up the others; it can reconnect and read the latest analyses. f.mu must be
+// held.
+func (f *feed) send(e event) {
+	for sub := range f.subscribers {
+		if sub.location != "" && !strings.EqualFold(sub.location, e.location) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			slog.Warn("Dropping event stream client that fell behind", "location", sub.location)
+			delete(f.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// scan reads the analysis files in dir that are new or changed since the last scan and publishes
+// their analyses, oldest first. With publish false it only records them as seen, along with their
+// alerts, so that the stream starts from what is already there.
+func (f *feed) scan(dir string, publish bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var found []models.AnalysisResult
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json"+fileio.GzipExt)) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed since it was listed
+		}
+		f.mu.Lock()
+		modified, seen := f.files[name]
+		f.mu.Unlock()
+		if seen && modified.Equal(info.ModTime()) {
+			continue
+		}
+
+		data, err := fileio.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			slog.Warn("Failed to read analysis file", "path", filepath.Join(dir, name), "error", err)
+			continue
+		}
+		var result models.AnalysisResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			slog.Warn("Skipping invalid analysis file", "path", filepath.Join(dir, name), "error", err)
+		} else if result.Location != "" { // Not a location's analysis otherwise, such as a regional one
+			found = append(found, result)
+		}
+		f.mu.Lock()
+		f.files[name] = info.ModTime()
+		f.mu.Unlock()
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].GeneratedAt.Before(found[j].GeneratedAt) })
+	for _, result := range found {
+		if publish {
+			f.publish(result)
+			continue
+		}
+		f.mu.Lock()
+		if f.advance(result) {
+			f.alerts.Update([]models.AnalysisResult{result}, time.Now())
+		}
+		f.mu.Unlock()
+	}
+	return nil
+}
+
+// Watch streams the analyses saved to the analysis directory by other processes, such as analyze
+// runs after each collection of the collector's daemon mode, checking for new files every
+// interval until ctx is cancelled. The analyses already there when it starts are not streamed.
+func (s *Server) Watch(ctx context.Context, interval time.Duration) error {
+	if s.analysisDir == "" {
+		return fmt.Errorf("no analysis directory to watch")
+	}
+	if err := s.feed.scan(s.analysisDir, false); err != nil {
+		return fmt.Errorf("reading analysis directory: %w", err)
+	}
+	slog.Info("Watching for new analyses", "directory", s.analysisDir, "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.feed.scan(s.analysisDir, true); err != nil {
+				slog.Warn("Failed to read analysis directory", "directory", s.analysisDir, "error", err)
+			}
+		}
+	}
+}
+
+// handleEvents streams analyses and alert transitions as Server-Sent Events until the client
+// disconnects or the server shuts down. ?location= limits the stream to one location, matched
+// ignoring case.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	controller := http.NewResponseController(w)
+	sub := s.feed.subscribe(r.URL.Query().Get("location"))
+	if sub == nil {
+		writeError(w, http.StatusServiceUnavailable, "server is shutting down")
+		return
+	}
+	defer s.feed.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep reverse proxies such as nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	// Clients reconnect this long after the stream ends, such as when they fall behind
+	if _, err := fmt.Fprint(w, "retry: 5000\n\n"); err != nil || controller.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.events:
+			if !ok {
+				return
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+		}
+		if err == nil {
+			err = controller.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
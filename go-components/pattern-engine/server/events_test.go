@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/alerts"
+	"pattern-engine/models"
+)
+
+// readEvent reads the next event of a stream, skipping comments and the retry field
+func readEvent(t *testing.T, r *bufio.Reader) (name, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Stream ended before an event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && name != "":
+			return name, data
+		}
+	}
+}
+
+// collect subscribes to the feed and returns a function draining the events received since
+func collect(f *feed, location string) func() []event {
+	sub := f.subscribe(location)
+	return func() []event {
+		var events []event
+		for {
+			select {
+			case e := <-sub.events:
+				events = append(events, e)
+			default:
+				return events
+			}
+		}
+	}
+}
+
+// writeAnalysis writes an analysis file as the analyze command would
+func writeAnalysis(t *testing.T, dir, name string, result models.AnalysisResult) {
+	t.Helper()
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestEventsStream tests that GET /events streams the analyses of POST /analyze
+func TestEventsStream(t *testing.T) {
+	s := newTestServer(t)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/events?location=oslo", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	for _, location := range []string{"Bergen", "Oslo"} {
+		if rec := serve(s, "POST", "/analyze", locationBody(location, 6)); rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	name, data := readEvent(t, bufio.NewReader(resp.Body))
+	var result models.AnalysisResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil || name != EventAnalysis {
+		t.Fatalf("Expected an analysis event, got %s %q", name, data)
+	}
+	if result.Location != "Oslo" {
+		t.Errorf("Expected only the analysis of Oslo, got that of %s", result.Location)
+	}
+}
+
+// TestFeedScan tests that analyses saved to the analysis directory after the first scan are
+// published once each, with the alerts they raise and clear
+func TestFeedScan(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	stormy := models.AnalysisResult{Location: "Oslo", GeneratedAt: start, WeatherSummary: models.WeatherSummary{Alerts: []string{"storm_risk"}}}
+	writeAnalysis(t, dir, "oslo_analysis_20251003_120000.json", stormy)
+
+	f := newFeed(alerts.DefaultConfig())
+	drain := collect(f, "")
+	if err := f.scan(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if events := drain(); len(events) != 0 {
+		t.Fatalf("Expected the analyses already saved not to be published, got %d events", len(events))
+	}
+
+	calm := models.AnalysisResult{Location: "Oslo", GeneratedAt: start.Add(time.Hour)}
+	writeAnalysis(t, dir, "oslo_analysis_20251003_130000.json", calm)
+	writeAnalysis(t, dir, "regional_analysis_20251003_130000.json", models.AnalysisResult{})
+	if err := f.scan(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	events := drain()
+	if len(events) != 2 || events[0].name != EventAnalysis || events[1].name != EventAlert {
+		t.Fatalf("Expected the new analysis and the alert it cleared, got %v", events)
+	}
+	var transition alerts.Transition
+	if err := json.Unmarshal(events[1].data, &transition); err != nil || transition.Alert != "storm_risk" || transition.State != alerts.StateCleared {
+		t.Errorf("Expected storm_risk cleared, got %s", events[1].data)
+	}
+
+	if err := f.scan(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	f.publish(calm) // As returned by POST /analyze and then found in the directory
+	if events := drain(); len(events) != 0 {
+		t.Errorf("Expected an analysis to be published once, got %v", events)
+	}
+}
+
+// TestFeedClose tests that closing the feed ends the streams and refuses new clients
+func TestFeedClose(t *testing.T) {
+	f := newFeed(alerts.DefaultConfig())
+	sub := f.subscribe("")
+	f.close()
+	if _, ok := <-sub.events; ok {
+		t.Error("Expected the stream to end")
+	}
+	f.unsubscribe(sub) // As the handler does on returning
+	if f.subscribe("") != nil {
+		t.Error("Expected no new clients once closed")
+	}
+}
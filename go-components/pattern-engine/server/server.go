@@ -0,0 +1,185 @@
+// Package server exposes the pattern engine over a small REST API, so dashboards and the Python
+// layer can have readings analyzed and read the latest analyses without knowing the layout of
+// the analysis directory.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"pattern-engine/alerts"
+	"pattern-engine/engine"
+)
+
+// maxRequestBytes bounds the size of a POST /analyze body
+const maxRequestBytes = 32 << 20
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// Server exposes analysis over a small REST API:
+//
+//	POST /analyze                    body: LocationData (a time-series file) -> AnalysisResult
+//	GET  /analysis/{location}/latest -> AnalysisResult
+//	GET  /events                     -> Server-Sent Events: "analysis" and "alert"
+//
+// Responses use the same JSON and units as the analysis files. Analyses of POST /analyze are
+// saved to the analysis directory, where GET /analysis/{location}/latest finds them along with
+// those of the analyze command. GET /events streams each new analysis, of POST /analyze or found
+// by Watch, and the alerts it raises and clears (see alerts.Transition).
+type Server struct {
+	engine      *engine.Engine
+	analysisDir string
+	mu          sync.Mutex // Analyses run one at a time, bounding the memory and CPU a burst of requests takes
+	feed        *feed
+}
+
+// New creates a server analyzing with e, whose output directory should be analysisDir, and
+// reading the latest analyses from analysisDir. The alerts of the event stream are raised again
+// only after the cooldowns of alertsCfg.
+func New(e *engine.Engine, analysisDir string, alertsCfg alerts.Config) *Server {
+	return &Server{engine: e, analysisDir: analysisDir, feed: newFeed(alertsCfg)}
+}
+
+// Handler returns the HTTP routes for the server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /analyze", s.handleAnalyze)
+	mux.HandleFunc("GET /analysis/{location}/latest", s.handleLatest)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /health", s.handleHealth)
+	return mux
+}
+
+// ListenAndServe serves on addr until ctx is cancelled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Shutdown waits for requests to finish, which event streams only do once ended
+	httpServer.RegisterOnShutdown(s.feed.close)
+
+	errs := make(chan error, 1)
+	go func() {
+		slog.Info("Serving analysis API", "address", addr)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleAnalyze analyzes the location in the request body and saves the analysis. Entries of the
+// body that cannot be parsed are dropped, or with ?strict=true reject the request.
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	strict, _ := strconv.ParseBool(r.URL.Query().Get("strict"))
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	locationData, entryErrs, err := engine.DecodeLocationData(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid location data: %v", err))
+		return
+	}
+	if locationData.Name == "" {
+		writeError(w, http.StatusBadRequest, "location is required")
+		return
+	}
+	if len(entryErrs) > 0 {
+		if strict {
+			writeError(w, http.StatusBadRequest, errors.Join(entryErrs...).Error())
+			return
+		}
+		slog.Warn("Dropped entries that could not be parsed", "location", locationData.Name, "entries", len(entryErrs))
+	}
+
+	s.mu.Lock()
+	result, err := s.engine.Analyze(&locationData)
+	s.mu.Unlock()
+	if errors.Is(err, engine.ErrInsufficientData) {
+		writeError(w, http.StatusUnprocessableEntity, "at least 2 readings are needed")
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to analyze", "location", locationData.Name, "error", err)
+		writeError(w, http.StatusInternalServerError, "analysis failed")
+		return
+	}
+	if s.analysisDir != "" {
+		if _, err := s.engine.Save(result); err != nil {
+			slog.Error("Failed to save analysis", "location", result.Location, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to save analysis")
+			return
+		}
+	}
+	slog.Info("Analyzed", "location", result.Location, "readings", len(result.Readings))
+	s.feed.publish(result)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleLatest returns the newest saved analysis of a location, whose name is matched ignoring case
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	location := r.PathValue("location")
+	if s.analysisDir == "" {
+		writeError(w, http.StatusNotFound, "analyses are not saved")
+		return
+	}
+	latest, err := engine.LoadLatestAnalysis(s.analysisDir, location)
+	if errors.Is(err, engine.ErrNoAnalysis) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no analysis of %q", location))
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to read analyses", "directory", s.analysisDir, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to read analyses")
+		return
+	}
+	writeJSON(w, http.StatusOK, latest)
+}
+
+// handleHealth reports that the server is up
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		slog.Warn("Failed to write response", "error", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
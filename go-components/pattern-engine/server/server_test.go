@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pattern-engine/alerts"
+	"pattern-engine/engine"
+	"pattern-engine/models"
+)
+
+// newTestServer creates a server saving analyses to a temporary directory
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	dir := t.TempDir()
+	e, err := engine.New(engine.Options{OutputDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(e, dir, alerts.DefaultConfig())
+}
+
+// locationBody returns a time-series file of a location with hourly readings
+func locationBody(location string, readings int) string {
+	start := time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)
+	entries := make([]string, readings)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"timestamp": %q, "temperature": %d, "pressure": %d, "humidity": 70}`,
+			start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339), 10+i, 1015-i)
+	}
+	return fmt.Sprintf(`{"location": %q, "coordinates": {"lat": 59.91, "lon": 10.75}, "readings": [%s]}`, location, strings.Join(entries, ","))
+}
+
+// serve sends a request to the server's handler
+func serve(s *Server, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+// TestAnalyzeEndpoint tests that POST /analyze returns the analysis and saves it for
+// GET /analysis/{location}/latest
+func TestAnalyzeEndpoint(t *testing.T) {
+	s := newTestServer(t)
+	rec := serve(s, "POST", "/analyze", locationBody("Oslo, Norway", 6))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result models.AnalysisResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Response is not an analysis: %v", err)
+	}
+	if result.Location != "Oslo, Norway" || result.Timeframe != "5h" || len(result.Trends) == 0 {
+		t.Errorf("Unexpected analysis %+v", result)
+	}
+
+	rec = serve(s, "GET", "/analysis/oslo,%20norway/latest", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var latest models.AnalysisResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &latest); err != nil || !latest.GeneratedAt.Equal(result.GeneratedAt) {
+		t.Errorf("Expected the saved analysis, got %+v (err: %v)", latest, err)
+	}
+}
+
+// TestAnalyzeEndpointRejectsBadInput tests the status of requests that cannot be analyzed
+func TestAnalyzeEndpointRejectsBadInput(t *testing.T) {
+	s := newTestServer(t)
+	bad := strings.Replace(locationBody("Oslo", 3), `"temperature": 10`, `"temperature": "warm"`, 1)
+	for _, tc := range []struct {
+		target, body string
+		status       int
+	}{
+		{"/analyze", "not json", http.StatusBadRequest},
+		{"/analyze", `{"readings": []}`, http.StatusBadRequest},
+		{"/analyze", locationBody("Oslo", 1), http.StatusUnprocessableEntity},
+		{"/analyze?strict=true", bad, http.StatusBadRequest},
+		{"/analyze", bad, http.StatusOK},
+	} {
+		if rec := serve(s, "POST", tc.target, tc.body); rec.Code != tc.status {
+			t.Errorf("%s %.40q: expected %d, got %d: %s", tc.target, tc.body, tc.status, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestLatestEndpointNotFound tests that a location without an analysis is not found
+func TestLatestEndpointNotFound(t *testing.T) {
+	if rec := serve(newTestServer(t), "GET", "/analysis/Bergen/latest", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
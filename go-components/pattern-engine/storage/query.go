@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Tables that can be queried
+const (
+	TableAnalyses   = "analyses"
+	TableTrends     = "trends"
+	TableAnomalies  = "anomalies"
+	TablePatterns   = "patterns"
+	TableStatistics = "statistics"
+)
+
+// Query selects rows of one table, newest first
+type Query struct {
+	Table    string    // TableAnalyses, TableTrends, TableAnomalies, TablePatterns or TableStatistics
+	Location string    // Locations whose name starts with it, ignoring case ("" = all)
+	Since    time.Time // Rows from this time on: of the anomaly or pattern, else of the run (zero = all)
+	Severity string    // Anomalies of this severity, e.g. "high" ("" = all)
+	Variable string    // Trends, anomalies or statistics of this variable ("" = all)
+	Limit    int       // Most rows returned (0 = all)
+}
+
+// tableQuery is how a table is queried
+type tableQuery struct {
+	columns   []string
+	timeCol   string // Column Query.Since applies to
+	orderBy   string
+	groupBy   string // Columns identifying a row found again by later runs, reported once from the latest ("" = none)
+	variables bool   // The table has a variable column
+}
+
+var tableQueries = map[string]tableQuery{
+	TableAnalyses: {
+		columns: []string{"location", "generated_at", "analysis_type", "timeframe", "forecast_summary", "confidence", "alerts", "narrative"},
+		timeCol: "generated_at",
+		orderBy: "generated_at DESC, location",
+	},
+	TableTrends: {
+		columns:   []string{"location", "generated_at", "variable", "trend", "rate_of_change", "unit", "p_value", "r_squared", "duration"},
+		timeCol:   "generated_at",
+		orderBy:   "generated_at DESC, location, variable",
+		variables: true,
+	},
+	// The readings of successive runs overlap, so the same anomaly or pattern is usually found by several
+	TableAnomalies: {
+		columns:   []string{"location", "generated_at", "timestamp", "variable", "type", "severity", "value", "threshold", "unit"},
+		timeCol:   "timestamp",
+		orderBy:   "timestamp DESC, location, variable",
+		groupBy:   "location, timestamp, variable, type",
+		variables: true,
+	},
+	TablePatterns: {
+		columns: []string{"location", "generated_at", "name", "confidence", "strength", "variables", "timestamp", "until", "description"},
+		timeCol: "COALESCE(timestamp, generated_at)",
+		orderBy: "COALESCE(timestamp, generated_at) DESC, location, name",
+		groupBy: "location, name, timestamp",
+	},
+	TableStatistics: {
+		columns:   []string{"location", "generated_at", "variable", "unit", "sample_size", "mean", "median", "min", "max", "std_dev", "p10", "p90"},
+		timeCol:   "generated_at",
+		orderBy:   "generated_at DESC, location, variable",
+		variables: true,
+	},
+}
+
+// Tables returns the names of the tables that can be queried
+func Tables() []string {
+	return []string{TableAnalyses, TableTrends, TableAnomalies, TablePatterns, TableStatistics}
+}
+
+// Rows are the result of a query: the names of the columns and a row of values for each,
+// strings, int64s, float64s or nil
+type Rows struct {
+	Columns []string
+	Values  [][]any
+}
+
+// Query returns the rows selected by q, or an error for an unknown table or a filter the table
+// does not have
+func (s *Store) Query(ctx context.Context, q Query) (Rows, error) {
+	table, ok := tableQueries[q.Table]
+	if !ok {
+		return Rows{}, fmt.Errorf("unknown table %q (expected one of %s)", q.Table, strings.Join(Tables(), ", "))
+	}
+	if q.Severity != "" && q.Table != TableAnomalies {
+		return Rows{}, errors.New("only anomalies have a severity")
+	}
+	if q.Variable != "" && !table.variables {
+		return Rows{}, fmt.Errorf("%s have no variable", q.Table)
+	}
+
+	var where []string
+	var args []any
+	if q.Location != "" {
+		// Case is ignored by lowering both sides, as LIKE only does so in SQLite; wildcards in the
+		// name are escaped
+		where = append(where, `LOWER(location) LIKE LOWER(?) ESCAPE '\'`)
+		args = append(args, likeEscaper.Replace(q.Location)+"%")
+	}
+	if !q.Since.IsZero() {
+		where = append(where, table.timeCol+" >= ?")
+		args = append(args, formatTime(q.Since))
+	}
+	if q.Severity != "" {
+		where = append(where, "LOWER(severity) = LOWER(?)")
+		args = append(args, q.Severity)
+	}
+	if q.Variable != "" {
+		where = append(where, "variable = ?")
+		args = append(args, q.Variable)
+	}
+
+	from := q.Table
+	if len(where) > 0 {
+		from += " WHERE " + strings.Join(where, " AND ")
+	}
+	if table.groupBy != "" {
+		from = "(SELECT *, ROW_NUMBER() OVER (PARTITION BY " + table.groupBy + " ORDER BY generated_at DESC) AS row_rank FROM " +
+			from + ") AS latest WHERE row_rank = 1"
+	}
+	query := "SELECT " + strings.Join(table.columns, ", ") + " FROM " + from + " ORDER BY " + table.orderBy
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return Rows{}, fmt.Errorf("failed to query %s: %w", q.Table, err)
+	}
+	defer rows.Close()
+	result := Rows{Columns: slices.Clone(table.columns)}
+	for rows.Next() {
+		values := make([]any, len(result.Columns))
+		pointers := make([]any, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return Rows{}, fmt.Errorf("failed to read %s: %w", q.Table, err)
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Values = append(result.Values, values)
+	}
+	return result, rows.Err()
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
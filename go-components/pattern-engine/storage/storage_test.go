@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/units"
+)
+
+// openTestStore opens a database in a temporary directory
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(context.Background(), filepath.Join(t.TempDir(), "db", "analyses.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// testAnalysis returns an analysis of location generated at a time, with an anomaly an hour earlier
+func testAnalysis(location string, generated time.Time, severity string) models.AnalysisResult {
+	return models.AnalysisResult{
+		AnalysisType: "trend_analysis",
+		Timeframe:    "24_hours",
+		Location:     location,
+		GeneratedAt:  generated,
+		Trends:       []models.Trend{{Variable: "pressure", Trend: "falling", ChangeRate: -1.5, Duration: "6h"}},
+		Anomalies: []models.Anomaly{{Variable: "temperature", Type: "spike", Severity: severity, Value: 25.5, Threshold: 20,
+			Timestamp: generated.Add(-time.Hour)}},
+		Patterns:        []models.Pattern{{Name: "cold_front", Confidence: 0.8, Variables: []string{"temperature", "pressure"}}},
+		StatisticalData: []models.StatisticalData{{Variable: "temperature", Mean: 18, SampleSize: 24}},
+		WeatherSummary:  models.WeatherSummary{ForecastSummary: "deteriorating", Alerts: []string{"storm_risk"}},
+	}
+}
+
+// TestSave tests that the rows of an analysis are stored and replaced when it is saved again
+func TestSave(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	generated := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	result := testAnalysis("Oslo", generated, "high")
+	for range 2 {
+		if err := store.Save(ctx, result); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	for _, table := range Tables() {
+		rows, err := store.Query(ctx, Query{Table: table})
+		if err != nil {
+			t.Fatalf("Query of %s failed: %v", table, err)
+		}
+		if len(rows.Values) != 1 {
+			t.Errorf("Expected 1 row in %s after saving the analysis twice, got %d", table, len(rows.Values))
+		}
+	}
+
+	rows, _ := store.Query(ctx, Query{Table: TableAnalyses})
+	row := rowMap(rows, 0)
+	if row["location"] != "Oslo" || row["generated_at"] != "2025-10-03T12:00:00Z" || row["alerts"] != "storm_risk" {
+		t.Errorf("Unexpected analysis row %v", row)
+	}
+	rows, _ = store.Query(ctx, Query{Table: TablePatterns})
+	if row := rowMap(rows, 0); row["variables"] != "temperature,pressure" || row["timestamp"] != nil {
+		t.Errorf("Unexpected pattern row %v", row)
+	}
+}
+
+// TestSaveUnits tests that values are stored with the units of the analysis
+func TestSaveUnits(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	imperial := units.Imperial()
+	result := testAnalysis("Denver, CO", time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), "moderate")
+	result.Units = &imperial
+	if err := store.Save(ctx, result); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	rows, _ := store.Query(ctx, Query{Table: TableAnomalies})
+	if unit := rowMap(rows, 0)["unit"]; unit != "°F" {
+		t.Errorf("Expected anomaly unit °F, got %v", unit)
+	}
+	rows, _ = store.Query(ctx, Query{Table: TableTrends})
+	if unit := rowMap(rows, 0)["unit"]; unit != "inHg/h" {
+		t.Errorf("Expected trend unit inHg/h, got %v", unit)
+	}
+}
+
+// TestQuery tests the filters of a query and that an anomaly found by several runs is reported once
+func TestQuery(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	now := time.Date(2025, 10, 10, 12, 0, 0, 0, time.UTC)
+	for _, result := range []models.AnalysisResult{
+		testAnalysis("Oslo, Norway", now.Add(-10*24*time.Hour), "high"),
+		testAnalysis("Oslo, Norway", now.Add(-2*24*time.Hour), "high"),
+		testAnalysis("Oslo, Norway", now.Add(-24*time.Hour), "moderate"),
+		testAnalysis("Bergen", now.Add(-24*time.Hour), "high"),
+	} {
+		if err := store.Save(ctx, result); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	// The second run finds the first's anomaly again
+	again := testAnalysis("Oslo, Norway", now.Add(-2*24*time.Hour+time.Minute), "high")
+	again.Anomalies[0].Timestamp = now.Add(-2*24*time.Hour - time.Hour)
+	if err := store.Save(ctx, again); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	rows, err := store.Query(ctx, Query{Table: TableAnomalies, Location: "oslo", Severity: "HIGH", Since: now.Add(-7 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(rows.Values) != 1 {
+		t.Fatalf("Expected 1 high-severity anomaly for Oslo in the last 7 days, got %v", rows.Values)
+	}
+	if row := rowMap(rows, 0); row["location"] != "Oslo, Norway" || row["generated_at"] != "2025-10-08T12:01:00Z" {
+		t.Errorf("Expected the anomaly from the latest run that found it, got %v", row)
+	}
+
+	rows, _ = store.Query(ctx, Query{Table: TableTrends, Variable: "pressure", Limit: 2})
+	if len(rows.Values) != 2 || rowMap(rows, 0)["generated_at"] != "2025-10-09T12:00:00Z" {
+		t.Errorf("Expected the 2 newest trends, got %v", rows.Values)
+	}
+	rows, _ = store.Query(ctx, Query{Table: TableAnalyses, Location: "o%"})
+	if len(rows.Values) != 0 {
+		t.Errorf("Expected wildcards in the location to match literally, got %v", rows.Values)
+	}
+
+	for _, q := range []Query{
+		{Table: "readings"},
+		{Table: TableTrends, Severity: "high"},
+		{Table: TablePatterns, Variable: "temperature"},
+	} {
+		if _, err := store.Query(ctx, q); err == nil {
+			t.Errorf("Expected an error for %+v", q)
+		}
+	}
+}
+
+// TestOpenMigrated tests that reopening a database leaves its schema and rows as they are
+func TestOpenMigrated(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "analyses.db")
+	store, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := store.Save(ctx, testAnalysis("Oslo", time.Now(), "high")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	store.Close()
+
+	store, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Reopening failed: %v", err)
+	}
+	defer store.Close()
+	rows, err := store.Query(ctx, Query{Table: TableAnalyses})
+	if err != nil || len(rows.Values) != 1 {
+		t.Errorf("Expected the saved analysis after reopening, got %v, %v", rows.Values, err)
+	}
+}
+
+// TestRebind tests that placeholders are numbered for PostgreSQL only
+func TestRebind(t *testing.T) {
+	query := "SELECT * FROM trends WHERE location = ? AND generated_at >= ? LIMIT ?"
+	if got := (&Store{}).rebind(query); got != query {
+		t.Errorf("Expected SQLite placeholders unchanged, got %s", got)
+	}
+	if got := (&Store{postgres: true}).rebind(query); got != "SELECT * FROM trends WHERE location = $1 AND generated_at >= $2 LIMIT $3" {
+		t.Errorf("Unexpected PostgreSQL query %s", got)
+	}
+	if !IsPostgres("postgres://weather@localhost/weather") || !IsPostgres("postgresql://localhost") || IsPostgres("data/analyses.db") {
+		t.Error("Expected only connection URLs to be PostgreSQL databases")
+	}
+}
+
+// rowMap returns a row of the result by column name
+func rowMap(rows Rows, i int) map[string]any {
+	row := make(map[string]any)
+	if i < len(rows.Values) {
+		for j, column := range rows.Columns {
+			row[column] = rows.Values[i][j]
+		}
+	}
+	return row
+}
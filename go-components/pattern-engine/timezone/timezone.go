@@ -0,0 +1,61 @@
+// Package timezone resolves the IANA time zone of a location from its coordinates, so that days
+// and hours of the day are those of the people living there rather than UTC's. The zone is the
+// one whose principal city (zone.tab of the tz database) is nearest, which is right away from
+// borders; at sea, far from any city, it is the nautical zone of the longitude (Etc/GMT±N).
+package timezone
+
+import (
+	"fmt"
+	"math"
+	_ "time/tzdata" // The zones resolved load on hosts without a zoneinfo database
+)
+
+// maxCityDistance is the farthest a location can be from a zone's principal city for the zone
+// to be taken as the location's (km)
+const maxCityDistance = 1500
+
+// earthRadius is the mean radius of the Earth (km)
+const earthRadius = 6371.0
+
+// zone is a time zone and where its principal city lies
+type zone struct {
+	name      string
+	latitude  float64
+	longitude float64
+}
+
+// Resolve returns the IANA name of the time zone at a latitude and longitude in degrees
+func Resolve(latitude, longitude float64) string {
+	best, bestDistance := "", math.Inf(1)
+	for _, z := range zones {
+		if d := distance(latitude, longitude, z.latitude, z.longitude); d < bestDistance {
+			best, bestDistance = z.name, d
+		}
+	}
+	if bestDistance <= maxCityDistance {
+		return best
+	}
+	return nautical(longitude)
+}
+
+// nautical returns the Etc zone of the 15° band of a longitude; Etc names count hours west of
+// Greenwich, so "Etc/GMT-1" is an hour ahead of UTC
+func nautical(longitude float64) string {
+	offset := int(math.Round(longitude / 15))
+	switch {
+	case offset == 0:
+		return "Etc/GMT"
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", min(offset, 12))
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", min(-offset, 12))
+	}
+}
+
+// distance returns the great-circle distance between two points in degrees (km)
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const rad = math.Pi / 180
+	dLat, dLon := (lat2-lat1)*rad, (lon2-lon1)*rad
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
@@ -0,0 +1,44 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+// TestResolve tests that locations get the zone of the nearest principal city, or at sea the
+// nautical zone of their longitude
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		want      string
+	}{
+		{"Oslo", 59.91, 10.75, "Europe/Oslo"},
+		{"Bergen", 60.39, 5.32, "Europe/Oslo"},
+		{"Rio de Janeiro", -22.91, -43.17, "America/Sao_Paulo"},
+		{"Brooklyn", 40.65, -73.95, "America/New_York"},
+		{"Tokyo", 35.68, 139.69, "Asia/Tokyo"},
+		{"South Pacific", -45, -140, "Etc/GMT+9"},
+		{"Indian Ocean", -30, 80, "Etc/GMT-5"},
+	}
+	for _, tt := range tests {
+		if got := Resolve(tt.latitude, tt.longitude); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestZonesLoad tests that every zone resolved can be loaded
+func TestZonesLoad(t *testing.T) {
+	for _, z := range zones {
+		if _, err := time.LoadLocation(z.name); err != nil {
+			t.Errorf("Zone %s does not load: %v", z.name, err)
+		}
+	}
+	for _, longitude := range []float64{-180, -7, 0, 7.6, 180} {
+		if _, err := time.LoadLocation(nautical(longitude)); err != nil {
+			t.Errorf("Nautical zone of %v° does not load: %v", longitude, err)
+		}
+	}
+}
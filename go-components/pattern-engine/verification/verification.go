@@ -0,0 +1,364 @@
+// Package verification measures how accurate the collector's forecasts were. The forecasts the
+// collector keeps for a location (see models.ForecastArchive) are compared with the readings later
+// observed at the times they were for, giving the mean absolute error, root mean squared error and
+// bias of each variable overall and by lead time, the time from issuing a forecast to the time it
+// is for.
+package verification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"pattern-engine/models"
+	"weathermodels/fileio"
+	"weathermodels/units"
+)
+
+// variables are the forecast variables verified, in report order
+var variables = []struct {
+	name  string
+	value func(models.WeatherPoint) float64
+}{
+	{"temperature", func(p models.WeatherPoint) float64 { return p.Temperature }},
+	{"pressure", func(p models.WeatherPoint) float64 { return p.Pressure }},
+	{"humidity", func(p models.WeatherPoint) float64 { return p.Humidity }},
+	{"wind_speed", func(p models.WeatherPoint) float64 { return p.WindSpeed }},
+	{"cloud_cover", func(p models.WeatherPoint) float64 { return p.CloudCover }},
+	{"precipitation_mm", func(p models.WeatherPoint) float64 { return p.PrecipitationMm }},
+}
+
+// statementLeadHours is the lead time the skill statements describe, or the nearest verified
+const statementLeadHours = 24
+
+// Config is the "verification" section of the shared config file
+type Config struct {
+	LeadTimes   []int              `json:"lead_times"`   // Upper bounds of the lead time buckets, hours, ascending; later points are not verified
+	Tolerance   time.Duration      `json:"tolerance"`    // Furthest a reading can be from the time of a forecast point to verify it
+	ErrorScales map[string]float64 `json:"error_scales"` // MAE of each variable at which its skill is 0.5
+	MinVerified int                `json:"min_verified"` // Points verified for the skill to make up the whole confidence of an analysis
+}
+
+// DefaultConfig returns the verification settings used when no config file is given
+func DefaultConfig() Config {
+	return Config{
+		LeadTimes: []int{3, 6, 12, 24, 48, 72},
+		Tolerance: 30 * time.Minute,
+		ErrorScales: map[string]float64{
+			"temperature":      2,  // °C
+			"pressure":         2,  // hPa
+			"humidity":         10, // %
+			"wind_speed":       2,  // m/s
+			"cloud_cover":      25, // %
+			"precipitation_mm": 1,  // mm
+		},
+		MinVerified: 48,
+	}
+}
+
+// LoadConfig reads the "verification" section of a config file, falling back to defaults if path
+// is empty, and validates it
+func LoadConfig(path string) (Config, error) {
+	file := struct {
+		Verification Config `json:"verification"`
+	}{Verification: DefaultConfig()}
+
+	if path == "" {
+		return file.Verification, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file.Verification, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file.Verification, err
+	}
+	cfg := file.Verification
+	if len(cfg.LeadTimes) == 0 {
+		return cfg, fmt.Errorf("verification.lead_times needs at least one lead time")
+	}
+	for i, hours := range cfg.LeadTimes {
+		if hours <= 0 || i > 0 && hours <= cfg.LeadTimes[i-1] {
+			return cfg, fmt.Errorf("verification.lead_times must be positive and ascending (value: %v)", cfg.LeadTimes)
+		}
+	}
+	if cfg.Tolerance < 0 {
+		return cfg, fmt.Errorf("verification.tolerance cannot be negative (value: %v)", cfg.Tolerance)
+	}
+	for name, scale := range cfg.ErrorScales {
+		if scale <= 0 {
+			return cfg, fmt.Errorf("verification.error_scales.%s must be positive (value: %v)", name, scale)
+		}
+	}
+	if cfg.MinVerified < 1 {
+		return cfg, fmt.Errorf("verification.min_verified must be at least 1 (value: %d)", cfg.MinVerified)
+	}
+	return cfg, nil
+}
+
+// accumulator sums the errors of forecast points
+type accumulator struct {
+	count                       int
+	sumError, sumAbs, sumSquare float64
+}
+
+// add adds the error of one forecast point
+func (a *accumulator) add(forecast, observed float64) {
+	e := forecast - observed
+	a.count++
+	a.sumError += e
+	a.sumAbs += math.Abs(e)
+	a.sumSquare += e * e
+}
+
+// accuracy returns the accuracy of the points added
+func (a *accumulator) accuracy() models.Accuracy {
+	if a.count == 0 {
+		return models.Accuracy{}
+	}
+	n := float64(a.count)
+	return models.Accuracy{
+		Count: a.count,
+		MAE:   a.sumAbs / n,
+		RMSE:  math.Sqrt(a.sumSquare / n),
+		Bias:  a.sumError / n,
+	}
+}
+
+// Verify compares the forecasts of an archive with the readings observed at the times they were
+// for. Each forecast point after its forecast was issued is verified against the reading nearest
+// its time, if one is within the tolerance, and counted in the first lead time bucket its lead
+// time fits in. Forecast and nowcast readings are not verified against. Variables without a
+// verified point are left out of the report.
+func Verify(archive models.ForecastArchive, readings []models.WeatherPoint, cfg Config) models.VerificationReport {
+	readings = slices.DeleteFunc(slices.Clone(readings), func(r models.WeatherPoint) bool { return !r.Observed() })
+	slices.SortFunc(readings, func(a, b models.WeatherPoint) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	overall := make([]accumulator, len(variables))
+	byLead := make([][]accumulator, len(variables))
+	for i := range byLead {
+		byLead[i] = make([]accumulator, len(cfg.LeadTimes))
+	}
+
+	report := models.VerificationReport{Location: archive.Location, GeneratedAt: time.Now()}
+	for _, forecast := range archive.Forecasts {
+		verified := false
+		for _, point := range forecast.Points {
+			lead := point.Timestamp.Sub(forecast.IssuedAt)
+			if lead <= 0 {
+				continue
+			}
+			bucket := sort.SearchInts(cfg.LeadTimes, int(math.Ceil(lead.Hours())))
+			if bucket == len(cfg.LeadTimes) {
+				continue
+			}
+			observed, ok := nearest(readings, point.Timestamp, cfg.Tolerance)
+			if !ok {
+				continue
+			}
+
+			for i, v := range variables {
+				overall[i].add(v.value(point), v.value(observed))
+				byLead[i][bucket].add(v.value(point), v.value(observed))
+			}
+			verified = true
+			if report.Start.IsZero() || point.Timestamp.Before(report.Start) {
+				report.Start = point.Timestamp
+			}
+			if point.Timestamp.After(report.End) {
+				report.End = point.Timestamp
+			}
+		}
+		if verified {
+			report.Forecasts++
+		}
+	}
+
+	for i, v := range variables {
+		if overall[i].count == 0 {
+			continue
+		}
+		accuracy := models.ForecastAccuracy{Variable: v.name, Accuracy: overall[i].accuracy()}
+		for j, hours := range cfg.LeadTimes {
+			if byLead[i][j].count > 0 {
+				accuracy.LeadTimes = append(accuracy.LeadTimes, models.LeadTimeAccuracy{LeadHours: hours, Accuracy: byLead[i][j].accuracy()})
+			}
+		}
+		report.Variables = append(report.Variables, accuracy)
+	}
+	return report
+}
+
+// nearest returns the reading nearest t within tolerance; readings is in chronological order
+func nearest(readings []models.WeatherPoint, t time.Time, tolerance time.Duration) (models.WeatherPoint, bool) {
+	i := sort.Search(len(readings), func(i int) bool { return !readings[i].Timestamp.Before(t) })
+	best, found := models.WeatherPoint{}, false
+	bestDistance := tolerance
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(readings) {
+			continue
+		}
+		distance := readings[j].Timestamp.Sub(t).Abs()
+		if distance <= bestDistance {
+			best, found, bestDistance = readings[j], true, distance
+		}
+	}
+	return best, found
+}
+
+// Skill returns the skill of the forecasts verified in a report. Each variable with an error
+// scale scores scale / (scale + MAE), 1 without error and 0.5 at an MAE of the scale, and the
+// score is the mean of the variables' weighted by their points verified. The weight grows with
+// the points verified up to MinVerified. It returns nil when no variable with a scale was verified.
+func Skill(report models.VerificationReport, cfg Config) *models.ForecastSkill {
+	skill := &models.ForecastSkill{VerifiedAt: report.GeneratedAt, Forecasts: report.Forecasts, Variables: report.Variables}
+	var weighted float64
+	verified, scored := 0, 0
+	for _, v := range report.Variables {
+		scale, ok := cfg.ErrorScales[v.Variable]
+		if !ok || v.Count == 0 {
+			continue
+		}
+		weighted += float64(v.Count) * scale / (scale + v.MAE)
+		verified += v.Count
+		scored++
+		if statement := statement(v, units.Metric()); statement != "" {
+			skill.Statements = append(skill.Statements, statement)
+		}
+	}
+	if verified == 0 {
+		return nil
+	}
+	skill.Score = weighted / float64(verified)
+	points := float64(verified) / float64(scored) // Each point is verified once per variable
+	skill.Weight = min(points/float64(cfg.MinVerified), 1)
+	return skill
+}
+
+// statement describes the MAE of a variable at the lead time nearest statementLeadHours in the
+// units of system, such as "temperature forecasts have 1.2°C MAE at 24h lead"
+func statement(accuracy models.ForecastAccuracy, system units.System) string {
+	if len(accuracy.LeadTimes) == 0 {
+		return ""
+	}
+	nearest := accuracy.LeadTimes[0]
+	for _, lead := range accuracy.LeadTimes[1:] {
+		if math.Abs(float64(lead.LeadHours-statementLeadHours)) < math.Abs(float64(nearest.LeadHours-statementLeadHours)) {
+			nearest = lead
+		}
+	}
+	name := strings.ReplaceAll(strings.TrimSuffix(accuracy.Variable, "_mm"), "_", " ")
+	return fmt.Sprintf("%s forecasts have %.1f%s MAE at %dh lead", name, nearest.MAE, system.Symbol(accuracy.Variable), nearest.LeadHours)
+}
+
+// ConvertSkill returns a copy of a skill with the errors of its variables and its statements in
+// the units of system; the skill itself is shared by every analysis of its location
+func ConvertSkill(skill *models.ForecastSkill, system units.System) *models.ForecastSkill {
+	if skill == nil || system.IsMetric() {
+		return skill
+	}
+	converted := *skill
+	converted.Statements = slices.Clone(skill.Statements)
+	converted.Variables = make([]models.ForecastAccuracy, len(skill.Variables))
+	for i, v := range skill.Variables {
+		c := v
+		c.Accuracy = convertAccuracy(v.Variable, v.Accuracy, system)
+		c.LeadTimes = slices.Clone(v.LeadTimes)
+		for j := range c.LeadTimes {
+			c.LeadTimes[j].Accuracy = convertAccuracy(v.Variable, v.LeadTimes[j].Accuracy, system)
+		}
+		converted.Variables[i] = c
+		if j := slices.Index(converted.Statements, statement(v, units.Metric())); j >= 0 {
+			converted.Statements[j] = statement(c, system)
+		}
+	}
+	return &converted
+}
+
+// convertAccuracy converts the errors of a variable, which are differences of its values
+func convertAccuracy(variable string, accuracy models.Accuracy, system units.System) models.Accuracy {
+	accuracy.MAE = system.ConvertChange(variable, accuracy.MAE)
+	accuracy.RMSE = system.ConvertChange(variable, accuracy.RMSE)
+	accuracy.Bias = system.ConvertChange(variable, accuracy.Bias)
+	return accuracy
+}
+
+// LoadSkills reads the verification reports in dir, as written by SaveReport, and returns the
+// skill of each location's forecasts by location name. A missing directory has no reports.
+func LoadSkills(dir string, cfg Config) (map[string]*models.ForecastSkill, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	skills := make(map[string]*models.ForecastSkill)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_verification.json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var report models.VerificationReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("invalid verification report %s: %w", path, err)
+		}
+		if skill := Skill(report, cfg); skill != nil && report.Location != "" {
+			skills[report.Location] = skill
+		}
+	}
+	return skills, nil
+}
+
+// LoadArchive reads a forecast archive written by the collector (gzipped or not)
+func LoadArchive(path string) (models.ForecastArchive, error) {
+	var archive models.ForecastArchive
+	data, err := fileio.ReadFile(path)
+	if err != nil {
+		return archive, err
+	}
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return archive, fmt.Errorf("invalid forecast archive %s: %w", path, err)
+	}
+	if archive.SchemaVersion > models.TimeseriesSchemaVersion {
+		return archive, fmt.Errorf("unsupported schema_version %d in %s", archive.SchemaVersion, path)
+	}
+	return archive, nil
+}
+
+// ReportPath returns the path of a location's verification report in dir
+func ReportPath(dir, location string) string {
+	safe := strings.NewReplacer(" ", "_", ",", "", "/", "_").Replace(location)
+	return filepath.Join(dir, safe+"_verification.json")
+}
+
+// SaveReport writes a report to its location's report file in dir, replacing the previous one,
+// and returns its path
+func SaveReport(dir string, report models.VerificationReport) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating verification directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := ReportPath(dir, report.Location)
+	if err := fileio.WriteFileAtomic(path, data, 0644); err != nil {
+		return "", fmt.Errorf("writing verification report to %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,167 @@
+package verification
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+)
+
+// TestVerify tests the errors of each variable overall and by lead time, against the nearest
+// reading within the tolerance
+func TestVerify(t *testing.T) {
+	issued := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time { return issued.Add(time.Duration(hours * float64(time.Hour))) }
+
+	var readings []models.WeatherPoint
+	for h := 0; h <= 30; h++ {
+		readings = append(readings, models.WeatherPoint{Timestamp: at(float64(h)).Add(10 * time.Minute), Temperature: 10, Pressure: 1010})
+	}
+	// A forecast at the very time of a point is not what was observed
+	readings = append(readings, models.WeatherPoint{Timestamp: at(24), Temperature: 14, Pressure: 1012, Source: models.SourceForecast})
+	archive := models.ForecastArchive{
+		Location: "Oslo",
+		Forecasts: []models.IssuedForecast{
+			{IssuedAt: issued, Points: []models.WeatherPoint{
+				{Timestamp: at(0), Temperature: 50},                   // Not a forecast yet
+				{Timestamp: at(1), Temperature: 11, Pressure: 1010},   // Lead 1h: +1
+				{Timestamp: at(2), Temperature: 9, Pressure: 1010},    // Lead 2h: -1
+				{Timestamp: at(24), Temperature: 14, Pressure: 1012},  // Lead 24h: +4
+				{Timestamp: at(100), Temperature: 50, Pressure: 1012}, // Beyond the last lead time
+			}},
+			{IssuedAt: at(30), Points: []models.WeatherPoint{
+				{Timestamp: at(31), Temperature: 50}, // No reading yet
+			}},
+		},
+	}
+
+	cfg := Config{LeadTimes: []int{3, 24}, Tolerance: 15 * time.Minute}
+	report := Verify(archive, readings, cfg)
+	if report.Location != "Oslo" || report.Forecasts != 1 || !report.Start.Equal(at(1)) || !report.End.Equal(at(24)) {
+		t.Errorf("Unexpected report header: %+v", report)
+	}
+	if len(report.Variables) != 6 || report.Variables[0].Variable != "temperature" {
+		t.Fatalf("Expected the six variables, temperature first, got %+v", report.Variables)
+	}
+
+	temperature := report.Variables[0]
+	if temperature.Count != 3 || math.Abs(temperature.MAE-2) > 1e-9 || math.Abs(temperature.RMSE-math.Sqrt(6)) > 1e-9 ||
+		math.Abs(temperature.Bias-4.0/3) > 1e-9 {
+		t.Errorf("Unexpected temperature accuracy: %+v", temperature.Accuracy)
+	}
+	if len(temperature.LeadTimes) != 2 || temperature.LeadTimes[0].LeadHours != 3 || temperature.LeadTimes[0].Count != 2 ||
+		temperature.LeadTimes[0].MAE != 1 || temperature.LeadTimes[0].Bias != 0 || temperature.LeadTimes[1].Bias != 4 {
+		t.Errorf("Unexpected temperature lead times: %+v", temperature.LeadTimes)
+	}
+	if pressure := report.Variables[1]; pressure.Bias != 2.0/3 {
+		t.Errorf("Unexpected pressure accuracy: %+v", pressure)
+	}
+
+	cfg.Tolerance = 5 * time.Minute
+	if report := Verify(archive, readings, cfg); report.Forecasts != 0 || len(report.Variables) != 0 {
+		t.Errorf("Expected no point verified without a reading within 5 minutes, got %+v", report)
+	}
+}
+
+// TestSkill tests the score, weight and statements of a report's skill
+func TestSkill(t *testing.T) {
+	report := models.VerificationReport{
+		Location:  "Oslo",
+		Forecasts: 4,
+		Variables: []models.ForecastAccuracy{
+			{Variable: "temperature", Accuracy: models.Accuracy{Count: 24, MAE: 2}, LeadTimes: []models.LeadTimeAccuracy{
+				{LeadHours: 6, Accuracy: models.Accuracy{Count: 12, MAE: 1}},
+				{LeadHours: 48, Accuracy: models.Accuracy{Count: 12, MAE: 3}},
+			}},
+			{Variable: "precipitation_mm", Accuracy: models.Accuracy{Count: 24}, LeadTimes: []models.LeadTimeAccuracy{
+				{LeadHours: 24, Accuracy: models.Accuracy{Count: 24, MAE: 0.04}},
+			}},
+		},
+	}
+	cfg := DefaultConfig()
+	skill := Skill(report, cfg)
+	if skill == nil {
+		t.Fatal("Expected a skill")
+	}
+	// Temperature scores 2 / (2 + 2) and precipitation 1 / (1 + 0)
+	if skill.Score != 0.75 || skill.Weight != 0.5 || skill.Forecasts != 4 {
+		t.Errorf("Unexpected skill: %+v", skill)
+	}
+	want := []string{"temperature forecasts have 1.0°C MAE at 6h lead", "precipitation forecasts have 0.0 mm MAE at 24h lead"}
+	if len(skill.Statements) != 2 || skill.Statements[0] != want[0] || skill.Statements[1] != want[1] {
+		t.Errorf("Expected statements %q, got %q", want, skill.Statements)
+	}
+
+	cfg.ErrorScales = map[string]float64{"pressure": 2}
+	if skill := Skill(report, cfg); skill != nil {
+		t.Errorf("Expected no skill without a scored variable, got %+v", skill)
+	}
+}
+
+// TestLoadSkills tests that the saved reports are read by location and a missing directory has none
+func TestLoadSkills(t *testing.T) {
+	dir := t.TempDir()
+	report := models.VerificationReport{Location: "Oslo", Variables: []models.ForecastAccuracy{
+		{Variable: "temperature", Accuracy: models.Accuracy{Count: 96, MAE: 1}, LeadTimes: []models.LeadTimeAccuracy{{LeadHours: 24, Accuracy: models.Accuracy{Count: 96, MAE: 1}}}},
+	}}
+	if _, err := SaveReport(dir, report); err != nil {
+		t.Fatal(err)
+	}
+	skills, err := LoadSkills(dir, DefaultConfig())
+	if err != nil {
+		t.Fatalf("LoadSkills failed: %v", err)
+	}
+	if skill := skills["Oslo"]; skill == nil || math.Abs(skill.Score-2.0/3) > 1e-9 || skill.Weight != 1 {
+		t.Errorf("Unexpected skills: %+v", skills)
+	}
+
+	if skills, err := LoadSkills(filepath.Join(dir, "missing"), DefaultConfig()); skills != nil || err != nil {
+		t.Errorf("Expected no skills from a missing directory, got %v (err: %v)", skills, err)
+	}
+}
+
+// TestLoadConfig tests that lead times must be positive and ascending
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	tests := []struct {
+		config  string
+		wantErr bool
+	}{
+		{`{"verification": {"lead_times": [6, 24]}}`, false},
+		{`{"verification": {"lead_times": []}}`, true},
+		{`{"verification": {"lead_times": [24, 6]}}`, true},
+		{`{"verification": {"tolerance": -1}}`, true},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.config, tt.wantErr, err)
+		}
+		if err == nil && (len(cfg.LeadTimes) != 2 || cfg.Tolerance != 30*time.Minute) {
+			t.Errorf("%s: unexpected config %+v", tt.config, cfg)
+		}
+	}
+}
+
+// TestSaveReport tests that a location's report replaces the previous one
+func TestSaveReport(t *testing.T) {
+	dir := t.TempDir()
+	for _, forecasts := range []int{1, 2} {
+		path, err := SaveReport(dir, models.VerificationReport{Location: "Rio de Janeiro, Brazil", Forecasts: forecasts})
+		if err != nil {
+			t.Fatalf("SaveReport failed: %v", err)
+		}
+		if want := filepath.Join(dir, "Rio_de_Janeiro_Brazil_verification.json"); path != want {
+			t.Errorf("Expected %s, got %s", want, path)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected one report, got %d files", len(entries))
+	}
+}
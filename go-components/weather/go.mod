@@ -0,0 +1,31 @@
+module weather
+
+go 1.25.1
+
+require (
+	pattern-engine v0.0.0
+	weather-collector v0.0.0
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/pgx/v5 v5.9.2 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/mattn/go-sqlite3 v1.14.33 // indirect
+	golang.org/x/image v0.25.0 // indirect
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
+	google.golang.org/grpc v1.84.0 // indirect
+	google.golang.org/protobuf v1.36.12 // indirect
+	weathermodels v0.0.0 // indirect
+)
+
+replace (
+	pattern-engine => ../pattern-engine
+	weather-collector => ../data-collector
+	weathermodels => ../weathermodels
+)
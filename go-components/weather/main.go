@@ -0,0 +1,132 @@
+// Command weather is the single binary of the Weather Intelligence System's Go components.
+//
+//	weather collect   [flags]  collect weather for the input locations (also -daemon and -pipe)
+//	weather analyze   [flags]  analyze the per-location time-series files
+//	weather pipeline  [flags]  collect into the time-series files, then analyze them
+//	                           (-in-memory to analyze the collected forecasts without files)
+//	weather serve     [flags]  serve the collection REST API (or gRPC with -grpc)
+//	weather interpolate [flags] estimate the weather at a point from the analyses around it
+//	weather verify    [flags]  verify the kept forecasts against the readings observed since
+//	weather query <table> [flags]  query the analyses stored with analyze -database
+//	weather serve-analysis [flags] serve the analysis REST API
+//
+// Run "weather <command> -h" for the flags of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	patterncli "pattern-engine/cli"
+	"weather-collector/cli"
+)
+
+// Process exit codes shared by every command, so orchestration scripts can tell a partial
+// failure from a total one
+const (
+	exitOK             = 0 // Every location was collected or analyzed
+	exitError          = 1 // Unexpected failure
+	exitPartialFailure = 2 // Some locations failed
+	exitConfigError    = 4 // Invalid configuration, flags or command
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run dispatches to a command and returns its exit code
+func run(args []string, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitConfigError
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "collect":
+		return cli.Collect(args)
+	case "analyze":
+		return patterncli.Analyze(args)
+	case "pipeline":
+		return pipeline(args)
+	case "serve":
+		return cli.Serve(args)
+	case "interpolate":
+		return patterncli.Interpolate(args)
+	case "verify":
+		return patterncli.Verify(args)
+	case "query":
+		return patterncli.Query(args)
+	case "serve-analysis":
+		return patterncli.Serve(args)
+	case "help", "-h", "-help", "--help":
+		usage(stderr)
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "weather: unknown command %q\n\n", command)
+	usage(stderr)
+	return exitConfigError
+}
+
+// pipeline collects into the time-series files, then analyzes them. Analysis is skipped when
+// collection failed outright; after a partial collection it still runs, and the worse of the two
+// outcomes is returned.
+func pipeline(args []string) int {
+	flags := flag.NewFlagSet("pipeline", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a JSON configuration file shared by both steps")
+	compress := flags.Bool("compress", false, "gzip analysis files (written as .json.gz)")
+	timeseriesDir := flags.String("timeseries-dir", patterncli.DefaultTimeseriesDir, "directory of per-location time-series files collected into and analyzed")
+	analysisDir := flags.String("analysis-dir", patterncli.DefaultAnalysisDir, "directory analysis files are written to (with -in-memory, empty to skip them)")
+	regional := flags.Bool("regional", false, "also analyze the locations together for correlations and delays between them")
+	inMemory := flags.Bool("in-memory", false, "analyze the collected forecasts in the same process and write the analyses to stdout, skipping the output and time-series files")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitConfigError
+	}
+
+	if *inMemory {
+		return pipelineInMemory(*configPath, *analysisDir, *compress, os.Stdout)
+	}
+
+	code := cli.Collect([]string{"-config", *configPath, "-timeseries-dir", *timeseriesDir})
+	if code != exitOK && code != exitPartialFailure {
+		return code
+	}
+
+	analyzeArgs := []string{"-config", *configPath, "-timeseries-dir", *timeseriesDir, "-analysis-dir", *analysisDir}
+	if *compress {
+		analyzeArgs = append(analyzeArgs, "-compress")
+	}
+	if *regional {
+		analyzeArgs = append(analyzeArgs, "-regional")
+	}
+	if analyzeCode := patterncli.Analyze(analyzeArgs); analyzeCode != exitOK {
+		return analyzeCode
+	}
+	return code
+}
+
+// usage prints the command overview
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: weather <command> [flags]
+
+Commands:
+  collect   collect weather for the input locations (-daemon to run on a schedule, -pipe for stdin/stdout)
+  analyze   analyze the per-location time-series files
+  pipeline  collect into the time-series files, then analyze them (-in-memory to skip the files)
+  serve     serve the collection REST API (-grpc for the gRPC API)
+  interpolate  estimate the weather at a point (-lat, -lon) from the analyses around it
+  verify    verify the forecasts kept by the collector against the readings observed since
+  query     query the trends, anomalies, patterns or statistics stored with analyze -database,
+            e.g. weather query anomalies -location Oslo -severity high -since 7d
+  serve-analysis  serve the analysis REST API (POST /analyze, GET /analysis/{location}/latest)
+            and stream new analyses and alerts as Server-Sent Events (GET /events)
+
+Run "weather <command> -h" for the flags of a command.
+`)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRun tests the dispatch of the commands and the exit codes of the usage errors
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string // Expected in stderr
+	}{
+		{name: "No command", args: nil, code: exitConfigError, stderr: "Usage: weather <command>"},
+		{name: "Help", args: []string{"help"}, code: exitOK, stderr: "Usage: weather <command>"},
+		{name: "Help flag", args: []string{"--help"}, code: exitOK, stderr: "serve-analysis"},
+		{name: "Unknown command", args: []string{"forecast"}, code: exitConfigError, stderr: `unknown command "forecast"`},
+		{name: "Command help", args: []string{"pipeline", "-h"}, code: exitOK},
+		{name: "Unknown pipeline flag", args: []string{"pipeline", "-bogus"}, code: exitConfigError},
+		{name: "Unknown collect flag", args: []string{"collect", "-bogus"}, code: exitConfigError},
+		{name: "Unknown analyze flag", args: []string{"analyze", "-bogus"}, code: exitConfigError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if code := run(tt.args, &stderr); code != tt.code {
+				t.Errorf("Expected exit code %d, got %d", tt.code, code)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("Expected %q in stderr, got %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+// TestPipelineInMemoryDispatch tests that pipeline -in-memory runs the in-memory pipeline
+func TestPipelineInMemoryDispatch(t *testing.T) {
+	dir := t.TempDir()
+	configPath := writeTestConfig(t, dir)
+	if code := run([]string{"pipeline", "-in-memory", "-config", configPath, "-analysis-dir", ""}, &bytes.Buffer{}); code != exitOK {
+		t.Errorf("Expected exit code %d, got %d", exitOK, code)
+	}
+}
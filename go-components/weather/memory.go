@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	patterncli "pattern-engine/cli"
+	"pattern-engine/engine"
+	"pattern-engine/models"
+	"weather-collector/cli"
+	"weather-collector/collector"
+)
+
+// pipelineInMemory collects and hands the results straight to the pattern engine, without the
+// output file and time-series files in between. Each location is analyzed from its current
+// reading and forecast; the analyses are written as a JSON array to stdout and, unless
+// analysisDir is empty, to analysis files.
+func pipelineInMemory(configPath, analysisDir string, compress bool, stdout io.Writer) int {
+	results, code := cli.CollectResults(configPath)
+	if code != exitOK && code != exitPartialFailure {
+		return code
+	}
+
+	var locations []models.LocationData
+	for _, result := range results {
+		if result.Success {
+			locations = append(locations, locationData(result))
+		}
+	}
+
+	analyses, analyzeCode := patterncli.AnalyzeLocations(configPath, locations, analysisDir, compress, "")
+	if analyses == nil {
+		analyses = []models.AnalysisResult{} // An empty array rather than null
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(analyses); err != nil {
+		return exitError
+	}
+
+	if analyzeCode != exitOK {
+		return analyzeCode
+	}
+	return code
+}
+
+// locationData converts a successful collection result into the pattern engine's input. The
+// readings are the current conditions followed by the forecast, ordered by time with one reading
+// per timestamp.
+func locationData(result collector.WeatherResult) models.LocationData {
+	readings := append([]models.WeatherPoint{result.CurrentWeather}, result.Forecast...)
+	readings = slices.DeleteFunc(readings, func(p models.WeatherPoint) bool { return p.Timestamp.IsZero() })
+	slices.SortStableFunc(readings, func(a, b models.WeatherPoint) int { return a.Timestamp.Compare(b.Timestamp) })
+	readings = slices.CompactFunc(readings, func(a, b models.WeatherPoint) bool { return a.Timestamp.Equal(b.Timestamp) })
+
+	data := models.LocationData{
+		Name: result.Location.Name,
+		Coordinates: models.Coordinates{
+			Latitude:  result.Location.Lat,
+			Longitude: result.Location.Lon,
+			Altitude:  result.Location.Alt,
+		},
+		Readings: readings,
+		Marine:   result.Marine,
+	}
+	for _, alert := range result.Alerts {
+		if label := engine.AlertLabel(alert.Event, alert.AwarenessLevel); label != "" {
+			data.Alerts = append(data.Alerts, label)
+		}
+	}
+	return data
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pattern-engine/models"
+	"weather-collector/collector"
+	"weather-collector/config"
+)
+
+// fixturesDir holds the met.no responses recorded for London, replayed by the mock provider
+const fixturesDir = "../data-collector/collector/testdata/fixtures"
+
+// writeTestConfig writes the default config with the mock provider collecting London from the
+// fixtures, and makes dir the working directory so that every file of a run lands there
+func writeTestConfig(t *testing.T, dir string) string {
+	t.Helper()
+	fixtures, err := filepath.Abs(fixturesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	cfg, _, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.API.Provider = collector.ProviderMock
+	cfg.API.FixturesDir = fixtures
+	cfg.Integration.Handshake = false // No Python side to mark the locations file complete
+	locations := []collector.Location{{Name: "London, UK", Lat: 51.5074, Lon: -0.1278}}
+	data, err := json.Marshal(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfg.GetInputFilePath(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := cfg.SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestPipelineInMemory tests that the collected forecasts are analyzed without the output and
+// time-series files, and that the analyses are written to stdout and the analysis directory
+func TestPipelineInMemory(t *testing.T) {
+	dir := t.TempDir()
+	configPath := writeTestConfig(t, dir)
+	analysisDir := filepath.Join(dir, "analysis")
+
+	var stdout bytes.Buffer
+	if code := pipelineInMemory(configPath, analysisDir, false, &stdout); code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stdout.String())
+	}
+	var analyses []models.AnalysisResult
+	if err := json.Unmarshal(stdout.Bytes(), &analyses); err != nil {
+		t.Fatalf("Invalid analyses on stdout: %v", err)
+	}
+	if len(analyses) != 1 || analyses[0].Location != "London, UK" {
+		t.Fatalf("Expected the analysis of London, got %s", stdout.String())
+	}
+	if analyses[0].Timeframe == "" || analyses[0].Narrative == "" {
+		t.Errorf("Expected an analysis of the readings, got %s", stdout.String())
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(analysisDir, "*_analysis_*.json")); len(files) != 1 {
+		t.Errorf("Expected one analysis file, got %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "integration", "output_weather.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no output file, got %v", err)
+	}
+}
+
+// TestLocationData tests that the current reading and the forecast become time-ordered
+// readings with one per timestamp, and that warnings become summary alerts
+func TestLocationData(t *testing.T) {
+	start := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+	result := collector.WeatherResult{
+		Location:       collector.Location{Name: "Oslo", Lat: 59.91, Lon: 10.75, Alt: 23},
+		CurrentWeather: collector.WeatherPoint{Timestamp: start, Temperature: 9},
+		Forecast: []collector.WeatherPoint{
+			{Timestamp: start.Add(2 * time.Hour), Temperature: 11},
+			{Timestamp: start, Temperature: 8}, // Repeats the current reading's hour
+			{Temperature: 99},                  // No timestamp
+			{Timestamp: start.Add(time.Hour), Temperature: 10},
+		},
+		Alerts:  []collector.Alert{{Event: "wind", AwarenessLevel: "yellow"}, {Title: "No event"}},
+		Success: true,
+	}
+
+	data := locationData(result)
+	if data.Name != "Oslo" || data.Coordinates.Altitude != 23 {
+		t.Errorf("Unexpected location: %+v", data)
+	}
+	if len(data.Readings) != 3 {
+		t.Fatalf("Expected 3 readings, got %d", len(data.Readings))
+	}
+	for i, want := range []float64{9, 10, 11} {
+		if data.Readings[i].Temperature != want {
+			t.Errorf("Expected reading %d at %.0f°C, got %.0f°C", i, want, data.Readings[i].Temperature)
+		}
+	}
+	if len(data.Alerts) != 1 || data.Alerts[0] != "yellow_wind_warning" {
+		t.Errorf("Expected the wind warning, got %v", data.Alerts)
+	}
+}
@@ -0,0 +1,54 @@
+// Package eventbus is the event bus client shared by the data collector and the pattern engine,
+// which publish their weather results and analyses to a NATS subject or a Kafka topic. It
+// contains a minimal NATS client (CONNECT, PUB, PING/PONG) and a producer for the Kafka REST
+// Proxy, so neither component needs a native client library.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Event bus drivers accepted in events.driver
+const (
+	DriverNATS  = "nats"
+	DriverKafka = "kafka"
+)
+
+// Message is one event: a JSON value and the key consumers (and Kafka partitioning) group it by
+type Message struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// Bus is a connection setting for each driver and the driver to publish with
+type Bus struct {
+	Driver string
+	NATS   NATSOptions
+	Kafka  *KafkaProducer
+}
+
+// Publish sends messages to subject using one NATS connection or one Kafka REST request
+func (b Bus) Publish(ctx context.Context, subject string, messages []Message) error {
+	switch b.Driver {
+	case DriverNATS:
+		client, err := DialNATS(ctx, b.NATS)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		for _, msg := range messages {
+			if err := client.Publish(subject, msg.Value); err != nil {
+				return fmt.Errorf("failed to publish to %s: %w", subject, err)
+			}
+		}
+		if err := client.Flush(ctx); err != nil {
+			return fmt.Errorf("failed to publish to %s: %w", subject, err)
+		}
+		return nil
+	case DriverKafka:
+		return b.Kafka.Produce(ctx, subject, messages)
+	}
+	return fmt.Errorf("unknown event bus driver %q", b.Driver)
+}
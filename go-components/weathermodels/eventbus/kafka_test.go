@@ -0,0 +1,55 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestPublishKafka tests the produce request sent to the Kafka REST Proxy
+func TestPublishKafka(t *testing.T) {
+	var records []kafkaRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/weather.results" || r.Header.Get("Content-Type") != kafkaContentType {
+			t.Errorf("Unexpected request: %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "weather" || pass != "secret" {
+			t.Errorf("Expected basic auth, got %q/%q", user, pass)
+		}
+		var body struct {
+			Records []kafkaRecord `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		records = body.Records
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":10},{"partition":1,"offset":4}]}`))
+	}))
+	defer server.Close()
+
+	bus := Bus{Driver: DriverKafka, Kafka: &KafkaProducer{URL: server.URL + "/", Username: "weather", Password: "secret"}}
+	if err := bus.Publish(context.Background(), "weather.results", eventMessages); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if len(records) != 2 || records[0].Key != "Oslo" || records[1].Key != "Broken" {
+		t.Fatalf("Expected records keyed by location, got %+v", records)
+	}
+	if !strings.Contains(string(records[0].Value), `"success":true`) {
+		t.Errorf("Expected the message as record value, got %s", records[0].Value)
+	}
+}
+
+// TestKafkaRecordError tests that a record the proxy could not produce is reported
+func TestKafkaRecordError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":10},{"error_code":2,"error":"record too large"}]}`))
+	}))
+	defer server.Close()
+
+	producer := &KafkaProducer{URL: server.URL}
+	err := producer.Produce(context.Background(), "weather.results", []Message{{Value: []byte("{}")}, {Value: []byte("{}")}})
+	if err == nil || !strings.Contains(err.Error(), "record 2 of 2") || !strings.Contains(err.Error(), "record too large") {
+		t.Errorf("Expected the second record's error, got %v", err)
+	}
+}
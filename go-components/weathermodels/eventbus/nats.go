@@ -0,0 +1,190 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// natsInfo is the part of the server's INFO message the client uses
+type natsInfo struct {
+	TLSRequired bool  `json:"tls_required"`
+	MaxPayload  int64 `json:"max_payload"`
+}
+
+// natsConnect is the CONNECT message sent after INFO
+type natsConnect struct {
+	Verbose     bool   `json:"verbose"`
+	Pedantic    bool   `json:"pedantic"`
+	TLSRequired bool   `json:"tls_required"`
+	Name        string `json:"name,omitempty"`
+	Lang        string `json:"lang"`
+	Version     string `json:"version"`
+	Protocol    int    `json:"protocol"`
+	User        string `json:"user,omitempty"`
+	Pass        string `json:"pass,omitempty"`
+	AuthToken   string `json:"auth_token,omitempty"`
+}
+
+// NATSOptions are the connection settings for DialNATS
+type NATSOptions struct {
+	URL      string // "nats://host:4222" or "tls://host:4222"; user info in the URL is used for authentication
+	Name     string // Client name shown in server monitoring
+	Username string
+	Password string
+	Token    string
+}
+
+// NATSClient is a connection to a NATS server that can publish messages
+type NATSClient struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	writer     *bufio.Writer
+	maxPayload int64
+}
+
+// DialNATS connects to the server and completes the handshake. ctx bounds the whole handshake.
+func DialNATS(ctx context.Context, opts NATSOptions) (*NATSClient, error) {
+	server, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	if server.Scheme != "nats" && server.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported NATS scheme %q", server.Scheme)
+	}
+	address := server.Host
+	if server.Port() == "" {
+		address = net.JoinHostPort(server.Hostname(), "4222")
+	}
+	if server.User != nil && opts.Username == "" && opts.Token == "" {
+		if password, ok := server.User.Password(); ok {
+			opts.Username, opts.Password = server.User.Username(), password
+		} else {
+			opts.Token = server.User.Username()
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client := &NATSClient{conn: conn, reader: bufio.NewReader(conn)}
+	if err := client.handshake(server, opts); err != nil {
+		client.conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// handshake reads INFO, upgrades to TLS when asked to, sends CONNECT and waits for the PONG
+// that confirms the server accepted it
+func (c *NATSClient) handshake(server *url.URL, opts NATSOptions) error {
+	line, err := c.readLine()
+	if err != nil {
+		return fmt.Errorf("failed to read INFO: %w", err)
+	}
+	infoJSON, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return fmt.Errorf("unexpected greeting %q", line)
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(infoJSON), &info); err != nil {
+		return fmt.Errorf("invalid INFO: %w", err)
+	}
+	c.maxPayload = info.MaxPayload
+
+	secure := server.Scheme == "tls" || info.TLSRequired
+	if secure {
+		tlsConn := tls.Client(c.conn, &tls.Config{ServerName: server.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		c.conn = tlsConn
+		c.reader = bufio.NewReader(tlsConn)
+	}
+	c.writer = bufio.NewWriter(c.conn)
+
+	connect, err := json.Marshal(natsConnect{
+		TLSRequired: secure,
+		Name:        opts.Name,
+		Lang:        "go",
+		Version:     "1.0.0",
+		Protocol:    1,
+		User:        opts.Username,
+		Pass:        opts.Password,
+		AuthToken:   opts.Token,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.writer, "CONNECT %s\r\n", connect)
+	return c.ping()
+}
+
+// Publish queues payload for subject; Flush sends queued messages and confirms the server received them
+func (c *NATSClient) Publish(subject string, payload []byte) error {
+	if c.maxPayload > 0 && int64(len(payload)) > c.maxPayload {
+		return fmt.Errorf("message of %d bytes exceeds the server's maximum payload of %d bytes", len(payload), c.maxPayload)
+	}
+	fmt.Fprintf(c.writer, "PUB %s %d\r\n", subject, len(payload))
+	c.writer.Write(payload)
+	_, err := c.writer.WriteString("\r\n")
+	return err
+}
+
+// Flush sends queued messages and waits for the server to process them, reporting errors
+// such as permission violations that the server sent in the meantime
+func (c *NATSClient) Flush(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+	}
+	return c.ping()
+}
+
+// Close closes the connection without flushing
+func (c *NATSClient) Close() error {
+	return c.conn.Close()
+}
+
+// ping sends PING and reads until the matching PONG, answering server PINGs on the way
+func (c *NATSClient) ping() error {
+	c.writer.WriteString("PING\r\n")
+	if err := c.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write to NATS server: %w", err)
+	}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return fmt.Errorf("failed to read from NATS server: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			c.writer.WriteString("PONG\r\n")
+			c.writer.Flush()
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS server error: %s", strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'"))
+		}
+		// +OK and INFO updates need no action
+	}
+}
+
+// readLine reads one protocol line without its CRLF
+func (c *NATSClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,125 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// natsMessage is a PUB received by fakeNATS
+type natsMessage struct {
+	subject string
+	payload []byte
+}
+
+// fakeNATS accepts one connection, answers PINGs and records CONNECT and every PUB
+type fakeNATS struct {
+	listener net.Listener
+	connect  natsConnect
+	messages []natsMessage
+	done     chan struct{}
+	reject   string // -ERR sent instead of the PONG to the first flush after CONNECT
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	server := &fakeNATS{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	return server
+}
+
+func (s *fakeNATS) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeNATS) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	io.WriteString(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+
+	pings := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &s.connect)
+		case strings.HasPrefix(line, "PUB "):
+			fields := strings.Fields(line)
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			s.messages = append(s.messages, natsMessage{subject: fields[1], payload: payload[:size]})
+		case line == "PING":
+			pings++
+			if pings > 1 && s.reject != "" {
+				io.WriteString(conn, "-ERR '"+s.reject+"'\r\n")
+				return
+			}
+			io.WriteString(conn, "PONG\r\n")
+		}
+	}
+}
+
+// eventMessages are the events of one successful and one failed location
+var eventMessages = []Message{
+	{Key: "Oslo", Value: json.RawMessage(`{"location":{"name":"Oslo"},"success":true}`)},
+	{Key: "Broken", Value: json.RawMessage(`{"location":{"name":"Broken"},"success":false}`)},
+}
+
+// TestPublishNATS tests that every message is published to the subject
+func TestPublishNATS(t *testing.T) {
+	server := newFakeNATS(t)
+	bus := Bus{Driver: DriverNATS, NATS: NATSOptions{URL: server.url(), Name: "test", Username: "weather", Password: "secret"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.Publish(ctx, "weather.results", eventMessages); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	<-server.done
+
+	if server.connect.User != "weather" || server.connect.Pass != "secret" || server.connect.Verbose {
+		t.Errorf("Unexpected CONNECT: %+v", server.connect)
+	}
+	if len(server.messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(server.messages))
+	}
+	if server.messages[1].subject != "weather.results" || string(server.messages[1].payload) != string(eventMessages[1].Value) {
+		t.Errorf("Unexpected message on %s: %s", server.messages[1].subject, server.messages[1].payload)
+	}
+}
+
+// TestPublishNATSError tests that an error sent by the server before the flush PONG is reported
+func TestPublishNATSError(t *testing.T) {
+	server := newFakeNATS(t)
+	server.reject = "Permissions Violation for Publish to \"weather.results\""
+
+	bus := Bus{Driver: DriverNATS, NATS: NATSOptions{URL: server.url()}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := bus.Publish(ctx, "weather.results", eventMessages)
+	if err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("Expected a permissions error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteFileAtomic tests that files are replaced in place without leaving temp files behind
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output.json")
+
+	if err := WriteFileAtomic(path, []byte(`{"v": 1}`), 0644); err != nil {
+		t.Fatalf("First write failed: %v", err)
+	}
+	if err := WriteFileAtomic(path, []byte(`{"v": 2}`), 0644); err != nil {
+		t.Fatalf("Second write failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"v": 2}` {
+		t.Errorf("Expected replaced content, got %q (err: %v)", data, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Expected mode 0644, got %v (err: %v)", info.Mode().Perm(), err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the output file in %s, found %d entries", dir, len(entries))
+	}
+}
+
+// TestWriteFileAtomicMissingDir tests that a missing directory is reported and nothing is written
+func TestWriteFileAtomicMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "output.json")
+	if err := WriteFileAtomic(path, []byte("{}"), 0644); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
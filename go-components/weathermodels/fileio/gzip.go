@@ -0,0 +1,49 @@
+package fileio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+)
+
+// GzipExt is the extension used for gzip-compressed exchange and data files
+const GzipExt = ".gz"
+
+// IsGzip reports whether path names a gzip-compressed file
+func IsGzip(path string) bool {
+	return strings.HasSuffix(path, GzipExt)
+}
+
+// Compress gzips data
+func Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress gunzips data
+func Decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// ReadFile reads a file, transparently decompressing it when its name ends in .gz
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsGzip(path) {
+		return data, err
+	}
+	return Decompress(data)
+}
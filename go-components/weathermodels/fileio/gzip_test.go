@@ -0,0 +1,25 @@
+package fileio
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestCompressRoundTrip tests that compressed files are read back transparently
+func TestCompressRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output_weather.json.gz")
+	original := []byte(`[{"location": {"name": "Oslo"}, "success": true}]`)
+
+	compressed, err := Compress(original)
+	if err != nil {
+		t.Fatalf("Compress failed: %v", err)
+	}
+	if err := WriteFileAtomic(path, compressed, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ReadFile(path)
+	if err != nil || string(data) != string(original) {
+		t.Errorf("Expected original data back, got %q (err: %v)", data, err)
+	}
+}
@@ -0,0 +1,3 @@
+module weathermodels
+
+go 1.25.1
@@ -0,0 +1,180 @@
+// Package logfile provides the rotating log file the data collector and the pattern engine write
+// their logs to when log_to_file is set.
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat timestamps rotated files; it sorts lexically in time order
+const backupTimeFormat = "20060102-150405.000"
+
+// RotationPolicy controls when a log file is rotated and how many old files are kept.
+// Zero values disable the corresponding limit.
+type RotationPolicy struct {
+	MaxSize    int64         // Rotate before a write would grow the file past this many bytes
+	Interval   time.Duration // Rotate when the wall clock crosses an interval boundary (UTC-aligned)
+	MaxBackups int           // Rotated files to keep, newest first
+	MaxAge     time.Duration // Delete rotated files older than this
+}
+
+// RotatingFile is an io.Writer appending to a log file and rotating it by size and time.
+// Rotated files are renamed to <base>-<timestamp><ext> next to the active file.
+type RotatingFile struct {
+	path   string
+	policy RotationPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// OpenRotatingFile opens (or creates) the log file at path, creating its directory if needed
+func OpenRotatingFile(path string, policy RotationPolicy) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	rf := &RotatingFile{path: path, policy: policy, now: time.Now}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write appends p to the log file, rotating first if a size or time limit is reached
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if rf.shouldRotate(int64(len(p))) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close closes the active log file
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+// open opens the active file for appending, picking up its current size and age
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = rf.now()
+	if rf.size > 0 {
+		// An existing file belongs to the interval it was last written in
+		rf.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// shouldRotate reports whether writing n more bytes requires a rotation first
+func (rf *RotatingFile) shouldRotate(n int64) bool {
+	if rf.size == 0 {
+		return false
+	}
+	if rf.policy.MaxSize > 0 && rf.size+n > rf.policy.MaxSize {
+		return true
+	}
+	if rf.policy.Interval > 0 {
+		return !rf.now().Truncate(rf.policy.Interval).Equal(rf.openedAt.Truncate(rf.policy.Interval))
+	}
+	return false
+}
+
+// rotate renames the active file to a timestamped backup, reopens it and prunes old backups
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	rf.file = nil
+
+	if err := os.Rename(rf.path, rf.backupPath(rf.now())); err != nil {
+		return fmt.Errorf("Failed to rotate log file: %w", err)
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+	return rf.prune()
+}
+
+// backupPath returns the name a backup rotated at t is stored under
+func (rf *RotatingFile) backupPath(t time.Time) string {
+	ext := filepath.Ext(rf.path)
+	base := strings.TrimSuffix(rf.path, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.UTC().Format(backupTimeFormat), ext)
+}
+
+// prune deletes backups beyond MaxBackups and those older than MaxAge
+func (rf *RotatingFile) prune() error {
+	if rf.policy.MaxBackups <= 0 && rf.policy.MaxAge <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(rf.path)
+	pattern := strings.TrimSuffix(rf.path, ext) + "-*" + ext
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+
+	// Newest first; the timestamp format sorts lexically
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	cutoff := rf.now().Add(-rf.policy.MaxAge)
+	for i, backup := range backups {
+		expired := false
+		if rf.policy.MaxBackups > 0 && i >= rf.policy.MaxBackups {
+			expired = true
+		} else if rf.policy.MaxAge > 0 {
+			if info, err := os.Stat(backup); err == nil && info.ModTime().Before(cutoff) {
+				expired = true
+			}
+		}
+
+		if expired {
+			if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
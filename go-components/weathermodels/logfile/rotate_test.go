@@ -0,0 +1,93 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRotatingFile opens a rotating file in a temp dir driven by a fake clock
+func newTestRotatingFile(t *testing.T, policy RotationPolicy, clock *time.Time) (*RotatingFile, string) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "weather.log")
+
+	rf, err := OpenRotatingFile(path, policy)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	rf.now = func() time.Time { return *clock }
+	t.Cleanup(func() { rf.Close() })
+	return rf, dir
+}
+
+// backups lists rotated files in dir
+func backups(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "weather-*.log"))
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	return matches
+}
+
+// TestRotateBySize tests that a write exceeding MaxSize rotates the file first
+func TestRotateBySize(t *testing.T) {
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rf, dir := newTestRotatingFile(t, RotationPolicy{MaxSize: 20}, &clock)
+
+	rf.Write([]byte("first line 123\n"))
+	clock = clock.Add(time.Second)
+	rf.Write([]byte("second line 456\n"))
+
+	if n := len(backups(t, dir)); n != 1 {
+		t.Fatalf("Expected 1 rotated file, got %d", n)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "weather.log"))
+	if string(data) != "second line 456\n" {
+		t.Errorf("Expected active file to hold only the latest write, got %q", data)
+	}
+}
+
+// TestRotateByInterval tests that crossing an interval boundary rotates the file
+func TestRotateByInterval(t *testing.T) {
+	clock := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+	rf, dir := newTestRotatingFile(t, RotationPolicy{Interval: 24 * time.Hour}, &clock)
+	rf.openedAt = clock
+
+	rf.Write([]byte("before midnight\n"))
+	clock = clock.Add(30 * time.Second)
+	rf.Write([]byte("still before\n"))
+	if n := len(backups(t, dir)); n != 0 {
+		t.Fatalf("Expected no rotation within the interval, got %d", n)
+	}
+
+	clock = clock.Add(time.Minute)
+	rf.Write([]byte("after midnight\n"))
+	rotated := backups(t, dir)
+	if len(rotated) != 1 {
+		t.Fatalf("Expected 1 rotated file after midnight, got %d", len(rotated))
+	}
+	if !strings.Contains(rotated[0], "20250602-") {
+		t.Errorf("Expected backup to be stamped with rotation time, got %s", rotated[0])
+	}
+}
+
+// TestRotatePrunesBackups tests that only MaxBackups rotated files are kept
+func TestRotatePrunesBackups(t *testing.T) {
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rf, dir := newTestRotatingFile(t, RotationPolicy{MaxSize: 5, MaxBackups: 2}, &clock)
+
+	for i := 0; i < 5; i++ {
+		rf.Write([]byte("line\n"))
+		clock = clock.Add(time.Second)
+	}
+
+	rotated := backups(t, dir)
+	if len(rotated) != 2 {
+		t.Fatalf("Expected 2 backups to be kept, got %d: %v", len(rotated), rotated)
+	}
+	if !strings.Contains(rotated[1], "120004") {
+		t.Errorf("Expected newest backups to be kept, got %v", rotated)
+	}
+}
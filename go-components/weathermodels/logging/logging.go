@@ -0,0 +1,72 @@
+// Package logging installs the process-wide slog logger of the data collector and the pattern
+// engine. Both read the same "logging" config section; each turns it into Options.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"weathermodels/logfile"
+)
+
+// Options are the logger settings of the "logging" config section
+type Options struct {
+	Level    int                    // 0=Error, 1=Warn, 2=Info, 3=Debug
+	Debug    bool                   // Lowers the level to Debug whatever Level is
+	Format   string                 // "text" or "json"
+	File     string                 // Rotating log file also written to ("" = stderr only)
+	Rotation logfile.RotationPolicy // When File is rotated
+}
+
+// Level maps the numeric config level (0=Error ... 3=Debug) to a slog level.
+// enable_debug always lowers the level to Debug.
+func Level(opts Options) slog.Level {
+	if opts.Debug {
+		return slog.LevelDebug
+	}
+	switch opts.Level {
+	case 0:
+		return slog.LevelError
+	case 1:
+		return slog.LevelWarn
+	case 3:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// NewHandler creates a text or JSON handler writing to w at the configured level
+func NewHandler(w io.Writer, opts Options) slog.Handler {
+	handlerOpts := &slog.HandlerOptions{Level: Level(opts)}
+	if opts.Format == "json" {
+		return slog.NewJSONHandler(w, handlerOpts)
+	}
+	return slog.NewTextHandler(w, handlerOpts)
+}
+
+// Setup installs the configured logger as the slog (and log package) default.
+// Logs always go to stderr so stdout stays free for pipe mode; with a File they
+// are also appended to the rotating log file. The returned closer closes that file.
+func Setup(opts Options) (io.Closer, error) {
+	var w io.Writer = os.Stderr
+	var closer io.Closer = nopCloser{}
+
+	if opts.File != "" {
+		file, err := logfile.OpenRotatingFile(opts.File, opts.Rotation)
+		if err != nil {
+			return nil, err
+		}
+		w = io.MultiWriter(os.Stderr, file)
+		closer = file
+	}
+
+	slog.SetDefault(slog.New(NewHandler(w, opts)))
+	return closer, nil
+}
+
+// nopCloser is returned when there is no log file to close
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
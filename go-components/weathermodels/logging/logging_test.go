@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLevel tests mapping of numeric log levels and the debug override
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		opts     Options
+		expected slog.Level
+	}{
+		{Options{Level: 0}, slog.LevelError},
+		{Options{Level: 1}, slog.LevelWarn},
+		{Options{Level: 2}, slog.LevelInfo},
+		{Options{Level: 3}, slog.LevelDebug},
+		{Options{Level: 0, Debug: true}, slog.LevelDebug},
+	}
+
+	for _, tt := range tests {
+		if got := Level(tt.opts); got != tt.expected {
+			t.Errorf("Level(%+v): expected %v, got %v", tt.opts, tt.expected, got)
+		}
+	}
+}
+
+// TestJSONHandler tests that json format emits one JSON object per record and filters by level
+func TestJSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, Options{Level: 2, Format: "json"}))
+
+	logger.Debug("hidden")
+	logger.Info("Collection complete", "locations", 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 log line, got %d: %q", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected JSON log line, got %q: %v", lines[0], err)
+	}
+	if record["msg"] != "Collection complete" || record["locations"] != float64(3) {
+		t.Errorf("Unexpected record: %v", record)
+	}
+}
+
+// TestSetupLogToFile tests that records are appended to the log file
+func TestSetupLogToFile(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	path := filepath.Join(t.TempDir(), "logs", "weather.log")
+	closer, err := Setup(Options{Level: 2, File: path})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	slog.Info("written to file")
+	closer.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "written to file") {
+		t.Errorf("Expected log record in file, got %q", data)
+	}
+}
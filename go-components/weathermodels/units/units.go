@@ -0,0 +1,290 @@
+// Package units converts readings and analysis results from the metric units the collector and
+// the pattern engine work in (°C, m/s, hPa, mm) to the unit system configured for their output:
+// metric, imperial, or a mix such as metric with knots for the wind. Readings are stored and
+// analyzed in metric units, so thresholds and history files keep one meaning; only what is
+// written out for people and other programs is converted.
+package units
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"weathermodels"
+)
+
+// Temperature units
+const (
+	Celsius    = "celsius"
+	Fahrenheit = "fahrenheit"
+)
+
+// Wind speed units
+const (
+	MetersPerSecond   = "m/s"
+	KilometersPerHour = "km/h"
+	MilesPerHour      = "mph"
+	Knots             = "kn"
+)
+
+// Pressure units
+const (
+	Hectopascals    = "hPa"
+	InchesOfMercury = "inHg"
+	MillimetersOfHg = "mmHg"
+)
+
+// Precipitation units
+const (
+	Millimeters = "mm"
+	Inches      = "in"
+)
+
+// Names of the unit systems Config.System selects
+const (
+	MetricSystem   = "metric"
+	ImperialSystem = "imperial"
+)
+
+// Conversion factors between the metric units and the others
+const (
+	kmhPerMS   = 3.6
+	mphPerMS   = 1 / 0.44704
+	knotsPerMS = 3600 / 1852.0
+	inHgPerHPa = 1 / 33.8638866667
+	mmHgPerHPa = 1 / 1.33322387415
+	mmPerInch  = 25.4
+)
+
+// System is the unit of each kind of quantity in an output; quantities without a unit, as in the
+// zero System, are metric
+type System struct {
+	Temperature   string `json:"temperature"`   // Celsius or Fahrenheit
+	WindSpeed     string `json:"wind_speed"`    // MetersPerSecond, KilometersPerHour, MilesPerHour or Knots
+	Pressure      string `json:"pressure"`      // Hectopascals, InchesOfMercury or MillimetersOfHg
+	Precipitation string `json:"precipitation"` // Millimeters or Inches
+}
+
+// Metric returns the units the readings are collected and analyzed in
+func Metric() System {
+	return System{Temperature: Celsius, WindSpeed: MetersPerSecond, Pressure: Hectopascals, Precipitation: Millimeters}
+}
+
+// Imperial returns the units of US weather reports
+func Imperial() System {
+	return System{Temperature: Fahrenheit, WindSpeed: MilesPerHour, Pressure: InchesOfMercury, Precipitation: Inches}
+}
+
+// IsMetric reports whether the system is Metric, so that values need no conversion
+func (s System) IsMetric() bool {
+	return s.withDefaults() == Metric()
+}
+
+// withDefaults returns the system with the metric unit of each quantity without one
+func (s System) withDefaults() System {
+	metric := Metric()
+	for _, u := range []struct{ unit, metric *string }{
+		{&s.Temperature, &metric.Temperature},
+		{&s.WindSpeed, &metric.WindSpeed},
+		{&s.Pressure, &metric.Pressure},
+		{&s.Precipitation, &metric.Precipitation},
+	} {
+		if *u.unit == "" {
+			*u.unit = *u.metric
+		}
+	}
+	return s
+}
+
+// Config is the "units" section of the shared config file: a system and the units overriding it,
+// such as {"system": "metric", "wind_speed": "kn"}
+type Config struct {
+	System        string `json:"system"`                  // MetricSystem or ImperialSystem ("" = metric)
+	Temperature   string `json:"temperature,omitempty"`   // Overrides the system's temperature unit
+	WindSpeed     string `json:"wind_speed,omitempty"`    // Overrides the system's wind speed unit
+	Pressure      string `json:"pressure,omitempty"`      // Overrides the system's pressure unit
+	Precipitation string `json:"precipitation,omitempty"` // Overrides the system's precipitation unit
+}
+
+// Resolve returns the units of the config, or an error naming the first unknown system or unit
+func (c Config) Resolve() (System, error) {
+	var system System
+	switch c.System {
+	case "", MetricSystem:
+		system = Metric()
+	case ImperialSystem:
+		system = Imperial()
+	default:
+		return System{}, fmt.Errorf("unknown unit system %q (expected %q or %q)", c.System, MetricSystem, ImperialSystem)
+	}
+
+	overrides := []struct {
+		name  string
+		value string
+		unit  *string
+		valid []string
+	}{
+		{"temperature", c.Temperature, &system.Temperature, []string{Celsius, Fahrenheit}},
+		{"wind_speed", c.WindSpeed, &system.WindSpeed, []string{MetersPerSecond, KilometersPerHour, MilesPerHour, Knots}},
+		{"pressure", c.Pressure, &system.Pressure, []string{Hectopascals, InchesOfMercury, MillimetersOfHg}},
+		{"precipitation", c.Precipitation, &system.Precipitation, []string{Millimeters, Inches}},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		if !slices.Contains(o.valid, o.value) {
+			return System{}, fmt.Errorf("unknown %s unit %q (expected one of %s)", o.name, o.value, strings.Join(o.valid, ", "))
+		}
+		*o.unit = o.value
+	}
+	return system, nil
+}
+
+// ConvertTemperature converts a temperature from °C
+func (s System) ConvertTemperature(celsius float64) float64 {
+	if s.Temperature == Fahrenheit {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+// ConvertTemperatureChange converts a difference of temperatures from °C, such as a rate or a
+// standard deviation, which unlike a temperature has no offset
+func (s System) ConvertTemperatureChange(celsius float64) float64 {
+	if s.Temperature == Fahrenheit {
+		return celsius * 9 / 5
+	}
+	return celsius
+}
+
+// ConvertWindSpeed converts a wind speed, or a change of one, from m/s
+func (s System) ConvertWindSpeed(ms float64) float64 {
+	switch s.WindSpeed {
+	case KilometersPerHour:
+		return ms * kmhPerMS
+	case MilesPerHour:
+		return ms * mphPerMS
+	case Knots:
+		return ms * knotsPerMS
+	}
+	return ms
+}
+
+// ConvertPressure converts a pressure, or a change of one, from hPa
+func (s System) ConvertPressure(hPa float64) float64 {
+	switch s.Pressure {
+	case InchesOfMercury:
+		return hPa * inHgPerHPa
+	case MillimetersOfHg:
+		return hPa * mmHgPerHPa
+	}
+	return hPa
+}
+
+// ConvertPrecipitation converts an amount of precipitation from mm
+func (s System) ConvertPrecipitation(mm float64) float64 {
+	if s.Precipitation == Inches {
+		return mm / mmPerInch
+	}
+	return mm
+}
+
+// Convert converts a value of a variable, named as in the analysis results ("temperature",
+// "wind_speed", "precipitation_mm", ...); variables without a unit of the system, such as
+// humidity or wind direction, are returned unchanged
+func (s System) Convert(variable string, value float64) float64 {
+	switch kind(variable) {
+	case "temperature":
+		return s.ConvertTemperature(value)
+	case "wind_speed":
+		return s.ConvertWindSpeed(value)
+	case "pressure":
+		return s.ConvertPressure(value)
+	case "precipitation":
+		return s.ConvertPrecipitation(value)
+	}
+	return value
+}
+
+// ConvertChange converts a difference of values of a variable, such as a rate of change, an error or a
+// spread, named as in Convert
+func (s System) ConvertChange(variable string, change float64) float64 {
+	if kind(variable) == "temperature" {
+		return s.ConvertTemperatureChange(change)
+	}
+	return s.Convert(variable, change)
+}
+
+// Symbol returns the symbol of a variable's unit as written after a number: "°C" and "%" follow
+// the number directly, the others after a space, such as " hPa". It is "" for variables without
+// a unit.
+func (s System) Symbol(variable string) string {
+	s = s.withDefaults()
+	switch kind(variable) {
+	case "temperature":
+		if s.Temperature == Fahrenheit {
+			return "°F"
+		}
+		return "°C"
+	case "wind_speed":
+		return " " + s.WindSpeed
+	case "pressure":
+		return " " + s.Pressure
+	case "precipitation":
+		return " " + s.Precipitation
+	case "percent":
+		return "%"
+	}
+	return ""
+}
+
+// ConvertPoint converts the measured and derived values of a reading
+func (s System) ConvertPoint(p weathermodels.WeatherPoint) weathermodels.WeatherPoint {
+	if s.IsMetric() {
+		return p
+	}
+	p.Temperature = s.ConvertTemperature(p.Temperature)
+	p.DewPoint = s.ConvertTemperature(p.DewPoint)
+	p.Pressure = s.ConvertPressure(p.Pressure)
+	p.WindSpeed = s.ConvertWindSpeed(p.WindSpeed)
+	p.WindGust = s.ConvertWindSpeed(p.WindGust)
+	p.PrecipitationMm = s.ConvertPrecipitation(p.PrecipitationMm)
+	if p.Derived != (weathermodels.Derived{}) { // The collector leaves them empty
+		p.Derived.DewPoint = s.ConvertTemperature(p.Derived.DewPoint)
+		p.Derived.HeatIndex = s.ConvertTemperature(p.Derived.HeatIndex)
+		p.Derived.WindChill = s.ConvertTemperature(p.Derived.WindChill)
+		p.Derived.ApparentTemperature = s.ConvertTemperature(p.Derived.ApparentTemperature)
+	}
+	return p
+}
+
+// ConvertPoints converts readings into a new slice, or returns them as they are in the metric system
+func (s System) ConvertPoints(points []weathermodels.WeatherPoint) []weathermodels.WeatherPoint {
+	if s.IsMetric() || points == nil {
+		return points
+	}
+	converted := make([]weathermodels.WeatherPoint, len(points))
+	for i, p := range points {
+		converted[i] = s.ConvertPoint(p)
+	}
+	return converted
+}
+
+// kind returns the kind of quantity of a variable: "temperature", "wind_speed", "pressure",
+// "precipitation", "percent", or "" for one without a unit
+func kind(variable string) string {
+	switch variable {
+	case "temperature", "dew_point", "heat_index", "wind_chill", "apparent_temperature", "humidex", "surface_temperature":
+		return "temperature"
+	case "wind_speed", "wind_gust":
+		return "wind_speed"
+	case "pressure":
+		return "pressure"
+	case "precipitation", "precipitation_mm":
+		return "precipitation"
+	case "humidity", "cloud_cover", "precipitation_probability", "fog_area_fraction":
+		return "percent"
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package units
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"weathermodels"
+)
+
+// TestResolve tests the systems, overrides and unknown units of a config
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    System
+		wantErr bool
+	}{
+		{"default", Config{}, Metric(), false},
+		{"imperial", Config{System: ImperialSystem}, Imperial(), false},
+		{"metric with knots", Config{System: MetricSystem, WindSpeed: Knots},
+			System{Temperature: Celsius, WindSpeed: Knots, Pressure: Hectopascals, Precipitation: Millimeters}, false},
+		{"imperial with hPa", Config{System: ImperialSystem, Pressure: Hectopascals},
+			System{Temperature: Fahrenheit, WindSpeed: MilesPerHour, Pressure: Hectopascals, Precipitation: Inches}, false},
+		{"unknown system", Config{System: "nautical"}, System{}, true},
+		{"unknown unit", Config{WindSpeed: "furlongs"}, System{}, true},
+		{"unit of another quantity", Config{Pressure: Inches}, System{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Resolve()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConvert tests the conversion of each kind of quantity, and of changes of temperature
+func TestConvert(t *testing.T) {
+	imperial := Imperial()
+	tests := []struct {
+		name   string
+		system System
+		got    float64
+		want   float64
+	}{
+		{"freezing", imperial, imperial.Convert("temperature", 0), 32},
+		{"body temperature", imperial, imperial.Convert("dew_point", 37), 98.6},
+		{"temperature change", imperial, imperial.ConvertChange("temperature", 5), 9},
+		{"wind in mph", imperial, imperial.Convert("wind_speed", 10), 22.369},
+		{"wind in knots", System{WindSpeed: Knots}, System{WindSpeed: Knots}.Convert("wind_gust", 10), 19.438},
+		{"wind in km/h", System{WindSpeed: KilometersPerHour}, System{WindSpeed: KilometersPerHour}.Convert("wind_speed", 10), 36},
+		{"standard pressure", imperial, imperial.Convert("pressure", 1013.25), 29.921},
+		{"pressure change", imperial, imperial.ConvertChange("pressure", -6), -0.177},
+		{"pressure in mmHg", System{Pressure: MillimetersOfHg}, System{Pressure: MillimetersOfHg}.Convert("pressure", 1013.25), 760},
+		{"precipitation", imperial, imperial.Convert("precipitation_mm", 25.4), 1},
+		{"humidity unchanged", imperial, imperial.Convert("humidity", 80), 80},
+		{"metric unchanged", Metric(), Metric().Convert("temperature", 21.5), 21.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 0.001 {
+				t.Errorf("Got %.4f, want %.3f", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSymbol tests the symbols written after numbers, with the zero System metric
+func TestSymbol(t *testing.T) {
+	tests := []struct {
+		system   System
+		variable string
+		want     string
+	}{
+		{Imperial(), "temperature", "°F"},
+		{System{}, "temperature", "°C"},
+		{Imperial(), "pressure", " inHg"},
+		{System{}, "pressure", " hPa"},
+		{System{WindSpeed: Knots}, "wind_speed", " kn"},
+		{Imperial(), "precipitation_mm", " in"},
+		{Imperial(), "humidity", "%"},
+		{Imperial(), "wind_direction", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.system.Symbol(tt.variable); got != tt.want {
+			t.Errorf("%+v.Symbol(%q) = %q, want %q", tt.system, tt.variable, got, tt.want)
+		}
+	}
+}
+
+// TestConvertPoint tests that the measured values of a reading are converted and its empty
+// derived values left empty
+func TestConvertPoint(t *testing.T) {
+	point := weathermodels.WeatherPoint{
+		Timestamp:   time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC),
+		Temperature: 10, Pressure: 1000, Humidity: 70, WindSpeed: 5, WindDirection: 180, PrecipitationMm: 2.54,
+	}
+	got := Imperial().ConvertPoint(point)
+	if got.Temperature != 50 || got.Humidity != 70 || got.WindDirection != 180 || math.Abs(got.PrecipitationMm-0.1) > 1e-9 {
+		t.Errorf("Unexpected conversion: %+v", got)
+	}
+	if got.Derived != (weathermodels.Derived{}) {
+		t.Errorf("Expected empty derived values to stay empty, got %+v", got.Derived)
+	}
+
+	point.Derived.WindChill = -10
+	if got := Imperial().ConvertPoint(point); got.Derived.WindChill != 14 {
+		t.Errorf("Expected a wind chill of 14°F, got %v", got.Derived.WindChill)
+	}
+	if got := Metric().ConvertPoint(point); got != point {
+		t.Errorf("Expected the metric system to leave the reading unchanged, got %+v", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package weathermodels defines the weather readings shared by the data collector and the pattern
+// engine, so both components use one set of types and one JSON encoding.
+//
+// Timestamps are time.Time in Go and RFC 3339 strings in JSON ("2025-10-03T12:00:00Z"). A zero
+// timestamp is written as "" and an empty string reads back as the zero time, so results without
+// a reading (failed locations) keep the layout the collector has always written.
+package weathermodels
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimestampLayout is the layout of every timestamp in the JSON files and APIs
+const TimestampLayout = time.RFC3339
+
+// WeatherPoint represents a single weather reading at a specific time
+type WeatherPoint struct {
+	Timestamp                time.Time `json:"timestamp"`
+	Temperature              float64   `json:"temperature"`
+	Pressure                 float64   `json:"pressure"`
+	Humidity                 float64   `json:"humidity"`
+	WindSpeed                float64   `json:"wind_speed"`
+	WindDirection            float64   `json:"wind_direction"`
+	CloudCover               float64   `json:"cloud_cover"`
+	PrecipitationMm          float64   `json:"precipitation_mm"`
+	PrecipitationProbability float64   `json:"precipitation_probability"`
+	SymbolCode               string    `json:"symbol_code"`
+	DewPoint                 float64   `json:"dew_point"`         // Dew point temperature (°C)
+	UVIndex                  float64   `json:"uv_index"`          // UV index under clear sky
+	WindGust                 float64   `json:"wind_gust"`         // Maximum wind gust speed (m/s)
+	FogAreaFraction          float64   `json:"fog_area_fraction"` // Fog coverage (%)
+	Source                   string    `json:"source,omitempty"`  // SourceObservation, SourceForecast or SourceNowcast ("" = not recorded)
+	Derived                  Derived   `json:"derived,omitzero"`  // Computed from the measured values, not measured
+}
+
+// Sources of a reading: the conditions at the time of collection or archived since, a forecast of
+// a later time, or a radar nowcast of the next minutes
+const (
+	SourceObservation = "observation"
+	SourceForecast    = "forecast"
+	SourceNowcast     = "nowcast"
+)
+
+// Observed reports whether the reading is of the weather that was rather than a forecast or
+// nowcast; readings written before sources were recorded count as observed
+func (p WeatherPoint) Observed() bool {
+	return p.Source == "" || p.Source == SourceObservation
+}
+
+// Derived holds the variables of a reading computed from its measured values (see the
+// pattern-engine derive package); the collector leaves them empty
+type Derived struct {
+	DewPoint            float64 `json:"dew_point"`            // Dew point from temperature and humidity (°C)
+	HeatIndex           float64 `json:"heat_index"`           // Temperature felt in humid heat (°C)
+	WindChill           float64 `json:"wind_chill"`           // Temperature felt in cold wind (°C)
+	ApparentTemperature float64 `json:"apparent_temperature"` // Temperature felt from humidity and wind (°C)
+}
+
+// MarinePoint represents a single ocean forecast reading for a coastal location (from met.no oceanforecast)
+type MarinePoint struct {
+	Timestamp        time.Time `json:"timestamp"`
+	WaveHeight       float64   `json:"wave_height"`       // Significant wave height (m)
+	WaveDirection    float64   `json:"wave_direction"`    // Direction waves come from (degrees)
+	SeaTemperature   float64   `json:"sea_temperature"`   // Sea surface temperature (°C)
+	CurrentSpeed     float64   `json:"current_speed"`     // Sea water speed (m/s)
+	CurrentDirection float64   `json:"current_direction"` // Direction the current flows towards (degrees)
+}
+
+// ForecastArchive is the forecasts issued for a location, kept by the collector next to the
+// location's history file so that the pattern engine can verify them against the readings
+// observed later
+type ForecastArchive struct {
+	SchemaVersion int              `json:"schema_version"`
+	Location      string           `json:"location"`
+	Forecasts     []IssuedForecast `json:"forecasts"` // Oldest first
+}
+
+// IssuedForecast is the forecast of one collection
+type IssuedForecast struct {
+	IssuedAt time.Time      `json:"issued_at"` // Time the forecast was collected; lead times are counted from it
+	Points   []WeatherPoint `json:"points"`    // Forecast points from the current reading on, in time order
+}
+
+// FormatTimestamp renders t in TimestampLayout, or "" for the zero time
+func FormatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(TimestampLayout)
+}
+
+// ParseTimestamp parses a TimestampLayout string; "" is the zero time
+func ParseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(TimestampLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+	return t, nil
+}
+
+// The plain types have the same fields without the JSON methods; the wrappers below shadow
+// their Timestamp with the string form
+type (
+	plainWeatherPoint WeatherPoint
+	plainMarinePoint  MarinePoint
+)
+
+// MarshalJSON writes the reading with its timestamp in TimestampLayout
+func (p WeatherPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Timestamp string `json:"timestamp"`
+		plainWeatherPoint
+	}{FormatTimestamp(p.Timestamp), plainWeatherPoint(p)})
+}
+
+// UnmarshalJSON reads a reading written by MarshalJSON
+func (p *WeatherPoint) UnmarshalJSON(data []byte) error {
+	var wire struct {
+		Timestamp string `json:"timestamp"`
+		*plainWeatherPoint
+	}
+	wire.plainWeatherPoint = (*plainWeatherPoint)(p)
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	t, err := ParseTimestamp(wire.Timestamp)
+	p.Timestamp = t
+	return err
+}
+
+// MarshalJSON writes the reading with its timestamp in TimestampLayout
+func (p MarinePoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Timestamp string `json:"timestamp"`
+		plainMarinePoint
+	}{FormatTimestamp(p.Timestamp), plainMarinePoint(p)})
+}
+
+// UnmarshalJSON reads a reading written by MarshalJSON
+func (p *MarinePoint) UnmarshalJSON(data []byte) error {
+	var wire struct {
+		Timestamp string `json:"timestamp"`
+		*plainMarinePoint
+	}
+	wire.plainMarinePoint = (*plainMarinePoint)(p)
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	t, err := ParseTimestamp(wire.Timestamp)
+	p.Timestamp = t
+	return err
+}
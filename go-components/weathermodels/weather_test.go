@@ -0,0 +1,55 @@
+package weathermodels
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWeatherPointJSON tests that timestamps round-trip as RFC 3339 strings and zero times as ""
+func TestWeatherPointJSON(t *testing.T) {
+	point := WeatherPoint{Timestamp: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC), Temperature: 9.5, SymbolCode: "rain"}
+	data, err := json.Marshal(point)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"timestamp":"2025-10-03T12:00:00Z"`) || !strings.Contains(string(data), `"temperature":9.5`) {
+		t.Errorf("Unexpected encoding: %s", data)
+	}
+
+	var decoded WeatherPoint
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != point {
+		t.Errorf("Round trip gave %+v, want %+v", decoded, point)
+	}
+
+	data, _ = json.Marshal(WeatherPoint{})
+	if !strings.Contains(string(data), `"timestamp":""`) {
+		t.Errorf("Expected an empty timestamp for the zero time, got %s", data)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || !decoded.Timestamp.IsZero() {
+		t.Errorf("Expected the zero time back, got %v (err: %v)", decoded.Timestamp, err)
+	}
+}
+
+// TestMarinePointJSON tests offsets are preserved and invalid timestamps are rejected
+func TestMarinePointJSON(t *testing.T) {
+	var point MarinePoint
+	if err := json.Unmarshal([]byte(`{"timestamp":"2025-10-03T14:00:00+02:00","wave_height":1.2}`), &point); err != nil {
+		t.Fatal(err)
+	}
+	if !point.Timestamp.Equal(time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)) || point.WaveHeight != 1.2 {
+		t.Errorf("Unexpected point %+v", point)
+	}
+	data, _ := json.Marshal(point)
+	if !strings.Contains(string(data), `"2025-10-03T14:00:00+02:00"`) {
+		t.Errorf("Expected the original offset, got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"timestamp":"yesterday"}`), &point); err == nil {
+		t.Error("Expected an error for an invalid timestamp")
+	}
+}
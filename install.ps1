@@ -7,6 +7,8 @@ Write-Host "Installing Weather Intelligence System..." -ForegroundColor Green
 $pythonExists = Get-Command python -ErrorAction SilentlyContinue
 $goExists = Get-Command go -ErrorAction SilentlyContinue
 $gitExists = Get-Command git -ErrorAction SilentlyContinue
+# The analysis database uses SQLite through cgo, which needs a C compiler such as MinGW-w64's gcc
+$gccExists = Get-Command gcc -ErrorAction SilentlyContinue
 
 if (-not $pythonExists) {
     Write-Host "Error: Python is required but not found." -ForegroundColor Red
@@ -23,6 +25,11 @@ if (-not $gitExists) {
     exit 1
 }
 
+if (-not $gccExists) {
+    Write-Host "Error: gcc (e.g. from MinGW-w64) is required for the SQLite analysis database but not found." -ForegroundColor Red
+    exit 1
+}
+
 # Define repository URL
 $repoUrl = "https://raw.githubusercontent.com/redsskull/weather-intelligence-system/main"
 
@@ -73,22 +80,17 @@ $tempDir = Join-Path $env:TEMP "weather-intel-install-$(Get-Random)"
 New-Item -ItemType Directory -Path $tempDir -Force
 git clone https://github.com/redsskull/weather-intelligence-system.git (Join-Path $tempDir "repo")
 
-# Build Go binaries
-$dataCollectorDir = Join-Path $tempDir "repo\go-components\data-collector"
-$patternEngineDir = Join-Path $tempDir "repo\go-components\pattern-engine"
-$collectorExe = Join-Path $installDir "data-collector.exe"
-$engineExe = Join-Path $installDir "pattern-engine.exe"
-
-Set-Location $dataCollectorDir
-go build -o $collectorExe
-Set-Location $installDir
+# Build the Go binary (data collector and pattern engine in one CLI)
+$weatherCliDir = Join-Path $tempDir "repo\go-components\weather"
+$weatherExe = Join-Path $installDir "weather.exe"
 
-Set-Location $patternEngineDir
-go build -o $engineExe
+Set-Location $weatherCliDir
+$env:CGO_ENABLED = "1"
+go build -o $weatherExe
 Set-Location $installDir
 
 # On Windows, executable permissions are handled by the system
-# The .exe files should be executable by default
+# The .exe file should be executable by default
 
 # Cleanup
 Remove-Item -Path $tempDir -Recurse -Force
@@ -6,6 +6,8 @@ echo "Installing Weather Intelligence System..."
 # Check Python and Go
 command -v python3 >/dev/null || { echo "Need Python 3"; exit 1; }
 command -v go >/dev/null || { echo "Need Go"; exit 1; }
+# The analysis database uses SQLite through cgo, which needs a C compiler
+command -v "${CC:-cc}" >/dev/null || { echo "Need a C compiler (e.g. gcc) for the SQLite analysis database"; exit 1; }
 
 # Define repository and version
 REPO_URL="https://raw.githubusercontent.com/redsskull/weather-intelligence-system/main"
@@ -48,12 +50,10 @@ python3 -m venv "$VENV_DIR"
 echo "Downloading and building Go components..."
 TEMP_DIR=$(mktemp -d)
 git clone https://github.com/redsskull/weather-intelligence-system.git "$TEMP_DIR/repo"
-cd "$TEMP_DIR/repo/go-components/data-collector" && go build -o "$INSTALL_DIR/data-collector" && cd "$INSTALL_DIR"
-cd "$TEMP_DIR/repo/go-components/pattern-engine" && go build -o "$INSTALL_DIR/pattern-engine" && cd "$INSTALL_DIR"
+cd "$TEMP_DIR/repo/go-components/weather" && CGO_ENABLED=1 go build -o "$INSTALL_DIR/weather" && cd "$INSTALL_DIR"
 
-# Make binaries executable
-chmod +x "$INSTALL_DIR/data-collector"
-chmod +x "$INSTALL_DIR/pattern-engine"
+# Make the binary executable
+chmod +x "$INSTALL_DIR/weather"
 
 # Cleanup
 rm -rf "$TEMP_DIR"
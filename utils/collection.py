@@ -5,12 +5,111 @@ I'm very proud of this one.
 
 import os
 import json
+import time
+import gzip
+import hashlib
+import tempfile
 import subprocess
 import platform
-import shutil
+from datetime import datetime, timezone
 
 from utils.errors import display_error_help
 
+# File handshake with the Go collector (see go-components/data-collector/integration):
+# a "<file>.done" marker with the file's size and SHA-256 is written once the file is complete
+DONE_SUFFIX = ".done"
+HANDSHAKE_TIMEOUT = 10  # seconds to wait for the collector's output marker
+
+# Newest collector result layout this module understands (collector.SchemaVersion in Go).
+# Results without a schema_version predate versioning and are read as version 1.
+COLLECTOR_SCHEMA_VERSION = 1
+
+# Collector exit codes: results are written for both 0 and 2, and failed locations
+# are reported per result. 3 means every location failed, 4 a configuration error.
+EXIT_OK = 0
+EXIT_PARTIAL_FAILURE = 2
+EXIT_TOTAL_FAILURE = 3
+EXIT_CONFIG_ERROR = 4
+COLLECTED_EXIT_CODES = (EXIT_OK, EXIT_PARTIAL_FAILURE)
+
+
+def write_with_marker(path, data):
+    """
+    Write a data file atomically, then its handshake marker
+
+    Args:
+        path: Data file path
+        data: File contents (str)
+    """
+    marker_path = path + DONE_SUFFIX
+    if os.path.exists(marker_path):
+        os.remove(marker_path)
+
+    raw = data.encode("utf-8")
+    _write_atomic(path, raw)
+
+    marker = {
+        "size": len(raw),
+        "sha256": hashlib.sha256(raw).hexdigest(),
+        "written_at": datetime.now(timezone.utc).isoformat().replace("+00:00", "Z"),
+    }
+    _write_atomic(marker_path, json.dumps(marker).encode("utf-8"))
+
+
+def read_with_marker(path, timeout=HANDSHAKE_TIMEOUT):
+    """
+    Wait for a data file's handshake marker and return the file contents,
+    decompressing files that end in ".gz"
+
+    Returns:
+        str: File contents, or None if the file was not completed within timeout
+    """
+    deadline = time.monotonic() + timeout
+    while True:
+        try:
+            with open(path + DONE_SUFFIX, "r") as f:
+                marker = json.load(f)
+            with open(path, "rb") as f:
+                raw = f.read()
+            if (
+                len(raw) == marker.get("size")
+                and hashlib.sha256(raw).hexdigest() == marker.get("sha256")
+            ):
+                if path.endswith(".gz"):
+                    raw = gzip.decompress(raw)
+                return raw.decode("utf-8")
+        except (FileNotFoundError, json.JSONDecodeError):
+            pass  # Writer has not finished yet
+
+        if time.monotonic() >= deadline:
+            return None
+        time.sleep(0.1)
+
+
+def parse_collector_output(data):
+    """
+    Parse collector output written either as one JSON array or as JSON Lines
+    (integration.output_format = "jsonl", one result per line)
+    """
+    try:
+        return json.loads(data)
+    except json.JSONDecodeError:
+        return [json.loads(line) for line in data.splitlines() if line.strip()]
+
+
+def _write_atomic(path, raw):
+    """Write bytes to a temp file in the same directory and rename it into place"""
+    directory = os.path.dirname(path) or "."
+    fd, tmp_path = tempfile.mkstemp(dir=directory, prefix="." + os.path.basename(path) + ".tmp-")
+    try:
+        with os.fdopen(fd, "wb") as f:
+            f.write(raw)
+        os.replace(tmp_path, path)
+    except Exception:
+        if os.path.exists(tmp_path):
+            os.remove(tmp_path)
+        raise
+
 
 def call_go_collector(locations):
     """
@@ -33,11 +132,21 @@ def call_go_collector(locations):
                 "lat": float(loc.get("lat", 0)),
                 "lon": float(loc.get("lon", 0)),
             }
+            # Optional per-location overrides of the collector's global config
+            for key in ("provider", "timeout", "priority"):
+                if loc.get(key) is not None:
+                    go_location[key] = loc[key]
             go_locations.append(go_location)
 
-        # Write to JSON file
-        with open(input_file, "w") as f:
-            json.dump(go_locations, f, indent=2)
+        # Write to JSON file, followed by the handshake marker the collector waits for
+        input_data = json.dumps(go_locations, indent=2)
+        write_with_marker(input_file, input_data)
+
+        # Drop any stale output markers so only this run's results are accepted
+        for output_name in ("output_weather.json", "output_weather.json.gz"):
+            output_marker = os.path.join(integration_dir, output_name + DONE_SUFFIX)
+            if os.path.exists(output_marker):
+                os.remove(output_marker)
 
     except Exception as e:
         display_error_help("file_write_error", f"Could not write locations: {e}")
@@ -48,19 +157,23 @@ def call_go_collector(locations):
         # Determine the correct binary name based on the OS
         system = platform.system().lower()
         if system == "windows":
-            binary_path = "./data-collector.exe"
+            binary_path = "./weather.exe"
         else:
-            binary_path = "./data-collector"  # Linux/macOS
+            binary_path = "./weather"  # Linux/macOS
 
         # Check if binary exists
         binary_name = binary_path.lstrip("./")
         if os.path.exists(binary_name):
             result = subprocess.run(
-                [binary_path], capture_output=True, text=True, timeout=30
+                [binary_path, "collect"], capture_output=True, text=True, timeout=30
             )
 
-            if result.returncode == 0:
+            if result.returncode in COLLECTED_EXIT_CODES:
                 return True
+            if result.returncode in (EXIT_TOTAL_FAILURE, EXIT_CONFIG_ERROR):
+                # Re-running from source would fail the same way
+                display_error_help("go_collector_failed", f"Go collector failed: {result.stderr}")
+                return False
 
     except subprocess.TimeoutExpired:
         display_error_help("subprocess_timeout", "Go collector took too long")
@@ -71,7 +184,7 @@ def call_go_collector(locations):
 
     # Fallback: try to run Go directly for development (go run)
     try:
-        go_dir = "go-components/data-collector"
+        go_dir = "go-components/weather"
         # Check if the go directory and main.go exist
         if not os.path.exists(os.path.join(go_dir, "main.go")):
             display_error_help("go_source_missing", "Go binary not found and source not available")
@@ -82,12 +195,12 @@ def call_go_collector(locations):
         os.makedirs(expected_input_dir, exist_ok=True)
         expected_input_file = os.path.join(expected_input_dir, "input_locations.json")
 
-        # Copy the input file to where Go expects it
-        shutil.copy2(input_file, expected_input_file)
+        # Copy the input file (and its marker) to where Go expects it
+        write_with_marker(expected_input_file, input_data)
 
         # Run from the go directory
         result = subprocess.run(
-            ["go", "run", "main.go"],
+            ["go", "run", ".", "collect"],
             cwd=go_dir,
             capture_output=True,
             text=True,
@@ -95,10 +208,11 @@ def call_go_collector(locations):
         )
 
         # Clean up the copied file
-        if os.path.exists(expected_input_file):
-            os.remove(expected_input_file)
+        for path in (expected_input_file, expected_input_file + DONE_SUFFIX):
+            if os.path.exists(path):
+                os.remove(path)
 
-        if result.returncode == 0:
+        if result.returncode in COLLECTED_EXIT_CODES:
             return True
         else:
             display_error_help("go_collector_failed", f"Go collector failed: {result.stderr}")
@@ -138,19 +252,32 @@ def load_go_collected_data():
 
     # Check if output file exists
     output_file = "data/integration/output_weather.json"
+    if not os.path.exists(output_file) and os.path.exists(output_file + ".gz"):
+        output_file += ".gz"  # Collector ran with integration.compress
 
     if not os.path.exists(output_file):
         display_error_help("file_not_found", f"Go output file not found: {output_file}")
         return None
 
-    # Read and parse the JSON file
+    # Read and parse the JSON file once the collector's handshake marker confirms it is complete
     try:
-        with open(output_file, "r") as f:
-            weather_data = json.load(f)
+        output_data = read_with_marker(output_file)
+        if output_data is None:
+            display_error_help("file_read_error", f"Go output file was not completed: {output_file}")
+            return None
+        weather_data = parse_collector_output(output_data)
 
         # Convert Go format to Python-friendly format (optional processing)
         processed_data = []
         for item in weather_data:
+            if item.get("schema_version", 1) > COLLECTOR_SCHEMA_VERSION:
+                display_error_help(
+                    "json_parsing_error",
+                    f"Go output uses schema_version {item['schema_version']}, "
+                    f"but this version only understands up to {COLLECTOR_SCHEMA_VERSION}",
+                )
+                return None
+
             current_weather = item.get("current_weather", {})
 
             processed_item = {
@@ -168,6 +295,8 @@ def load_go_collected_data():
                 "symbol_code": current_weather.get("symbol_code", "unknown"),
                 "success": item.get("success", False),
                 "error": item.get("error", ""),
+                "error_code": item.get("error_code", ""),
+                "meta": item.get("meta", {}),  # Latency, retries, provider, cache hit, HTTP status
                 "timestamp": current_weather.get("timestamp"),
                 "forecast": item.get(
                     "forecast", []
@@ -9,6 +9,10 @@ import glob
 from datetime import datetime, timedelta
 import statistics
 
+# Layout version of the time-series files; the Go pattern engine refuses files newer
+# than models.TimeseriesSchemaVersion. Bump both when fields are renamed or removed.
+TIMESERIES_SCHEMA_VERSION = 1
+
 
 def save_to_timeseries(weather_data, location_name, coordinates=None):
     """
@@ -82,6 +86,7 @@ def save_to_timeseries(weather_data, location_name, coordinates=None):
     }
 
     timeseries["readings"].append(reading)
+    timeseries["schema_version"] = TIMESERIES_SCHEMA_VERSION
 
     # Update metadata
     timeseries["metadata"]["total_readings"] = len(timeseries["readings"])